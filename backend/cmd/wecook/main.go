@@ -2,23 +2,117 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+
 	"github.com/matt-dz/wecook/internal/api"
+	"github.com/matt-dz/wecook/internal/appliance"
 	"github.com/matt-dz/wecook/internal/config"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/delivery"
+	"github.com/matt-dz/wecook/internal/doctor"
+	"github.com/matt-dz/wecook/internal/email"
 	"github.com/matt-dz/wecook/internal/env"
 	"github.com/matt-dz/wecook/internal/http"
 	"github.com/matt-dz/wecook/internal/log"
+	"github.com/matt-dz/wecook/internal/relocate"
+	"github.com/matt-dz/wecook/internal/report"
 	"github.com/matt-dz/wecook/internal/setup"
+	"github.com/matt-dz/wecook/internal/tagging"
+	"github.com/matt-dz/wecook/internal/undo"
+)
+
+const (
+	migrateStorageCommand = "migrate-storage"
+	doctorCommand         = "doctor"
 )
 
+// federationTimeout bounds a request to another instance, redirects
+// included.
+const federationTimeout = 10 * time.Second
+
+// migrateStorage moves stored images to the configured path template.
+//
+//	wecook migrate-storage [--dry-run]
+func migrateStorage(ctx context.Context, logger *slog.Logger, db *database.Database,
+	store relocate.Store, args []string,
+) error {
+	flags := flag.NewFlagSet(migrateStorageCommand, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report which files would be moved without moving them")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("parsing flags: %w", err)
+	}
+
+	result, err := relocate.Run(ctx, db, store, logger, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "storage migration complete",
+		slog.Bool("dry_run", *dryRun),
+		slog.Int("moved", result.Moved),
+		slog.Int("unchanged", result.Unchanged),
+		slog.Int("missing", result.Missing))
+	return nil
+}
+
+// runDoctor checks that the deployment is ready to run, prints a report,
+// and returns its exit code: 0 when every check passed, 1 when one
+// failed, and 2 when one only warned.
+//
+//	wecook doctor
+func runDoctor(ctx context.Context) int {
+	var (
+		pool       *pgxpool.Pool
+		smtpSender *email.SMTPSender
+	)
+	conf, err := config.LoadConfig()
+	if err == nil {
+		_, err = setup.FileStore(conf)
+	}
+	if err == nil {
+		smtpSender, err = setup.SMTP(conf)
+	}
+	if err == nil {
+		pool, err = setup.DatabasePool(ctx, conf)
+	}
+	if err != nil {
+		report := doctor.Run(ctx, []doctor.Check{doctor.Config(err)})
+		_ = report.Write(os.Stdout)
+		return report.ExitCode()
+	}
+	defer pool.Close()
+
+	address := fmt.Sprintf("%s:%d/%s", conf.Database.Host, conf.Database.Port, conf.Database.Database)
+	report := doctor.Run(ctx, []doctor.Check{
+		doctor.Config(nil),
+		doctor.Database(pool, address),
+		doctor.Migrations(database.NewDatabase(pool)),
+		doctor.Clock(pool, time.Now),
+		doctor.FileStore(conf.Fileserver.Volume, doctor.MinFreeSpace),
+		doctor.SMTP(smtpSender, conf.SMTP.Host != ""),
+	})
+	_ = report.Write(os.Stdout)
+	return report.ExitCode()
+}
+
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	// Setup, maintenance commands, and background jobs run as the system.
+	// Requests run as their own actor.
+	ctx = database.WithActor(ctx, database.System)
+
+	if len(os.Args) > 1 && os.Args[1] == doctorCommand {
+		os.Exit(runDoctor(ctx))
+	}
 
 	const setupTime = 30 * time.Second
 	setupCtx, cancel := context.WithTimeout(ctx, setupTime)
@@ -26,16 +120,20 @@ func main() {
 
 	logger := log.New(nil)
 
-	httpConfig := http.DefaultConfig()
-	httpConfig.Logger = logger
-	http := http.New(httpConfig)
-
 	conf, err := config.LoadConfig()
 	if err != nil {
 		logger.Error("failed to load config", slog.Any("error", err))
 		os.Exit(1)
 	}
 
+	// Appliance endpoints are registered by users, and other instances are
+	// named by their actors, so neither may reach this host.
+	httpConfig := http.DefaultConfig()
+	httpConfig.Logger = logger
+	http.Guard(httpConfig, appliance.AddressPolicy(conf.Appliances.AllowPrivateAddresses))
+	federationHTTP := http.NewGuarded(http.PublicAddress, federationTimeout)
+	http := http.New(httpConfig)
+
 	fs, err := setup.FileStore(conf)
 	if err != nil {
 		logger.Error("failed to setup file store", slog.Any("error", err))
@@ -48,6 +146,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == migrateStorageCommand {
+		if err := migrateStorage(ctx, logger, db, fs, os.Args[2:]); err != nil {
+			logger.Error("failed to migrate storage", slog.Any("error", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	sandbox, err := setup.Demo(setupCtx, conf)
+	if err != nil {
+		logger.Error("failed to setup demo sandbox", slog.Any("error", err))
+		os.Exit(1)
+	}
+	if sandbox != nil {
+		logger.Info("demo sandbox enabled, see GET /api/demo for its API key")
+	}
+
 	smtpSender, err := setup.SMTP(conf)
 	if err != nil {
 		logger.Error("failed to setup SMTP sender", slog.Any("error", err))
@@ -55,12 +170,16 @@ func main() {
 	}
 
 	env := &env.Env{
-		Logger:    logger,
-		FileStore: fs,
-		Database:  db,
-		SMTP:      smtpSender,
-		HTTP:      http,
-		Config:    conf,
+		Logger:         logger,
+		FileStore:      fs,
+		Database:       db,
+		SMTP:           smtpSender,
+		HTTP:           http,
+		FederationHTTP: federationHTTP,
+		Clock:          setup.Clock(),
+		IDGen:          setup.IDGenerator(),
+		Config:         conf,
+		Demo:           sandbox,
 	}
 
 	logger.DebugContext(ctx, "setting up admin")
@@ -75,6 +194,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	go tagging.RunSuggestionJob(ctx, env, tagging.DefaultInterval)
+	go undo.RunPurgeJob(ctx, env, undo.DefaultPurgeInterval)
+	go delivery.RunRetryJob(ctx, env, delivery.DefaultInterval)
+	if conf.SMTP.Host != "" {
+		go report.RunWeeklyJob(ctx, env, report.DefaultInterval)
+	}
+
 	if err := api.Start(env); err != nil {
 		env.Logger.Error("API Failed", slog.Any("error", err))
 		os.Exit(1)
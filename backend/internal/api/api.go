@@ -3,18 +3,25 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/matt-dz/wecook/docs"
 	apiError "github.com/matt-dz/wecook/internal/api/error"
 	"github.com/matt-dz/wecook/internal/api/middleware"
 	api "github.com/matt-dz/wecook/internal/api/openapi"
 	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/bandwidth"
 	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/federation"
+	"github.com/matt-dz/wecook/internal/filecrypt"
+	"github.com/matt-dz/wecook/internal/fileserver"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
 	"github.com/go-chi/chi/v5"
 	oapimw "github.com/oapi-codegen/nethttp-middleware"
 )
@@ -24,23 +31,62 @@ const (
 )
 
 func Start(env *env.Env) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	proxies, err := bandwidth.ParseTrustedProxies(env.Config.Fileserver.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	meter := &bandwidth.Meter{TrustedProxies: proxies}
+	if env.Config.Fileserver.Serve {
+		go meter.Report(ctx, env.Logger, bandwidth.DefaultReportInterval)
+	}
+
+	handler, err := NewHandler(env, meter)
+	if err != nil {
+		return err
+	}
+	s := &http.Server{
+		Handler: handler,
+		Addr:    "0.0.0.0:" + defaultPort,
+	}
+
+	env.Logger.Info(fmt.Sprintf("Listening at localhost:%s", defaultPort))
+	if !env.IsProd() {
+		env.Logger.Info(fmt.Sprintf("Swagger UI available at http://localhost:%s/docs/", defaultPort))
+	}
+	return s.ListenAndServe()
+}
+
+// NewHandler builds the API handler. When the backend serves files,
+// requests under the fileserver URL prefix bypass the API middleware and
+// their bandwidth is recorded in meter. When federation is enabled,
+// ActivityPub and WebFinger requests bypass it too.
+// When the API sandbox is enabled, requests presenting its key are
+// served from the sandbox.
+func NewHandler(env *env.Env, meter *bandwidth.Meter) (http.Handler, error) {
 	server := api.NewServer()
 	router := chi.NewMux()
 	spec, err := docs.Docs.ReadFile("api.yaml")
 	if err != nil {
-		return fmt.Errorf("reading openapi spec: %w", err)
+		return nil, fmt.Errorf("reading openapi spec: %w", err)
 	}
 	swagger, err := openapi3.NewLoader().LoadFromData(spec)
 	if err != nil {
-		return fmt.Errorf("creating openapi loader: %w", err)
+		return nil, fmt.Errorf("creating openapi loader: %w", err)
 	}
 	swagger.Servers = nil
+	specRouter, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("creating openapi router: %w", err)
+	}
 
-	router.Use(middleware.AddRequestID)
-	router.Use(middleware.LogRequest(env.Logger))
-	router.Use(middleware.InjectEnv(env))
-	router.Use(middleware.Recoverer)
 	router.Use(middleware.AddCors)
+	router.Use(middleware.StripVersionPrefix)
+	router.Use(middleware.SpecMethods(specRouter))
+	router.Use(middleware.DeprecationHeaders(specRouter))
+	router.Use(middleware.Lite)
+	router.Use(middleware.Demo)
 	router.Use(oapimw.OapiRequestValidatorWithOptions(swagger, &oapimw.Options{
 		Options: openapi3filter.Options{
 			AuthenticationFunc: middleware.OAPIAuthFunc,
@@ -65,14 +111,57 @@ func Start(env *env.Env) error {
 	api.HandlerFromMux(
 		api.NewStrictHandlerWithOptions(server, nil, strictHandlerOptions),
 		router)
-	s := &http.Server{
-		Handler: router,
-		Addr:    "0.0.0.0:" + defaultPort,
+
+	var handler http.Handler = router
+
+	// Files are served outside of the OpenAPI spec
+	if env.Config.Fileserver.Serve {
+		keyring, err := filecrypt.FromConfig(env.Config)
+		if err != nil {
+			return nil, fmt.Errorf("loading file encryption keys: %w", err)
+		}
+		var decrypter fileserver.Decrypter
+		if keyring != nil {
+			decrypter = keyring
+		}
+
+		prefix := strings.TrimSuffix(env.Config.Fileserver.URLPrefix, "/")
+		files := chi.Chain(
+			middleware.AllowMethods(http.MethodGet),
+			middleware.RestrictReferers(
+				env.Config.HostOrigin,
+				env.Config.Fileserver.AllowedReferers,
+				env.Config.Fileserver.BlockEmptyReferer),
+			meter.Middleware,
+		).Handler(http.StripPrefix(prefix, fileserver.Handler(env.Config.Fileserver.Volume, decrypter)))
+
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, prefix+"/") {
+				files.ServeHTTP(w, r)
+				return
+			}
+			router.ServeHTTP(w, r)
+		})
 	}
 
-	env.Logger.Info(fmt.Sprintf("Listening at localhost:%s", defaultPort))
-	if !env.IsProd() {
-		env.Logger.Info(fmt.Sprintf("Swagger UI available at http://localhost:%s/docs/", defaultPort))
+	// Federation is served outside of the OpenAPI spec
+	if env.Config.Federation.Enabled {
+		federated := federation.Handler(env)
+		next := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if federation.Handles(r.URL.Path) {
+				federated.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
 	}
-	return s.ListenAndServe()
+
+	return chi.Chain(
+		middleware.AddRequestID,
+		middleware.AddAnonymousActor,
+		middleware.LogRequest(env.Logger),
+		middleware.InjectEnv(env),
+		middleware.Recoverer,
+	).Handler(handler), nil
 }
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/bandwidth"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/demo"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func TestNewHandlerRouting(t *testing.T) {
+	volume := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(volume, "covers"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(volume, "covers", "a.png"), []byte("image"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	e := env.New(nil)
+	e.Logger = log.NullLogger()
+	e.Config.HostOrigin = "http://localhost:8080"
+	e.Config.Fileserver.Serve = true
+	e.Config.Fileserver.URLPrefix = "/files"
+	e.Config.Fileserver.Volume = volume
+	e.Config.Fileserver.AllowedReferers = []string{"friend.example.org"}
+
+	meter := &bandwidth.Meter{}
+	handler, err := NewHandler(e, meter)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		referer    string
+		wantStatus int
+		wantHeader map[string]string
+		wantEmpty  bool
+	}{
+		{
+			name:       "unversioned route",
+			method:     http.MethodGet,
+			path:       "/api/ping",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "versioned route",
+			method:     http.MethodGet,
+			path:       "/api/v1/ping",
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"API-Version": "v1"},
+		},
+		{
+			name:       "recipe schema",
+			method:     http.MethodGet,
+			path:       "/api/v1/schemas/recipe.json",
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Content-Type": "application/schema+json"},
+		},
+		{
+			name:       "cors preflight",
+			method:     http.MethodOptions,
+			path:       "/api/v1/recipes",
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{"Access-Control-Allow-Credentials": "true"},
+		},
+		{
+			name:       "head of get route",
+			method:     http.MethodHead,
+			path:       "/api/v1/versions",
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Content-Type": "application/json"},
+			wantEmpty:  true,
+		},
+		{
+			name:       "options lists path methods",
+			method:     http.MethodOptions,
+			path:       "/api/recipes/12",
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{
+				"Allow":                        "GET, HEAD, PATCH, DELETE, OPTIONS",
+				"Access-Control-Allow-Methods": "GET, HEAD, PATCH, DELETE, OPTIONS",
+			},
+		},
+		{
+			name:       "head of file",
+			method:     http.MethodHead,
+			path:       "/files/covers/a.png",
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Content-Length": "5"},
+			wantEmpty:  true,
+		},
+		{
+			name:       "options of file",
+			method:     http.MethodOptions,
+			path:       "/files/covers/a.png",
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{"Allow": "GET, HEAD, OPTIONS"},
+		},
+		{
+			name:       "post to file",
+			method:     http.MethodPost,
+			path:       "/files/covers/a.png",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantHeader: map[string]string{"Allow": "GET, HEAD, OPTIONS"},
+		},
+		{
+			name:       "served file",
+			method:     http.MethodGet,
+			path:       "/files/covers/a.png",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "hotlinked file",
+			method:     http.MethodGet,
+			path:       "/files/covers/a.png",
+			referer:    "https://evil.example.net/",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.referer != "" {
+				req.Header.Set("Referer", tt.referer)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantEmpty && rec.Body.Len() != 0 {
+				t.Errorf("expected empty body, got %q", rec.Body.String())
+			}
+			for key, want := range tt.wantHeader {
+				if got := rec.Header().Get(key); got != want {
+					t.Errorf("expected %s %q, got %q", key, want, got)
+				}
+			}
+		})
+	}
+
+	if usage := meter.Snapshot(); len(usage) != 1 || usage[0].Requests != 2 {
+		t.Errorf("expected two metered file requests, got %+v", usage)
+	}
+}
+
+func TestNewHandlerDemo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	sandboxDB := database.NewMockQuerier(ctrl)
+	sandboxDB.EXPECT().GetUserById(gomock.Any(), int64(7)).
+		Return(database.GetUserByIdRow{ID: 7, Email: demo.UserEmail, FirstName: "Demo", LastName: "Cook"}, nil)
+
+	e := env.New(nil)
+	e.Logger = log.NullLogger()
+	e.Config.HostOrigin = "http://localhost:8080"
+	e.Database = database.NewMockQuerier(ctrl)
+	e.Demo = &demo.Sandbox{Database: sandboxDB, UserID: 7, APIKey: "demo_try-me"}
+
+	handler, err := NewHandler(e, &bandwidth.Meter{})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "sandbox key", method: http.MethodGet, path: "/api/demo", wantStatus: http.StatusOK},
+		{name: "read as the example user", method: http.MethodGet, path: "/api/v1/user", wantStatus: http.StatusOK},
+		{
+			name:       "write rejected",
+			method:     http.MethodDelete,
+			path:       "/api/v1/recipes/1",
+			wantStatus: http.StatusForbidden,
+			wantCode:   "demo_read_only",
+		},
+		{
+			name:       "admin endpoint rejected",
+			method:     http.MethodGet,
+			path:       "/api/v1/users",
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "insufficient_permissions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer demo_try-me")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(rec.Body.String(), `"`+tt.wantCode+`"`) {
+				t.Errorf("expected code %s, got %s", tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package error
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Markdown renders the error catalog as a markdown document.
+func Markdown() string {
+	var b strings.Builder
+	b.WriteString("# API Error Codes\n\n")
+	b.WriteString("Every error response has the body ")
+	b.WriteString("`{\"code\", \"status\", \"message\", \"error_id\"}`. ")
+	b.WriteString("Clients should branch on `code`; `status` always matches the HTTP status of the response. ")
+	b.WriteString("Include `error_id` when reporting a problem.\n\n")
+	b.WriteString("| Code | Status | Description |\n")
+	b.WriteString("|------|--------|-------------|\n")
+	for _, d := range Definitions() {
+		status := "varies"
+		if d.Status != 0 {
+			status = fmt.Sprintf("%d %s", d.Status, http.StatusText(d.Status))
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s |\n", d.Code, status, d.Description)
+	}
+	return b.String()
+}
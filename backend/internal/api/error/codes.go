@@ -1,6 +1,10 @@
 package error
 
-import "net/http"
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
 
 type ErrorCode string
 
@@ -27,31 +31,109 @@ const (
 	InvalidInviteCode       ErrorCode = "invalid_invite_code"
 	InvalidPassword         ErrorCode = "invalid_password"
 	UnsupportedImageFormat  ErrorCode = "unsupported_image_format"
+	InvalidUploadURL        ErrorCode = "invalid_upload_url"
+	HotlinkNotAllowed       ErrorCode = "hotlink_not_allowed"
+	DensityVersionNotFound  ErrorCode = "density_version_not_found"
+	ApplianceNotFound       ErrorCode = "appliance_not_found"
+	ApplianceScopeDenied    ErrorCode = "appliance_scope_denied"
+	ApplianceUnavailable    ErrorCode = "appliance_unavailable"
+	InvalidUnsubscribeLink  ErrorCode = "invalid_unsubscribe_link"
+	UndoTokenNotFound       ErrorCode = "undo_token_not_found"
+	UndoConflict            ErrorCode = "undo_conflict"
+	DeliveryNotFound        ErrorCode = "delivery_not_found"
+	DeliveryNotRetryable    ErrorCode = "delivery_not_retryable"
+	StockImageNotFound      ErrorCode = "stock_image_not_found"
+	FederationDisabled      ErrorCode = "federation_disabled"
+	RemoteActorNotFound     ErrorCode = "remote_actor_not_found"
+	FollowNotFound          ErrorCode = "follow_not_found"
+	InvalidSignature        ErrorCode = "invalid_signature"
+	InvalidTimeZone         ErrorCode = "invalid_time_zone"
+	TagNotFound             ErrorCode = "tag_not_found"
+	TagConflict             ErrorCode = "tag_conflict"
+	DemoDisabled            ErrorCode = "demo_disabled"
+	DemoReadOnly            ErrorCode = "demo_read_only"
 )
 
-var errorCodeToStatusCode = map[ErrorCode]int{
-	UnknownError:            0, // No error code - unknown
-	InternalServerError:     http.StatusInternalServerError,
-	BadRequest:              http.StatusBadRequest,
-	UnprocessibleEntity:     http.StatusUnprocessableEntity,
-	InvalidAccessToken:      http.StatusUnauthorized,
-	ExpiredAccessToken:      http.StatusUnauthorized,
-	InsufficientPermissions: http.StatusForbidden,
-	WeakPassword:            http.StatusUnprocessableEntity,
-	EmailConflict:           http.StatusConflict,
-	AdminAlreadySetup:       http.StatusConflict,
-	RecipeNotFound:          http.StatusNotFound,
-	RecipeNotOwned:          http.StatusForbidden,
-	IngredientNotFound:      http.StatusNotFound,
-	StepNotFound:            http.StatusNotFound,
-	InvalidRefreshToken:     http.StatusUnauthorized,
-	ExpiredRefreshToken:     http.StatusUnauthorized,
-	InvalidCredentials:      http.StatusUnauthorized,
-	ImageNotFound:           http.StatusNotFound,
-	UserNotFound:            http.StatusNotFound,
-	InvalidInviteCode:       http.StatusUnprocessableEntity,
-	InvalidPassword:         http.StatusUnprocessableEntity,
-	UnsupportedImageFormat:  http.StatusUnprocessableEntity,
+// Definition pairs an error code with its canonical HTTP status.
+type Definition struct {
+	Code        ErrorCode
+	Status      int
+	Description string
+}
+
+// registry is the single source of truth for error codes. Every code
+// must be listed here; responses carrying a code must use its status.
+var registry = []Definition{
+	{UnknownError, 0, "The error could not be classified. The status varies."},
+	{InternalServerError, http.StatusInternalServerError, "The server failed to handle the request. Report the error ID."},
+	{BadRequest, http.StatusBadRequest, "The request is malformed or fails validation against the API spec."},
+	{UnprocessibleEntity, http.StatusUnprocessableEntity, "The request is well formed but its contents cannot be processed."},
+	{InvalidCredentials, http.StatusUnauthorized, "The email or password is incorrect."},
+	{InvalidAccessToken, http.StatusUnauthorized, "The access token is missing or invalid."},
+	{ExpiredAccessToken, http.StatusUnauthorized, "The access token has expired. Refresh the session and retry."},
+	{InvalidRefreshToken, http.StatusUnauthorized, "The refresh token is missing or invalid. Sign in again."},
+	{ExpiredRefreshToken, http.StatusUnauthorized, "The refresh token has expired. Sign in again."},
+	{InsufficientPermissions, http.StatusForbidden, "The user's role does not allow this operation."},
+	{WeakPassword, http.StatusUnprocessableEntity, "The new password does not meet the strength requirements."},
+	{EmailConflict, http.StatusConflict, "An account with this email already exists."},
+	{AdminAlreadySetup, http.StatusConflict, "The initial admin account has already been created."},
+	{RecipeNotFound, http.StatusNotFound, "The recipe does not exist or is not visible to the user."},
+	{RecipeNotOwned, http.StatusForbidden, "The recipe belongs to another user."},
+	{IngredientNotFound, http.StatusNotFound, "The ingredient does not exist on the recipe."},
+	{StepNotFound, http.StatusNotFound, "The step does not exist on the recipe."},
+	{ImageNotFound, http.StatusNotFound, "The resource has no image."},
+	{UserNotFound, http.StatusNotFound, "The user does not exist."},
+	{InvalidInviteCode, http.StatusUnprocessableEntity, "The invite code is unknown, used, or expired."},
+	{InvalidPassword, http.StatusUnprocessableEntity, "The current password is incorrect."},
+	{UnsupportedImageFormat, http.StatusUnprocessableEntity, "The image is not a supported format or its extension does not match its contents."},
+	{InvalidUploadURL, http.StatusForbidden, "The signed upload URL is invalid, expired, or already used."},
+	{HotlinkNotAllowed, http.StatusForbidden, "The file may not be embedded by the requesting site."},
+	{DensityVersionNotFound, http.StatusNotFound, "The ingredient densities version does not exist."},
+	{ApplianceNotFound, http.StatusNotFound, "The appliance does not exist or belongs to another user."},
+	{ApplianceScopeDenied, http.StatusForbidden, "The appliance was not registered to accept this action."},
+	{ApplianceUnavailable, http.StatusBadGateway, "The appliance could not be reached or rejected the command."},
+	{InvalidUnsubscribeLink, http.StatusForbidden, "The unsubscribe link is invalid."},
+	{UndoTokenNotFound, http.StatusNotFound, "The undo token is unknown, expired, or already used."},
+	{UndoConflict, http.StatusConflict, "The deletion can no longer be undone because the recipe changed since."},
+	{DeliveryNotFound, http.StatusNotFound, "The delivery does not exist or was removed after being sent."},
+	{DeliveryNotRetryable, http.StatusConflict, "The delivery was already sent or is being sent."},
+	{StockImageNotFound, http.StatusNotFound, "The stock image does not exist, or no stock image matches the ingredient."},
+	{FederationDisabled, http.StatusNotFound, "Federation is not enabled on this instance."},
+	{RemoteActorNotFound, http.StatusUnprocessableEntity, "The handle could not be resolved to a user of another instance."},
+	{FollowNotFound, http.StatusNotFound, "The user does not follow the actor."},
+	{InvalidSignature, http.StatusUnauthorized, "The HTTP signature of the activity is missing, expired, or invalid."},
+	{InvalidTimeZone, http.StatusUnprocessableEntity, "The time zone is not a known IANA time zone name."},
+	{TagNotFound, http.StatusNotFound, "None of the user's recipes have the tag."},
+	{TagConflict, http.StatusConflict, "The user already uses the tag name. Merge the tags instead."},
+	{DemoDisabled, http.StatusNotFound, "The API sandbox is not enabled on this instance."},
+	{DemoReadOnly, http.StatusForbidden, "Requests made with the sandbox API key can only read."},
+}
+
+var errorCodeToStatusCode = func() map[ErrorCode]int {
+	m := make(map[ErrorCode]int, len(registry))
+	for _, d := range registry {
+		m[d.Code] = d.Status
+	}
+	return m
+}()
+
+// Definitions returns every registered error code, sorted by code.
+func Definitions() []Definition {
+	definitions := slices.Clone(registry)
+	slices.SortFunc(definitions, func(a, b Definition) int {
+		return strings.Compare(string(a.Code), string(b.Code))
+	})
+	return definitions
+}
+
+// Lookup returns the definition of code.
+func Lookup(code ErrorCode) (Definition, bool) {
+	for _, d := range registry {
+		if d.Code == code {
+			return d, true
+		}
+	}
+	return Definition{}, false
 }
 
 func (ec ErrorCode) StatusCode() int {
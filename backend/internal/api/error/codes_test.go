@@ -0,0 +1,87 @@
+package error
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite docs/errors.md")
+
+func TestRegistry(t *testing.T) {
+	seen := make(map[ErrorCode]bool)
+	for _, d := range registry {
+		if seen[d.Code] {
+			t.Errorf("code %q registered twice", d.Code)
+		}
+		seen[d.Code] = true
+
+		if d.Code != UnknownError && http.StatusText(d.Status) == "" {
+			t.Errorf("code %q has invalid status %d", d.Code, d.Status)
+		}
+		if d.Description == "" {
+			t.Errorf("code %q has no description", d.Code)
+		}
+		if got := d.Code.StatusCode(); got != d.Status {
+			t.Errorf("%q.StatusCode() = %d, want %d", d.Code, got, d.Status)
+		}
+		if got, ok := Lookup(d.Code); !ok || got != d {
+			t.Errorf("Lookup(%q) = %+v, %v", d.Code, got, ok)
+		}
+	}
+
+	if _, ok := Lookup("no_such_code"); ok {
+		t.Error("Lookup of an unregistered code succeeded")
+	}
+}
+
+func TestDefinitionsSorted(t *testing.T) {
+	definitions := Definitions()
+	if len(definitions) != len(registry) {
+		t.Fatalf("expected %d definitions, got %d", len(registry), len(definitions))
+	}
+	if !slices.IsSortedFunc(definitions, func(a, b Definition) int {
+		return strings.Compare(string(a.Code), string(b.Code))
+	}) {
+		t.Error("definitions are not sorted by code")
+	}
+}
+
+func TestNewUsesCanonicalStatus(t *testing.T) {
+	e := New(RecipeNotFound, "recipe not found", "123")
+	if e.Status != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, e.Status)
+	}
+	if e.Code != RecipeNotFound || e.Message != "recipe not found" || e.ErrorID != "123" {
+		t.Errorf("unexpected error %+v", e)
+	}
+}
+
+// TestMarkdownUpToDate keeps the committed catalog in sync with the
+// registry. Run with -update after changing error codes.
+func TestMarkdownUpToDate(t *testing.T) {
+	path := filepath.Join("..", "..", "..", "docs", "errors.md")
+	markdown := Markdown()
+	if *update {
+		if err := os.WriteFile(path, []byte(markdown), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", path, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	if string(data) != markdown {
+		t.Errorf("%s is out of date; run go test ./internal/api/error -update", path)
+	}
+	for _, d := range registry {
+		if !strings.Contains(markdown, "| `"+string(d.Code)+"` |") {
+			t.Errorf("catalog is missing %q", d.Code)
+		}
+	}
+}
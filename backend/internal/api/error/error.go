@@ -22,7 +22,8 @@ func (e *Error) Error() string {
 	return string(data)
 }
 
-func buildError(code ErrorCode, message, errorID string) Error {
+// New builds an error response carrying the canonical status of code.
+func New(code ErrorCode, message, errorID string) Error {
 	return Error{
 		Code:    code,
 		Status:  errorCodeToStatusCode[code],
@@ -35,7 +36,7 @@ func EncodeError(w http.ResponseWriter, code ErrorCode, message, errorID string)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(errorCodeToStatusCode[code])
 
-	if err := json.NewEncoder(w).Encode(buildError(code, message, errorID)); err != nil {
+	if err := json.NewEncoder(w).Encode(New(code, message, errorID)); err != nil {
 		return fmt.Errorf("encoding error: %w", err)
 	}
 	return nil
@@ -59,7 +60,7 @@ func EncodeInternalError(w http.ResponseWriter, errorID string) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(errorCodeToStatusCode[InternalServerError])
 
-	res := buildError(InternalServerError, "Internal Server Error", errorID)
+	res := New(InternalServerError, "Internal Server Error", errorID)
 	if err := json.NewEncoder(w).Encode(res); err != nil {
 		return fmt.Errorf("encoding error: %w", err)
 	}
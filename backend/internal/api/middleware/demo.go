@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/demo"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+// Demo serves requests that present the sandbox API key as a Bearer
+// token from the sandbox: they read the sandbox schema as its example
+// user. Sandbox requests can only read; any other method is rejected.
+// Requests without the key, and every request when the sandbox is not
+// enabled, are passed on unchanged.
+func Demo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := env.EnvFromCtx(r.Context())
+		if e.Demo == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, err := token.ParseBearerToken(r.Header.Get(token.AuthorizationHeader))
+		if err != nil || !e.Demo.Matches(key) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method != http.MethodGet {
+			requestID := fmt.Sprintf("%d", requestid.ExtractRequestID(r.Context()))
+			_ = apiError.EncodeError(w, apiError.DemoReadOnly, "the API sandbox is read-only", requestID)
+			return
+		}
+
+		ctx := demo.WithCtx(r.Context())
+		ctx = log.AppendCtx(ctx, slog.Bool("demo", true))
+		ctx = env.WithCtx(ctx, e.WithDatabase(e.Demo.Database))
+		ctx = token.UserIDWithCtx(ctx, e.Demo.UserID)
+		ctx = database.WithActor(ctx, database.Actor{UserID: e.Demo.UserID})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+)
+
+// RestrictReferers rejects requests whose Origin or Referer header points
+// at a host other than that of hostOrigin or one of allowed, so that third
+// party sites cannot embed served files. Entries of allowed may be bare
+// hosts, origins, or wildcards such as "*.example.com"; an entry without a
+// port matches any port. Requests without either header are let through
+// unless blockEmpty is set. The check is skipped entirely when allowed is
+// empty and blockEmpty is false.
+func RestrictReferers(hostOrigin string, allowed []string, blockEmpty bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(allowed) == 0 && !blockEmpty {
+			return next
+		}
+
+		patterns := make([]string, 0, len(allowed)+1)
+		if host := refererHost(hostOrigin); host != "" {
+			patterns = append(patterns, host)
+		}
+		for _, entry := range allowed {
+			if host := refererHost(entry); host != "" {
+				patterns = append(patterns, host)
+			}
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			source := r.Header.Get("Origin")
+			if source == "" {
+				source = r.Header.Get("Referer")
+			}
+
+			if source == "" && !blockEmpty {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if source != "" {
+				host := refererHost(source)
+				for _, pattern := range patterns {
+					if host != "" && matchHost(pattern, host) {
+						next.ServeHTTP(w, r)
+						return
+					}
+				}
+			}
+
+			requestID := strconv.FormatUint(requestid.ExtractRequestID(r.Context()), 10)
+			_ = apiError.EncodeError(w, apiError.HotlinkNotAllowed, "file may not be embedded by this site", requestID)
+		})
+	}
+}
+
+// refererHost returns the lowercased host of an origin, URL, or bare host.
+func refererHost(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "://") {
+		value = "//" + value
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// matchHost reports whether host matches pattern. A pattern without a
+// port matches the host on any port and a leading "*." matches any
+// subdomain.
+func matchHost(pattern, host string) bool {
+	patternName, patternPort := splitHostPort(pattern)
+	hostName, hostPort := splitHostPort(host)
+	if patternPort != "" && patternPort != hostPort {
+		return false
+	}
+	if suffix, ok := strings.CutPrefix(patternName, "*."); ok {
+		return strings.HasSuffix(hostName, "."+suffix)
+	}
+	return hostName == patternName
+}
+
+func splitHostPort(hostport string) (host, port string) {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return strings.Trim(hostport, "[]"), ""
+	}
+	return host, port
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+)
+
+func TestRestrictReferers(t *testing.T) {
+	const hostOrigin = "https://recipes.example.com"
+
+	tests := []struct {
+		name       string
+		allowed    []string
+		blockEmpty bool
+		origin     string
+		referer    string
+		wantStatus int
+	}{
+		{
+			name:       "disabled without allowlist",
+			referer:    "https://evil.example.net/page",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "own origin is allowed",
+			allowed:    []string{"friend.example.org"},
+			referer:    "https://recipes.example.com/recipes/1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "listed host is allowed",
+			allowed:    []string{"friend.example.org"},
+			referer:    "https://friend.example.org:8443/blog",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "listed origin is allowed",
+			allowed:    []string{"https://friend.example.org"},
+			origin:     "https://FRIEND.example.org",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "listed port must match",
+			allowed:    []string{"friend.example.org:8443"},
+			referer:    "https://friend.example.org/blog",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "wildcard matches subdomains",
+			allowed:    []string{"*.example.org"},
+			referer:    "https://blog.example.org/post",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "wildcard does not match apex",
+			allowed:    []string{"*.example.org"},
+			referer:    "https://example.org/post",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "unlisted host is rejected",
+			allowed:    []string{"friend.example.org"},
+			referer:    "https://evil.example.net/page",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "origin takes precedence over referer",
+			allowed:    []string{"friend.example.org"},
+			origin:     "https://evil.example.net",
+			referer:    "https://friend.example.org/blog",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "opaque origin is rejected",
+			allowed:    []string{"friend.example.org"},
+			origin:     "null",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "empty referer is allowed by default",
+			allowed:    []string{"friend.example.org"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "empty referer is rejected when blocked",
+			blockEmpty: true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "own origin is allowed when blocking empty referers",
+			blockEmpty: true,
+			referer:    "https://recipes.example.com/",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RestrictReferers(hostOrigin, tt.allowed, tt.blockEmpty)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}))
+
+			req := httptest.NewRequest(http.MethodGet, "/files/recipes/1.png", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.referer != "" {
+				req.Header.Set("Referer", tt.referer)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantStatus == http.StatusForbidden &&
+				!strings.Contains(rec.Body.String(), apiError.HotlinkNotAllowed.String()) {
+				t.Errorf("expected %s error, got %s", apiError.HotlinkNotAllowed, rec.Body.String())
+			}
+		})
+	}
+}
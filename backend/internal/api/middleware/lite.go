@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// LiteHeader and LiteParam ask for low-bandwidth responses. Browsers with
+// data saving enabled send "Save-Data: on", which is honored too.
+const (
+	LiteHeader     = "Lite"
+	LiteParam      = "lite"
+	saveDataHeader = "Save-Data"
+)
+
+// LiteDescriptionLength is the number of characters descriptions are
+// truncated to in low-bandwidth responses.
+const LiteDescriptionLength = 140
+
+// litePrefix is the path prefix of the endpoints that honor lite mode.
+const litePrefix = "/api/recipes"
+
+// Lite shapes the JSON responses of recipe endpoints for clients on
+// metered connections when they ask for it with ?lite=true, "Lite: true",
+// or "Save-Data: on". Image URLs are omitted and descriptions longer than
+// LiteDescriptionLength are truncated. Shaped responses carry
+// "Lite: true".
+func Lite(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != litePrefix && !strings.HasPrefix(r.URL.Path, litePrefix+"/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", LiteHeader+", "+saveDataHeader)
+		if !wantsLite(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		lw := &liteWriter{ResponseWriter: w}
+		next.ServeHTTP(lw, r)
+		lw.finish()
+	})
+}
+
+// wantsLite reports whether r asks for a low-bandwidth response.
+func wantsLite(r *http.Request) bool {
+	if lite, err := strconv.ParseBool(r.URL.Query().Get(LiteParam)); err == nil {
+		return lite
+	}
+	if lite, err := strconv.ParseBool(r.Header.Get(LiteHeader)); err == nil {
+		return lite
+	}
+	return strings.EqualFold(r.Header.Get(saveDataHeader), "on")
+}
+
+// liteWriter buffers a response so it can be shaped before it is sent.
+type liteWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *liteWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *liteWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(p)
+}
+
+// finish shapes successful JSON responses and sends the response. Other
+// responses, and bodies that fail to decode, are sent unchanged.
+func (w *liteWriter) finish() {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := w.body.Bytes()
+
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if status >= 200 && status < 300 && mediaType == "application/json" {
+		if shaped, err := shapeLite(body); err == nil {
+			body = shaped
+			w.Header().Set(LiteHeader, "true")
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+	_, _ = w.ResponseWriter.Write(body)
+}
+
+// shapeLite removes image URLs from a JSON document and truncates its
+// descriptions.
+func shapeLite(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// Keep IDs exact.
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	shaped, err := json.Marshal(shapeValue(doc))
+	if err != nil {
+		return nil, err
+	}
+	return append(shaped, '\n'), nil
+}
+
+func shapeValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		delete(v, "image_url")
+		for key, value := range v {
+			if s, ok := value.(string); ok && key == "description" {
+				v[key] = truncate(s, LiteDescriptionLength)
+				continue
+			}
+			v[key] = shapeValue(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = shapeValue(value)
+		}
+	}
+	return v
+}
+
+// truncate shortens s to n characters, ending it with an ellipsis.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimRight(string(runes[:n-1]), " ") + "…"
+}
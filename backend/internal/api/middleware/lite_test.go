@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const liteRecipe = `{"recipe":{"id":9007199254740993,"image_url":"http://host/files/covers/a.png",` +
+	`"description":"` + "A slow-cooked ragù that takes most of a Sunday afternoon, " +
+	"simmered with milk and wine until the beef falls apart and the sauce clings " +
+	"to every strand of fresh tagliatelle." + `"},` +
+	`"ingredients":[{"id":1,"description":"salt","image_url":"http://host/files/ingredients/b.png"}]}`
+
+func serveLite(t *testing.T, method, target string, header http.Header, status int, contentType string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := Lite(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(liteRecipe))
+	}))
+	req := httptest.NewRequest(method, target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLite_ShapesRecipeResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		header http.Header
+	}{
+		{"query parameter", "/api/recipes/12?lite=true", nil},
+		{"lite header", "/api/recipes", http.Header{"Lite": {"1"}}},
+		{"save data header", "/api/recipes/public", http.Header{"Save-Data": {"on"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveLite(t, http.MethodGet, tt.target, tt.header, http.StatusOK, "application/json")
+
+			body := rec.Body.String()
+			if strings.Contains(body, "image_url") {
+				t.Errorf("expected image URLs to be omitted, got %s", body)
+			}
+			if !strings.Contains(body, `"id":9007199254740993`) {
+				t.Errorf("expected IDs to be kept exactly, got %s", body)
+			}
+			if !strings.Contains(body, `"description":"salt"`) {
+				t.Errorf("expected short descriptions to be kept, got %s", body)
+			}
+			if !strings.Contains(body, "…") {
+				t.Errorf("expected long description to be truncated, got %s", body)
+			}
+			if rec.Header().Get(LiteHeader) != "true" {
+				t.Errorf("expected %s header, got %q", LiteHeader, rec.Header().Get(LiteHeader))
+			}
+			if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len(body)) {
+				t.Errorf("expected Content-Length %d, got %s", len(body), got)
+			}
+		})
+	}
+}
+
+func TestLite_LeavesOtherResponsesUnchanged(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		header      http.Header
+		status      int
+		contentType string
+	}{
+		{"not requested", "/api/recipes/12", nil, http.StatusOK, "application/json"},
+		{"turned off", "/api/recipes/12?lite=false", http.Header{"Save-Data": {"on"}}, http.StatusOK, "application/json"},
+		{"other endpoint", "/api/user/preferences?lite=true", nil, http.StatusOK, "application/json"},
+		{"error response", "/api/recipes/12?lite=true", nil, http.StatusNotFound, "application/json"},
+		{"not json", "/api/recipes/12?lite=true", nil, http.StatusOK, "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveLite(t, http.MethodGet, tt.target, tt.header, tt.status, tt.contentType)
+
+			if rec.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, rec.Code)
+			}
+			if rec.Body.String() != liteRecipe {
+				t.Errorf("expected body to be unchanged, got %s", rec.Body.String())
+			}
+			if rec.Header().Get(LiteHeader) != "" {
+				t.Errorf("expected no %s header", LiteHeader)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("ragù alla bolognese", 6); got != "ragù…" {
+		t.Errorf("truncate() = %q, want %q", got, "ragù…")
+	}
+	if got := truncate("ragù", 6); got != "ragù" {
+		t.Errorf("truncate() = %q, want %q", got, "ragù")
+	}
+}
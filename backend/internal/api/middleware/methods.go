@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/routers"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+)
+
+// sniffLen is the number of body bytes used to detect the content type
+// of HEAD responses, matching net/http.
+const sniffLen = 512
+
+// specMethods are the methods looked up in the OpenAPI spec when
+// listing the methods a path allows.
+var specMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// SpecMethods answers HEAD and OPTIONS for every path in the OpenAPI
+// spec. HEAD requests to GET operations run the GET handler and send
+// its status and headers without the body. OPTIONS requests are answered
+// with the methods the path allows in the Allow and
+// Access-Control-Allow-Methods headers. Requests to paths missing from
+// the spec are passed on unchanged.
+func SpecMethods(router routers.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodHead:
+				get := r.Clone(r.Context())
+				get.Method = http.MethodGet
+				if _, _, err := router.FindRoute(get); err != nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+				hw := &headWriter{ResponseWriter: w}
+				next.ServeHTTP(hw, get)
+				hw.finish()
+			case http.MethodOptions:
+				allowed := allowedMethods(router, r)
+				if len(allowed) == 0 {
+					next.ServeHTTP(w, r)
+					return
+				}
+				methods := strings.Join(allowed, ", ")
+				w.Header().Set("Allow", methods)
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// AllowMethods restricts a route outside of the OpenAPI spec to the given
+// methods. OPTIONS is answered with the allowed methods and any other
+// method gets a 405. HEAD is allowed whenever GET is.
+func AllowMethods(methods ...string) func(http.Handler) http.Handler {
+	allowed := slices.Clone(methods)
+	if slices.Contains(allowed, http.MethodGet) && !slices.Contains(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	allowed = append(allowed, http.MethodOptions)
+	allow := strings.Join(allowed, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodOptions:
+				w.Header().Set("Allow", allow)
+				w.WriteHeader(http.StatusNoContent)
+			case !slices.Contains(allowed, r.Method):
+				w.Header().Set("Allow", allow)
+				requestID := strconv.FormatUint(requestid.ExtractRequestID(r.Context()), 10)
+				_ = apiError.EncodeUnknownError(w, "method not allowed", requestID, http.StatusMethodNotAllowed)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// allowedMethods lists the methods the spec defines for the path of r,
+// including HEAD for GET operations and OPTIONS. It is empty if the path
+// is not in the spec.
+func allowedMethods(router routers.Router, r *http.Request) []string {
+	var allowed []string
+	probe := r.Clone(r.Context())
+	for _, method := range specMethods {
+		probe.Method = method
+		if _, _, err := router.FindRoute(probe); err == nil {
+			allowed = append(allowed, method)
+			if method == http.MethodGet {
+				allowed = append(allowed, http.MethodHead)
+			}
+		}
+	}
+	if len(allowed) > 0 {
+		allowed = append(allowed, http.MethodOptions)
+	}
+	return allowed
+}
+
+// headWriter discards the body of a response while keeping the headers
+// it would have been sent with.
+type headWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+	sniff  []byte
+}
+
+func (w *headWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *headWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if len(w.sniff) < sniffLen {
+		w.sniff = append(w.sniff, p[:min(len(p), sniffLen-len(w.sniff))]...)
+	}
+	w.size += len(p)
+	return len(p), nil
+}
+
+// finish sends the status and the headers the body would have set.
+func (w *headWriter) finish() {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	h := w.Header()
+	if w.size > 0 {
+		if h.Get("Content-Type") == "" {
+			h.Set("Content-Type", http.DetectContentType(w.sniff))
+		}
+		if h.Get("Content-Length") == "" && h.Get("Transfer-Encoding") == "" {
+			h.Set("Content-Length", strconv.Itoa(w.size))
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func TestSpecMethods(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /api/items:
+    get:
+      responses:
+        "200":
+          description: OK
+    post:
+      responses:
+        "201":
+          description: Created
+  /api/items/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    put:
+      responses:
+        "204":
+          description: No Content
+    delete:
+      responses:
+        "204":
+          description: No Content
+`)
+	swagger, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	const body = `{"items":[]}`
+	var gotMethod string
+	handler := SpecMethods(router)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		w.Header().Set("ETag", `"abc"`)
+		_, _ = w.Write([]byte(body))
+	}))
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		wantStatus    int
+		wantMethod    string
+		wantBody      string
+		wantAllow     string
+		wantHeaders   map[string]string
+		wantNoHandler bool
+	}{
+		{
+			name:       "head runs get handler without body",
+			method:     http.MethodHead,
+			path:       "/api/items",
+			wantStatus: http.StatusOK,
+			wantMethod: http.MethodGet,
+			wantHeaders: map[string]string{
+				"ETag":           `"abc"`,
+				"Content-Length": strconv.Itoa(len(body)),
+				"Content-Type":   "text/plain; charset=utf-8",
+			},
+		},
+		{
+			name:       "head without get operation is passed on",
+			method:     http.MethodHead,
+			path:       "/api/items/1",
+			wantStatus: http.StatusOK,
+			wantMethod: http.MethodHead,
+			wantBody:   body,
+		},
+		{
+			name:          "options lists methods of collection",
+			method:        http.MethodOptions,
+			path:          "/api/items",
+			wantStatus:    http.StatusNoContent,
+			wantAllow:     "GET, HEAD, POST, OPTIONS",
+			wantNoHandler: true,
+		},
+		{
+			name:          "options lists methods of item",
+			method:        http.MethodOptions,
+			path:          "/api/items/1",
+			wantStatus:    http.StatusNoContent,
+			wantAllow:     "PUT, DELETE, OPTIONS",
+			wantNoHandler: true,
+		},
+		{
+			name:       "options of unknown path is passed on",
+			method:     http.MethodOptions,
+			path:       "/api/missing",
+			wantStatus: http.StatusOK,
+			wantMethod: http.MethodOptions,
+			wantBody:   body,
+		},
+		{
+			name:       "other methods are passed on",
+			method:     http.MethodPost,
+			path:       "/api/items",
+			wantStatus: http.StatusOK,
+			wantMethod: http.MethodPost,
+			wantBody:   body,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMethod = ""
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantNoHandler && gotMethod != "" {
+				t.Errorf("expected handler not to run, got %s", gotMethod)
+			}
+			if !tt.wantNoHandler && gotMethod != tt.wantMethod {
+				t.Errorf("expected handler method %s, got %s", tt.wantMethod, gotMethod)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, got)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("expected Allow %q, got %q", tt.wantAllow, got)
+			}
+			if tt.wantAllow != "" {
+				if got := rec.Header().Get("Access-Control-Allow-Methods"); got != tt.wantAllow {
+					t.Errorf("expected Access-Control-Allow-Methods %q, got %q", tt.wantAllow, got)
+				}
+			}
+			for key, want := range tt.wantHeaders {
+				if got := rec.Header().Get(key); got != want {
+					t.Errorf("expected %s %q, got %q", key, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestAllowMethods(t *testing.T) {
+	handler := AllowMethods(http.MethodGet)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		method     string
+		wantStatus int
+		wantAllow  string
+	}{
+		{method: http.MethodGet, wantStatus: http.StatusOK},
+		{method: http.MethodHead, wantStatus: http.StatusOK},
+		{method: http.MethodOptions, wantStatus: http.StatusNoContent, wantAllow: "GET, HEAD, OPTIONS"},
+		{method: http.MethodDelete, wantStatus: http.StatusMethodNotAllowed, wantAllow: "GET, HEAD, OPTIONS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, "/files/a.png", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("expected Allow %q, got %q", tt.wantAllow, got)
+			}
+		})
+	}
+}
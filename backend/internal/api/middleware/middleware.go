@@ -19,6 +19,8 @@ import (
 	apiError "github.com/matt-dz/wecook/internal/api/error"
 	"github.com/matt-dz/wecook/internal/api/requestid"
 	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/demo"
 	"github.com/matt-dz/wecook/internal/env"
 	wcJwt "github.com/matt-dz/wecook/internal/jwt"
 	"github.com/matt-dz/wecook/internal/log"
@@ -63,6 +65,14 @@ func AddRequestID(next http.Handler) http.Handler {
 	})
 }
 
+// AddAnonymousActor runs the database statements of a request as an
+// anonymous user until authentication identifies the user.
+func AddAnonymousActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(database.WithActor(r.Context(), database.Anonymous)))
+	})
+}
+
 // AddCors adds the necessary CORS headers to the response.
 func AddCors(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -90,16 +100,14 @@ func AddCors(next http.Handler) http.Handler {
 		}
 
 		w.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, DELETE, OPTIONS, PATCH")
 		w.Header().Set("Access-Control-Max-Age", "86400")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-CSRF-Token")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Add("Vary", "Origin")
 
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-
+		// Preflight requests are answered by SpecMethods with the methods
+		// of the requested path.
 		next.ServeHTTP(w, r)
 	})
 }
@@ -169,6 +177,20 @@ func OAPIAuthFunc(ctx context.Context, input *openapi3filter.AuthenticationInput
 	env := env.EnvFromCtx(ctx)
 	requestID := fmt.Sprintf("%d", requestid.ExtractRequestID(ctx))
 
+	// Sandbox requests were authenticated by Demo as the example user
+	if demo.FromCtx(ctx) {
+		if requiredRole > role.RoleUser {
+			env.Logger.ErrorContext(ctx, "sandbox requests cannot use admin endpoints")
+			return &apiError.Error{
+				Code:    apiError.InsufficientPermissions,
+				Status:  apiError.InsufficientPermissions.StatusCode(),
+				Message: "insufficient permissions",
+				ErrorID: requestID,
+			}
+		}
+		return nil
+	}
+
 	var accessToken string
 	cookie, err := input.RequestValidationInput.Request.Cookie(token.AccessTokenName())
 	if err != nil {
@@ -204,7 +226,8 @@ func OAPIAuthFunc(ctx context.Context, input *openapi3filter.AuthenticationInput
 		accessToken = cookie.Value
 	}
 
-	accessJwt, err := wcJwt.ValidateJWT(accessToken, env.Config.AppSecret.Version, []byte(*env.Config.AppSecret.Value))
+	accessJwt, err := wcJwt.ValidateJWT(
+		accessToken, env.Config.AppSecret.Version, []byte(*env.Config.AppSecret.Value), env.Now())
 	if errors.Is(err, jwt.ErrTokenExpired) {
 		env.Logger.ErrorContext(ctx, "jwt expired", slog.Any("error", err))
 		return &apiError.Error{
@@ -262,6 +285,10 @@ func OAPIAuthFunc(ctx context.Context, input *openapi3filter.AuthenticationInput
 	r := input.RequestValidationInput.Request
 	r = r.WithContext(log.AppendCtx(r.Context(), slog.Int64("user-id", userID)))
 	r = r.WithContext(token.UserIDWithCtx(r.Context(), userID))
+	r = r.WithContext(database.WithActor(r.Context(), database.Actor{
+		UserID: userID,
+		Admin:  userRole >= role.RoleAdmin,
+	}))
 	r = r.WithContext(token.AccessTokenWithCtx(r.Context(), accessJwt))
 	*input.RequestValidationInput.Request = *r
 
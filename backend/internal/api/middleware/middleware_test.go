@@ -7,11 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3filter"
 	apiError "github.com/matt-dz/wecook/internal/api/error"
 	"github.com/matt-dz/wecook/internal/api/requestid"
 	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/clock"
 	"github.com/matt-dz/wecook/internal/config"
 	"github.com/matt-dz/wecook/internal/env"
 	mJwt "github.com/matt-dz/wecook/internal/jwt"
@@ -439,3 +441,72 @@ func TestOAPIAuthFunc_ContextInjection(t *testing.T) {
 		t.Error("expected non-nil access token in context")
 	}
 }
+
+func TestOAPIAuthFunc_AccessTokenExpiry(t *testing.T) {
+	appSecret := config.AppSecretValue("test-secret-32-bytes-long-12345")
+	issuedAt := time.Date(2025, time.March, 9, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		elapsed       time.Duration
+		wantErrorCode apiError.ErrorCode
+	}{
+		{
+			name:    "token within lifetime",
+			elapsed: mJwt.JWTDuration - time.Second,
+		},
+		{
+			name:          "token past lifetime",
+			elapsed:       mJwt.JWTDuration + time.Second,
+			wantErrorCode: apiError.ExpiredAccessToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewFrozen(issuedAt)
+			e := env.New(nil)
+			e.Config.AppSecret.Value = &appSecret
+			e.Config.AppSecret.Version = "1"
+			e.Logger = log.NullLogger()
+			e.Clock = clk
+
+			accessToken, err := token.NewAccessToken(mJwt.JWTParams{
+				UserID: "123",
+				Role:   role.RoleUser,
+			}, e)
+			if err != nil {
+				t.Fatalf("failed to create access token: %v", err)
+			}
+			clk.Advance(tt.elapsed)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(token.AuthorizationHeader, "Bearer "+accessToken)
+
+			ctx := context.Background()
+			ctx = env.WithCtx(ctx, e)
+			ctx = requestid.InjectRequestID(ctx, 12345)
+
+			err = OAPIAuthFunc(ctx, &openapi3filter.AuthenticationInput{
+				SecuritySchemeName: "AccessTokenUserBearer",
+				RequestValidationInput: &openapi3filter.RequestValidationInput{
+					Request: req,
+				},
+			})
+
+			if tt.wantErrorCode == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			var apiErr *apiError.Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected apiError, got %T", err)
+			}
+			if apiErr.Code != tt.wantErrorCode {
+				t.Errorf("expected error code %s, got %s", tt.wantErrorCode, apiErr.Code)
+			}
+		})
+	}
+}
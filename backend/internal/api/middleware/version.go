@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/routers"
+
+	"github.com/matt-dz/wecook/internal/api/version"
+)
+
+// Extensions read from deprecated OpenAPI operations.
+const (
+	deprecatedAtExtension    = "x-deprecated-at"
+	sunsetExtension          = "x-sunset"
+	deprecationLinkExtension = "x-deprecation-link"
+)
+
+// Deprecation describes an endpoint slated for removal.
+type Deprecation struct {
+	// At is when the endpoint was deprecated. Optional.
+	At time.Time
+	// Sunset is when the endpoint will stop responding. Optional.
+	Sunset time.Time
+	// Link points to documentation about the replacement. Optional.
+	Link string
+}
+
+// SetHeaders sets the Deprecation (RFC 9745), Sunset (RFC 8594),
+// and deprecation Link headers.
+func (d Deprecation) SetHeaders(h http.Header) {
+	if d.At.IsZero() {
+		h.Set("Deprecation", "true")
+	} else {
+		h.Set("Deprecation", "@"+strconv.FormatInt(d.At.Unix(), 10))
+	}
+	if !d.Sunset.IsZero() {
+		h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		h.Add("Link", "<"+d.Link+`>; rel="deprecation"`)
+	}
+}
+
+// Deprecated marks every response of the wrapped handler as deprecated.
+// It is meant for routes mounted outside of the OpenAPI spec.
+func Deprecated(d Deprecation) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d.SetHeaders(w.Header())
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DeprecationHeaders adds deprecation headers to responses of operations
+// marked `deprecated: true` in the OpenAPI spec. The deprecation and
+// sunset dates and the documentation link are read from the
+// x-deprecated-at, x-sunset, and x-deprecation-link extensions.
+func DeprecationHeaders(router routers.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, _, err := router.FindRoute(r)
+			if err == nil && route.Operation != nil && route.Operation.Deprecated {
+				extensions := route.Operation.Extensions
+				Deprecation{
+					At:     extensionTime(extensions, deprecatedAtExtension),
+					Sunset: extensionTime(extensions, sunsetExtension),
+					Link:   extensionString(extensions, deprecationLinkExtension),
+				}.SetHeaders(w.Header())
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// StripVersionPrefix serves requests under the versioned prefix
+// (/api/v1/...) from the unversioned routes and reports the API version
+// on every response.
+func StripVersionPrefix(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(version.Header, version.Current)
+
+		rest, found := strings.CutPrefix(r.URL.Path, version.Prefix+"/")
+		if !found {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = "/api/" + rest
+		if r.URL.RawPath != "" {
+			r2.URL.RawPath = "/api/" + strings.TrimPrefix(r.URL.RawPath, version.Prefix+"/")
+		}
+		next.ServeHTTP(w, r2)
+	})
+}
+
+func extensionString(extensions map[string]any, key string) string {
+	value, _ := extensions[key].(string)
+	return value
+}
+
+func extensionTime(extensions map[string]any, key string) time.Time {
+	value := extensionString(extensions, key)
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
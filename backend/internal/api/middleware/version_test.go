@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	"github.com/matt-dz/wecook/internal/api/version"
+)
+
+func TestStripVersionPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		wantPath string
+	}{
+		{
+			name:     "versioned path is rewritten",
+			path:     "/api/v1/recipes/12",
+			wantPath: "/api/recipes/12",
+		},
+		{
+			name:     "unversioned path is untouched",
+			path:     "/api/recipes/12",
+			wantPath: "/api/recipes/12",
+		},
+		{
+			name:     "version prefix without trailing segment is untouched",
+			path:     "/api/v1",
+			wantPath: "/api/v1",
+		},
+		{
+			name:     "other versions are untouched",
+			path:     "/api/v2/recipes",
+			wantPath: "/api/v2/recipes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			handler := StripVersionPrefix(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if gotPath != tt.wantPath {
+				t.Errorf("expected path %q, got %q", tt.wantPath, gotPath)
+			}
+			if got := rec.Header().Get(version.Header); got != version.Current {
+				t.Errorf("expected %s header %q, got %q", version.Header, version.Current, got)
+			}
+		})
+	}
+}
+
+func TestDeprecationHeaders(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info:
+  title: test
+  version: "1"
+paths:
+  /api/old:
+    get:
+      deprecated: true
+      x-deprecated-at: "2025-01-01"
+      x-sunset: "2025-07-01T00:00:00Z"
+      x-deprecation-link: https://example.com/migrate
+      responses:
+        "200":
+          description: OK
+  /api/undated:
+    get:
+      deprecated: true
+      responses:
+        "200":
+          description: OK
+  /api/new:
+    get:
+      responses:
+        "200":
+          description: OK
+`)
+	swagger, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		path            string
+		wantDeprecation string
+		wantSunset      string
+		wantLink        string
+	}{
+		{
+			name:            "deprecated operation with dates and link",
+			path:            "/api/old",
+			wantDeprecation: "@1735689600",
+			wantSunset:      "Tue, 01 Jul 2025 00:00:00 GMT",
+			wantLink:        `<https://example.com/migrate>; rel="deprecation"`,
+		},
+		{
+			name:            "deprecated operation without dates",
+			path:            "/api/undated",
+			wantDeprecation: "true",
+		},
+		{
+			name: "current operation",
+			path: "/api/new",
+		},
+		{
+			name: "unknown route",
+			path: "/api/missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := DeprecationHeaders(router)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if got := rec.Header().Get("Deprecation"); got != tt.wantDeprecation {
+				t.Errorf("expected Deprecation %q, got %q", tt.wantDeprecation, got)
+			}
+			if got := rec.Header().Get("Sunset"); got != tt.wantSunset {
+				t.Errorf("expected Sunset %q, got %q", tt.wantSunset, got)
+			}
+			if got := rec.Header().Get("Link"); got != tt.wantLink {
+				t.Errorf("expected Link %q, got %q", tt.wantLink, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+)
+
+func (Server) GetApiUsersMeActivity(ctx context.Context,
+	request GetApiUsersMeActivityRequestObject) (
+	GetApiUsersMeActivityResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return GetApiUsersMeActivity500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	limit := pageLimit(env, request.Params.Limit)
+	params := database.GetUserActivityParams{
+		ActorID: pgtype.Int8{Int64: userID, Valid: true},
+		Limit:   limit + 1,
+	}
+	if request.Params.Before != nil {
+		params.Before = pgtype.Int8{Int64: *request.Params.Before, Valid: true}
+	}
+
+	// Get activity
+	env.Logger.DebugContext(ctx, "getting user activity")
+	rows, err := env.Database.GetUserActivity(ctx, params)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get user activity", slog.Any("error", err))
+		return GetApiUsersMeActivity500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Count activity
+	env.Logger.DebugContext(ctx, "counting user activity")
+	total, err := env.Database.GetUserActivityCount(ctx, params.ActorID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to count user activity", slog.Any("error", err))
+		return GetApiUsersMeActivity500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	rows, page := newPage(rows, limit, total)
+	res := GetApiUsersMeActivity200JSONResponse{
+		Activity: make([]Activity, len(rows)),
+		Page:     page,
+	}
+	for i, row := range rows {
+		res.Activity[i] = newActivity(row)
+		res.Cursor = row.ID
+	}
+
+	return res, nil
+}
+
+func newActivity(row database.GetUserActivityRow) Activity {
+	res := Activity{
+		Id:         row.ID,
+		Action:     row.Action,
+		TargetType: row.TargetType,
+		Metadata:   map[string]any{},
+		CreatedAt:  row.CreatedAt.Time,
+	}
+	if row.TargetID.Valid {
+		res.TargetId = &row.TargetID.Int64
+	}
+	// Metadata is always written as a JSON object.
+	_ = json.Unmarshal(row.Metadata, &res.Metadata)
+
+	// Deleted recipes are no longer joined; their title was recorded.
+	if row.RecipeTitle.Valid {
+		res.Title = &row.RecipeTitle.String
+	} else if title, ok := res.Metadata["title"].(string); ok {
+		res.Title = &title
+	}
+	return res
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func activityTestContext(mockDB database.Querier) context.Context {
+	ctx := context.Background()
+	ctx = requestid.InjectRequestID(ctx, 12345)
+	ctx = token.UserIDWithCtx(ctx, 3)
+	return env.WithCtx(ctx, &env.Env{
+		Logger:   log.NullLogger(),
+		Database: mockDB,
+	})
+}
+
+func TestGetApiUsersMeActivity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+
+	before, limit := int64(30), int32(2)
+	mockDB.EXPECT().
+		GetUserActivity(gomock.Any(), database.GetUserActivityParams{
+			ActorID: pgtype.Int8{Int64: 3, Valid: true},
+			Before:  pgtype.Int8{Int64: 30, Valid: true},
+			Limit:   3,
+		}).
+		Return([]database.GetUserActivityRow{
+			{
+				ID:          21,
+				Action:      "recipe.delete",
+				TargetType:  "recipe",
+				TargetID:    pgtype.Int8{Int64: 8, Valid: true},
+				Metadata:    []byte(`{"title":"Pancakes"}`),
+				RecipeTitle: pgtype.Text{},
+			},
+			{
+				ID:          14,
+				Action:      "recipe.create",
+				TargetType:  "recipe",
+				TargetID:    pgtype.Int8{Int64: 9, Valid: true},
+				Metadata:    []byte(`{}`),
+				RecipeTitle: pgtype.Text{String: "Waffles", Valid: true},
+			},
+			{ID: 9, Action: "recipe.create", TargetType: "recipe", Metadata: []byte(`{}`)},
+		}, nil)
+	mockDB.EXPECT().
+		GetUserActivityCount(gomock.Any(), pgtype.Int8{Int64: 3, Valid: true}).
+		Return(int64(5), nil)
+
+	resp, err := NewServer().GetApiUsersMeActivity(activityTestContext(mockDB), GetApiUsersMeActivityRequestObject{
+		Params: GetApiUsersMeActivityParams{Before: &before, Limit: &limit},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiUsersMeActivity200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	// The third event only tells that another page follows.
+	if len(v.Activity) != 2 || v.Cursor != 14 {
+		t.Fatalf("expected 2 events and cursor 14, got %d and %d", len(v.Activity), v.Cursor)
+	}
+	if want := (Page{Limit: 2, Total: 5, HasMore: true}); v.Page != want {
+		t.Errorf("expected page %+v, got %+v", want, v.Page)
+	}
+
+	// A deleted recipe is titled from its metadata, a live one from the recipe.
+	for i, want := range []string{"Pancakes", "Waffles"} {
+		if got := v.Activity[i].Title; got == nil || *got != want {
+			t.Errorf("expected event %d to be titled %q, got %v", i, want, got)
+		}
+	}
+	if v.Activity[1].TargetId == nil || *v.Activity[1].TargetId != 9 {
+		t.Errorf("expected target 9, got %v", v.Activity[1].TargetId)
+	}
+}
+
+func TestGetApiUsersMeActivity_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().
+		GetUserActivity(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("connection refused"))
+
+	resp, err := NewServer().GetApiUsersMeActivity(activityTestContext(mockDB),
+		GetApiUsersMeActivityRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiUsersMeActivity500JSONResponse)
+	if !ok {
+		t.Fatalf("expected 500 response, got %T", resp)
+	}
+	if v.Code != apiError.InternalServerError.String() {
+		t.Errorf("expected code %s, got %s", apiError.InternalServerError.String(), v.Code)
+	}
+}
@@ -0,0 +1,339 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/appliance"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/delivery"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/temperature"
+)
+
+// applianceResponse converts a stored appliance for responses. It omits the
+// token, which is only shown at registration.
+func applianceResponse(stored database.Appliance) Appliance {
+	res := Appliance{
+		Id:        stored.ID,
+		Name:      stored.Name,
+		Provider:  ApplianceProvider(stored.Provider),
+		Endpoint:  stored.Endpoint,
+		Scopes:    make([]ApplianceAction, 0, len(stored.Scopes)),
+		CreatedAt: stored.CreatedAt.Time,
+	}
+	for _, scope := range stored.Scopes {
+		res.Scopes = append(res.Scopes, ApplianceAction(scope))
+	}
+	return res
+}
+
+func (Server) GetApiAppliances(ctx context.Context,
+	request GetApiAppliancesRequestObject) (
+	GetApiAppliancesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return GetApiAppliances400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get appliances
+	env.Logger.DebugContext(ctx, "getting appliances")
+	appliances, err := env.Database.GetAppliances(ctx, userID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get appliances", slog.Any("error", err))
+		return GetApiAppliances500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	res := GetApiAppliances200JSONResponse{
+		Appliances: make([]Appliance, 0, len(appliances)),
+	}
+	for _, stored := range appliances {
+		res.Appliances = append(res.Appliances, applianceResponse(stored))
+	}
+	return res, nil
+}
+
+func (Server) PostApiAppliances(ctx context.Context,
+	request PostApiAppliancesRequestObject) (
+	PostApiAppliancesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiAppliances400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Validate endpoint
+	if err := appliance.ValidateEndpoint(request.Body.Endpoint,
+		env.Config.Appliances.AllowPrivateAddresses); err != nil {
+		env.Logger.ErrorContext(ctx, "invalid appliance endpoint", slog.Any("error", err))
+		return PostApiAppliances422JSONResponse{
+			Status:  apiError.UnprocessibleEntity.StatusCode(),
+			Code:    apiError.UnprocessibleEntity.String(),
+			Message: err.Error(),
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Register appliance
+	env.Logger.DebugContext(ctx, "registering appliance")
+	params := database.CreateApplianceParams{
+		UserID:   userID,
+		Name:     request.Body.Name,
+		Provider: string(request.Body.Provider),
+		Endpoint: request.Body.Endpoint,
+		Scopes:   make([]string, 0, len(request.Body.Scopes)),
+		Token:    env.NewID(),
+	}
+	for _, scope := range request.Body.Scopes {
+		params.Scopes = append(params.Scopes, string(scope))
+	}
+	stored, err := env.Database.CreateAppliance(ctx, params)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to register appliance", slog.Any("error", err))
+		return PostApiAppliances500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PostApiAppliances201JSONResponse{
+		Appliance: applianceResponse(stored),
+		Token:     stored.Token,
+	}, nil
+}
+
+func (Server) DeleteApiAppliancesApplianceID(ctx context.Context,
+	request DeleteApiAppliancesApplianceIDRequestObject) (
+	DeleteApiAppliancesApplianceIDResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return DeleteApiAppliancesApplianceID400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Delete appliance
+	env.Logger.DebugContext(ctx, "deleting appliance")
+	deleted, err := env.Database.DeleteAppliance(ctx, database.DeleteApplianceParams{
+		ID:     request.ApplianceID,
+		UserID: userID,
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to delete appliance", slog.Any("error", err))
+		return DeleteApiAppliancesApplianceID500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if deleted == 0 {
+		env.Logger.ErrorContext(ctx, "appliance not found")
+		return DeleteApiAppliancesApplianceID404JSONResponse{
+			Status:  apiError.ApplianceNotFound.StatusCode(),
+			Code:    apiError.ApplianceNotFound.String(),
+			Message: "appliance does not exist or user does not own it",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return DeleteApiAppliancesApplianceID204Response{}, nil
+}
+
+func (Server) PostApiAppliancesApplianceIDCommands(ctx context.Context,
+	request PostApiAppliancesApplianceIDCommandsRequestObject) (
+	PostApiAppliancesApplianceIDCommandsResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiAppliancesApplianceIDCommands400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get appliance
+	env.Logger.DebugContext(ctx, "getting appliance")
+	stored, err := env.Database.GetAppliance(ctx, database.GetApplianceParams{
+		ID:     request.ApplianceID,
+		UserID: userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		env.Logger.ErrorContext(ctx, "appliance not found", slog.Any("error", err))
+		return PostApiAppliancesApplianceIDCommands404JSONResponse{
+			Status:  apiError.ApplianceNotFound.StatusCode(),
+			Code:    apiError.ApplianceNotFound.String(),
+			Message: "appliance does not exist or user does not own it",
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get appliance", slog.Any("error", err))
+		return PostApiAppliancesApplianceIDCommands500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	target := appliance.Appliance{
+		ID:       stored.ID,
+		Name:     stored.Name,
+		Endpoint: stored.Endpoint,
+		Token:    stored.Token,
+	}
+	for _, scope := range stored.Scopes {
+		target.Scopes = append(target.Scopes, appliance.Action(scope))
+	}
+
+	// Check scope
+	command := appliance.Command{Action: appliance.Action(request.Body.Action)}
+	if !target.Allows(command.Action) {
+		env.Logger.ErrorContext(ctx, "action not in appliance scopes", slog.String("action", string(command.Action)))
+		return PostApiAppliancesApplianceIDCommands403JSONResponse{
+			Status:  apiError.ApplianceScopeDenied.StatusCode(),
+			Code:    apiError.ApplianceScopeDenied.String(),
+			Message: "appliance does not accept " + string(command.Action),
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Build command
+	if request.Body.Temperature != nil {
+		command.Temperature = &temperature.Temperature{
+			Value: float64(request.Body.Temperature.Value),
+			Unit:  temperature.Unit(request.Body.Temperature.Unit),
+		}
+	}
+	if request.Body.DurationSeconds != nil {
+		command.Duration = time.Duration(*request.Body.DurationSeconds) * time.Second
+	}
+	if request.Body.Label != nil {
+		command.Label = *request.Body.Label
+	}
+	if (request.Body.RecipeId == nil) != (request.Body.StepId == nil) {
+		env.Logger.ErrorContext(ctx, "recipe and step must be given together")
+		return PostApiAppliancesApplianceIDCommands422JSONResponse{
+			Status:  apiError.UnprocessibleEntity.StatusCode(),
+			Code:    apiError.UnprocessibleEntity.String(),
+			Message: "recipe_id and step_id must be given together",
+			ErrorId: requestID,
+		}, nil
+	}
+	if request.Body.StepId != nil {
+		env.Logger.DebugContext(ctx, "getting cooking step")
+		step, err := env.Database.GetCookingStep(ctx, database.GetCookingStepParams{
+			StepID:   *request.Body.StepId,
+			RecipeID: *request.Body.RecipeId,
+			UserID: pgtype.Int8{
+				Int64: userID,
+				Valid: true,
+			},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			env.Logger.ErrorContext(ctx, "step not found", slog.Any("error", err))
+			return PostApiAppliancesApplianceIDCommands404JSONResponse{
+				Status:  apiError.StepNotFound.StatusCode(),
+				Code:    apiError.StepNotFound.String(),
+				Message: "step does not exist or recipe is not visible to user",
+				ErrorId: requestID,
+			}, nil
+		} else if err != nil {
+			env.Logger.ErrorContext(ctx, "failed to get cooking step", slog.Any("error", err))
+			return PostApiAppliancesApplianceIDCommands500JSONResponse{
+				Status:  apiError.InternalServerError.StatusCode(),
+				Code:    apiError.InternalServerError.String(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+		command.RecipeID = *request.Body.RecipeId
+		command.StepID = *request.Body.StepId
+		if command.Temperature == nil && step.TemperatureValue.Valid && step.TemperatureUnit.Valid {
+			command.Temperature = &temperature.Temperature{
+				Value: float64(step.TemperatureValue.Float32),
+				Unit:  temperature.Unit(step.TemperatureUnit.TemperatureUnit),
+			}
+		}
+		if command.Label == "" && step.Instruction.Valid {
+			command.Label = step.Instruction.String
+		}
+	}
+
+	// Send command
+	env.Logger.DebugContext(ctx, "sending appliance command", slog.String("action", string(command.Action)))
+	provider, err := appliance.NewProvider(appliance.ProviderName(stored.Provider), env.HTTP)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to create appliance provider", slog.Any("error", err))
+		return PostApiAppliancesApplianceIDCommands500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	err = delivery.SendCommand(ctx, env, provider, target, command)
+	if errors.Is(err, appliance.ErrInvalidCommand) {
+		env.Logger.ErrorContext(ctx, "invalid appliance command", slog.Any("error", err))
+		return PostApiAppliancesApplianceIDCommands422JSONResponse{
+			Status:  apiError.UnprocessibleEntity.StatusCode(),
+			Code:    apiError.UnprocessibleEntity.String(),
+			Message: err.Error(),
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.WarnContext(ctx, "appliance did not accept command", slog.Any("error", err))
+		return PostApiAppliancesApplianceIDCommands502JSONResponse{
+			Status:  apiError.ApplianceUnavailable.StatusCode(),
+			Code:    apiError.ApplianceUnavailable.String(),
+			Message: "appliance did not accept the command",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PostApiAppliancesApplianceIDCommands204Response{}, nil
+}
@@ -0,0 +1,420 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/appliance"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/idgen"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func appliancesTestContext(mockDB database.Querier) context.Context {
+	client := retryablehttp.NewClient()
+	client.RetryMax = 0
+	client.Logger = nil
+
+	ctx := context.Background()
+	ctx = requestid.InjectRequestID(ctx, 12345)
+	ctx = token.UserIDWithCtx(ctx, 42)
+	return env.WithCtx(ctx, &env.Env{
+		Logger:   log.NullLogger(),
+		Database: mockDB,
+		HTTP:     client,
+		IDGen:    idgen.NewSequential("token-"),
+	})
+}
+
+func TestGetApiAppliances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().GetAppliances(gomock.Any(), int64(42)).Return([]database.Appliance{
+		{
+			ID:       1,
+			UserID:   42,
+			Name:     "Oven",
+			Provider: "webhook",
+			Endpoint: "https://oven.local/wecook",
+			Scopes:   []string{"preheat", "set_timer"},
+			Token:    "secret",
+		},
+	}, nil)
+
+	resp, err := NewServer().GetApiAppliances(appliancesTestContext(mockDB), GetApiAppliancesRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiAppliances200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(v.Appliances) != 1 {
+		t.Fatalf("expected 1 appliance, got %d", len(v.Appliances))
+	}
+	got := v.Appliances[0]
+	if got.Name != "Oven" || got.Provider != ApplianceProviderWebhook || len(got.Scopes) != 2 || got.Scopes[1] != SetTimer {
+		t.Errorf("unexpected appliance %+v", got)
+	}
+}
+
+func TestPostApiAppliances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		endpoint   string
+		setup      func()
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:     "registers appliance with a new token",
+			endpoint: "https://oven.local/wecook",
+			setup: func() {
+				mockDB.EXPECT().
+					CreateAppliance(gomock.Any(), database.CreateApplianceParams{
+						UserID:   42,
+						Name:     "Oven",
+						Provider: "webhook",
+						Endpoint: "https://oven.local/wecook",
+						Scopes:   []string{"preheat"},
+						Token:    "token-1",
+					}).
+					Return(database.Appliance{
+						ID:       1,
+						UserID:   42,
+						Name:     "Oven",
+						Provider: "webhook",
+						Endpoint: "https://oven.local/wecook",
+						Scopes:   []string{"preheat"},
+						Token:    "token-1",
+					}, nil)
+			},
+			wantStatus: 201,
+		},
+		{
+			name:       "rejects non-http endpoint",
+			endpoint:   "ftp://oven.local",
+			setup:      func() {},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name:       "rejects metadata endpoint",
+			endpoint:   "http://169.254.169.254/latest/meta-data",
+			setup:      func() {},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name:       "rejects private endpoint unless allowed",
+			endpoint:   "http://192.168.1.20:8080/do",
+			setup:      func() {},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name:     "database error",
+			endpoint: "https://oven.local/wecook",
+			setup: func() {
+				mockDB.EXPECT().
+					CreateAppliance(gomock.Any(), gomock.Any()).
+					Return(database.Appliance{}, errors.New("database error"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			resp, err := server.PostApiAppliances(appliancesTestContext(mockDB), PostApiAppliancesRequestObject{
+				Body: &PostApiAppliancesJSONRequestBody{
+					Name:     "Oven",
+					Provider: ApplianceProviderWebhook,
+					Endpoint: tt.endpoint,
+					Scopes:   []ApplianceAction{Preheat},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case PostApiAppliances201JSONResponse:
+				if tt.wantStatus != 201 {
+					t.Errorf("expected status %d, got 201", tt.wantStatus)
+				}
+				if v.Token != "token-1" {
+					t.Errorf("expected token token-1, got %q", v.Token)
+				}
+			case PostApiAppliances422JSONResponse:
+				if tt.wantStatus != 422 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 422 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiAppliances500JSONResponse:
+				if tt.wantStatus != 500 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 500 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			default:
+				t.Errorf("unexpected response type: %T", v)
+			}
+		})
+	}
+}
+
+func TestDeleteApiAppliancesApplianceID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	server := NewServer()
+	params := database.DeleteApplianceParams{ID: 1, UserID: 42}
+
+	mockDB.EXPECT().DeleteAppliance(gomock.Any(), params).Return(int64(1), nil)
+	resp, err := server.DeleteApiAppliancesApplianceID(appliancesTestContext(mockDB),
+		DeleteApiAppliancesApplianceIDRequestObject{ApplianceID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(DeleteApiAppliancesApplianceID204Response); !ok {
+		t.Errorf("expected 204 response, got %T", resp)
+	}
+
+	mockDB.EXPECT().DeleteAppliance(gomock.Any(), params).Return(int64(0), nil)
+	resp, err = server.DeleteApiAppliancesApplianceID(appliancesTestContext(mockDB),
+		DeleteApiAppliancesApplianceIDRequestObject{ApplianceID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(DeleteApiAppliancesApplianceID404JSONResponse)
+	if !ok {
+		t.Fatalf("expected 404 response, got %T", resp)
+	}
+	if v.Code != apiError.ApplianceNotFound.String() {
+		t.Errorf("expected code %s, got %s", apiError.ApplianceNotFound.String(), v.Code)
+	}
+}
+
+func TestPostApiAppliancesApplianceIDCommands(t *testing.T) {
+	var (
+		received []appliance.WebhookPayload
+		fail     bool
+	)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "offline", http.StatusServiceUnavailable)
+			return
+		}
+		var payload appliance.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		received = append(received, payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+
+	oven := database.Appliance{
+		ID:       1,
+		UserID:   42,
+		Name:     "Oven",
+		Provider: "webhook",
+		Endpoint: webhook.URL,
+		Scopes:   []string{"preheat"},
+		Token:    "secret",
+	}
+	recipeID, stepID := int64(7), int64(8)
+	duration := int32(600)
+
+	tests := []struct {
+		name       string
+		body       ApplianceCommand
+		fail       bool
+		setup      func(mockDB *database.MockQuerier)
+		wantStatus int
+		wantCode   string
+		validate   func(t *testing.T)
+	}{
+		{
+			name: "preheat uses step temperature",
+			body: ApplianceCommand{Action: Preheat, RecipeId: &recipeID, StepId: &stepID},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().GetAppliance(gomock.Any(), gomock.Any()).Return(oven, nil)
+				mockDB.EXPECT().CreateDelivery(gomock.Any(), gomock.Any()).Return(database.Delivery{ID: 3}, nil)
+				mockDB.EXPECT().MarkDeliverySent(gomock.Any(), gomock.Any()).Return(nil)
+				mockDB.EXPECT().
+					GetCookingStep(gomock.Any(), database.GetCookingStepParams{
+						StepID:   8,
+						RecipeID: 7,
+						UserID:   pgtype.Int8{Int64: 42, Valid: true},
+					}).
+					Return(database.GetCookingStepRow{
+						Instruction:      pgtype.Text{String: "Preheat the oven", Valid: true},
+						TemperatureValue: pgtype.Float4{Float32: 350, Valid: true},
+						TemperatureUnit: database.NullTemperatureUnit{
+							TemperatureUnit: database.TemperatureUnitF,
+							Valid:           true,
+						},
+					}, nil)
+			},
+			wantStatus: 204,
+			validate: func(t *testing.T) {
+				if len(received) != 1 {
+					t.Fatalf("expected 1 webhook, got %d", len(received))
+				}
+				got := received[0]
+				if got.Action != appliance.ActionPreheat || got.Label != "Preheat the oven" || got.StepID != 8 {
+					t.Errorf("unexpected payload %+v", got)
+				}
+				if got.Temperature == nil || *got.Temperature != (appliance.WebhookTemperature{Value: 350, Unit: "F"}) {
+					t.Errorf("expected temperature 350F, got %+v", got.Temperature)
+				}
+			},
+		},
+		{
+			name: "action outside scopes",
+			body: ApplianceCommand{Action: SetTimer, DurationSeconds: &duration},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().GetAppliance(gomock.Any(), gomock.Any()).Return(oven, nil)
+			},
+			wantStatus: 403,
+			wantCode:   apiError.ApplianceScopeDenied.String(),
+		},
+		{
+			name: "appliance not found",
+			body: ApplianceCommand{Action: Preheat},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					GetAppliance(gomock.Any(), gomock.Any()).
+					Return(database.Appliance{}, pgx.ErrNoRows)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.ApplianceNotFound.String(),
+		},
+		{
+			name: "step not found",
+			body: ApplianceCommand{Action: Preheat, RecipeId: &recipeID, StepId: &stepID},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().GetAppliance(gomock.Any(), gomock.Any()).Return(oven, nil)
+				mockDB.EXPECT().
+					GetCookingStep(gomock.Any(), gomock.Any()).
+					Return(database.GetCookingStepRow{}, pgx.ErrNoRows)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.StepNotFound.String(),
+		},
+		{
+			name: "preheat without temperature",
+			body: ApplianceCommand{Action: Preheat},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().GetAppliance(gomock.Any(), gomock.Any()).Return(oven, nil)
+			},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name: "step without recipe",
+			body: ApplianceCommand{Action: Preheat, StepId: &stepID},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().GetAppliance(gomock.Any(), gomock.Any()).Return(oven, nil)
+			},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name: "appliance rejects command",
+			body: ApplianceCommand{Action: Preheat, Temperature: &Temperature{Value: 200, Unit: C}},
+			fail: true,
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().GetAppliance(gomock.Any(), gomock.Any()).Return(oven, nil)
+				mockDB.EXPECT().
+					CreateDelivery(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.CreateDeliveryParams) (database.Delivery, error) {
+						if params.Kind != "webhook" || params.Recipient != "1" || params.Subject != "preheat" {
+							t.Errorf("unexpected delivery %+v", params)
+						}
+						return database.Delivery{ID: 3, Kind: params.Kind}, nil
+					})
+				mockDB.EXPECT().
+					MarkDeliveryFailed(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.MarkDeliveryFailedParams) error {
+						if params.Status != "dead" || !params.LastError.Valid {
+							t.Errorf("expected dead-lettered delivery, got %+v", params)
+						}
+						return nil
+					})
+			},
+			wantStatus: 502,
+			wantCode:   apiError.ApplianceUnavailable.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := database.NewMockQuerier(ctrl)
+			tt.setup(mockDB)
+			received, fail = nil, tt.fail
+
+			body := tt.body
+			resp, err := NewServer().PostApiAppliancesApplianceIDCommands(appliancesTestContext(mockDB),
+				PostApiAppliancesApplianceIDCommandsRequestObject{ApplianceID: 1, Body: &body})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case PostApiAppliancesApplianceIDCommands204Response:
+				if tt.wantStatus != 204 {
+					t.Errorf("expected status %d, got 204", tt.wantStatus)
+				}
+			case PostApiAppliancesApplianceIDCommands403JSONResponse:
+				if tt.wantStatus != 403 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 403 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiAppliancesApplianceIDCommands404JSONResponse:
+				if tt.wantStatus != 404 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 404 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiAppliancesApplianceIDCommands422JSONResponse:
+				if tt.wantStatus != 422 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 422 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiAppliancesApplianceIDCommands502JSONResponse:
+				if tt.wantStatus != 502 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 502 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			default:
+				t.Errorf("unexpected response type: %T", v)
+			}
+
+			if tt.validate != nil {
+				tt.validate(t)
+			}
+		})
+	}
+}
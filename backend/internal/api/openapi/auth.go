@@ -9,7 +9,6 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/jackc/pgx/v5"
@@ -304,7 +303,7 @@ func (Server) PostApiAuthRefresh(ctx context.Context,
 	}
 	env.Logger.DebugContext(ctx, "tokens match!")
 
-	if time.Now().After(refresh.RefreshTokenExpiresAt.Time) {
+	if env.Now().After(refresh.RefreshTokenExpiresAt.Time) {
 		env.Logger.ErrorContext(ctx, "refresh token is expired")
 		return PostApiAuthRefresh401JSONResponse{
 			Status:  apiError.InvalidRefreshToken.StatusCode(),
@@ -438,7 +437,7 @@ func (Server) GetApiAuthVerify(ctx context.Context,
 		givenRole = role.ToRole(string(*request.Params.Role))
 	}
 	if userRole < givenRole {
-		return GetApiAuthVerify401JSONResponse{
+		return GetApiAuthVerify403JSONResponse{
 			Status:  apiError.InsufficientPermissions.StatusCode(),
 			Code:    apiError.InsufficientPermissions.String(),
 			Message: "Insufficient Permissions",
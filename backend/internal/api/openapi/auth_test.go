@@ -14,6 +14,7 @@ import (
 	"github.com/matt-dz/wecook/internal/api/requestid"
 	"github.com/matt-dz/wecook/internal/api/token"
 	"github.com/matt-dz/wecook/internal/argon2id"
+	"github.com/matt-dz/wecook/internal/clock"
 	"github.com/matt-dz/wecook/internal/config"
 	"github.com/matt-dz/wecook/internal/database"
 	"github.com/matt-dz/wecook/internal/env"
@@ -317,6 +318,7 @@ func TestPostApiAuthRefresh(t *testing.T) {
 	mockDB := database.NewMockQuerier(ctrl)
 	server := NewServer()
 
+	now := time.Date(2025, time.March, 9, 12, 0, 0, 0, time.UTC)
 	userID := int64(123)
 	validRefreshToken, err := token.NewRefreshToken(userID)
 	if err != nil {
@@ -357,7 +359,7 @@ func TestPostApiAuthRefresh(t *testing.T) {
 							Valid:  true,
 						},
 						RefreshTokenExpiresAt: pgtype.Timestamptz{
-							Time:  time.Now().Add(24 * time.Hour),
+							Time:  now.Add(24 * time.Hour),
 							Valid: true,
 						},
 					}, nil)
@@ -390,7 +392,7 @@ func TestPostApiAuthRefresh(t *testing.T) {
 							Valid:  true,
 						},
 						RefreshTokenExpiresAt: pgtype.Timestamptz{
-							Time:  time.Now().Add(24 * time.Hour),
+							Time:  now.Add(24 * time.Hour),
 							Valid: true,
 						},
 					}, nil)
@@ -489,7 +491,7 @@ func TestPostApiAuthRefresh(t *testing.T) {
 							Valid:  true,
 						},
 						RefreshTokenExpiresAt: pgtype.Timestamptz{
-							Time:  time.Now().Add(24 * time.Hour),
+							Time:  now.Add(24 * time.Hour),
 							Valid: true,
 						},
 					}, nil)
@@ -514,7 +516,32 @@ func TestPostApiAuthRefresh(t *testing.T) {
 							Valid:  true,
 						},
 						RefreshTokenExpiresAt: pgtype.Timestamptz{
-							Time:  time.Now().Add(-24 * time.Hour),
+							Time:  now.Add(-24 * time.Hour),
+							Valid: true,
+						},
+					}, nil)
+			},
+			wantStatus: 401,
+			wantCode:   apiError.InvalidRefreshToken.String(),
+			wantError:  false,
+		},
+		{
+			name: "refresh token expired one second ago",
+			request: PostApiAuthRefreshRequestObject{
+				Body: &PostApiAuthRefreshJSONRequestBody{
+					RefreshToken: &validRefreshToken,
+				},
+			},
+			setup: func() {
+				mockDB.EXPECT().
+					GetUserRefreshTokenHash(gomock.Any(), userID).
+					Return(database.GetUserRefreshTokenHashRow{
+						RefreshTokenHash: pgtype.Text{
+							String: validRefreshTokenHash,
+							Valid:  true,
+						},
+						RefreshTokenExpiresAt: pgtype.Timestamptz{
+							Time:  now.Add(-time.Second),
 							Valid: true,
 						},
 					}, nil)
@@ -539,7 +566,7 @@ func TestPostApiAuthRefresh(t *testing.T) {
 							Valid:  true,
 						},
 						RefreshTokenExpiresAt: pgtype.Timestamptz{
-							Time:  time.Now().Add(24 * time.Hour),
+							Time:  now.Add(24 * time.Hour),
 							Valid: true,
 						},
 					}, nil)
@@ -568,7 +595,7 @@ func TestPostApiAuthRefresh(t *testing.T) {
 							Valid:  true,
 						},
 						RefreshTokenExpiresAt: pgtype.Timestamptz{
-							Time:  time.Now().Add(24 * time.Hour),
+							Time:  now.Add(24 * time.Hour),
 							Valid: true,
 						},
 					}, nil)
@@ -598,6 +625,7 @@ func TestPostApiAuthRefresh(t *testing.T) {
 			e.Config.AppSecret.Value = &secret
 			e.Logger = log.NullLogger()
 			e.Database = mockDB
+			e.Clock = clock.NewFrozen(now)
 			ctx = env.WithCtx(ctx, e)
 
 			resp, err := server.PostApiAuthRefresh(ctx, tt.request)
@@ -675,7 +703,7 @@ func TestGetApiAuthVerify(t *testing.T) {
 					t.Fatalf("failed to create access token: %v", err)
 				}
 
-				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"))
+				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"), time.Now())
 				if err != nil {
 					t.Fatalf("failed to validate token: %v", err)
 				}
@@ -705,7 +733,7 @@ func TestGetApiAuthVerify(t *testing.T) {
 					t.Fatalf("failed to create access token: %v", err)
 				}
 
-				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"))
+				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"), time.Now())
 				if err != nil {
 					t.Fatalf("failed to validate token: %v", err)
 				}
@@ -735,7 +763,7 @@ func TestGetApiAuthVerify(t *testing.T) {
 					t.Fatalf("failed to create access token: %v", err)
 				}
 
-				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"))
+				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"), time.Now())
 				if err != nil {
 					t.Fatalf("failed to validate token: %v", err)
 				}
@@ -765,7 +793,7 @@ func TestGetApiAuthVerify(t *testing.T) {
 					t.Fatalf("failed to create access token: %v", err)
 				}
 
-				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"))
+				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"), time.Now())
 				if err != nil {
 					t.Fatalf("failed to validate token: %v", err)
 				}
@@ -795,7 +823,7 @@ func TestGetApiAuthVerify(t *testing.T) {
 					t.Fatalf("failed to create access token: %v", err)
 				}
 
-				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"))
+				parsedToken, err := mJwt.ValidateJWT(accessToken, "1", []byte("test-secret-key-for-jwt-signing"), time.Now())
 				if err != nil {
 					t.Fatalf("failed to validate token: %v", err)
 				}
@@ -803,7 +831,7 @@ func TestGetApiAuthVerify(t *testing.T) {
 				ctx = token.AccessTokenWithCtx(ctx, parsedToken)
 				return ctx
 			},
-			wantStatus: 401,
+			wantStatus: 403,
 			wantCode:   apiError.InsufficientPermissions.String(),
 			wantError:  false,
 		},
@@ -852,6 +880,13 @@ func TestGetApiAuthVerify(t *testing.T) {
 				if v.Code != tt.wantCode {
 					t.Errorf("expected code %s, got %s", tt.wantCode, v.Code)
 				}
+			case GetApiAuthVerify403JSONResponse:
+				if tt.wantStatus != 403 {
+					t.Errorf("expected status %d, got 403", tt.wantStatus)
+				}
+				if v.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, v.Code)
+				}
 			case GetApiAuthVerify500JSONResponse:
 				if tt.wantStatus != 500 {
 					t.Errorf("expected status %d, got 500", tt.wantStatus)
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/audit"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/delivery"
+	"github.com/matt-dz/wecook/internal/env"
+)
+
+func (Server) GetApiDeliveries(ctx context.Context,
+	request GetApiDeliveriesRequestObject) (
+	GetApiDeliveriesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	limit := pageLimit(env, request.Params.Limit)
+	params := database.GetDeliveriesParams{
+		Limit: limit + 1,
+	}
+	if request.Params.Before != nil {
+		params.Before = pgtype.Int8{Int64: *request.Params.Before, Valid: true}
+	}
+	if request.Params.Status != nil {
+		params.Status = pgtype.Text{String: string(*request.Params.Status), Valid: true}
+	}
+	if request.Params.Kind != nil {
+		params.Kind = pgtype.Text{String: string(*request.Params.Kind), Valid: true}
+	}
+
+	// Get deliveries
+	env.Logger.DebugContext(ctx, "getting deliveries")
+	deliveries, err := env.Database.GetDeliveries(ctx, params)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get deliveries", slog.Any("error", err))
+		return GetApiDeliveries500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Count deliveries
+	env.Logger.DebugContext(ctx, "counting deliveries")
+	total, err := env.Database.GetDeliveryCount(ctx, database.GetDeliveryCountParams{
+		Status: params.Status,
+		Kind:   params.Kind,
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to count deliveries", slog.Any("error", err))
+		return GetApiDeliveries500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	deliveries, page := newPage(deliveries, limit, total)
+	res := GetApiDeliveries200JSONResponse{
+		Deliveries: make([]Delivery, len(deliveries)),
+		Page:       page,
+	}
+	for i, d := range deliveries {
+		res.Deliveries[i] = newDelivery(d)
+		res.Cursor = d.ID
+	}
+
+	return res, nil
+}
+
+func (Server) GetApiDeliveriesStats(ctx context.Context,
+	request GetApiDeliveriesStatsRequestObject) (
+	GetApiDeliveriesStatsResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Count deliveries
+	env.Logger.DebugContext(ctx, "counting deliveries")
+	rows, err := env.Database.GetDeliveryStats(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to count deliveries", slog.Any("error", err))
+		return GetApiDeliveriesStats500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Every kind is listed, even before anything of that kind was sent.
+	res := GetApiDeliveriesStats200JSONResponse{
+		Stats: []DeliveryStats{
+			{Kind: DeliveryKindEmail},
+			{Kind: DeliveryKindWebhook},
+			{Kind: DeliveryKindActivity},
+		},
+	}
+	for _, row := range rows {
+		for i := range res.Stats {
+			if string(res.Stats[i].Kind) == row.Kind {
+				res.Stats[i] = DeliveryStats{
+					Kind:    DeliveryKind(row.Kind),
+					Queued:  row.Queued,
+					Sent:    row.Sent,
+					Failed:  row.Failed,
+					Dead:    row.Dead,
+					Retried: row.Retried,
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (Server) PostApiDeliveriesDeliveryIDRetry(ctx context.Context,
+	request PostApiDeliveriesDeliveryIDRetryRequestObject) (
+	PostApiDeliveriesDeliveryIDRetryResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiDeliveriesDeliveryIDRetry500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Retry delivery
+	env.Logger.DebugContext(ctx, "retrying delivery", slog.Int64("delivery_id", request.DeliveryID))
+	d, err := delivery.Retry(ctx, env, request.DeliveryID)
+	if errors.Is(err, delivery.ErrNotFound) {
+		env.Logger.ErrorContext(ctx, "delivery not found", slog.Any("error", err))
+		return PostApiDeliveriesDeliveryIDRetry404JSONResponse{
+			Status:  apiError.DeliveryNotFound.StatusCode(),
+			Code:    apiError.DeliveryNotFound.String(),
+			Message: "delivery not found",
+			ErrorId: requestID,
+		}, nil
+	}
+	if errors.Is(err, delivery.ErrNotRetryable) {
+		env.Logger.ErrorContext(ctx, "delivery cannot be retried", slog.Any("error", err))
+		return PostApiDeliveriesDeliveryIDRetry409JSONResponse{
+			Status:  apiError.DeliveryNotRetryable.StatusCode(),
+			Code:    apiError.DeliveryNotRetryable.String(),
+			Message: "delivery was already sent or is being sent",
+			ErrorId: requestID,
+		}, nil
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to retry delivery", slog.Any("error", err))
+		return PostApiDeliveriesDeliveryIDRetry500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Record retry. The attempt was made, so a failure is only logged.
+	env.Logger.DebugContext(ctx, "recording audit event")
+	if err := audit.Record(ctx, env.Database, audit.Event{
+		ActorID:    userID,
+		Action:     audit.ActionRetryDelivery,
+		TargetType: audit.TargetDelivery,
+		TargetID:   d.ID,
+		Metadata: map[string]any{
+			"kind":   d.Kind,
+			"status": d.Status,
+		},
+	}); err != nil {
+		env.Logger.ErrorContext(ctx, "failed to record audit event", slog.Any("error", err))
+	}
+
+	return PostApiDeliveriesDeliveryIDRetry200JSONResponse(newDelivery(d)), nil
+}
+
+func newDelivery(d database.Delivery) Delivery {
+	res := Delivery{
+		Id:        d.ID,
+		Kind:      DeliveryKind(d.Kind),
+		Recipient: d.Recipient,
+		Subject:   d.Subject,
+		Status:    DeliveryStatus(d.Status),
+		Attempts:  d.Attempts,
+		CreatedAt: d.CreatedAt.Time,
+		UpdatedAt: d.UpdatedAt.Time,
+	}
+	if d.LastError.Valid {
+		res.LastError = &d.LastError.String
+	}
+	if d.NextAttemptAt.Valid {
+		res.NextAttemptAt = &d.NextAttemptAt.Time
+	}
+	if d.SentAt.Valid {
+		res.SentAt = &d.SentAt.Time
+	}
+	return res
+}
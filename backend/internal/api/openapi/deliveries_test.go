@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/config"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/email"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func deliveriesTestContext(mockDB database.Querier, mockSMTP email.Sender) context.Context {
+	ctx := context.Background()
+	ctx = requestid.InjectRequestID(ctx, 12345)
+	ctx = token.UserIDWithCtx(ctx, 1)
+	return env.WithCtx(ctx, &env.Env{
+		Logger:   log.NullLogger(),
+		Database: mockDB,
+		SMTP:     mockSMTP,
+	})
+}
+
+func TestGetApiDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+
+	dead := DeliveryStatus("dead")
+	before := int64(50)
+	mockDB.EXPECT().
+		GetDeliveries(gomock.Any(), database.GetDeliveriesParams{
+			Before: pgtype.Int8{Int64: 50, Valid: true},
+			Status: pgtype.Text{String: "dead", Valid: true},
+			Limit:  config.DefaultPageLimit + 1,
+		}).
+		Return([]database.Delivery{
+			{ID: 12, Kind: "email", Status: "dead", LastError: pgtype.Text{String: "mailbox full", Valid: true}},
+			{ID: 7, Kind: "webhook", Status: "dead"},
+		}, nil)
+	mockDB.EXPECT().
+		GetDeliveryCount(gomock.Any(), database.GetDeliveryCountParams{
+			Status: pgtype.Text{String: "dead", Valid: true},
+		}).
+		Return(int64(2), nil)
+
+	resp, err := NewServer().GetApiDeliveries(deliveriesTestContext(mockDB, nil), GetApiDeliveriesRequestObject{
+		Params: GetApiDeliveriesParams{Status: &dead, Before: &before},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiDeliveries200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(v.Deliveries) != 2 || v.Cursor != 7 {
+		t.Fatalf("expected 2 deliveries and cursor 7, got %d and %d", len(v.Deliveries), v.Cursor)
+	}
+	if want := (Page{Limit: config.DefaultPageLimit, Total: 2}); v.Page != want {
+		t.Errorf("expected page %+v, got %+v", want, v.Page)
+	}
+	if v.Deliveries[0].LastError == nil || *v.Deliveries[0].LastError != "mailbox full" {
+		t.Errorf("expected last error, got %v", v.Deliveries[0].LastError)
+	}
+	if v.Deliveries[1].LastError != nil {
+		t.Errorf("expected no last error, got %q", *v.Deliveries[1].LastError)
+	}
+}
+
+func TestGetApiDeliveriesStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().
+		GetDeliveryStats(gomock.Any()).
+		Return([]database.GetDeliveryStatsRow{{Kind: "email", Sent: 40, Failed: 1, Dead: 2, Retried: 3}}, nil)
+
+	resp, err := NewServer().GetApiDeliveriesStats(deliveriesTestContext(mockDB, nil),
+		GetApiDeliveriesStatsRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiDeliveriesStats200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	// Webhooks and activities are listed even though none were sent.
+	want := []DeliveryStats{
+		{Kind: DeliveryKindEmail, Sent: 40, Failed: 1, Dead: 2, Retried: 3},
+		{Kind: DeliveryKindWebhook},
+		{Kind: DeliveryKindActivity},
+	}
+	if !slices.Equal(v.Stats, want) {
+		t.Errorf("expected %+v, got %+v", want, v.Stats)
+	}
+}
+
+func TestPostApiDeliveriesDeliveryIDRetry(t *testing.T) {
+	dead := database.Delivery{
+		ID:       5,
+		Kind:     "email",
+		Subject:  "WeCook Invitation",
+		Payload:  []byte(`{"to":["cook@example.com"],"body":"Join us"}`),
+		Status:   "queued",
+		Attempts: 1,
+	}
+
+	tests := []struct {
+		name     string
+		setup    func(mockDB *database.MockQuerier, mockSMTP *email.MockSender)
+		validate func(t *testing.T, resp PostApiDeliveriesDeliveryIDRetryResponseObject)
+	}{
+		{
+			name: "sends dead-lettered email",
+			setup: func(mockDB *database.MockQuerier, mockSMTP *email.MockSender) {
+				mockDB.EXPECT().ClaimDelivery(gomock.Any(), gomock.Any()).Return(dead, nil)
+				mockSMTP.EXPECT().Send([]string{"cook@example.com"}, "WeCook Invitation", "Join us").Return(nil)
+				mockDB.EXPECT().MarkDeliverySent(gomock.Any(), gomock.Any()).Return(nil)
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.CreateAuditEventParams) (int64, error) {
+						if params.Action != "delivery.retry" || params.TargetID.Int64 != 5 ||
+							!strings.Contains(string(params.Metadata), `"status":"sent"`) {
+							t.Errorf("unexpected audit event %+v", params)
+						}
+						return 1, nil
+					})
+			},
+			validate: func(t *testing.T, resp PostApiDeliveriesDeliveryIDRetryResponseObject) {
+				v, ok := resp.(PostApiDeliveriesDeliveryIDRetry200JSONResponse)
+				if !ok {
+					t.Fatalf("expected 200 response, got %T", resp)
+				}
+				if v.Status != "sent" || v.Attempts != 2 || v.SentAt == nil {
+					t.Errorf("unexpected delivery %+v", v)
+				}
+			},
+		},
+		{
+			name: "reports failed attempt",
+			setup: func(mockDB *database.MockQuerier, mockSMTP *email.MockSender) {
+				mockDB.EXPECT().ClaimDelivery(gomock.Any(), gomock.Any()).Return(dead, nil)
+				mockSMTP.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("relay denied"))
+				mockDB.EXPECT().MarkDeliveryFailed(gomock.Any(), gomock.Any()).Return(nil)
+				mockDB.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+			},
+			validate: func(t *testing.T, resp PostApiDeliveriesDeliveryIDRetryResponseObject) {
+				v, ok := resp.(PostApiDeliveriesDeliveryIDRetry200JSONResponse)
+				if !ok {
+					t.Fatalf("expected 200 response, got %T", resp)
+				}
+				if v.Status != "dead" || v.LastError == nil || *v.LastError != "relay denied" {
+					t.Errorf("unexpected delivery %+v", v)
+				}
+			},
+		},
+		{
+			name: "already sent",
+			setup: func(mockDB *database.MockQuerier, mockSMTP *email.MockSender) {
+				mockDB.EXPECT().ClaimDelivery(gomock.Any(), gomock.Any()).Return(database.Delivery{}, pgx.ErrNoRows)
+				mockDB.EXPECT().GetDelivery(gomock.Any(), int64(5)).Return(database.Delivery{ID: 5, Status: "sent"}, nil)
+			},
+			validate: func(t *testing.T, resp PostApiDeliveriesDeliveryIDRetryResponseObject) {
+				v, ok := resp.(PostApiDeliveriesDeliveryIDRetry409JSONResponse)
+				if !ok {
+					t.Fatalf("expected 409 response, got %T", resp)
+				}
+				if v.Code != apiError.DeliveryNotRetryable.String() {
+					t.Errorf("expected code %s, got %s", apiError.DeliveryNotRetryable, v.Code)
+				}
+			},
+		},
+		{
+			name: "not found",
+			setup: func(mockDB *database.MockQuerier, mockSMTP *email.MockSender) {
+				mockDB.EXPECT().ClaimDelivery(gomock.Any(), gomock.Any()).Return(database.Delivery{}, pgx.ErrNoRows)
+				mockDB.EXPECT().GetDelivery(gomock.Any(), int64(5)).Return(database.Delivery{}, pgx.ErrNoRows)
+			},
+			validate: func(t *testing.T, resp PostApiDeliveriesDeliveryIDRetryResponseObject) {
+				v, ok := resp.(PostApiDeliveriesDeliveryIDRetry404JSONResponse)
+				if !ok {
+					t.Fatalf("expected 404 response, got %T", resp)
+				}
+				if v.Code != apiError.DeliveryNotFound.String() {
+					t.Errorf("expected code %s, got %s", apiError.DeliveryNotFound, v.Code)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := database.NewMockQuerier(ctrl)
+			mockSMTP := email.NewMockSender(ctrl)
+			tt.setup(mockDB, mockSMTP)
+
+			resp, err := NewServer().PostApiDeliveriesDeliveryIDRetry(deliveriesTestContext(mockDB, mockSMTP),
+				PostApiDeliveriesDeliveryIDRetryRequestObject{DeliveryID: 5})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.validate(t, resp)
+		})
+	}
+}
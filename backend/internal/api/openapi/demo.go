@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"strconv"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/env"
+)
+
+func (Server) GetApiDemo(ctx context.Context,
+	request GetApiDemoRequestObject) (
+	GetApiDemoResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	if env.Demo == nil {
+		return GetApiDemo404JSONResponse{
+			Status:  apiError.DemoDisabled.StatusCode(),
+			Code:    apiError.DemoDisabled.String(),
+			Message: "the API sandbox is not enabled",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return GetApiDemo200JSONResponse{
+		ApiKey: env.Demo.APIKey,
+		UserId: env.Demo.UserID,
+	}, nil
+}
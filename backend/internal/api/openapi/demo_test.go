@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/demo"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func TestGetApiDemo(t *testing.T) {
+	ctx := requestid.InjectRequestID(context.Background(), 12345)
+	sandbox := &demo.Sandbox{UserID: 7, APIKey: "demo_try-me"}
+
+	resp, err := NewServer().GetApiDemo(env.WithCtx(ctx, &env.Env{Logger: log.NullLogger(), Demo: sandbox}),
+		GetApiDemoRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiDemo200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if v.ApiKey != "demo_try-me" || v.UserId != 7 {
+		t.Errorf("unexpected sandbox %+v", v)
+	}
+}
+
+func TestGetApiDemo_Disabled(t *testing.T) {
+	ctx := requestid.InjectRequestID(context.Background(), 12345)
+
+	resp, err := NewServer().GetApiDemo(env.WithCtx(ctx, &env.Env{Logger: log.NullLogger()}),
+		GetApiDemoRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := resp.(GetApiDemo404JSONResponse); !ok || v.Code != apiError.DemoDisabled.String() {
+		t.Errorf("expected demo_disabled, got %+v", resp)
+	}
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+)
+
+var errorResponseType = regexp.MustCompile(`^\w+?(\d{3})JSONResponse$`)
+
+// TestErrorResponsesUseCanonicalStatus checks that every error response
+// built by a handler carries a registered code, reports the status of
+// that code, and is returned with the matching HTTP status.
+func TestErrorResponsesUseCanonicalStatus(t *testing.T) {
+	fset := token.NewFileSet()
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatalf("reading package directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || strings.HasSuffix(name, ".gen.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatalf("parsing %s: %v", name, err)
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			typeName, ok := lit.Type.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			match := errorResponseType.FindStringSubmatch(typeName.Name)
+			if match == nil {
+				return true
+			}
+			httpStatus, _ := strconv.Atoi(match[1])
+
+			var code, statusCode string
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				switch key.Name {
+				case "Code":
+					code = apiErrorMethodReceiver(kv.Value, "String")
+				case "Status":
+					statusCode = apiErrorMethodReceiver(kv.Value, "StatusCode")
+				}
+			}
+			if code == "" {
+				return true
+			}
+
+			pos := fset.Position(lit.Pos())
+			if statusCode != code {
+				t.Errorf("%s: %s sets Status from something other than apiError.%s.StatusCode()", pos, typeName.Name, code)
+			}
+			definition, ok := lookupErrorConstant(code)
+			if !ok {
+				t.Errorf("%s: apiError.%s is not registered", pos, code)
+				return true
+			}
+			if definition.Status != httpStatus {
+				t.Errorf("%s: %s carries %s, whose status is %d", pos, typeName.Name, definition.Code, definition.Status)
+			}
+			return true
+		})
+	}
+}
+
+// apiErrorMethodReceiver returns X for an expression of the form
+// apiError.X.method().
+func apiErrorMethodReceiver(expr ast.Expr, method string) string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	fn, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || fn.Sel.Name != method {
+		return ""
+	}
+	constant, ok := fn.X.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	pkg, ok := constant.X.(*ast.Ident)
+	if !ok || pkg.Name != "apiError" {
+		return ""
+	}
+	return constant.Sel.Name
+}
+
+// lookupErrorConstant finds the definition of the apiError constant name.
+func lookupErrorConstant(name string) (apiError.Definition, bool) {
+	for _, d := range apiError.Definitions() {
+		if strings.ReplaceAll(string(d.Code), "_", "") == strings.ToLower(name) {
+			return d, true
+		}
+	}
+	return apiError.Definition{}, false
+}
+
+func TestGetApiErrors(t *testing.T) {
+	server := NewServer()
+
+	resp, err := server.GetApiErrors(context.Background(), GetApiErrorsRequestObject{})
+	if err != nil {
+		t.Fatalf("GetApiErrors() error = %v", err)
+	}
+	catalog, ok := resp.(GetApiErrors200JSONResponse)
+	if !ok {
+		t.Fatalf("unexpected response type: %T", resp)
+	}
+	if len(catalog.Errors) != len(apiError.Definitions()) {
+		t.Errorf("expected %d errors, got %d", len(apiError.Definitions()), len(catalog.Errors))
+	}
+	found := false
+	for _, e := range catalog.Errors {
+		if e.Code == apiError.RecipeNotFound.String() {
+			found = true
+			if e.Status != apiError.RecipeNotFound.StatusCode() {
+				t.Errorf("expected status %d, got %d", apiError.RecipeNotFound.StatusCode(), e.Status)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("catalog is missing %s", apiError.RecipeNotFound)
+	}
+
+	format := Markdown
+	resp, err = server.GetApiErrors(context.Background(), GetApiErrorsRequestObject{
+		Params: GetApiErrorsParams{Format: &format},
+	})
+	if err != nil {
+		t.Fatalf("GetApiErrors() error = %v", err)
+	}
+	markdown, ok := resp.(GetApiErrors200TextmarkdownResponse)
+	if !ok {
+		t.Fatalf("unexpected response type: %T", resp)
+	}
+	body, err := io.ReadAll(markdown.Body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if string(body) != apiError.Markdown() {
+		t.Error("markdown body does not match the catalog")
+	}
+}
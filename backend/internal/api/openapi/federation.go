@@ -0,0 +1,282 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/federation"
+)
+
+const federationDisabledMessage = "federation is not enabled"
+
+// federationFollowingResponse converts a stored follow for responses.
+func federationFollowingResponse(stored database.FederationFollowing) FederationFollowing {
+	return FederationFollowing{
+		Id:        stored.ID,
+		Handle:    stored.Handle,
+		Name:      stored.Name,
+		Actor:     stored.Actor,
+		Accepted:  stored.Accepted,
+		CreatedAt: stored.CreatedAt.Time,
+	}
+}
+
+// federateRecipe sends a recipe to or retracts it from the followers of
+// its owner after it was published or unpublished. Failures are logged;
+// failed deliveries are retried by the delivery job.
+func federateRecipe(ctx context.Context, env *env.Env, userID, recipeID int64, published bool) {
+	if !env.Config.Federation.Enabled {
+		return
+	}
+	env.Logger.DebugContext(ctx, "federating recipe", slog.Bool("published", published))
+	var err error
+	if published {
+		err = federation.Publish(ctx, env, recipeID)
+	} else {
+		err = federation.Retract(ctx, env, userID, recipeID)
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to federate recipe", slog.Any("error", err))
+	}
+}
+
+func (Server) GetApiFederationFollowing(ctx context.Context,
+	request GetApiFederationFollowingRequestObject) (
+	GetApiFederationFollowingResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	if !env.Config.Federation.Enabled {
+		return GetApiFederationFollowing404JSONResponse{
+			Status:  apiError.FederationDisabled.StatusCode(),
+			Code:    apiError.FederationDisabled.String(),
+			Message: federationDisabledMessage,
+			ErrorId: requestID,
+		}, nil
+	}
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return GetApiFederationFollowing400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get follows
+	env.Logger.DebugContext(ctx, "getting followed actors")
+	following, err := env.Database.GetFederationFollowing(ctx, userID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get followed actors", slog.Any("error", err))
+		return GetApiFederationFollowing500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	res := GetApiFederationFollowing200JSONResponse{
+		Handle:    federation.Handle(env.Config.HostOrigin, userID),
+		Following: make([]FederationFollowing, 0, len(following)),
+	}
+	for _, stored := range following {
+		res.Following = append(res.Following, federationFollowingResponse(stored))
+	}
+	return res, nil
+}
+
+func (Server) PostApiFederationFollowing(ctx context.Context,
+	request PostApiFederationFollowingRequestObject) (
+	PostApiFederationFollowingResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	if !env.Config.Federation.Enabled {
+		return PostApiFederationFollowing404JSONResponse{
+			Status:  apiError.FederationDisabled.StatusCode(),
+			Code:    apiError.FederationDisabled.String(),
+			Message: federationDisabledMessage,
+			ErrorId: requestID,
+		}, nil
+	}
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiFederationFollowing400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Follow actor
+	env.Logger.DebugContext(ctx, "following remote actor")
+	following, err := federation.Follow(ctx, env, userID, request.Body.Handle)
+	if errors.Is(err, federation.ErrInvalidHandle) || errors.Is(err, federation.ErrLocalActor) {
+		return PostApiFederationFollowing422JSONResponse{
+			Status:  apiError.UnprocessibleEntity.StatusCode(),
+			Code:    apiError.UnprocessibleEntity.String(),
+			Message: err.Error(),
+			ErrorId: requestID,
+		}, nil
+	} else if errors.Is(err, federation.ErrActorNotFound) {
+		env.Logger.WarnContext(ctx, "failed to resolve handle", slog.Any("error", err))
+		return PostApiFederationFollowing422JSONResponse{
+			Status:  apiError.RemoteActorNotFound.StatusCode(),
+			Code:    apiError.RemoteActorNotFound.String(),
+			Message: "handle could not be resolved",
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to follow remote actor", slog.Any("error", err))
+		return PostApiFederationFollowing500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PostApiFederationFollowing201JSONResponse(federationFollowingResponse(following)), nil
+}
+
+func (Server) DeleteApiFederationFollowingFollowingID(ctx context.Context,
+	request DeleteApiFederationFollowingFollowingIDRequestObject) (
+	DeleteApiFederationFollowingFollowingIDResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	if !env.Config.Federation.Enabled {
+		return DeleteApiFederationFollowingFollowingID404JSONResponse{
+			Status:  apiError.FederationDisabled.StatusCode(),
+			Code:    apiError.FederationDisabled.String(),
+			Message: federationDisabledMessage,
+			ErrorId: requestID,
+		}, nil
+	}
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return DeleteApiFederationFollowingFollowingID400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Unfollow actor
+	env.Logger.DebugContext(ctx, "unfollowing remote actor")
+	err = federation.Unfollow(ctx, env, userID, request.FollowingID)
+	if errors.Is(err, federation.ErrNotFollowing) {
+		return DeleteApiFederationFollowingFollowingID404JSONResponse{
+			Status:  apiError.FollowNotFound.StatusCode(),
+			Code:    apiError.FollowNotFound.String(),
+			Message: "follow not found",
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to unfollow remote actor", slog.Any("error", err))
+		return DeleteApiFederationFollowingFollowingID500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return DeleteApiFederationFollowingFollowingID204Response{}, nil
+}
+
+func (Server) GetApiFederationRecipes(ctx context.Context,
+	request GetApiFederationRecipesRequestObject) (
+	GetApiFederationRecipesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	if !env.Config.Federation.Enabled {
+		return GetApiFederationRecipes404JSONResponse{
+			Status:  apiError.FederationDisabled.StatusCode(),
+			Code:    apiError.FederationDisabled.String(),
+			Message: federationDisabledMessage,
+			ErrorId: requestID,
+		}, nil
+	}
+
+	limit := pageLimit(env, request.Params.Limit)
+	var offset int32
+	if request.Params.Offset != nil {
+		offset = *request.Params.Offset
+	}
+
+	env.Logger.DebugContext(ctx, "getting federated recipes")
+	rows, err := env.Database.GetFederatedRecipes(ctx, database.GetFederatedRecipesParams{
+		Limit:  limit + 1,
+		Offset: offset,
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get federated recipes", slog.Any("error", err))
+		return GetApiFederationRecipes500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	env.Logger.DebugContext(ctx, "counting federated recipes")
+	total, err := env.Database.GetFederatedRecipeCount(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to count federated recipes", slog.Any("error", err))
+		return GetApiFederationRecipes500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Build response
+	rows, page := newPage(rows, limit, total)
+	res := GetApiFederationRecipes200JSONResponse{
+		Recipes: make([]FederatedRecipe, len(rows)),
+		Page:    page,
+	}
+	for idx, row := range rows {
+		recipe := FederatedRecipe{
+			Id:          row.ID,
+			Title:       row.Title,
+			Author:      row.Author,
+			Url:         row.Url,
+			Ingredients: row.Ingredients,
+			Steps:       row.Steps,
+			PublishedAt: row.PublishedAt.Time,
+		}
+		if recipe.Ingredients == nil {
+			recipe.Ingredients = []string{}
+		}
+		if recipe.Steps == nil {
+			recipe.Steps = []string{}
+		}
+		if row.Description.Valid {
+			recipe.Description = &row.Description.String
+		}
+		if row.ImageUrl.Valid {
+			recipe.ImageUrl = &row.ImageUrl.String
+		}
+		res.Recipes[idx] = recipe
+	}
+
+	return res, nil
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/config"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func federationTestContext(mockDB database.Querier, enabled bool) context.Context {
+	ctx := context.Background()
+	ctx = requestid.InjectRequestID(ctx, 12345)
+	ctx = token.UserIDWithCtx(ctx, 42)
+	return env.WithCtx(ctx, &env.Env{
+		Logger:   log.NullLogger(),
+		Database: mockDB,
+		Config: config.Config{
+			HostOrigin: "https://wecook.example",
+			Federation: config.Federation{Enabled: enabled},
+		},
+	})
+}
+
+func TestFederationDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	ctx := federationTestContext(mockDB, false)
+
+	resp, err := NewServer().GetApiFederationRecipes(ctx, GetApiFederationRecipesRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := resp.(GetApiFederationRecipes404JSONResponse); !ok || v.Code != apiError.FederationDisabled.String() {
+		t.Errorf("expected federation_disabled, got %+v", resp)
+	}
+
+	followResp, err := NewServer().PostApiFederationFollowing(ctx, PostApiFederationFollowingRequestObject{
+		Body: &PostApiFederationFollowingJSONRequestBody{Handle: "7@cook.example"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := followResp.(PostApiFederationFollowing404JSONResponse); !ok {
+		t.Errorf("expected 404, got %T", followResp)
+	}
+}
+
+func TestGetApiFederationFollowing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().GetFederationFollowing(gomock.Any(), int64(42)).Return([]database.FederationFollowing{{
+		ID:       3,
+		UserID:   42,
+		Actor:    "https://cook.example/federation/users/7",
+		Handle:   "7@cook.example",
+		Name:     "Grace Hopper",
+		Accepted: true,
+	}}, nil)
+
+	resp, err := NewServer().GetApiFederationFollowing(federationTestContext(mockDB, true),
+		GetApiFederationFollowingRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiFederationFollowing200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if v.Handle != "42@wecook.example" {
+		t.Errorf("expected own handle 42@wecook.example, got %q", v.Handle)
+	}
+	if len(v.Following) != 1 || v.Following[0].Handle != "7@cook.example" || !v.Following[0].Accepted {
+		t.Errorf("unexpected following %+v", v.Following)
+	}
+}
+
+func TestDeleteApiFederationFollowingFollowingID_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().
+		DeleteFederationFollowing(gomock.Any(), database.DeleteFederationFollowingParams{ID: 3, UserID: 42}).
+		Return(database.FederationFollowing{}, pgx.ErrNoRows)
+
+	resp, err := NewServer().DeleteApiFederationFollowingFollowingID(federationTestContext(mockDB, true),
+		DeleteApiFederationFollowingFollowingIDRequestObject{FollowingID: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := resp.(DeleteApiFederationFollowingFollowingID404JSONResponse); !ok ||
+		v.Code != apiError.FollowNotFound.String() {
+		t.Errorf("expected follow_not_found, got %+v", resp)
+	}
+}
+
+func TestGetApiFederationRecipes(t *testing.T) {
+	published := time.Date(2026, time.April, 6, 9, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().
+		GetFederatedRecipes(gomock.Any(), database.GetFederatedRecipesParams{Limit: 2, Offset: 0}).
+		Return([]database.FederatedRecipe{
+			{ID: 2, Title: "Shakshuka", Author: "Grace Hopper", Url: "https://cook.example/recipes/9",
+				PublishedAt: pgtype.Timestamptz{Time: published, Valid: true}},
+			{ID: 1, Title: "Dal", Author: "Grace Hopper", Url: "https://cook.example/recipes/8",
+				Ingredients: []string{"1 cup lentils"}},
+		}, nil)
+	mockDB.EXPECT().GetFederatedRecipeCount(gomock.Any()).Return(int64(2), nil)
+
+	limit := int32(1)
+	resp, err := NewServer().GetApiFederationRecipes(federationTestContext(mockDB, true),
+		GetApiFederationRecipesRequestObject{Params: GetApiFederationRecipesParams{Limit: &limit}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiFederationRecipes200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(v.Recipes) != 1 || v.Recipes[0].Title != "Shakshuka" || !v.Recipes[0].PublishedAt.Equal(published) {
+		t.Errorf("unexpected recipes %+v", v.Recipes)
+	}
+	if v.Recipes[0].Ingredients == nil || v.Recipes[0].Steps == nil {
+		t.Error("expected empty ingredient and step lists, got nil")
+	}
+	if !v.Page.HasMore || v.Page.Total != 2 {
+		t.Errorf("unexpected page %+v", v.Page)
+	}
+}
@@ -3,11 +3,15 @@ package client
 import (
 	"bytes"
 	"context"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/matt-dz/wecook/docs"
 	apiError "github.com/matt-dz/wecook/internal/api/error"
 	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/version"
+	"github.com/matt-dz/wecook/internal/export"
 )
 
 var _ StrictServerInterface = (*Server)(nil)
@@ -22,6 +26,49 @@ func (Server) GetApiPing(ctx context.Context, request GetApiPingRequestObject) (
 	return GetApiPing200Response{}, nil
 }
 
+func (Server) GetApiVersions(ctx context.Context,
+	request GetApiVersionsRequestObject,
+) (GetApiVersionsResponseObject, error) {
+	versions := make([]ApiVersion, 0, len(version.Versions))
+	for _, v := range version.Versions {
+		versions = append(versions, ApiVersion{
+			Version: v.Name,
+			Prefix:  v.Prefix,
+			Status:  ApiVersionStatus(v.Status),
+			Sunset:  v.Sunset,
+		})
+	}
+
+	return GetApiVersions200JSONResponse{
+		Current:      version.Current,
+		Versions:     versions,
+		Capabilities: version.Capabilities,
+	}, nil
+}
+
+func (Server) GetApiErrors(ctx context.Context,
+	request GetApiErrorsRequestObject,
+) (GetApiErrorsResponseObject, error) {
+	if request.Params.Format != nil && *request.Params.Format == Markdown {
+		markdown := apiError.Markdown()
+		return GetApiErrors200TextmarkdownResponse{
+			Body:          strings.NewReader(markdown),
+			ContentLength: int64(len(markdown)),
+		}, nil
+	}
+
+	definitions := apiError.Definitions()
+	catalog := make([]ErrorDefinition, 0, len(definitions))
+	for _, d := range definitions {
+		catalog = append(catalog, ErrorDefinition{
+			Code:        d.Code.String(),
+			Status:      d.Status,
+			Description: d.Description,
+		})
+	}
+	return GetApiErrors200JSONResponse{Errors: catalog}, nil
+}
+
 func (Server) GetApiOpenapiYaml(
 	ctx context.Context,
 	request GetApiOpenapiYamlRequestObject,
@@ -43,3 +90,22 @@ func (Server) GetApiOpenapiYaml(
 		ContentLength: int64(len(data)),
 	}, nil
 }
+
+// recipeSchemaResponse writes the schema as stored. The generated response
+// would decode and re-encode it, losing the order of its keywords.
+type recipeSchemaResponse []byte
+
+func (r recipeSchemaResponse) VisitGetApiSchemasRecipeJsonResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(r)))
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(r)
+	return err
+}
+
+func (Server) GetApiSchemasRecipeJson(
+	ctx context.Context,
+	request GetApiSchemasRecipeJsonRequestObject,
+) (GetApiSchemasRecipeJsonResponseObject, error) {
+	return recipeSchemaResponse(export.RecipeSchema), nil
+}
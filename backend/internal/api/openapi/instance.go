@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/branding"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/form"
+)
+
+// instanceBranding builds the branding response from the stored row.
+func instanceBranding(env *env.Env, row database.InstanceBranding) InstanceBranding {
+	res := InstanceBranding{Name: row.Name}
+	if row.LogoKey.Valid {
+		logoURL := env.FileStore.FileURL(row.LogoKey.String)
+		res.LogoUrl = &logoURL
+	}
+	if row.AccentColor.Valid {
+		res.AccentColor = &row.AccentColor.String
+	}
+	if row.ContactEmail.Valid {
+		email := openapi_types.Email(row.ContactEmail.String)
+		res.ContactEmail = &email
+	}
+	return res
+}
+
+func (Server) GetApiInstance(ctx context.Context,
+	request GetApiInstanceRequestObject) (
+	GetApiInstanceResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Get branding
+	env.Logger.DebugContext(ctx, "getting instance branding")
+	row, err := env.Database.GetInstanceBranding(ctx, branding.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		row = database.InstanceBranding{ID: branding.ID, Name: branding.DefaultName}
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get instance branding", slog.Any("error", err))
+		return GetApiInstance500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return GetApiInstance200JSONResponse(instanceBranding(env, row)), nil
+}
+
+func (Server) PatchApiInstance(ctx context.Context,
+	request PatchApiInstanceRequestObject) (
+	PatchApiInstanceResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	params := database.UpdateInstanceBrandingParams{
+		UpdateAccentColor:  request.Body.AccentColor.IsSpecified(),
+		UpdateContactEmail: request.Body.ContactEmail.IsSpecified(),
+		ID:                 branding.ID,
+	}
+	if request.Body.Name != nil {
+		name := strings.TrimSpace(*request.Body.Name)
+		if name == "" {
+			return PatchApiInstance400JSONResponse{
+				Status:  apiError.BadRequest.StatusCode(),
+				Code:    apiError.BadRequest.String(),
+				Message: "name must not be blank",
+				ErrorId: requestID,
+			}, nil
+		}
+		params.Name = pgtype.Text{String: name, Valid: true}
+	}
+	if request.Body.AccentColor.IsSpecified() && !request.Body.AccentColor.IsNull() {
+		color, err := branding.NormalizeAccentColor(request.Body.AccentColor.MustGet())
+		if err != nil {
+			env.Logger.ErrorContext(ctx, "invalid accent color", slog.Any("error", err))
+			return PatchApiInstance400JSONResponse{
+				Status:  apiError.BadRequest.StatusCode(),
+				Code:    apiError.BadRequest.String(),
+				Message: "invalid accent color",
+				ErrorId: requestID,
+			}, nil
+		}
+		params.AccentColor = pgtype.Text{String: color, Valid: true}
+	}
+	if request.Body.ContactEmail.IsSpecified() && !request.Body.ContactEmail.IsNull() {
+		params.ContactEmail = pgtype.Text{String: string(request.Body.ContactEmail.MustGet()), Valid: true}
+	}
+
+	// Update branding
+	env.Logger.DebugContext(ctx, "updating instance branding")
+	row, err := env.Database.UpdateInstanceBranding(ctx, params)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to update instance branding", slog.Any("error", err))
+		return PatchApiInstance500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PatchApiInstance200JSONResponse(instanceBranding(env, row)), nil
+}
+
+func (Server) PostApiInstanceLogo(ctx context.Context,
+	request PostApiInstanceLogoRequestObject) (
+	PostApiInstanceLogoResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Read form
+	env.Logger.DebugContext(ctx, "reading logo form")
+	requestForm, err := request.Body.ReadForm(form.MaximumUploadSize)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to read form", slog.Any("error", err))
+		return PostApiInstanceLogo400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "invalid form",
+			ErrorId: requestID,
+		}, nil
+	}
+	if len(requestForm.File["image"]) == 0 {
+		return PostApiInstanceLogo400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing image",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Read image
+	env.Logger.DebugContext(ctx, "reading logo")
+	imageHeader := requestForm.File["image"][0]
+	imageFile, err := imageHeader.Open()
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to open image", slog.Any("error", err))
+		return PostApiInstanceLogo400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "invalid image",
+			ErrorId: requestID,
+		}, nil
+	}
+	defer func() { _ = imageFile.Close() }()
+	file, err := form.ReadImage(imageFile, imageHeader.Filename)
+	if errors.Is(err, form.ErrUnsupportedMimeType) {
+		env.Logger.ErrorContext(ctx, "unsupported format", slog.Any("error", err))
+		return PostApiInstanceLogo422JSONResponse{
+			Status:  apiError.UnsupportedImageFormat.StatusCode(),
+			Code:    apiError.UnsupportedImageFormat.String(),
+			Message: "unsupported image format",
+			ErrorId: requestID,
+		}, nil
+	}
+	if errors.Is(err, form.ErrExtensionMismatch) {
+		env.Logger.ErrorContext(ctx, "image extension does not match content", slog.Any("error", err))
+		return PostApiInstanceLogo422JSONResponse{
+			Status:  apiError.UnsupportedImageFormat.StatusCode(),
+			Code:    apiError.UnsupportedImageFormat.String(),
+			Message: "image extension does not match its contents",
+			ErrorId: requestID,
+		}, nil
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to read image", slog.Any("error", err))
+		return PostApiInstanceLogo400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "invalid image",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Write image
+	env.Logger.DebugContext(ctx, "writing logo")
+	logoKey, _, err := env.FileStore.WriteBrandingImage(file.Suffix, file.Data)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to write logo", slog.Any("error", err))
+		return PostApiInstanceLogo500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Store logo
+	env.Logger.DebugContext(ctx, "storing logo")
+	oldKey, err := env.Database.SetInstanceLogo(ctx, database.SetInstanceLogoParams{
+		ID:      branding.ID,
+		LogoKey: pgtype.Text{String: logoKey, Valid: true},
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to store logo", slog.Any("error", err))
+		if err := env.FileStore.DeleteKey(logoKey); err != nil {
+			env.Logger.WarnContext(ctx, "failed to delete unused logo", slog.Any("error", err))
+		}
+		return PostApiInstanceLogo500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	deleteReplacedLogo(ctx, env, oldKey)
+
+	// Get branding
+	row, err := env.Database.GetInstanceBranding(ctx, branding.ID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get instance branding", slog.Any("error", err))
+		return PostApiInstanceLogo500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PostApiInstanceLogo200JSONResponse(instanceBranding(env, row)), nil
+}
+
+func (Server) DeleteApiInstanceLogo(ctx context.Context,
+	request DeleteApiInstanceLogoRequestObject) (
+	DeleteApiInstanceLogoResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Clear logo
+	env.Logger.DebugContext(ctx, "removing logo")
+	oldKey, err := env.Database.SetInstanceLogo(ctx, database.SetInstanceLogoParams{ID: branding.ID})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to remove logo", slog.Any("error", err))
+		return DeleteApiInstanceLogo500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	deleteReplacedLogo(ctx, env, oldKey)
+
+	// Get branding
+	row, err := env.Database.GetInstanceBranding(ctx, branding.ID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get instance branding", slog.Any("error", err))
+		return DeleteApiInstanceLogo500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return DeleteApiInstanceLogo200JSONResponse(instanceBranding(env, row)), nil
+}
+
+// deleteReplacedLogo deletes the file of a logo that is no longer used.
+func deleteReplacedLogo(ctx context.Context, env *env.Env, key pgtype.Text) {
+	if !key.Valid {
+		return
+	}
+	env.Logger.DebugContext(ctx, "deleting replaced logo")
+	if err := env.FileStore.DeleteKey(key.String); err != nil {
+		env.Logger.WarnContext(ctx, "failed to delete replaced logo", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/oapi-codegen/nullable"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/branding"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/filestore"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func instanceTestContext(mockDB database.Querier, mockFS filestore.FileStoreInterface) context.Context {
+	ctx := requestid.InjectRequestID(context.Background(), 12345)
+	return env.WithCtx(ctx, &env.Env{
+		Logger:    log.NullLogger(),
+		Database:  mockDB,
+		FileStore: mockFS,
+	})
+}
+
+func TestGetApiInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockFS := filestore.NewMockFileStoreInterface(ctrl)
+	mockDB.EXPECT().GetInstanceBranding(gomock.Any(), int32(branding.ID)).Return(database.InstanceBranding{
+		ID:          branding.ID,
+		Name:        "Test Kitchen",
+		LogoKey:     pgtype.Text{String: "/files/branding/logo.png", Valid: true},
+		AccentColor: pgtype.Text{String: "#ee5522", Valid: true},
+	}, nil)
+	mockFS.EXPECT().FileURL("/files/branding/logo.png").Return("https://wecook.example/files/branding/logo.png")
+
+	resp, err := NewServer().GetApiInstance(instanceTestContext(mockDB, mockFS), GetApiInstanceRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiInstance200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if v.Name != "Test Kitchen" || v.AccentColor == nil || *v.AccentColor != "#ee5522" || v.ContactEmail != nil {
+		t.Errorf("unexpected branding %+v", v)
+	}
+	if v.LogoUrl == nil || *v.LogoUrl != "https://wecook.example/files/branding/logo.png" {
+		t.Errorf("unexpected logo url %v", v.LogoUrl)
+	}
+}
+
+func TestGetApiInstance_Default(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().
+		GetInstanceBranding(gomock.Any(), int32(branding.ID)).
+		Return(database.InstanceBranding{}, pgx.ErrNoRows)
+
+	resp, err := NewServer().GetApiInstance(instanceTestContext(mockDB, nil), GetApiInstanceRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := resp.(GetApiInstance200JSONResponse); !ok || v.Name != branding.DefaultName || v.LogoUrl != nil {
+		t.Errorf("expected default branding, got %+v", resp)
+	}
+}
+
+func TestPatchApiInstance(t *testing.T) {
+	name := "  Test Kitchen "
+	tests := []struct {
+		name     string
+		body     UpdateInstanceBrandingRequest
+		dbSetup  func(mockDB *database.MockQuerier)
+		wantCode string
+	}{
+		{
+			name: "normalizes accent color and clears contact email",
+			body: UpdateInstanceBrandingRequest{
+				Name:         &name,
+				AccentColor:  nullable.NewNullableWithValue("#E52"),
+				ContactEmail: nullable.NewNullNullable[openapi_types.Email](),
+			},
+			dbSetup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					UpdateInstanceBranding(gomock.Any(), database.UpdateInstanceBrandingParams{
+						Name:               pgtype.Text{String: "Test Kitchen", Valid: true},
+						UpdateAccentColor:  true,
+						AccentColor:        pgtype.Text{String: "#ee5522", Valid: true},
+						UpdateContactEmail: true,
+						ID:                 branding.ID,
+					}).
+					Return(database.InstanceBranding{
+						ID:          branding.ID,
+						Name:        "Test Kitchen",
+						AccentColor: pgtype.Text{String: "#ee5522", Valid: true},
+					}, nil)
+			},
+		},
+		{
+			name: "unset fields are left alone",
+			body: UpdateInstanceBrandingRequest{},
+			dbSetup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					UpdateInstanceBranding(gomock.Any(), database.UpdateInstanceBrandingParams{ID: branding.ID}).
+					Return(database.InstanceBranding{ID: branding.ID, Name: branding.DefaultName}, nil)
+			},
+		},
+		{
+			name:     "invalid accent color",
+			body:     UpdateInstanceBrandingRequest{AccentColor: nullable.NewNullableWithValue("#ee55")},
+			dbSetup:  func(mockDB *database.MockQuerier) {},
+			wantCode: apiError.BadRequest.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := database.NewMockQuerier(ctrl)
+			tt.dbSetup(mockDB)
+
+			resp, err := NewServer().PatchApiInstance(instanceTestContext(mockDB, nil),
+				PatchApiInstanceRequestObject{Body: &tt.body})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantCode != "" {
+				if v, ok := resp.(PatchApiInstance400JSONResponse); !ok || v.Code != tt.wantCode {
+					t.Errorf("expected %s, got %+v", tt.wantCode, resp)
+				}
+				return
+			}
+			if _, ok := resp.(PatchApiInstance200JSONResponse); !ok {
+				t.Errorf("expected 200 response, got %T", resp)
+			}
+		})
+	}
+}
+
+func TestDeleteApiInstanceLogo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockFS := filestore.NewMockFileStoreInterface(ctrl)
+	mockDB.EXPECT().
+		SetInstanceLogo(gomock.Any(), database.SetInstanceLogoParams{ID: branding.ID}).
+		Return(pgtype.Text{String: "/files/branding/logo.png", Valid: true}, nil)
+	mockFS.EXPECT().DeleteKey("/files/branding/logo.png").Return(nil)
+	mockDB.EXPECT().
+		GetInstanceBranding(gomock.Any(), int32(branding.ID)).
+		Return(database.InstanceBranding{ID: branding.ID, Name: "Test Kitchen"}, nil)
+
+	resp, err := NewServer().DeleteApiInstanceLogo(instanceTestContext(mockDB, mockFS),
+		DeleteApiInstanceLogoRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := resp.(DeleteApiInstanceLogo200JSONResponse); !ok || v.LogoUrl != nil {
+		t.Errorf("expected branding without logo, got %+v", resp)
+	}
+}
@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/ingredient"
+	"github.com/matt-dz/wecook/internal/mealprep"
+	"github.com/matt-dz/wecook/internal/tagging"
+)
+
+const hoursPerDay = 24
+
+func (Server) PostApiMealprepPlan(ctx context.Context,
+	request PostApiMealprepPlanRequestObject) (
+	PostApiMealprepPlanResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiMealprepPlan500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Validate request
+	env.Logger.DebugContext(ctx, "validating request")
+	if request.Body == nil || len(request.Body.Recipes) == 0 {
+		return PostApiMealprepPlan400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "at least one recipe is required",
+			ErrorId: requestID,
+		}, nil
+	}
+	seen := make(map[int64]bool, len(request.Body.Recipes))
+	for _, r := range request.Body.Recipes {
+		if seen[r.RecipeId] {
+			return PostApiMealprepPlan400JSONResponse{
+				Status:  apiError.BadRequest.StatusCode(),
+				Code:    apiError.BadRequest.String(),
+				Message: "recipe " + strconv.FormatInt(r.RecipeId, 10) + " is listed more than once",
+				ErrorId: requestID,
+			}, nil
+		}
+		if r.Servings <= 0 {
+			return PostApiMealprepPlan400JSONResponse{
+				Status:  apiError.BadRequest.StatusCode(),
+				Code:    apiError.BadRequest.String(),
+				Message: "servings must be greater than zero",
+				ErrorId: requestID,
+			}, nil
+		}
+		seen[r.RecipeId] = true
+	}
+
+	// Get allergies
+	var allergies []string
+	if request.Body.ExcludeAllergens != nil && *request.Body.ExcludeAllergens {
+		env.Logger.DebugContext(ctx, "getting allergies")
+		allergies, err = env.Database.GetUserAllergies(ctx, userID)
+		if err != nil {
+			env.Logger.ErrorContext(ctx, "failed to get allergies", slog.Any("error", err))
+			return PostApiMealprepPlan500JSONResponse{
+				Status:  apiError.InternalServerError.StatusCode(),
+				Code:    apiError.InternalServerError.String(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+	}
+
+	recipes := make([]mealprep.Recipe, 0, len(request.Body.Recipes))
+	excluded := make([]MealPrepExcludedRecipe, 0)
+	for _, r := range request.Body.Recipes {
+		// Check ownership
+		env.Logger.DebugContext(ctx, "checking user ownership", slog.Int64("recipe_id", r.RecipeId))
+		ownsRecipe, err := env.Database.CheckRecipeOwnership(ctx, database.CheckRecipeOwnershipParams{
+			ID: r.RecipeId,
+			UserID: pgtype.Int8{
+				Int64: userID,
+				Valid: true,
+			},
+		})
+		if err != nil {
+			env.Logger.ErrorContext(ctx, "failed to check recipe ownership", slog.Any("error", err))
+			return PostApiMealprepPlan500JSONResponse{
+				Status:  apiError.InternalServerError.StatusCode(),
+				Code:    apiError.InternalServerError.String(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+		if !ownsRecipe {
+			env.Logger.ErrorContext(ctx, "user does not own recipe", slog.Int64("recipe_id", r.RecipeId))
+			return PostApiMealprepPlan404JSONResponse{
+				Status:  apiError.RecipeNotFound.StatusCode(),
+				Code:    apiError.RecipeNotFound.String(),
+				Message: "recipe " + strconv.FormatInt(r.RecipeId, 10) + " does not exist or user does not own it",
+				ErrorId: requestID,
+			}, nil
+		}
+
+		// Get recipe details
+		env.Logger.DebugContext(ctx, "getting recipe details", slog.Int64("recipe_id", r.RecipeId))
+		recipe, err := mealPrepRecipe(ctx, env.Database, r)
+		if errors.Is(err, pgx.ErrNoRows) {
+			env.Logger.ErrorContext(ctx, "recipe does not exist", slog.Any("error", err))
+			return PostApiMealprepPlan404JSONResponse{
+				Status:  apiError.RecipeNotFound.StatusCode(),
+				Code:    apiError.RecipeNotFound.String(),
+				Message: "recipe " + strconv.FormatInt(r.RecipeId, 10) + " does not exist or user does not own it",
+				ErrorId: requestID,
+			}, nil
+		} else if err != nil {
+			env.Logger.ErrorContext(ctx, "failed to get recipe details", slog.Any("error", err))
+			return PostApiMealprepPlan500JSONResponse{
+				Status:  apiError.InternalServerError.StatusCode(),
+				Code:    apiError.InternalServerError.String(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+
+		// Leave out recipes containing allergies
+		if matches := tagging.DetectAllergens(recipe.Ingredients, allergies); len(matches) > 0 {
+			env.Logger.DebugContext(ctx, "excluding recipe with allergens", slog.Int64("recipe_id", r.RecipeId))
+			entry := MealPrepExcludedRecipe{
+				RecipeId:  recipe.ID,
+				Title:     recipe.Title,
+				Allergens: make([]Allergen, 0, len(matches)),
+			}
+			for _, match := range matches {
+				entry.Allergens = append(entry.Allergens, Allergen(match.Allergen))
+			}
+			excluded = append(excluded, entry)
+			continue
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	// Build plan
+	env.Logger.DebugContext(ctx, "building meal prep plan")
+	plan := mealprep.NewPlan(recipes)
+
+	response := PostApiMealprepPlan200JSONResponse{
+		Recipes:      make([]MealPrepRecipe, 0, len(recipes)),
+		Ingredients:  make([]MealPrepIngredient, 0, len(plan.Ingredients)),
+		Equipment:    make([]MealPrepEquipment, 0, len(plan.Equipment)),
+		Timeline:     make([]MealPrepTimelineEntry, 0, len(plan.Timeline)),
+		TotalMinutes: int64(plan.Total / time.Minute),
+		Excluded:     excluded,
+	}
+	for _, recipe := range recipes {
+		entry := MealPrepRecipe{
+			RecipeId:       recipe.ID,
+			Title:          recipe.Title,
+			TargetServings: float32(recipe.TargetServings),
+			Scale:          recipe.Scale(),
+		}
+		if recipe.Servings > 0 {
+			servings := float32(recipe.Servings)
+			entry.Servings = &servings
+		}
+		response.Recipes = append(response.Recipes, entry)
+	}
+	locale := ingredient.English
+	if request.Body.Locale != nil {
+		locale = ingredient.Locale(*request.Body.Locale)
+	}
+	for _, item := range plan.Ingredients {
+		response.Ingredients = append(response.Ingredients, MealPrepIngredient{
+			Quantity:  item.Quantity,
+			Item:      item.Item,
+			Display:   ingredient.Format(ingredient.ParseItem(item.Quantity, item.Item), locale),
+			RecipeIds: item.RecipeIDs,
+		})
+	}
+	for _, equipment := range plan.Equipment {
+		response.Equipment = append(response.Equipment, MealPrepEquipment{
+			Name:      equipment.Name,
+			RecipeIds: equipment.RecipeIDs,
+		})
+	}
+	for _, slot := range plan.Timeline {
+		response.Timeline = append(response.Timeline, MealPrepTimelineEntry{
+			RecipeId:    slot.RecipeID,
+			Title:       slot.Title,
+			Phase:       MealPrepTimelineEntryPhase(slot.Phase),
+			StartMinute: int64(slot.Start / time.Minute),
+			EndMinute:   int64(slot.End / time.Minute),
+		})
+	}
+
+	return response, nil
+}
+
+// mealPrepRecipe loads a recipe with its ingredients and steps.
+func mealPrepRecipe(ctx context.Context, db database.Querier, r MealPrepRecipeRequest) (
+	mealprep.Recipe, error,
+) {
+	row, err := db.GetRecipeAndOwner(ctx, r.RecipeId)
+	if err != nil {
+		return mealprep.Recipe{}, err
+	}
+	ingredients, err := db.GetRecipeIngredients(ctx, r.RecipeId)
+	if err != nil {
+		return mealprep.Recipe{}, err
+	}
+	steps, err := db.GetRecipeSteps(ctx, r.RecipeId)
+	if err != nil {
+		return mealprep.Recipe{}, err
+	}
+
+	recipe := mealprep.Recipe{
+		ID:             row.ID,
+		Title:          row.Title,
+		TargetServings: float64(r.Servings),
+		Prep:           timeAmount(row.PrepTimeAmount, row.PrepTimeUnit),
+		Cook:           timeAmount(row.CookTimeAmount, row.CookTimeUnit),
+	}
+	if row.Servings.Valid {
+		recipe.Servings = float64(row.Servings.Float32)
+	}
+	for _, ingredient := range ingredients {
+		if ingredient.Description.Valid {
+			recipe.Ingredients = append(recipe.Ingredients, ingredient.Description.String)
+		}
+	}
+	for _, step := range steps {
+		if step.Instruction.Valid {
+			recipe.Instructions = append(recipe.Instructions, step.Instruction.String)
+		}
+	}
+
+	return recipe, nil
+}
+
+// timeAmount converts a recipe time amount and unit to a duration.
+func timeAmount(amount pgtype.Int4, unit database.NullTimeUnit) time.Duration {
+	if !amount.Valid || !unit.Valid {
+		return 0
+	}
+	d := time.Duration(amount.Int32)
+	switch unit.TimeUnit {
+	case database.TimeUnitMinutes:
+		return d * time.Minute
+	case database.TimeUnitHours:
+		return d * time.Hour
+	case database.TimeUnitDays:
+		return d * hoursPerDay * time.Hour
+	default:
+		return 0
+	}
+}
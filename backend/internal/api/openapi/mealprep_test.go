@@ -0,0 +1,240 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func TestPostApiMealprepPlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	server := NewServer()
+
+	expectRecipe := func(id int64, row database.GetRecipeAndOwnerRow,
+		ingredients []database.RecipeIngredient, steps []database.RecipeStep,
+	) {
+		mockDB.EXPECT().
+			CheckRecipeOwnership(gomock.Any(), database.CheckRecipeOwnershipParams{
+				ID:     id,
+				UserID: pgtype.Int8{Int64: 42, Valid: true},
+			}).
+			Return(true, nil)
+		mockDB.EXPECT().GetRecipeAndOwner(gomock.Any(), id).Return(row, nil)
+		mockDB.EXPECT().GetRecipeIngredients(gomock.Any(), id).Return(ingredients, nil)
+		mockDB.EXPECT().GetRecipeSteps(gomock.Any(), id).Return(steps, nil)
+	}
+	text := func(s string) pgtype.Text { return pgtype.Text{String: s, Valid: true} }
+	minutes := database.NullTimeUnit{TimeUnit: database.TimeUnitMinutes, Valid: true}
+	hours := database.NullTimeUnit{TimeUnit: database.TimeUnitHours, Valid: true}
+	four, three, one := 4.0, 3.0, 1.0
+	exclude := true
+	servings := float32(2)
+
+	tests := []struct {
+		name       string
+		body       *MealPrepPlanRequest
+		setup      func()
+		wantStatus int
+		wantCode   string
+		wantPlan   *MealPrepPlan
+	}{
+		{
+			name: "combines recipes",
+			body: &MealPrepPlanRequest{Recipes: []MealPrepRecipeRequest{
+				{RecipeId: 1, Servings: 4},
+				{RecipeId: 2, Servings: 6},
+			}},
+			setup: func() {
+				expectRecipe(1, database.GetRecipeAndOwnerRow{
+					ID:             1,
+					Title:          "Porridge",
+					Servings:       pgtype.Float4{Float32: 2, Valid: true},
+					PrepTimeAmount: pgtype.Int4{Int32: 5, Valid: true},
+					PrepTimeUnit:   minutes,
+					CookTimeAmount: pgtype.Int4{Int32: 10, Valid: true},
+					CookTimeUnit:   minutes,
+				}, []database.RecipeIngredient{
+					{Description: text("1 cup oats")},
+					{Description: text("1 1/2 cups milk")},
+				}, []database.RecipeStep{
+					{Instruction: text("Simmer the oats in a saucepan.")},
+				})
+				expectRecipe(2, database.GetRecipeAndOwnerRow{
+					ID:             2,
+					Title:          "Granola",
+					PrepTimeAmount: pgtype.Int4{Int32: 10, Valid: true},
+					PrepTimeUnit:   minutes,
+					CookTimeAmount: pgtype.Int4{Int32: 1, Valid: true},
+					CookTimeUnit:   hours,
+				}, []database.RecipeIngredient{
+					{Description: text("2 cups oats")},
+					{Description: pgtype.Text{}},
+				}, nil)
+			},
+			wantStatus: 200,
+			wantPlan: &MealPrepPlan{
+				Recipes: []MealPrepRecipe{
+					{RecipeId: 1, Title: "Porridge", Servings: &servings, TargetServings: 4, Scale: 2},
+					{RecipeId: 2, Title: "Granola", TargetServings: 6, Scale: 1},
+				},
+				Ingredients: []MealPrepIngredient{
+					{Quantity: &four, Item: "cup oats", Display: "4 cups oats", RecipeIds: []int64{1, 2}},
+					{Quantity: &three, Item: "cups milk", Display: "3 cups milk", RecipeIds: []int64{1}},
+				},
+				Equipment: []MealPrepEquipment{
+					{Name: "saucepan", RecipeIds: []int64{1}},
+					{Name: "stovetop", RecipeIds: []int64{1}},
+				},
+				Timeline: []MealPrepTimelineEntry{
+					{RecipeId: 2, Title: "Granola", Phase: Prep, StartMinute: 0, EndMinute: 10},
+					{RecipeId: 2, Title: "Granola", Phase: Cook, StartMinute: 10, EndMinute: 70},
+					{RecipeId: 1, Title: "Porridge", Phase: Prep, StartMinute: 10, EndMinute: 15},
+					{RecipeId: 1, Title: "Porridge", Phase: Cook, StartMinute: 15, EndMinute: 25},
+				},
+				TotalMinutes: 70,
+				Excluded:     []MealPrepExcludedRecipe{},
+			},
+		},
+		{
+			name: "excludes recipes with allergens",
+			body: &MealPrepPlanRequest{
+				Recipes: []MealPrepRecipeRequest{
+					{RecipeId: 6, Servings: 2},
+					{RecipeId: 7, Servings: 2},
+				},
+				ExcludeAllergens: &exclude,
+			},
+			setup: func() {
+				mockDB.EXPECT().GetUserAllergies(gomock.Any(), int64(42)).Return([]string{"peanuts"}, nil)
+				expectRecipe(6, database.GetRecipeAndOwnerRow{ID: 6, Title: "Satay"},
+					[]database.RecipeIngredient{{Description: text("1/2 cup peanut butter")}}, nil)
+				expectRecipe(7, database.GetRecipeAndOwnerRow{ID: 7, Title: "Rice"},
+					[]database.RecipeIngredient{{Description: text("1 cup rice")}}, nil)
+			},
+			wantStatus: 200,
+			wantPlan: &MealPrepPlan{
+				Recipes: []MealPrepRecipe{
+					{RecipeId: 7, Title: "Rice", TargetServings: 2, Scale: 1},
+				},
+				Ingredients: []MealPrepIngredient{
+					{Quantity: &one, Item: "cup rice", Display: "1 cup rice", RecipeIds: []int64{7}},
+				},
+				Equipment: []MealPrepEquipment{},
+				Timeline:  []MealPrepTimelineEntry{},
+				Excluded: []MealPrepExcludedRecipe{
+					{RecipeId: 6, Title: "Satay", Allergens: []Allergen{"peanuts"}},
+				},
+			},
+		},
+		{
+			name:       "missing body",
+			setup:      func() {},
+			wantStatus: 400,
+			wantCode:   apiError.BadRequest.String(),
+		},
+		{
+			name: "duplicate recipe",
+			body: &MealPrepPlanRequest{Recipes: []MealPrepRecipeRequest{
+				{RecipeId: 1, Servings: 4},
+				{RecipeId: 1, Servings: 2},
+			}},
+			setup:      func() {},
+			wantStatus: 400,
+			wantCode:   apiError.BadRequest.String(),
+		},
+		{
+			name: "recipe not owned",
+			body: &MealPrepPlanRequest{Recipes: []MealPrepRecipeRequest{{RecipeId: 3, Servings: 4}}},
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.RecipeNotFound.String(),
+		},
+		{
+			name: "recipe deleted concurrently",
+			body: &MealPrepPlanRequest{Recipes: []MealPrepRecipeRequest{{RecipeId: 4, Servings: 4}}},
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+				mockDB.EXPECT().
+					GetRecipeAndOwner(gomock.Any(), int64(4)).
+					Return(database.GetRecipeAndOwnerRow{}, pgx.ErrNoRows)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.RecipeNotFound.String(),
+		},
+		{
+			name: "database error",
+			body: &MealPrepPlanRequest{Recipes: []MealPrepRecipeRequest{{RecipeId: 5, Servings: 4}}},
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(false, errors.New("db down"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			ctx := context.Background()
+			ctx = requestid.InjectRequestID(ctx, 12345)
+			ctx = token.UserIDWithCtx(ctx, 42)
+			ctx = env.WithCtx(ctx, &env.Env{
+				Logger:   log.NullLogger(),
+				Database: mockDB,
+			})
+
+			resp, err := server.PostApiMealprepPlan(ctx, PostApiMealprepPlanRequestObject{Body: tt.body})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case PostApiMealprepPlan200JSONResponse:
+				if tt.wantStatus != 200 {
+					t.Errorf("expected status %d, got 200", tt.wantStatus)
+				}
+				if !reflect.DeepEqual(MealPrepPlan(v), *tt.wantPlan) {
+					t.Errorf("expected plan %+v, got %+v", *tt.wantPlan, v)
+				}
+			case PostApiMealprepPlan400JSONResponse:
+				if tt.wantStatus != 400 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 400 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiMealprepPlan404JSONResponse:
+				if tt.wantStatus != 404 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 404 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiMealprepPlan500JSONResponse:
+				if tt.wantStatus != 500 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 500 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			default:
+				t.Fatalf("unexpected response type %T", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package client
+
+import "github.com/matt-dz/wecook/internal/env"
+
+// List queries fetch one item past the page size, which tells whether
+// another page follows without a second query.
+
+// pageLimit returns the page size of a list request that asked for
+// requested items.
+func pageLimit(env *env.Env, requested *int32) int32 {
+	return env.Config.Pagination.Limit(requested)
+}
+
+// newPage trims the item fetched past limit from items and describes the
+// page. total is the number of items on all pages.
+func newPage[T any](items []T, limit int32, total int64) ([]T, Page) {
+	hasMore := len(items) > int(limit)
+	if hasMore {
+		items = items[:limit]
+	}
+	return items, Page{
+		Limit:   limit,
+		Total:   total,
+		HasMore: hasMore,
+	}
+}
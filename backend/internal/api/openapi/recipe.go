@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"strconv"
 
@@ -13,10 +14,14 @@ import (
 	apiError "github.com/matt-dz/wecook/internal/api/error"
 	"github.com/matt-dz/wecook/internal/api/requestid"
 	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/audit"
+	"github.com/matt-dz/wecook/internal/completeness"
 	"github.com/matt-dz/wecook/internal/database"
 	"github.com/matt-dz/wecook/internal/env"
-	"github.com/matt-dz/wecook/internal/fileserver"
 	"github.com/matt-dz/wecook/internal/form"
+	"github.com/matt-dz/wecook/internal/tagging"
+	"github.com/matt-dz/wecook/internal/temperature"
+	"github.com/matt-dz/wecook/internal/undo"
 )
 
 const (
@@ -108,6 +113,7 @@ func buildRecipeWithIngredientsAndSteps(
 			imageURL := env.FileStore.FileURL(step.ImageKey.String)
 			newStep.ImageUrl = &imageURL
 		}
+		newStep.Temperature = stepTemperature(step.TemperatureValue, step.TemperatureUnit)
 		recipe.Steps = append(recipe.Steps, newStep)
 	}
 
@@ -131,6 +137,100 @@ func buildRecipeWithIngredientsAndSteps(
 	return recipe, owner, nil
 }
 
+// stepTemperature builds a step temperature from its columns, returning nil
+// when the step has none.
+func stepTemperature(value pgtype.Float4, unit database.NullTemperatureUnit) *Temperature {
+	if !value.Valid || !unit.Valid {
+		return nil
+	}
+	return &Temperature{
+		Value: value.Float32,
+		Unit:  TemperatureUnit(unit.TemperatureUnit),
+	}
+}
+
+// hasStepTemperatures reports whether any step has a temperature.
+func hasStepTemperatures(steps []RecipeStep) bool {
+	for _, step := range steps {
+		if step.Temperature != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// convertStepTemperatures converts every step temperature to unit in place.
+func convertStepTemperatures(steps []RecipeStep, unit TemperatureUnit) {
+	for i, step := range steps {
+		if step.Temperature == nil {
+			continue
+		}
+		converted, err := temperature.Temperature{
+			Value: float64(step.Temperature.Value),
+			Unit:  temperature.Unit(step.Temperature.Unit),
+		}.Convert(temperature.Unit(unit))
+		if err != nil {
+			continue
+		}
+		steps[i].Temperature = &Temperature{
+			Value: float32(converted.Value),
+			Unit:  TemperatureUnit(converted.Unit),
+		}
+	}
+}
+
+// allergenNames converts allergens to their names in the tagging package.
+func allergenNames(allergens []Allergen) []string {
+	names := make([]string, 0, len(allergens))
+	for _, allergen := range allergens {
+		names = append(names, string(allergen))
+	}
+	return names
+}
+
+// allergyWarnings flags the ingredients that contain one of allergies.
+func allergyWarnings(ingredients []RecipeIngredient, allergies []string) []AllergyWarning {
+	warnings := make([]AllergyWarning, 0)
+	if len(allergies) == 0 {
+		return warnings
+	}
+	descriptions := make([]string, len(ingredients))
+	for idx, ingredient := range ingredients {
+		if description, err := ingredient.Description.Get(); err == nil {
+			descriptions[idx] = description
+		}
+	}
+	for _, match := range tagging.DetectAllergens(descriptions, allergies) {
+		warning := AllergyWarning{
+			Allergen:      Allergen(match.Allergen),
+			Message:       "contains " + tagging.AllergenLabel(match.Allergen),
+			IngredientIds: make([]int64, 0, len(match.Ingredients)),
+		}
+		for _, idx := range match.Ingredients {
+			warning.IngredientIds = append(warning.IngredientIds, ingredients[idx].Id)
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+// recordRecipeActivity records an action on a recipe to the audit trail.
+// The action has already happened, so a failure is only logged.
+func recordRecipeActivity(ctx context.Context, env *env.Env, userID int64, action audit.Action,
+	recipeID int64, metadata any,
+) {
+	env.Logger.DebugContext(ctx, "recording audit event", slog.String("action", string(action)))
+	if err := audit.Record(ctx, env.Database, audit.Event{
+		ActorID:    userID,
+		Action:     action,
+		TargetType: audit.TargetRecipe,
+		TargetID:   recipeID,
+		Metadata:   metadata,
+	}); err != nil {
+		env.Logger.ErrorContext(ctx, "failed to record audit event", slog.Any("error", err))
+	}
+}
+
 func (Server) PostApiRecipes(ctx context.Context,
 	request PostApiRecipesRequestObject,
 ) (PostApiRecipesResponseObject, error) {
@@ -166,6 +266,9 @@ func (Server) PostApiRecipes(ctx context.Context,
 		}, nil
 	}
 
+	// Record activity. The recipe exists, so a failure is only logged.
+	recordRecipeActivity(ctx, env, userID, audit.ActionCreateRecipe, recipeID, nil)
+
 	return PostApiRecipes201JSONResponse{
 		RecipeId: recipeID,
 	}, nil
@@ -230,9 +333,21 @@ func (Server) GetApiRecipesRecipeIDPublic(ctx context.Context,
 		}, nil
 	}
 
+	// Convert step temperatures to the requested unit
+	if request.Params.TemperatureUnit != nil {
+		convertStepTemperatures(recipe.Steps, *request.Params.TemperatureUnit)
+	}
+
+	// Flag requested allergies
+	var allergies []string
+	if request.Params.Allergies != nil {
+		allergies = allergenNames(*request.Params.Allergies)
+	}
+
 	return GetApiRecipesRecipeIDPublic200JSONResponse{
-		Owner:  owner,
-		Recipe: recipe,
+		Owner:           owner,
+		Recipe:          recipe,
+		AllergyWarnings: allergyWarnings(recipe.Ingredients, allergies),
 	}, nil
 }
 
@@ -314,9 +429,49 @@ func (Server) GetApiRecipesRecipeID(ctx context.Context,
 		}, nil
 	}
 
+	// Convert step temperatures to the requested or preferred unit
+	unit := request.Params.TemperatureUnit
+	if unit == nil && hasStepTemperatures(recipe.Steps) {
+		env.Logger.DebugContext(ctx, "getting temperature unit preference")
+		preferred, err := env.Database.GetUserTemperatureUnit(ctx, userID)
+		if err != nil {
+			env.Logger.ErrorContext(ctx, "failed to get temperature unit preference", slog.Any("error", err))
+			return GetApiRecipesRecipeID500JSONResponse{
+				Code:    apiError.InternalServerError.String(),
+				Status:  apiError.InternalServerError.StatusCode(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+		if preferred.Valid {
+			preferredUnit := TemperatureUnit(preferred.TemperatureUnit)
+			unit = &preferredUnit
+		}
+	}
+	if unit != nil {
+		convertStepTemperatures(recipe.Steps, *unit)
+	}
+
+	// Flag the user's allergies
+	var allergies []string
+	if len(recipe.Ingredients) > 0 {
+		env.Logger.DebugContext(ctx, "getting allergies")
+		allergies, err = env.Database.GetUserAllergies(ctx, userID)
+		if err != nil {
+			env.Logger.ErrorContext(ctx, "failed to get allergies", slog.Any("error", err))
+			return GetApiRecipesRecipeID500JSONResponse{
+				Code:    apiError.InternalServerError.String(),
+				Status:  apiError.InternalServerError.StatusCode(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+	}
+
 	return GetApiRecipesRecipeID200JSONResponse{
-		Owner:  owner,
-		Recipe: recipe,
+		Owner:           owner,
+		Recipe:          recipe,
+		AllergyWarnings: allergyWarnings(recipe.Ingredients, allergies),
 	}, nil
 }
 
@@ -444,6 +599,15 @@ func (Server) DeleteApiRecipesRecipeID(
 		}, nil
 	}
 
+	// Record activity. The title is kept, as the recipe can no longer be
+	// looked up.
+	recordRecipeActivity(ctx, env, userID, audit.ActionDeleteRecipe, request.RecipeID, map[string]any{
+		"title": recipe.Title,
+	})
+	if recipe.Published {
+		federateRecipe(ctx, env, userID, request.RecipeID, false)
+	}
+
 	return DeleteApiRecipesRecipeID204Response{}, nil
 }
 
@@ -657,7 +821,8 @@ func (Server) PostApiRecipesRecipeIDIngredientsIngredientIDImage(ctx context.Con
 			ErrorId: requestID,
 		}, nil
 	}
-	imageFile, err := requestForm.File["image"][0].Open()
+	imageHeader := requestForm.File["image"][0]
+	imageFile, err := imageHeader.Open()
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to open image", slog.Any("error", err))
 		return PostApiRecipesRecipeIDIngredientsIngredientIDImage400JSONResponse{
@@ -668,7 +833,7 @@ func (Server) PostApiRecipesRecipeIDIngredientsIngredientIDImage(ctx context.Con
 		}, nil
 	}
 	defer func() { _ = imageFile.Close() }()
-	file, err := form.ReadImage(imageFile)
+	file, err := form.ReadImage(imageFile, imageHeader.Filename)
 	if errors.Is(err, form.ErrUnsupportedMimeType) {
 		env.Logger.ErrorContext(ctx, "unsupported format", slog.Any("error", err))
 		return PostApiRecipesRecipeIDIngredientsIngredientIDImage422JSONResponse{
@@ -678,6 +843,15 @@ func (Server) PostApiRecipesRecipeIDIngredientsIngredientIDImage(ctx context.Con
 			ErrorId: requestID,
 		}, nil
 	}
+	if errors.Is(err, form.ErrExtensionMismatch) {
+		env.Logger.ErrorContext(ctx, "image extension does not match content", slog.Any("error", err))
+		return PostApiRecipesRecipeIDIngredientsIngredientIDImage422JSONResponse{
+			Status:  apiError.UnsupportedImageFormat.StatusCode(),
+			Code:    apiError.UnsupportedImageFormat.String(),
+			Message: "image extension does not match its contents",
+			ErrorId: requestID,
+		}, nil
+	}
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to read image", slog.Any("error", err))
 		return PostApiRecipesRecipeIDIngredientsIngredientIDImage400JSONResponse{
@@ -831,24 +1005,19 @@ func (Server) DeleteApiRecipesRecipeIDIngredientsIngredientIDImage(ctx context.C
 		}, nil
 	}
 
-	// Delete from database
+	// Delete from database and hold image for undo
 	env.Logger.DebugContext(ctx, "deleting image from database")
-	err = env.Database.DeleteRecipeIngredientImageKey(ctx, request.IngredientID)
-	if err != nil {
-		env.Logger.ErrorContext(ctx, "failed to delete image from database", slog.Any("error", err))
-		return DeleteApiRecipesRecipeIDIngredientsIngredientIDImage500JSONResponse{
-			Status:  apiError.InternalServerError.StatusCode(),
-			Code:    apiError.InternalServerError.String(),
-			Message: "Internal Server Error",
-			ErrorId: requestID,
-		}, nil
-	}
-
-	// Delete file from system
-	env.Logger.DebugContext(ctx, "deleting current image")
-	err = env.FileStore.DeleteKey(oldImage.String)
+	var undoToken undo.Token
+	err = env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		if err := tx.DeleteRecipeIngredientImageKey(ctx, request.IngredientID); err != nil {
+			return fmt.Errorf("deleting image key: %w", err)
+		}
+		env.Logger.DebugContext(ctx, "holding image for undo")
+		undoToken, err = undo.HoldImage(ctx, env.WithDatabase(tx), userID, undo.KindIngredientImage, request.RecipeID, request.IngredientID, oldImage.String)
+		return err
+	})
 	if err != nil {
-		env.Logger.ErrorContext(ctx, "failed to delete old image", slog.Any("error", err))
+		env.Logger.ErrorContext(ctx, "failed to delete image", slog.Any("error", err))
 		return DeleteApiRecipesRecipeIDIngredientsIngredientIDImage500JSONResponse{
 			Status:  apiError.InternalServerError.StatusCode(),
 			Code:    apiError.InternalServerError.String(),
@@ -857,7 +1026,10 @@ func (Server) DeleteApiRecipesRecipeIDIngredientsIngredientIDImage(ctx context.C
 		}, nil
 	}
 
-	return DeleteApiRecipesRecipeIDIngredientsIngredientIDImage204Response{}, nil
+	return DeleteApiRecipesRecipeIDIngredientsIngredientIDImage200JSONResponse{
+		UndoToken:     undoToken.Value,
+		UndoExpiresAt: undoToken.ExpiresAt,
+	}, nil
 }
 
 func (Server) PostApiRecipesRecipeIDSteps(ctx context.Context,
@@ -993,6 +1165,31 @@ func (Server) PatchApiRecipesRecipeIDStepsStepID(ctx context.Context,
 		updateParams.StepNumber.Int32 = *request.Body.StepNumber
 		updateParams.StepNumber.Valid = true
 	}
+	// Temperature - nullable, validated against the allowed range
+	if request.Body.Temperature.IsSpecified() {
+		updateParams.UpdateTemperature.Bool = true
+		updateParams.UpdateTemperature.Valid = true
+		if !request.Body.Temperature.IsNull() {
+			temp := request.Body.Temperature.MustGet()
+			err := temperature.Temperature{
+				Value: float64(temp.Value),
+				Unit:  temperature.Unit(temp.Unit),
+			}.Validate()
+			if err != nil {
+				env.Logger.ErrorContext(ctx, "invalid step temperature", slog.Any("error", err))
+				return PatchApiRecipesRecipeIDStepsStepID422JSONResponse{
+					Status:  apiError.UnprocessibleEntity.StatusCode(),
+					Code:    apiError.UnprocessibleEntity.String(),
+					Message: err.Error(),
+					ErrorId: requestID,
+				}, nil
+			}
+			updateParams.TemperatureValue.Float32 = temp.Value
+			updateParams.TemperatureValue.Valid = true
+			updateParams.TemperatureUnit.TemperatureUnit = database.TemperatureUnit(temp.Unit)
+			updateParams.TemperatureUnit.Valid = true
+		}
+	}
 	step, err := env.Database.UpdateRecipeStep(ctx, updateParams)
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to update recipe step", slog.Any("error", err))
@@ -1017,6 +1214,7 @@ func (Server) PatchApiRecipesRecipeIDStepsStepID(ctx context.Context,
 		url := env.FileStore.FileURL(step.ImageKey.String)
 		res.ImageUrl = &url
 	}
+	res.Temperature = stepTemperature(step.TemperatureValue, step.TemperatureUnit)
 	return res, nil
 }
 
@@ -1077,7 +1275,8 @@ func (Server) PostApiRecipesRecipeIDStepsStepIDImage(ctx context.Context,
 			ErrorId: requestID,
 		}, nil
 	}
-	imageFile, err := requestForm.File["image"][0].Open()
+	imageHeader := requestForm.File["image"][0]
+	imageFile, err := imageHeader.Open()
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to open image", slog.Any("error", err))
 		return PostApiRecipesRecipeIDStepsStepIDImage400JSONResponse{
@@ -1088,7 +1287,7 @@ func (Server) PostApiRecipesRecipeIDStepsStepIDImage(ctx context.Context,
 		}, nil
 	}
 	defer func() { _ = imageFile.Close() }()
-	file, err := form.ReadImage(imageFile)
+	file, err := form.ReadImage(imageFile, imageHeader.Filename)
 	if errors.Is(err, form.ErrUnsupportedMimeType) {
 		env.Logger.ErrorContext(ctx, "unsupported format", slog.Any("error", err))
 		return PostApiRecipesRecipeIDStepsStepIDImage422JSONResponse{
@@ -1098,6 +1297,15 @@ func (Server) PostApiRecipesRecipeIDStepsStepIDImage(ctx context.Context,
 			ErrorId: requestID,
 		}, nil
 	}
+	if errors.Is(err, form.ErrExtensionMismatch) {
+		env.Logger.ErrorContext(ctx, "image extension does not match content", slog.Any("error", err))
+		return PostApiRecipesRecipeIDStepsStepIDImage422JSONResponse{
+			Status:  apiError.UnsupportedImageFormat.StatusCode(),
+			Code:    apiError.UnsupportedImageFormat.String(),
+			Message: "image extension does not match its contents",
+			ErrorId: requestID,
+		}, nil
+	}
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to read image", slog.Any("error", err))
 		return PostApiRecipesRecipeIDStepsStepIDImage400JSONResponse{
@@ -1252,24 +1460,19 @@ func (Server) DeleteApiRecipesRecipeIDStepsStepIDImage(ctx context.Context,
 		}, nil
 	}
 
-	// Delete from database
+	// Delete from database and hold image for undo
 	env.Logger.DebugContext(ctx, "deleting image from database")
-	err = env.Database.DeleteRecipeStepImageKey(ctx, request.StepID)
-	if err != nil {
-		env.Logger.ErrorContext(ctx, "failed to delete image from database", slog.Any("error", err))
-		return DeleteApiRecipesRecipeIDStepsStepIDImage500JSONResponse{
-			Status:  apiError.InternalServerError.StatusCode(),
-			Code:    apiError.InternalServerError.String(),
-			Message: "Internal Server Error",
-			ErrorId: requestID,
-		}, nil
-	}
-
-	// Delete file from system
-	env.Logger.DebugContext(ctx, "deleting current image")
-	err = env.FileStore.DeleteKey(oldImage.String)
+	var undoToken undo.Token
+	err = env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		if err := tx.DeleteRecipeStepImageKey(ctx, request.StepID); err != nil {
+			return fmt.Errorf("deleting image key: %w", err)
+		}
+		env.Logger.DebugContext(ctx, "holding image for undo")
+		undoToken, err = undo.HoldImage(ctx, env.WithDatabase(tx), userID, undo.KindStepImage, request.RecipeID, request.StepID, oldImage.String)
+		return err
+	})
 	if err != nil {
-		env.Logger.ErrorContext(ctx, "failed to delete old image", slog.Any("error", err))
+		env.Logger.ErrorContext(ctx, "failed to delete image", slog.Any("error", err))
 		return DeleteApiRecipesRecipeIDStepsStepIDImage500JSONResponse{
 			Status:  apiError.InternalServerError.StatusCode(),
 			Code:    apiError.InternalServerError.String(),
@@ -1278,7 +1481,10 @@ func (Server) DeleteApiRecipesRecipeIDStepsStepIDImage(ctx context.Context,
 		}, nil
 	}
 
-	return DeleteApiRecipesRecipeIDStepsStepIDImage204Response{}, nil
+	return DeleteApiRecipesRecipeIDStepsStepIDImage200JSONResponse{
+		UndoToken:     undoToken.Value,
+		UndoExpiresAt: undoToken.ExpiresAt,
+	}, nil
 }
 
 func (Server) DeleteApiRecipesRecipeIDIngredientsIngredientID(ctx context.Context,
@@ -1327,38 +1533,19 @@ func (Server) DeleteApiRecipesRecipeIDIngredientsIngredientID(ctx context.Contex
 		}, nil
 	}
 
-	// Get image key
-	env.Logger.DebugContext(ctx, "getting image key")
-	imageKey, err := env.Database.GetRecipeIngredientImageKey(ctx, request.IngredientID)
-	if err != nil {
-		env.Logger.ErrorContext(ctx, "failed to get image key", slog.Any("error", err))
-		return DeleteApiRecipesRecipeIDIngredientsIngredientID500JSONResponse{
-			Status:  apiError.InternalServerError.StatusCode(),
-			Code:    apiError.InternalServerError.String(),
-			Message: "Internal Server Error",
-			ErrorId: requestID,
-		}, nil
-	}
-
-	if imageKey.Valid {
-		env.Logger.DebugContext(ctx, "deleting image")
-		err = env.FileStore.DeleteKey(imageKey.String)
-		if errors.Is(err, fileserver.ErrNotExist) {
-			env.Logger.WarnContext(ctx, "image not found", slog.Any("error", err))
-		} else if err != nil {
-			env.Logger.ErrorContext(ctx, "failed to delete image")
-			return DeleteApiRecipesRecipeIDIngredientsIngredientID500JSONResponse{
-				Status:  apiError.InternalServerError.StatusCode(),
-				Code:    apiError.InternalServerError.String(),
-				Message: "Internal Server Error",
-				ErrorId: requestID,
-			}, nil
-		}
-	}
-
-	// Delete ingredient from database
+	// Delete ingredient and hold it for undo. Its image is kept until the
+	// token expires.
 	env.Logger.DebugContext(ctx, "deleting ingredient")
-	err = env.Database.DeleteRecipeIngredient(ctx, request.IngredientID)
+	var undoToken undo.Token
+	err = env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		deleted, err := tx.DeleteRecipeIngredient(ctx, request.IngredientID)
+		if err != nil {
+			return fmt.Errorf("deleting ingredient: %w", err)
+		}
+		env.Logger.DebugContext(ctx, "holding ingredient for undo")
+		undoToken, err = undo.HoldIngredient(ctx, env.WithDatabase(tx), userID, deleted)
+		return err
+	})
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to delete ingredient", slog.Any("error", err))
 		return DeleteApiRecipesRecipeIDIngredientsIngredientID500JSONResponse{
@@ -1369,7 +1556,10 @@ func (Server) DeleteApiRecipesRecipeIDIngredientsIngredientID(ctx context.Contex
 		}, nil
 	}
 
-	return DeleteApiRecipesRecipeIDIngredientsIngredientID204Response{}, nil
+	return DeleteApiRecipesRecipeIDIngredientsIngredientID200JSONResponse{
+		UndoToken:     undoToken.Value,
+		UndoExpiresAt: undoToken.ExpiresAt,
+	}, nil
 }
 
 func (Server) GetApiRecipesPublic(ctx context.Context,
@@ -1379,9 +1569,22 @@ func (Server) GetApiRecipesPublic(ctx context.Context,
 	env := env.EnvFromCtx(ctx)
 	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
 
-	// TODO: add pagination
+	limit := pageLimit(env, request.Params.Limit)
+	var offset int32
+	if request.Params.Offset != nil {
+		offset = *request.Params.Offset
+	}
+	var allergenPatterns []string
+	if request.Params.ExcludeAllergens != nil {
+		allergenPatterns = tagging.AllergenPatterns(allergenNames(*request.Params.ExcludeAllergens))
+	}
+
 	env.Logger.DebugContext(ctx, "getting public recipes")
-	rows, err := env.Database.GetPublicRecipes(ctx)
+	rows, err := env.Database.GetPublicRecipes(ctx, database.GetPublicRecipesParams{
+		AllergenPatterns: allergenPatterns,
+		Limit:            limit + 1,
+		Offset:           offset,
+	})
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to get public recipes", slog.Any("error", err))
 		return GetApiRecipesPublic500JSONResponse{
@@ -1392,9 +1595,23 @@ func (Server) GetApiRecipesPublic(ctx context.Context,
 		}, nil
 	}
 
+	env.Logger.DebugContext(ctx, "counting public recipes")
+	total, err := env.Database.GetPublicRecipeCount(ctx, allergenPatterns)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to count public recipes", slog.Any("error", err))
+		return GetApiRecipesPublic500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
 	// Build response
+	rows, page := newPage(rows, limit, total)
 	res := GetApiRecipesPublic200JSONResponse{
 		Recipes: make([]RecipeAndOwner, len(rows)),
+		Page:    page,
 	}
 	for idx, recipe := range rows {
 		r := Recipe{
@@ -1489,38 +1706,19 @@ func (Server) DeleteApiRecipesRecipeIDStepsStepID(ctx context.Context,
 		}, nil
 	}
 
-	// Get image key
-	env.Logger.DebugContext(ctx, "getting image key")
-	imageKey, err := env.Database.GetRecipeStepImageKey(ctx, request.StepID)
-	if err != nil {
-		env.Logger.ErrorContext(ctx, "failed to get image key")
-		return DeleteApiRecipesRecipeIDStepsStepID500JSONResponse{
-			Status:  apiError.InternalServerError.StatusCode(),
-			Code:    apiError.InternalServerError.String(),
-			Message: "Internal Server Error",
-			ErrorId: requestID,
-		}, nil
-	}
-
-	if imageKey.Valid {
-		env.Logger.DebugContext(ctx, "deleting image")
-		err = env.FileStore.DeleteKey(imageKey.String)
-		if errors.Is(err, fileserver.ErrNotExist) {
-			env.Logger.WarnContext(ctx, "image not found", slog.Any("error", err))
-		} else if err != nil {
-			env.Logger.ErrorContext(ctx, "failed to delete image")
-			return DeleteApiRecipesRecipeIDStepsStepID500JSONResponse{
-				Status:  apiError.InternalServerError.StatusCode(),
-				Code:    apiError.InternalServerError.String(),
-				Message: "Internal Server Error",
-				ErrorId: requestID,
-			}, nil
-		}
-	}
-
-	// Delete step
+	// Delete step and hold it for undo. Its image is kept until the token
+	// expires.
 	env.Logger.DebugContext(ctx, "deleting recipe step")
-	err = env.Database.DeleteRecipeStep(ctx, request.StepID)
+	var undoToken undo.Token
+	err = env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		deleted, err := tx.DeleteRecipeStep(ctx, request.StepID)
+		if err != nil {
+			return fmt.Errorf("deleting step: %w", err)
+		}
+		env.Logger.DebugContext(ctx, "holding step for undo")
+		undoToken, err = undo.HoldStep(ctx, env.WithDatabase(tx), userID, deleted)
+		return err
+	})
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to delete step", slog.Any("error", err))
 		return DeleteApiRecipesRecipeIDStepsStepID500JSONResponse{
@@ -1531,7 +1729,10 @@ func (Server) DeleteApiRecipesRecipeIDStepsStepID(ctx context.Context,
 		}, nil
 	}
 
-	return DeleteApiRecipesRecipeIDStepsStepID204Response{}, nil
+	return DeleteApiRecipesRecipeIDStepsStepID200JSONResponse{
+		UndoToken:     undoToken.Value,
+		UndoExpiresAt: undoToken.ExpiresAt,
+	}, nil
 }
 
 func (Server) GetApiRecipes(ctx context.Context,
@@ -1551,9 +1752,19 @@ func (Server) GetApiRecipes(ctx context.Context,
 		}, nil
 	}
 
+	limit := pageLimit(env, request.Params.Limit)
+	var offset int32
+	if request.Params.Offset != nil {
+		offset = *request.Params.Offset
+	}
+
 	// Get user recipes
 	env.Logger.DebugContext(ctx, "getting user recipes")
-	rows, err := env.Database.GetRecipesByOwner(ctx, userID)
+	rows, err := env.Database.GetRecipesByOwner(ctx, database.GetRecipesByOwnerParams{
+		UserID: userID,
+		Limit:  pgtype.Int4{Int32: limit + 1, Valid: true},
+		Offset: offset,
+	})
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to get user recipes", slog.Any("error", err))
 		return GetApiRecipes500JSONResponse{
@@ -1564,10 +1775,25 @@ func (Server) GetApiRecipes(ctx context.Context,
 		}, nil
 	}
 
+	// Count user recipes
+	env.Logger.DebugContext(ctx, "counting user recipes")
+	total, err := env.Database.GetUserRecipeCount(ctx, pgtype.Int8{Int64: userID, Valid: true})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to count user recipes", slog.Any("error", err))
+		return GetApiRecipes500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
 	// Build response
 	env.Logger.DebugContext(ctx, "building response")
+	rows, page := newPage(rows, limit, total)
 	res := GetApiRecipes200JSONResponse{
 		Recipes: make([]RecipeAndOwner, len(rows)),
+		Page:    page,
 	}
 	for idx, recipe := range rows {
 		r := Recipe{
@@ -1608,14 +1834,36 @@ func (Server) GetApiRecipes(ctx context.Context,
 		}
 
 		res.Recipes[idx] = RecipeAndOwner{
-			Recipe: &r,
-			Owner:  &ro,
+			Recipe:       &r,
+			Owner:        &ro,
+			Completeness: recipeCompleteness(recipe),
 		}
 	}
 
 	return res, nil
 }
 
+// recipeCompleteness scores a recipe of the owner's listing.
+func recipeCompleteness(recipe database.GetRecipesByOwnerRow) *Completeness {
+	result := completeness.Score(completeness.Recipe{
+		HasCoverImage: recipe.ImageKey.Valid,
+		HasCookTime:   recipe.CookTimeAmount.Valid && recipe.CookTimeUnit.Valid,
+		HasPrepTime:   recipe.PrepTimeAmount.Valid && recipe.PrepTimeUnit.Valid,
+		HasServings:   recipe.Servings.Valid,
+		Tags:          int(recipe.TagCount),
+		Steps:         int(recipe.StepCount),
+		BlankSteps:    int(recipe.BlankStepCount),
+	})
+	c := Completeness{
+		Score:   result.Score,
+		Missing: make([]CompletenessItem, 0, len(result.Missing)),
+	}
+	for _, item := range result.Missing {
+		c.Missing = append(c.Missing, CompletenessItem(item))
+	}
+	return &c
+}
+
 func (Server) PatchApiRecipesRecipeID(ctx context.Context,
 	request PatchApiRecipesRecipeIDRequestObject) (
 	PatchApiRecipesRecipeIDResponseObject, error,
@@ -1756,6 +2004,16 @@ func (Server) PatchApiRecipesRecipeID(ctx context.Context,
 		}, nil
 	}
 
+	// Record activity
+	if request.Body.Published != nil {
+		action := audit.ActionUnpublishRecipe
+		if *request.Body.Published {
+			action = audit.ActionPublishRecipe
+		}
+		recordRecipeActivity(ctx, env, userID, action, rec.ID, nil)
+		federateRecipe(ctx, env, userID, rec.ID, rec.Published)
+	}
+
 	resp := PatchApiRecipesRecipeID200JSONResponse{
 		Id:        rec.ID,
 		Published: rec.Published,
@@ -1851,7 +2109,8 @@ func (Server) PostApiRecipesRecipeIDImage(ctx context.Context,
 			ErrorId: requestID,
 		}, nil
 	}
-	imageFile, err := requestForm.File["image"][0].Open()
+	imageHeader := requestForm.File["image"][0]
+	imageFile, err := imageHeader.Open()
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to open image", slog.Any("error", err))
 		return PostApiRecipesRecipeIDImage400JSONResponse{
@@ -1862,7 +2121,7 @@ func (Server) PostApiRecipesRecipeIDImage(ctx context.Context,
 		}, nil
 	}
 	defer func() { _ = imageFile.Close() }()
-	file, err := form.ReadImage(imageFile)
+	file, err := form.ReadImage(imageFile, imageHeader.Filename)
 	if errors.Is(err, form.ErrUnsupportedMimeType) {
 		env.Logger.ErrorContext(ctx, "unsupported format", slog.Any("error", err))
 		return PostApiRecipesRecipeIDImage422JSONResponse{
@@ -1872,6 +2131,15 @@ func (Server) PostApiRecipesRecipeIDImage(ctx context.Context,
 			ErrorId: requestID,
 		}, nil
 	}
+	if errors.Is(err, form.ErrExtensionMismatch) {
+		env.Logger.ErrorContext(ctx, "image extension does not match content", slog.Any("error", err))
+		return PostApiRecipesRecipeIDImage422JSONResponse{
+			Status:  apiError.UnsupportedImageFormat.StatusCode(),
+			Code:    apiError.UnsupportedImageFormat.String(),
+			Message: "image extension does not match its contents",
+			ErrorId: requestID,
+		}, nil
+	}
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to read image", slog.Any("error", err))
 		return PostApiRecipesRecipeIDImage400JSONResponse{
@@ -1993,7 +2261,7 @@ func (Server) DeleteApiRecipesRecipeIDImage(ctx context.Context,
 	userID, err := token.UserIDFromCtx(ctx)
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
-		return DeleteApiRecipesRecipeIDImage404JSONResponse{
+		return DeleteApiRecipesRecipeIDImage400JSONResponse{
 			Status:  apiError.BadRequest.StatusCode(),
 			Code:    apiError.BadRequest.String(),
 			Message: "missing user id",
@@ -2050,27 +2318,22 @@ func (Server) DeleteApiRecipesRecipeIDImage(ctx context.Context,
 		}, nil
 	}
 
-	// Delete from database
+	// Delete from database and hold image for undo
 	env.Logger.DebugContext(ctx, "deleting image from database")
-	err = env.Database.UpdateRecipeCoverImage(ctx, database.UpdateRecipeCoverImageParams{
-		ID:       request.RecipeID,
-		ImageKey: pgtype.Text{Valid: false},
+	var undoToken undo.Token
+	err = env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		if err := tx.UpdateRecipeCoverImage(ctx, database.UpdateRecipeCoverImageParams{
+			ID:       request.RecipeID,
+			ImageKey: pgtype.Text{Valid: false},
+		}); err != nil {
+			return fmt.Errorf("deleting image key: %w", err)
+		}
+		env.Logger.DebugContext(ctx, "holding image for undo")
+		undoToken, err = undo.HoldImage(ctx, env.WithDatabase(tx), userID, undo.KindRecipeImage, request.RecipeID, 0, oldImage.String)
+		return err
 	})
 	if err != nil {
-		env.Logger.ErrorContext(ctx, "failed to delete image from database", slog.Any("error", err))
-		return DeleteApiRecipesRecipeIDImage500JSONResponse{
-			Status:  apiError.InternalServerError.StatusCode(),
-			Code:    apiError.InternalServerError.String(),
-			Message: "Internal Server Error",
-			ErrorId: requestID,
-		}, nil
-	}
-
-	// Delete file from system
-	env.Logger.DebugContext(ctx, "deleting current image")
-	err = env.FileStore.DeleteKey(oldImage.String)
-	if err != nil {
-		env.Logger.ErrorContext(ctx, "failed to delete old image", slog.Any("error", err))
+		env.Logger.ErrorContext(ctx, "failed to delete image", slog.Any("error", err))
 		return DeleteApiRecipesRecipeIDImage500JSONResponse{
 			Status:  apiError.InternalServerError.StatusCode(),
 			Code:    apiError.InternalServerError.String(),
@@ -2079,5 +2342,8 @@ func (Server) DeleteApiRecipesRecipeIDImage(ctx context.Context,
 		}, nil
 	}
 
-	return DeleteApiRecipesRecipeIDImage204Response{}, nil
+	return DeleteApiRecipesRecipeIDImage200JSONResponse{
+		UndoToken:     undoToken.Value,
+		UndoExpiresAt: undoToken.ExpiresAt,
+	}, nil
 }
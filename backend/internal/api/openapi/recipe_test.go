@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"mime/multipart"
+	"reflect"
 	"testing"
 	"time"
 
@@ -16,9 +17,9 @@ import (
 	apiError "github.com/matt-dz/wecook/internal/api/error"
 	"github.com/matt-dz/wecook/internal/api/requestid"
 	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/config"
 	"github.com/matt-dz/wecook/internal/database"
 	"github.com/matt-dz/wecook/internal/env"
-	"github.com/matt-dz/wecook/internal/fileserver"
 	"github.com/matt-dz/wecook/internal/filestore"
 	"github.com/matt-dz/wecook/internal/log"
 )
@@ -50,6 +51,15 @@ func TestPostApiRecipes(t *testing.T) {
 				mockDB.EXPECT().
 					CreateRecipe(gomock.Any(), gomock.Any()).
 					Return(int64(456), nil)
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), database.CreateAuditEventParams{
+						ActorID:    pgtype.Int8{Int64: 123, Valid: true},
+						Action:     "recipe.create",
+						TargetType: "recipe",
+						TargetID:   pgtype.Int8{Int64: 456, Valid: true},
+						Metadata:   []byte("{}"),
+					}).
+					Return(int64(1), nil)
 			},
 			wantStatus: 201,
 			wantCode:   "",
@@ -288,6 +298,10 @@ func TestGetApiRecipesRecipeID(t *testing.T) {
 						},
 					}, nil)
 
+				mockDB.EXPECT().
+					GetUserAllergies(gomock.Any(), int64(456)).
+					Return([]string{"wheat", "peanuts"}, nil)
+
 				// Expect FileURL calls for recipe and step images
 				mockFS.EXPECT().FileURL("recipe.jpg").Return("http://localhost:8080/recipe.jpg")
 				mockFS.EXPECT().FileURL("step1.jpg").Return("http://localhost:8080/step1.jpg")
@@ -328,6 +342,10 @@ func TestGetApiRecipesRecipeID(t *testing.T) {
 				if v.Recipe.Servings == nil || *v.Recipe.Servings != 4.0 {
 					t.Errorf("expected servings 4.0, got %v", v.Recipe.Servings)
 				}
+				wantWarnings := []AllergyWarning{{Allergen: "wheat", Message: "contains wheat", IngredientIds: []int64{1}}}
+				if !reflect.DeepEqual(v.AllergyWarnings, wantWarnings) {
+					t.Errorf("expected allergy warnings %+v, got %+v", wantWarnings, v.AllergyWarnings)
+				}
 			},
 		},
 		{
@@ -532,6 +550,135 @@ func TestGetApiRecipesRecipeID(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "step temperatures converted to preferred unit",
+			request: GetApiRecipesRecipeIDRequestObject{
+				RecipeID: 123,
+			},
+			userID:     456,
+			injectUser: true,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+
+				mockDB.EXPECT().
+					GetRecipeAndOwner(gomock.Any(), int64(123)).
+					Return(database.GetRecipeAndOwnerRow{
+						ID:     123,
+						UserID: pgtype.Int8{Int64: 456, Valid: true},
+						Title:  "Test Recipe",
+						ID_2:   456,
+					}, nil)
+
+				mockDB.EXPECT().
+					GetRecipeSteps(gomock.Any(), int64(123)).
+					Return([]database.RecipeStep{
+						{
+							ID:               1,
+							RecipeID:         123,
+							StepNumber:       1,
+							Instruction:      pgtype.Text{String: "Preheat the oven", Valid: true},
+							TemperatureValue: pgtype.Float4{Float32: 350, Valid: true},
+							TemperatureUnit: database.NullTemperatureUnit{
+								TemperatureUnit: database.TemperatureUnitF,
+								Valid:           true,
+							},
+						},
+						{
+							ID:          2,
+							RecipeID:    123,
+							StepNumber:  2,
+							Instruction: pgtype.Text{String: "Mix ingredients", Valid: true},
+						},
+					}, nil)
+
+				mockDB.EXPECT().
+					GetRecipeIngredients(gomock.Any(), int64(123)).
+					Return([]database.RecipeIngredient{}, nil)
+
+				mockDB.EXPECT().
+					GetUserTemperatureUnit(gomock.Any(), int64(456)).
+					Return(database.NullTemperatureUnit{
+						TemperatureUnit: database.TemperatureUnitC,
+						Valid:           true,
+					}, nil)
+			},
+			wantStatus: 200,
+			wantError:  false,
+			validate: func(t *testing.T, resp GetApiRecipesRecipeIDResponseObject) {
+				v, ok := resp.(GetApiRecipesRecipeID200JSONResponse)
+				if !ok {
+					t.Errorf("expected GetApiRecipesRecipeID200JSONResponse, got %T", resp)
+					return
+				}
+				if len(v.Recipe.Steps) != 2 {
+					t.Fatalf("expected 2 steps, got %d", len(v.Recipe.Steps))
+				}
+				if got := v.Recipe.Steps[0].Temperature; got == nil || *got != (Temperature{Value: 177, Unit: C}) {
+					t.Errorf("expected temperature 177C, got %+v", got)
+				}
+				if v.Recipe.Steps[1].Temperature != nil {
+					t.Errorf("expected no temperature on step 2, got %+v", v.Recipe.Steps[1].Temperature)
+				}
+			},
+		},
+		{
+			name: "temperature unit query overrides preference",
+			request: GetApiRecipesRecipeIDRequestObject{
+				RecipeID: 123,
+				Params: GetApiRecipesRecipeIDParams{
+					TemperatureUnit: temperatureUnitPtr(F),
+				},
+			},
+			userID:     456,
+			injectUser: true,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+
+				mockDB.EXPECT().
+					GetRecipeAndOwner(gomock.Any(), int64(123)).
+					Return(database.GetRecipeAndOwnerRow{
+						ID:     123,
+						UserID: pgtype.Int8{Int64: 456, Valid: true},
+						Title:  "Test Recipe",
+						ID_2:   456,
+					}, nil)
+
+				mockDB.EXPECT().
+					GetRecipeSteps(gomock.Any(), int64(123)).
+					Return([]database.RecipeStep{
+						{
+							ID:               1,
+							RecipeID:         123,
+							StepNumber:       1,
+							TemperatureValue: pgtype.Float4{Float32: 200, Valid: true},
+							TemperatureUnit: database.NullTemperatureUnit{
+								TemperatureUnit: database.TemperatureUnitC,
+								Valid:           true,
+							},
+						},
+					}, nil)
+
+				mockDB.EXPECT().
+					GetRecipeIngredients(gomock.Any(), int64(123)).
+					Return([]database.RecipeIngredient{}, nil)
+			},
+			wantStatus: 200,
+			wantError:  false,
+			validate: func(t *testing.T, resp GetApiRecipesRecipeIDResponseObject) {
+				v, ok := resp.(GetApiRecipesRecipeID200JSONResponse)
+				if !ok {
+					t.Errorf("expected GetApiRecipesRecipeID200JSONResponse, got %T", resp)
+					return
+				}
+				if got := v.Recipe.Steps[0].Temperature; got == nil || *got != (Temperature{Value: 392, Unit: F}) {
+					t.Errorf("expected temperature 392F, got %+v", got)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -612,7 +759,10 @@ func TestGetApiRecipes(t *testing.T) {
 			injectUser: true,
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
-					GetRecipesByOwner(gomock.Any(), int64(456)).
+					GetRecipesByOwner(gomock.Any(), database.GetRecipesByOwnerParams{
+						UserID: 456,
+						Limit:  pgtype.Int4{Int32: config.DefaultPageLimit + 1, Valid: true},
+					}).
 					Return(nil, errors.New("database connection failed"))
 			},
 			wantStatus: 500,
@@ -635,8 +785,14 @@ func TestGetApiRecipes(t *testing.T) {
 			injectUser: true,
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
-					GetRecipesByOwner(gomock.Any(), int64(456)).
+					GetRecipesByOwner(gomock.Any(), database.GetRecipesByOwnerParams{
+						UserID: 456,
+						Limit:  pgtype.Int4{Int32: config.DefaultPageLimit + 1, Valid: true},
+					}).
 					Return([]database.GetRecipesByOwnerRow{}, nil)
+				mockDB.EXPECT().
+					GetUserRecipeCount(gomock.Any(), pgtype.Int8{Int64: 456, Valid: true}).
+					Return(int64(2), nil)
 			},
 			wantStatus: 200,
 			wantError:  false,
@@ -657,7 +813,10 @@ func TestGetApiRecipes(t *testing.T) {
 			injectUser: true,
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
-					GetRecipesByOwner(gomock.Any(), int64(456)).
+					GetRecipesByOwner(gomock.Any(), database.GetRecipesByOwnerParams{
+						UserID: 456,
+						Limit:  pgtype.Int4{Int32: config.DefaultPageLimit + 1, Valid: true},
+					}).
 					Return([]database.GetRecipesByOwnerRow{
 						{
 							UserID:         pgtype.Int8{Int64: 456, Valid: true},
@@ -675,6 +834,8 @@ func TestGetApiRecipes(t *testing.T) {
 							Servings:       pgtype.Float4{Float32: 4.0, Valid: true},
 							FirstName:      "John",
 							LastName:       "Doe",
+							TagCount:       2,
+							StepCount:      3,
 						},
 						{
 							UserID:         pgtype.Int8{Int64: 456, Valid: true},
@@ -692,8 +853,13 @@ func TestGetApiRecipes(t *testing.T) {
 							Servings:       pgtype.Float4{Float32: 0, Valid: false},
 							FirstName:      "John",
 							LastName:       "Doe",
+							StepCount:      2,
+							BlankStepCount: 1,
 						},
 					}, nil)
+				mockDB.EXPECT().
+					GetUserRecipeCount(gomock.Any(), pgtype.Int8{Int64: 456, Valid: true}).
+					Return(int64(2), nil)
 
 				mockFS.EXPECT().
 					FileURL("recipe1.jpg").
@@ -744,6 +910,10 @@ func TestGetApiRecipes(t *testing.T) {
 				if recipe1.Owner.LastName != "Doe" {
 					t.Errorf("expected owner last name 'Doe', got %s", recipe1.Owner.LastName)
 				}
+				if want := (&Completeness{Score: 100, Missing: []CompletenessItem{}}); !reflect.DeepEqual(
+					recipe1.Completeness, want) {
+					t.Errorf("expected completeness %+v, got %+v", want, recipe1.Completeness)
+				}
 
 				// Validate second recipe (minimal fields)
 				recipe2 := v.Recipes[1]
@@ -771,6 +941,11 @@ func TestGetApiRecipes(t *testing.T) {
 				if recipe2.Recipe.Servings != nil {
 					t.Errorf("expected nil servings, got %v", recipe2.Recipe.Servings)
 				}
+				wantMissing := []CompletenessItem{CoverImage, Times, Servings, Tags, StepInstructions}
+				if recipe2.Completeness == nil || recipe2.Completeness.Score != 0 ||
+					!reflect.DeepEqual(recipe2.Completeness.Missing, wantMissing) {
+					t.Errorf("expected every item missing, got %+v", recipe2.Completeness)
+				}
 			},
 		},
 	}
@@ -834,7 +1009,7 @@ func TestGetApiRecipesPublic(t *testing.T) {
 			injectUser: true,
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
-					GetPublicRecipes(gomock.Any()).
+					GetPublicRecipes(gomock.Any(), database.GetPublicRecipesParams{Limit: config.DefaultPageLimit + 1}).
 					Return(nil, errors.New("database connection failed"))
 			},
 			wantStatus: 500,
@@ -857,8 +1032,11 @@ func TestGetApiRecipesPublic(t *testing.T) {
 			injectUser: true,
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
-					GetPublicRecipes(gomock.Any()).
+					GetPublicRecipes(gomock.Any(), database.GetPublicRecipesParams{Limit: config.DefaultPageLimit + 1}).
 					Return([]database.GetPublicRecipesRow{}, nil)
+				mockDB.EXPECT().
+					GetPublicRecipeCount(gomock.Any(), gomock.Nil()).
+					Return(int64(2), nil)
 			},
 			wantStatus: 200,
 			wantError:  false,
@@ -879,7 +1057,7 @@ func TestGetApiRecipesPublic(t *testing.T) {
 			injectUser: true,
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
-					GetPublicRecipes(gomock.Any()).
+					GetPublicRecipes(gomock.Any(), database.GetPublicRecipesParams{Limit: config.DefaultPageLimit + 1}).
 					Return([]database.GetPublicRecipesRow{
 						{
 							UserID:         pgtype.Int8{Int64: 456, Valid: true},
@@ -916,6 +1094,9 @@ func TestGetApiRecipesPublic(t *testing.T) {
 							LastName:       "Doe",
 						},
 					}, nil)
+				mockDB.EXPECT().
+					GetPublicRecipeCount(gomock.Any(), gomock.Nil()).
+					Return(int64(2), nil)
 
 				mockFS.EXPECT().
 					FileURL("recipe1.jpg").
@@ -1071,7 +1252,7 @@ func TestDeleteApiRecipesRecipeID(t *testing.T) {
 
 				mockDB.EXPECT().
 					GetRecipeAndOwner(gomock.Any(), int64(123)).
-					Return(database.GetRecipeAndOwnerRow{}, nil)
+					Return(database.GetRecipeAndOwnerRow{Title: "Pancakes"}, nil)
 
 				mockDB.EXPECT().
 					GetRecipeSteps(gomock.Any(), int64(123)).
@@ -1084,6 +1265,16 @@ func TestDeleteApiRecipesRecipeID(t *testing.T) {
 				mockDB.EXPECT().
 					DeleteRecipe(gomock.Any(), int64(123)).
 					Return(nil)
+
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), database.CreateAuditEventParams{
+						ActorID:    pgtype.Int8{Int64: 456, Valid: true},
+						Action:     "recipe.delete",
+						TargetType: "recipe",
+						TargetID:   pgtype.Int8{Int64: 123, Valid: true},
+						Metadata:   []byte(`{"title":"Pancakes"}`),
+					}).
+					Return(int64(1), nil)
 			},
 			wantStatus: 204,
 			wantError:  false,
@@ -1146,6 +1337,11 @@ func TestDeleteApiRecipesRecipeID(t *testing.T) {
 				mockDB.EXPECT().
 					DeleteRecipe(gomock.Any(), int64(123)).
 					Return(nil)
+
+				// The recipe is gone, so a failure to record it is only logged.
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					Return(int64(0), errors.New("database connection failed"))
 			},
 			wantStatus: 204,
 			wantError:  false,
@@ -1959,6 +2155,7 @@ func TestPostApiRecipesRecipeIDIngredientsIngredientIDImage(t *testing.T) {
 		userID     int64
 		injectUser bool
 		imageData  []byte
+		filename   string
 		setup      func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface)
 		wantStatus int
 		wantCode   string
@@ -2039,6 +2236,7 @@ func TestPostApiRecipesRecipeIDIngredientsIngredientIDImage(t *testing.T) {
 			userID:     789,
 			injectUser: true,
 			imageData:  validJPEGImage,
+			filename:   "test.jpg",
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
 					CheckIngredientOwnership(gomock.Any(), gomock.Any()).
@@ -2179,6 +2377,35 @@ func TestPostApiRecipesRecipeIDIngredientsIngredientIDImage(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "extension does not match content",
+			request: PostApiRecipesRecipeIDIngredientsIngredientIDImageRequestObject{
+				RecipeID:     123,
+				IngredientID: 456,
+			},
+			userID:     789,
+			injectUser: true,
+			imageData:  validJPEGImage,
+			filename:   "photo.png",
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckIngredientOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+			},
+			wantStatus: 422,
+			wantCode:   apiError.UnsupportedImageFormat.String(),
+			wantError:  false,
+			validate: func(t *testing.T, resp PostApiRecipesRecipeIDIngredientsIngredientIDImageResponseObject) {
+				v, ok := resp.(PostApiRecipesRecipeIDIngredientsIngredientIDImage422JSONResponse)
+				if !ok {
+					t.Errorf("expected 422 response, got %T", resp)
+					return
+				}
+				if v.Code != apiError.UnsupportedImageFormat.String() {
+					t.Errorf("expected code %s, got %s", apiError.UnsupportedImageFormat.String(), v.Code)
+				}
+			},
+		},
 		{
 			name: "invalid image format",
 			request: PostApiRecipesRecipeIDIngredientsIngredientIDImageRequestObject{
@@ -2363,7 +2590,11 @@ func TestPostApiRecipesRecipeIDIngredientsIngredientIDImage(t *testing.T) {
 			// Create multipart form with image
 			body := &bytes.Buffer{}
 			writer := multipart.NewWriter(body)
-			part, err := writer.CreateFormFile("image", "test.png")
+			filename := tt.filename
+			if filename == "" {
+				filename = "test.png"
+			}
+			part, err := writer.CreateFormFile("image", filename)
 			if err != nil {
 				t.Fatalf("failed to create form file: %v", err)
 			}
@@ -2451,16 +2682,21 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientIDImage(t *testing.T) {
 					DeleteRecipeIngredientImageKey(gomock.Any(), int64(456)).
 					Return(nil)
 
-				mockFS.EXPECT().
-					DeleteKey("files/ingredients/123/456.png").
+				// The image is held for undo, not deleted.
+				mockDB.EXPECT().
+					CreateUndoToken(gomock.Any(), gomock.Any()).
 					Return(nil)
 			},
-			wantStatus: 204,
+			wantStatus: 200,
 			wantError:  false,
 			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDIngredientsIngredientIDImageResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDIngredientsIngredientIDImage204Response)
+				v, ok := resp.(DeleteApiRecipesRecipeIDIngredientsIngredientIDImage200JSONResponse)
 				if !ok {
-					t.Errorf("expected 204 response, got %T", resp)
+					t.Errorf("expected 200 response, got %T", resp)
+					return
+				}
+				if v.UndoToken == "" {
+					t.Error("expected an undo token")
 				}
 			},
 		},
@@ -2637,7 +2873,7 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientIDImage(t *testing.T) {
 			},
 		},
 		{
-			name: "file system error deleting image",
+			name: "database error holding image",
 			request: DeleteApiRecipesRecipeIDIngredientsIngredientIDImageRequestObject{
 				RecipeID:     123,
 				IngredientID: 456,
@@ -2660,9 +2896,9 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientIDImage(t *testing.T) {
 					DeleteRecipeIngredientImageKey(gomock.Any(), int64(456)).
 					Return(nil)
 
-				mockFS.EXPECT().
-					DeleteKey("files/ingredients/123/456.png").
-					Return(errors.New("file system error"))
+				mockDB.EXPECT().
+					CreateUndoToken(gomock.Any(), gomock.Any()).
+					Return(errors.New("database error"))
 			},
 			wantStatus: 500,
 			wantCode:   apiError.InternalServerError.String(),
@@ -2692,10 +2928,8 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientIDImage(t *testing.T) {
 				ctx = token.UserIDWithCtx(ctx, tt.userID)
 			}
 			ctx = env.WithCtx(ctx, &env.Env{
-				Logger: log.NullLogger(),
-				Database: &database.Database{
-					Querier: mockDB,
-				},
+				Logger:    log.NullLogger(),
+				Database:  mockDB,
 				FileStore: mockFS,
 			})
 
@@ -3408,6 +3642,124 @@ func TestPatchApiRecipesRecipeIDStepsStepID(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "successful update with temperature",
+			request: PatchApiRecipesRecipeIDStepsStepIDRequestObject{
+				RecipeID: 123,
+				StepID:   456,
+				Body: &UpdateStepRequest{
+					Temperature: nullable.NewNullableWithValue(Temperature{Value: 350, Unit: F}),
+				},
+			},
+			userID:     789,
+			injectUser: true,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckStepOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+
+				mockDB.EXPECT().
+					UpdateRecipeStep(gomock.Any(), database.UpdateRecipeStepParams{
+						ID:                456,
+						UpdateTemperature: pgtype.Bool{Bool: true, Valid: true},
+						TemperatureValue:  pgtype.Float4{Float32: 350, Valid: true},
+						TemperatureUnit: database.NullTemperatureUnit{
+							TemperatureUnit: database.TemperatureUnitF,
+							Valid:           true,
+						},
+					}).
+					Return(database.UpdateRecipeStepRow{
+						ID:               456,
+						StepNumber:       1,
+						TemperatureValue: pgtype.Float4{Float32: 350, Valid: true},
+						TemperatureUnit: database.NullTemperatureUnit{
+							TemperatureUnit: database.TemperatureUnitF,
+							Valid:           true,
+						},
+					}, nil)
+			},
+			wantStatus: 200,
+			wantError:  false,
+			validate: func(t *testing.T, resp PatchApiRecipesRecipeIDStepsStepIDResponseObject) {
+				v, ok := resp.(PatchApiRecipesRecipeIDStepsStepID200JSONResponse)
+				if !ok {
+					t.Errorf("expected 200 response, got %T", resp)
+					return
+				}
+				if v.Temperature == nil || *v.Temperature != (Temperature{Value: 350, Unit: F}) {
+					t.Errorf("expected temperature 350F, got %+v", v.Temperature)
+				}
+			},
+		},
+		{
+			name: "successful update with null temperature to unset field",
+			request: PatchApiRecipesRecipeIDStepsStepIDRequestObject{
+				RecipeID: 123,
+				StepID:   456,
+				Body: &UpdateStepRequest{
+					Temperature: nullable.NewNullNullable[Temperature](),
+				},
+			},
+			userID:     789,
+			injectUser: true,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckStepOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+
+				mockDB.EXPECT().
+					UpdateRecipeStep(gomock.Any(), database.UpdateRecipeStepParams{
+						ID:                456,
+						UpdateTemperature: pgtype.Bool{Bool: true, Valid: true},
+					}).
+					Return(database.UpdateRecipeStepRow{
+						ID:         456,
+						StepNumber: 1,
+					}, nil)
+			},
+			wantStatus: 200,
+			wantError:  false,
+			validate: func(t *testing.T, resp PatchApiRecipesRecipeIDStepsStepIDResponseObject) {
+				v, ok := resp.(PatchApiRecipesRecipeIDStepsStepID200JSONResponse)
+				if !ok {
+					t.Errorf("expected 200 response, got %T", resp)
+					return
+				}
+				if v.Temperature != nil {
+					t.Errorf("expected no temperature, got %+v", v.Temperature)
+				}
+			},
+		},
+		{
+			name: "temperature out of range",
+			request: PatchApiRecipesRecipeIDStepsStepIDRequestObject{
+				RecipeID: 123,
+				StepID:   456,
+				Body: &UpdateStepRequest{
+					Temperature: nullable.NewNullableWithValue(Temperature{Value: 3500, Unit: F}),
+				},
+			},
+			userID:     789,
+			injectUser: true,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckStepOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+			},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+			wantError:  false,
+			validate: func(t *testing.T, resp PatchApiRecipesRecipeIDStepsStepIDResponseObject) {
+				v, ok := resp.(PatchApiRecipesRecipeIDStepsStepID422JSONResponse)
+				if !ok {
+					t.Errorf("expected 422 response, got %T", resp)
+					return
+				}
+				if v.Code != apiError.UnprocessibleEntity.String() {
+					t.Errorf("expected code %s, got %s", apiError.UnprocessibleEntity.String(), v.Code)
+				}
+			},
+		},
 		{
 			name: "database error on update",
 			request: PatchApiRecipesRecipeIDStepsStepIDRequestObject{
@@ -3519,6 +3871,7 @@ func TestPostApiRecipesRecipeIDStepsStepIDImage(t *testing.T) {
 		userID     int64
 		injectUser bool
 		imageData  []byte
+		filename   string
 		setup      func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface)
 		wantStatus int
 		wantCode   string
@@ -3597,6 +3950,7 @@ func TestPostApiRecipesRecipeIDStepsStepIDImage(t *testing.T) {
 			userID:     789,
 			injectUser: true,
 			imageData:  validJPEGImage,
+			filename:   "test.jpg",
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
 					CheckStepOwnership(gomock.Any(), gomock.Any()).
@@ -3743,14 +4097,15 @@ func TestPostApiRecipesRecipeIDStepsStepIDImage(t *testing.T) {
 			},
 		},
 		{
-			name: "invalid image format",
+			name: "extension does not match content",
 			request: PostApiRecipesRecipeIDStepsStepIDImageRequestObject{
 				RecipeID: 123,
 				StepID:   456,
 			},
 			userID:     789,
 			injectUser: true,
-			imageData:  invalidImage,
+			imageData:  validJPEGImage,
+			filename:   "photo.png",
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
 					CheckStepOwnership(gomock.Any(), gomock.Any()).
@@ -3771,25 +4126,53 @@ func TestPostApiRecipesRecipeIDStepsStepIDImage(t *testing.T) {
 			},
 		},
 		{
-			name: "database error getting old image URL",
+			name: "invalid image format",
 			request: PostApiRecipesRecipeIDStepsStepIDImageRequestObject{
 				RecipeID: 123,
 				StepID:   456,
 			},
 			userID:     789,
 			injectUser: true,
-			imageData:  validPNGImage,
+			imageData:  invalidImage,
 			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
 				mockDB.EXPECT().
 					CheckStepOwnership(gomock.Any(), gomock.Any()).
 					Return(true, nil)
-
-				mockDB.EXPECT().
-					GetRecipeStepImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{}, errors.New("database error"))
 			},
-			wantStatus: 500,
-			wantCode:   apiError.InternalServerError.String(),
+			wantStatus: 422,
+			wantCode:   apiError.UnsupportedImageFormat.String(),
+			wantError:  false,
+			validate: func(t *testing.T, resp PostApiRecipesRecipeIDStepsStepIDImageResponseObject) {
+				v, ok := resp.(PostApiRecipesRecipeIDStepsStepIDImage422JSONResponse)
+				if !ok {
+					t.Errorf("expected 422 response, got %T", resp)
+					return
+				}
+				if v.Code != apiError.UnsupportedImageFormat.String() {
+					t.Errorf("expected code %s, got %s", apiError.UnsupportedImageFormat.String(), v.Code)
+				}
+			},
+		},
+		{
+			name: "database error getting old image URL",
+			request: PostApiRecipesRecipeIDStepsStepIDImageRequestObject{
+				RecipeID: 123,
+				StepID:   456,
+			},
+			userID:     789,
+			injectUser: true,
+			imageData:  validPNGImage,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckStepOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+
+				mockDB.EXPECT().
+					GetRecipeStepImageKey(gomock.Any(), int64(456)).
+					Return(pgtype.Text{}, errors.New("database error"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
 			wantError:  false,
 			validate: func(t *testing.T, resp PostApiRecipesRecipeIDStepsStepIDImageResponseObject) {
 				_, ok := resp.(PostApiRecipesRecipeIDStepsStepIDImage500JSONResponse)
@@ -3926,7 +4309,11 @@ func TestPostApiRecipesRecipeIDStepsStepIDImage(t *testing.T) {
 			// Create multipart form with image
 			body := &bytes.Buffer{}
 			writer := multipart.NewWriter(body)
-			part, err := writer.CreateFormFile("image", "test.png")
+			filename := tt.filename
+			if filename == "" {
+				filename = "test.png"
+			}
+			part, err := writer.CreateFormFile("image", filename)
 			if err != nil {
 				t.Fatalf("failed to create form file: %v", err)
 			}
@@ -4014,16 +4401,21 @@ func TestDeleteApiRecipesRecipeIDStepsStepIDImage(t *testing.T) {
 					DeleteRecipeStepImageKey(gomock.Any(), int64(456)).
 					Return(nil)
 
-				mockFS.EXPECT().
-					DeleteKey("files/steps/123/456.png").
+				// The image is held for undo, not deleted.
+				mockDB.EXPECT().
+					CreateUndoToken(gomock.Any(), gomock.Any()).
 					Return(nil)
 			},
-			wantStatus: 204,
+			wantStatus: 200,
 			wantError:  false,
 			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDStepsStepIDImageResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDStepsStepIDImage204Response)
+				v, ok := resp.(DeleteApiRecipesRecipeIDStepsStepIDImage200JSONResponse)
 				if !ok {
-					t.Errorf("expected 204 response, got %T", resp)
+					t.Errorf("expected 200 response, got %T", resp)
+					return
+				}
+				if v.UndoToken == "" {
+					t.Error("expected an undo token")
 				}
 			},
 		},
@@ -4200,7 +4592,7 @@ func TestDeleteApiRecipesRecipeIDStepsStepIDImage(t *testing.T) {
 			},
 		},
 		{
-			name: "file system error deleting image",
+			name: "database error holding image",
 			request: DeleteApiRecipesRecipeIDStepsStepIDImageRequestObject{
 				RecipeID: 123,
 				StepID:   456,
@@ -4223,9 +4615,9 @@ func TestDeleteApiRecipesRecipeIDStepsStepIDImage(t *testing.T) {
 					DeleteRecipeStepImageKey(gomock.Any(), int64(456)).
 					Return(nil)
 
-				mockFS.EXPECT().
-					DeleteKey("files/steps/123/456.png").
-					Return(errors.New("file system error"))
+				mockDB.EXPECT().
+					CreateUndoToken(gomock.Any(), gomock.Any()).
+					Return(errors.New("database error"))
 			},
 			wantStatus: 500,
 			wantCode:   apiError.InternalServerError.String(),
@@ -4255,10 +4647,8 @@ func TestDeleteApiRecipesRecipeIDStepsStepIDImage(t *testing.T) {
 				ctx = token.UserIDWithCtx(ctx, tt.userID)
 			}
 			ctx = env.WithCtx(ctx, &env.Env{
-				Logger: log.NullLogger(),
-				Database: &database.Database{
-					Querier: mockDB,
-				},
+				Logger:    log.NullLogger(),
+				Database:  mockDB,
 				FileStore: mockFS,
 			})
 
@@ -4308,69 +4698,41 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientID(t *testing.T) {
 					}).
 					Return(true, nil)
 
-				mockDB.EXPECT().
-					GetRecipeIngredientImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{Valid: false}, nil)
-
 				mockDB.EXPECT().
 					DeleteRecipeIngredient(gomock.Any(), int64(456)).
-					Return(nil)
-			},
-			wantStatus: 204,
-			wantError:  false,
-			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDIngredientsIngredientIDResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDIngredientsIngredientID204Response)
-				if !ok {
-					t.Errorf("expected 204 response, got %T", resp)
-				}
-			},
-		},
-		{
-			name: "successful deletion with image",
-			request: DeleteApiRecipesRecipeIDIngredientsIngredientIDRequestObject{
-				RecipeID:     123,
-				IngredientID: 456,
-			},
-			userID:     789,
-			injectUser: true,
-			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
-				mockDB.EXPECT().
-					CheckIngredientOwnership(gomock.Any(), database.CheckIngredientOwnershipParams{
-						RecipeID:     123,
-						IngredientID: 456,
-						UserID: pgtype.Int8{
-							Int64: 789,
-							Valid: true,
-						},
-					}).
-					Return(true, nil)
-
-				mockDB.EXPECT().
-					GetRecipeIngredientImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{
-						String: "files/ingredients/123/456.png",
-						Valid:  true,
+					Return(database.RecipeIngredient{
+						ID:          456,
+						RecipeID:    123,
+						Description: pgtype.Text{String: "2 cups flour", Valid: true},
 					}, nil)
 
-				mockFS.EXPECT().
-					DeleteKey("files/ingredients/123/456.png").
-					Return(nil)
-
 				mockDB.EXPECT().
-					DeleteRecipeIngredient(gomock.Any(), int64(456)).
-					Return(nil)
+					CreateUndoToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params database.CreateUndoTokenParams) error {
+						if params.Kind != "ingredient" || params.RecipeID != 123 || params.UserID != 789 {
+							t.Errorf("unexpected undo token params: %+v", params)
+						}
+						if params.ImageKey.Valid {
+							t.Errorf("expected no held image, got %q", params.ImageKey.String)
+						}
+						return nil
+					})
 			},
-			wantStatus: 204,
+			wantStatus: 200,
 			wantError:  false,
 			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDIngredientsIngredientIDResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDIngredientsIngredientID204Response)
+				v, ok := resp.(DeleteApiRecipesRecipeIDIngredientsIngredientID200JSONResponse)
 				if !ok {
-					t.Errorf("expected 204 response, got %T", resp)
+					t.Errorf("expected 200 response, got %T", resp)
+					return
+				}
+				if v.UndoToken == "" {
+					t.Error("expected an undo token")
 				}
 			},
 		},
 		{
-			name: "successful deletion with missing image file",
+			name: "successful deletion holds image",
 			request: DeleteApiRecipesRecipeIDIngredientsIngredientIDRequestObject{
 				RecipeID:     123,
 				IngredientID: 456,
@@ -4390,26 +4752,33 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientID(t *testing.T) {
 					Return(true, nil)
 
 				mockDB.EXPECT().
-					GetRecipeIngredientImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{
-						String: "files/ingredients/123/456.png",
-						Valid:  true,
+					DeleteRecipeIngredient(gomock.Any(), int64(456)).
+					Return(database.RecipeIngredient{
+						ID:       456,
+						RecipeID: 123,
+						ImageKey: pgtype.Text{String: "files/ingredients/123/456.png", Valid: true},
 					}, nil)
 
-				mockFS.EXPECT().
-					DeleteKey("files/ingredients/123/456.png").
-					Return(fileserver.ErrNotExist)
-
+				// The image is held for undo, not deleted.
 				mockDB.EXPECT().
-					DeleteRecipeIngredient(gomock.Any(), int64(456)).
-					Return(nil)
+					CreateUndoToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params database.CreateUndoTokenParams) error {
+						if params.ImageKey.String != "files/ingredients/123/456.png" {
+							t.Errorf("expected held image, got %q", params.ImageKey.String)
+						}
+						return nil
+					})
 			},
-			wantStatus: 204,
+			wantStatus: 200,
 			wantError:  false,
 			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDIngredientsIngredientIDResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDIngredientsIngredientID204Response)
+				v, ok := resp.(DeleteApiRecipesRecipeIDIngredientsIngredientID200JSONResponse)
 				if !ok {
-					t.Errorf("expected 204 response, got %T", resp)
+					t.Errorf("expected 200 response, got %T", resp)
+					return
+				}
+				if v.UndoToken == "" {
+					t.Error("expected an undo token")
 				}
 			},
 		},
@@ -4491,7 +4860,7 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientID(t *testing.T) {
 			},
 		},
 		{
-			name: "database error getting image URL",
+			name: "database error holding deletion",
 			request: DeleteApiRecipesRecipeIDIngredientsIngredientIDRequestObject{
 				RecipeID:     123,
 				IngredientID: 456,
@@ -4504,42 +4873,12 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientID(t *testing.T) {
 					Return(true, nil)
 
 				mockDB.EXPECT().
-					GetRecipeIngredientImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{}, errors.New("database error"))
-			},
-			wantStatus: 500,
-			wantCode:   apiError.InternalServerError.String(),
-			wantError:  false,
-			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDIngredientsIngredientIDResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDIngredientsIngredientID500JSONResponse)
-				if !ok {
-					t.Errorf("expected 500 response, got %T", resp)
-				}
-			},
-		},
-		{
-			name: "file system error deleting image",
-			request: DeleteApiRecipesRecipeIDIngredientsIngredientIDRequestObject{
-				RecipeID:     123,
-				IngredientID: 456,
-			},
-			userID:     789,
-			injectUser: true,
-			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
-				mockDB.EXPECT().
-					CheckIngredientOwnership(gomock.Any(), gomock.Any()).
-					Return(true, nil)
+					DeleteRecipeIngredient(gomock.Any(), int64(456)).
+					Return(database.RecipeIngredient{ID: 456, RecipeID: 123}, nil)
 
 				mockDB.EXPECT().
-					GetRecipeIngredientImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{
-						String: "files/ingredients/123/456.png",
-						Valid:  true,
-					}, nil)
-
-				mockFS.EXPECT().
-					DeleteKey("files/ingredients/123/456.png").
-					Return(errors.New("file system error"))
+					CreateUndoToken(gomock.Any(), gomock.Any()).
+					Return(errors.New("database error"))
 			},
 			wantStatus: 500,
 			wantCode:   apiError.InternalServerError.String(),
@@ -4564,13 +4903,9 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientID(t *testing.T) {
 					CheckIngredientOwnership(gomock.Any(), gomock.Any()).
 					Return(true, nil)
 
-				mockDB.EXPECT().
-					GetRecipeIngredientImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{Valid: false}, nil)
-
 				mockDB.EXPECT().
 					DeleteRecipeIngredient(gomock.Any(), int64(456)).
-					Return(errors.New("database error"))
+					Return(database.RecipeIngredient{}, errors.New("database error"))
 			},
 			wantStatus: 500,
 			wantCode:   apiError.InternalServerError.String(),
@@ -4600,10 +4935,8 @@ func TestDeleteApiRecipesRecipeIDIngredientsIngredientID(t *testing.T) {
 				ctx = token.UserIDWithCtx(ctx, tt.userID)
 			}
 			ctx = env.WithCtx(ctx, &env.Env{
-				Logger: log.NullLogger(),
-				Database: &database.Database{
-					Querier: mockDB,
-				},
+				Logger:    log.NullLogger(),
+				Database:  mockDB,
 				FileStore: mockFS,
 			})
 
@@ -4653,69 +4986,42 @@ func TestDeleteApiRecipesRecipeIDStepsStepID(t *testing.T) {
 					}).
 					Return(true, nil)
 
-				mockDB.EXPECT().
-					GetRecipeStepImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{Valid: false}, nil)
-
 				mockDB.EXPECT().
 					DeleteRecipeStep(gomock.Any(), int64(456)).
-					Return(nil)
-			},
-			wantStatus: 204,
-			wantError:  false,
-			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDStepsStepIDResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDStepsStepID204Response)
-				if !ok {
-					t.Errorf("expected 204 response, got %T", resp)
-				}
-			},
-		},
-		{
-			name: "successful deletion with image",
-			request: DeleteApiRecipesRecipeIDStepsStepIDRequestObject{
-				RecipeID: 123,
-				StepID:   456,
-			},
-			userID:     789,
-			injectUser: true,
-			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
-				mockDB.EXPECT().
-					CheckStepOwnership(gomock.Any(), database.CheckStepOwnershipParams{
-						RecipeID: 123,
-						StepID:   456,
-						UserID: pgtype.Int8{
-							Int64: 789,
-							Valid: true,
-						},
-					}).
-					Return(true, nil)
-
-				mockDB.EXPECT().
-					GetRecipeStepImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{
-						String: "files/steps/123/456.png",
-						Valid:  true,
+					Return(database.RecipeStep{
+						ID:          456,
+						RecipeID:    123,
+						StepNumber:  2,
+						Instruction: pgtype.Text{String: "Whisk the eggs", Valid: true},
 					}, nil)
 
-				mockFS.EXPECT().
-					DeleteKey("files/steps/123/456.png").
-					Return(nil)
-
 				mockDB.EXPECT().
-					DeleteRecipeStep(gomock.Any(), int64(456)).
-					Return(nil)
+					CreateUndoToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params database.CreateUndoTokenParams) error {
+						if params.Kind != "step" || params.RecipeID != 123 || params.UserID != 789 {
+							t.Errorf("unexpected undo token params: %+v", params)
+						}
+						if params.ImageKey.Valid {
+							t.Errorf("expected no held image, got %q", params.ImageKey.String)
+						}
+						return nil
+					})
 			},
-			wantStatus: 204,
+			wantStatus: 200,
 			wantError:  false,
 			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDStepsStepIDResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDStepsStepID204Response)
+				v, ok := resp.(DeleteApiRecipesRecipeIDStepsStepID200JSONResponse)
 				if !ok {
-					t.Errorf("expected 204 response, got %T", resp)
+					t.Errorf("expected 200 response, got %T", resp)
+					return
+				}
+				if v.UndoToken == "" {
+					t.Error("expected an undo token")
 				}
 			},
 		},
 		{
-			name: "successful deletion with missing image file",
+			name: "successful deletion holds image",
 			request: DeleteApiRecipesRecipeIDStepsStepIDRequestObject{
 				RecipeID: 123,
 				StepID:   456,
@@ -4735,26 +5041,33 @@ func TestDeleteApiRecipesRecipeIDStepsStepID(t *testing.T) {
 					Return(true, nil)
 
 				mockDB.EXPECT().
-					GetRecipeStepImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{
-						String: "files/steps/123/456.png",
-						Valid:  true,
+					DeleteRecipeStep(gomock.Any(), int64(456)).
+					Return(database.RecipeStep{
+						ID:       456,
+						RecipeID: 123,
+						ImageKey: pgtype.Text{String: "files/steps/123/456.png", Valid: true},
 					}, nil)
 
-				mockFS.EXPECT().
-					DeleteKey("files/steps/123/456.png").
-					Return(fileserver.ErrNotExist)
-
+				// The image is held for undo, not deleted.
 				mockDB.EXPECT().
-					DeleteRecipeStep(gomock.Any(), int64(456)).
-					Return(nil)
+					CreateUndoToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, params database.CreateUndoTokenParams) error {
+						if params.ImageKey.String != "files/steps/123/456.png" {
+							t.Errorf("expected held image, got %q", params.ImageKey.String)
+						}
+						return nil
+					})
 			},
-			wantStatus: 204,
+			wantStatus: 200,
 			wantError:  false,
 			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDStepsStepIDResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDStepsStepID204Response)
+				v, ok := resp.(DeleteApiRecipesRecipeIDStepsStepID200JSONResponse)
 				if !ok {
-					t.Errorf("expected 204 response, got %T", resp)
+					t.Errorf("expected 200 response, got %T", resp)
+					return
+				}
+				if v.UndoToken == "" {
+					t.Error("expected an undo token")
 				}
 			},
 		},
@@ -4836,7 +5149,7 @@ func TestDeleteApiRecipesRecipeIDStepsStepID(t *testing.T) {
 			},
 		},
 		{
-			name: "database error getting image URL",
+			name: "database error holding deletion",
 			request: DeleteApiRecipesRecipeIDStepsStepIDRequestObject{
 				RecipeID: 123,
 				StepID:   456,
@@ -4849,42 +5162,12 @@ func TestDeleteApiRecipesRecipeIDStepsStepID(t *testing.T) {
 					Return(true, nil)
 
 				mockDB.EXPECT().
-					GetRecipeStepImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{}, errors.New("database error"))
-			},
-			wantStatus: 500,
-			wantCode:   apiError.InternalServerError.String(),
-			wantError:  false,
-			validate: func(t *testing.T, resp DeleteApiRecipesRecipeIDStepsStepIDResponseObject) {
-				_, ok := resp.(DeleteApiRecipesRecipeIDStepsStepID500JSONResponse)
-				if !ok {
-					t.Errorf("expected 500 response, got %T", resp)
-				}
-			},
-		},
-		{
-			name: "file system error deleting image",
-			request: DeleteApiRecipesRecipeIDStepsStepIDRequestObject{
-				RecipeID: 123,
-				StepID:   456,
-			},
-			userID:     789,
-			injectUser: true,
-			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
-				mockDB.EXPECT().
-					CheckStepOwnership(gomock.Any(), gomock.Any()).
-					Return(true, nil)
+					DeleteRecipeStep(gomock.Any(), int64(456)).
+					Return(database.RecipeStep{ID: 456, RecipeID: 123}, nil)
 
 				mockDB.EXPECT().
-					GetRecipeStepImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{
-						String: "files/steps/123/456.png",
-						Valid:  true,
-					}, nil)
-
-				mockFS.EXPECT().
-					DeleteKey("files/steps/123/456.png").
-					Return(errors.New("file system error"))
+					CreateUndoToken(gomock.Any(), gomock.Any()).
+					Return(errors.New("database error"))
 			},
 			wantStatus: 500,
 			wantCode:   apiError.InternalServerError.String(),
@@ -4909,13 +5192,9 @@ func TestDeleteApiRecipesRecipeIDStepsStepID(t *testing.T) {
 					CheckStepOwnership(gomock.Any(), gomock.Any()).
 					Return(true, nil)
 
-				mockDB.EXPECT().
-					GetRecipeStepImageKey(gomock.Any(), int64(456)).
-					Return(pgtype.Text{Valid: false}, nil)
-
 				mockDB.EXPECT().
 					DeleteRecipeStep(gomock.Any(), int64(456)).
-					Return(errors.New("database error"))
+					Return(database.RecipeStep{}, errors.New("database error"))
 			},
 			wantStatus: 500,
 			wantCode:   apiError.InternalServerError.String(),
@@ -4945,10 +5224,8 @@ func TestDeleteApiRecipesRecipeIDStepsStepID(t *testing.T) {
 				ctx = token.UserIDWithCtx(ctx, tt.userID)
 			}
 			ctx = env.WithCtx(ctx, &env.Env{
-				Logger: log.NullLogger(),
-				Database: &database.Database{
-					Querier: mockDB,
-				},
+				Logger:    log.NullLogger(),
+				Database:  mockDB,
 				FileStore: mockFS,
 			})
 
@@ -4966,6 +5243,150 @@ func TestDeleteApiRecipesRecipeIDStepsStepID(t *testing.T) {
 	}
 }
 
+func TestPostApiRecipesRecipeIDImage(t *testing.T) {
+	// Create a simple JPEG image header for testing
+	validJPEGImage := []byte{
+		0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 0x4A, 0x46,
+		0x49, 0x46, 0x00, 0x01, 0x01, 0x00, 0x00, 0x01,
+		0x00, 0x01, 0x00, 0x00, 0xFF, 0xDB, 0x00, 0x43,
+		0x00, 0x08, 0x06, 0x06, 0x07, 0x06, 0x05, 0x08,
+		0xFF, 0xD9, // EOI
+	}
+
+	tests := []struct {
+		name      string
+		imageData []byte
+		filename  string
+		setup     func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface)
+		validate  func(t *testing.T, resp PostApiRecipesRecipeIDImageResponseObject)
+	}{
+		{
+			name:      "successful upload",
+			imageData: validJPEGImage,
+			filename:  "cover.jpg",
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+				mockDB.EXPECT().
+					GetRecipeImageKey(gomock.Any(), int64(123)).
+					Return(pgtype.Text{Valid: false}, nil)
+				mockFS.EXPECT().
+					WriteRecipeCoverImage(".jpg", validJPEGImage).
+					Return("files/covers/123.jpg", len(validJPEGImage), nil)
+				mockDB.EXPECT().
+					UpdateRecipe(gomock.Any(), gomock.Any()).
+					Return(database.UpdateRecipeRow{
+						ID: 123,
+						ImageKey: pgtype.Text{
+							String: "files/covers/123.jpg",
+							Valid:  true,
+						},
+					}, nil)
+				mockFS.EXPECT().
+					FileURL("files/covers/123.jpg").
+					Return("http://test-host/files/covers/123.jpg")
+			},
+			validate: func(t *testing.T, resp PostApiRecipesRecipeIDImageResponseObject) {
+				v, ok := resp.(PostApiRecipesRecipeIDImage200JSONResponse)
+				if !ok {
+					t.Fatalf("expected 200 response, got %T", resp)
+				}
+				if v.ImageUrl == nil || *v.ImageUrl != "http://test-host/files/covers/123.jpg" {
+					t.Errorf("unexpected image_url %v", v.ImageUrl)
+				}
+			},
+		},
+		{
+			name:      "extension does not match content",
+			imageData: validJPEGImage,
+			filename:  "cover.png",
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+			},
+			validate: func(t *testing.T, resp PostApiRecipesRecipeIDImageResponseObject) {
+				v, ok := resp.(PostApiRecipesRecipeIDImage422JSONResponse)
+				if !ok {
+					t.Fatalf("expected 422 response, got %T", resp)
+				}
+				if v.Code != apiError.UnsupportedImageFormat.String() {
+					t.Errorf("expected code %s, got %s", apiError.UnsupportedImageFormat.String(), v.Code)
+				}
+				if v.Message != "image extension does not match its contents" {
+					t.Errorf("unexpected message %q", v.Message)
+				}
+			},
+		},
+		{
+			name:      "unsupported format",
+			imageData: []byte("not an image"),
+			filename:  "cover.png",
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+			},
+			validate: func(t *testing.T, resp PostApiRecipesRecipeIDImageResponseObject) {
+				v, ok := resp.(PostApiRecipesRecipeIDImage422JSONResponse)
+				if !ok {
+					t.Fatalf("expected 422 response, got %T", resp)
+				}
+				if v.Message != "unsupported image format" {
+					t.Errorf("unexpected message %q", v.Message)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := database.NewMockQuerier(ctrl)
+			mockFS := filestore.NewMockFileStoreInterface(ctrl)
+
+			tt.setup(mockDB, mockFS)
+
+			// Create multipart form with image
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("image", tt.filename)
+			if err != nil {
+				t.Fatalf("failed to create form file: %v", err)
+			}
+			if _, err := part.Write(tt.imageData); err != nil {
+				t.Fatalf("failed to write image data: %v", err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("failed to close writer: %v", err)
+			}
+
+			ctx := context.Background()
+			ctx = requestid.InjectRequestID(ctx, 12345)
+			ctx = token.UserIDWithCtx(ctx, 789)
+			ctx = env.WithCtx(ctx, &env.Env{
+				Logger: log.NullLogger(),
+				Database: &database.Database{
+					Querier: mockDB,
+				},
+				FileStore: mockFS,
+			})
+
+			resp, err := NewServer().PostApiRecipesRecipeIDImage(ctx, PostApiRecipesRecipeIDImageRequestObject{
+				RecipeID: 123,
+				Body:     multipart.NewReader(body, writer.Boundary()),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.validate(t, resp)
+		})
+	}
+}
+
 func TestPatchApiRecipesRecipeID(t *testing.T) {
 	now := time.Now()
 
@@ -5028,6 +5449,15 @@ func TestPatchApiRecipesRecipeID(t *testing.T) {
 						UpdatedAt:      pgtype.Timestamptz{Time: now, Valid: true},
 						ImageKey:       pgtype.Text{String: "recipe.jpg", Valid: true},
 					}, nil)
+
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.CreateAuditEventParams) (int64, error) {
+						if params.Action != "recipe.publish" || params.TargetID.Int64 != 123 {
+							t.Errorf("unexpected audit event %+v", params)
+						}
+						return 1, nil
+					})
 			},
 			wantStatus: 200,
 			wantError:  false,
@@ -5091,6 +5521,15 @@ func TestPatchApiRecipesRecipeID(t *testing.T) {
 						CreatedAt: pgtype.Timestamptz{Time: now, Valid: true},
 						UpdatedAt: pgtype.Timestamptz{Time: now, Valid: true},
 					}, nil)
+
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.CreateAuditEventParams) (int64, error) {
+						if params.Action != "recipe.unpublish" {
+							t.Errorf("unexpected audit event %+v", params)
+						}
+						return 1, nil
+					})
 			},
 			wantStatus: 200,
 			wantError:  false,
@@ -5483,3 +5922,7 @@ func nullNullableFloat32() nullable.Nullable[float32] {
 func nullNullableTimeUnit() nullable.Nullable[TimeUnit] {
 	return nullable.NewNullNullable[TimeUnit]()
 }
+
+func temperatureUnitPtr(u TemperatureUnit) *TemperatureUnit {
+	return &u
+}
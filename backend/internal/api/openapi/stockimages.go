@@ -0,0 +1,468 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/oapi-codegen/nullable"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/audit"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/form"
+	"github.com/matt-dz/wecook/internal/stockimage"
+)
+
+func (Server) GetApiIngredientsStockImage(ctx context.Context,
+	request GetApiIngredientsStockImageRequestObject) (
+	GetApiIngredientsStockImageResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Match stock image
+	candidates := stockimage.Candidates(request.Params.Ingredient)
+	env.Logger.DebugContext(ctx, "matching stock image", slog.Any("candidates", candidates))
+	if len(candidates) == 0 {
+		return GetApiIngredientsStockImage404JSONResponse{
+			Status:  apiError.StockImageNotFound.StatusCode(),
+			Code:    apiError.StockImageNotFound.String(),
+			Message: "no stock image matches the ingredient",
+			ErrorId: requestID,
+		}, nil
+	}
+	row, err := env.Database.MatchStockImage(ctx, candidates)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return GetApiIngredientsStockImage404JSONResponse{
+			Status:  apiError.StockImageNotFound.StatusCode(),
+			Code:    apiError.StockImageNotFound.String(),
+			Message: "no stock image matches the ingredient",
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to match stock image", slog.Any("error", err))
+		return GetApiIngredientsStockImage500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return GetApiIngredientsStockImage200JSONResponse(newStockImage(env, row)), nil
+}
+
+func (Server) GetApiStockImages(ctx context.Context,
+	request GetApiStockImagesRequestObject) (
+	GetApiStockImagesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Get stock images
+	env.Logger.DebugContext(ctx, "getting stock images")
+	rows, err := env.Database.GetStockImages(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get stock images", slog.Any("error", err))
+		return GetApiStockImages500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	res := GetApiStockImages200JSONResponse{
+		StockImages: make([]StockImage, 0, len(rows)),
+	}
+	for _, row := range rows {
+		res.StockImages = append(res.StockImages, newStockImage(env, row))
+	}
+	return res, nil
+}
+
+func (Server) PostApiStockImages(ctx context.Context,
+	request PostApiStockImagesRequestObject) (
+	PostApiStockImagesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	actorID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiStockImages500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Read form
+	env.Logger.DebugContext(ctx, "reading stock image form")
+	requestForm, err := request.Body.ReadForm(form.MaximumUploadSize)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to read form", slog.Any("error", err))
+		return PostApiStockImages400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "invalid form",
+			ErrorId: requestID,
+		}, nil
+	}
+	if len(requestForm.File["image"]) == 0 {
+		return PostApiStockImages400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing image",
+			ErrorId: requestID,
+		}, nil
+	}
+	var ingredient string
+	if values := requestForm.Value["ingredient"]; len(values) > 0 {
+		ingredient = stockimage.Canonical(values[0])
+	}
+	if ingredient == "" {
+		return PostApiStockImages422JSONResponse{
+			Status:  apiError.UnprocessibleEntity.StatusCode(),
+			Code:    apiError.UnprocessibleEntity.String(),
+			Message: "ingredient name must not be blank",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Read image
+	env.Logger.DebugContext(ctx, "reading stock image")
+	imageHeader := requestForm.File["image"][0]
+	imageFile, err := imageHeader.Open()
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to open image", slog.Any("error", err))
+		return PostApiStockImages400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "invalid image",
+			ErrorId: requestID,
+		}, nil
+	}
+	defer func() { _ = imageFile.Close() }()
+	file, err := form.ReadImage(imageFile, imageHeader.Filename)
+	if errors.Is(err, form.ErrUnsupportedMimeType) {
+		env.Logger.ErrorContext(ctx, "unsupported format", slog.Any("error", err))
+		return PostApiStockImages422JSONResponse{
+			Status:  apiError.UnsupportedImageFormat.StatusCode(),
+			Code:    apiError.UnsupportedImageFormat.String(),
+			Message: "unsupported image format",
+			ErrorId: requestID,
+		}, nil
+	}
+	if errors.Is(err, form.ErrExtensionMismatch) {
+		env.Logger.ErrorContext(ctx, "image extension does not match content", slog.Any("error", err))
+		return PostApiStockImages422JSONResponse{
+			Status:  apiError.UnsupportedImageFormat.StatusCode(),
+			Code:    apiError.UnsupportedImageFormat.String(),
+			Message: "image extension does not match its contents",
+			ErrorId: requestID,
+		}, nil
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to read image", slog.Any("error", err))
+		return PostApiStockImages400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "invalid image",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Write image
+	env.Logger.DebugContext(ctx, "writing stock image")
+	imageKey, _, err := env.FileStore.WriteIngredientImage(file.Suffix, file.Data)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to write stock image", slog.Any("error", err))
+		return PostApiStockImages500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Store stock image
+	env.Logger.DebugContext(ctx, "storing stock image", slog.String("ingredient", ingredient))
+	row, err := env.Database.UpsertStockImage(ctx, database.UpsertStockImageParams{
+		Ingredient: ingredient,
+		ImageKey:   imageKey,
+		CreatedBy:  pgtype.Int8{Int64: actorID, Valid: true},
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to store stock image", slog.Any("error", err))
+		if err := env.FileStore.DeleteKey(imageKey); err != nil {
+			env.Logger.WarnContext(ctx, "failed to delete unused stock image", slog.Any("error", err))
+		}
+		return PostApiStockImages500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Delete replaced image
+	if row.OldImageKey.Valid {
+		env.Logger.DebugContext(ctx, "deleting replaced stock image")
+		if err := env.FileStore.DeleteKey(row.OldImageKey.String); err != nil {
+			env.Logger.WarnContext(ctx, "failed to delete replaced stock image", slog.Any("error", err))
+		}
+	}
+
+	// Record upload
+	env.Logger.DebugContext(ctx, "recording upload")
+	if err := audit.Record(ctx, env.Database, audit.Event{
+		ActorID:    actorID,
+		Action:     audit.ActionUploadStockImage,
+		TargetType: audit.TargetStockImage,
+		TargetID:   row.ID,
+		Metadata:   map[string]any{"ingredient": ingredient, "replaced": row.OldImageKey.Valid},
+	}); err != nil {
+		env.Logger.WarnContext(ctx, "failed to record audit event", slog.Any("error", err))
+	}
+
+	return PostApiStockImages201JSONResponse(newStockImage(env, database.StockImage{
+		ID:         row.ID,
+		Ingredient: row.Ingredient,
+		ImageKey:   row.ImageKey,
+		CreatedBy:  row.CreatedBy,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	})), nil
+}
+
+func (Server) DeleteApiStockImagesStockImageID(ctx context.Context,
+	request DeleteApiStockImagesStockImageIDRequestObject) (
+	DeleteApiStockImagesStockImageIDResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	actorID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return DeleteApiStockImagesStockImageID500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Delete stock image
+	env.Logger.DebugContext(ctx, "deleting stock image", slog.Int64("stock_image_id", request.StockImageID))
+	imageKey, err := env.Database.DeleteStockImage(ctx, request.StockImageID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return DeleteApiStockImagesStockImageID404JSONResponse{
+			Status:  apiError.StockImageNotFound.StatusCode(),
+			Code:    apiError.StockImageNotFound.String(),
+			Message: "stock image not found",
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to delete stock image", slog.Any("error", err))
+		return DeleteApiStockImagesStockImageID500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Delete image file. Ingredients attached to the image have their own
+	// copy, so only the library's file goes.
+	env.Logger.DebugContext(ctx, "deleting stock image file")
+	if err := env.FileStore.DeleteKey(imageKey); err != nil {
+		env.Logger.WarnContext(ctx, "failed to delete stock image file", slog.Any("error", err))
+	}
+
+	// Record deletion
+	env.Logger.DebugContext(ctx, "recording deletion")
+	if err := audit.Record(ctx, env.Database, audit.Event{
+		ActorID:    actorID,
+		Action:     audit.ActionDeleteStockImage,
+		TargetType: audit.TargetStockImage,
+		TargetID:   request.StockImageID,
+	}); err != nil {
+		env.Logger.WarnContext(ctx, "failed to record audit event", slog.Any("error", err))
+	}
+
+	return DeleteApiStockImagesStockImageID204Response{}, nil
+}
+
+func (Server) PostApiRecipesRecipeIDIngredientsIngredientIDStockImage(ctx context.Context,
+	request PostApiRecipesRecipeIDIngredientsIngredientIDStockImageRequestObject) (
+	PostApiRecipesRecipeIDIngredientsIngredientIDStockImageResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+	if request.Body == nil {
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing request body",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Check ownership
+	env.Logger.DebugContext(ctx, "checking user ownership")
+	ownsIngredient, err := env.Database.CheckIngredientOwnership(ctx, database.CheckIngredientOwnershipParams{
+		RecipeID:     request.RecipeID,
+		IngredientID: request.IngredientID,
+		UserID: pgtype.Int8{
+			Int64: userID,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to check recipe ownership", slog.Any("error", err))
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if !ownsIngredient {
+		env.Logger.ErrorContext(ctx, "user does not own recipe or ingredient")
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage404JSONResponse{
+			Status:  apiError.RecipeNotFound.StatusCode(),
+			Code:    apiError.RecipeNotFound.String(),
+			Message: "recipe/ingredient does not exist or user does not own recipe",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get stock image
+	env.Logger.DebugContext(ctx, "getting stock image", slog.Int64("stock_image_id", request.Body.StockImageId))
+	stock, err := env.Database.GetStockImage(ctx, request.Body.StockImageId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage404JSONResponse{
+			Status:  apiError.StockImageNotFound.StatusCode(),
+			Code:    apiError.StockImageNotFound.String(),
+			Message: "stock image not found",
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get stock image", slog.Any("error", err))
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get current image
+	env.Logger.DebugContext(ctx, "getting current image key")
+	oldImage, err := env.Database.GetRecipeIngredientImageKey(ctx, request.IngredientID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get current image key", slog.Any("error", err))
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Copy stock image. The ingredient gets its own copy so deleting either
+	// leaves the other intact.
+	env.Logger.DebugContext(ctx, "copying stock image")
+	data, err := env.FileStore.ReadKey(stock.ImageKey)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to read stock image", slog.Any("error", err))
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	imageKey, _, err := env.FileStore.WriteIngredientImage(path.Ext(stock.ImageKey), data)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to write ingredient image", slog.Any("error", err))
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Update image key in database
+	env.Logger.DebugContext(ctx, "update image in database")
+	ingredient, err := env.Database.UpdateRecipeIngredient(ctx, database.UpdateRecipeIngredientParams{
+		ID: request.IngredientID,
+		UpdateImageKey: pgtype.Bool{
+			Bool:  true,
+			Valid: true,
+		},
+		ImageKey: pgtype.Text{
+			String: imageKey,
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to update recipe ingredient", slog.Any("error", err))
+		return PostApiRecipesRecipeIDIngredientsIngredientIDStockImage500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Delete replaced image
+	if oldImage.Valid {
+		env.Logger.DebugContext(ctx, "deleting replaced image")
+		if err := env.FileStore.DeleteKey(oldImage.String); err != nil {
+			env.Logger.WarnContext(ctx, "failed to delete replaced image", slog.Any("error", err))
+		}
+	}
+
+	imageURL := env.FileStore.FileURL(ingredient.ImageKey.String)
+	res := PostApiRecipesRecipeIDIngredientsIngredientIDStockImage200JSONResponse{
+		Id:       ingredient.ID,
+		ImageUrl: &imageURL,
+	}
+	if ingredient.Description.Valid {
+		res.Description = nullable.NewNullableWithValue(ingredient.Description.String)
+	}
+	return res, nil
+}
+
+// newStockImage converts a stored stock image to its API form.
+func newStockImage(env *env.Env, row database.StockImage) StockImage {
+	return StockImage{
+		Id:         row.ID,
+		Ingredient: row.Ingredient,
+		ImageUrl:   env.FileStore.FileURL(row.ImageKey),
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,317 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/audit"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/filestore"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func stockImageTestContext(mockDB database.Querier, mockFS filestore.FileStoreInterface) context.Context {
+	ctx := context.Background()
+	ctx = requestid.InjectRequestID(ctx, 12345)
+	ctx = token.UserIDWithCtx(ctx, 42)
+	return env.WithCtx(ctx, &env.Env{
+		Logger:    log.NullLogger(),
+		Database:  mockDB,
+		FileStore: mockFS,
+	})
+}
+
+func TestGetApiIngredientsStockImage(t *testing.T) {
+	egg := database.StockImage{ID: 7, Ingredient: "egg", ImageKey: "files/ingredients/egg.png"}
+
+	tests := []struct {
+		name       string
+		ingredient string
+		setup      func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface)
+		wantStatus int
+	}{
+		{
+			name:       "matches most specific name",
+			ingredient: "2 large eggs, beaten",
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					MatchStockImage(gomock.Any(), []string{"large eggs", "large egg", "eggs", "egg"}).
+					Return(egg, nil)
+				mockFS.EXPECT().FileURL(egg.ImageKey).Return("http://test-host/files/ingredients/egg.png")
+			},
+			wantStatus: 200,
+		},
+		{
+			name:       "no match",
+			ingredient: "saffron",
+			setup: func(mockDB *database.MockQuerier, _ *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().MatchStockImage(gomock.Any(), gomock.Any()).Return(database.StockImage{}, pgx.ErrNoRows)
+			},
+			wantStatus: 404,
+		},
+		{
+			name:       "no ingredient name",
+			ingredient: "2",
+			setup:      func(*database.MockQuerier, *filestore.MockFileStoreInterface) {},
+			wantStatus: 404,
+		},
+		{
+			name:       "database error",
+			ingredient: "salt",
+			setup: func(mockDB *database.MockQuerier, _ *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().MatchStockImage(gomock.Any(), gomock.Any()).Return(database.StockImage{}, errors.New("db down"))
+			},
+			wantStatus: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := database.NewMockQuerier(ctrl)
+			mockFS := filestore.NewMockFileStoreInterface(ctrl)
+			tt.setup(mockDB, mockFS)
+
+			resp, err := NewServer().GetApiIngredientsStockImage(stockImageTestContext(mockDB, mockFS),
+				GetApiIngredientsStockImageRequestObject{
+					Params: GetApiIngredientsStockImageParams{Ingredient: tt.ingredient},
+				})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case GetApiIngredientsStockImage200JSONResponse:
+				if tt.wantStatus != 200 {
+					t.Errorf("expected status %d, got 200", tt.wantStatus)
+				}
+				if v.Id != 7 || v.Ingredient != "egg" || v.ImageUrl != "http://test-host/files/ingredients/egg.png" {
+					t.Errorf("unexpected stock image %+v", v)
+				}
+			case GetApiIngredientsStockImage404JSONResponse:
+				if tt.wantStatus != 404 || v.Code != apiError.StockImageNotFound.String() {
+					t.Errorf("expected status %d, got 404 %s", tt.wantStatus, v.Code)
+				}
+			case GetApiIngredientsStockImage500JSONResponse:
+				if tt.wantStatus != 500 {
+					t.Errorf("expected status %d, got 500", tt.wantStatus)
+				}
+			default:
+				t.Fatalf("unexpected response type %T", resp)
+			}
+		})
+	}
+}
+
+func TestPostApiStockImages_ReplacesExistingImage(t *testing.T) {
+	pngImage := []byte{
+		0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A,
+		0x00, 0x00, 0x00, 0x0D, 0x49, 0x48, 0x44, 0x52,
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+		0x08, 0x02, 0x00, 0x00, 0x00, 0x90, 0x77, 0x53,
+		0xDE, 0x00, 0x00, 0x00, 0x0C, 0x49, 0x44, 0x41,
+		0x54, 0x08, 0xD7, 0x63, 0xF8, 0xCF, 0xC0, 0x00,
+		0x00, 0x03, 0x01, 0x01, 0x00, 0x18, 0xDD, 0x8D,
+		0xB4, 0x00, 0x00, 0x00, 0x00, 0x49, 0x45, 0x4E,
+		0x44, 0xAE, 0x42, 0x60, 0x82,
+	}
+
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockFS := filestore.NewMockFileStoreInterface(ctrl)
+
+	mockFS.EXPECT().WriteIngredientImage(".png", pngImage).Return("files/ingredients/new.png", len(pngImage), nil)
+	mockDB.EXPECT().
+		UpsertStockImage(gomock.Any(), database.UpsertStockImageParams{
+			Ingredient: "egg yolk",
+			ImageKey:   "files/ingredients/new.png",
+			CreatedBy:  pgtype.Int8{Int64: 42, Valid: true},
+		}).
+		Return(database.UpsertStockImageRow{
+			ID:          3,
+			Ingredient:  "egg yolk",
+			ImageKey:    "files/ingredients/new.png",
+			OldImageKey: pgtype.Text{String: "files/ingredients/old.png", Valid: true},
+		}, nil)
+	mockFS.EXPECT().DeleteKey("files/ingredients/old.png").Return(nil)
+	mockDB.EXPECT().
+		CreateAuditEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params database.CreateAuditEventParams) (int64, error) {
+			if params.Action != string(audit.ActionUploadStockImage) || params.TargetID.Int64 != 3 {
+				t.Errorf("unexpected audit event %+v", params)
+			}
+			return 1, nil
+		})
+	mockFS.EXPECT().FileURL("files/ingredients/new.png").Return("http://test-host/files/ingredients/new.png")
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if err := writer.WriteField("ingredient", "  Egg Yolks "); err != nil {
+		t.Fatalf("failed to write field: %v", err)
+	}
+	part, err := writer.CreateFormFile("image", "yolk.png")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	if _, err := part.Write(pngImage); err != nil {
+		t.Fatalf("failed to write image data: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+
+	resp, err := NewServer().PostApiStockImages(stockImageTestContext(mockDB, mockFS), PostApiStockImagesRequestObject{
+		Body: multipart.NewReader(body, writer.Boundary()),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(PostApiStockImages201JSONResponse)
+	if !ok {
+		t.Fatalf("expected 201 response, got %T", resp)
+	}
+	if v.Id != 3 || v.Ingredient != "egg yolk" || v.ImageUrl != "http://test-host/files/ingredients/new.png" {
+		t.Errorf("unexpected stock image %+v", v)
+	}
+}
+
+func TestDeleteApiStockImagesStockImageID(t *testing.T) {
+	t.Run("deletes image", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockFS := filestore.NewMockFileStoreInterface(ctrl)
+		mockDB.EXPECT().DeleteStockImage(gomock.Any(), int64(3)).Return("files/ingredients/egg.png", nil)
+		mockFS.EXPECT().DeleteKey("files/ingredients/egg.png").Return(nil)
+		mockDB.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+		resp, err := NewServer().DeleteApiStockImagesStockImageID(stockImageTestContext(mockDB, mockFS),
+			DeleteApiStockImagesStockImageIDRequestObject{StockImageID: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := resp.(DeleteApiStockImagesStockImageID204Response); !ok {
+			t.Errorf("expected 204 response, got %T", resp)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().DeleteStockImage(gomock.Any(), int64(3)).Return("", pgx.ErrNoRows)
+
+		resp, err := NewServer().DeleteApiStockImagesStockImageID(stockImageTestContext(mockDB, nil),
+			DeleteApiStockImagesStockImageIDRequestObject{StockImageID: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v, ok := resp.(DeleteApiStockImagesStockImageID404JSONResponse); !ok || v.Code != apiError.StockImageNotFound.String() {
+			t.Errorf("expected stock image not found, got %+v", resp)
+		}
+	})
+}
+
+func TestPostApiRecipesRecipeIDIngredientsIngredientIDStockImage(t *testing.T) {
+	stock := database.StockImage{ID: 7, Ingredient: "salt", ImageKey: "files/ingredients/salt.jpg"}
+	request := PostApiRecipesRecipeIDIngredientsIngredientIDStockImageRequestObject{
+		RecipeID:     123,
+		IngredientID: 456,
+		Body:         &AttachStockImageRequest{StockImageId: 7},
+	}
+
+	t.Run("copies stock image", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockFS := filestore.NewMockFileStoreInterface(ctrl)
+
+		var calls []string
+		mockDB.EXPECT().CheckIngredientOwnership(gomock.Any(), gomock.Any()).Return(true, nil)
+		mockDB.EXPECT().GetStockImage(gomock.Any(), int64(7)).Return(stock, nil)
+		mockDB.EXPECT().
+			GetRecipeIngredientImageKey(gomock.Any(), int64(456)).
+			Return(pgtype.Text{String: "files/ingredients/old.png", Valid: true}, nil)
+		mockFS.EXPECT().ReadKey(stock.ImageKey).Return([]byte("salt"), nil)
+		mockFS.EXPECT().WriteIngredientImage(".jpg", []byte("salt")).Return("files/ingredients/copy.jpg", 4, nil)
+		mockDB.EXPECT().
+			UpdateRecipeIngredient(gomock.Any(), database.UpdateRecipeIngredientParams{
+				ID:             456,
+				UpdateImageKey: pgtype.Bool{Bool: true, Valid: true},
+				ImageKey:       pgtype.Text{String: "files/ingredients/copy.jpg", Valid: true},
+			}).
+			DoAndReturn(func(context.Context, database.UpdateRecipeIngredientParams) (database.RecipeIngredient, error) {
+				calls = append(calls, "update")
+				return database.RecipeIngredient{
+					ID:          456,
+					Description: pgtype.Text{String: "salt, to taste", Valid: true},
+					ImageKey:    pgtype.Text{String: "files/ingredients/copy.jpg", Valid: true},
+				}, nil
+			})
+		mockFS.EXPECT().
+			DeleteKey("files/ingredients/old.png").
+			DoAndReturn(func(string) error {
+				calls = append(calls, "delete")
+				return nil
+			})
+		mockFS.EXPECT().FileURL("files/ingredients/copy.jpg").Return("http://test-host/files/ingredients/copy.jpg")
+
+		resp, err := NewServer().PostApiRecipesRecipeIDIngredientsIngredientIDStockImage(
+			stockImageTestContext(mockDB, mockFS), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PostApiRecipesRecipeIDIngredientsIngredientIDStockImage200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		if v.Id != 456 || v.ImageUrl == nil || *v.ImageUrl != "http://test-host/files/ingredients/copy.jpg" {
+			t.Errorf("unexpected ingredient %+v", v)
+		}
+		if !slices.Equal(calls, []string{"update", "delete"}) {
+			t.Errorf("expected old image to be deleted after the update, got %v", calls)
+		}
+	})
+
+	t.Run("stock image not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().CheckIngredientOwnership(gomock.Any(), gomock.Any()).Return(true, nil)
+		mockDB.EXPECT().GetStockImage(gomock.Any(), int64(7)).Return(database.StockImage{}, pgx.ErrNoRows)
+
+		resp, err := NewServer().PostApiRecipesRecipeIDIngredientsIngredientIDStockImage(
+			stockImageTestContext(mockDB, nil), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PostApiRecipesRecipeIDIngredientsIngredientIDStockImage404JSONResponse)
+		if !ok || v.Code != apiError.StockImageNotFound.String() {
+			t.Errorf("expected stock image not found, got %+v", resp)
+		}
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().CheckIngredientOwnership(gomock.Any(), gomock.Any()).Return(false, nil)
+
+		resp, err := NewServer().PostApiRecipesRecipeIDIngredientsIngredientIDStockImage(
+			stockImageTestContext(mockDB, nil), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PostApiRecipesRecipeIDIngredientsIngredientIDStockImage404JSONResponse)
+		if !ok || v.Code != apiError.RecipeNotFound.String() {
+			t.Errorf("expected recipe not found, got %+v", resp)
+		}
+	})
+}
@@ -0,0 +1,471 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/tagging"
+)
+
+func (Server) GetApiRecipesRecipeIDTags(ctx context.Context,
+	request GetApiRecipesRecipeIDTagsRequestObject) (
+	GetApiRecipesRecipeIDTagsResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return GetApiRecipesRecipeIDTags500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Check ownership
+	env.Logger.DebugContext(ctx, "checking user ownership")
+	ownsRecipe, err := env.Database.CheckRecipeOwnership(ctx, database.CheckRecipeOwnershipParams{
+		ID: request.RecipeID,
+		UserID: pgtype.Int8{
+			Int64: userID,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to check recipe ownership", slog.Any("error", err))
+		return GetApiRecipesRecipeIDTags500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if !ownsRecipe {
+		env.Logger.ErrorContext(ctx, "user does not own recipe")
+		return GetApiRecipesRecipeIDTags404JSONResponse{
+			Status:  apiError.RecipeNotFound.StatusCode(),
+			Code:    apiError.RecipeNotFound.String(),
+			Message: "recipe does not exist or user does not own it",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get tags
+	env.Logger.DebugContext(ctx, "getting recipe tags")
+	tags, err := env.Database.GetRecipeTags(ctx, request.RecipeID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get recipe tags", slog.Any("error", err))
+		return GetApiRecipesRecipeIDTags500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return GetApiRecipesRecipeIDTags200JSONResponse{
+		Tags: tags,
+	}, nil
+}
+
+func (Server) GetApiRecipesRecipeIDTagSuggestions(ctx context.Context,
+	request GetApiRecipesRecipeIDTagSuggestionsRequestObject) (
+	GetApiRecipesRecipeIDTagSuggestionsResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return GetApiRecipesRecipeIDTagSuggestions500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Check ownership
+	env.Logger.DebugContext(ctx, "checking user ownership")
+	ownsRecipe, err := env.Database.CheckRecipeOwnership(ctx, database.CheckRecipeOwnershipParams{
+		ID: request.RecipeID,
+		UserID: pgtype.Int8{
+			Int64: userID,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to check recipe ownership", slog.Any("error", err))
+		return GetApiRecipesRecipeIDTagSuggestions500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if !ownsRecipe {
+		env.Logger.ErrorContext(ctx, "user does not own recipe")
+		return GetApiRecipesRecipeIDTagSuggestions404JSONResponse{
+			Status:  apiError.RecipeNotFound.StatusCode(),
+			Code:    apiError.RecipeNotFound.String(),
+			Message: "recipe does not exist or user does not own it",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get suggestions
+	env.Logger.DebugContext(ctx, "getting tag suggestions")
+	rows, err := env.Database.GetRecipeTagSuggestions(ctx, request.RecipeID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get tag suggestions", slog.Any("error", err))
+		return GetApiRecipesRecipeIDTagSuggestions500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	suggestions := make([]TagSuggestion, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, TagSuggestion{
+			Tag:   row.Tag,
+			Score: row.Score,
+		})
+	}
+
+	return GetApiRecipesRecipeIDTagSuggestions200JSONResponse{
+		Suggestions: suggestions,
+	}, nil
+}
+
+func (Server) PostApiRecipesRecipeIDTagSuggestions(ctx context.Context,
+	request PostApiRecipesRecipeIDTagSuggestionsRequestObject) (
+	PostApiRecipesRecipeIDTagSuggestionsResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiRecipesRecipeIDTagSuggestions500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Check ownership
+	env.Logger.DebugContext(ctx, "checking user ownership")
+	ownsRecipe, err := env.Database.CheckRecipeOwnership(ctx, database.CheckRecipeOwnershipParams{
+		ID: request.RecipeID,
+		UserID: pgtype.Int8{
+			Int64: userID,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to check recipe ownership", slog.Any("error", err))
+		return PostApiRecipesRecipeIDTagSuggestions500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if !ownsRecipe {
+		env.Logger.ErrorContext(ctx, "user does not own recipe")
+		return PostApiRecipesRecipeIDTagSuggestions404JSONResponse{
+			Status:  apiError.RecipeNotFound.StatusCode(),
+			Code:    apiError.RecipeNotFound.String(),
+			Message: "recipe does not exist or user does not own it",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get suggestions
+	env.Logger.DebugContext(ctx, "getting tag suggestions")
+	rows, err := env.Database.GetRecipeTagSuggestions(ctx, request.RecipeID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get tag suggestions", slog.Any("error", err))
+		return PostApiRecipesRecipeIDTagSuggestions500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	suggested := make(map[string]bool, len(rows))
+	accepted := make([]string, 0, len(rows))
+	for _, row := range rows {
+		suggested[row.Tag] = true
+		accepted = append(accepted, row.Tag)
+	}
+
+	// Select accepted tags
+	if request.Body != nil && request.Body.Tags != nil {
+		accepted = accepted[:0]
+		for _, tag := range *request.Body.Tags {
+			tag = tagging.NormalizeTag(tag)
+			if !suggested[tag] {
+				env.Logger.ErrorContext(ctx, "tag was not suggested", slog.String("tag", tag))
+				return PostApiRecipesRecipeIDTagSuggestions400JSONResponse{
+					Status:  apiError.BadRequest.StatusCode(),
+					Code:    apiError.BadRequest.String(),
+					Message: fmt.Sprintf("tag %q was not suggested for this recipe", tag),
+					ErrorId: requestID,
+				}, nil
+			}
+			accepted = append(accepted, tag)
+		}
+	}
+
+	// Attach tags
+	env.Logger.DebugContext(ctx, "attaching tags")
+	err = env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		for _, tag := range accepted {
+			if err := tx.AddRecipeTag(ctx, database.AddRecipeTagParams{
+				RecipeID: request.RecipeID,
+				Tag:      tag,
+			}); err != nil {
+				return fmt.Errorf("adding tag %q: %w", tag, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to add recipe tags", slog.Any("error", err))
+		return PostApiRecipesRecipeIDTagSuggestions500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Clear suggestions
+	env.Logger.DebugContext(ctx, "clearing tag suggestions")
+	if err := env.Database.DeleteRecipeTagSuggestions(ctx, request.RecipeID); err != nil {
+		env.Logger.WarnContext(ctx, "failed to clear tag suggestions", slog.Any("error", err))
+	}
+
+	// Get tags
+	env.Logger.DebugContext(ctx, "getting recipe tags")
+	tags, err := env.Database.GetRecipeTags(ctx, request.RecipeID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get recipe tags", slog.Any("error", err))
+		return PostApiRecipesRecipeIDTagSuggestions500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return PostApiRecipesRecipeIDTagSuggestions200JSONResponse{
+		Tags: tags,
+	}, nil
+}
+
+func (Server) PatchApiTagsTag(ctx context.Context,
+	request PatchApiTagsTagRequestObject) (
+	PatchApiTagsTagResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PatchApiTagsTag500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	tag := tagging.NormalizeTag(request.Tag)
+	name := tagging.NormalizeTag(request.Body.Name)
+	if tag == "" || name == "" || name == tag {
+		env.Logger.ErrorContext(ctx, "invalid tag rename", slog.String("tag", tag), slog.String("name", name))
+		return PatchApiTagsTag400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "new name must be non-empty and differ from the tag",
+			ErrorId: requestID,
+		}, nil
+	}
+	owner := pgtype.Int8{Int64: userID, Valid: true}
+
+	// Rename tag if the new name is unused. A rename that adds the name
+	// to a recipe concurrently fails on the recipe_tags primary key.
+	env.Logger.DebugContext(ctx, "renaming tag")
+	renamed, err := env.Database.RenameUserTag(ctx, database.RenameUserTagParams{
+		UserID: owner,
+		Tag:    tag,
+		NewTag: name,
+	})
+	var pgErr *pgconn.PgError
+	if renamed.InUse || (errors.As(err, &pgErr) && pgErr.Code == "23505") {
+		env.Logger.ErrorContext(ctx, "tag name already in use", slog.String("name", name), slog.Any("error", err))
+		return PatchApiTagsTag409JSONResponse{
+			Status:  apiError.TagConflict.StatusCode(),
+			Code:    apiError.TagConflict.String(),
+			Message: fmt.Sprintf("tag %q is already in use", name),
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to rename tag", slog.Any("error", err))
+		return PatchApiTagsTag500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if renamed.Recipes == 0 {
+		env.Logger.ErrorContext(ctx, "tag not found", slog.String("tag", tag))
+		return PatchApiTagsTag404JSONResponse{
+			Status:  apiError.TagNotFound.StatusCode(),
+			Code:    apiError.TagNotFound.String(),
+			Message: fmt.Sprintf("no recipe has tag %q", tag),
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PatchApiTagsTag200JSONResponse{
+		Tag:     name,
+		Recipes: renamed.Recipes,
+	}, nil
+}
+
+func (Server) PostApiTagsTagMerge(ctx context.Context,
+	request PostApiTagsTagMergeRequestObject) (
+	PostApiTagsTagMergeResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiTagsTagMerge500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	tag := tagging.NormalizeTag(request.Tag)
+	into := tagging.NormalizeTag(request.Body.Into)
+	if tag == "" || into == "" || into == tag {
+		env.Logger.ErrorContext(ctx, "invalid tag merge", slog.String("tag", tag), slog.String("into", into))
+		return PostApiTagsTagMerge400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "target tag must be non-empty and differ from the tag",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Merge tags
+	env.Logger.DebugContext(ctx, "merging tags")
+	moved, err := env.Database.MoveUserTag(ctx, database.MoveUserTagParams{
+		UserID: pgtype.Int8{Int64: userID, Valid: true},
+		Tag:    tag,
+		NewTag: into,
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to merge tags", slog.Any("error", err))
+		return PostApiTagsTagMerge500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if moved.Recipes == 0 {
+		env.Logger.ErrorContext(ctx, "tag not found", slog.String("tag", tag))
+		return PostApiTagsTagMerge404JSONResponse{
+			Status:  apiError.TagNotFound.StatusCode(),
+			Code:    apiError.TagNotFound.String(),
+			Message: fmt.Sprintf("no recipe has tag %q", tag),
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PostApiTagsTagMerge200JSONResponse{
+		Tag:     into,
+		Recipes: moved.Recipes,
+		Merged:  moved.Merged,
+	}, nil
+}
+
+func (Server) DeleteApiTagsTag(ctx context.Context,
+	request DeleteApiTagsTagRequestObject) (
+	DeleteApiTagsTagResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return DeleteApiTagsTag500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Detach tag
+	tag := tagging.NormalizeTag(request.Tag)
+	env.Logger.DebugContext(ctx, "deleting tag")
+	deleted, err := env.Database.DeleteUserTag(ctx, database.DeleteUserTagParams{
+		UserID: pgtype.Int8{Int64: userID, Valid: true},
+		Tag:    tag,
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to delete tag", slog.Any("error", err))
+		return DeleteApiTagsTag500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if deleted == 0 {
+		env.Logger.ErrorContext(ctx, "tag not found", slog.String("tag", tag))
+		return DeleteApiTagsTag404JSONResponse{
+			Status:  apiError.TagNotFound.StatusCode(),
+			Code:    apiError.TagNotFound.String(),
+			Message: fmt.Sprintf("no recipe has tag %q", tag),
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return DeleteApiTagsTag200JSONResponse{
+		Tag:     tag,
+		Recipes: deleted,
+	}, nil
+}
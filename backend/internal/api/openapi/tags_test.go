@@ -0,0 +1,495 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func TestGetApiRecipesRecipeIDTagSuggestions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	server := NewServer()
+
+	tests := []struct {
+		name            string
+		recipeID        int64
+		setup           func()
+		wantStatus      int
+		wantCode        string
+		wantSuggestions []TagSuggestion
+	}{
+		{
+			name:     "returns stored suggestions",
+			recipeID: 1,
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), database.CheckRecipeOwnershipParams{
+						ID:     1,
+						UserID: pgtype.Int8{Int64: 42, Valid: true},
+					}).
+					Return(true, nil)
+				mockDB.EXPECT().
+					GetRecipeTagSuggestions(gomock.Any(), int64(1)).
+					Return([]database.GetRecipeTagSuggestionsRow{
+						{Tag: "soup", Score: 3},
+						{Tag: "chicken", Score: 1},
+					}, nil)
+			},
+			wantStatus: 200,
+			wantSuggestions: []TagSuggestion{
+				{Tag: "soup", Score: 3},
+				{Tag: "chicken", Score: 1},
+			},
+		},
+		{
+			name:     "no suggestions",
+			recipeID: 2,
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+				mockDB.EXPECT().
+					GetRecipeTagSuggestions(gomock.Any(), int64(2)).
+					Return(nil, nil)
+			},
+			wantStatus:      200,
+			wantSuggestions: []TagSuggestion{},
+		},
+		{
+			name:     "recipe not owned",
+			recipeID: 3,
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.RecipeNotFound.String(),
+		},
+		{
+			name:     "database error getting suggestions",
+			recipeID: 4,
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+				mockDB.EXPECT().
+					GetRecipeTagSuggestions(gomock.Any(), int64(4)).
+					Return(nil, errors.New("database error"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			ctx := context.Background()
+			ctx = requestid.InjectRequestID(ctx, 12345)
+			ctx = token.UserIDWithCtx(ctx, 42)
+			ctx = env.WithCtx(ctx, &env.Env{
+				Logger:   log.NullLogger(),
+				Database: mockDB,
+			})
+
+			resp, err := server.GetApiRecipesRecipeIDTagSuggestions(ctx,
+				GetApiRecipesRecipeIDTagSuggestionsRequestObject{RecipeID: tt.recipeID})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case GetApiRecipesRecipeIDTagSuggestions200JSONResponse:
+				if tt.wantStatus != 200 {
+					t.Errorf("expected status %d, got 200", tt.wantStatus)
+				}
+				if !reflect.DeepEqual(v.Suggestions, tt.wantSuggestions) {
+					t.Errorf("expected suggestions %+v, got %+v", tt.wantSuggestions, v.Suggestions)
+				}
+			case GetApiRecipesRecipeIDTagSuggestions404JSONResponse:
+				if tt.wantStatus != 404 {
+					t.Errorf("expected status %d, got 404", tt.wantStatus)
+				}
+				if v.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, v.Code)
+				}
+			case GetApiRecipesRecipeIDTagSuggestions500JSONResponse:
+				if tt.wantStatus != 500 {
+					t.Errorf("expected status %d, got 500", tt.wantStatus)
+				}
+				if v.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, v.Code)
+				}
+			default:
+				t.Errorf("unexpected response type: %T", v)
+			}
+		})
+	}
+}
+
+func TestPostApiRecipesRecipeIDTagSuggestions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	server := NewServer()
+
+	suggestions := []database.GetRecipeTagSuggestionsRow{
+		{Tag: "soup", Score: 3},
+		{Tag: "chicken", Score: 1},
+	}
+
+	tests := []struct {
+		name       string
+		body       *PostApiRecipesRecipeIDTagSuggestionsJSONRequestBody
+		setup      func()
+		wantStatus int
+		wantCode   string
+		wantTags   []string
+	}{
+		{
+			name: "accepts all suggestions without a body",
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+				mockDB.EXPECT().
+					GetRecipeTagSuggestions(gomock.Any(), int64(1)).
+					Return(suggestions, nil)
+				mockDB.EXPECT().
+					AddRecipeTag(gomock.Any(), database.AddRecipeTagParams{RecipeID: 1, Tag: "soup"}).
+					Return(nil)
+				mockDB.EXPECT().
+					AddRecipeTag(gomock.Any(), database.AddRecipeTagParams{RecipeID: 1, Tag: "chicken"}).
+					Return(nil)
+				mockDB.EXPECT().
+					DeleteRecipeTagSuggestions(gomock.Any(), int64(1)).
+					Return(nil)
+				mockDB.EXPECT().
+					GetRecipeTags(gomock.Any(), int64(1)).
+					Return([]string{"chicken", "soup"}, nil)
+			},
+			wantStatus: 200,
+			wantTags:   []string{"chicken", "soup"},
+		},
+		{
+			name: "accepts a subset of suggestions",
+			body: &PostApiRecipesRecipeIDTagSuggestionsJSONRequestBody{
+				Tags: &[]string{" Soup "},
+			},
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+				mockDB.EXPECT().
+					GetRecipeTagSuggestions(gomock.Any(), int64(1)).
+					Return(suggestions, nil)
+				mockDB.EXPECT().
+					AddRecipeTag(gomock.Any(), database.AddRecipeTagParams{RecipeID: 1, Tag: "soup"}).
+					Return(nil)
+				mockDB.EXPECT().
+					DeleteRecipeTagSuggestions(gomock.Any(), int64(1)).
+					Return(nil)
+				mockDB.EXPECT().
+					GetRecipeTags(gomock.Any(), int64(1)).
+					Return([]string{"soup"}, nil)
+			},
+			wantStatus: 200,
+			wantTags:   []string{"soup"},
+		},
+		{
+			name: "rejects tags that were not suggested",
+			body: &PostApiRecipesRecipeIDTagSuggestionsJSONRequestBody{
+				Tags: &[]string{"dessert"},
+			},
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+				mockDB.EXPECT().
+					GetRecipeTagSuggestions(gomock.Any(), int64(1)).
+					Return(suggestions, nil)
+			},
+			wantStatus: 400,
+			wantCode:   apiError.BadRequest.String(),
+		},
+		{
+			name: "recipe not owned",
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.RecipeNotFound.String(),
+		},
+		{
+			name: "database error adding tag",
+			setup: func() {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+				mockDB.EXPECT().
+					GetRecipeTagSuggestions(gomock.Any(), int64(1)).
+					Return(suggestions, nil)
+				mockDB.EXPECT().
+					AddRecipeTag(gomock.Any(), gomock.Any()).
+					Return(errors.New("database error"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			ctx := context.Background()
+			ctx = requestid.InjectRequestID(ctx, 12345)
+			ctx = token.UserIDWithCtx(ctx, 42)
+			ctx = env.WithCtx(ctx, &env.Env{
+				Logger:   log.NullLogger(),
+				Database: mockDB,
+			})
+
+			resp, err := server.PostApiRecipesRecipeIDTagSuggestions(ctx,
+				PostApiRecipesRecipeIDTagSuggestionsRequestObject{RecipeID: 1, Body: tt.body})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case PostApiRecipesRecipeIDTagSuggestions200JSONResponse:
+				if tt.wantStatus != 200 {
+					t.Errorf("expected status %d, got 200", tt.wantStatus)
+				}
+				if !reflect.DeepEqual(v.Tags, tt.wantTags) {
+					t.Errorf("expected tags %v, got %v", tt.wantTags, v.Tags)
+				}
+			case PostApiRecipesRecipeIDTagSuggestions400JSONResponse:
+				if tt.wantStatus != 400 {
+					t.Errorf("expected status %d, got 400", tt.wantStatus)
+				}
+				if v.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, v.Code)
+				}
+			case PostApiRecipesRecipeIDTagSuggestions404JSONResponse:
+				if tt.wantStatus != 404 {
+					t.Errorf("expected status %d, got 404", tt.wantStatus)
+				}
+				if v.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, v.Code)
+				}
+			case PostApiRecipesRecipeIDTagSuggestions500JSONResponse:
+				if tt.wantStatus != 500 {
+					t.Errorf("expected status %d, got 500", tt.wantStatus)
+				}
+				if v.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, v.Code)
+				}
+			default:
+				t.Errorf("unexpected response type: %T", v)
+			}
+		})
+	}
+}
+
+func tagsTestContext(mockDB database.Querier) context.Context {
+	ctx := context.Background()
+	ctx = requestid.InjectRequestID(ctx, 12345)
+	ctx = token.UserIDWithCtx(ctx, 42)
+	return env.WithCtx(ctx, &env.Env{
+		Logger:   log.NullLogger(),
+		Database: mockDB,
+	})
+}
+
+func TestPatchApiTagsTag(t *testing.T) {
+	owner := pgtype.Int8{Int64: 42, Valid: true}
+
+	t.Run("renames tag", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			RenameUserTag(gomock.Any(), database.RenameUserTagParams{UserID: owner, Tag: "quick", NewTag: "weeknight"}).
+			Return(database.RenameUserTagRow{Recipes: 3}, nil)
+
+		resp, err := NewServer().PatchApiTagsTag(tagsTestContext(mockDB), PatchApiTagsTagRequestObject{
+			Tag:  "Quick",
+			Body: &PatchApiTagsTagJSONRequestBody{Name: " Weeknight "},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := PatchApiTagsTag200JSONResponse{Tag: "weeknight", Recipes: 3}
+		if resp != want {
+			t.Errorf("expected %+v, got %+v", want, resp)
+		}
+	})
+
+	t.Run("name in use", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			RenameUserTag(gomock.Any(), gomock.Any()).
+			Return(database.RenameUserTagRow{InUse: true}, nil)
+
+		resp, err := NewServer().PatchApiTagsTag(tagsTestContext(mockDB), PatchApiTagsTagRequestObject{
+			Tag:  "quick",
+			Body: &PatchApiTagsTagJSONRequestBody{Name: "weeknight"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v, ok := resp.(PatchApiTagsTag409JSONResponse); !ok || v.Code != apiError.TagConflict.String() {
+			t.Errorf("expected tag_conflict, got %+v", resp)
+		}
+	})
+
+	t.Run("name added concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			RenameUserTag(gomock.Any(), gomock.Any()).
+			Return(database.RenameUserTagRow{}, &pgconn.PgError{Code: "23505"})
+
+		resp, err := NewServer().PatchApiTagsTag(tagsTestContext(mockDB), PatchApiTagsTagRequestObject{
+			Tag:  "quick",
+			Body: &PatchApiTagsTagJSONRequestBody{Name: "weeknight"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v, ok := resp.(PatchApiTagsTag409JSONResponse); !ok || v.Code != apiError.TagConflict.String() {
+			t.Errorf("expected tag_conflict, got %+v", resp)
+		}
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+
+		resp, err := NewServer().PatchApiTagsTag(tagsTestContext(mockDB), PatchApiTagsTagRequestObject{
+			Tag:  "quick",
+			Body: &PatchApiTagsTagJSONRequestBody{Name: "QUICK"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := resp.(PatchApiTagsTag400JSONResponse); !ok {
+			t.Errorf("expected 400, got %T", resp)
+		}
+	})
+
+	t.Run("tag not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			RenameUserTag(gomock.Any(), gomock.Any()).
+			Return(database.RenameUserTagRow{}, nil)
+
+		resp, err := NewServer().PatchApiTagsTag(tagsTestContext(mockDB), PatchApiTagsTagRequestObject{
+			Tag:  "quick",
+			Body: &PatchApiTagsTagJSONRequestBody{Name: "weeknight"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v, ok := resp.(PatchApiTagsTag404JSONResponse); !ok || v.Code != apiError.TagNotFound.String() {
+			t.Errorf("expected tag_not_found, got %+v", resp)
+		}
+	})
+}
+
+func TestPostApiTagsTagMerge(t *testing.T) {
+	t.Run("merges tags", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			MoveUserTag(gomock.Any(), database.MoveUserTagParams{
+				UserID: pgtype.Int8{Int64: 42, Valid: true},
+				Tag:    "veggie",
+				NewTag: "vegetarian",
+			}).
+			Return(database.MoveUserTagRow{Recipes: 4, Merged: 1}, nil)
+
+		resp, err := NewServer().PostApiTagsTagMerge(tagsTestContext(mockDB), PostApiTagsTagMergeRequestObject{
+			Tag:  "veggie",
+			Body: &PostApiTagsTagMergeJSONRequestBody{Into: "vegetarian"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := PostApiTagsTagMerge200JSONResponse{Tag: "vegetarian", Recipes: 4, Merged: 1}
+		if resp != want {
+			t.Errorf("expected %+v, got %+v", want, resp)
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			MoveUserTag(gomock.Any(), gomock.Any()).
+			Return(database.MoveUserTagRow{}, errors.New("database error"))
+
+		resp, err := NewServer().PostApiTagsTagMerge(tagsTestContext(mockDB), PostApiTagsTagMergeRequestObject{
+			Tag:  "veggie",
+			Body: &PostApiTagsTagMergeJSONRequestBody{Into: "vegetarian"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := resp.(PostApiTagsTagMerge500JSONResponse); !ok {
+			t.Errorf("expected 500, got %T", resp)
+		}
+	})
+}
+
+func TestDeleteApiTagsTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().
+		DeleteUserTag(gomock.Any(), database.DeleteUserTagParams{
+			UserID: pgtype.Int8{Int64: 42, Valid: true},
+			Tag:    "quick",
+		}).
+		Return(int64(5), nil)
+	mockDB.EXPECT().
+		DeleteUserTag(gomock.Any(), gomock.Any()).
+		Return(int64(0), nil)
+
+	resp, err := NewServer().DeleteApiTagsTag(tagsTestContext(mockDB), DeleteApiTagsTagRequestObject{Tag: "quick"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (DeleteApiTagsTag200JSONResponse{Tag: "quick", Recipes: 5}); resp != want {
+		t.Errorf("expected %+v, got %+v", want, resp)
+	}
+
+	resp, err = NewServer().DeleteApiTagsTag(tagsTestContext(mockDB), DeleteApiTagsTagRequestObject{Tag: "quick"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := resp.(DeleteApiTagsTag404JSONResponse); !ok || v.Code != apiError.TagNotFound.String() {
+		t.Errorf("expected tag_not_found, got %+v", resp)
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/undo"
+)
+
+func (Server) PostApiUndoToken(ctx context.Context,
+	request PostApiUndoTokenRequestObject) (
+	PostApiUndoTokenResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiUndoToken400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Restore deletion
+	env.Logger.DebugContext(ctx, "restoring deletion")
+	restored, err := undo.Restore(ctx, env, userID, request.Token)
+	if errors.Is(err, undo.ErrNotFound) {
+		env.Logger.ErrorContext(ctx, "undo token not found", slog.Any("error", err))
+		return PostApiUndoToken404JSONResponse{
+			Status:  apiError.UndoTokenNotFound.StatusCode(),
+			Code:    apiError.UndoTokenNotFound.String(),
+			Message: "undo token is unknown, expired, or already used",
+			ErrorId: requestID,
+		}, nil
+	}
+	if errors.Is(err, undo.ErrConflict) {
+		env.Logger.ErrorContext(ctx, "deletion can no longer be undone", slog.Any("error", err))
+		return PostApiUndoToken409JSONResponse{
+			Status:  apiError.UndoConflict.StatusCode(),
+			Code:    apiError.UndoConflict.String(),
+			Message: err.Error(),
+			ErrorId: requestID,
+		}, nil
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to restore deletion", slog.Any("error", err))
+		return PostApiUndoToken500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PostApiUndoToken200JSONResponse{
+		Kind:     UndoResultKind(restored.Kind),
+		RecipeId: restored.RecipeID,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func TestPostApiUndoToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		injectUser bool
+		setup      func(mockDB *database.MockQuerier)
+		validate   func(t *testing.T, resp PostApiUndoTokenResponseObject)
+	}{
+		{
+			name:       "restores ingredient",
+			injectUser: true,
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					ConsumeUndoToken(gomock.Any(), gomock.Any()).
+					Return(database.UndoToken{
+						Token:    "abc",
+						UserID:   789,
+						Kind:     "ingredient",
+						RecipeID: 123,
+						Snapshot: []byte(`{"id":456,"description":"2 eggs"}`),
+					}, nil)
+				mockDB.EXPECT().
+					RestoreRecipeIngredient(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			validate: func(t *testing.T, resp PostApiUndoTokenResponseObject) {
+				v, ok := resp.(PostApiUndoToken200JSONResponse)
+				if !ok {
+					t.Fatalf("expected 200 response, got %T", resp)
+				}
+				if v.Kind != UndoResultKindIngredient || v.RecipeId != 123 {
+					t.Errorf("unexpected response %+v", v)
+				}
+			},
+		},
+		{
+			name:       "missing user id in context",
+			injectUser: false,
+			setup:      func(mockDB *database.MockQuerier) {},
+			validate: func(t *testing.T, resp PostApiUndoTokenResponseObject) {
+				if _, ok := resp.(PostApiUndoToken400JSONResponse); !ok {
+					t.Errorf("expected 400 response, got %T", resp)
+				}
+			},
+		},
+		{
+			name:       "unknown token",
+			injectUser: true,
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					ConsumeUndoToken(gomock.Any(), gomock.Any()).
+					Return(database.UndoToken{}, pgx.ErrNoRows)
+			},
+			validate: func(t *testing.T, resp PostApiUndoTokenResponseObject) {
+				v, ok := resp.(PostApiUndoToken404JSONResponse)
+				if !ok {
+					t.Fatalf("expected 404 response, got %T", resp)
+				}
+				if v.Code != apiError.UndoTokenNotFound.String() {
+					t.Errorf("expected code %s, got %s", apiError.UndoTokenNotFound.String(), v.Code)
+				}
+			},
+		},
+		{
+			name:       "recipe deleted",
+			injectUser: true,
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					ConsumeUndoToken(gomock.Any(), gomock.Any()).
+					Return(database.UndoToken{Token: "abc", Kind: "step", RecipeID: 123, Snapshot: []byte(`{}`)}, nil)
+				mockDB.EXPECT().
+					RestoreRecipeStep(gomock.Any(), gomock.Any()).
+					Return(&pgconn.PgError{Code: "23503"})
+			},
+			validate: func(t *testing.T, resp PostApiUndoTokenResponseObject) {
+				v, ok := resp.(PostApiUndoToken409JSONResponse)
+				if !ok {
+					t.Fatalf("expected 409 response, got %T", resp)
+				}
+				if v.Code != apiError.UndoConflict.String() {
+					t.Errorf("expected code %s, got %s", apiError.UndoConflict.String(), v.Code)
+				}
+			},
+		},
+		{
+			name:       "database error",
+			injectUser: true,
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					ConsumeUndoToken(gomock.Any(), gomock.Any()).
+					Return(database.UndoToken{}, errors.New("database error"))
+			},
+			validate: func(t *testing.T, resp PostApiUndoTokenResponseObject) {
+				if _, ok := resp.(PostApiUndoToken500JSONResponse); !ok {
+					t.Errorf("expected 500 response, got %T", resp)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := database.NewMockQuerier(ctrl)
+			tt.setup(mockDB)
+
+			ctx := context.Background()
+			ctx = requestid.InjectRequestID(ctx, 12345)
+			if tt.injectUser {
+				ctx = token.UserIDWithCtx(ctx, 789)
+			}
+			ctx = env.WithCtx(ctx, &env.Env{
+				Logger:   log.NullLogger(),
+				Database: mockDB,
+			})
+
+			resp, err := NewServer().PostApiUndoToken(ctx, PostApiUndoTokenRequestObject{Token: "abc"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.validate(t, resp)
+		})
+	}
+}
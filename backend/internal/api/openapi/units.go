@@ -0,0 +1,380 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/oapi-codegen/nullable"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/audit"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/ingredient"
+	"github.com/matt-dz/wecook/internal/units"
+)
+
+// conversionPrecision is the number of decimal places converted
+// quantities are rounded to.
+const conversionPrecision = 100
+
+func (Server) GetApiDensities(ctx context.Context,
+	request GetApiDensitiesRequestObject) (
+	GetApiDensitiesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Get version
+	env.Logger.DebugContext(ctx, "getting density version")
+	var version database.DensityVersion
+	var err error
+	if request.Params.Version != nil {
+		version, err = env.Database.GetDensityVersion(ctx, *request.Params.Version)
+	} else {
+		version, err = env.Database.GetLatestDensityVersion(ctx)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		env.Logger.ErrorContext(ctx, "density version not found", slog.Any("error", err))
+		return GetApiDensities404JSONResponse{
+			Status:  apiError.DensityVersionNotFound.StatusCode(),
+			Code:    apiError.DensityVersionNotFound.String(),
+			Message: "density version not found",
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get density version", slog.Any("error", err))
+		return GetApiDensities500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get densities
+	env.Logger.DebugContext(ctx, "getting densities", slog.Int64("version", version.ID))
+	rows, err := env.Database.GetDensities(ctx, version.ID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get densities", slog.Any("error", err))
+		return GetApiDensities500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	res := GetApiDensities200JSONResponse{
+		Version:   version.ID,
+		Note:      version.Note,
+		CreatedAt: version.CreatedAt.Time,
+		Densities: make([]Density, 0, len(rows)),
+	}
+	for _, row := range rows {
+		res.Densities = append(res.Densities, Density{
+			Ingredient: row.Ingredient,
+			MlPerGram:  row.MlPerGram,
+		})
+	}
+
+	return res, nil
+}
+
+func (Server) PostApiDensities(ctx context.Context,
+	request PostApiDensitiesRequestObject) (
+	PostApiDensitiesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	actorID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiDensities500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Validate dataset
+	env.Logger.DebugContext(ctx, "validating densities")
+	if request.Body == nil || len(request.Body.Densities) == 0 {
+		return PostApiDensities400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "at least one density is required",
+			ErrorId: requestID,
+		}, nil
+	}
+	params := database.ImportDensitiesParams{
+		CreatedBy:   pgtype.Int8{Int64: actorID, Valid: true},
+		Ingredients: make([]string, 0, len(request.Body.Densities)),
+		MlPerGram:   make([]float64, 0, len(request.Body.Densities)),
+	}
+	if request.Body.Note != nil {
+		params.Note = *request.Body.Note
+	}
+	seen := make(map[string]bool, len(request.Body.Densities))
+	for _, d := range request.Body.Densities {
+		ingredient := units.NormalizeIngredient(d.Ingredient)
+		if ingredient == "" {
+			return PostApiDensities422JSONResponse{
+				Status:  apiError.UnprocessibleEntity.StatusCode(),
+				Code:    apiError.UnprocessibleEntity.String(),
+				Message: "ingredient names must not be blank",
+				ErrorId: requestID,
+			}, nil
+		}
+		if seen[ingredient] {
+			return PostApiDensities422JSONResponse{
+				Status:  apiError.UnprocessibleEntity.StatusCode(),
+				Code:    apiError.UnprocessibleEntity.String(),
+				Message: "ingredient " + strconv.Quote(ingredient) + " is listed more than once",
+				ErrorId: requestID,
+			}, nil
+		}
+		if d.MlPerGram <= 0 || math.IsInf(d.MlPerGram, 0) {
+			return PostApiDensities422JSONResponse{
+				Status:  apiError.UnprocessibleEntity.StatusCode(),
+				Code:    apiError.UnprocessibleEntity.String(),
+				Message: "density of " + strconv.Quote(ingredient) + " must be positive",
+				ErrorId: requestID,
+			}, nil
+		}
+		seen[ingredient] = true
+		params.Ingredients = append(params.Ingredients, ingredient)
+		params.MlPerGram = append(params.MlPerGram, d.MlPerGram)
+	}
+
+	// Import densities
+	env.Logger.DebugContext(ctx, "importing densities", slog.Int("entries", len(params.Ingredients)))
+	row, err := env.Database.ImportDensities(ctx, params)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to import densities", slog.Any("error", err))
+		return PostApiDensities500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Record import
+	env.Logger.DebugContext(ctx, "recording import")
+	if err := audit.Record(ctx, env.Database, audit.Event{
+		ActorID:    actorID,
+		Action:     audit.ActionImportDensities,
+		TargetType: audit.TargetDensityVersion,
+		TargetID:   row.ID,
+		Metadata:   map[string]any{"entries": len(params.Ingredients), "note": params.Note},
+	}); err != nil {
+		env.Logger.WarnContext(ctx, "failed to record audit event", slog.Any("error", err))
+	}
+
+	return PostApiDensities201JSONResponse{
+		Version:   row.ID,
+		Note:      params.Note,
+		CreatedBy: nullable.NewNullableWithValue(actorID),
+		CreatedAt: row.CreatedAt.Time,
+		Entries:   int64(len(params.Ingredients)),
+	}, nil
+}
+
+func (Server) GetApiDensitiesVersions(ctx context.Context,
+	request GetApiDensitiesVersionsRequestObject) (
+	GetApiDensitiesVersionsResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Get versions
+	env.Logger.DebugContext(ctx, "getting density versions")
+	rows, err := env.Database.GetDensityVersions(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get density versions", slog.Any("error", err))
+		return GetApiDensitiesVersions500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	res := GetApiDensitiesVersions200JSONResponse{
+		Versions: make([]DensityVersion, 0, len(rows)),
+	}
+	for _, row := range rows {
+		version := DensityVersion{
+			Version:   row.ID,
+			Note:      row.Note,
+			CreatedAt: row.CreatedAt.Time,
+			Entries:   row.Entries,
+		}
+		if row.CreatedBy.Valid {
+			version.CreatedBy = nullable.NewNullableWithValue(row.CreatedBy.Int64)
+		} else {
+			version.CreatedBy = nullable.NewNullNullable[int64]()
+		}
+		res.Versions = append(res.Versions, version)
+	}
+
+	return res, nil
+}
+
+func (Server) PostApiUnitsConvert(ctx context.Context,
+	request PostApiUnitsConvertRequestObject) (
+	PostApiUnitsConvertResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	if request.Body == nil {
+		return PostApiUnitsConvert400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing request body",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Look up units
+	env.Logger.DebugContext(ctx, "looking up units")
+	from, ok := units.LookupUnit(request.Body.From)
+	if !ok {
+		return PostApiUnitsConvert422JSONResponse{
+			Status:  apiError.UnprocessibleEntity.StatusCode(),
+			Code:    apiError.UnprocessibleEntity.String(),
+			Message: "unknown unit " + strconv.Quote(request.Body.From),
+			ErrorId: requestID,
+		}, nil
+	}
+	to, ok := units.LookupUnit(request.Body.To)
+	if !ok {
+		return PostApiUnitsConvert422JSONResponse{
+			Status:  apiError.UnprocessibleEntity.StatusCode(),
+			Code:    apiError.UnprocessibleEntity.String(),
+			Message: "unknown unit " + strconv.Quote(request.Body.To),
+			ErrorId: requestID,
+		}, nil
+	}
+
+	var ingredient string
+	if request.Body.Ingredient != nil {
+		ingredient = *request.Body.Ingredient
+	}
+	res := PostApiUnitsConvert200JSONResponse{Unit: to.Name}
+
+	// Load densities when converting between volume and mass
+	var densities units.Densities
+	if from.Dimension != to.Dimension {
+		if units.NormalizeIngredient(ingredient) == "" {
+			return PostApiUnitsConvert400JSONResponse{
+				Status:  apiError.BadRequest.StatusCode(),
+				Code:    apiError.BadRequest.String(),
+				Message: "an ingredient is required to convert between volume and mass",
+				ErrorId: requestID,
+			}, nil
+		}
+
+		env.Logger.DebugContext(ctx, "loading latest densities")
+		version, err := env.Database.GetLatestDensityVersion(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			env.Logger.ErrorContext(ctx, "failed to get latest density version", slog.Any("error", err))
+			return PostApiUnitsConvert500JSONResponse{
+				Status:  apiError.InternalServerError.StatusCode(),
+				Code:    apiError.InternalServerError.String(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+		if err == nil {
+			rows, err := env.Database.GetDensities(ctx, version.ID)
+			if err != nil {
+				env.Logger.ErrorContext(ctx, "failed to get densities", slog.Any("error", err))
+				return PostApiUnitsConvert500JSONResponse{
+					Status:  apiError.InternalServerError.StatusCode(),
+					Code:    apiError.InternalServerError.String(),
+					Message: "Internal Server Error",
+					ErrorId: requestID,
+				}, nil
+			}
+			densities = make(units.Densities, len(rows))
+			for _, row := range rows {
+				densities[row.Ingredient] = row.MlPerGram
+			}
+			res.DensityVersion = &version.ID
+		}
+	}
+
+	// Convert
+	env.Logger.DebugContext(ctx, "converting quantity")
+	quantity, err := units.Convert(request.Body.Quantity, from.Name, to.Name, ingredient, densities)
+	if errors.Is(err, units.ErrNoDensity) {
+		return PostApiUnitsConvert422JSONResponse{
+			Status:  apiError.UnprocessibleEntity.StatusCode(),
+			Code:    apiError.UnprocessibleEntity.String(),
+			Message: "no density known for " + strconv.Quote(ingredient),
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to convert quantity", slog.Any("error", err))
+		return PostApiUnitsConvert500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	res.Quantity = math.Round(quantity*conversionPrecision) / conversionPrecision
+
+	return res, nil
+}
+
+func (Server) PostApiIngredientsFormat(ctx context.Context,
+	request PostApiIngredientsFormatRequestObject) (
+	PostApiIngredientsFormatResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	if request.Body == nil || len(request.Body.Ingredients) == 0 {
+		return PostApiIngredientsFormat400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "at least one ingredient is required",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Format ingredients
+	locale := ingredient.English
+	if request.Body.Locale != nil {
+		locale = ingredient.Locale(*request.Body.Locale)
+	}
+	env.Logger.DebugContext(ctx, "formatting ingredients", slog.String("locale", string(locale)))
+	res := PostApiIngredientsFormat200JSONResponse{
+		Language: string(ingredient.Resolve(locale)),
+		Lines:    make([]string, 0, len(request.Body.Ingredients)),
+	}
+	for _, line := range request.Body.Ingredients {
+		formatted := ingredient.Line{Quantity: line.Quantity, Name: line.Name}
+		if line.Unit != nil {
+			formatted.Unit = *line.Unit
+		}
+		if line.Note != nil {
+			formatted.Note = *line.Note
+		}
+		res.Lines = append(res.Lines, ingredient.Format(formatted, locale))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,487 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func unitsTestContext(mockDB database.Querier) context.Context {
+	ctx := context.Background()
+	ctx = requestid.InjectRequestID(ctx, 12345)
+	ctx = token.UserIDWithCtx(ctx, 42)
+	return env.WithCtx(ctx, &env.Env{
+		Logger:   log.NullLogger(),
+		Database: mockDB,
+	})
+}
+
+func TestGetApiDensities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	server := NewServer()
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	version := database.DensityVersion{
+		ID:        3,
+		Note:      "Fix sugar",
+		CreatedAt: pgtype.Timestamptz{Time: created, Valid: true},
+	}
+	three := int64(3)
+
+	tests := []struct {
+		name        string
+		version     *int64
+		setup       func()
+		wantStatus  int
+		wantCode    string
+		wantEntries int
+	}{
+		{
+			name: "exports latest version",
+			setup: func() {
+				mockDB.EXPECT().GetLatestDensityVersion(gomock.Any()).Return(version, nil)
+				mockDB.EXPECT().GetDensities(gomock.Any(), int64(3)).Return([]database.GetDensitiesRow{
+					{Ingredient: "flour", MlPerGram: 1.89},
+					{Ingredient: "sugar", MlPerGram: 1.18},
+				}, nil)
+			},
+			wantStatus:  200,
+			wantEntries: 2,
+		},
+		{
+			name:    "exports requested version",
+			version: &three,
+			setup: func() {
+				mockDB.EXPECT().GetDensityVersion(gomock.Any(), int64(3)).Return(version, nil)
+				mockDB.EXPECT().GetDensities(gomock.Any(), int64(3)).Return([]database.GetDensitiesRow{
+					{Ingredient: "flour", MlPerGram: 1.89},
+				}, nil)
+			},
+			wantStatus:  200,
+			wantEntries: 1,
+		},
+		{
+			name:    "version not found",
+			version: &three,
+			setup: func() {
+				mockDB.EXPECT().GetDensityVersion(gomock.Any(), int64(3)).Return(database.DensityVersion{}, pgx.ErrNoRows)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.DensityVersionNotFound.String(),
+		},
+		{
+			name: "database error",
+			setup: func() {
+				mockDB.EXPECT().GetLatestDensityVersion(gomock.Any()).Return(version, nil)
+				mockDB.EXPECT().GetDensities(gomock.Any(), int64(3)).Return(nil, errors.New("db down"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			resp, err := server.GetApiDensities(unitsTestContext(mockDB), GetApiDensitiesRequestObject{
+				Params: GetApiDensitiesParams{Version: tt.version},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case GetApiDensities200JSONResponse:
+				if tt.wantStatus != 200 {
+					t.Errorf("expected status %d, got 200", tt.wantStatus)
+				}
+				if v.Version != 3 || v.Note != "Fix sugar" || !v.CreatedAt.Equal(created) {
+					t.Errorf("unexpected version %+v", v)
+				}
+				if len(v.Densities) != tt.wantEntries {
+					t.Errorf("expected %d densities, got %d", tt.wantEntries, len(v.Densities))
+				}
+			case GetApiDensities404JSONResponse:
+				if tt.wantStatus != 404 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 404 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case GetApiDensities500JSONResponse:
+				if tt.wantStatus != 500 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 500 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			default:
+				t.Fatalf("unexpected response type %T", resp)
+			}
+		})
+	}
+}
+
+func TestPostApiDensities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	server := NewServer()
+	note := "Fix sugar"
+
+	tests := []struct {
+		name       string
+		body       *DensityImport
+		setup      func()
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "imports new version",
+			body: &DensityImport{Note: &note, Densities: []Density{
+				{Ingredient: " Brown  Sugar ", MlPerGram: 1.08},
+				{Ingredient: "flour", MlPerGram: 1.89},
+			}},
+			setup: func() {
+				mockDB.EXPECT().
+					ImportDensities(gomock.Any(), database.ImportDensitiesParams{
+						Note:        "Fix sugar",
+						CreatedBy:   pgtype.Int8{Int64: 42, Valid: true},
+						Ingredients: []string{"brown sugar", "flour"},
+						MlPerGram:   []float64{1.08, 1.89},
+					}).
+					Return(database.ImportDensitiesRow{ID: 4}, nil)
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, arg database.CreateAuditEventParams) (int64, error) {
+						if arg.Action != "densities.import" || arg.TargetID.Int64 != 4 {
+							t.Errorf("unexpected audit event %+v", arg)
+						}
+						return 1, nil
+					})
+			},
+			wantStatus: 201,
+		},
+		{
+			name:       "empty dataset",
+			body:       &DensityImport{},
+			setup:      func() {},
+			wantStatus: 400,
+			wantCode:   apiError.BadRequest.String(),
+		},
+		{
+			name: "duplicate ingredient after normalization",
+			body: &DensityImport{Densities: []Density{
+				{Ingredient: "Flour", MlPerGram: 1.89},
+				{Ingredient: "flour", MlPerGram: 1.9},
+			}},
+			setup:      func() {},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name:       "blank ingredient",
+			body:       &DensityImport{Densities: []Density{{Ingredient: "   ", MlPerGram: 1}}},
+			setup:      func() {},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name:       "non-positive density",
+			body:       &DensityImport{Densities: []Density{{Ingredient: "flour", MlPerGram: 0}}},
+			setup:      func() {},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name: "database error",
+			body: &DensityImport{Densities: []Density{{Ingredient: "flour", MlPerGram: 1.89}}},
+			setup: func() {
+				mockDB.EXPECT().
+					ImportDensities(gomock.Any(), gomock.Any()).
+					Return(database.ImportDensitiesRow{}, errors.New("db down"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			resp, err := server.PostApiDensities(unitsTestContext(mockDB), PostApiDensitiesRequestObject{Body: tt.body})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case PostApiDensities201JSONResponse:
+				if tt.wantStatus != 201 {
+					t.Errorf("expected status %d, got 201", tt.wantStatus)
+				}
+				if v.Version != 4 || v.Entries != 2 || v.Note != note {
+					t.Errorf("unexpected version %+v", v)
+				}
+				if createdBy, err := v.CreatedBy.Get(); err != nil || createdBy != 42 {
+					t.Errorf("expected created_by 42, got %v (%v)", createdBy, err)
+				}
+			case PostApiDensities400JSONResponse:
+				if tt.wantStatus != 400 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 400 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiDensities422JSONResponse:
+				if tt.wantStatus != 422 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 422 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiDensities500JSONResponse:
+				if tt.wantStatus != 500 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 500 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			default:
+				t.Fatalf("unexpected response type %T", resp)
+			}
+		})
+	}
+}
+
+func TestGetApiDensitiesVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	server := NewServer()
+
+	mockDB.EXPECT().GetDensityVersions(gomock.Any()).Return([]database.GetDensityVersionsRow{
+		{ID: 2, Note: "Fix sugar", CreatedBy: pgtype.Int8{Int64: 42, Valid: true}, Entries: 26},
+		{ID: 1, Note: "Initial dataset", Entries: 26},
+	}, nil)
+
+	resp, err := server.GetApiDensitiesVersions(unitsTestContext(mockDB), GetApiDensitiesVersionsRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, ok := resp.(GetApiDensitiesVersions200JSONResponse)
+	if !ok {
+		t.Fatalf("unexpected response type %T", resp)
+	}
+	if len(v.Versions) != 2 || v.Versions[0].Version != 2 || v.Versions[1].Entries != 26 {
+		t.Fatalf("unexpected versions %+v", v.Versions)
+	}
+	if createdBy, err := v.Versions[0].CreatedBy.Get(); err != nil || createdBy != 42 {
+		t.Errorf("expected created_by 42, got %v (%v)", createdBy, err)
+	}
+	if !v.Versions[1].CreatedBy.IsNull() {
+		t.Error("expected null created_by for the initial dataset")
+	}
+}
+
+func TestPostApiUnitsConvert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := database.NewMockQuerier(ctrl)
+	server := NewServer()
+	flour := "All-Purpose Flour"
+	saffron := "saffron"
+
+	expectDensities := func() {
+		mockDB.EXPECT().GetLatestDensityVersion(gomock.Any()).Return(database.DensityVersion{ID: 2}, nil)
+		mockDB.EXPECT().GetDensities(gomock.Any(), int64(2)).Return([]database.GetDensitiesRow{
+			{Ingredient: "all-purpose flour", MlPerGram: 1.89},
+		}, nil)
+	}
+
+	tests := []struct {
+		name            string
+		body            *ConversionRequest
+		setup           func()
+		wantStatus      int
+		wantCode        string
+		wantQuantity    float64
+		wantUnit        string
+		wantDensityUsed bool
+	}{
+		{
+			name:         "volume to volume skips densities",
+			body:         &ConversionRequest{Quantity: 3, From: "teaspoons", To: "tablespoon"},
+			setup:        func() {},
+			wantStatus:   200,
+			wantQuantity: 1,
+			wantUnit:     "tbsp",
+		},
+		{
+			name:            "volume to mass uses latest densities",
+			body:            &ConversionRequest{Quantity: 2, From: "cups", To: "grams", Ingredient: &flour},
+			setup:           expectDensities,
+			wantStatus:      200,
+			wantQuantity:    250.36,
+			wantUnit:        "g",
+			wantDensityUsed: true,
+		},
+		{
+			name:       "unknown unit",
+			body:       &ConversionRequest{Quantity: 1, From: "pinch", To: "g"},
+			setup:      func() {},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name:       "missing ingredient",
+			body:       &ConversionRequest{Quantity: 1, From: "cup", To: "g"},
+			setup:      func() {},
+			wantStatus: 400,
+			wantCode:   apiError.BadRequest.String(),
+		},
+		{
+			name:       "unknown density",
+			body:       &ConversionRequest{Quantity: 1, From: "cup", To: "g", Ingredient: &saffron},
+			setup:      expectDensities,
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name: "no dataset",
+			body: &ConversionRequest{Quantity: 1, From: "cup", To: "g", Ingredient: &flour},
+			setup: func() {
+				mockDB.EXPECT().GetLatestDensityVersion(gomock.Any()).Return(database.DensityVersion{}, pgx.ErrNoRows)
+			},
+			wantStatus: 422,
+			wantCode:   apiError.UnprocessibleEntity.String(),
+		},
+		{
+			name: "database error",
+			body: &ConversionRequest{Quantity: 1, From: "cup", To: "g", Ingredient: &flour},
+			setup: func() {
+				mockDB.EXPECT().GetLatestDensityVersion(gomock.Any()).Return(database.DensityVersion{}, errors.New("db down"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			resp, err := server.PostApiUnitsConvert(unitsTestContext(mockDB), PostApiUnitsConvertRequestObject{Body: tt.body})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case PostApiUnitsConvert200JSONResponse:
+				if tt.wantStatus != 200 {
+					t.Errorf("expected status %d, got 200", tt.wantStatus)
+				}
+				if v.Quantity != tt.wantQuantity || v.Unit != tt.wantUnit {
+					t.Errorf("expected %v %s, got %v %s", tt.wantQuantity, tt.wantUnit, v.Quantity, v.Unit)
+				}
+				if (v.DensityVersion != nil) != tt.wantDensityUsed {
+					t.Errorf("expected density version used %v, got %v", tt.wantDensityUsed, v.DensityVersion)
+				}
+			case PostApiUnitsConvert400JSONResponse:
+				if tt.wantStatus != 400 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 400 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiUnitsConvert422JSONResponse:
+				if tt.wantStatus != 422 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 422 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiUnitsConvert500JSONResponse:
+				if tt.wantStatus != 500 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 500 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			default:
+				t.Fatalf("unexpected response type %T", resp)
+			}
+		})
+	}
+}
+
+func TestPostApiIngredientsFormat(t *testing.T) {
+	server := NewServer()
+	quantity := 1.5
+	cups := "cups"
+	sifted := "sifted"
+	austrian := "de-AT"
+
+	tests := []struct {
+		name         string
+		body         *FormatIngredientsRequest
+		wantStatus   int
+		wantLanguage string
+		wantLines    []string
+	}{
+		{
+			name: "english by default",
+			body: &FormatIngredientsRequest{
+				Ingredients: []IngredientLine{
+					{Quantity: &quantity, Unit: &cups, Name: "flour", Note: &sifted},
+					{Name: "salt"},
+				},
+			},
+			wantStatus:   200,
+			wantLanguage: "en",
+			wantLines:    []string{"1½ cups flour, sifted", "salt"},
+		},
+		{
+			name: "regional locale",
+			body: &FormatIngredientsRequest{
+				Locale:      &austrian,
+				Ingredients: []IngredientLine{{Quantity: &quantity, Unit: &cups, Name: "Mehl"}},
+			},
+			wantStatus:   200,
+			wantLanguage: "de",
+			wantLines:    []string{"1,5 Tassen Mehl"},
+		},
+		{
+			name:       "no ingredients",
+			body:       &FormatIngredientsRequest{},
+			wantStatus: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := database.NewMockQuerier(ctrl)
+
+			resp, err := server.PostApiIngredientsFormat(unitsTestContext(mockDB),
+				PostApiIngredientsFormatRequestObject{Body: tt.body})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case PostApiIngredientsFormat200JSONResponse:
+				if tt.wantStatus != 200 {
+					t.Fatalf("expected status %d, got 200", tt.wantStatus)
+				}
+				if v.Language != tt.wantLanguage {
+					t.Errorf("language = %q, want %q", v.Language, tt.wantLanguage)
+				}
+				if !slices.Equal(v.Lines, tt.wantLines) {
+					t.Errorf("lines = %q, want %q", v.Lines, tt.wantLines)
+				}
+			case PostApiIngredientsFormat400JSONResponse:
+				if tt.wantStatus != 400 {
+					t.Fatalf("expected status %d, got 400", tt.wantStatus)
+				}
+			default:
+				t.Fatalf("unexpected response type %T", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/api/version"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/form"
+	"github.com/matt-dz/wecook/internal/upload"
+)
+
+// errUploadTokenUsed is returned when an upload token is used by another
+// request while the image is being attached.
+var errUploadTokenUsed = errors.New("upload token already used or expired")
+
+func (Server) PostApiRecipesRecipeIDUploadUrl(ctx context.Context,
+	request PostApiRecipesRecipeIDUploadUrlRequestObject) (
+	PostApiRecipesRecipeIDUploadUrlResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiRecipesRecipeIDUploadUrl500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Validate request
+	env.Logger.DebugContext(ctx, "validating request")
+	if request.Body == nil || !upload.Target(request.Body.Target).Valid() {
+		return PostApiRecipesRecipeIDUploadUrl400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "target must be one of cover, ingredient, or step",
+			ErrorId: requestID,
+		}, nil
+	}
+	target := upload.Target(request.Body.Target)
+	targetID := request.RecipeID
+	if target != upload.TargetCover {
+		if request.Body.TargetId == nil {
+			return PostApiRecipesRecipeIDUploadUrl400JSONResponse{
+				Status:  apiError.BadRequest.StatusCode(),
+				Code:    apiError.BadRequest.String(),
+				Message: "target_id is required for " + string(target) + " uploads",
+				ErrorId: requestID,
+			}, nil
+		}
+		targetID = *request.Body.TargetId
+	}
+
+	// Check ownership
+	env.Logger.DebugContext(ctx, "checking user ownership")
+	owns, err := ownsUploadTarget(ctx, env.Database, userID, request.RecipeID, target, targetID)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to check ownership", slog.Any("error", err))
+		return PostApiRecipesRecipeIDUploadUrl500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+	if !owns {
+		env.Logger.ErrorContext(ctx, "user does not own upload target")
+		return PostApiRecipesRecipeIDUploadUrl404JSONResponse{
+			Status:  apiError.RecipeNotFound.StatusCode(),
+			Code:    apiError.RecipeNotFound.String(),
+			Message: "recipe/" + string(target) + " does not exist or user does not own recipe",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Delete expired tokens
+	now := env.Now()
+	env.Logger.DebugContext(ctx, "deleting expired upload tokens")
+	if _, err := env.Database.DeleteExpiredUploadTokens(ctx, pgtype.Timestamptz{Time: now, Valid: true}); err != nil {
+		env.Logger.WarnContext(ctx, "failed to delete expired upload tokens", slog.Any("error", err))
+	}
+
+	// Create token
+	env.Logger.DebugContext(ctx, "creating upload token")
+	uploadToken := env.NewID()
+	expiresAt := now.Add(upload.DefaultLifetime).Truncate(time.Second)
+	err = env.Database.CreateUploadToken(ctx, database.CreateUploadTokenParams{
+		ID:        uploadToken,
+		UserID:    userID,
+		RecipeID:  request.RecipeID,
+		Target:    string(target),
+		TargetID:  targetID,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to create upload token", slog.Any("error", err))
+		return PostApiRecipesRecipeIDUploadUrl500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PostApiRecipesRecipeIDUploadUrl201JSONResponse{
+		UploadUrl: upload.URL(env.Config.HostOrigin+version.Prefix+"/uploads",
+			[]byte(*env.Config.AppSecret.Value), uploadToken, expiresAt),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (Server) PostApiUploadsToken(ctx context.Context,
+	request PostApiUploadsTokenRequestObject) (
+	PostApiUploadsTokenResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Verify signature
+	env.Logger.DebugContext(ctx, "verifying upload signature")
+	now := env.Now()
+	err := upload.Verify([]byte(*env.Config.AppSecret.Value), request.Token,
+		time.Unix(request.Params.Expires, 0), request.Params.Signature, now)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "invalid upload url", slog.Any("error", err))
+		return PostApiUploadsToken403JSONResponse{
+			Status:  apiError.InvalidUploadURL.StatusCode(),
+			Code:    apiError.InvalidUploadURL.String(),
+			Message: "upload url is invalid or expired",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Read image
+	env.Logger.DebugContext(ctx, "reading image")
+	requestForm, err := request.Body.ReadForm(form.MaximumUploadSize)
+	if err != nil || len(requestForm.File["image"]) == 0 {
+		env.Logger.ErrorContext(ctx, "failed to read form", slog.Any("error", err))
+		return PostApiUploadsToken400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "invalid form",
+			ErrorId: requestID,
+		}, nil
+	}
+	imageHeader := requestForm.File["image"][0]
+	imageFile, err := imageHeader.Open()
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to open image", slog.Any("error", err))
+		return PostApiUploadsToken400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "invalid image",
+			ErrorId: requestID,
+		}, nil
+	}
+	defer func() { _ = imageFile.Close() }()
+	file, err := form.ReadImage(imageFile, imageHeader.Filename)
+	if errors.Is(err, form.ErrUnsupportedMimeType) {
+		env.Logger.ErrorContext(ctx, "unsupported format", slog.Any("error", err))
+		return PostApiUploadsToken422JSONResponse{
+			Status:  apiError.UnsupportedImageFormat.StatusCode(),
+			Code:    apiError.UnsupportedImageFormat.String(),
+			Message: "unsupported image format",
+			ErrorId: requestID,
+		}, nil
+	}
+	if errors.Is(err, form.ErrExtensionMismatch) {
+		env.Logger.ErrorContext(ctx, "image extension does not match content", slog.Any("error", err))
+		return PostApiUploadsToken422JSONResponse{
+			Status:  apiError.UnsupportedImageFormat.StatusCode(),
+			Code:    apiError.UnsupportedImageFormat.String(),
+			Message: "image extension does not match its contents",
+			ErrorId: requestID,
+		}, nil
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to read image", slog.Any("error", err))
+		return PostApiUploadsToken400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "invalid image",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Look up the user who requested the upload url
+	env.Logger.DebugContext(ctx, "looking up upload token")
+	userID, err := env.Database.GetUploadTokenUser(ctx, database.GetUploadTokenUserParams{
+		ID:        request.Token,
+		ExpiresAt: pgtype.Timestamptz{Time: now, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		env.Logger.ErrorContext(ctx, "upload token does not exist, expired, or was used")
+		return PostApiUploadsToken403JSONResponse{
+			Status:  apiError.InvalidUploadURL.StatusCode(),
+			Code:    apiError.InvalidUploadURL.String(),
+			Message: "upload url is invalid, expired, or already used",
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get upload token", slog.Any("error", err))
+		return PostApiUploadsToken500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Consume token and attach image as that user. The token is only
+	// used up if the image is attached, so a failed upload can be retried.
+	ctx = database.WithActor(ctx, database.Actor{UserID: userID})
+	var (
+		uploadToken database.ConsumeUploadTokenRow
+		imageKey    string
+		replacedKey pgtype.Text
+	)
+	err = env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		env.Logger.DebugContext(ctx, "consuming upload token")
+		uploadToken, err = tx.ConsumeUploadToken(ctx, database.ConsumeUploadTokenParams{
+			ID:     request.Token,
+			UsedAt: pgtype.Timestamptz{Time: now, Valid: true},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errUploadTokenUsed
+		} else if err != nil {
+			return fmt.Errorf("consuming upload token: %w", err)
+		}
+
+		env.Logger.DebugContext(ctx, "attaching image",
+			slog.String("target", uploadToken.Target), slog.Int64("target_id", uploadToken.TargetID))
+		imageKey, replacedKey, err = attachUpload(ctx, env.WithDatabase(tx),
+			upload.Target(uploadToken.Target), uploadToken.TargetID, file)
+		return err
+	})
+	if err != nil && imageKey != "" {
+		if err := env.FileStore.DeleteKey(imageKey); err != nil {
+			env.Logger.ErrorContext(ctx, "failed to delete orphaned image", slog.Any("error", err))
+		}
+	}
+	target := upload.Target(uploadToken.Target)
+	if errors.Is(err, errUploadTokenUsed) {
+		env.Logger.ErrorContext(ctx, "upload token was used concurrently")
+		return PostApiUploadsToken403JSONResponse{
+			Status:  apiError.InvalidUploadURL.StatusCode(),
+			Code:    apiError.InvalidUploadURL.String(),
+			Message: "upload url is invalid, expired, or already used",
+			ErrorId: requestID,
+		}, nil
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		env.Logger.ErrorContext(ctx, "upload target no longer exists", slog.Any("error", err))
+		return PostApiUploadsToken404JSONResponse{
+			Status:  apiError.RecipeNotFound.StatusCode(),
+			Code:    apiError.RecipeNotFound.String(),
+			Message: "recipe/" + string(target) + " no longer exists",
+			ErrorId: requestID,
+		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to attach image", slog.Any("error", err))
+		return PostApiUploadsToken500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Delete replaced image
+	if replacedKey.Valid {
+		env.Logger.DebugContext(ctx, "deleting replaced image")
+		if err := env.FileStore.DeleteKey(replacedKey.String); err != nil {
+			env.Logger.WarnContext(ctx, "failed to delete replaced image", slog.Any("error", err))
+		}
+	}
+
+	return PostApiUploadsToken200JSONResponse{
+		Target:   UploadResultTarget(target),
+		RecipeId: uploadToken.RecipeID,
+		TargetId: uploadToken.TargetID,
+		ImageUrl: env.FileStore.FileURL(imageKey),
+	}, nil
+}
+
+// ownsUploadTarget reports whether the user owns the recipe and, for
+// ingredient and step targets, whether the target belongs to it.
+func ownsUploadTarget(ctx context.Context, db database.Querier, userID, recipeID int64,
+	target upload.Target, targetID int64,
+) (bool, error) {
+	user := pgtype.Int8{Int64: userID, Valid: true}
+	switch target {
+	case upload.TargetCover:
+		return db.CheckRecipeOwnership(ctx, database.CheckRecipeOwnershipParams{ID: recipeID, UserID: user})
+	case upload.TargetIngredient:
+		return db.CheckIngredientOwnership(ctx, database.CheckIngredientOwnershipParams{
+			UserID:       user,
+			RecipeID:     recipeID,
+			IngredientID: targetID,
+		})
+	case upload.TargetStep:
+		return db.CheckStepOwnership(ctx, database.CheckStepOwnershipParams{
+			UserID:   user,
+			RecipeID: recipeID,
+			StepID:   targetID,
+		})
+	default:
+		return false, fmt.Errorf("unknown upload target %q", target)
+	}
+}
+
+// attachUpload writes the image and points the target at it. It returns
+// the key of the written image, which the caller must delete if the
+// transaction of env is rolled back, and the key of the image it
+// replaces, which the caller deletes once committed. pgx.ErrNoRows is
+// returned if the target no longer exists.
+func attachUpload(ctx context.Context, env *env.Env, target upload.Target, targetID int64,
+	file *form.File,
+) (key string, replaced pgtype.Text, err error) {
+	var (
+		getKey func(context.Context, int64) (pgtype.Text, error)
+		write  func(string, []byte) (string, int, error)
+		update func(ctx context.Context, key pgtype.Text) error
+	)
+	switch target {
+	case upload.TargetCover:
+		getKey, write = env.Database.GetRecipeImageKey, env.FileStore.WriteRecipeCoverImage
+		update = func(ctx context.Context, key pgtype.Text) error {
+			return env.Database.UpdateRecipeCoverImage(ctx,
+				database.UpdateRecipeCoverImageParams{ImageKey: key, ID: targetID})
+		}
+	case upload.TargetIngredient:
+		getKey, write = env.Database.GetRecipeIngredientImageKey, env.FileStore.WriteIngredientImage
+		update = func(ctx context.Context, key pgtype.Text) error {
+			return env.Database.UpdateRecipeIngredientImage(ctx,
+				database.UpdateRecipeIngredientImageParams{ImageKey: key, ID: targetID})
+		}
+	case upload.TargetStep:
+		getKey, write = env.Database.GetRecipeStepImageKey, env.FileStore.WriteStepImage
+		update = func(ctx context.Context, key pgtype.Text) error {
+			return env.Database.UpdateRecipeStepImage(ctx,
+				database.UpdateRecipeStepImageParams{ImageKey: key, ID: targetID})
+		}
+	default:
+		return "", pgtype.Text{}, fmt.Errorf("unknown upload target %q", target)
+	}
+
+	replaced, err = getKey(ctx, targetID)
+	if err != nil {
+		return "", pgtype.Text{}, fmt.Errorf("getting current image key: %w", err)
+	}
+
+	key, _, err = write(file.Suffix, file.Data)
+	if err != nil {
+		return "", pgtype.Text{}, fmt.Errorf("writing image: %w", err)
+	}
+
+	if err := update(ctx, pgtype.Text{String: key, Valid: true}); err != nil {
+		return key, pgtype.Text{}, fmt.Errorf("updating image key: %w", err)
+	}
+	return key, replaced, nil
+}
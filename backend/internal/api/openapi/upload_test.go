@@ -0,0 +1,403 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/api/token"
+	"github.com/matt-dz/wecook/internal/clock"
+	"github.com/matt-dz/wecook/internal/config"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/filestore"
+	"github.com/matt-dz/wecook/internal/idgen"
+	"github.com/matt-dz/wecook/internal/log"
+	"github.com/matt-dz/wecook/internal/upload"
+)
+
+const uploadTestSecret = "test-secret-key-for-upload-signing"
+
+func newUploadTestEnv(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface,
+	now time.Time,
+) *env.Env {
+	secret := config.AppSecretValue(uploadTestSecret)
+	e := &env.Env{
+		Logger:    log.NullLogger(),
+		Database:  mockDB,
+		FileStore: mockFS,
+		Clock:     clock.NewFrozen(now),
+		IDGen:     idgen.NewSequential("upload-"),
+	}
+	e.Config.HostOrigin = "https://wecook.example.com"
+	e.Config.AppSecret.Value = &secret
+	return e
+}
+
+func TestPostApiRecipesRecipeIDUploadUrl(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(upload.DefaultLifetime)
+	ingredientID := int64(7)
+
+	tests := []struct {
+		name       string
+		body       *CreateUploadURLRequest
+		setup      func(mockDB *database.MockQuerier)
+		wantStatus int
+		wantCode   string
+		wantURL    string
+	}{
+		{
+			name: "cover upload url",
+			body: &CreateUploadURLRequest{Target: CreateUploadURLRequestTargetCover},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					CheckRecipeOwnership(gomock.Any(), database.CheckRecipeOwnershipParams{
+						ID:     1,
+						UserID: pgtype.Int8{Int64: 42, Valid: true},
+					}).
+					Return(true, nil)
+				mockDB.EXPECT().
+					DeleteExpiredUploadTokens(gomock.Any(), pgtype.Timestamptz{Time: now, Valid: true}).
+					Return(int64(0), nil)
+				mockDB.EXPECT().
+					CreateUploadToken(gomock.Any(), database.CreateUploadTokenParams{
+						ID:        "upload-1",
+						UserID:    42,
+						RecipeID:  1,
+						Target:    "cover",
+						TargetID:  1,
+						ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+					}).
+					Return(nil)
+			},
+			wantStatus: 201,
+			wantURL: upload.URL("https://wecook.example.com/api/v1/uploads",
+				[]byte(uploadTestSecret), "upload-1", expiresAt),
+		},
+		{
+			name: "ingredient upload url",
+			body: &CreateUploadURLRequest{Target: CreateUploadURLRequestTargetIngredient, TargetId: &ingredientID},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					CheckIngredientOwnership(gomock.Any(), database.CheckIngredientOwnershipParams{
+						UserID:       pgtype.Int8{Int64: 42, Valid: true},
+						RecipeID:     1,
+						IngredientID: 7,
+					}).
+					Return(true, nil)
+				mockDB.EXPECT().DeleteExpiredUploadTokens(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+				mockDB.EXPECT().
+					CreateUploadToken(gomock.Any(), gomock.Cond(func(p database.CreateUploadTokenParams) bool {
+						return p.Target == "ingredient" && p.TargetID == 7
+					})).
+					Return(nil)
+			},
+			wantStatus: 201,
+			wantURL: upload.URL("https://wecook.example.com/api/v1/uploads",
+				[]byte(uploadTestSecret), "upload-1", expiresAt),
+		},
+		{
+			name:       "step without target id",
+			body:       &CreateUploadURLRequest{Target: CreateUploadURLRequestTargetStep},
+			setup:      func(mockDB *database.MockQuerier) {},
+			wantStatus: 400,
+			wantCode:   apiError.BadRequest.String(),
+		},
+		{
+			name:       "unknown target",
+			body:       &CreateUploadURLRequest{Target: "avatar"},
+			setup:      func(mockDB *database.MockQuerier) {},
+			wantStatus: 400,
+			wantCode:   apiError.BadRequest.String(),
+		},
+		{
+			name: "recipe not owned",
+			body: &CreateUploadURLRequest{Target: CreateUploadURLRequestTargetCover},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().CheckRecipeOwnership(gomock.Any(), gomock.Any()).Return(false, nil)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.RecipeNotFound.String(),
+		},
+		{
+			name: "database error creating token",
+			body: &CreateUploadURLRequest{Target: CreateUploadURLRequestTargetCover},
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().CheckRecipeOwnership(gomock.Any(), gomock.Any()).Return(true, nil)
+				mockDB.EXPECT().DeleteExpiredUploadTokens(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+				mockDB.EXPECT().CreateUploadToken(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := database.NewMockQuerier(ctrl)
+			tt.setup(mockDB)
+
+			ctx := context.Background()
+			ctx = requestid.InjectRequestID(ctx, 12345)
+			ctx = token.UserIDWithCtx(ctx, 42)
+			ctx = env.WithCtx(ctx, newUploadTestEnv(mockDB, nil, now))
+
+			resp, err := NewServer().PostApiRecipesRecipeIDUploadUrl(ctx,
+				PostApiRecipesRecipeIDUploadUrlRequestObject{RecipeID: 1, Body: tt.body})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case PostApiRecipesRecipeIDUploadUrl201JSONResponse:
+				if tt.wantStatus != 201 {
+					t.Errorf("expected status %d, got 201", tt.wantStatus)
+				}
+				if v.UploadUrl != tt.wantURL {
+					t.Errorf("expected upload url %q, got %q", tt.wantURL, v.UploadUrl)
+				}
+				if !v.ExpiresAt.Equal(expiresAt) {
+					t.Errorf("expected expires_at %v, got %v", expiresAt, v.ExpiresAt)
+				}
+			case PostApiRecipesRecipeIDUploadUrl400JSONResponse:
+				if tt.wantStatus != 400 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 400 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiRecipesRecipeIDUploadUrl404JSONResponse:
+				if tt.wantStatus != 404 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 404 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiRecipesRecipeIDUploadUrl500JSONResponse:
+				if tt.wantStatus != 500 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 500 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			default:
+				t.Fatalf("unexpected response type %T", resp)
+			}
+		})
+	}
+}
+
+func TestPostApiUploadsToken(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(upload.DefaultLifetime)
+	signature := upload.Sign([]byte(uploadTestSecret), "upload-1", expires)
+
+	pngImage := []byte{
+		0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A,
+		0x00, 0x00, 0x00, 0x0D, 0x49, 0x48, 0x44, 0x52,
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+		0x08, 0x02, 0x00, 0x00, 0x00, 0x90, 0x77, 0x53,
+		0xDE,
+	}
+
+	tests := []struct {
+		name       string
+		expires    time.Time
+		signature  string
+		now        time.Time
+		setup      func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface)
+		wantStatus int
+		wantCode   string
+		wantURL    string
+	}{
+		{
+			name:      "attaches step image and replaces old one",
+			expires:   expires,
+			signature: signature,
+			now:       now,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					GetUploadTokenUser(gomock.Any(), database.GetUploadTokenUserParams{
+						ID:        "upload-1",
+						ExpiresAt: pgtype.Timestamptz{Time: now, Valid: true},
+					}).
+					Return(int64(42), nil)
+				mockDB.EXPECT().
+					ConsumeUploadToken(gomock.Any(), database.ConsumeUploadTokenParams{
+						ID:     "upload-1",
+						UsedAt: pgtype.Timestamptz{Time: now, Valid: true},
+					}).
+					Return(database.ConsumeUploadTokenRow{UserID: 42, RecipeID: 1, Target: "step", TargetID: 9}, nil)
+				mockDB.EXPECT().
+					GetRecipeStepImageKey(gomock.Any(), int64(9)).
+					Return(pgtype.Text{String: "/files/steps/old.png", Valid: true}, nil)
+				mockFS.EXPECT().WriteStepImage(".png", pngImage).Return("/files/steps/new.png", len(pngImage), nil)
+				mockDB.EXPECT().
+					UpdateRecipeStepImage(gomock.Any(), database.UpdateRecipeStepImageParams{
+						ImageKey: pgtype.Text{String: "/files/steps/new.png", Valid: true},
+						ID:       9,
+					}).
+					Return(nil)
+				mockFS.EXPECT().DeleteKey("/files/steps/old.png").Return(nil)
+				mockFS.EXPECT().FileURL("/files/steps/new.png").Return("https://wecook.example.com/files/steps/new.png")
+			},
+			wantStatus: 200,
+			wantURL:    "https://wecook.example.com/files/steps/new.png",
+		},
+		{
+			name:       "forged signature",
+			expires:    expires,
+			signature:  "forged",
+			now:        now,
+			setup:      func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {},
+			wantStatus: 403,
+			wantCode:   apiError.InvalidUploadURL.String(),
+		},
+		{
+			name:       "expired url",
+			expires:    expires,
+			signature:  signature,
+			now:        expires.Add(time.Second),
+			setup:      func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {},
+			wantStatus: 403,
+			wantCode:   apiError.InvalidUploadURL.String(),
+		},
+		{
+			name:      "token already used",
+			expires:   expires,
+			signature: signature,
+			now:       now,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					GetUploadTokenUser(gomock.Any(), gomock.Any()).
+					Return(int64(0), pgx.ErrNoRows)
+			},
+			wantStatus: 403,
+			wantCode:   apiError.InvalidUploadURL.String(),
+		},
+		{
+			name:      "token used concurrently",
+			expires:   expires,
+			signature: signature,
+			now:       now,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					GetUploadTokenUser(gomock.Any(), gomock.Any()).
+					Return(int64(42), nil)
+				mockDB.EXPECT().
+					ConsumeUploadToken(gomock.Any(), gomock.Any()).
+					Return(database.ConsumeUploadTokenRow{}, pgx.ErrNoRows)
+			},
+			wantStatus: 403,
+			wantCode:   apiError.InvalidUploadURL.String(),
+		},
+		{
+			name:      "target deleted",
+			expires:   expires,
+			signature: signature,
+			now:       now,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					GetUploadTokenUser(gomock.Any(), gomock.Any()).
+					Return(int64(42), nil)
+				mockDB.EXPECT().
+					ConsumeUploadToken(gomock.Any(), gomock.Any()).
+					Return(database.ConsumeUploadTokenRow{UserID: 42, RecipeID: 1, Target: "cover", TargetID: 1}, nil)
+				mockDB.EXPECT().
+					GetRecipeImageKey(gomock.Any(), int64(1)).
+					Return(pgtype.Text{}, pgx.ErrNoRows)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.RecipeNotFound.String(),
+		},
+		{
+			name:      "database error updating key removes written image",
+			expires:   expires,
+			signature: signature,
+			now:       now,
+			setup: func(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) {
+				mockDB.EXPECT().
+					GetUploadTokenUser(gomock.Any(), gomock.Any()).
+					Return(int64(42), nil)
+				mockDB.EXPECT().
+					ConsumeUploadToken(gomock.Any(), gomock.Any()).
+					Return(database.ConsumeUploadTokenRow{UserID: 42, RecipeID: 1, Target: "cover", TargetID: 1}, nil)
+				mockDB.EXPECT().GetRecipeImageKey(gomock.Any(), int64(1)).Return(pgtype.Text{}, nil)
+				mockFS.EXPECT().WriteRecipeCoverImage(".png", pngImage).Return("/files/covers/new.png", len(pngImage), nil)
+				mockDB.EXPECT().UpdateRecipeCoverImage(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+				mockFS.EXPECT().DeleteKey("/files/covers/new.png").Return(nil)
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := database.NewMockQuerier(ctrl)
+			mockFS := filestore.NewMockFileStoreInterface(ctrl)
+			tt.setup(mockDB, mockFS)
+
+			// Create multipart form with image
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("image", "photo.png")
+			if err != nil {
+				t.Fatalf("failed to create form file: %v", err)
+			}
+			if _, err := part.Write(pngImage); err != nil {
+				t.Fatalf("failed to write image data: %v", err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("failed to close writer: %v", err)
+			}
+
+			ctx := context.Background()
+			ctx = requestid.InjectRequestID(ctx, 12345)
+			ctx = env.WithCtx(ctx, newUploadTestEnv(mockDB, mockFS, tt.now))
+
+			resp, err := NewServer().PostApiUploadsToken(ctx, PostApiUploadsTokenRequestObject{
+				Token: "upload-1",
+				Params: PostApiUploadsTokenParams{
+					Expires:   tt.expires.Unix(),
+					Signature: tt.signature,
+				},
+				Body: multipart.NewReader(body, writer.Boundary()),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch v := resp.(type) {
+			case PostApiUploadsToken200JSONResponse:
+				if tt.wantStatus != 200 {
+					t.Errorf("expected status %d, got 200", tt.wantStatus)
+				}
+				if v.ImageUrl != tt.wantURL {
+					t.Errorf("expected image url %q, got %q", tt.wantURL, v.ImageUrl)
+				}
+			case PostApiUploadsToken403JSONResponse:
+				if tt.wantStatus != 403 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 403 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiUploadsToken404JSONResponse:
+				if tt.wantStatus != 404 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 404 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			case PostApiUploadsToken500JSONResponse:
+				if tt.wantStatus != 500 || v.Code != tt.wantCode {
+					t.Errorf("expected status %d code %s, got 500 %s", tt.wantStatus, tt.wantCode, v.Code)
+				}
+			default:
+				t.Fatalf("unexpected response type %T", resp)
+			}
+		})
+	}
+}
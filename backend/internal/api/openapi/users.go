@@ -1,12 +1,12 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"slices"
 	"strconv"
 	"strings"
@@ -14,20 +14,37 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/oapi-codegen/nullable"
 
 	apiError "github.com/matt-dz/wecook/internal/api/error"
 	"github.com/matt-dz/wecook/internal/api/requestid"
 	"github.com/matt-dz/wecook/internal/api/token"
 	"github.com/matt-dz/wecook/internal/argon2id"
+	"github.com/matt-dz/wecook/internal/audit"
+	"github.com/matt-dz/wecook/internal/branding"
 	"github.com/matt-dz/wecook/internal/config"
 	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/delivery"
 	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/export"
 	"github.com/matt-dz/wecook/internal/invite"
 	mJwt "github.com/matt-dz/wecook/internal/jwt"
 	"github.com/matt-dz/wecook/internal/password"
+	"github.com/matt-dz/wecook/internal/report"
 	"github.com/matt-dz/wecook/internal/role"
+	"github.com/matt-dz/wecook/internal/timezone"
 )
 
+// dryRunSampleSize is the number of affected IDs returned by dry runs.
+const dryRunSampleSize = 10
+
+// errInviteCodeRedeemed is returned when an invite code expires or is
+// used by someone else during signup.
+var errInviteCodeRedeemed = errors.New("invite code already redeemed or expired")
+
+// errUserNotFound is returned when the user to delete does not exist.
+var errUserNotFound = errors.New("user not found")
+
 func (Server) GetApiUsers(ctx context.Context, request GetApiUsersRequestObject) (GetApiUsersResponseObject, error) {
 	env := env.EnvFromCtx(ctx)
 	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
@@ -37,10 +54,7 @@ func (Server) GetApiUsers(ctx context.Context, request GetApiUsersRequestObject)
 		after = *request.Params.After
 	}
 
-	var limit int32
-	if request.Params.Limit != nil {
-		limit = *request.Params.Limit
-	}
+	limit := pageLimit(env, request.Params.Limit)
 
 	env.Logger.DebugContext(ctx, "getting users")
 	users, err := env.Database.GetUsers(ctx, database.GetUsersParams{
@@ -48,10 +62,7 @@ func (Server) GetApiUsers(ctx context.Context, request GetApiUsersRequestObject)
 			Int64: after,
 			Valid: request.Params.After != nil,
 		},
-		Limit: pgtype.Int4{
-			Int32: limit,
-			Valid: request.Params.Limit != nil,
-		},
+		Limit: limit + 1,
 	})
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to get users", slog.Any("error", err))
@@ -63,8 +74,22 @@ func (Server) GetApiUsers(ctx context.Context, request GetApiUsersRequestObject)
 		}, nil
 	}
 
+	env.Logger.DebugContext(ctx, "counting users")
+	total, err := env.Database.GetUserCount(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to count users", slog.Any("error", err))
+		return GetApiUsers500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	users, page := newPage(users, limit, total)
 	res := GetApiUsers200JSONResponse{
 		Users: make([]User, len(users)),
+		Page:  page,
 	}
 	for idx, user := range users {
 		res.Users[idx] = User{
@@ -185,16 +210,24 @@ func (Server) PostApiUserInvite(ctx context.Context,
 	inviteLink := fmt.Sprintf("%s/signup?code=%s",
 		strings.TrimRight(env.Config.HostOrigin, "/"), invite)
 
+	// Get branding
+	env.Logger.DebugContext(ctx, "getting instance branding")
+	instance, err := branding.Load(ctx, env.Database)
+	if err != nil {
+		env.Logger.WarnContext(ctx, "failed to load branding, using default", slog.Any("error", err))
+		instance = branding.Default()
+	}
+
 	//nolint:lll
 	msg := fmt.Sprintf(`Hello!
 
-You have been invited to sign up for WeCook — a platform for creating and sharing recipes. Signup via the invite link below (note the link expires in 8 hours):
+You have been invited to sign up for %s — a platform for creating and sharing recipes. Signup via the invite link below (note the link expires in 8 hours):
 
-%s`, inviteLink)
+%s`, instance.Name, inviteLink)
 
 	// Send invite
 	env.Logger.DebugContext(ctx, "sending invite")
-	err = env.SMTP.Send([]string{string(request.Body.Email)}, "WeCook Invitation", msg)
+	err = delivery.SendEmail(ctx, env, []string{string(request.Body.Email)}, instance.Name+" Invitation", msg)
 	if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to send invite", slog.Any("error", err))
 		return PostApiUserInvite500JSONResponse{
@@ -331,23 +364,53 @@ func (Server) PostApiSignup(ctx context.Context,
 		}, nil
 	}
 
-	// Create user
+	// Create user and redeem invitation code
 	env.Logger.DebugContext(ctx, "creating user")
-	userID, err := env.Database.CreateUser(ctx, database.CreateUserParams{
-		Email:        string(request.Body.Email),
-		FirstName:    request.Body.FirstName,
-		LastName:     request.Body.LastName,
-		PasswordHash: passwordHash,
+	var userID int64
+	err = env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		var err error
+		userID, err = tx.CreateUser(ctx, database.CreateUserParams{
+			Email:        string(request.Body.Email),
+			FirstName:    request.Body.FirstName,
+			LastName:     request.Body.LastName,
+			PasswordHash: passwordHash,
+		})
+		if err != nil {
+			return fmt.Errorf("creating user: %w", err)
+		}
+		if allowPublicSignup {
+			return nil
+		}
+
+		env.Logger.DebugContext(ctx, "redeem invite code")
+		rows, err := tx.RedeemInvitationCode(ctx, inviteid)
+		if err != nil {
+			return fmt.Errorf("redeeming invite code: %w", err)
+		} else if rows == 0 {
+			// Rare edge case where code becomes invalid during signup.
+			// The user is not created, so the email can be used again
+			// with another code.
+			return errInviteCodeRedeemed
+		}
+		return nil
 	})
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 		env.Logger.ErrorContext(ctx, "user with email already exists", slog.Any("error", err))
-		return PostApiSignup422JSONResponse{
+		return PostApiSignup409JSONResponse{
 			Status:  apiError.EmailConflict.StatusCode(),
 			Code:    apiError.EmailConflict.String(),
 			Message: "email already in use",
 			ErrorId: requestID,
 		}, nil
+	} else if errors.Is(err, errInviteCodeRedeemed) {
+		env.Logger.ErrorContext(ctx, "failed to redeem invite code; it may have expired", slog.Any("error", err))
+		return PostApiSignup422JSONResponse{
+			Status:  apiError.InvalidInviteCode.StatusCode(),
+			Code:    apiError.InvalidInviteCode.String(),
+			Message: "invalid invite code",
+			ErrorId: requestID,
+		}, nil
 	} else if err != nil {
 		env.Logger.ErrorContext(ctx, "failed to create user", slog.Any("error", err))
 		return PostApiSignup500JSONResponse{
@@ -358,32 +421,6 @@ func (Server) PostApiSignup(ctx context.Context,
 		}, nil
 	}
 
-	// Redeem invitation code
-	if !allowPublicSignup {
-		env.Logger.DebugContext(ctx, "redeem invite code")
-		rows, err := env.Database.RedeemInvitationCode(ctx, inviteid)
-		if err != nil {
-			env.Logger.ErrorContext(ctx, "failed to redeem invite code", slog.Any("error", err))
-			return PostApiSignup500JSONResponse{
-				Status:  apiError.InternalServerError.StatusCode(),
-				Code:    apiError.InternalServerError.String(),
-				Message: "Internal Server Error",
-				ErrorId: requestID,
-			}, nil
-		} else if rows == 0 {
-			// Rare edge case where code becomes invalid during signup.
-			// Good to process it otherwise the db would become inconsistent
-			// and the invite code would never have a valid used_at field.
-			env.Logger.ErrorContext(ctx, "failed to redeem invite code; it may have expired", slog.Any("error", err))
-			return PostApiSignup422JSONResponse{
-				Status:  apiError.InvalidInviteCode.StatusCode(),
-				Code:    apiError.InvalidInviteCode.String(),
-				Message: "invalid invite code",
-				ErrorId: requestID,
-			}, nil
-		}
-	}
-
 	// Create tokens
 	env.Logger.DebugContext(ctx, "creating user tokens")
 	refreshToken, err := token.NewRefreshToken(userID)
@@ -506,8 +543,8 @@ func (Server) PatchApiUserPassword(ctx context.Context,
 	currentHash := argon2id.HashWithSalt(request.Body.CurrentPassword, *p, salt)
 	if subtle.ConstantTimeCompare(currentHash, groundHash) == 0 {
 		env.Logger.ErrorContext(ctx, "passwords do not match")
-		return PatchApiUserPassword401JSONResponse{
-			Status:  http.StatusForbidden,
+		return PatchApiUserPassword422JSONResponse{
+			Status:  apiError.InvalidPassword.StatusCode(),
 			Code:    apiError.InvalidPassword.String(),
 			Message: "current password is incorrect",
 			ErrorId: requestID,
@@ -559,12 +596,232 @@ func (Server) PatchApiUserPassword(ctx context.Context,
 	return PatchApiUserPassword204Response{}, nil
 }
 
+// userPreferences builds the preferences response from the stored values.
+func userPreferences(unit database.NullTemperatureUnit, weeklyReport bool, allergies []string,
+	timeZone string,
+) UserPreferences {
+	prefs := UserPreferences{
+		TemperatureUnit: nullable.NewNullNullable[TemperatureUnit](),
+		WeeklyReport:    weeklyReport,
+		Allergies:       make([]Allergen, 0, len(allergies)),
+		TimeZone:        timeZone,
+	}
+	if unit.Valid {
+		prefs.TemperatureUnit.Set(TemperatureUnit(unit.TemperatureUnit))
+	}
+	for _, allergy := range allergies {
+		prefs.Allergies = append(prefs.Allergies, Allergen(allergy))
+	}
+	return prefs
+}
+
+func (Server) GetApiUserPreferences(ctx context.Context,
+	request GetApiUserPreferencesRequestObject) (
+	GetApiUserPreferencesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return GetApiUserPreferences400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Get preferences
+	env.Logger.DebugContext(ctx, "getting user preferences")
+	prefs, err := env.Database.GetUserPreferences(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		env.Logger.ErrorContext(ctx, "user not found", slog.Any("error", err))
+		return GetApiUserPreferences404JSONResponse{
+			Status:  apiError.UserNotFound.StatusCode(),
+			Code:    apiError.UserNotFound.String(),
+			Message: "user not found",
+			ErrorId: requestID,
+		}, nil
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to get user preferences", slog.Any("error", err))
+		return GetApiUserPreferences500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return GetApiUserPreferences200JSONResponse(userPreferences(prefs.TemperatureUnit, prefs.WeeklyReport, prefs.Allergies,
+		prefs.TimeZone)), nil
+}
+
+func (Server) PatchApiUserPreferences(ctx context.Context,
+	request PatchApiUserPreferencesRequestObject) (
+	PatchApiUserPreferencesResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PatchApiUserPreferences400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "missing user id",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Update preferences
+	env.Logger.DebugContext(ctx, "updating user preferences")
+	params := database.UpdateUserPreferencesParams{
+		UpdateTemperatureUnit: request.Body.TemperatureUnit.IsSpecified(),
+		ID:                    userID,
+	}
+	if request.Body.TemperatureUnit.IsSpecified() && !request.Body.TemperatureUnit.IsNull() {
+		params.TemperatureUnit.TemperatureUnit = database.TemperatureUnit(request.Body.TemperatureUnit.MustGet())
+		params.TemperatureUnit.Valid = true
+	}
+	if request.Body.WeeklyReport != nil {
+		params.WeeklyReport = pgtype.Bool{Bool: *request.Body.WeeklyReport, Valid: true}
+	}
+	if request.Body.Allergies != nil {
+		params.Allergies = allergenNames(*request.Body.Allergies)
+		slices.Sort(params.Allergies)
+		params.Allergies = slices.Compact(params.Allergies)
+	}
+	if request.Body.TimeZone != nil {
+		loc, err := timezone.Load(*request.Body.TimeZone)
+		if err != nil {
+			env.Logger.DebugContext(ctx, "unknown time zone", slog.Any("error", err))
+			return PatchApiUserPreferences422JSONResponse{
+				Status:  apiError.InvalidTimeZone.StatusCode(),
+				Code:    apiError.InvalidTimeZone.String(),
+				Message: "unknown time zone",
+				ErrorId: requestID,
+			}, nil
+		}
+		params.TimeZone = pgtype.Text{String: loc.String(), Valid: true}
+		params.WeeklyReportDueAt = pgtype.Timestamptz{Time: report.NextDue(env.Now(), loc), Valid: true}
+	}
+	prefs, err := env.Database.UpdateUserPreferences(ctx, params)
+	if errors.Is(err, pgx.ErrNoRows) {
+		env.Logger.ErrorContext(ctx, "user not found", slog.Any("error", err))
+		return PatchApiUserPreferences404JSONResponse{
+			Status:  apiError.UserNotFound.StatusCode(),
+			Code:    apiError.UserNotFound.String(),
+			Message: "user not found",
+			ErrorId: requestID,
+		}, nil
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to update user preferences", slog.Any("error", err))
+		return PatchApiUserPreferences500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PatchApiUserPreferences200JSONResponse(userPreferences(prefs.TemperatureUnit, prefs.WeeklyReport, prefs.Allergies,
+		prefs.TimeZone)), nil
+}
+
+func (Server) GetApiReportsUnsubscribe(ctx context.Context,
+	request GetApiReportsUnsubscribeRequestObject) (
+	GetApiReportsUnsubscribeResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Verify signature
+	env.Logger.DebugContext(ctx, "verifying unsubscribe link")
+	if !report.Verify([]byte(*env.Config.AppSecret.Value), request.Params.User, request.Params.Signature) {
+		env.Logger.ErrorContext(ctx, "invalid unsubscribe link")
+		return GetApiReportsUnsubscribe403JSONResponse{
+			Status:  apiError.InvalidUnsubscribeLink.StatusCode(),
+			Code:    apiError.InvalidUnsubscribeLink.String(),
+			Message: "invalid unsubscribe link",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Ask for confirmation
+	page, err := report.RenderUnsubscribe(request.Params.User, request.Params.Signature)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to render unsubscribe page", slog.Any("error", err))
+		return GetApiReportsUnsubscribe500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return GetApiReportsUnsubscribe200TexthtmlResponse{
+		Body:          strings.NewReader(page),
+		ContentLength: int64(len(page)),
+	}, nil
+}
+
+func (Server) PostApiReportsUnsubscribe(ctx context.Context,
+	request PostApiReportsUnsubscribeRequestObject) (
+	PostApiReportsUnsubscribeResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	// Verify signature
+	env.Logger.DebugContext(ctx, "verifying unsubscribe link")
+	if !report.Verify([]byte(*env.Config.AppSecret.Value), request.Params.User, request.Params.Signature) {
+		env.Logger.ErrorContext(ctx, "invalid unsubscribe link")
+		return PostApiReportsUnsubscribe403JSONResponse{
+			Status:  apiError.InvalidUnsubscribeLink.StatusCode(),
+			Code:    apiError.InvalidUnsubscribeLink.String(),
+			Message: "invalid unsubscribe link",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Unsubscribe. A deleted user has nothing left to unsubscribe from, so
+	// the link still succeeds.
+	env.Logger.DebugContext(ctx, "unsubscribing from weekly report")
+	if _, err := env.Database.UnsubscribeWeeklyReport(ctx, request.Params.User); err != nil {
+		env.Logger.ErrorContext(ctx, "failed to unsubscribe from weekly report", slog.Any("error", err))
+		return PostApiReportsUnsubscribe500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PostApiReportsUnsubscribe200TexthtmlResponse{
+		Body:          strings.NewReader(report.UnsubscribedHTML),
+		ContentLength: int64(len(report.UnsubscribedHTML)),
+	}, nil
+}
+
 func (Server) DeleteApiUserId(ctx context.Context,
 	request DeleteApiUserIdRequestObject) (
 	DeleteApiUserIdResponseObject, error,
 ) {
 	env := env.EnvFromCtx(ctx)
 	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	actorID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return DeleteApiUserId500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
 
 	// Get all user images
 	env.Logger.DebugContext(ctx, "getting user images")
@@ -610,19 +867,110 @@ func (Server) DeleteApiUserId(ctx context.Context,
 		}, nil
 	}
 
-	// Delete user
-	env.Logger.DebugContext(ctx, "deleting user")
-	rows, err := env.Database.DeleteUser(ctx, request.Id)
-	if err != nil {
-		env.Logger.ErrorContext(ctx, "failed to delete user", slog.Any("error", err))
-		return DeleteApiUserId500JSONResponse{
-			Status:  apiError.InternalServerError.StatusCode(),
-			Code:    apiError.InternalServerError.String(),
-			Message: "Internal Server Error",
-			ErrorId: requestID,
-		}, nil
+	images := slices.Concat(coverImages, ingredientImages, stepImages)
+
+	if request.Params.DryRun != nil && *request.Params.DryRun {
+		// Ensure user exists
+		env.Logger.DebugContext(ctx, "getting user")
+		if _, err := env.Database.GetUserById(ctx, request.Id); errors.Is(err, pgx.ErrNoRows) {
+			env.Logger.ErrorContext(ctx, "user not found", slog.Any("error", err))
+			return DeleteApiUserId404JSONResponse{
+				Status:  apiError.UserNotFound.StatusCode(),
+				Code:    apiError.UserNotFound.String(),
+				Message: "User not found",
+				ErrorId: requestID,
+			}, nil
+		} else if err != nil {
+			env.Logger.ErrorContext(ctx, "failed to get user", slog.Any("error", err))
+			return DeleteApiUserId500JSONResponse{
+				Status:  apiError.InternalServerError.StatusCode(),
+				Code:    apiError.InternalServerError.String(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+
+		// Count affected recipes
+		env.Logger.DebugContext(ctx, "counting user recipes")
+		recipeCount, err := env.Database.GetUserRecipeCount(ctx, pgtype.Int8{
+			Int64: request.Id,
+			Valid: true,
+		})
+		if err != nil {
+			env.Logger.ErrorContext(ctx, "failed to count user recipes", slog.Any("error", err))
+			return DeleteApiUserId500JSONResponse{
+				Status:  apiError.InternalServerError.StatusCode(),
+				Code:    apiError.InternalServerError.String(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+		sampleIDs, err := env.Database.GetUserRecipeIDSample(ctx, database.GetUserRecipeIDSampleParams{
+			UserID: pgtype.Int8{Int64: request.Id, Valid: true},
+			Limit:  dryRunSampleSize,
+		})
+		if err != nil {
+			env.Logger.ErrorContext(ctx, "failed to get user recipe ids", slog.Any("error", err))
+			return DeleteApiUserId500JSONResponse{
+				Status:  apiError.InternalServerError.StatusCode(),
+				Code:    apiError.InternalServerError.String(),
+				Message: "Internal Server Error",
+				ErrorId: requestID,
+			}, nil
+		}
+		if sampleIDs == nil {
+			sampleIDs = []int64{}
+		}
+
+		var imageCount int64
+		for _, img := range images {
+			if img.Valid {
+				imageCount++
+			}
+		}
+
+		res := DeleteApiUserId200JSONResponse{
+			UserId:          request.Id,
+			RecipeCount:     recipeCount,
+			ImageCount:      imageCount,
+			SampleRecipeIds: sampleIDs,
+		}
+
+		// Record dry run
+		env.Logger.DebugContext(ctx, "recording dry run")
+		if err := audit.Record(ctx, env.Database, audit.Event{
+			ActorID:    actorID,
+			Action:     audit.ActionDeleteUser,
+			TargetType: audit.TargetUser,
+			TargetID:   request.Id,
+			DryRun:     true,
+			Metadata:   res,
+		}); err != nil {
+			env.Logger.WarnContext(ctx, "failed to record audit event", slog.Any("error", err))
+		}
+
+		return res, nil
 	}
-	if rows == 0 {
+
+	// Delete user and record the deletion
+	err = env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		env.Logger.DebugContext(ctx, "deleting user")
+		rows, err := tx.DeleteUser(ctx, request.Id)
+		if err != nil {
+			return fmt.Errorf("deleting user: %w", err)
+		} else if rows == 0 {
+			return errUserNotFound
+		}
+
+		env.Logger.DebugContext(ctx, "recording deletion")
+		return audit.Record(ctx, tx, audit.Event{
+			ActorID:    actorID,
+			Action:     audit.ActionDeleteUser,
+			TargetType: audit.TargetUser,
+			TargetID:   request.Id,
+		})
+	})
+	if errors.Is(err, errUserNotFound) {
 		env.Logger.ErrorContext(ctx, "no rows deleted - user not found")
 		return DeleteApiUserId404JSONResponse{
 			Status:  apiError.UserNotFound.StatusCode(),
@@ -630,11 +978,19 @@ func (Server) DeleteApiUserId(ctx context.Context,
 			Message: "User not found",
 			ErrorId: requestID,
 		}, nil
+	} else if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to delete user", slog.Any("error", err))
+		return DeleteApiUserId500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
 	}
 
 	// Remove images
 	env.Logger.DebugContext(ctx, "removing all images")
-	for _, img := range slices.Concat(coverImages, ingredientImages, stepImages) {
+	for _, img := range images {
 		if img.Valid {
 			if err := env.FileStore.DeleteKey(img.String); err != nil {
 				env.Logger.WarnContext(ctx, "failed to delete image - manual cleanup required",
@@ -645,3 +1001,136 @@ func (Server) DeleteApiUserId(ctx context.Context,
 
 	return DeleteApiUserId204Response{}, nil
 }
+
+func (Server) GetApiUserExport(ctx context.Context,
+	request GetApiUserExportRequestObject) (
+	GetApiUserExportResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return GetApiUserExport500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Build archive
+	env.Logger.DebugContext(ctx, "exporting user")
+	var archive bytes.Buffer
+	_, err = export.Write(ctx, env, userID, &archive)
+	if errors.Is(err, export.ErrUserNotFound) {
+		env.Logger.ErrorContext(ctx, "user not found", slog.Any("error", err))
+		return GetApiUserExport404JSONResponse{
+			Status:  apiError.UserNotFound.StatusCode(),
+			Code:    apiError.UserNotFound.String(),
+			Message: "User not found",
+			ErrorId: requestID,
+		}, nil
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to export user", slog.Any("error", err))
+		return GetApiUserExport500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return GetApiUserExport200ApplicationzipResponse{
+		Body:          &archive,
+		ContentLength: int64(archive.Len()),
+		Headers: GetApiUserExport200ResponseHeaders{
+			ContentDisposition: fmt.Sprintf(`attachment; filename="wecook-user-%d.zip"`, userID),
+		},
+	}, nil
+}
+
+func (Server) PostApiUserIdExport(ctx context.Context,
+	request PostApiUserIdExportRequestObject) (
+	PostApiUserIdExportResponseObject, error,
+) {
+	env := env.EnvFromCtx(ctx)
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	actorID, err := token.UserIDFromCtx(ctx)
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to extract user id from context", slog.Any("error", err))
+		return PostApiUserIdExport500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	justification := strings.TrimSpace(request.Body.Justification)
+	if justification == "" {
+		env.Logger.ErrorContext(ctx, "missing justification")
+		return PostApiUserIdExport400JSONResponse{
+			Status:  apiError.BadRequest.StatusCode(),
+			Code:    apiError.BadRequest.String(),
+			Message: "justification is required",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Build archive
+	env.Logger.DebugContext(ctx, "exporting user")
+	var archive bytes.Buffer
+	summary, err := export.Write(ctx, env, request.Id, &archive)
+	if errors.Is(err, export.ErrUserNotFound) {
+		env.Logger.ErrorContext(ctx, "user not found", slog.Any("error", err))
+		return PostApiUserIdExport404JSONResponse{
+			Status:  apiError.UserNotFound.StatusCode(),
+			Code:    apiError.UserNotFound.String(),
+			Message: "User not found",
+			ErrorId: requestID,
+		}, nil
+	}
+	if err != nil {
+		env.Logger.ErrorContext(ctx, "failed to export user", slog.Any("error", err))
+		return PostApiUserIdExport500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	// Record export. Unlike other audit events, an export that cannot be
+	// recorded is not handed out.
+	env.Logger.DebugContext(ctx, "recording export")
+	if err := audit.Record(ctx, env.Database, audit.Event{
+		ActorID:    actorID,
+		Action:     audit.ActionExportUser,
+		TargetType: audit.TargetUser,
+		TargetID:   request.Id,
+		Metadata: map[string]any{
+			"justification":  justification,
+			"recipes":        summary.Recipes,
+			"images":         summary.Images,
+			"format_version": export.FormatVersion,
+		},
+	}); err != nil {
+		env.Logger.ErrorContext(ctx, "failed to record audit event", slog.Any("error", err))
+		return PostApiUserIdExport500JSONResponse{
+			Status:  apiError.InternalServerError.StatusCode(),
+			Code:    apiError.InternalServerError.String(),
+			Message: "Internal Server Error",
+			ErrorId: requestID,
+		}, nil
+	}
+
+	return PostApiUserIdExport200ApplicationzipResponse{
+		Body:          &archive,
+		ContentLength: int64(archive.Len()),
+		Headers: PostApiUserIdExport200ResponseHeaders{
+			ContentDisposition: fmt.Sprintf(`attachment; filename="wecook-user-%d.zip"`, request.Id),
+		},
+	}, nil
+}
@@ -3,12 +3,16 @@ package client
 import (
 	"context"
 	"errors"
+	"io"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/oapi-codegen/nullable"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 	"go.uber.org/mock/gomock"
 
@@ -16,12 +20,15 @@ import (
 	"github.com/matt-dz/wecook/internal/api/requestid"
 	"github.com/matt-dz/wecook/internal/api/token"
 	"github.com/matt-dz/wecook/internal/argon2id"
+	"github.com/matt-dz/wecook/internal/branding"
+	"github.com/matt-dz/wecook/internal/clock"
 	"github.com/matt-dz/wecook/internal/config"
 	"github.com/matt-dz/wecook/internal/database"
 	"github.com/matt-dz/wecook/internal/email"
 	"github.com/matt-dz/wecook/internal/env"
 	"github.com/matt-dz/wecook/internal/filestore"
 	"github.com/matt-dz/wecook/internal/log"
+	"github.com/matt-dz/wecook/internal/report"
 )
 
 func TestGetApiUsers(t *testing.T) {
@@ -53,10 +60,7 @@ func TestGetApiUsers(t *testing.T) {
 							Int64: 0,
 							Valid: false,
 						},
-						Limit: pgtype.Int4{
-							Int32: 0,
-							Valid: false,
-						},
+						Limit: config.DefaultPageLimit + 1,
 					}).
 					Return([]database.GetUsersRow{
 						{
@@ -74,6 +78,9 @@ func TestGetApiUsers(t *testing.T) {
 							Role:      database.RoleAdmin,
 						},
 					}, nil)
+				mockDB.EXPECT().
+					GetUserCount(gomock.Any()).
+					Return(int64(13), nil)
 			},
 			wantStatus: 200,
 			wantUsers:  2,
@@ -94,10 +101,7 @@ func TestGetApiUsers(t *testing.T) {
 							Int64: 0,
 							Valid: false,
 						},
-						Limit: pgtype.Int4{
-							Int32: 10,
-							Valid: true,
-						},
+						Limit: 11,
 					}).
 					Return([]database.GetUsersRow{
 						{
@@ -108,6 +112,9 @@ func TestGetApiUsers(t *testing.T) {
 							Role:      database.RoleUser,
 						},
 					}, nil)
+				mockDB.EXPECT().
+					GetUserCount(gomock.Any()).
+					Return(int64(13), nil)
 			},
 			wantStatus: 200,
 			wantUsers:  1,
@@ -128,10 +135,7 @@ func TestGetApiUsers(t *testing.T) {
 							Int64: 5,
 							Valid: true,
 						},
-						Limit: pgtype.Int4{
-							Int32: 0,
-							Valid: false,
-						},
+						Limit: config.DefaultPageLimit + 1,
 					}).
 					Return([]database.GetUsersRow{
 						{
@@ -149,6 +153,9 @@ func TestGetApiUsers(t *testing.T) {
 							Role:      database.RoleUser,
 						},
 					}, nil)
+				mockDB.EXPECT().
+					GetUserCount(gomock.Any()).
+					Return(int64(13), nil)
 			},
 			wantStatus: 200,
 			wantUsers:  2,
@@ -170,10 +177,7 @@ func TestGetApiUsers(t *testing.T) {
 							Int64: 10,
 							Valid: true,
 						},
-						Limit: pgtype.Int4{
-							Int32: 5,
-							Valid: true,
-						},
+						Limit: 6,
 					}).
 					Return([]database.GetUsersRow{
 						{
@@ -198,6 +202,9 @@ func TestGetApiUsers(t *testing.T) {
 							Role:      database.RoleUser,
 						},
 					}, nil)
+				mockDB.EXPECT().
+					GetUserCount(gomock.Any()).
+					Return(int64(13), nil)
 			},
 			wantStatus: 200,
 			wantUsers:  3,
@@ -218,12 +225,12 @@ func TestGetApiUsers(t *testing.T) {
 							Int64: 1000,
 							Valid: true,
 						},
-						Limit: pgtype.Int4{
-							Int32: 0,
-							Valid: false,
-						},
+						Limit: config.DefaultPageLimit + 1,
 					}).
 					Return([]database.GetUsersRow{}, nil)
+				mockDB.EXPECT().
+					GetUserCount(gomock.Any()).
+					Return(int64(13), nil)
 			},
 			wantStatus: 200,
 			wantUsers:  0,
@@ -308,6 +315,9 @@ func TestGetApiUsers_UserFieldMapping(t *testing.T) {
 				Role:      database.RoleAdmin,
 			},
 		}, nil)
+	mockDB.EXPECT().
+		GetUserCount(gomock.Any()).
+		Return(int64(13), nil)
 
 	ctx := context.Background()
 	ctx = requestid.InjectRequestID(ctx, 12345)
@@ -406,6 +416,9 @@ func TestGetApiUsers_CursorCalculation(t *testing.T) {
 			mockDB.EXPECT().
 				GetUsers(gomock.Any(), gomock.Any()).
 				Return(tt.users, nil)
+			mockDB.EXPECT().
+				GetUserCount(gomock.Any()).
+				Return(int64(13), nil)
 
 			ctx := context.Background()
 			ctx = requestid.InjectRequestID(ctx, 12345)
@@ -605,6 +618,411 @@ func TestGetApiUser(t *testing.T) {
 	}
 }
 
+func TestUserPreferences(t *testing.T) {
+	server := NewServer()
+	celsius := database.NullTemperatureUnit{TemperatureUnit: database.TemperatureUnitC, Valid: true}
+
+	newCtx := func(mockDB *database.MockQuerier) context.Context {
+		ctx := context.Background()
+		ctx = requestid.InjectRequestID(ctx, 12345)
+		ctx = token.UserIDWithCtx(ctx, 123)
+		return env.WithCtx(ctx, &env.Env{
+			Logger: log.NullLogger(),
+			Database: &database.Database{
+				Querier: mockDB,
+			},
+		})
+	}
+
+	t.Run("get returns stored unit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			GetUserPreferences(gomock.Any(), int64(123)).
+			Return(database.GetUserPreferencesRow{TemperatureUnit: celsius, WeeklyReport: true}, nil)
+
+		resp, err := server.GetApiUserPreferences(newCtx(mockDB), GetApiUserPreferencesRequestObject{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(GetApiUserPreferences200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		if got, err := v.TemperatureUnit.Get(); err != nil || got != C {
+			t.Errorf("expected temperature unit C, got %v (%v)", got, err)
+		}
+		if !v.WeeklyReport {
+			t.Error("expected weekly report to be on")
+		}
+	})
+
+	t.Run("get returns null when unset", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			GetUserPreferences(gomock.Any(), int64(123)).
+			Return(database.GetUserPreferencesRow{}, nil)
+
+		resp, err := server.GetApiUserPreferences(newCtx(mockDB), GetApiUserPreferencesRequestObject{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(GetApiUserPreferences200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		if !v.TemperatureUnit.IsNull() {
+			t.Errorf("expected null temperature unit, got %v", v.TemperatureUnit)
+		}
+	})
+
+	t.Run("get user not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			GetUserPreferences(gomock.Any(), int64(123)).
+			Return(database.GetUserPreferencesRow{}, pgx.ErrNoRows)
+
+		resp, err := server.GetApiUserPreferences(newCtx(mockDB), GetApiUserPreferencesRequestObject{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(GetApiUserPreferences404JSONResponse)
+		if !ok {
+			t.Fatalf("expected 404 response, got %T", resp)
+		}
+		if v.Code != apiError.UserNotFound.String() {
+			t.Errorf("expected code %s, got %s", apiError.UserNotFound.String(), v.Code)
+		}
+	})
+
+	t.Run("patch sets unit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			UpdateUserPreferences(gomock.Any(), database.UpdateUserPreferencesParams{
+				UpdateTemperatureUnit: true,
+				TemperatureUnit:       celsius,
+				ID:                    123,
+			}).
+			Return(database.UpdateUserPreferencesRow{TemperatureUnit: celsius}, nil)
+
+		resp, err := server.PatchApiUserPreferences(newCtx(mockDB), PatchApiUserPreferencesRequestObject{
+			Body: &UpdateUserPreferencesRequest{TemperatureUnit: nullable.NewNullableWithValue(C)},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PatchApiUserPreferences200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		if got, err := v.TemperatureUnit.Get(); err != nil || got != C {
+			t.Errorf("expected temperature unit C, got %v (%v)", got, err)
+		}
+	})
+
+	t.Run("patch null clears unit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			UpdateUserPreferences(gomock.Any(), database.UpdateUserPreferencesParams{
+				UpdateTemperatureUnit: true,
+				ID:                    123,
+			}).
+			Return(database.UpdateUserPreferencesRow{}, nil)
+
+		resp, err := server.PatchApiUserPreferences(newCtx(mockDB), PatchApiUserPreferencesRequestObject{
+			Body: &UpdateUserPreferencesRequest{TemperatureUnit: nullable.NewNullNullable[TemperatureUnit]()},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PatchApiUserPreferences200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		if !v.TemperatureUnit.IsNull() {
+			t.Errorf("expected null temperature unit, got %v", v.TemperatureUnit)
+		}
+	})
+
+	t.Run("patch weekly report keeps unit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			UpdateUserPreferences(gomock.Any(), database.UpdateUserPreferencesParams{
+				WeeklyReport: pgtype.Bool{Bool: true, Valid: true},
+				ID:           123,
+			}).
+			Return(database.UpdateUserPreferencesRow{TemperatureUnit: celsius, WeeklyReport: true}, nil)
+
+		weeklyReport := true
+		resp, err := server.PatchApiUserPreferences(newCtx(mockDB), PatchApiUserPreferencesRequestObject{
+			Body: &UpdateUserPreferencesRequest{WeeklyReport: &weeklyReport},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PatchApiUserPreferences200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		if got, err := v.TemperatureUnit.Get(); err != nil || got != C {
+			t.Errorf("expected temperature unit C, got %v (%v)", got, err)
+		}
+		if !v.WeeklyReport {
+			t.Error("expected weekly report to be on")
+		}
+	})
+
+	t.Run("patch allergies sorts and deduplicates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			UpdateUserPreferences(gomock.Any(), database.UpdateUserPreferencesParams{
+				Allergies: []string{"peanuts", "sesame"},
+				ID:        123,
+			}).
+			Return(database.UpdateUserPreferencesRow{Allergies: []string{"peanuts", "sesame"}}, nil)
+
+		allergies := []Allergen{Sesame, Peanuts, Sesame}
+		resp, err := server.PatchApiUserPreferences(newCtx(mockDB), PatchApiUserPreferencesRequestObject{
+			Body: &UpdateUserPreferencesRequest{Allergies: &allergies},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PatchApiUserPreferences200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		if !reflect.DeepEqual(v.Allergies, []Allergen{Peanuts, Sesame}) {
+			t.Errorf("expected allergies [peanuts sesame], got %v", v.Allergies)
+		}
+	})
+
+	t.Run("patch time zone reschedules weekly report", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			UpdateUserPreferences(gomock.Any(), database.UpdateUserPreferencesParams{
+				TimeZone: pgtype.Text{String: "Asia/Tokyo", Valid: true},
+				// Monday 08:00 in Tokyo is 23:00 UTC on Sunday.
+				WeeklyReportDueAt: pgtype.Timestamptz{
+					Time:  time.Date(2026, time.January, 11, 23, 0, 0, 0, time.UTC),
+					Valid: true,
+				},
+				ID: 123,
+			}).
+			Return(database.UpdateUserPreferencesRow{TimeZone: "Asia/Tokyo"}, nil)
+
+		ctx := newCtx(mockDB)
+		env.EnvFromCtx(ctx).Clock = clock.NewFrozen(time.Date(2026, time.January, 7, 12, 0, 0, 0, time.UTC))
+		timeZone := "Asia/Tokyo"
+		resp, err := server.PatchApiUserPreferences(ctx, PatchApiUserPreferencesRequestObject{
+			Body: &UpdateUserPreferencesRequest{TimeZone: &timeZone},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PatchApiUserPreferences200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		if v.TimeZone != "Asia/Tokyo" {
+			t.Errorf("expected time zone Asia/Tokyo, got %q", v.TimeZone)
+		}
+	})
+
+	t.Run("patch unknown time zone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+
+		timeZone := "Mars/Olympus_Mons"
+		resp, err := server.PatchApiUserPreferences(newCtx(mockDB), PatchApiUserPreferencesRequestObject{
+			Body: &UpdateUserPreferencesRequest{TimeZone: &timeZone},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PatchApiUserPreferences422JSONResponse)
+		if !ok {
+			t.Fatalf("expected 422 response, got %T", resp)
+		}
+		if v.Code != apiError.InvalidTimeZone.String() {
+			t.Errorf("expected code %s, got %s", apiError.InvalidTimeZone.String(), v.Code)
+		}
+	})
+
+	t.Run("patch database error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			UpdateUserPreferences(gomock.Any(), gomock.Any()).
+			Return(database.UpdateUserPreferencesRow{}, errors.New("database error"))
+
+		resp, err := server.PatchApiUserPreferences(newCtx(mockDB), PatchApiUserPreferencesRequestObject{
+			Body: &UpdateUserPreferencesRequest{TemperatureUnit: nullable.NewNullableWithValue(F)},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PatchApiUserPreferences500JSONResponse)
+		if !ok {
+			t.Fatalf("expected 500 response, got %T", resp)
+		}
+		if v.Code != apiError.InternalServerError.String() {
+			t.Errorf("expected code %s, got %s", apiError.InternalServerError.String(), v.Code)
+		}
+	})
+}
+
+func TestGetApiReportsUnsubscribe(t *testing.T) {
+	server := NewServer()
+	secret := config.AppSecretValue("test-secret-32-bytes-long-12345")
+
+	newCtx := func(mockDB *database.MockQuerier) context.Context {
+		ctx := context.Background()
+		ctx = requestid.InjectRequestID(ctx, 12345)
+		e := &env.Env{
+			Logger: log.NullLogger(),
+			Database: &database.Database{
+				Querier: mockDB,
+			},
+		}
+		e.Config.AppSecret.Value = &secret
+		return env.WithCtx(ctx, e)
+	}
+
+	t.Run("valid link asks for confirmation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		signature := report.Sign([]byte(secret), 123)
+
+		// Following the link unsubscribes no one.
+		resp, err := server.GetApiReportsUnsubscribe(newCtx(mockDB), GetApiReportsUnsubscribeRequestObject{
+			Params: GetApiReportsUnsubscribeParams{
+				User:      123,
+				Signature: signature,
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(GetApiReportsUnsubscribe200TexthtmlResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		page, err := io.ReadAll(v.Body)
+		if err != nil {
+			t.Fatalf("reading page: %v", err)
+		}
+		if !strings.Contains(string(page), `method="post"`) || !strings.Contains(string(page), "user=123") {
+			t.Errorf("expected page to post the link back, got %s", page)
+		}
+	})
+
+	t.Run("signature of another user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+
+		resp, err := server.GetApiReportsUnsubscribe(newCtx(mockDB), GetApiReportsUnsubscribeRequestObject{
+			Params: GetApiReportsUnsubscribeParams{
+				User:      123,
+				Signature: report.Sign([]byte(secret), 456),
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := resp.(GetApiReportsUnsubscribe403JSONResponse); !ok {
+			t.Fatalf("expected 403 response, got %T", resp)
+		}
+	})
+}
+
+func TestPostApiReportsUnsubscribe(t *testing.T) {
+	server := NewServer()
+	secret := config.AppSecretValue("test-secret-32-bytes-long-12345")
+
+	newCtx := func(mockDB *database.MockQuerier) context.Context {
+		ctx := context.Background()
+		ctx = requestid.InjectRequestID(ctx, 12345)
+		e := &env.Env{
+			Logger: log.NullLogger(),
+			Database: &database.Database{
+				Querier: mockDB,
+			},
+		}
+		e.Config.AppSecret.Value = &secret
+		return env.WithCtx(ctx, e)
+	}
+
+	t.Run("valid link unsubscribes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().UnsubscribeWeeklyReport(gomock.Any(), int64(123)).Return(int64(1), nil)
+
+		resp, err := server.PostApiReportsUnsubscribe(newCtx(mockDB), PostApiReportsUnsubscribeRequestObject{
+			Params: PostApiReportsUnsubscribeParams{
+				User:      123,
+				Signature: report.Sign([]byte(secret), 123),
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := resp.(PostApiReportsUnsubscribe200TexthtmlResponse); !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+	})
+
+	t.Run("signature of another user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+
+		resp, err := server.PostApiReportsUnsubscribe(newCtx(mockDB), PostApiReportsUnsubscribeRequestObject{
+			Params: PostApiReportsUnsubscribeParams{
+				User:      123,
+				Signature: report.Sign([]byte(secret), 456),
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, ok := resp.(PostApiReportsUnsubscribe403JSONResponse)
+		if !ok {
+			t.Fatalf("expected 403 response, got %T", resp)
+		}
+		if v.Code != apiError.InvalidUnsubscribeLink.String() {
+			t.Errorf("expected code %s, got %s", apiError.InvalidUnsubscribeLink.String(), v.Code)
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			UnsubscribeWeeklyReport(gomock.Any(), int64(123)).
+			Return(int64(0), errors.New("database error"))
+
+		resp, err := server.PostApiReportsUnsubscribe(newCtx(mockDB), PostApiReportsUnsubscribeRequestObject{
+			Params: PostApiReportsUnsubscribeParams{
+				User:      123,
+				Signature: report.Sign([]byte(secret), 123),
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := resp.(PostApiReportsUnsubscribe500JSONResponse); !ok {
+			t.Fatalf("expected 500 response, got %T", resp)
+		}
+	})
+}
+
 func TestGetApiUser_FieldMapping(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -748,11 +1166,24 @@ func TestPostApiUserInvite(t *testing.T) {
 						}
 						return int64(456), nil
 					})
+				mockDB.EXPECT().
+					GetInstanceBranding(gomock.Any(), int32(branding.ID)).
+					Return(database.InstanceBranding{ID: branding.ID, Name: "Test Kitchen"}, nil)
 			},
 			smtpSetup: func() {
+				mockDB.EXPECT().
+					CreateDelivery(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.CreateDeliveryParams) (database.Delivery, error) {
+						return database.Delivery{ID: 1, Kind: params.Kind, Subject: params.Subject,
+							Payload: params.Payload}, nil
+					})
+				mockDB.EXPECT().MarkDeliverySent(gomock.Any(), gomock.Any()).Return(nil)
 				mockSMTP.EXPECT().
-					Send(gomock.Eq([]string{"newuser@example.com"}), gomock.Eq("WeCook Invitation"), gomock.Any()).
-					DoAndReturn(func(to []string, subject, body string) error {
+					Send(gomock.Eq([]string{"newuser@example.com"}), gomock.Eq("Test Kitchen Invitation"), gomock.Any()).
+					DoAndReturn(func(to []string, subject, body string, _ ...email.Header) error {
+						if !strings.Contains(body, "sign up for Test Kitchen") {
+							t.Errorf("expected instance name in email body, got: %s", body)
+						}
 						if !strings.Contains(body, "http://localhost:5173/signup?code=") {
 							t.Errorf("expected invite link in email body, got: %s", body)
 						}
@@ -812,11 +1243,29 @@ func TestPostApiUserInvite(t *testing.T) {
 				mockDB.EXPECT().
 					CreateInviteCode(gomock.Any(), gomock.Any()).
 					Return(int64(456), nil)
+				mockDB.EXPECT().
+					GetInstanceBranding(gomock.Any(), int32(branding.ID)).
+					Return(database.InstanceBranding{}, errors.New("database connection error"))
 			},
 			smtpSetup: func() {
+				mockDB.EXPECT().
+					CreateDelivery(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.CreateDeliveryParams) (database.Delivery, error) {
+						return database.Delivery{ID: 1, Kind: params.Kind, Subject: params.Subject,
+							Payload: params.Payload}, nil
+					})
 				mockSMTP.EXPECT().
-					Send(gomock.Any(), gomock.Any(), gomock.Any()).
+					Send(gomock.Any(), gomock.Eq("WeCook Invitation"), gomock.Any()).
 					Return(errors.New("SMTP connection failed"))
+				// Invites are not retried; the admin sends another.
+				mockDB.EXPECT().
+					MarkDeliveryFailed(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.MarkDeliveryFailedParams) error {
+						if params.Status != "dead" || params.LastError.String != "SMTP connection failed" {
+							t.Errorf("expected dead-lettered delivery, got %+v", params)
+						}
+						return nil
+					})
 			},
 			wantStatus: 500,
 			wantCode:   apiError.InternalServerError.String(),
@@ -1150,7 +1599,7 @@ func TestPostApiSignup(t *testing.T) {
 					CreateUser(gomock.Any(), gomock.Any()).
 					Return(int64(0), pgErr)
 			},
-			wantStatus: 422,
+			wantStatus: 409,
 			wantCode:   apiError.EmailConflict.String(),
 			wantError:  false,
 		},
@@ -1335,6 +1784,13 @@ func TestPostApiSignup(t *testing.T) {
 				if tt.wantCode != "" && v.Code != tt.wantCode {
 					t.Errorf("expected code %s, got %s", tt.wantCode, v.Code)
 				}
+			case PostApiSignup409JSONResponse:
+				if tt.wantStatus != 409 {
+					t.Errorf("expected status %d, got 409", tt.wantStatus)
+				}
+				if tt.wantCode != "" && v.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %s", tt.wantCode, v.Code)
+				}
 			case PostApiSignup500JSONResponse:
 				if tt.wantStatus != 500 {
 					t.Errorf("expected status %d, got 500", tt.wantStatus)
@@ -1532,7 +1988,7 @@ func TestPatchApiUserPassword(t *testing.T) {
 			dbSetup: func() {
 				mockDB.EXPECT().GetUserPasswordHash(gomock.Any(), int64(123)).Return(currentPasswordHash, nil)
 			},
-			wantStatus: 403,
+			wantStatus: 422,
 			wantCode:   apiError.InvalidPassword.String(),
 			wantError:  false,
 		},
@@ -1700,9 +2156,11 @@ func TestDeleteApiUserId(t *testing.T) {
 	tests := []struct {
 		name       string
 		userId     int64
+		dryRun     bool
 		setup      func()
 		wantStatus int
 		wantCode   string
+		wantDryRun DeleteUserDryRunResponse
 	}{
 		{
 			name:   "successful deletion with no images",
@@ -1723,6 +2181,16 @@ func TestDeleteApiUserId(t *testing.T) {
 				mockDB.EXPECT().
 					DeleteUser(gomock.Any(), int64(123)).
 					Return(int64(1), nil)
+
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), database.CreateAuditEventParams{
+						ActorID:    pgtype.Int8{Int64: 1, Valid: true},
+						Action:     "user.delete",
+						TargetType: "user",
+						TargetID:   pgtype.Int8{Int64: 123, Valid: true},
+						Metadata:   []byte("{}"),
+					}).
+					Return(int64(1), nil)
 			},
 			wantStatus: 204,
 		},
@@ -1758,6 +2226,16 @@ func TestDeleteApiUserId(t *testing.T) {
 					DeleteUser(gomock.Any(), int64(456)).
 					Return(int64(1), nil)
 
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), database.CreateAuditEventParams{
+						ActorID:    pgtype.Int8{Int64: 1, Valid: true},
+						Action:     "user.delete",
+						TargetType: "user",
+						TargetID:   pgtype.Int8{Int64: 456, Valid: true},
+						Metadata:   []byte("{}"),
+					}).
+					Return(int64(1), nil)
+
 				// Expect deletion of all 5 images
 				mockFileStore.EXPECT().
 					DeleteKey("/files/cover1.jpg").
@@ -1802,6 +2280,16 @@ func TestDeleteApiUserId(t *testing.T) {
 					DeleteUser(gomock.Any(), int64(789)).
 					Return(int64(1), nil)
 
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), database.CreateAuditEventParams{
+						ActorID:    pgtype.Int8{Int64: 1, Valid: true},
+						Action:     "user.delete",
+						TargetType: "user",
+						TargetID:   pgtype.Int8{Int64: 789, Valid: true},
+						Metadata:   []byte("{}"),
+					}).
+					Return(int64(1), nil)
+
 				// Only valid image should be deleted
 				mockFileStore.EXPECT().
 					DeleteKey("/files/cover1.jpg").
@@ -1833,6 +2321,16 @@ func TestDeleteApiUserId(t *testing.T) {
 					DeleteUser(gomock.Any(), int64(999)).
 					Return(int64(1), nil)
 
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), database.CreateAuditEventParams{
+						ActorID:    pgtype.Int8{Int64: 1, Valid: true},
+						Action:     "user.delete",
+						TargetType: "user",
+						TargetID:   pgtype.Int8{Int64: 999, Valid: true},
+						Metadata:   []byte("{}"),
+					}).
+					Return(int64(1), nil)
+
 				// Image deletion fails but operation should still succeed
 				mockFileStore.EXPECT().
 					DeleteKey("/files/cover1.jpg").
@@ -1863,6 +2361,35 @@ func TestDeleteApiUserId(t *testing.T) {
 			wantStatus: 404,
 			wantCode:   apiError.UserNotFound.String(),
 		},
+		{
+			name:   "audit failure rolls back deletion",
+			userId: 405,
+			setup: func() {
+				mockDB.EXPECT().
+					GetUserRecipeImages(gomock.Any(), pgtype.Int8{Int64: 405, Valid: true}).
+					Return([]pgtype.Text{{String: "/files/cover1.jpg", Valid: true}}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeIngredientImages(gomock.Any(), pgtype.Int8{Int64: 405, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeStepImages(gomock.Any(), pgtype.Int8{Int64: 405, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					DeleteUser(gomock.Any(), int64(405)).
+					Return(int64(1), nil)
+
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					Return(int64(0), errors.New("database error"))
+
+				// Images must not be deleted
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
 		{
 			name:   "database error getting cover images",
 			userId: 500,
@@ -1931,6 +2458,151 @@ func TestDeleteApiUserId(t *testing.T) {
 			wantStatus: 500,
 			wantCode:   apiError.InternalServerError.String(),
 		},
+		{
+			name:   "dry run reports affected resources without deleting",
+			userId: 600,
+			dryRun: true,
+			setup: func() {
+				mockDB.EXPECT().
+					GetUserRecipeImages(gomock.Any(), pgtype.Int8{Int64: 600, Valid: true}).
+					Return([]pgtype.Text{
+						{String: "/files/cover1.jpg", Valid: true},
+						{String: "", Valid: false},
+					}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeIngredientImages(gomock.Any(), pgtype.Int8{Int64: 600, Valid: true}).
+					Return([]pgtype.Text{{String: "/files/ingredient1.jpg", Valid: true}}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeStepImages(gomock.Any(), pgtype.Int8{Int64: 600, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserById(gomock.Any(), int64(600)).
+					Return(database.GetUserByIdRow{ID: 600}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeCount(gomock.Any(), pgtype.Int8{Int64: 600, Valid: true}).
+					Return(int64(2), nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeIDSample(gomock.Any(), database.GetUserRecipeIDSampleParams{
+						UserID: pgtype.Int8{Int64: 600, Valid: true},
+						Limit:  10,
+					}).
+					Return([]int64{7, 8}, nil)
+
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, arg database.CreateAuditEventParams) (int64, error) {
+						if !arg.DryRun {
+							t.Errorf("expected dry run audit event")
+						}
+						return 1, nil
+					})
+
+				// DeleteUser and DeleteKey must not be called
+			},
+			wantStatus: 200,
+			wantDryRun: DeleteUserDryRunResponse{
+				UserId:          600,
+				RecipeCount:     2,
+				ImageCount:      2,
+				SampleRecipeIds: []int64{7, 8},
+			},
+		},
+		{
+			name:   "dry run succeeds when audit event fails",
+			userId: 601,
+			dryRun: true,
+			setup: func() {
+				mockDB.EXPECT().
+					GetUserRecipeImages(gomock.Any(), pgtype.Int8{Int64: 601, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeIngredientImages(gomock.Any(), pgtype.Int8{Int64: 601, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeStepImages(gomock.Any(), pgtype.Int8{Int64: 601, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserById(gomock.Any(), int64(601)).
+					Return(database.GetUserByIdRow{ID: 601}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeCount(gomock.Any(), pgtype.Int8{Int64: 601, Valid: true}).
+					Return(int64(0), nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeIDSample(gomock.Any(), gomock.Any()).
+					Return(nil, nil)
+
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					Return(int64(0), errors.New("database connection error"))
+			},
+			wantStatus: 200,
+			wantDryRun: DeleteUserDryRunResponse{
+				UserId:          601,
+				SampleRecipeIds: []int64{},
+			},
+		},
+		{
+			name:   "dry run user not found",
+			userId: 602,
+			dryRun: true,
+			setup: func() {
+				mockDB.EXPECT().
+					GetUserRecipeImages(gomock.Any(), pgtype.Int8{Int64: 602, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeIngredientImages(gomock.Any(), pgtype.Int8{Int64: 602, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeStepImages(gomock.Any(), pgtype.Int8{Int64: 602, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserById(gomock.Any(), int64(602)).
+					Return(database.GetUserByIdRow{}, pgx.ErrNoRows)
+			},
+			wantStatus: 404,
+			wantCode:   apiError.UserNotFound.String(),
+		},
+		{
+			name:   "dry run database error counting recipes",
+			userId: 603,
+			dryRun: true,
+			setup: func() {
+				mockDB.EXPECT().
+					GetUserRecipeImages(gomock.Any(), pgtype.Int8{Int64: 603, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeIngredientImages(gomock.Any(), pgtype.Int8{Int64: 603, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeStepImages(gomock.Any(), pgtype.Int8{Int64: 603, Valid: true}).
+					Return([]pgtype.Text{}, nil)
+
+				mockDB.EXPECT().
+					GetUserById(gomock.Any(), int64(603)).
+					Return(database.GetUserByIdRow{ID: 603}, nil)
+
+				mockDB.EXPECT().
+					GetUserRecipeCount(gomock.Any(), pgtype.Int8{Int64: 603, Valid: true}).
+					Return(int64(0), errors.New("database connection error"))
+			},
+			wantStatus: 500,
+			wantCode:   apiError.InternalServerError.String(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -1945,9 +2617,13 @@ func TestDeleteApiUserId(t *testing.T) {
 			ctx := context.Background()
 			ctx = env.WithCtx(ctx, e)
 			ctx = requestid.InjectRequestID(ctx, 12345)
+			ctx = token.UserIDWithCtx(ctx, 1)
 
 			request := DeleteApiUserIdRequestObject{
 				Id: tt.userId,
+				Params: DeleteApiUserIdParams{
+					DryRun: &tt.dryRun,
+				},
 			}
 
 			resp, err := server.DeleteApiUserId(ctx, request)
@@ -1956,6 +2632,13 @@ func TestDeleteApiUserId(t *testing.T) {
 			}
 
 			switch v := resp.(type) {
+			case DeleteApiUserId200JSONResponse:
+				if tt.wantStatus != 200 {
+					t.Errorf("expected status %d, got 200", tt.wantStatus)
+				}
+				if !reflect.DeepEqual(DeleteUserDryRunResponse(v), tt.wantDryRun) {
+					t.Errorf("expected dry run %+v, got %+v", tt.wantDryRun, v)
+				}
 			case DeleteApiUserId204Response:
 				if tt.wantStatus != 204 {
 					t.Errorf("expected status %d, got 204", tt.wantStatus)
@@ -1980,3 +2663,200 @@ func TestDeleteApiUserId(t *testing.T) {
 		})
 	}
 }
+
+func TestGetApiUserExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(mockDB *database.MockQuerier)
+		validate func(t *testing.T, resp GetApiUserExportResponseObject)
+	}{
+		{
+			name: "exports own data",
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					GetUserForExport(gomock.Any(), int64(7)).
+					Return(database.GetUserForExportRow{ID: 7, Role: database.RoleUser}, nil)
+				mockDB.EXPECT().GetRecipesByOwner(gomock.Any(), database.GetRecipesByOwnerParams{UserID: 7}).Return(nil, nil)
+				mockDB.EXPECT().GetAppliances(gomock.Any(), int64(7)).Return(nil, nil)
+				mockDB.EXPECT().
+					GetInstanceBranding(gomock.Any(), int32(branding.ID)).
+					Return(database.InstanceBranding{ID: branding.ID, Name: branding.DefaultName}, nil)
+			},
+			validate: func(t *testing.T, resp GetApiUserExportResponseObject) {
+				v, ok := resp.(GetApiUserExport200ApplicationzipResponse)
+				if !ok {
+					t.Fatalf("expected 200 response, got %T", resp)
+				}
+				if v.ContentLength == 0 {
+					t.Error("expected a non-empty archive")
+				}
+				if v.Headers.ContentDisposition != `attachment; filename="wecook-user-7.zip"` {
+					t.Errorf("unexpected content disposition %q", v.Headers.ContentDisposition)
+				}
+			},
+		},
+		{
+			name: "user not found",
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					GetUserForExport(gomock.Any(), int64(7)).
+					Return(database.GetUserForExportRow{}, pgx.ErrNoRows)
+			},
+			validate: func(t *testing.T, resp GetApiUserExportResponseObject) {
+				v, ok := resp.(GetApiUserExport404JSONResponse)
+				if !ok {
+					t.Fatalf("expected 404 response, got %T", resp)
+				}
+				if v.Code != apiError.UserNotFound.String() {
+					t.Errorf("expected code %s, got %s", apiError.UserNotFound.String(), v.Code)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := database.NewMockQuerier(ctrl)
+			tt.setup(mockDB)
+
+			ctx := context.Background()
+			ctx = requestid.InjectRequestID(ctx, 12345)
+			ctx = token.UserIDWithCtx(ctx, 7)
+			ctx = env.WithCtx(ctx, &env.Env{
+				Logger:   log.NullLogger(),
+				Database: &database.Database{Querier: mockDB},
+			})
+
+			resp, err := NewServer().GetApiUserExport(ctx, GetApiUserExportRequestObject{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.validate(t, resp)
+		})
+	}
+}
+
+func TestPostApiUserIdExport(t *testing.T) {
+	tests := []struct {
+		name          string
+		justification string
+		setup         func(mockDB *database.MockQuerier)
+		validate      func(t *testing.T, resp PostApiUserIdExportResponseObject)
+	}{
+		{
+			name:          "exports user and records justification",
+			justification: " Ticket 42 ",
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					GetUserForExport(gomock.Any(), int64(123)).
+					Return(database.GetUserForExportRow{ID: 123, Role: database.RoleUser}, nil)
+				mockDB.EXPECT().GetRecipesByOwner(gomock.Any(), database.GetRecipesByOwnerParams{UserID: 123}).Return(nil, nil)
+				mockDB.EXPECT().GetAppliances(gomock.Any(), int64(123)).Return(nil, nil)
+				mockDB.EXPECT().
+					GetInstanceBranding(gomock.Any(), int32(branding.ID)).
+					Return(database.InstanceBranding{ID: branding.ID, Name: branding.DefaultName}, nil)
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.CreateAuditEventParams) (int64, error) {
+						if params.Action != "user.export" || params.TargetID.Int64 != 123 ||
+							!strings.Contains(string(params.Metadata), `"justification":"Ticket 42"`) {
+							t.Errorf("unexpected audit event %+v", params)
+						}
+						return 1, nil
+					})
+			},
+			validate: func(t *testing.T, resp PostApiUserIdExportResponseObject) {
+				v, ok := resp.(PostApiUserIdExport200ApplicationzipResponse)
+				if !ok {
+					t.Fatalf("expected 200 response, got %T", resp)
+				}
+				if v.ContentLength == 0 {
+					t.Error("expected a non-empty archive")
+				}
+				if v.Headers.ContentDisposition != `attachment; filename="wecook-user-123.zip"` {
+					t.Errorf("unexpected content disposition %q", v.Headers.ContentDisposition)
+				}
+			},
+		},
+		{
+			name:          "blank justification",
+			justification: "   ",
+			setup:         func(mockDB *database.MockQuerier) {},
+			validate: func(t *testing.T, resp PostApiUserIdExportResponseObject) {
+				if _, ok := resp.(PostApiUserIdExport400JSONResponse); !ok {
+					t.Errorf("expected 400 response, got %T", resp)
+				}
+			},
+		},
+		{
+			name:          "user not found",
+			justification: "Ticket 42",
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					GetUserForExport(gomock.Any(), int64(123)).
+					Return(database.GetUserForExportRow{}, pgx.ErrNoRows)
+			},
+			validate: func(t *testing.T, resp PostApiUserIdExportResponseObject) {
+				v, ok := resp.(PostApiUserIdExport404JSONResponse)
+				if !ok {
+					t.Fatalf("expected 404 response, got %T", resp)
+				}
+				if v.Code != apiError.UserNotFound.String() {
+					t.Errorf("expected code %s, got %s", apiError.UserNotFound.String(), v.Code)
+				}
+			},
+		},
+		{
+			name:          "export withheld when audit event fails",
+			justification: "Ticket 42",
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					GetUserForExport(gomock.Any(), int64(123)).
+					Return(database.GetUserForExportRow{ID: 123}, nil)
+				mockDB.EXPECT().GetRecipesByOwner(gomock.Any(), database.GetRecipesByOwnerParams{UserID: 123}).Return(nil, nil)
+				mockDB.EXPECT().GetAppliances(gomock.Any(), int64(123)).Return(nil, nil)
+				mockDB.EXPECT().
+					GetInstanceBranding(gomock.Any(), int32(branding.ID)).
+					Return(database.InstanceBranding{ID: branding.ID, Name: branding.DefaultName}, nil)
+				mockDB.EXPECT().
+					CreateAuditEvent(gomock.Any(), gomock.Any()).
+					Return(int64(0), errors.New("database error"))
+			},
+			validate: func(t *testing.T, resp PostApiUserIdExportResponseObject) {
+				if _, ok := resp.(PostApiUserIdExport500JSONResponse); !ok {
+					t.Errorf("expected 500 response, got %T", resp)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := database.NewMockQuerier(ctrl)
+			tt.setup(mockDB)
+
+			ctx := context.Background()
+			ctx = requestid.InjectRequestID(ctx, 12345)
+			ctx = token.UserIDWithCtx(ctx, 1)
+			ctx = env.WithCtx(ctx, &env.Env{
+				Logger:   log.NullLogger(),
+				Database: &database.Database{Querier: mockDB},
+			})
+
+			resp, err := NewServer().PostApiUserIdExport(ctx, PostApiUserIdExportRequestObject{
+				Id:   123,
+				Body: &PostApiUserIdExportJSONRequestBody{Justification: tt.justification},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.validate(t, resp)
+		})
+	}
+}
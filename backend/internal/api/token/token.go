@@ -78,7 +78,7 @@ func NewRefreshToken(userid int64) (string, error) {
 }
 
 func NewAccessToken(params mJwt.JWTParams, env *env.Env) (string, error) {
-	token, err := mJwt.GenerateJWT(params, []byte(*env.Config.AppSecret.Value), "1")
+	token, err := mJwt.GenerateJWT(params, []byte(*env.Config.AppSecret.Value), "1", env.Now())
 	if err != nil {
 		return "", fmt.Errorf("generating access token: %w", err)
 	}
@@ -0,0 +1,67 @@
+// Package version describes the versions of the public API.
+package version
+
+import "time"
+
+const (
+	// Current is the API version served by this build.
+	Current = "v1"
+	// Prefix is the path prefix of the current API version. Routes are
+	// also served without the version segment for existing clients.
+	Prefix = "/api/" + Current
+	// Header is the response header reporting the API version.
+	Header = "API-Version"
+)
+
+// Status is the lifecycle stage of an API version.
+type Status string
+
+const (
+	StatusCurrent    Status = "current"
+	StatusDeprecated Status = "deprecated"
+)
+
+// Version describes a single API version.
+type Version struct {
+	Name   string
+	Prefix string
+	Status Status
+	// Sunset is when the version stops being served, if scheduled.
+	Sunset *time.Time
+}
+
+// Versions lists every API version this build serves.
+var Versions = []Version{
+	{Name: Current, Prefix: Prefix, Status: StatusCurrent},
+}
+
+// Capabilities lists optional features clients can detect at runtime
+// instead of comparing server versions.
+var Capabilities = []string{
+	"user-delete-dry-run",
+	"recipe-tags",
+	"tag-suggestions",
+	"meal-prep-plan",
+	"signed-uploads",
+	"error-catalog",
+	"unit-conversion",
+	"step-temperatures",
+	"appliances",
+	"weekly-report",
+	"ingredient-format",
+	"undo",
+	"user-export",
+	"deliveries",
+	"recipe-schema",
+	"activity",
+	"pagination",
+	"stock-images",
+	"lite",
+	"federation",
+	"allergy-warnings",
+	"time-zones",
+	"tag-management",
+	"completeness",
+	"branding",
+	"demo",
+}
@@ -0,0 +1,170 @@
+// Package appliance sends commands, such as preheating an oven or starting
+// a timer, to users' kitchen appliances through provider adapters.
+package appliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matt-dz/wecook/internal/http"
+	"github.com/matt-dz/wecook/internal/temperature"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown appliance provider")
+	ErrInvalidEndpoint = errors.New("invalid appliance endpoint")
+	ErrInvalidCommand  = errors.New("invalid appliance command")
+	ErrScopeDenied     = errors.New("action not in appliance scopes")
+)
+
+// Action is something an appliance can be asked to do. An appliance is
+// registered with the actions it accepts, its scopes.
+type Action string
+
+const (
+	ActionPreheat  Action = "preheat"
+	ActionSetTimer Action = "set_timer"
+)
+
+// MaxTimer is the longest timer that can be set.
+const MaxTimer = 24 * time.Hour
+
+// Valid reports whether a is a known action.
+func (a Action) Valid() bool {
+	return a == ActionPreheat || a == ActionSetTimer
+}
+
+// Appliance is a registered appliance.
+type Appliance struct {
+	ID       int64
+	Name     string
+	Endpoint string
+	Scopes   []Action
+	// Token is sent with every command so the receiver can authenticate
+	// WeCook. It only ever accompanies actions within Scopes.
+	Token string
+}
+
+// Allows reports whether action is within the appliance's scopes.
+func (a Appliance) Allows(action Action) bool {
+	return slices.Contains(a.Scopes, action)
+}
+
+// Command is an action sent to an appliance.
+type Command struct {
+	Action Action
+	// Temperature is the preheat temperature.
+	Temperature *temperature.Temperature
+	// Duration is the timer length.
+	Duration time.Duration
+	// Label describes the command, such as the step being cooked.
+	Label string
+	// RecipeID and StepID identify the step that triggered the command,
+	// if any.
+	RecipeID int64
+	StepID   int64
+}
+
+// Validate checks that the command has what its action needs.
+func (c Command) Validate() error {
+	switch c.Action {
+	case ActionPreheat:
+		if c.Temperature == nil {
+			return fmt.Errorf("%w: preheat requires a temperature", ErrInvalidCommand)
+		}
+		if err := c.Temperature.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCommand, err)
+		}
+	case ActionSetTimer:
+		if c.Duration <= 0 || c.Duration > MaxTimer {
+			return fmt.Errorf("%w: timer must be between 1 second and %s", ErrInvalidCommand, MaxTimer)
+		}
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidCommand, c.Action)
+	}
+	return nil
+}
+
+// Provider delivers commands to appliances.
+type Provider interface {
+	Send(ctx context.Context, appliance Appliance, command Command) error
+}
+
+// ProviderName identifies a provider adapter.
+type ProviderName string
+
+const (
+	// Webhook posts commands as JSON to the appliance's endpoint.
+	Webhook ProviderName = "webhook"
+)
+
+// Valid reports whether p is a known provider.
+func (p ProviderName) Valid() bool {
+	return p == Webhook
+}
+
+// NewProvider returns the adapter for the named provider.
+func NewProvider(name ProviderName, doer http.HTTPDoer) (Provider, error) {
+	switch name {
+	case Webhook:
+		return NewWebhook(doer), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+}
+
+// AddressPolicy returns the addresses appliance endpoints may be reached
+// at. Loopback and link-local addresses are always refused, and private
+// addresses unless the operator allows appliances on the local network.
+func AddressPolicy(allowPrivate bool) http.AddressPolicy {
+	if allowPrivate {
+		return http.NetworkAddress
+	}
+	return http.PublicAddress
+}
+
+// ValidateEndpoint checks that endpoint is an absolute http(s) URL whose
+// host is not an address refused by AddressPolicy. Hosts given by name are
+// checked when they are dialed.
+func ValidateEndpoint(endpoint string, allowPrivate bool) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEndpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: must be an http or https URL", ErrInvalidEndpoint)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: must not be this host", ErrInvalidEndpoint)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !AddressPolicy(allowPrivate)(addr) {
+		return fmt.Errorf("%w: address %s is not allowed", ErrInvalidEndpoint, addr)
+	}
+	return nil
+}
+
+// Check validates command and checks it against the appliance's scopes.
+func Check(appliance Appliance, command Command) error {
+	if err := command.Validate(); err != nil {
+		return err
+	}
+	if !appliance.Allows(command.Action) {
+		return fmt.Errorf("%w: %q", ErrScopeDenied, command.Action)
+	}
+	return nil
+}
+
+// Send checks command and delivers it through provider.
+func Send(ctx context.Context, provider Provider, appliance Appliance, command Command) error {
+	if err := Check(appliance, command); err != nil {
+		return err
+	}
+	return provider.Send(ctx, appliance, command)
+}
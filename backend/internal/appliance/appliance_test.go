@@ -0,0 +1,223 @@
+package appliance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/matt-dz/wecook/internal/temperature"
+)
+
+type recordingProvider struct {
+	sent []Command
+}
+
+func (p *recordingProvider) Send(_ context.Context, _ Appliance, command Command) error {
+	p.sent = append(p.sent, command)
+	return nil
+}
+
+func TestSend(t *testing.T) {
+	oven := Appliance{ID: 1, Name: "Oven", Scopes: []Action{ActionPreheat}}
+	preheat := Command{
+		Action:      ActionPreheat,
+		Temperature: &temperature.Temperature{Value: 350, Unit: temperature.Fahrenheit},
+	}
+
+	tests := []struct {
+		name      string
+		appliance Appliance
+		command   Command
+		wantErr   error
+	}{
+		{name: "preheat within scope", appliance: oven, command: preheat},
+		{
+			name:      "timer outside scope",
+			appliance: oven,
+			command:   Command{Action: ActionSetTimer, Duration: time.Minute},
+			wantErr:   ErrScopeDenied,
+		},
+		{
+			name:      "preheat without temperature",
+			appliance: oven,
+			command:   Command{Action: ActionPreheat},
+			wantErr:   ErrInvalidCommand,
+		},
+		{
+			name:      "preheat out of range",
+			appliance: oven,
+			command: Command{
+				Action:      ActionPreheat,
+				Temperature: &temperature.Temperature{Value: 2000, Unit: temperature.Celsius},
+			},
+			wantErr: ErrInvalidCommand,
+		},
+		{
+			name:      "timer too long",
+			appliance: Appliance{Scopes: []Action{ActionSetTimer}},
+			command:   Command{Action: ActionSetTimer, Duration: MaxTimer + time.Second},
+			wantErr:   ErrInvalidCommand,
+		},
+		{
+			name:      "unknown action",
+			appliance: oven,
+			command:   Command{Action: "broil"},
+			wantErr:   ErrInvalidCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &recordingProvider{}
+			err := Send(context.Background(), provider, tt.appliance, tt.command)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && len(provider.sent) != 1 {
+				t.Errorf("expected command to be sent, got %d sends", len(provider.sent))
+			}
+			if tt.wantErr != nil && len(provider.sent) != 0 {
+				t.Errorf("expected no sends, got %d", len(provider.sent))
+			}
+		})
+	}
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint     string
+		allowPrivate bool
+		valid        bool
+	}{
+		{endpoint: "https://oven.local/wecook", valid: true},
+		{endpoint: "https://93.184.216.34/wecook", valid: true},
+		{endpoint: "http://192.168.1.20:8080/do", allowPrivate: true, valid: true},
+		{endpoint: "http://192.168.1.20:8080/do"},
+		{endpoint: "http://[fd00::20]/do"},
+		{endpoint: "http://127.0.0.1:8080/do", allowPrivate: true},
+		{endpoint: "http://[::1]/do", allowPrivate: true},
+		{endpoint: "http://localhost:8080/do", allowPrivate: true},
+		{endpoint: "http://169.254.169.254/latest/meta-data", allowPrivate: true},
+		{endpoint: "ftp://oven.local"},
+		{endpoint: "/relative/path"},
+		{endpoint: "https://"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateEndpoint(tt.endpoint, tt.allowPrivate)
+		if tt.valid && err != nil {
+			t.Errorf("expected %q to be valid, got %v", tt.endpoint, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidEndpoint) {
+			t.Errorf("expected %q to be invalid, got %v", tt.endpoint, err)
+		}
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	if _, err := NewProvider(Webhook, nil); err != nil {
+		t.Errorf("unexpected error for webhook provider: %v", err)
+	}
+	if _, err := NewProvider("smoke-signal", nil); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("expected ErrUnknownProvider, got %v", err)
+	}
+}
+
+func newTestClient() *retryablehttp.Client {
+	client := retryablehttp.NewClient()
+	client.RetryMax = 0
+	client.Logger = nil
+	return client
+}
+
+func TestWebhookSend(t *testing.T) {
+	var (
+		gotAuth    string
+		gotPayload WebhookPayload
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	appliance := Appliance{ID: 7, Endpoint: server.URL, Token: "secret", Scopes: []Action{ActionPreheat}}
+	err := NewWebhook(newTestClient()).Send(context.Background(), appliance, Command{
+		Action:      ActionPreheat,
+		Temperature: &temperature.Temperature{Value: 220, Unit: temperature.Celsius},
+		Label:       "Preheat the oven",
+		RecipeID:    3,
+		StepID:      4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", gotAuth)
+	}
+	want := WebhookPayload{
+		ApplianceID: 7,
+		Action:      ActionPreheat,
+		Temperature: &WebhookTemperature{Value: 220, Unit: "C"},
+		Label:       "Preheat the oven",
+		RecipeID:    3,
+		StepID:      4,
+	}
+	if gotPayload.Temperature == nil || *gotPayload.Temperature != *want.Temperature {
+		t.Fatalf("expected temperature %+v, got %+v", want.Temperature, gotPayload.Temperature)
+	}
+	gotPayload.Temperature, want.Temperature = nil, nil
+	if gotPayload != want {
+		t.Errorf("expected payload %+v, got %+v", want, gotPayload)
+	}
+}
+
+func TestWebhookPayloadCommand(t *testing.T) {
+	commands := []Command{
+		{
+			Action:      ActionPreheat,
+			Temperature: &temperature.Temperature{Value: 400, Unit: temperature.Fahrenheit},
+			Label:       "Preheat",
+			RecipeID:    3,
+			StepID:      4,
+		},
+		{Action: ActionSetTimer, Duration: 90 * time.Second},
+	}
+
+	for _, command := range commands {
+		got := NewWebhookPayload(Appliance{ID: 7}, command).Command()
+		if (got.Temperature == nil) != (command.Temperature == nil) ||
+			(got.Temperature != nil && *got.Temperature != *command.Temperature) {
+			t.Errorf("expected temperature %+v, got %+v", command.Temperature, got.Temperature)
+		}
+		got.Temperature, command.Temperature = nil, nil
+		if got != command {
+			t.Errorf("expected command %+v, got %+v", command, got)
+		}
+	}
+}
+
+func TestWebhookSendRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oven is offline", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	appliance := Appliance{ID: 7, Endpoint: server.URL, Scopes: []Action{ActionSetTimer}}
+	err := NewWebhook(newTestClient()).Send(context.Background(), appliance, Command{
+		Action:   ActionSetTimer,
+		Duration: 10 * time.Minute,
+	})
+	if err == nil {
+		t.Fatal("expected error for rejected webhook")
+	}
+}
@@ -0,0 +1,105 @@
+package appliance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	wcHttp "github.com/matt-dz/wecook/internal/http"
+	"github.com/matt-dz/wecook/internal/temperature"
+)
+
+// WebhookProvider posts commands to the appliance endpoint as JSON, with
+// the appliance token as a bearer token.
+type WebhookProvider struct {
+	http wcHttp.HTTPDoer
+}
+
+var _ Provider = (*WebhookProvider)(nil)
+
+// NewWebhook returns a webhook provider sending requests with doer.
+func NewWebhook(doer wcHttp.HTTPDoer) *WebhookProvider {
+	return &WebhookProvider{http: doer}
+}
+
+// WebhookPayload is the body of a webhook command.
+type WebhookPayload struct {
+	ApplianceID     int64               `json:"appliance_id"`
+	Action          Action              `json:"action"`
+	Temperature     *WebhookTemperature `json:"temperature,omitempty"`
+	DurationSeconds int64               `json:"duration_seconds,omitempty"`
+	Label           string              `json:"label,omitempty"`
+	RecipeID        int64               `json:"recipe_id,omitempty"`
+	StepID          int64               `json:"step_id,omitempty"`
+}
+
+// WebhookTemperature is a preheat temperature in a webhook payload.
+type WebhookTemperature struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// NewWebhookPayload returns the webhook body of a command.
+func NewWebhookPayload(appliance Appliance, command Command) WebhookPayload {
+	payload := WebhookPayload{
+		ApplianceID:     appliance.ID,
+		Action:          command.Action,
+		DurationSeconds: int64(command.Duration.Seconds()),
+		Label:           command.Label,
+		RecipeID:        command.RecipeID,
+		StepID:          command.StepID,
+	}
+	if command.Temperature != nil {
+		payload.Temperature = &WebhookTemperature{
+			Value: command.Temperature.Value,
+			Unit:  string(command.Temperature.Unit),
+		}
+	}
+	return payload
+}
+
+// Command returns the command the payload was made from.
+func (p WebhookPayload) Command() Command {
+	command := Command{
+		Action:   p.Action,
+		Duration: time.Duration(p.DurationSeconds) * time.Second,
+		Label:    p.Label,
+		RecipeID: p.RecipeID,
+		StepID:   p.StepID,
+	}
+	if p.Temperature != nil {
+		command.Temperature = &temperature.Temperature{
+			Value: p.Temperature.Value,
+			Unit:  temperature.Unit(p.Temperature.Unit),
+		}
+	}
+	return command
+}
+
+func (w *WebhookProvider) Send(ctx context.Context, appliance Appliance, command Command) error {
+	payload := NewWebhookPayload(appliance, command)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, appliance.Endpoint, body)
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+appliance.Token)
+
+	res, err := w.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending webhook: %w", err)
+	}
+	if err := wcHttp.ExpectStatus2xx(res); err != nil {
+		return fmt.Errorf("webhook rejected command: %w", err)
+	}
+	return res.Body.Close()
+}
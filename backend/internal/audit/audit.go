@@ -0,0 +1,78 @@
+// Package audit records administrative actions to the audit trail.
+//
+// The trail also records what users do with their recipes, and each
+// user's events make up their activity timeline.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/matt-dz/wecook/internal/database"
+)
+
+// Action identifies the operation being audited.
+type Action string
+
+const (
+	ActionDeleteUser       Action = "user.delete"
+	ActionExportUser       Action = "user.export"
+	ActionImportDensities  Action = "densities.import"
+	ActionRetryDelivery    Action = "delivery.retry"
+	ActionCreateRecipe     Action = "recipe.create"
+	ActionPublishRecipe    Action = "recipe.publish"
+	ActionUnpublishRecipe  Action = "recipe.unpublish"
+	ActionDeleteRecipe     Action = "recipe.delete"
+	ActionUploadStockImage Action = "stock_image.upload"
+	ActionDeleteStockImage Action = "stock_image.delete"
+)
+
+// TargetType identifies the kind of resource an action was applied to.
+type TargetType string
+
+const (
+	TargetUser           TargetType = "user"
+	TargetDensityVersion TargetType = "density_version"
+	TargetDelivery       TargetType = "delivery"
+	TargetRecipe         TargetType = "recipe"
+	TargetStockImage     TargetType = "stock_image"
+)
+
+// Event is a single entry in the audit trail.
+type Event struct {
+	ActorID    int64
+	Action     Action
+	TargetType TargetType
+	TargetID   int64
+	// DryRun is set when the action was only simulated.
+	DryRun bool
+	// Metadata is encoded as JSON. A nil value is stored as an empty object.
+	Metadata any
+}
+
+// Record writes the event to the audit trail.
+func Record(ctx context.Context, db database.Querier, event Event) error {
+	metadata := []byte("{}")
+	if event.Metadata != nil {
+		var err error
+		metadata, err = json.Marshal(event.Metadata)
+		if err != nil {
+			return fmt.Errorf("marshalling audit metadata: %w", err)
+		}
+	}
+
+	if _, err := db.CreateAuditEvent(ctx, database.CreateAuditEventParams{
+		ActorID:    pgtype.Int8{Int64: event.ActorID, Valid: true},
+		Action:     string(event.Action),
+		TargetType: string(event.TargetType),
+		TargetID:   pgtype.Int8{Int64: event.TargetID, Valid: true},
+		DryRun:     event.DryRun,
+		Metadata:   metadata,
+	}); err != nil {
+		return fmt.Errorf("creating audit event: %w", err)
+	}
+
+	return nil
+}
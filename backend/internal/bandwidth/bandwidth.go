@@ -0,0 +1,187 @@
+// Package bandwidth accounts for the bytes served to each client IP.
+package bandwidth
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RealIPHeader is set by the reverse proxy to the address of the client.
+const RealIPHeader = "X-Real-IP"
+
+// DefaultReportInterval is how often usage is logged and reset.
+const DefaultReportInterval = time.Hour
+
+// reportSize is the number of clients included in each report.
+const reportSize = 10
+
+// Usage is the traffic served to one client.
+type Usage struct {
+	IP       string
+	Requests int64
+	Bytes    int64
+}
+
+// Meter counts requests and response bytes per client IP. The zero
+// value is ready to use.
+type Meter struct {
+	// TrustedProxies are the reverse proxies whose RealIPHeader is
+	// believed. The header of any other client is ignored.
+	TrustedProxies []netip.Prefix
+
+	mu    sync.Mutex
+	usage map[string]*Usage
+}
+
+// ParseTrustedProxies parses proxy addresses given as IPs or CIDR
+// prefixes, such as "172.18.0.0/16".
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Record adds a request of n bytes to the usage of ip.
+func (m *Meter) Record(ip string, n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.usage == nil {
+		m.usage = make(map[string]*Usage)
+	}
+	u, found := m.usage[ip]
+	if !found {
+		u = &Usage{IP: ip}
+		m.usage[ip] = u
+	}
+	u.Requests++
+	u.Bytes += n
+}
+
+// Snapshot returns the usage of every client, highest bytes first.
+func (m *Meter) Snapshot() []Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshot()
+}
+
+// Reset returns the usage of every client, highest bytes first, and
+// clears the meter.
+func (m *Meter) Reset() []Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := m.snapshot()
+	m.usage = nil
+	return snapshot
+}
+
+func (m *Meter) snapshot() []Usage {
+	snapshot := make([]Usage, 0, len(m.usage))
+	for _, u := range m.usage {
+		snapshot = append(snapshot, *u)
+	}
+	slices.SortFunc(snapshot, func(a, b Usage) int {
+		if c := cmp.Compare(b.Bytes, a.Bytes); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.IP, b.IP)
+	})
+	return snapshot
+}
+
+// Middleware records the response size of every request.
+func (m *Meter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cw := &countingWriter{ResponseWriter: w}
+		next.ServeHTTP(cw, r)
+		m.Record(ClientIP(r, m.TrustedProxies), cw.n)
+	})
+}
+
+// Report logs the heaviest clients and resets the meter every interval
+// until ctx is cancelled.
+func (m *Meter) Report(ctx context.Context, logger *slog.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		usage := m.Reset()
+		if len(usage) == 0 {
+			continue
+		}
+		var total int64
+		for _, u := range usage {
+			total += u.Bytes
+		}
+		top := make([]any, 0, reportSize)
+		for _, u := range usage[:min(len(usage), reportSize)] {
+			top = append(top, slog.Group(u.IP, slog.Int64("requests", u.Requests), slog.Int64("bytes", u.Bytes)))
+		}
+		logger.InfoContext(ctx, "file bandwidth report",
+			slog.Duration("interval", interval),
+			slog.Int("clients", len(usage)),
+			slog.Int64("bytes", total),
+			slog.Group("top", top...))
+	}
+}
+
+// ClientIP returns the remote address of the connection or, when that is
+// one of the trusted proxies, the client address the proxy set.
+func ClientIP(r *http.Request, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := r.Header.Get(RealIPHeader); ip != "" && isTrusted(host, trusted) {
+		return ip
+	}
+	return host
+}
+
+func isTrusted(host string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	return slices.ContainsFunc(trusted, func(p netip.Prefix) bool { return p.Contains(addr) })
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func (w *countingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,118 @@
+package bandwidth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestMeterMiddleware(t *testing.T) {
+	meter := Meter{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}}
+	handler := meter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Query().Get("body")))
+	}))
+
+	requests := []struct {
+		remoteAddr string
+		realIP     string
+		body       string
+	}{
+		{remoteAddr: "10.0.0.1:1234", body: "hello"},
+		{remoteAddr: "10.0.0.1:5678", body: "world!"},
+		{remoteAddr: "127.0.0.1:80", realIP: "203.0.113.7", body: "a-much-longer-body"},
+		{remoteAddr: "10.0.0.2:1234", body: ""},
+		{remoteAddr: "10.0.0.2:1234", realIP: "198.51.100.9", body: "spoof"},
+	}
+	for _, req := range requests {
+		r := httptest.NewRequest(http.MethodGet, "/files/a.png?body="+req.body, nil)
+		r.RemoteAddr = req.remoteAddr
+		if req.realIP != "" {
+			r.Header.Set(RealIPHeader, req.realIP)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	want := []Usage{
+		{IP: "203.0.113.7", Requests: 1, Bytes: 18},
+		{IP: "10.0.0.1", Requests: 2, Bytes: 11},
+		{IP: "10.0.0.2", Requests: 2, Bytes: 5},
+	}
+	if got := meter.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Snapshot() = %+v, want %+v", got, want)
+	}
+
+	if got := meter.Reset(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reset() = %+v, want %+v", got, want)
+	}
+	if got := meter.Snapshot(); len(got) != 0 {
+		t.Errorf("Snapshot() after Reset() = %+v, want empty", got)
+	}
+}
+
+func TestMeterConcurrentRecord(t *testing.T) {
+	var meter Meter
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			meter.Record("10.0.0.1", 10)
+		}()
+	}
+	wg.Wait()
+
+	got := meter.Snapshot()
+	if len(got) != 1 || got[0].Requests != 50 || got[0].Bytes != 500 {
+		t.Errorf("Snapshot() = %+v, want 50 requests and 500 bytes", got)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	got, err := ParseTrustedProxies([]string{"172.18.0.0/16", "10.0.0.5", "::ffff:192.0.2.1", "fd00::1/64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []netip.Prefix{
+		netip.MustParsePrefix("172.18.0.0/16"),
+		netip.MustParsePrefix("10.0.0.5/32"),
+		netip.MustParsePrefix("192.0.2.1/32"),
+		netip.MustParsePrefix("fd00::/64"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTrustedProxies() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseTrustedProxies([]string{"nginx"}); err == nil {
+		t.Error("expected error for a host name, got nil")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("172.18.0.0/16")}
+	tests := []struct {
+		name       string
+		remoteAddr string
+		realIP     string
+		want       string
+	}{
+		{name: "direct client", remoteAddr: "203.0.113.7:1234", want: "203.0.113.7"},
+		{name: "trusted proxy", remoteAddr: "172.18.0.3:80", realIP: "203.0.113.7", want: "203.0.113.7"},
+		{name: "spoofed header", remoteAddr: "198.51.100.9:1234", realIP: "203.0.113.7", want: "198.51.100.9"},
+		{name: "ipv4-mapped proxy", remoteAddr: "[::ffff:172.18.0.3]:80", realIP: "203.0.113.7", want: "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/files/a.png", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.realIP != "" {
+				r.Header.Set(RealIPHeader, tt.realIP)
+			}
+			if got := ClientIP(r, trusted); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
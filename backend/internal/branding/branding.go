@@ -0,0 +1,71 @@
+// Package branding holds the instance branding admins configure: the
+// name, logo, accent color, and contact email shown by the frontend and
+// in emails, exports, and share pages.
+package branding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/matt-dz/wecook/internal/database"
+)
+
+// ID is the key of the single branding row.
+const ID = 1
+
+// DefaultName is the name of an instance that has not been renamed.
+const DefaultName = "WeCook"
+
+var ErrInvalidAccentColor = errors.New("invalid accent color")
+
+var accentColorPattern = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+
+// Branding is the branding of the instance.
+type Branding struct {
+	Name string
+	// LogoKey is the file store key of the logo, or empty if there is none.
+	LogoKey      string
+	AccentColor  string
+	ContactEmail string
+}
+
+// Default returns the branding of an instance that has not been
+// configured.
+func Default() Branding {
+	return Branding{Name: DefaultName}
+}
+
+// Load returns the branding of the instance.
+func Load(ctx context.Context, db database.Querier) (Branding, error) {
+	row, err := db.GetInstanceBranding(ctx, ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Branding{}, fmt.Errorf("getting instance branding: %w", err)
+	}
+	return Branding{
+		Name:         row.Name,
+		LogoKey:      row.LogoKey.String,
+		AccentColor:  row.AccentColor.String,
+		ContactEmail: row.ContactEmail.String,
+	}, nil
+}
+
+// NormalizeAccentColor returns color as six lower-case hex digits after
+// a '#', expanding the three-digit form: "#E52" becomes "#ee5522".
+func NormalizeAccentColor(color string) (string, error) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if !accentColorPattern.MatchString(color) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAccentColor, color)
+	}
+	if len(color) == len("#rgb") {
+		color = string([]byte{'#', color[1], color[1], color[2], color[2], color[3], color[3]})
+	}
+	return color, nil
+}
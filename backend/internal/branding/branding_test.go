@@ -0,0 +1,79 @@
+package branding
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/database"
+)
+
+func TestNormalizeAccentColor(t *testing.T) {
+	tests := []struct {
+		color   string
+		want    string
+		wantErr bool
+	}{
+		{color: "#e4572e", want: "#e4572e"},
+		{color: " #E4572E ", want: "#e4572e"},
+		{color: "#E52", want: "#ee5522"},
+		{color: "e4572e", wantErr: true},
+		{color: "#e4572", wantErr: true},
+		{color: "#ggg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.color, func(t *testing.T) {
+			got, err := NormalizeAccentColor(tt.color)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAccentColor) {
+					t.Errorf("NormalizeAccentColor() error = %v, want ErrInvalidAccentColor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeAccentColor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().
+		GetInstanceBranding(gomock.Any(), int32(ID)).
+		Return(database.InstanceBranding{
+			ID:           ID,
+			Name:         "Grandma's Kitchen",
+			AccentColor:  pgtype.Text{String: "#e4572e", Valid: true},
+			ContactEmail: pgtype.Text{String: "admin@example.com", Valid: true},
+		}, nil)
+	mockDB.EXPECT().
+		GetInstanceBranding(gomock.Any(), int32(ID)).
+		Return(database.InstanceBranding{}, pgx.ErrNoRows)
+
+	got, err := Load(context.Background(), mockDB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Branding{Name: "Grandma's Kitchen", AccentColor: "#e4572e", ContactEmail: "admin@example.com"}
+	if got != want {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+
+	got, err = Load(context.Background(), mockDB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != Default() {
+		t.Errorf("Load() = %+v, want the default branding", got)
+	}
+}
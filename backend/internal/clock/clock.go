@@ -0,0 +1,24 @@
+// Package clock provides an abstraction over the current time.
+package clock
+
+import "time"
+
+// Clock reports the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// System is a Clock backed by the system time.
+type System struct{}
+
+var _ Clock = System{}
+
+// New returns a Clock backed by the system time.
+func New() System {
+	return System{}
+}
+
+// Now returns the current UTC time.
+func (System) Now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,61 @@
+// Package completeness scores how complete a recipe is, so owners can be
+// nudged to fill in what is missing before publishing.
+package completeness
+
+// Item is a part of a recipe that the score checks for.
+type Item string
+
+const (
+	CoverImage       Item = "cover_image"
+	Times            Item = "times"
+	Servings         Item = "servings"
+	Tags             Item = "tags"
+	StepInstructions Item = "step_instructions"
+)
+
+// Items are the checked items, in the order missing items are reported.
+// Each is worth the same share of the score.
+var Items = []Item{CoverImage, Times, Servings, Tags, StepInstructions}
+
+// MaxScore is the score of a recipe with every item present.
+const MaxScore = 100
+
+// Recipe is what the score is computed from.
+type Recipe struct {
+	HasCoverImage bool
+	HasCookTime   bool
+	HasPrepTime   bool
+	HasServings   bool
+	Tags          int
+	Steps         int
+	// BlankSteps is the number of steps without an instruction.
+	BlankSteps int
+}
+
+// Result is a completeness score and the items that lower it.
+type Result struct {
+	Score   int
+	Missing []Item
+}
+
+// Score returns the completeness of r. Times requires both the prep and
+// the cook time, and step instructions requires at least one step and no
+// blank steps.
+func Score(r Recipe) Result {
+	present := map[Item]bool{
+		CoverImage:       r.HasCoverImage,
+		Times:            r.HasCookTime && r.HasPrepTime,
+		Servings:         r.HasServings,
+		Tags:             r.Tags > 0,
+		StepInstructions: r.Steps > 0 && r.BlankSteps == 0,
+	}
+
+	result := Result{Missing: []Item{}}
+	for _, item := range Items {
+		if !present[item] {
+			result.Missing = append(result.Missing, item)
+		}
+	}
+	result.Score = MaxScore * (len(Items) - len(result.Missing)) / len(Items)
+	return result
+}
@@ -0,0 +1,61 @@
+package completeness
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	complete := Recipe{
+		HasCoverImage: true,
+		HasCookTime:   true,
+		HasPrepTime:   true,
+		HasServings:   true,
+		Tags:          2,
+		Steps:         3,
+	}
+
+	tests := []struct {
+		name   string
+		recipe Recipe
+		want   Result
+	}{
+		{
+			name:   "complete",
+			recipe: complete,
+			want:   Result{Score: MaxScore, Missing: []Item{}},
+		},
+		{
+			name:   "empty",
+			recipe: Recipe{},
+			want:   Result{Score: 0, Missing: Items},
+		},
+		{
+			name: "prep time only",
+			recipe: func() Recipe {
+				r := complete
+				r.HasCookTime = false
+				return r
+			}(),
+			want: Result{Score: 80, Missing: []Item{Times}},
+		},
+		{
+			name: "blank step and no tags",
+			recipe: func() Recipe {
+				r := complete
+				r.Tags = 0
+				r.BlankSteps = 1
+				return r
+			}(),
+			want: Result{Score: 60, Missing: []Item{Tags, StepInstructions}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.recipe); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Score() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -200,6 +200,9 @@ type AppSecret struct {
 	Value   *AppSecretValue `yaml:"value" validate:"omitempty,validateFn"`
 	Path    string          `yaml:"path" validate:"omitempty,filepath"`
 	Version string          `yaml:"version"`
+	// Previous maps the versions of rotated secrets to their values, so
+	// files encrypted under them can still be read.
+	Previous map[string]string `yaml:"previous"`
 }
 
 type Database struct {
@@ -208,13 +211,36 @@ type Database struct {
 	Database string `yaml:"database"`
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
+	// RowLevelSecurity enforces the row-level security policies on recipe
+	// tables, so statements of a request only see the rows its user may.
+	RowLevelSecurity bool `yaml:"row_level_security"`
 
 	Validate struct{} `yaml:"-" validate:"allOrNothing=Port Host Database User Password"`
 }
 
 type Fileserver struct {
-	Volume    string `yaml:"volume"`
-	URLPrefix string `yaml:"url_prefix"`
+	Volume       string `yaml:"volume"`
+	URLPrefix    string `yaml:"url_prefix"`
+	PathTemplate string `yaml:"path_template"`
+
+	// Serve makes the backend serve files under URLPrefix itself, so that
+	// referer checks and bandwidth accounting apply to them.
+	Serve bool `yaml:"serve"`
+	// AllowedReferers lists the hosts, besides that of HostOrigin, that may
+	// embed served files. Entries may be bare hosts, origins, or wildcards
+	// such as "*.example.com". The referer check is disabled while the list
+	// is empty and BlockEmptyReferer is false.
+	AllowedReferers []string `yaml:"allowed_referers"`
+	// BlockEmptyReferer rejects requests that carry neither a Referer nor
+	// an Origin header.
+	BlockEmptyReferer bool `yaml:"block_empty_referer"`
+	// TrustedProxies lists the reverse proxies, as IPs or CIDR prefixes,
+	// whose X-Real-IP header names the client of a served file. The
+	// header of any other client is ignored.
+	TrustedProxies []string `yaml:"trusted_proxies" validate:"dive,cidr|ip"`
+	// Encrypt encrypts new files with a key derived from the app secret.
+	// Encrypted files are decrypted when served, so Serve is required.
+	Encrypt bool `yaml:"encrypt" validate:"excluded_unless=Serve true"`
 }
 
 type SMTP struct {
@@ -238,12 +264,75 @@ type Admin struct {
 	Validate struct{} `yaml:"-" validate:"allOrNothing=FirstName LastName Email Password"`
 }
 
+// Default page sizes of list endpoints.
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Pagination bounds the page sizes of list endpoints.
+type Pagination struct {
+	// DefaultLimit is the page size of requests that do not ask for one.
+	DefaultLimit int32 `yaml:"default_limit" validate:"min=1,ltefield=MaxLimit"`
+	// MaxLimit caps the page size a request may ask for.
+	MaxLimit int32 `yaml:"max_limit" validate:"min=1,max=10000"`
+}
+
+// Limit returns the page size of a request that asked for requested
+// items, or for none if requested is nil. Unset limits fall back to
+// DefaultPageLimit and MaxPageLimit.
+func (p Pagination) Limit(requested *int32) int32 {
+	def, maxLimit := p.DefaultLimit, p.MaxLimit
+	if maxLimit <= 0 {
+		maxLimit = MaxPageLimit
+	}
+	if def <= 0 {
+		def = min(DefaultPageLimit, maxLimit)
+	}
+	if requested == nil {
+		return def
+	}
+	return min(max(*requested, 1), maxLimit)
+}
+
+// Federation configures the experimental ActivityPub federation with
+// other wecook instances.
+type Federation struct {
+	// Enabled publishes public recipes as ActivityPub objects and lets
+	// users follow users of other instances. Actors and object IDs are
+	// built from HostOrigin, which must not change once enabled.
+	Enabled bool `yaml:"enabled"`
+}
+
+// Appliances configures the kitchen appliances users can register.
+type Appliances struct {
+	// AllowPrivateAddresses lets users register appliances on the local
+	// network, such as an oven bridge at 192.168.1.20. Loopback and
+	// link-local addresses are refused either way.
+	AllowPrivateAddresses bool `yaml:"allow_private_addresses"`
+}
+
+// Demo configures the read-only API sandbox, which serves example data
+// from its own database schema to requests presenting the sandbox key.
+type Demo struct {
+	// Enabled seeds the sandbox schema at startup and accepts the
+	// sandbox key.
+	Enabled bool `yaml:"enabled"`
+	// APIKey is the sandbox key. The key is published, so it grants
+	// nothing outside the sandbox. Derived from the app secret if unset.
+	APIKey string `yaml:"api_key"`
+}
+
 type Config struct {
 	AppSecret  AppSecret  `yaml:"app_secret"`
 	SMTP       SMTP       `yaml:"smtp"`
 	Admin      Admin      `yaml:"admin"`
 	Fileserver Fileserver `yaml:"fileserver"`
 	Database   Database   `yaml:"database"`
+	Pagination Pagination `yaml:"pagination"`
+	Federation Federation `yaml:"federation"`
+	Appliances Appliances `yaml:"appliances"`
+	Demo       Demo       `yaml:"demo"`
 	HostOrigin string     `yaml:"host_origin" validate:"url"`
 	Env        string     `yaml:"env" validate:"omitempty,oneof=DEV PROD"`
 }
@@ -311,6 +400,7 @@ func loadConfigFromEnv() (Config, error) {
 	appSecretValue := AppSecretValue(loadWithDefault("APP_SECRET", ""))
 	appSecretPath := loadWithDefault("APP_SECRET_PATH", "/data/secret")
 	appSecretVersion := loadWithDefault("APP_SECRET_VERSION", "1")
+	appSecretPrevious := loadWithDefault("APP_SECRET_PREVIOUS", "")
 
 	// Database
 	databasePort := loadWithDefault("DATABASE_PORT", "5432")
@@ -318,10 +408,17 @@ func loadConfigFromEnv() (Config, error) {
 	databaseDatabase := loadWithDefault("DATABASE", "")
 	databaseUser := loadWithDefault("DATABASE_USER", "")
 	databasePassword := loadWithDefault("DATABASE_PASSWORD", "")
+	databaseRowLevelSecurity := loadWithDefault("DATABASE_ROW_LEVEL_SECURITY", "false")
 
 	// Fileserver
 	fileserverVolume := loadWithDefault("FILESERVER_VOLUME", "/data/files")
 	fileserverURLPrefix := loadWithDefault("FILESERVER_URL_PREFIX", "/files")
+	fileserverPathTemplate := loadWithDefault("FILESERVER_PATH_TEMPLATE", "{kind}/{id}{ext}")
+	fileserverServe := loadWithDefault("FILESERVER_SERVE", "false")
+	fileserverAllowedReferers := loadWithDefault("FILESERVER_ALLOWED_REFERERS", "")
+	fileserverTrustedProxies := loadWithDefault("FILESERVER_TRUSTED_PROXIES", "")
+	fileserverBlockEmptyReferer := loadWithDefault("FILESERVER_BLOCK_EMPTY_REFERER", "false")
+	fileserverEncrypt := loadWithDefault("FILESERVER_ENCRYPT", "false")
 
 	// SMTP
 	smtpTLSMode := TLSMode(loadWithDefault("SMTP_TLS_MODE", string(TLSModeAuto)))
@@ -337,6 +434,20 @@ func loadConfigFromEnv() (Config, error) {
 		smtpPort = "587"
 	}
 
+	// Pagination
+	paginationDefaultLimit := loadWithDefault("PAGINATION_DEFAULT_LIMIT", "")
+	paginationMaxLimit := loadWithDefault("PAGINATION_MAX_LIMIT", strconv.Itoa(MaxPageLimit))
+
+	// Federation
+	federationEnabled := loadWithDefault("FEDERATION_ENABLED", "false")
+
+	// Appliances
+	appliancesAllowPrivate := loadWithDefault("APPLIANCES_ALLOW_PRIVATE_ADDRESSES", "false")
+
+	// Demo
+	demoEnabled := loadWithDefault("DEMO_ENABLED", "false")
+	demoAPIKey := loadWithDefault("DEMO_API_KEY", "")
+
 	// Admin
 	adminFirstName := loadWithDefault("ADMIN_FIRST_NAME", "")
 	adminLastName := loadWithDefault("ADMIN_LAST_NAME", "")
@@ -358,6 +469,20 @@ func loadConfigFromEnv() (Config, error) {
 	} else {
 		conf.AppSecret.Value = &appSecretValue
 	}
+	// APP_SECRET_PREVIOUS is a comma-separated list of version=secret pairs
+	for pair := range strings.SplitSeq(appSecretPrevious, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		version, secret, ok := strings.Cut(pair, "=")
+		if !ok || version == "" || secret == "" {
+			return conf, fmt.Errorf("invalid APP_SECRET_PREVIOUS entry %q: expected version=secret", pair)
+		}
+		if conf.AppSecret.Previous == nil {
+			conf.AppSecret.Previous = make(map[string]string)
+		}
+		conf.AppSecret.Previous[version] = secret
+	}
 
 	// Load Database
 	conf.Database = Database{
@@ -371,11 +496,42 @@ func loadConfigFromEnv() (Config, error) {
 	} else {
 		conf.Database.Port = uint16(port)
 	}
+	if b, err := strconv.ParseBool(databaseRowLevelSecurity); err != nil {
+		return conf, fmt.Errorf("invalid DATABASE_ROW_LEVEL_SECURITY (%q): %w", databaseRowLevelSecurity, err)
+	} else {
+		conf.Database.RowLevelSecurity = b
+	}
 
 	// Load fileserver
 	conf.Fileserver = Fileserver{
-		Volume:    fileserverVolume,
-		URLPrefix: fileserverURLPrefix,
+		Volume:       fileserverVolume,
+		URLPrefix:    fileserverURLPrefix,
+		PathTemplate: fileserverPathTemplate,
+	}
+	if b, err := strconv.ParseBool(fileserverServe); err != nil {
+		return conf, fmt.Errorf("invalid FILESERVER_SERVE (%q): %w", fileserverServe, err)
+	} else {
+		conf.Fileserver.Serve = b
+	}
+	if b, err := strconv.ParseBool(fileserverBlockEmptyReferer); err != nil {
+		return conf, fmt.Errorf("invalid FILESERVER_BLOCK_EMPTY_REFERER (%q): %w", fileserverBlockEmptyReferer, err)
+	} else {
+		conf.Fileserver.BlockEmptyReferer = b
+	}
+	if b, err := strconv.ParseBool(fileserverEncrypt); err != nil {
+		return conf, fmt.Errorf("invalid FILESERVER_ENCRYPT (%q): %w", fileserverEncrypt, err)
+	} else {
+		conf.Fileserver.Encrypt = b
+	}
+	for referer := range strings.SplitSeq(fileserverAllowedReferers, ",") {
+		if referer = strings.TrimSpace(referer); referer != "" {
+			conf.Fileserver.AllowedReferers = append(conf.Fileserver.AllowedReferers, referer)
+		}
+	}
+	for proxy := range strings.SplitSeq(fileserverTrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			conf.Fileserver.TrustedProxies = append(conf.Fileserver.TrustedProxies, proxy)
+		}
 	}
 
 	// Load SMTP
@@ -399,6 +555,44 @@ func loadConfigFromEnv() (Config, error) {
 		}
 	}
 
+	// Load Pagination
+	if n, err := strconv.ParseInt(paginationMaxLimit, 10, 32); err != nil {
+		return conf, fmt.Errorf("invalid PAGINATION_MAX_LIMIT (%q): %w", paginationMaxLimit, err)
+	} else {
+		conf.Pagination.MaxLimit = int32(n)
+	}
+	// Only default PAGINATION_DEFAULT_LIMIT up to the maximum
+	conf.Pagination.DefaultLimit = min(DefaultPageLimit, conf.Pagination.MaxLimit)
+	if paginationDefaultLimit != "" {
+		if n, err := strconv.ParseInt(paginationDefaultLimit, 10, 32); err != nil {
+			return conf, fmt.Errorf("invalid PAGINATION_DEFAULT_LIMIT (%q): %w", paginationDefaultLimit, err)
+		} else {
+			conf.Pagination.DefaultLimit = int32(n)
+		}
+	}
+
+	// Load Federation
+	if b, err := strconv.ParseBool(federationEnabled); err != nil {
+		return conf, fmt.Errorf("invalid FEDERATION_ENABLED (%q): %w", federationEnabled, err)
+	} else {
+		conf.Federation.Enabled = b
+	}
+
+	// Load Appliances
+	if b, err := strconv.ParseBool(appliancesAllowPrivate); err != nil {
+		return conf, fmt.Errorf("invalid APPLIANCES_ALLOW_PRIVATE_ADDRESSES (%q): %w", appliancesAllowPrivate, err)
+	} else {
+		conf.Appliances.AllowPrivateAddresses = b
+	}
+
+	// Load Demo
+	if b, err := strconv.ParseBool(demoEnabled); err != nil {
+		return conf, fmt.Errorf("invalid DEMO_ENABLED (%q): %w", demoEnabled, err)
+	} else {
+		conf.Demo.Enabled = b
+	}
+	conf.Demo.APIKey = demoAPIKey
+
 	// Load Admin
 	conf.Admin = Admin{
 		FirstName: adminFirstName,
@@ -458,6 +652,9 @@ func loadConfigFromFile(path string) (Config, error) {
 	if config.Fileserver.URLPrefix == "" {
 		config.Fileserver.URLPrefix = "/files"
 	}
+	if config.Fileserver.PathTemplate == "" {
+		config.Fileserver.PathTemplate = "{kind}/{id}{ext}"
+	}
 	// Only set SMTP.Port default if SMTP is being configured
 	if config.SMTP.Port == 0 && (config.SMTP.From != "" || config.SMTP.Password != "" ||
 		config.SMTP.Host != "" || config.SMTP.Username != "") {
@@ -466,6 +663,12 @@ func loadConfigFromFile(path string) (Config, error) {
 	if config.SMTP.TLSMode == "" {
 		config.SMTP.TLSMode = TLSModeAuto
 	}
+	if config.Pagination.MaxLimit == 0 {
+		config.Pagination.MaxLimit = MaxPageLimit
+	}
+	if config.Pagination.DefaultLimit == 0 {
+		config.Pagination.DefaultLimit = min(DefaultPageLimit, config.Pagination.MaxLimit)
+	}
 
 	// Validate config
 	validate := validator.New(validator.WithRequiredStructEnabled())
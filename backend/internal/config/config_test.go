@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/go-playground/validator/v10"
@@ -59,6 +60,21 @@ func TestLoadConfigFromEnv(t *testing.T) {
 				if c.Fileserver.URLPrefix != "/files" {
 					t.Errorf("expected Fileserver.URLPrefix %q, got %q", "/files", c.Fileserver.URLPrefix)
 				}
+				if c.Fileserver.PathTemplate != "{kind}/{id}{ext}" {
+					t.Errorf("expected Fileserver.PathTemplate %q, got %q", "{kind}/{id}{ext}", c.Fileserver.PathTemplate)
+				}
+				if c.Fileserver.Serve {
+					t.Error("expected Fileserver.Serve false, got true")
+				}
+				if len(c.Fileserver.AllowedReferers) != 0 {
+					t.Errorf("expected no Fileserver.AllowedReferers, got %v", c.Fileserver.AllowedReferers)
+				}
+				if c.Fileserver.BlockEmptyReferer {
+					t.Error("expected Fileserver.BlockEmptyReferer false, got true")
+				}
+				if want := (Pagination{DefaultLimit: DefaultPageLimit, MaxLimit: MaxPageLimit}); c.Pagination != want {
+					t.Errorf("expected Pagination %+v, got %+v", want, c.Pagination)
+				}
 				// SMTP is not configured, so Port should be 0 (no default when SMTP fields are empty)
 				if c.SMTP.Port != 0 {
 					t.Errorf("expected SMTP.Port 0, got %d", c.SMTP.Port)
@@ -90,6 +106,11 @@ func TestLoadConfigFromEnv(t *testing.T) {
 				t.Setenv("DATABASE_PORT", "5433")
 				t.Setenv("FILESERVER_VOLUME", "/custom/files")
 				t.Setenv("FILESERVER_URL_PREFIX", "/uploads")
+				t.Setenv("FILESERVER_PATH_TEMPLATE", "{kind}/{shard}/{id}{ext}")
+				t.Setenv("FILESERVER_SERVE", "true")
+				t.Setenv("FILESERVER_ALLOWED_REFERERS", "friend.example.org, *.cdn.example.net")
+				t.Setenv("FILESERVER_BLOCK_EMPTY_REFERER", "true")
+				t.Setenv("FILESERVER_TRUSTED_PROXIES", "172.18.0.0/16, 10.0.0.5")
 				t.Setenv("SMTP_HOST", "smtp.example.com")
 				t.Setenv("SMTP_PORT", "465")
 				t.Setenv("SMTP_USERNAME", "user@example.com")
@@ -133,6 +154,21 @@ func TestLoadConfigFromEnv(t *testing.T) {
 				if c.Fileserver.URLPrefix != "/uploads" {
 					t.Errorf("expected Fileserver.URLPrefix %q, got %q", "/uploads", c.Fileserver.URLPrefix)
 				}
+				if c.Fileserver.PathTemplate != "{kind}/{shard}/{id}{ext}" {
+					t.Errorf("expected Fileserver.PathTemplate %q, got %q", "{kind}/{shard}/{id}{ext}", c.Fileserver.PathTemplate)
+				}
+				if !c.Fileserver.Serve {
+					t.Error("expected Fileserver.Serve true, got false")
+				}
+				if want := []string{"friend.example.org", "*.cdn.example.net"}; !slices.Equal(c.Fileserver.AllowedReferers, want) {
+					t.Errorf("expected Fileserver.AllowedReferers %v, got %v", want, c.Fileserver.AllowedReferers)
+				}
+				if !c.Fileserver.BlockEmptyReferer {
+					t.Error("expected Fileserver.BlockEmptyReferer true, got false")
+				}
+				if want := []string{"172.18.0.0/16", "10.0.0.5"}; !slices.Equal(c.Fileserver.TrustedProxies, want) {
+					t.Errorf("expected Fileserver.TrustedProxies %v, got %v", want, c.Fileserver.TrustedProxies)
+				}
 				if c.SMTP.Port != 465 {
 					t.Errorf("expected SMTP.Port 465, got %d", c.SMTP.Port)
 				}
@@ -173,6 +209,167 @@ func TestLoadConfigFromEnv(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "invalid fileserver serve",
+			setup: func(t *testing.T) {
+				t.Setenv("FILESERVER_SERVE", "invalid")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: true,
+		},
+		{
+			name: "file encryption with previous app secrets",
+			setup: func(t *testing.T) {
+				t.Setenv("FILESERVER_SERVE", "true")
+				t.Setenv("FILESERVER_ENCRYPT", "true")
+				t.Setenv("APP_SECRET_VERSION", "3")
+				t.Setenv("APP_SECRET_PREVIOUS", "1=first-secret, 2=second=secret")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: false,
+			validate: func(t *testing.T, c *Config) {
+				if !c.Fileserver.Encrypt {
+					t.Error("expected Fileserver.Encrypt true, got false")
+				}
+				want := map[string]string{"1": "first-secret", "2": "second=secret"}
+				if len(c.AppSecret.Previous) != len(want) {
+					t.Fatalf("expected AppSecret.Previous %v, got %v", want, c.AppSecret.Previous)
+				}
+				for version, secret := range want {
+					if c.AppSecret.Previous[version] != secret {
+						t.Errorf("expected AppSecret.Previous[%q] %q, got %q", version, secret, c.AppSecret.Previous[version])
+					}
+				}
+			},
+		},
+		{
+			name: "file encryption requires serving files",
+			setup: func(t *testing.T) {
+				t.Setenv("FILESERVER_ENCRYPT", "true")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid previous app secrets",
+			setup: func(t *testing.T) {
+				t.Setenv("APP_SECRET_PREVIOUS", "first-secret")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: true,
+		},
+		{
+			name: "row-level security",
+			setup: func(t *testing.T) {
+				t.Setenv("DATABASE_ROW_LEVEL_SECURITY", "true")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: false,
+			validate: func(t *testing.T, c *Config) {
+				if !c.Database.RowLevelSecurity {
+					t.Error("expected Database.RowLevelSecurity true, got false")
+				}
+			},
+		},
+		{
+			name: "invalid row-level security",
+			setup: func(t *testing.T) {
+				t.Setenv("DATABASE_ROW_LEVEL_SECURITY", "sometimes")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: true,
+		},
+		{
+			name: "federation",
+			setup: func(t *testing.T) {
+				t.Setenv("FEDERATION_ENABLED", "true")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: false,
+			validate: func(t *testing.T, c *Config) {
+				if !c.Federation.Enabled {
+					t.Error("expected Federation.Enabled true, got false")
+				}
+			},
+		},
+		{
+			name: "appliances on the local network",
+			setup: func(t *testing.T) {
+				t.Setenv("APPLIANCES_ALLOW_PRIVATE_ADDRESSES", "true")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: false,
+			validate: func(t *testing.T, c *Config) {
+				if !c.Appliances.AllowPrivateAddresses {
+					t.Error("expected Appliances.AllowPrivateAddresses true, got false")
+				}
+			},
+		},
+		{
+			name: "invalid trusted proxy",
+			setup: func(t *testing.T) {
+				t.Setenv("FILESERVER_TRUSTED_PROXIES", "nginx")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid federation",
+			setup: func(t *testing.T) {
+				t.Setenv("FEDERATION_ENABLED", "sometimes")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: true,
+		},
+		{
+			name: "demo",
+			setup: func(t *testing.T) {
+				t.Setenv("DEMO_ENABLED", "true")
+				t.Setenv("DEMO_API_KEY", "demo_try-me")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: false,
+			validate: func(t *testing.T, c *Config) {
+				if !c.Demo.Enabled {
+					t.Error("expected Demo.Enabled true, got false")
+				}
+				if c.Demo.APIKey != "demo_try-me" {
+					t.Errorf("expected Demo.APIKey demo_try-me, got %q", c.Demo.APIKey)
+				}
+			},
+		},
+		{
+			name: "invalid demo",
+			setup: func(t *testing.T) {
+				t.Setenv("DEMO_ENABLED", "sometimes")
+				t.Setenv("DATABASE_USER", "testuser")
+				t.Setenv("DATABASE_PASSWORD", "testpass")
+				t.Setenv("DATABASE", "testdb")
+			},
+			wantError: true,
+		},
 		{
 			name: "invalid TLS skip verify",
 			setup: func(t *testing.T) {
@@ -339,6 +536,9 @@ database:
 fileserver:
   volume: /data/production/files
   url_prefix: /uploads
+  serve: true
+  allowed_referers:
+    - friend.example.org
 smtp:
   host: smtp.example.com
   port: 465
@@ -370,6 +570,12 @@ admin:
 				if c.SMTP.TLSMode != TLSModeImplicit {
 					t.Errorf("expected SMTP.TLSMode %q, got %q", TLSModeImplicit, c.SMTP.TLSMode)
 				}
+				if !c.Fileserver.Serve {
+					t.Error("expected Fileserver.Serve true, got false")
+				}
+				if want := []string{"friend.example.org"}; !slices.Equal(c.Fileserver.AllowedReferers, want) {
+					t.Errorf("expected Fileserver.AllowedReferers %v, got %v", want, c.Fileserver.AllowedReferers)
+				}
 			},
 		},
 		{
@@ -411,6 +617,9 @@ database:
 				if c.Fileserver.URLPrefix != "/files" {
 					t.Errorf("expected default Fileserver.URLPrefix %q, got %q", "/files", c.Fileserver.URLPrefix)
 				}
+				if c.Fileserver.PathTemplate != "{kind}/{id}{ext}" {
+					t.Errorf("expected default Fileserver.PathTemplate %q, got %q", "{kind}/{id}{ext}", c.Fileserver.PathTemplate)
+				}
 				// SMTP is not configured, so Port should be 0 (no default when SMTP fields are empty)
 				if c.SMTP.Port != 0 {
 					t.Errorf("expected default SMTP.Port 0, got %d", c.SMTP.Port)
@@ -418,8 +627,46 @@ database:
 				if c.SMTP.TLSMode != TLSModeAuto {
 					t.Errorf("expected default SMTP.TLSMode %q, got %q", TLSModeAuto, c.SMTP.TLSMode)
 				}
+				if want := (Pagination{DefaultLimit: DefaultPageLimit, MaxLimit: MaxPageLimit}); c.Pagination != want {
+					t.Errorf("expected default Pagination %+v, got %+v", want, c.Pagination)
+				}
 			},
 		},
+		{
+			name: "pagination maximum below the default",
+			yaml: func(t *testing.T) string {
+				return fmt.Sprintf(`
+app_secret:
+  path: %s
+database:
+  database: testdb
+  user: testuser
+  password: testpass
+pagination:
+  max_limit: 10
+`, filepath.Join(t.TempDir(), "secret"))
+			},
+			validate: func(t *testing.T, c *Config) {
+				if want := (Pagination{DefaultLimit: 10, MaxLimit: 10}); c.Pagination != want {
+					t.Errorf("expected Pagination %+v, got %+v", want, c.Pagination)
+				}
+			},
+		},
+		{
+			name: "pagination default above the maximum",
+			yaml: `
+app_secret:
+  value: this-is-a-very-long-secret-key-with-more-than-32-bytes
+database:
+  database: testdb
+  user: testuser
+  password: testpass
+pagination:
+  default_limit: 50
+  max_limit: 10
+`,
+			wantError: true,
+		},
 		{
 			name:      "invalid YAML",
 			yaml:      `{invalid yaml content`,
@@ -949,3 +1196,29 @@ func TestLoadAppSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestPagination_Limit(t *testing.T) {
+	limit := func(n int32) *int32 { return &n }
+
+	tests := []struct {
+		name       string
+		pagination Pagination
+		requested  *int32
+		want       int32
+	}{
+		{"default", Pagination{DefaultLimit: 25, MaxLimit: 50}, nil, 25},
+		{"requested", Pagination{DefaultLimit: 25, MaxLimit: 50}, limit(40), 40},
+		{"capped", Pagination{DefaultLimit: 25, MaxLimit: 50}, limit(500), 50},
+		{"at least one", Pagination{DefaultLimit: 25, MaxLimit: 50}, limit(0), 1},
+		{"unset", Pagination{}, nil, DefaultPageLimit},
+		{"unset cap", Pagination{}, limit(500), MaxPageLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pagination.Limit(tt.requested); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
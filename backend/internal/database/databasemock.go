@@ -41,6 +41,49 @@ func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
 	return m.recorder
 }
 
+// AcceptFederationFollowing mocks base method.
+func (m *MockQuerier) AcceptFederationFollowing(ctx context.Context, arg AcceptFederationFollowingParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptFederationFollowing", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptFederationFollowing indicates an expected call of AcceptFederationFollowing.
+func (mr *MockQuerierMockRecorder) AcceptFederationFollowing(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptFederationFollowing", reflect.TypeOf((*MockQuerier)(nil).AcceptFederationFollowing), ctx, arg)
+}
+
+// AddFederationFollower mocks base method.
+func (m *MockQuerier) AddFederationFollower(ctx context.Context, arg AddFederationFollowerParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFederationFollower", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFederationFollower indicates an expected call of AddFederationFollower.
+func (mr *MockQuerierMockRecorder) AddFederationFollower(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFederationFollower", reflect.TypeOf((*MockQuerier)(nil).AddFederationFollower), ctx, arg)
+}
+
+// AddRecipeTag mocks base method.
+func (m *MockQuerier) AddRecipeTag(ctx context.Context, arg AddRecipeTagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRecipeTag", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRecipeTag indicates an expected call of AddRecipeTag.
+func (mr *MockQuerierMockRecorder) AddRecipeTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecipeTag", reflect.TypeOf((*MockQuerier)(nil).AddRecipeTag), ctx, arg)
+}
+
 // BatchUpdateRecipeIngredientImages mocks base method.
 func (m *MockQuerier) BatchUpdateRecipeIngredientImages(ctx context.Context, arg []BatchUpdateRecipeIngredientImagesParams) *BatchUpdateRecipeIngredientImagesBatchResults {
 	m.ctrl.T.Helper()
@@ -187,6 +230,66 @@ func (mr *MockQuerierMockRecorder) CheckUsersTableExists(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUsersTableExists", reflect.TypeOf((*MockQuerier)(nil).CheckUsersTableExists), ctx)
 }
 
+// ClaimDelivery mocks base method.
+func (m *MockQuerier) ClaimDelivery(ctx context.Context, arg ClaimDeliveryParams) (Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDelivery", ctx, arg)
+	ret0, _ := ret[0].(Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDelivery indicates an expected call of ClaimDelivery.
+func (mr *MockQuerierMockRecorder) ClaimDelivery(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDelivery", reflect.TypeOf((*MockQuerier)(nil).ClaimDelivery), ctx, arg)
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockQuerier) ClaimDueDeliveries(ctx context.Context, arg ClaimDueDeliveriesParams) ([]Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockQuerierMockRecorder) ClaimDueDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockQuerier)(nil).ClaimDueDeliveries), ctx, arg)
+}
+
+// ConsumeUndoToken mocks base method.
+func (m *MockQuerier) ConsumeUndoToken(ctx context.Context, arg ConsumeUndoTokenParams) (UndoToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeUndoToken", ctx, arg)
+	ret0, _ := ret[0].(UndoToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeUndoToken indicates an expected call of ConsumeUndoToken.
+func (mr *MockQuerierMockRecorder) ConsumeUndoToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeUndoToken", reflect.TypeOf((*MockQuerier)(nil).ConsumeUndoToken), ctx, arg)
+}
+
+// ConsumeUploadToken mocks base method.
+func (m *MockQuerier) ConsumeUploadToken(ctx context.Context, arg ConsumeUploadTokenParams) (ConsumeUploadTokenRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeUploadToken", ctx, arg)
+	ret0, _ := ret[0].(ConsumeUploadTokenRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeUploadToken indicates an expected call of ConsumeUploadToken.
+func (mr *MockQuerierMockRecorder) ConsumeUploadToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeUploadToken", reflect.TypeOf((*MockQuerier)(nil).ConsumeUploadToken), ctx, arg)
+}
+
 // CreateAdmin mocks base method.
 func (m *MockQuerier) CreateAdmin(ctx context.Context, arg CreateAdminParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -202,6 +305,51 @@ func (mr *MockQuerierMockRecorder) CreateAdmin(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdmin", reflect.TypeOf((*MockQuerier)(nil).CreateAdmin), ctx, arg)
 }
 
+// CreateAppliance mocks base method.
+func (m *MockQuerier) CreateAppliance(ctx context.Context, arg CreateApplianceParams) (Appliance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppliance", ctx, arg)
+	ret0, _ := ret[0].(Appliance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAppliance indicates an expected call of CreateAppliance.
+func (mr *MockQuerierMockRecorder) CreateAppliance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppliance", reflect.TypeOf((*MockQuerier)(nil).CreateAppliance), ctx, arg)
+}
+
+// CreateAuditEvent mocks base method.
+func (m *MockQuerier) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockQuerierMockRecorder) CreateAuditEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockQuerier)(nil).CreateAuditEvent), ctx, arg)
+}
+
+// CreateDelivery mocks base method.
+func (m *MockQuerier) CreateDelivery(ctx context.Context, arg CreateDeliveryParams) (Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelivery", ctx, arg)
+	ret0, _ := ret[0].(Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery.
+func (mr *MockQuerierMockRecorder) CreateDelivery(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*MockQuerier)(nil).CreateDelivery), ctx, arg)
+}
+
 // CreateEmptyRecipeIngredient mocks base method.
 func (m *MockQuerier) CreateEmptyRecipeIngredient(ctx context.Context, recipeID int64) (RecipeIngredient, error) {
 	m.ctrl.T.Helper()
@@ -217,6 +365,36 @@ func (mr *MockQuerierMockRecorder) CreateEmptyRecipeIngredient(ctx, recipeID any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmptyRecipeIngredient", reflect.TypeOf((*MockQuerier)(nil).CreateEmptyRecipeIngredient), ctx, recipeID)
 }
 
+// CreateFederationFollowing mocks base method.
+func (m *MockQuerier) CreateFederationFollowing(ctx context.Context, arg CreateFederationFollowingParams) (FederationFollowing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFederationFollowing", ctx, arg)
+	ret0, _ := ret[0].(FederationFollowing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFederationFollowing indicates an expected call of CreateFederationFollowing.
+func (mr *MockQuerierMockRecorder) CreateFederationFollowing(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFederationFollowing", reflect.TypeOf((*MockQuerier)(nil).CreateFederationFollowing), ctx, arg)
+}
+
+// CreateFederationKey mocks base method.
+func (m *MockQuerier) CreateFederationKey(ctx context.Context, arg CreateFederationKeyParams) (FederationKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFederationKey", ctx, arg)
+	ret0, _ := ret[0].(FederationKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFederationKey indicates an expected call of CreateFederationKey.
+func (mr *MockQuerierMockRecorder) CreateFederationKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFederationKey", reflect.TypeOf((*MockQuerier)(nil).CreateFederationKey), ctx, arg)
+}
+
 // CreateInviteCode mocks base method.
 func (m *MockQuerier) CreateInviteCode(ctx context.Context, arg CreateInviteCodeParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -291,6 +469,48 @@ func (mr *MockQuerierMockRecorder) CreateRecipeStep(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecipeStep", reflect.TypeOf((*MockQuerier)(nil).CreateRecipeStep), ctx, arg)
 }
 
+// CreateRecipeTagSuggestion mocks base method.
+func (m *MockQuerier) CreateRecipeTagSuggestion(ctx context.Context, arg CreateRecipeTagSuggestionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecipeTagSuggestion", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecipeTagSuggestion indicates an expected call of CreateRecipeTagSuggestion.
+func (mr *MockQuerierMockRecorder) CreateRecipeTagSuggestion(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecipeTagSuggestion", reflect.TypeOf((*MockQuerier)(nil).CreateRecipeTagSuggestion), ctx, arg)
+}
+
+// CreateUndoToken mocks base method.
+func (m *MockQuerier) CreateUndoToken(ctx context.Context, arg CreateUndoTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUndoToken", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUndoToken indicates an expected call of CreateUndoToken.
+func (mr *MockQuerierMockRecorder) CreateUndoToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUndoToken", reflect.TypeOf((*MockQuerier)(nil).CreateUndoToken), ctx, arg)
+}
+
+// CreateUploadToken mocks base method.
+func (m *MockQuerier) CreateUploadToken(ctx context.Context, arg CreateUploadTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadToken", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUploadToken indicates an expected call of CreateUploadToken.
+func (mr *MockQuerierMockRecorder) CreateUploadToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadToken", reflect.TypeOf((*MockQuerier)(nil).CreateUploadToken), ctx, arg)
+}
+
 // CreateUser mocks base method.
 func (m *MockQuerier) CreateUser(ctx context.Context, arg CreateUserParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -306,6 +526,81 @@ func (mr *MockQuerierMockRecorder) CreateUser(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockQuerier)(nil).CreateUser), ctx, arg)
 }
 
+// DeleteAppliance mocks base method.
+func (m *MockQuerier) DeleteAppliance(ctx context.Context, arg DeleteApplianceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppliance", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAppliance indicates an expected call of DeleteAppliance.
+func (mr *MockQuerierMockRecorder) DeleteAppliance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppliance", reflect.TypeOf((*MockQuerier)(nil).DeleteAppliance), ctx, arg)
+}
+
+// DeleteExpiredUploadTokens mocks base method.
+func (m *MockQuerier) DeleteExpiredUploadTokens(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredUploadTokens", ctx, expiresAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredUploadTokens indicates an expected call of DeleteExpiredUploadTokens.
+func (mr *MockQuerierMockRecorder) DeleteExpiredUploadTokens(ctx, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredUploadTokens", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredUploadTokens), ctx, expiresAt)
+}
+
+// DeleteFederatedRecipe mocks base method.
+func (m *MockQuerier) DeleteFederatedRecipe(ctx context.Context, arg DeleteFederatedRecipeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFederatedRecipe", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFederatedRecipe indicates an expected call of DeleteFederatedRecipe.
+func (mr *MockQuerierMockRecorder) DeleteFederatedRecipe(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFederatedRecipe", reflect.TypeOf((*MockQuerier)(nil).DeleteFederatedRecipe), ctx, arg)
+}
+
+// DeleteFederationFollower mocks base method.
+func (m *MockQuerier) DeleteFederationFollower(ctx context.Context, arg DeleteFederationFollowerParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFederationFollower", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFederationFollower indicates an expected call of DeleteFederationFollower.
+func (mr *MockQuerierMockRecorder) DeleteFederationFollower(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFederationFollower", reflect.TypeOf((*MockQuerier)(nil).DeleteFederationFollower), ctx, arg)
+}
+
+// DeleteFederationFollowing mocks base method.
+func (m *MockQuerier) DeleteFederationFollowing(ctx context.Context, arg DeleteFederationFollowingParams) (FederationFollowing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFederationFollowing", ctx, arg)
+	ret0, _ := ret[0].(FederationFollowing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFederationFollowing indicates an expected call of DeleteFederationFollowing.
+func (mr *MockQuerierMockRecorder) DeleteFederationFollowing(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFederationFollowing", reflect.TypeOf((*MockQuerier)(nil).DeleteFederationFollowing), ctx, arg)
+}
+
 // DeleteRecipe mocks base method.
 func (m *MockQuerier) DeleteRecipe(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
@@ -321,11 +616,12 @@ func (mr *MockQuerierMockRecorder) DeleteRecipe(ctx, id any) *gomock.Call {
 }
 
 // DeleteRecipeIngredient mocks base method.
-func (m *MockQuerier) DeleteRecipeIngredient(ctx context.Context, id int64) error {
+func (m *MockQuerier) DeleteRecipeIngredient(ctx context.Context, id int64) (RecipeIngredient, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteRecipeIngredient", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(RecipeIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteRecipeIngredient indicates an expected call of DeleteRecipeIngredient.
@@ -363,11 +659,12 @@ func (mr *MockQuerierMockRecorder) DeleteRecipeIngredientsByIDs(ctx, arg any) *g
 }
 
 // DeleteRecipeStep mocks base method.
-func (m *MockQuerier) DeleteRecipeStep(ctx context.Context, id int64) error {
+func (m *MockQuerier) DeleteRecipeStep(ctx context.Context, id int64) (RecipeStep, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteRecipeStep", ctx, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(RecipeStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteRecipeStep indicates an expected call of DeleteRecipeStep.
@@ -404,6 +701,78 @@ func (mr *MockQuerierMockRecorder) DeleteRecipeStepsByIDs(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecipeStepsByIDs", reflect.TypeOf((*MockQuerier)(nil).DeleteRecipeStepsByIDs), ctx, arg)
 }
 
+// DeleteRecipeTagSuggestions mocks base method.
+func (m *MockQuerier) DeleteRecipeTagSuggestions(ctx context.Context, recipeID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecipeTagSuggestions", ctx, recipeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecipeTagSuggestions indicates an expected call of DeleteRecipeTagSuggestions.
+func (mr *MockQuerierMockRecorder) DeleteRecipeTagSuggestions(ctx, recipeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecipeTagSuggestions", reflect.TypeOf((*MockQuerier)(nil).DeleteRecipeTagSuggestions), ctx, recipeID)
+}
+
+// DeleteSentDeliveriesBefore mocks base method.
+func (m *MockQuerier) DeleteSentDeliveriesBefore(ctx context.Context, before pgtype.Timestamptz) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSentDeliveriesBefore", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSentDeliveriesBefore indicates an expected call of DeleteSentDeliveriesBefore.
+func (mr *MockQuerierMockRecorder) DeleteSentDeliveriesBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSentDeliveriesBefore", reflect.TypeOf((*MockQuerier)(nil).DeleteSentDeliveriesBefore), ctx, before)
+}
+
+// DeleteStockImage mocks base method.
+func (m *MockQuerier) DeleteStockImage(ctx context.Context, id int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStockImage", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStockImage indicates an expected call of DeleteStockImage.
+func (mr *MockQuerierMockRecorder) DeleteStockImage(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStockImage", reflect.TypeOf((*MockQuerier)(nil).DeleteStockImage), ctx, id)
+}
+
+// DeleteUndoToken mocks base method.
+func (m *MockQuerier) DeleteUndoToken(ctx context.Context, token string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUndoToken", ctx, token)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUndoToken indicates an expected call of DeleteUndoToken.
+func (mr *MockQuerierMockRecorder) DeleteUndoToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUndoToken", reflect.TypeOf((*MockQuerier)(nil).DeleteUndoToken), ctx, token)
+}
+
+// DeleteUnfollowedFederatedRecipes mocks base method.
+func (m *MockQuerier) DeleteUnfollowedFederatedRecipes(ctx context.Context, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnfollowedFederatedRecipes", ctx, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUnfollowedFederatedRecipes indicates an expected call of DeleteUnfollowedFederatedRecipes.
+func (mr *MockQuerierMockRecorder) DeleteUnfollowedFederatedRecipes(ctx, actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnfollowedFederatedRecipes", reflect.TypeOf((*MockQuerier)(nil).DeleteUnfollowedFederatedRecipes), ctx, actor)
+}
+
 // DeleteUser mocks base method.
 func (m *MockQuerier) DeleteUser(ctx context.Context, id int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -419,6 +788,21 @@ func (mr *MockQuerierMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockQuerier)(nil).DeleteUser), ctx, id)
 }
 
+// DeleteUserTag mocks base method.
+func (m *MockQuerier) DeleteUserTag(ctx context.Context, arg DeleteUserTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTag", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserTag indicates an expected call of DeleteUserTag.
+func (mr *MockQuerierMockRecorder) DeleteUserTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTag", reflect.TypeOf((*MockQuerier)(nil).DeleteUserTag), ctx, arg)
+}
+
 // GetAdminCount mocks base method.
 func (m *MockQuerier) GetAdminCount(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -434,409 +818,1348 @@ func (mr *MockQuerierMockRecorder) GetAdminCount(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminCount", reflect.TypeOf((*MockQuerier)(nil).GetAdminCount), ctx)
 }
 
-// GetAllowPublicSignupPreference mocks base method.
-func (m *MockQuerier) GetAllowPublicSignupPreference(ctx context.Context, id int32) (bool, error) {
+// GetAllRecipeCoverImageKeys mocks base method.
+func (m *MockQuerier) GetAllRecipeCoverImageKeys(ctx context.Context) ([]GetAllRecipeCoverImageKeysRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllowPublicSignupPreference", ctx, id)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetAllRecipeCoverImageKeys", ctx)
+	ret0, _ := ret[0].([]GetAllRecipeCoverImageKeysRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllowPublicSignupPreference indicates an expected call of GetAllowPublicSignupPreference.
-func (mr *MockQuerierMockRecorder) GetAllowPublicSignupPreference(ctx, id any) *gomock.Call {
+// GetAllRecipeCoverImageKeys indicates an expected call of GetAllRecipeCoverImageKeys.
+func (mr *MockQuerierMockRecorder) GetAllRecipeCoverImageKeys(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllowPublicSignupPreference", reflect.TypeOf((*MockQuerier)(nil).GetAllowPublicSignupPreference), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRecipeCoverImageKeys", reflect.TypeOf((*MockQuerier)(nil).GetAllRecipeCoverImageKeys), ctx)
 }
 
-// GetInvitationCode mocks base method.
-func (m *MockQuerier) GetInvitationCode(ctx context.Context, id int64) (string, error) {
+// GetAllRecipeIngredientImageKeys mocks base method.
+func (m *MockQuerier) GetAllRecipeIngredientImageKeys(ctx context.Context) ([]GetAllRecipeIngredientImageKeysRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetInvitationCode", ctx, id)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetAllRecipeIngredientImageKeys", ctx)
+	ret0, _ := ret[0].([]GetAllRecipeIngredientImageKeysRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetInvitationCode indicates an expected call of GetInvitationCode.
-func (mr *MockQuerierMockRecorder) GetInvitationCode(ctx, id any) *gomock.Call {
+// GetAllRecipeIngredientImageKeys indicates an expected call of GetAllRecipeIngredientImageKeys.
+func (mr *MockQuerierMockRecorder) GetAllRecipeIngredientImageKeys(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationCode", reflect.TypeOf((*MockQuerier)(nil).GetInvitationCode), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRecipeIngredientImageKeys", reflect.TypeOf((*MockQuerier)(nil).GetAllRecipeIngredientImageKeys), ctx)
 }
 
-// GetPreferences mocks base method.
-func (m *MockQuerier) GetPreferences(ctx context.Context, id int32) (Preference, error) {
+// GetAllRecipeStepImageKeys mocks base method.
+func (m *MockQuerier) GetAllRecipeStepImageKeys(ctx context.Context) ([]GetAllRecipeStepImageKeysRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPreferences", ctx, id)
-	ret0, _ := ret[0].(Preference)
+	ret := m.ctrl.Call(m, "GetAllRecipeStepImageKeys", ctx)
+	ret0, _ := ret[0].([]GetAllRecipeStepImageKeysRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPreferences indicates an expected call of GetPreferences.
-func (mr *MockQuerierMockRecorder) GetPreferences(ctx, id any) *gomock.Call {
+// GetAllRecipeStepImageKeys indicates an expected call of GetAllRecipeStepImageKeys.
+func (mr *MockQuerierMockRecorder) GetAllRecipeStepImageKeys(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockQuerier)(nil).GetPreferences), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRecipeStepImageKeys", reflect.TypeOf((*MockQuerier)(nil).GetAllRecipeStepImageKeys), ctx)
 }
 
-// GetPublicRecipes mocks base method.
-func (m *MockQuerier) GetPublicRecipes(ctx context.Context) ([]GetPublicRecipesRow, error) {
+// GetAllStockImageKeys mocks base method.
+func (m *MockQuerier) GetAllStockImageKeys(ctx context.Context) ([]GetAllStockImageKeysRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPublicRecipes", ctx)
-	ret0, _ := ret[0].([]GetPublicRecipesRow)
+	ret := m.ctrl.Call(m, "GetAllStockImageKeys", ctx)
+	ret0, _ := ret[0].([]GetAllStockImageKeysRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPublicRecipes indicates an expected call of GetPublicRecipes.
-func (mr *MockQuerierMockRecorder) GetPublicRecipes(ctx any) *gomock.Call {
+// GetAllStockImageKeys indicates an expected call of GetAllStockImageKeys.
+func (mr *MockQuerierMockRecorder) GetAllStockImageKeys(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicRecipes", reflect.TypeOf((*MockQuerier)(nil).GetPublicRecipes), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllStockImageKeys", reflect.TypeOf((*MockQuerier)(nil).GetAllStockImageKeys), ctx)
 }
 
-// GetPublishedRecipeAndOwner mocks base method.
-func (m *MockQuerier) GetPublishedRecipeAndOwner(ctx context.Context, id int64) (GetPublishedRecipeAndOwnerRow, error) {
+// GetAllUndoTokenImageKeys mocks base method.
+func (m *MockQuerier) GetAllUndoTokenImageKeys(ctx context.Context) ([]GetAllUndoTokenImageKeysRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPublishedRecipeAndOwner", ctx, id)
-	ret0, _ := ret[0].(GetPublishedRecipeAndOwnerRow)
+	ret := m.ctrl.Call(m, "GetAllUndoTokenImageKeys", ctx)
+	ret0, _ := ret[0].([]GetAllUndoTokenImageKeysRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPublishedRecipeAndOwner indicates an expected call of GetPublishedRecipeAndOwner.
-func (mr *MockQuerierMockRecorder) GetPublishedRecipeAndOwner(ctx, id any) *gomock.Call {
+// GetAllUndoTokenImageKeys indicates an expected call of GetAllUndoTokenImageKeys.
+func (mr *MockQuerierMockRecorder) GetAllUndoTokenImageKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUndoTokenImageKeys", reflect.TypeOf((*MockQuerier)(nil).GetAllUndoTokenImageKeys), ctx)
+}
+
+// GetAllowPublicSignupPreference mocks base method.
+func (m *MockQuerier) GetAllowPublicSignupPreference(ctx context.Context, id int32) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllowPublicSignupPreference", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllowPublicSignupPreference indicates an expected call of GetAllowPublicSignupPreference.
+func (mr *MockQuerierMockRecorder) GetAllowPublicSignupPreference(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllowPublicSignupPreference", reflect.TypeOf((*MockQuerier)(nil).GetAllowPublicSignupPreference), ctx, id)
+}
+
+// GetAppliance mocks base method.
+func (m *MockQuerier) GetAppliance(ctx context.Context, arg GetApplianceParams) (Appliance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppliance", ctx, arg)
+	ret0, _ := ret[0].(Appliance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppliance indicates an expected call of GetAppliance.
+func (mr *MockQuerierMockRecorder) GetAppliance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppliance", reflect.TypeOf((*MockQuerier)(nil).GetAppliance), ctx, arg)
+}
+
+// GetApplianceByID mocks base method.
+func (m *MockQuerier) GetApplianceByID(ctx context.Context, id int64) (Appliance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplianceByID", ctx, id)
+	ret0, _ := ret[0].(Appliance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplianceByID indicates an expected call of GetApplianceByID.
+func (mr *MockQuerierMockRecorder) GetApplianceByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplianceByID", reflect.TypeOf((*MockQuerier)(nil).GetApplianceByID), ctx, id)
+}
+
+// GetAppliances mocks base method.
+func (m *MockQuerier) GetAppliances(ctx context.Context, userID int64) ([]Appliance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppliances", ctx, userID)
+	ret0, _ := ret[0].([]Appliance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppliances indicates an expected call of GetAppliances.
+func (mr *MockQuerierMockRecorder) GetAppliances(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppliances", reflect.TypeOf((*MockQuerier)(nil).GetAppliances), ctx, userID)
+}
+
+// GetCookingStep mocks base method.
+func (m *MockQuerier) GetCookingStep(ctx context.Context, arg GetCookingStepParams) (GetCookingStepRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCookingStep", ctx, arg)
+	ret0, _ := ret[0].(GetCookingStepRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCookingStep indicates an expected call of GetCookingStep.
+func (mr *MockQuerierMockRecorder) GetCookingStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCookingStep", reflect.TypeOf((*MockQuerier)(nil).GetCookingStep), ctx, arg)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockQuerier) GetDeliveries(ctx context.Context, arg GetDeliveriesParams) ([]Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockQuerierMockRecorder) GetDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockQuerier)(nil).GetDeliveries), ctx, arg)
+}
+
+// GetDelivery mocks base method.
+func (m *MockQuerier) GetDelivery(ctx context.Context, id int64) (Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", ctx, id)
+	ret0, _ := ret[0].(Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockQuerierMockRecorder) GetDelivery(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockQuerier)(nil).GetDelivery), ctx, id)
+}
+
+// GetDeliveryCount mocks base method.
+func (m *MockQuerier) GetDeliveryCount(ctx context.Context, arg GetDeliveryCountParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryCount", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryCount indicates an expected call of GetDeliveryCount.
+func (mr *MockQuerierMockRecorder) GetDeliveryCount(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryCount", reflect.TypeOf((*MockQuerier)(nil).GetDeliveryCount), ctx, arg)
+}
+
+// GetDeliveryStats mocks base method.
+func (m *MockQuerier) GetDeliveryStats(ctx context.Context) ([]GetDeliveryStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryStats", ctx)
+	ret0, _ := ret[0].([]GetDeliveryStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryStats indicates an expected call of GetDeliveryStats.
+func (mr *MockQuerierMockRecorder) GetDeliveryStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryStats", reflect.TypeOf((*MockQuerier)(nil).GetDeliveryStats), ctx)
+}
+
+// GetDensities mocks base method.
+func (m *MockQuerier) GetDensities(ctx context.Context, versionID int64) ([]GetDensitiesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDensities", ctx, versionID)
+	ret0, _ := ret[0].([]GetDensitiesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDensities indicates an expected call of GetDensities.
+func (mr *MockQuerierMockRecorder) GetDensities(ctx, versionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDensities", reflect.TypeOf((*MockQuerier)(nil).GetDensities), ctx, versionID)
+}
+
+// GetDensityVersion mocks base method.
+func (m *MockQuerier) GetDensityVersion(ctx context.Context, id int64) (DensityVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDensityVersion", ctx, id)
+	ret0, _ := ret[0].(DensityVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDensityVersion indicates an expected call of GetDensityVersion.
+func (mr *MockQuerierMockRecorder) GetDensityVersion(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDensityVersion", reflect.TypeOf((*MockQuerier)(nil).GetDensityVersion), ctx, id)
+}
+
+// GetDensityVersions mocks base method.
+func (m *MockQuerier) GetDensityVersions(ctx context.Context) ([]GetDensityVersionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDensityVersions", ctx)
+	ret0, _ := ret[0].([]GetDensityVersionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDensityVersions indicates an expected call of GetDensityVersions.
+func (mr *MockQuerierMockRecorder) GetDensityVersions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDensityVersions", reflect.TypeOf((*MockQuerier)(nil).GetDensityVersions), ctx)
+}
+
+// GetExpiredUndoTokens mocks base method.
+func (m *MockQuerier) GetExpiredUndoTokens(ctx context.Context, arg GetExpiredUndoTokensParams) ([]GetExpiredUndoTokensRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredUndoTokens", ctx, arg)
+	ret0, _ := ret[0].([]GetExpiredUndoTokensRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredUndoTokens indicates an expected call of GetExpiredUndoTokens.
+func (mr *MockQuerierMockRecorder) GetExpiredUndoTokens(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredUndoTokens", reflect.TypeOf((*MockQuerier)(nil).GetExpiredUndoTokens), ctx, arg)
+}
+
+// GetFederatedRecipeCount mocks base method.
+func (m *MockQuerier) GetFederatedRecipeCount(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFederatedRecipeCount", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFederatedRecipeCount indicates an expected call of GetFederatedRecipeCount.
+func (mr *MockQuerierMockRecorder) GetFederatedRecipeCount(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFederatedRecipeCount", reflect.TypeOf((*MockQuerier)(nil).GetFederatedRecipeCount), ctx)
+}
+
+// GetFederatedRecipes mocks base method.
+func (m *MockQuerier) GetFederatedRecipes(ctx context.Context, arg GetFederatedRecipesParams) ([]FederatedRecipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFederatedRecipes", ctx, arg)
+	ret0, _ := ret[0].([]FederatedRecipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFederatedRecipes indicates an expected call of GetFederatedRecipes.
+func (mr *MockQuerierMockRecorder) GetFederatedRecipes(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFederatedRecipes", reflect.TypeOf((*MockQuerier)(nil).GetFederatedRecipes), ctx, arg)
+}
+
+// GetFederationActor mocks base method.
+func (m *MockQuerier) GetFederationActor(ctx context.Context, id int64) (GetFederationActorRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFederationActor", ctx, id)
+	ret0, _ := ret[0].(GetFederationActorRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFederationActor indicates an expected call of GetFederationActor.
+func (mr *MockQuerierMockRecorder) GetFederationActor(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFederationActor", reflect.TypeOf((*MockQuerier)(nil).GetFederationActor), ctx, id)
+}
+
+// GetFederationFollowers mocks base method.
+func (m *MockQuerier) GetFederationFollowers(ctx context.Context, userID int64) ([]FederationFollower, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFederationFollowers", ctx, userID)
+	ret0, _ := ret[0].([]FederationFollower)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFederationFollowers indicates an expected call of GetFederationFollowers.
+func (mr *MockQuerierMockRecorder) GetFederationFollowers(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFederationFollowers", reflect.TypeOf((*MockQuerier)(nil).GetFederationFollowers), ctx, userID)
+}
+
+// GetFederationFollowing mocks base method.
+func (m *MockQuerier) GetFederationFollowing(ctx context.Context, userID int64) ([]FederationFollowing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFederationFollowing", ctx, userID)
+	ret0, _ := ret[0].([]FederationFollowing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFederationFollowing indicates an expected call of GetFederationFollowing.
+func (mr *MockQuerierMockRecorder) GetFederationFollowing(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFederationFollowing", reflect.TypeOf((*MockQuerier)(nil).GetFederationFollowing), ctx, userID)
+}
+
+// GetFederationKey mocks base method.
+func (m *MockQuerier) GetFederationKey(ctx context.Context, userID int64) (FederationKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFederationKey", ctx, userID)
+	ret0, _ := ret[0].(FederationKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFederationKey indicates an expected call of GetFederationKey.
+func (mr *MockQuerierMockRecorder) GetFederationKey(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFederationKey", reflect.TypeOf((*MockQuerier)(nil).GetFederationKey), ctx, userID)
+}
+
+// GetInstanceBranding mocks base method.
+func (m *MockQuerier) GetInstanceBranding(ctx context.Context, id int32) (InstanceBranding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceBranding", ctx, id)
+	ret0, _ := ret[0].(InstanceBranding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceBranding indicates an expected call of GetInstanceBranding.
+func (mr *MockQuerierMockRecorder) GetInstanceBranding(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceBranding", reflect.TypeOf((*MockQuerier)(nil).GetInstanceBranding), ctx, id)
+}
+
+// GetInvitationCode mocks base method.
+func (m *MockQuerier) GetInvitationCode(ctx context.Context, id int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationCode", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationCode indicates an expected call of GetInvitationCode.
+func (mr *MockQuerierMockRecorder) GetInvitationCode(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationCode", reflect.TypeOf((*MockQuerier)(nil).GetInvitationCode), ctx, id)
+}
+
+// GetLatestDensityVersion mocks base method.
+func (m *MockQuerier) GetLatestDensityVersion(ctx context.Context) (DensityVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestDensityVersion", ctx)
+	ret0, _ := ret[0].(DensityVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestDensityVersion indicates an expected call of GetLatestDensityVersion.
+func (mr *MockQuerierMockRecorder) GetLatestDensityVersion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestDensityVersion", reflect.TypeOf((*MockQuerier)(nil).GetLatestDensityVersion), ctx)
+}
+
+// GetPreferences mocks base method.
+func (m *MockQuerier) GetPreferences(ctx context.Context, id int32) (Preference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, id)
+	ret0, _ := ret[0].(Preference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockQuerierMockRecorder) GetPreferences(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockQuerier)(nil).GetPreferences), ctx, id)
+}
+
+// GetPublicRecipeCount mocks base method.
+func (m *MockQuerier) GetPublicRecipeCount(ctx context.Context, allergenPatterns []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicRecipeCount", ctx, allergenPatterns)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicRecipeCount indicates an expected call of GetPublicRecipeCount.
+func (mr *MockQuerierMockRecorder) GetPublicRecipeCount(ctx, allergenPatterns any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicRecipeCount", reflect.TypeOf((*MockQuerier)(nil).GetPublicRecipeCount), ctx, allergenPatterns)
+}
+
+// GetPublicRecipes mocks base method.
+func (m *MockQuerier) GetPublicRecipes(ctx context.Context, arg GetPublicRecipesParams) ([]GetPublicRecipesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicRecipes", ctx, arg)
+	ret0, _ := ret[0].([]GetPublicRecipesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicRecipes indicates an expected call of GetPublicRecipes.
+func (mr *MockQuerierMockRecorder) GetPublicRecipes(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicRecipes", reflect.TypeOf((*MockQuerier)(nil).GetPublicRecipes), ctx, arg)
+}
+
+// GetPublishedRecipeAndOwner mocks base method.
+func (m *MockQuerier) GetPublishedRecipeAndOwner(ctx context.Context, id int64) (GetPublishedRecipeAndOwnerRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublishedRecipeAndOwner", ctx, id)
+	ret0, _ := ret[0].(GetPublishedRecipeAndOwnerRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublishedRecipeAndOwner indicates an expected call of GetPublishedRecipeAndOwner.
+func (mr *MockQuerierMockRecorder) GetPublishedRecipeAndOwner(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedRecipeAndOwner", reflect.TypeOf((*MockQuerier)(nil).GetPublishedRecipeAndOwner), ctx, id)
 }
 
-// GetRecipeAndOwner mocks base method.
-func (m *MockQuerier) GetRecipeAndOwner(ctx context.Context, id int64) (GetRecipeAndOwnerRow, error) {
+// GetPublishedRecipesByOwner mocks base method.
+func (m *MockQuerier) GetPublishedRecipesByOwner(ctx context.Context, arg GetPublishedRecipesByOwnerParams) ([]Recipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublishedRecipesByOwner", ctx, arg)
+	ret0, _ := ret[0].([]Recipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublishedRecipesByOwner indicates an expected call of GetPublishedRecipesByOwner.
+func (mr *MockQuerierMockRecorder) GetPublishedRecipesByOwner(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedRecipesByOwner", reflect.TypeOf((*MockQuerier)(nil).GetPublishedRecipesByOwner), ctx, arg)
+}
+
+// GetPublishedRecipesCreatedSince mocks base method.
+func (m *MockQuerier) GetPublishedRecipesCreatedSince(ctx context.Context, arg GetPublishedRecipesCreatedSinceParams) ([]GetPublishedRecipesCreatedSinceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublishedRecipesCreatedSince", ctx, arg)
+	ret0, _ := ret[0].([]GetPublishedRecipesCreatedSinceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublishedRecipesCreatedSince indicates an expected call of GetPublishedRecipesCreatedSince.
+func (mr *MockQuerierMockRecorder) GetPublishedRecipesCreatedSince(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedRecipesCreatedSince", reflect.TypeOf((*MockQuerier)(nil).GetPublishedRecipesCreatedSince), ctx, arg)
+}
+
+// GetRecipeAndOwner mocks base method.
+func (m *MockQuerier) GetRecipeAndOwner(ctx context.Context, id int64) (GetRecipeAndOwnerRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeAndOwner", ctx, id)
+	ret0, _ := ret[0].(GetRecipeAndOwnerRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeAndOwner indicates an expected call of GetRecipeAndOwner.
+func (mr *MockQuerierMockRecorder) GetRecipeAndOwner(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeAndOwner", reflect.TypeOf((*MockQuerier)(nil).GetRecipeAndOwner), ctx, id)
+}
+
+// GetRecipeImageKey mocks base method.
+func (m *MockQuerier) GetRecipeImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeImageKey", ctx, id)
+	ret0, _ := ret[0].(pgtype.Text)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeImageKey indicates an expected call of GetRecipeImageKey.
+func (mr *MockQuerierMockRecorder) GetRecipeImageKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeImageKey", reflect.TypeOf((*MockQuerier)(nil).GetRecipeImageKey), ctx, id)
+}
+
+// GetRecipeIngredientExistence mocks base method.
+func (m *MockQuerier) GetRecipeIngredientExistence(ctx context.Context, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeIngredientExistence", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeIngredientExistence indicates an expected call of GetRecipeIngredientExistence.
+func (mr *MockQuerierMockRecorder) GetRecipeIngredientExistence(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredientExistence", reflect.TypeOf((*MockQuerier)(nil).GetRecipeIngredientExistence), ctx, id)
+}
+
+// GetRecipeIngredientIDs mocks base method.
+func (m *MockQuerier) GetRecipeIngredientIDs(ctx context.Context, recipeID int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeIngredientIDs", ctx, recipeID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeIngredientIDs indicates an expected call of GetRecipeIngredientIDs.
+func (mr *MockQuerierMockRecorder) GetRecipeIngredientIDs(ctx, recipeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredientIDs", reflect.TypeOf((*MockQuerier)(nil).GetRecipeIngredientIDs), ctx, recipeID)
+}
+
+// GetRecipeIngredientImageKey mocks base method.
+func (m *MockQuerier) GetRecipeIngredientImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeIngredientImageKey", ctx, id)
+	ret0, _ := ret[0].(pgtype.Text)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeIngredientImageKey indicates an expected call of GetRecipeIngredientImageKey.
+func (mr *MockQuerierMockRecorder) GetRecipeIngredientImageKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredientImageKey", reflect.TypeOf((*MockQuerier)(nil).GetRecipeIngredientImageKey), ctx, id)
+}
+
+// GetRecipeIngredients mocks base method.
+func (m *MockQuerier) GetRecipeIngredients(ctx context.Context, recipeID int64) ([]RecipeIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeIngredients", ctx, recipeID)
+	ret0, _ := ret[0].([]RecipeIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeIngredients indicates an expected call of GetRecipeIngredients.
+func (mr *MockQuerierMockRecorder) GetRecipeIngredients(ctx, recipeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredients", reflect.TypeOf((*MockQuerier)(nil).GetRecipeIngredients), ctx, recipeID)
+}
+
+// GetRecipeOwner mocks base method.
+func (m *MockQuerier) GetRecipeOwner(ctx context.Context, id int64) (pgtype.Int8, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeOwner", ctx, id)
+	ret0, _ := ret[0].(pgtype.Int8)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeOwner indicates an expected call of GetRecipeOwner.
+func (mr *MockQuerierMockRecorder) GetRecipeOwner(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeOwner", reflect.TypeOf((*MockQuerier)(nil).GetRecipeOwner), ctx, id)
+}
+
+// GetRecipeStepExistence mocks base method.
+func (m *MockQuerier) GetRecipeStepExistence(ctx context.Context, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeStepExistence", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeStepExistence indicates an expected call of GetRecipeStepExistence.
+func (mr *MockQuerierMockRecorder) GetRecipeStepExistence(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeStepExistence", reflect.TypeOf((*MockQuerier)(nil).GetRecipeStepExistence), ctx, id)
+}
+
+// GetRecipeStepIDs mocks base method.
+func (m *MockQuerier) GetRecipeStepIDs(ctx context.Context, recipeID int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeStepIDs", ctx, recipeID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeStepIDs indicates an expected call of GetRecipeStepIDs.
+func (mr *MockQuerierMockRecorder) GetRecipeStepIDs(ctx, recipeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeStepIDs", reflect.TypeOf((*MockQuerier)(nil).GetRecipeStepIDs), ctx, recipeID)
+}
+
+// GetRecipeStepImageKey mocks base method.
+func (m *MockQuerier) GetRecipeStepImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeStepImageKey", ctx, id)
+	ret0, _ := ret[0].(pgtype.Text)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeStepImageKey indicates an expected call of GetRecipeStepImageKey.
+func (mr *MockQuerierMockRecorder) GetRecipeStepImageKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeStepImageKey", reflect.TypeOf((*MockQuerier)(nil).GetRecipeStepImageKey), ctx, id)
+}
+
+// GetRecipeSteps mocks base method.
+func (m *MockQuerier) GetRecipeSteps(ctx context.Context, recipeID int64) ([]RecipeStep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeSteps", ctx, recipeID)
+	ret0, _ := ret[0].([]RecipeStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeSteps indicates an expected call of GetRecipeSteps.
+func (mr *MockQuerierMockRecorder) GetRecipeSteps(ctx, recipeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeSteps", reflect.TypeOf((*MockQuerier)(nil).GetRecipeSteps), ctx, recipeID)
+}
+
+// GetRecipeTagSuggestions mocks base method.
+func (m *MockQuerier) GetRecipeTagSuggestions(ctx context.Context, recipeID int64) ([]GetRecipeTagSuggestionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeTagSuggestions", ctx, recipeID)
+	ret0, _ := ret[0].([]GetRecipeTagSuggestionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeTagSuggestions indicates an expected call of GetRecipeTagSuggestions.
+func (mr *MockQuerierMockRecorder) GetRecipeTagSuggestions(ctx, recipeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeTagSuggestions", reflect.TypeOf((*MockQuerier)(nil).GetRecipeTagSuggestions), ctx, recipeID)
+}
+
+// GetRecipeTags mocks base method.
+func (m *MockQuerier) GetRecipeTags(ctx context.Context, recipeID int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipeTags", ctx, recipeID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipeTags indicates an expected call of GetRecipeTags.
+func (mr *MockQuerierMockRecorder) GetRecipeTags(ctx, recipeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeTags", reflect.TypeOf((*MockQuerier)(nil).GetRecipeTags), ctx, recipeID)
+}
+
+// GetRecipesByOwner mocks base method.
+func (m *MockQuerier) GetRecipesByOwner(ctx context.Context, arg GetRecipesByOwnerParams) ([]GetRecipesByOwnerRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecipesByOwner", ctx, arg)
+	ret0, _ := ret[0].([]GetRecipesByOwnerRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipesByOwner indicates an expected call of GetRecipesByOwner.
+func (mr *MockQuerierMockRecorder) GetRecipesByOwner(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipesByOwner", reflect.TypeOf((*MockQuerier)(nil).GetRecipesByOwner), ctx, arg)
+}
+
+// GetStockImage mocks base method.
+func (m *MockQuerier) GetStockImage(ctx context.Context, id int64) (StockImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockImage", ctx, id)
+	ret0, _ := ret[0].(StockImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockImage indicates an expected call of GetStockImage.
+func (mr *MockQuerierMockRecorder) GetStockImage(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockImage", reflect.TypeOf((*MockQuerier)(nil).GetStockImage), ctx, id)
+}
+
+// GetStockImages mocks base method.
+func (m *MockQuerier) GetStockImages(ctx context.Context) ([]StockImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockImages", ctx)
+	ret0, _ := ret[0].([]StockImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockImages indicates an expected call of GetStockImages.
+func (mr *MockQuerierMockRecorder) GetStockImages(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockImages", reflect.TypeOf((*MockQuerier)(nil).GetStockImages), ctx)
+}
+
+// GetUntaggedRecipes mocks base method.
+func (m *MockQuerier) GetUntaggedRecipes(ctx context.Context, arg GetUntaggedRecipesParams) ([]GetUntaggedRecipesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUntaggedRecipes", ctx, arg)
+	ret0, _ := ret[0].([]GetUntaggedRecipesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUntaggedRecipes indicates an expected call of GetUntaggedRecipes.
+func (mr *MockQuerierMockRecorder) GetUntaggedRecipes(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUntaggedRecipes", reflect.TypeOf((*MockQuerier)(nil).GetUntaggedRecipes), ctx, arg)
+}
+
+// GetUploadTokenUser mocks base method.
+func (m *MockQuerier) GetUploadTokenUser(ctx context.Context, arg GetUploadTokenUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadTokenUser", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadTokenUser indicates an expected call of GetUploadTokenUser.
+func (mr *MockQuerierMockRecorder) GetUploadTokenUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadTokenUser", reflect.TypeOf((*MockQuerier)(nil).GetUploadTokenUser), ctx, arg)
+}
+
+// GetUser mocks base method.
+func (m *MockQuerier) GetUser(ctx context.Context, lower string) (GetUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, lower)
+	ret0, _ := ret[0].(GetUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockQuerierMockRecorder) GetUser(ctx, lower any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockQuerier)(nil).GetUser), ctx, lower)
+}
+
+// GetUserActivity mocks base method.
+func (m *MockQuerier) GetUserActivity(ctx context.Context, arg GetUserActivityParams) ([]GetUserActivityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserActivity", ctx, arg)
+	ret0, _ := ret[0].([]GetUserActivityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserActivity indicates an expected call of GetUserActivity.
+func (mr *MockQuerierMockRecorder) GetUserActivity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserActivity", reflect.TypeOf((*MockQuerier)(nil).GetUserActivity), ctx, arg)
+}
+
+// GetUserActivityCount mocks base method.
+func (m *MockQuerier) GetUserActivityCount(ctx context.Context, actorID pgtype.Int8) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserActivityCount", ctx, actorID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserActivityCount indicates an expected call of GetUserActivityCount.
+func (mr *MockQuerierMockRecorder) GetUserActivityCount(ctx, actorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserActivityCount", reflect.TypeOf((*MockQuerier)(nil).GetUserActivityCount), ctx, actorID)
+}
+
+// GetUserAllergies mocks base method.
+func (m *MockQuerier) GetUserAllergies(ctx context.Context, id int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAllergies", ctx, id)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAllergies indicates an expected call of GetUserAllergies.
+func (mr *MockQuerierMockRecorder) GetUserAllergies(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAllergies", reflect.TypeOf((*MockQuerier)(nil).GetUserAllergies), ctx, id)
+}
+
+// GetUserById mocks base method.
+func (m *MockQuerier) GetUserById(ctx context.Context, id int64) (GetUserByIdRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", ctx, id)
+	ret0, _ := ret[0].(GetUserByIdRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockQuerierMockRecorder) GetUserById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockQuerier)(nil).GetUserById), ctx, id)
+}
+
+// GetUserCount mocks base method.
+func (m *MockQuerier) GetUserCount(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCount", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCount indicates an expected call of GetUserCount.
+func (mr *MockQuerierMockRecorder) GetUserCount(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCount", reflect.TypeOf((*MockQuerier)(nil).GetUserCount), ctx)
+}
+
+// GetUserForExport mocks base method.
+func (m *MockQuerier) GetUserForExport(ctx context.Context, id int64) (GetUserForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserForExport", ctx, id)
+	ret0, _ := ret[0].(GetUserForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserForExport indicates an expected call of GetUserForExport.
+func (mr *MockQuerierMockRecorder) GetUserForExport(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForExport", reflect.TypeOf((*MockQuerier)(nil).GetUserForExport), ctx, id)
+}
+
+// GetUserPasswordHash mocks base method.
+func (m *MockQuerier) GetUserPasswordHash(ctx context.Context, id int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPasswordHash", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPasswordHash indicates an expected call of GetUserPasswordHash.
+func (mr *MockQuerierMockRecorder) GetUserPasswordHash(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPasswordHash", reflect.TypeOf((*MockQuerier)(nil).GetUserPasswordHash), ctx, id)
+}
+
+// GetUserPreferences mocks base method.
+func (m *MockQuerier) GetUserPreferences(ctx context.Context, id int64) (GetUserPreferencesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPreferences", ctx, id)
+	ret0, _ := ret[0].(GetUserPreferencesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPreferences indicates an expected call of GetUserPreferences.
+func (mr *MockQuerierMockRecorder) GetUserPreferences(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPreferences", reflect.TypeOf((*MockQuerier)(nil).GetUserPreferences), ctx, id)
+}
+
+// GetUserRecipeCount mocks base method.
+func (m *MockQuerier) GetUserRecipeCount(ctx context.Context, userID pgtype.Int8) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRecipeCount", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRecipeCount indicates an expected call of GetUserRecipeCount.
+func (mr *MockQuerierMockRecorder) GetUserRecipeCount(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecipeCount", reflect.TypeOf((*MockQuerier)(nil).GetUserRecipeCount), ctx, userID)
+}
+
+// GetUserRecipeIDSample mocks base method.
+func (m *MockQuerier) GetUserRecipeIDSample(ctx context.Context, arg GetUserRecipeIDSampleParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRecipeIDSample", ctx, arg)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRecipeIDSample indicates an expected call of GetUserRecipeIDSample.
+func (mr *MockQuerierMockRecorder) GetUserRecipeIDSample(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecipeIDSample", reflect.TypeOf((*MockQuerier)(nil).GetUserRecipeIDSample), ctx, arg)
+}
+
+// GetUserRecipeImages mocks base method.
+func (m *MockQuerier) GetUserRecipeImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRecipeImages", ctx, userID)
+	ret0, _ := ret[0].([]pgtype.Text)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRecipeImages indicates an expected call of GetUserRecipeImages.
+func (mr *MockQuerierMockRecorder) GetUserRecipeImages(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecipeImages", reflect.TypeOf((*MockQuerier)(nil).GetUserRecipeImages), ctx, userID)
+}
+
+// GetUserRecipeIngredientImages mocks base method.
+func (m *MockQuerier) GetUserRecipeIngredientImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRecipeIngredientImages", ctx, userID)
+	ret0, _ := ret[0].([]pgtype.Text)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRecipeIngredientImages indicates an expected call of GetUserRecipeIngredientImages.
+func (mr *MockQuerierMockRecorder) GetUserRecipeIngredientImages(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecipeIngredientImages", reflect.TypeOf((*MockQuerier)(nil).GetUserRecipeIngredientImages), ctx, userID)
+}
+
+// GetUserRecipeStepImages mocks base method.
+func (m *MockQuerier) GetUserRecipeStepImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRecipeStepImages", ctx, userID)
+	ret0, _ := ret[0].([]pgtype.Text)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRecipeStepImages indicates an expected call of GetUserRecipeStepImages.
+func (mr *MockQuerierMockRecorder) GetUserRecipeStepImages(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecipeStepImages", reflect.TypeOf((*MockQuerier)(nil).GetUserRecipeStepImages), ctx, userID)
+}
+
+// GetUserRecipesCreatedSince mocks base method.
+func (m *MockQuerier) GetUserRecipesCreatedSince(ctx context.Context, arg GetUserRecipesCreatedSinceParams) ([]GetUserRecipesCreatedSinceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRecipesCreatedSince", ctx, arg)
+	ret0, _ := ret[0].([]GetUserRecipesCreatedSinceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRecipesCreatedSince indicates an expected call of GetUserRecipesCreatedSince.
+func (mr *MockQuerierMockRecorder) GetUserRecipesCreatedSince(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecipesCreatedSince", reflect.TypeOf((*MockQuerier)(nil).GetUserRecipesCreatedSince), ctx, arg)
+}
+
+// GetUserRefreshTokenHash mocks base method.
+func (m *MockQuerier) GetUserRefreshTokenHash(ctx context.Context, id int64) (GetUserRefreshTokenHashRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeAndOwner", ctx, id)
-	ret0, _ := ret[0].(GetRecipeAndOwnerRow)
+	ret := m.ctrl.Call(m, "GetUserRefreshTokenHash", ctx, id)
+	ret0, _ := ret[0].(GetUserRefreshTokenHashRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecipeAndOwner indicates an expected call of GetRecipeAndOwner.
-func (mr *MockQuerierMockRecorder) GetRecipeAndOwner(ctx, id any) *gomock.Call {
+// GetUserRefreshTokenHash indicates an expected call of GetUserRefreshTokenHash.
+func (mr *MockQuerierMockRecorder) GetUserRefreshTokenHash(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeAndOwner", reflect.TypeOf((*MockQuerier)(nil).GetRecipeAndOwner), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRefreshTokenHash", reflect.TypeOf((*MockQuerier)(nil).GetUserRefreshTokenHash), ctx, id)
 }
 
-// GetRecipeImageKey mocks base method.
-func (m *MockQuerier) GetRecipeImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
+// GetUserRole mocks base method.
+func (m *MockQuerier) GetUserRole(ctx context.Context, id int64) (Role, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeImageKey", ctx, id)
-	ret0, _ := ret[0].(pgtype.Text)
+	ret := m.ctrl.Call(m, "GetUserRole", ctx, id)
+	ret0, _ := ret[0].(Role)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecipeImageKey indicates an expected call of GetRecipeImageKey.
-func (mr *MockQuerierMockRecorder) GetRecipeImageKey(ctx, id any) *gomock.Call {
+// GetUserRole indicates an expected call of GetUserRole.
+func (mr *MockQuerierMockRecorder) GetUserRole(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeImageKey", reflect.TypeOf((*MockQuerier)(nil).GetRecipeImageKey), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRole", reflect.TypeOf((*MockQuerier)(nil).GetUserRole), ctx, id)
 }
 
-// GetRecipeIngredientExistence mocks base method.
-func (m *MockQuerier) GetRecipeIngredientExistence(ctx context.Context, id int64) (bool, error) {
+// GetUserTags mocks base method.
+func (m *MockQuerier) GetUserTags(ctx context.Context, userID pgtype.Int8) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeIngredientExistence", ctx, id)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetUserTags", ctx, userID)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecipeIngredientExistence indicates an expected call of GetRecipeIngredientExistence.
-func (mr *MockQuerierMockRecorder) GetRecipeIngredientExistence(ctx, id any) *gomock.Call {
+// GetUserTags indicates an expected call of GetUserTags.
+func (mr *MockQuerierMockRecorder) GetUserTags(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredientExistence", reflect.TypeOf((*MockQuerier)(nil).GetRecipeIngredientExistence), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTags", reflect.TypeOf((*MockQuerier)(nil).GetUserTags), ctx, userID)
 }
 
-// GetRecipeIngredientIDs mocks base method.
-func (m *MockQuerier) GetRecipeIngredientIDs(ctx context.Context, recipeID int64) ([]int64, error) {
+// GetUserTemperatureUnit mocks base method.
+func (m *MockQuerier) GetUserTemperatureUnit(ctx context.Context, id int64) (NullTemperatureUnit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeIngredientIDs", ctx, recipeID)
-	ret0, _ := ret[0].([]int64)
+	ret := m.ctrl.Call(m, "GetUserTemperatureUnit", ctx, id)
+	ret0, _ := ret[0].(NullTemperatureUnit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecipeIngredientIDs indicates an expected call of GetRecipeIngredientIDs.
-func (mr *MockQuerierMockRecorder) GetRecipeIngredientIDs(ctx, recipeID any) *gomock.Call {
+// GetUserTemperatureUnit indicates an expected call of GetUserTemperatureUnit.
+func (mr *MockQuerierMockRecorder) GetUserTemperatureUnit(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredientIDs", reflect.TypeOf((*MockQuerier)(nil).GetRecipeIngredientIDs), ctx, recipeID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTemperatureUnit", reflect.TypeOf((*MockQuerier)(nil).GetUserTemperatureUnit), ctx, id)
 }
 
-// GetRecipeIngredientImageKey mocks base method.
-func (m *MockQuerier) GetRecipeIngredientImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
+// GetUsers mocks base method.
+func (m *MockQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeIngredientImageKey", ctx, id)
-	ret0, _ := ret[0].(pgtype.Text)
+	ret := m.ctrl.Call(m, "GetUsers", ctx, arg)
+	ret0, _ := ret[0].([]GetUsersRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecipeIngredientImageKey indicates an expected call of GetRecipeIngredientImageKey.
-func (mr *MockQuerierMockRecorder) GetRecipeIngredientImageKey(ctx, id any) *gomock.Call {
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockQuerierMockRecorder) GetUsers(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredientImageKey", reflect.TypeOf((*MockQuerier)(nil).GetRecipeIngredientImageKey), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockQuerier)(nil).GetUsers), ctx, arg)
 }
 
-// GetRecipeIngredients mocks base method.
-func (m *MockQuerier) GetRecipeIngredients(ctx context.Context, recipeID int64) ([]RecipeIngredient, error) {
+// GetWeeklyReportRecipients mocks base method.
+func (m *MockQuerier) GetWeeklyReportRecipients(ctx context.Context, arg GetWeeklyReportRecipientsParams) ([]GetWeeklyReportRecipientsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeIngredients", ctx, recipeID)
-	ret0, _ := ret[0].([]RecipeIngredient)
+	ret := m.ctrl.Call(m, "GetWeeklyReportRecipients", ctx, arg)
+	ret0, _ := ret[0].([]GetWeeklyReportRecipientsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecipeIngredients indicates an expected call of GetRecipeIngredients.
-func (mr *MockQuerierMockRecorder) GetRecipeIngredients(ctx, recipeID any) *gomock.Call {
+// GetWeeklyReportRecipients indicates an expected call of GetWeeklyReportRecipients.
+func (mr *MockQuerierMockRecorder) GetWeeklyReportRecipients(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeIngredients", reflect.TypeOf((*MockQuerier)(nil).GetRecipeIngredients), ctx, recipeID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWeeklyReportRecipients", reflect.TypeOf((*MockQuerier)(nil).GetWeeklyReportRecipients), ctx, arg)
 }
 
-// GetRecipeOwner mocks base method.
-func (m *MockQuerier) GetRecipeOwner(ctx context.Context, id int64) (pgtype.Int8, error) {
+// ImportDensities mocks base method.
+func (m *MockQuerier) ImportDensities(ctx context.Context, arg ImportDensitiesParams) (ImportDensitiesRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeOwner", ctx, id)
-	ret0, _ := ret[0].(pgtype.Int8)
+	ret := m.ctrl.Call(m, "ImportDensities", ctx, arg)
+	ret0, _ := ret[0].(ImportDensitiesRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecipeOwner indicates an expected call of GetRecipeOwner.
-func (mr *MockQuerierMockRecorder) GetRecipeOwner(ctx, id any) *gomock.Call {
+// ImportDensities indicates an expected call of ImportDensities.
+func (mr *MockQuerierMockRecorder) ImportDensities(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeOwner", reflect.TypeOf((*MockQuerier)(nil).GetRecipeOwner), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportDensities", reflect.TypeOf((*MockQuerier)(nil).ImportDensities), ctx, arg)
 }
 
-// GetRecipeStepExistence mocks base method.
-func (m *MockQuerier) GetRecipeStepExistence(ctx context.Context, id int64) (bool, error) {
+// IsFederationActorFollowed mocks base method.
+func (m *MockQuerier) IsFederationActorFollowed(ctx context.Context, actor string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeStepExistence", ctx, id)
+	ret := m.ctrl.Call(m, "IsFederationActorFollowed", ctx, actor)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecipeStepExistence indicates an expected call of GetRecipeStepExistence.
-func (mr *MockQuerierMockRecorder) GetRecipeStepExistence(ctx, id any) *gomock.Call {
+// IsFederationActorFollowed indicates an expected call of IsFederationActorFollowed.
+func (mr *MockQuerierMockRecorder) IsFederationActorFollowed(ctx, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeStepExistence", reflect.TypeOf((*MockQuerier)(nil).GetRecipeStepExistence), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFederationActorFollowed", reflect.TypeOf((*MockQuerier)(nil).IsFederationActorFollowed), ctx, actor)
 }
 
-// GetRecipeStepIDs mocks base method.
-func (m *MockQuerier) GetRecipeStepIDs(ctx context.Context, recipeID int64) ([]int64, error) {
+// MarkDeliveryFailed mocks base method.
+func (m *MockQuerier) MarkDeliveryFailed(ctx context.Context, arg MarkDeliveryFailedParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeStepIDs", ctx, recipeID)
-	ret0, _ := ret[0].([]int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkDeliveryFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetRecipeStepIDs indicates an expected call of GetRecipeStepIDs.
-func (mr *MockQuerierMockRecorder) GetRecipeStepIDs(ctx, recipeID any) *gomock.Call {
+// MarkDeliveryFailed indicates an expected call of MarkDeliveryFailed.
+func (mr *MockQuerierMockRecorder) MarkDeliveryFailed(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeStepIDs", reflect.TypeOf((*MockQuerier)(nil).GetRecipeStepIDs), ctx, recipeID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDeliveryFailed", reflect.TypeOf((*MockQuerier)(nil).MarkDeliveryFailed), ctx, arg)
 }
 
-// GetRecipeStepImageKey mocks base method.
-func (m *MockQuerier) GetRecipeStepImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
+// MarkDeliverySent mocks base method.
+func (m *MockQuerier) MarkDeliverySent(ctx context.Context, arg MarkDeliverySentParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeStepImageKey", ctx, id)
-	ret0, _ := ret[0].(pgtype.Text)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkDeliverySent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetRecipeStepImageKey indicates an expected call of GetRecipeStepImageKey.
-func (mr *MockQuerierMockRecorder) GetRecipeStepImageKey(ctx, id any) *gomock.Call {
+// MarkDeliverySent indicates an expected call of MarkDeliverySent.
+func (mr *MockQuerierMockRecorder) MarkDeliverySent(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeStepImageKey", reflect.TypeOf((*MockQuerier)(nil).GetRecipeStepImageKey), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDeliverySent", reflect.TypeOf((*MockQuerier)(nil).MarkDeliverySent), ctx, arg)
 }
 
-// GetRecipeSteps mocks base method.
-func (m *MockQuerier) GetRecipeSteps(ctx context.Context, recipeID int64) ([]RecipeStep, error) {
+// MarkWeeklyReportSent mocks base method.
+func (m *MockQuerier) MarkWeeklyReportSent(ctx context.Context, arg MarkWeeklyReportSentParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipeSteps", ctx, recipeID)
-	ret0, _ := ret[0].([]RecipeStep)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkWeeklyReportSent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetRecipeSteps indicates an expected call of GetRecipeSteps.
-func (mr *MockQuerierMockRecorder) GetRecipeSteps(ctx, recipeID any) *gomock.Call {
+// MarkWeeklyReportSent indicates an expected call of MarkWeeklyReportSent.
+func (mr *MockQuerierMockRecorder) MarkWeeklyReportSent(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipeSteps", reflect.TypeOf((*MockQuerier)(nil).GetRecipeSteps), ctx, recipeID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWeeklyReportSent", reflect.TypeOf((*MockQuerier)(nil).MarkWeeklyReportSent), ctx, arg)
 }
 
-// GetRecipesByOwner mocks base method.
-func (m *MockQuerier) GetRecipesByOwner(ctx context.Context, id int64) ([]GetRecipesByOwnerRow, error) {
+// MatchStockImage mocks base method.
+func (m *MockQuerier) MatchStockImage(ctx context.Context, candidates []string) (StockImage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipesByOwner", ctx, id)
-	ret0, _ := ret[0].([]GetRecipesByOwnerRow)
+	ret := m.ctrl.Call(m, "MatchStockImage", ctx, candidates)
+	ret0, _ := ret[0].(StockImage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecipesByOwner indicates an expected call of GetRecipesByOwner.
-func (mr *MockQuerierMockRecorder) GetRecipesByOwner(ctx, id any) *gomock.Call {
+// MatchStockImage indicates an expected call of MatchStockImage.
+func (mr *MockQuerierMockRecorder) MatchStockImage(ctx, candidates any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipesByOwner", reflect.TypeOf((*MockQuerier)(nil).GetRecipesByOwner), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchStockImage", reflect.TypeOf((*MockQuerier)(nil).MatchStockImage), ctx, candidates)
 }
 
-// GetUser mocks base method.
-func (m *MockQuerier) GetUser(ctx context.Context, lower string) (GetUserRow, error) {
+// MoveUserTag mocks base method.
+func (m *MockQuerier) MoveUserTag(ctx context.Context, arg MoveUserTagParams) (MoveUserTagRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", ctx, lower)
-	ret0, _ := ret[0].(GetUserRow)
+	ret := m.ctrl.Call(m, "MoveUserTag", ctx, arg)
+	ret0, _ := ret[0].(MoveUserTagRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockQuerierMockRecorder) GetUser(ctx, lower any) *gomock.Call {
+// MoveUserTag indicates an expected call of MoveUserTag.
+func (mr *MockQuerierMockRecorder) MoveUserTag(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockQuerier)(nil).GetUser), ctx, lower)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUserTag", reflect.TypeOf((*MockQuerier)(nil).MoveUserTag), ctx, arg)
 }
 
-// GetUserById mocks base method.
-func (m *MockQuerier) GetUserById(ctx context.Context, id int64) (GetUserByIdRow, error) {
+// RedeemInvitationCode mocks base method.
+func (m *MockQuerier) RedeemInvitationCode(ctx context.Context, id int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserById", ctx, id)
-	ret0, _ := ret[0].(GetUserByIdRow)
+	ret := m.ctrl.Call(m, "RedeemInvitationCode", ctx, id)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserById indicates an expected call of GetUserById.
-func (mr *MockQuerierMockRecorder) GetUserById(ctx, id any) *gomock.Call {
+// RedeemInvitationCode indicates an expected call of RedeemInvitationCode.
+func (mr *MockQuerierMockRecorder) RedeemInvitationCode(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockQuerier)(nil).GetUserById), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemInvitationCode", reflect.TypeOf((*MockQuerier)(nil).RedeemInvitationCode), ctx, id)
 }
 
-// GetUserPasswordHash mocks base method.
-func (m *MockQuerier) GetUserPasswordHash(ctx context.Context, id int64) (string, error) {
+// RenameUserTag mocks base method.
+func (m *MockQuerier) RenameUserTag(ctx context.Context, arg RenameUserTagParams) (RenameUserTagRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserPasswordHash", ctx, id)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "RenameUserTag", ctx, arg)
+	ret0, _ := ret[0].(RenameUserTagRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserPasswordHash indicates an expected call of GetUserPasswordHash.
-func (mr *MockQuerierMockRecorder) GetUserPasswordHash(ctx, id any) *gomock.Call {
+// RenameUserTag indicates an expected call of RenameUserTag.
+func (mr *MockQuerierMockRecorder) RenameUserTag(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPasswordHash", reflect.TypeOf((*MockQuerier)(nil).GetUserPasswordHash), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameUserTag", reflect.TypeOf((*MockQuerier)(nil).RenameUserTag), ctx, arg)
 }
 
-// GetUserRecipeImages mocks base method.
-func (m *MockQuerier) GetUserRecipeImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
+// RestoreRecipeCoverImage mocks base method.
+func (m *MockQuerier) RestoreRecipeCoverImage(ctx context.Context, arg RestoreRecipeCoverImageParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserRecipeImages", ctx, userID)
-	ret0, _ := ret[0].([]pgtype.Text)
+	ret := m.ctrl.Call(m, "RestoreRecipeCoverImage", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserRecipeImages indicates an expected call of GetUserRecipeImages.
-func (mr *MockQuerierMockRecorder) GetUserRecipeImages(ctx, userID any) *gomock.Call {
+// RestoreRecipeCoverImage indicates an expected call of RestoreRecipeCoverImage.
+func (mr *MockQuerierMockRecorder) RestoreRecipeCoverImage(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecipeImages", reflect.TypeOf((*MockQuerier)(nil).GetUserRecipeImages), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRecipeCoverImage", reflect.TypeOf((*MockQuerier)(nil).RestoreRecipeCoverImage), ctx, arg)
 }
 
-// GetUserRecipeIngredientImages mocks base method.
-func (m *MockQuerier) GetUserRecipeIngredientImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
+// RestoreRecipeIngredient mocks base method.
+func (m *MockQuerier) RestoreRecipeIngredient(ctx context.Context, arg RestoreRecipeIngredientParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserRecipeIngredientImages", ctx, userID)
-	ret0, _ := ret[0].([]pgtype.Text)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "RestoreRecipeIngredient", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetUserRecipeIngredientImages indicates an expected call of GetUserRecipeIngredientImages.
-func (mr *MockQuerierMockRecorder) GetUserRecipeIngredientImages(ctx, userID any) *gomock.Call {
+// RestoreRecipeIngredient indicates an expected call of RestoreRecipeIngredient.
+func (mr *MockQuerierMockRecorder) RestoreRecipeIngredient(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecipeIngredientImages", reflect.TypeOf((*MockQuerier)(nil).GetUserRecipeIngredientImages), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRecipeIngredient", reflect.TypeOf((*MockQuerier)(nil).RestoreRecipeIngredient), ctx, arg)
 }
 
-// GetUserRecipeStepImages mocks base method.
-func (m *MockQuerier) GetUserRecipeStepImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
+// RestoreRecipeIngredientImage mocks base method.
+func (m *MockQuerier) RestoreRecipeIngredientImage(ctx context.Context, arg RestoreRecipeIngredientImageParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserRecipeStepImages", ctx, userID)
-	ret0, _ := ret[0].([]pgtype.Text)
+	ret := m.ctrl.Call(m, "RestoreRecipeIngredientImage", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserRecipeStepImages indicates an expected call of GetUserRecipeStepImages.
-func (mr *MockQuerierMockRecorder) GetUserRecipeStepImages(ctx, userID any) *gomock.Call {
+// RestoreRecipeIngredientImage indicates an expected call of RestoreRecipeIngredientImage.
+func (mr *MockQuerierMockRecorder) RestoreRecipeIngredientImage(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecipeStepImages", reflect.TypeOf((*MockQuerier)(nil).GetUserRecipeStepImages), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRecipeIngredientImage", reflect.TypeOf((*MockQuerier)(nil).RestoreRecipeIngredientImage), ctx, arg)
 }
 
-// GetUserRefreshTokenHash mocks base method.
-func (m *MockQuerier) GetUserRefreshTokenHash(ctx context.Context, id int64) (GetUserRefreshTokenHashRow, error) {
+// RestoreRecipeStep mocks base method.
+func (m *MockQuerier) RestoreRecipeStep(ctx context.Context, arg RestoreRecipeStepParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserRefreshTokenHash", ctx, id)
-	ret0, _ := ret[0].(GetUserRefreshTokenHashRow)
+	ret := m.ctrl.Call(m, "RestoreRecipeStep", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRecipeStep indicates an expected call of RestoreRecipeStep.
+func (mr *MockQuerierMockRecorder) RestoreRecipeStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRecipeStep", reflect.TypeOf((*MockQuerier)(nil).RestoreRecipeStep), ctx, arg)
+}
+
+// RestoreRecipeStepImage mocks base method.
+func (m *MockQuerier) RestoreRecipeStepImage(ctx context.Context, arg RestoreRecipeStepImageParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRecipeStepImage", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserRefreshTokenHash indicates an expected call of GetUserRefreshTokenHash.
-func (mr *MockQuerierMockRecorder) GetUserRefreshTokenHash(ctx, id any) *gomock.Call {
+// RestoreRecipeStepImage indicates an expected call of RestoreRecipeStepImage.
+func (mr *MockQuerierMockRecorder) RestoreRecipeStepImage(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRefreshTokenHash", reflect.TypeOf((*MockQuerier)(nil).GetUserRefreshTokenHash), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRecipeStepImage", reflect.TypeOf((*MockQuerier)(nil).RestoreRecipeStepImage), ctx, arg)
 }
 
-// GetUserRole mocks base method.
-func (m *MockQuerier) GetUserRole(ctx context.Context, id int64) (Role, error) {
+// SetInstanceLogo mocks base method.
+func (m *MockQuerier) SetInstanceLogo(ctx context.Context, arg SetInstanceLogoParams) (pgtype.Text, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserRole", ctx, id)
-	ret0, _ := ret[0].(Role)
+	ret := m.ctrl.Call(m, "SetInstanceLogo", ctx, arg)
+	ret0, _ := ret[0].(pgtype.Text)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserRole indicates an expected call of GetUserRole.
-func (mr *MockQuerierMockRecorder) GetUserRole(ctx, id any) *gomock.Call {
+// SetInstanceLogo indicates an expected call of SetInstanceLogo.
+func (mr *MockQuerierMockRecorder) SetInstanceLogo(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRole", reflect.TypeOf((*MockQuerier)(nil).GetUserRole), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceLogo", reflect.TypeOf((*MockQuerier)(nil).SetInstanceLogo), ctx, arg)
 }
 
-// GetUsers mocks base method.
-func (m *MockQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
+// SetRecipeTagSuggestionInputs mocks base method.
+func (m *MockQuerier) SetRecipeTagSuggestionInputs(ctx context.Context, arg SetRecipeTagSuggestionInputsParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUsers", ctx, arg)
-	ret0, _ := ret[0].([]GetUsersRow)
+	ret := m.ctrl.Call(m, "SetRecipeTagSuggestionInputs", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRecipeTagSuggestionInputs indicates an expected call of SetRecipeTagSuggestionInputs.
+func (mr *MockQuerierMockRecorder) SetRecipeTagSuggestionInputs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRecipeTagSuggestionInputs", reflect.TypeOf((*MockQuerier)(nil).SetRecipeTagSuggestionInputs), ctx, arg)
+}
+
+// UnsubscribeWeeklyReport mocks base method.
+func (m *MockQuerier) UnsubscribeWeeklyReport(ctx context.Context, id int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribeWeeklyReport", ctx, id)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUsers indicates an expected call of GetUsers.
-func (mr *MockQuerierMockRecorder) GetUsers(ctx, arg any) *gomock.Call {
+// UnsubscribeWeeklyReport indicates an expected call of UnsubscribeWeeklyReport.
+func (mr *MockQuerierMockRecorder) UnsubscribeWeeklyReport(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockQuerier)(nil).GetUsers), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeWeeklyReport", reflect.TypeOf((*MockQuerier)(nil).UnsubscribeWeeklyReport), ctx, id)
 }
 
-// RedeemInvitationCode mocks base method.
-func (m *MockQuerier) RedeemInvitationCode(ctx context.Context, id int64) (int64, error) {
+// UpdateInstanceBranding mocks base method.
+func (m *MockQuerier) UpdateInstanceBranding(ctx context.Context, arg UpdateInstanceBrandingParams) (InstanceBranding, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RedeemInvitationCode", ctx, id)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "UpdateInstanceBranding", ctx, arg)
+	ret0, _ := ret[0].(InstanceBranding)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RedeemInvitationCode indicates an expected call of RedeemInvitationCode.
-func (mr *MockQuerierMockRecorder) RedeemInvitationCode(ctx, id any) *gomock.Call {
+// UpdateInstanceBranding indicates an expected call of UpdateInstanceBranding.
+func (mr *MockQuerierMockRecorder) UpdateInstanceBranding(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemInvitationCode", reflect.TypeOf((*MockQuerier)(nil).RedeemInvitationCode), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstanceBranding", reflect.TypeOf((*MockQuerier)(nil).UpdateInstanceBranding), ctx, arg)
 }
 
 // UpdatePreferences mocks base method.
@@ -941,6 +2264,34 @@ func (mr *MockQuerierMockRecorder) UpdateRecipeStepImage(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecipeStepImage", reflect.TypeOf((*MockQuerier)(nil).UpdateRecipeStepImage), ctx, arg)
 }
 
+// UpdateStockImageKey mocks base method.
+func (m *MockQuerier) UpdateStockImageKey(ctx context.Context, arg UpdateStockImageKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStockImageKey", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStockImageKey indicates an expected call of UpdateStockImageKey.
+func (mr *MockQuerierMockRecorder) UpdateStockImageKey(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStockImageKey", reflect.TypeOf((*MockQuerier)(nil).UpdateStockImageKey), ctx, arg)
+}
+
+// UpdateUndoTokenImage mocks base method.
+func (m *MockQuerier) UpdateUndoTokenImage(ctx context.Context, arg UpdateUndoTokenImageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUndoTokenImage", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUndoTokenImage indicates an expected call of UpdateUndoTokenImage.
+func (mr *MockQuerierMockRecorder) UpdateUndoTokenImage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUndoTokenImage", reflect.TypeOf((*MockQuerier)(nil).UpdateUndoTokenImage), ctx, arg)
+}
+
 // UpdateUserPasswordHash mocks base method.
 func (m *MockQuerier) UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) error {
 	m.ctrl.T.Helper()
@@ -955,6 +2306,21 @@ func (mr *MockQuerierMockRecorder) UpdateUserPasswordHash(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordHash", reflect.TypeOf((*MockQuerier)(nil).UpdateUserPasswordHash), ctx, arg)
 }
 
+// UpdateUserPreferences mocks base method.
+func (m *MockQuerier) UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) (UpdateUserPreferencesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPreferences", ctx, arg)
+	ret0, _ := ret[0].(UpdateUserPreferencesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPreferences indicates an expected call of UpdateUserPreferences.
+func (mr *MockQuerierMockRecorder) UpdateUserPreferences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPreferences", reflect.TypeOf((*MockQuerier)(nil).UpdateUserPreferences), ctx, arg)
+}
+
 // UpdateUserRefreshTokenHash mocks base method.
 func (m *MockQuerier) UpdateUserRefreshTokenHash(ctx context.Context, arg UpdateUserRefreshTokenHashParams) error {
 	m.ctrl.T.Helper()
@@ -968,3 +2334,33 @@ func (mr *MockQuerierMockRecorder) UpdateUserRefreshTokenHash(ctx, arg any) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRefreshTokenHash", reflect.TypeOf((*MockQuerier)(nil).UpdateUserRefreshTokenHash), ctx, arg)
 }
+
+// UpsertFederatedRecipe mocks base method.
+func (m *MockQuerier) UpsertFederatedRecipe(ctx context.Context, arg UpsertFederatedRecipeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertFederatedRecipe", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertFederatedRecipe indicates an expected call of UpsertFederatedRecipe.
+func (mr *MockQuerierMockRecorder) UpsertFederatedRecipe(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertFederatedRecipe", reflect.TypeOf((*MockQuerier)(nil).UpsertFederatedRecipe), ctx, arg)
+}
+
+// UpsertStockImage mocks base method.
+func (m *MockQuerier) UpsertStockImage(ctx context.Context, arg UpsertStockImageParams) (UpsertStockImageRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertStockImage", ctx, arg)
+	ret0, _ := ret[0].(UpsertStockImageRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertStockImage indicates an expected call of UpsertStockImage.
+func (mr *MockQuerierMockRecorder) UpsertStockImage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertStockImage", reflect.TypeOf((*MockQuerier)(nil).UpsertStockImage), ctx, arg)
+}
@@ -53,6 +53,48 @@ func (ns NullRole) Value() (driver.Value, error) {
 	return string(ns.Role), nil
 }
 
+type TemperatureUnit string
+
+const (
+	TemperatureUnitC TemperatureUnit = "C"
+	TemperatureUnitF TemperatureUnit = "F"
+)
+
+func (e *TemperatureUnit) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemperatureUnit(s)
+	case string:
+		*e = TemperatureUnit(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemperatureUnit: %T", src)
+	}
+	return nil
+}
+
+type NullTemperatureUnit struct {
+	TemperatureUnit TemperatureUnit
+	Valid           bool // Valid is true if TemperatureUnit is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemperatureUnit) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemperatureUnit, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemperatureUnit.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemperatureUnit) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemperatureUnit), nil
+}
+
 type TimeUnit string
 
 const (
@@ -96,6 +138,106 @@ func (ns NullTimeUnit) Value() (driver.Value, error) {
 	return string(ns.TimeUnit), nil
 }
 
+type Appliance struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	Provider  string
+	Endpoint  string
+	Scopes    []string
+	Token     string
+	CreatedAt pgtype.Timestamptz
+}
+
+type AuditEvent struct {
+	ID         int64
+	ActorID    pgtype.Int8
+	Action     string
+	TargetType string
+	TargetID   pgtype.Int8
+	DryRun     bool
+	Metadata   []byte
+	CreatedAt  pgtype.Timestamptz
+}
+
+type Delivery struct {
+	ID            int64
+	Kind          string
+	Recipient     string
+	Subject       string
+	Payload       []byte
+	Status        string
+	Retry         bool
+	Attempts      int32
+	LastError     pgtype.Text
+	NextAttemptAt pgtype.Timestamptz
+	SentAt        pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+	UpdatedAt     pgtype.Timestamptz
+}
+
+type DensityVersion struct {
+	ID        int64
+	Note      string
+	CreatedBy pgtype.Int8
+	CreatedAt pgtype.Timestamptz
+}
+
+type FederatedRecipe struct {
+	ID          int64
+	ObjectID    string
+	Actor       string
+	Author      string
+	Url         string
+	Title       string
+	Description pgtype.Text
+	Ingredients []string
+	Steps       []string
+	ImageUrl    pgtype.Text
+	PublishedAt pgtype.Timestamptz
+	ReceivedAt  pgtype.Timestamptz
+}
+
+type FederationFollower struct {
+	UserID    int64
+	Actor     string
+	Inbox     string
+	CreatedAt pgtype.Timestamptz
+}
+
+type FederationFollowing struct {
+	ID        int64
+	UserID    int64
+	Actor     string
+	Handle    string
+	Name      string
+	Inbox     string
+	Accepted  bool
+	CreatedAt pgtype.Timestamptz
+}
+
+type FederationKey struct {
+	UserID     int64
+	PrivateKey string
+	PublicKey  string
+	CreatedAt  pgtype.Timestamptz
+}
+
+type IngredientDensity struct {
+	VersionID  int64
+	Ingredient string
+	MlPerGram  float64
+}
+
+type InstanceBranding struct {
+	ID           int32
+	Name         string
+	LogoKey      pgtype.Text
+	AccentColor  pgtype.Text
+	ContactEmail pgtype.Text
+	UpdatedAt    pgtype.Timestamptz
+}
+
 type InvitationCode struct {
 	ID        int64
 	CodeHash  string
@@ -136,13 +278,65 @@ type RecipeIngredient struct {
 }
 
 type RecipeStep struct {
-	ID          int64
+	ID               int64
+	RecipeID         int64
+	StepNumber       int32
+	Instruction      pgtype.Text
+	ImageKey         pgtype.Text
+	CreatedAt        pgtype.Timestamptz
+	UpdatedAt        pgtype.Timestamptz
+	TemperatureValue pgtype.Float4
+	TemperatureUnit  NullTemperatureUnit
+}
+
+type RecipeTag struct {
+	RecipeID  int64
+	Tag       string
+	CreatedAt pgtype.Timestamptz
+}
+
+type RecipeTagSuggestion struct {
+	RecipeID  int64
+	Tag       string
+	Score     int32
+	CreatedAt pgtype.Timestamptz
+}
+
+type RecipeTagSuggestionInput struct {
 	RecipeID    int64
-	StepNumber  int32
-	Instruction pgtype.Text
-	ImageKey    pgtype.Text
-	CreatedAt   pgtype.Timestamptz
-	UpdatedAt   pgtype.Timestamptz
+	Fingerprint string
+	SuggestedAt pgtype.Timestamptz
+}
+
+type StockImage struct {
+	ID         int64
+	Ingredient string
+	ImageKey   string
+	CreatedBy  pgtype.Int8
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+}
+
+type UndoToken struct {
+	Token     string
+	UserID    int64
+	Kind      string
+	RecipeID  int64
+	Snapshot  []byte
+	ImageKey  pgtype.Text
+	ExpiresAt pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type UploadToken struct {
+	ID        string
+	UserID    int64
+	RecipeID  int64
+	Target    string
+	TargetID  int64
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
 }
 
 type User struct {
@@ -156,6 +350,12 @@ type User struct {
 	RefreshTokenExpiresAt pgtype.Timestamptz
 	CreatedAt             pgtype.Timestamptz
 	UpdatedAt             pgtype.Timestamptz
+	TemperatureUnit       NullTemperatureUnit
+	WeeklyReport          bool
+	WeeklyReportSentAt    pgtype.Timestamptz
+	Allergies             []string
+	TimeZone              string
+	WeeklyReportDueAt     pgtype.Timestamptz
 }
 
 type ValidInvitationCode struct {
@@ -11,6 +11,9 @@ import (
 )
 
 type Querier interface {
+	AcceptFederationFollowing(ctx context.Context, arg AcceptFederationFollowingParams) (int64, error)
+	AddFederationFollower(ctx context.Context, arg AddFederationFollowerParams) error
+	AddRecipeTag(ctx context.Context, arg AddRecipeTagParams) error
 	BatchUpdateRecipeIngredientImages(ctx context.Context, arg []BatchUpdateRecipeIngredientImagesParams) *BatchUpdateRecipeIngredientImagesBatchResults
 	BatchUpdateRecipeStepImages(ctx context.Context, arg []BatchUpdateRecipeStepImagesParams) *BatchUpdateRecipeStepImagesBatchResults
 	BulkInsertRecipeIngredients(ctx context.Context, arg []BulkInsertRecipeIngredientsParams) (int64, error)
@@ -21,28 +24,79 @@ type Querier interface {
 	CheckRecipeOwnership(ctx context.Context, arg CheckRecipeOwnershipParams) (bool, error)
 	CheckStepOwnership(ctx context.Context, arg CheckStepOwnershipParams) (bool, error)
 	CheckUsersTableExists(ctx context.Context) (bool, error)
+	ClaimDelivery(ctx context.Context, arg ClaimDeliveryParams) (Delivery, error)
+	ClaimDueDeliveries(ctx context.Context, arg ClaimDueDeliveriesParams) ([]Delivery, error)
+	ConsumeUndoToken(ctx context.Context, arg ConsumeUndoTokenParams) (UndoToken, error)
+	ConsumeUploadToken(ctx context.Context, arg ConsumeUploadTokenParams) (ConsumeUploadTokenRow, error)
 	CreateAdmin(ctx context.Context, arg CreateAdminParams) (int64, error)
+	CreateAppliance(ctx context.Context, arg CreateApplianceParams) (Appliance, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (int64, error)
+	CreateDelivery(ctx context.Context, arg CreateDeliveryParams) (Delivery, error)
 	CreateEmptyRecipeIngredient(ctx context.Context, recipeID int64) (RecipeIngredient, error)
+	CreateFederationFollowing(ctx context.Context, arg CreateFederationFollowingParams) (FederationFollowing, error)
+	CreateFederationKey(ctx context.Context, arg CreateFederationKeyParams) (FederationKey, error)
 	CreateInviteCode(ctx context.Context, arg CreateInviteCodeParams) (int64, error)
 	CreatePreferences(ctx context.Context, id int32) error
 	CreateRecipe(ctx context.Context, arg CreateRecipeParams) (int64, error)
 	CreateRecipeIngredient(ctx context.Context, arg CreateRecipeIngredientParams) (int64, error)
 	CreateRecipeStep(ctx context.Context, arg CreateRecipeStepParams) (CreateRecipeStepRow, error)
+	CreateRecipeTagSuggestion(ctx context.Context, arg CreateRecipeTagSuggestionParams) error
+	CreateUndoToken(ctx context.Context, arg CreateUndoTokenParams) error
+	CreateUploadToken(ctx context.Context, arg CreateUploadTokenParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (int64, error)
+	DeleteAppliance(ctx context.Context, arg DeleteApplianceParams) (int64, error)
+	DeleteExpiredUploadTokens(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error)
+	DeleteFederatedRecipe(ctx context.Context, arg DeleteFederatedRecipeParams) (int64, error)
+	DeleteFederationFollower(ctx context.Context, arg DeleteFederationFollowerParams) (int64, error)
+	DeleteFederationFollowing(ctx context.Context, arg DeleteFederationFollowingParams) (FederationFollowing, error)
 	DeleteRecipe(ctx context.Context, id int64) error
-	DeleteRecipeIngredient(ctx context.Context, id int64) error
+	DeleteRecipeIngredient(ctx context.Context, id int64) (RecipeIngredient, error)
 	DeleteRecipeIngredientImageKey(ctx context.Context, id int64) error
 	DeleteRecipeIngredientsByIDs(ctx context.Context, arg DeleteRecipeIngredientsByIDsParams) error
-	DeleteRecipeStep(ctx context.Context, id int64) error
+	DeleteRecipeStep(ctx context.Context, id int64) (RecipeStep, error)
 	DeleteRecipeStepImageKey(ctx context.Context, id int64) error
 	DeleteRecipeStepsByIDs(ctx context.Context, arg DeleteRecipeStepsByIDsParams) error
+	DeleteRecipeTagSuggestions(ctx context.Context, recipeID int64) error
+	DeleteSentDeliveriesBefore(ctx context.Context, before pgtype.Timestamptz) error
+	DeleteStockImage(ctx context.Context, id int64) (string, error)
+	DeleteUndoToken(ctx context.Context, token string) (int64, error)
+	DeleteUnfollowedFederatedRecipes(ctx context.Context, actor string) error
 	DeleteUser(ctx context.Context, id int64) (int64, error)
+	DeleteUserTag(ctx context.Context, arg DeleteUserTagParams) (int64, error)
 	GetAdminCount(ctx context.Context) (int64, error)
+	GetAllRecipeCoverImageKeys(ctx context.Context) ([]GetAllRecipeCoverImageKeysRow, error)
+	GetAllRecipeIngredientImageKeys(ctx context.Context) ([]GetAllRecipeIngredientImageKeysRow, error)
+	GetAllRecipeStepImageKeys(ctx context.Context) ([]GetAllRecipeStepImageKeysRow, error)
+	GetAllStockImageKeys(ctx context.Context) ([]GetAllStockImageKeysRow, error)
+	GetAllUndoTokenImageKeys(ctx context.Context) ([]GetAllUndoTokenImageKeysRow, error)
 	GetAllowPublicSignupPreference(ctx context.Context, id int32) (bool, error)
+	GetAppliance(ctx context.Context, arg GetApplianceParams) (Appliance, error)
+	GetApplianceByID(ctx context.Context, id int64) (Appliance, error)
+	GetAppliances(ctx context.Context, userID int64) ([]Appliance, error)
+	GetCookingStep(ctx context.Context, arg GetCookingStepParams) (GetCookingStepRow, error)
+	GetDeliveries(ctx context.Context, arg GetDeliveriesParams) ([]Delivery, error)
+	GetDelivery(ctx context.Context, id int64) (Delivery, error)
+	GetDeliveryCount(ctx context.Context, arg GetDeliveryCountParams) (int64, error)
+	GetDeliveryStats(ctx context.Context) ([]GetDeliveryStatsRow, error)
+	GetDensities(ctx context.Context, versionID int64) ([]GetDensitiesRow, error)
+	GetDensityVersion(ctx context.Context, id int64) (DensityVersion, error)
+	GetDensityVersions(ctx context.Context) ([]GetDensityVersionsRow, error)
+	GetExpiredUndoTokens(ctx context.Context, arg GetExpiredUndoTokensParams) ([]GetExpiredUndoTokensRow, error)
+	GetFederatedRecipeCount(ctx context.Context) (int64, error)
+	GetFederatedRecipes(ctx context.Context, arg GetFederatedRecipesParams) ([]FederatedRecipe, error)
+	GetFederationActor(ctx context.Context, id int64) (GetFederationActorRow, error)
+	GetFederationFollowers(ctx context.Context, userID int64) ([]FederationFollower, error)
+	GetFederationFollowing(ctx context.Context, userID int64) ([]FederationFollowing, error)
+	GetFederationKey(ctx context.Context, userID int64) (FederationKey, error)
+	GetInstanceBranding(ctx context.Context, id int32) (InstanceBranding, error)
 	GetInvitationCode(ctx context.Context, id int64) (string, error)
+	GetLatestDensityVersion(ctx context.Context) (DensityVersion, error)
 	GetPreferences(ctx context.Context, id int32) (Preference, error)
-	GetPublicRecipes(ctx context.Context) ([]GetPublicRecipesRow, error)
+	GetPublicRecipeCount(ctx context.Context, allergenPatterns []string) (int64, error)
+	GetPublicRecipes(ctx context.Context, arg GetPublicRecipesParams) ([]GetPublicRecipesRow, error)
 	GetPublishedRecipeAndOwner(ctx context.Context, id int64) (GetPublishedRecipeAndOwnerRow, error)
+	GetPublishedRecipesByOwner(ctx context.Context, arg GetPublishedRecipesByOwnerParams) ([]Recipe, error)
+	GetPublishedRecipesCreatedSince(ctx context.Context, arg GetPublishedRecipesCreatedSinceParams) ([]GetPublishedRecipesCreatedSinceRow, error)
 	GetRecipeAndOwner(ctx context.Context, id int64) (GetRecipeAndOwnerRow, error)
 	GetRecipeImageKey(ctx context.Context, id int64) (pgtype.Text, error)
 	GetRecipeIngredientExistence(ctx context.Context, id int64) (bool, error)
@@ -54,17 +108,52 @@ type Querier interface {
 	GetRecipeStepIDs(ctx context.Context, recipeID int64) ([]int64, error)
 	GetRecipeStepImageKey(ctx context.Context, id int64) (pgtype.Text, error)
 	GetRecipeSteps(ctx context.Context, recipeID int64) ([]RecipeStep, error)
-	GetRecipesByOwner(ctx context.Context, id int64) ([]GetRecipesByOwnerRow, error)
+	GetRecipeTagSuggestions(ctx context.Context, recipeID int64) ([]GetRecipeTagSuggestionsRow, error)
+	GetRecipeTags(ctx context.Context, recipeID int64) ([]string, error)
+	GetRecipesByOwner(ctx context.Context, arg GetRecipesByOwnerParams) ([]GetRecipesByOwnerRow, error)
+	GetStockImage(ctx context.Context, id int64) (StockImage, error)
+	GetStockImages(ctx context.Context) ([]StockImage, error)
+	GetUntaggedRecipes(ctx context.Context, arg GetUntaggedRecipesParams) ([]GetUntaggedRecipesRow, error)
+	GetUploadTokenUser(ctx context.Context, arg GetUploadTokenUserParams) (int64, error)
 	GetUser(ctx context.Context, lower string) (GetUserRow, error)
+	GetUserActivity(ctx context.Context, arg GetUserActivityParams) ([]GetUserActivityRow, error)
+	GetUserActivityCount(ctx context.Context, actorID pgtype.Int8) (int64, error)
+	GetUserAllergies(ctx context.Context, id int64) ([]string, error)
 	GetUserById(ctx context.Context, id int64) (GetUserByIdRow, error)
+	GetUserCount(ctx context.Context) (int64, error)
+	GetUserForExport(ctx context.Context, id int64) (GetUserForExportRow, error)
 	GetUserPasswordHash(ctx context.Context, id int64) (string, error)
+	GetUserPreferences(ctx context.Context, id int64) (GetUserPreferencesRow, error)
+	GetUserRecipeCount(ctx context.Context, userID pgtype.Int8) (int64, error)
+	GetUserRecipeIDSample(ctx context.Context, arg GetUserRecipeIDSampleParams) ([]int64, error)
 	GetUserRecipeImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error)
 	GetUserRecipeIngredientImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error)
 	GetUserRecipeStepImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error)
+	GetUserRecipesCreatedSince(ctx context.Context, arg GetUserRecipesCreatedSinceParams) ([]GetUserRecipesCreatedSinceRow, error)
 	GetUserRefreshTokenHash(ctx context.Context, id int64) (GetUserRefreshTokenHashRow, error)
 	GetUserRole(ctx context.Context, id int64) (Role, error)
+	GetUserTags(ctx context.Context, userID pgtype.Int8) ([]string, error)
+	GetUserTemperatureUnit(ctx context.Context, id int64) (NullTemperatureUnit, error)
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error)
+	GetWeeklyReportRecipients(ctx context.Context, arg GetWeeklyReportRecipientsParams) ([]GetWeeklyReportRecipientsRow, error)
+	ImportDensities(ctx context.Context, arg ImportDensitiesParams) (ImportDensitiesRow, error)
+	IsFederationActorFollowed(ctx context.Context, actor string) (bool, error)
+	MarkDeliveryFailed(ctx context.Context, arg MarkDeliveryFailedParams) error
+	MarkDeliverySent(ctx context.Context, arg MarkDeliverySentParams) error
+	MarkWeeklyReportSent(ctx context.Context, arg MarkWeeklyReportSentParams) error
+	MatchStockImage(ctx context.Context, candidates []string) (StockImage, error)
+	MoveUserTag(ctx context.Context, arg MoveUserTagParams) (MoveUserTagRow, error)
 	RedeemInvitationCode(ctx context.Context, id int64) (int64, error)
+	RenameUserTag(ctx context.Context, arg RenameUserTagParams) (RenameUserTagRow, error)
+	RestoreRecipeCoverImage(ctx context.Context, arg RestoreRecipeCoverImageParams) (int64, error)
+	RestoreRecipeIngredient(ctx context.Context, arg RestoreRecipeIngredientParams) error
+	RestoreRecipeIngredientImage(ctx context.Context, arg RestoreRecipeIngredientImageParams) (int64, error)
+	RestoreRecipeStep(ctx context.Context, arg RestoreRecipeStepParams) error
+	RestoreRecipeStepImage(ctx context.Context, arg RestoreRecipeStepImageParams) (int64, error)
+	SetInstanceLogo(ctx context.Context, arg SetInstanceLogoParams) (pgtype.Text, error)
+	SetRecipeTagSuggestionInputs(ctx context.Context, arg SetRecipeTagSuggestionInputsParams) error
+	UnsubscribeWeeklyReport(ctx context.Context, id int64) (int64, error)
+	UpdateInstanceBranding(ctx context.Context, arg UpdateInstanceBrandingParams) (InstanceBranding, error)
 	UpdatePreferences(ctx context.Context, arg UpdatePreferencesParams) (Preference, error)
 	UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (UpdateRecipeRow, error)
 	UpdateRecipeCoverImage(ctx context.Context, arg UpdateRecipeCoverImageParams) error
@@ -72,8 +161,13 @@ type Querier interface {
 	UpdateRecipeIngredientImage(ctx context.Context, arg UpdateRecipeIngredientImageParams) error
 	UpdateRecipeStep(ctx context.Context, arg UpdateRecipeStepParams) (UpdateRecipeStepRow, error)
 	UpdateRecipeStepImage(ctx context.Context, arg UpdateRecipeStepImageParams) error
+	UpdateStockImageKey(ctx context.Context, arg UpdateStockImageKeyParams) error
+	UpdateUndoTokenImage(ctx context.Context, arg UpdateUndoTokenImageParams) error
 	UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) error
+	UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) (UpdateUserPreferencesRow, error)
 	UpdateUserRefreshTokenHash(ctx context.Context, arg UpdateUserRefreshTokenHashParams) error
+	UpsertFederatedRecipe(ctx context.Context, arg UpsertFederatedRecipeParams) (int64, error)
+	UpsertStockImage(ctx context.Context, arg UpsertStockImageParams) (UpsertStockImageRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
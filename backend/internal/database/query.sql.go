@@ -24,6 +24,65 @@ type BulkInsertRecipeStepsParams struct {
 	StepNumber  int32
 }
 
+const acceptFederationFollowing = `-- name: AcceptFederationFollowing :execrows
+UPDATE
+  federation_following
+SET
+  accepted = TRUE
+WHERE
+  user_id = $1
+  AND actor = $2
+`
+
+type AcceptFederationFollowingParams struct {
+	UserID int64
+	Actor  string
+}
+
+func (q *Queries) AcceptFederationFollowing(ctx context.Context, arg AcceptFederationFollowingParams) (int64, error) {
+	result, err := q.db.Exec(ctx, acceptFederationFollowing, arg.UserID, arg.Actor)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const addFederationFollower = `-- name: AddFederationFollower :exec
+INSERT INTO federation_followers (user_id, actor, inbox)
+  VALUES ($1, $2, $3)
+ON CONFLICT (user_id, actor)
+  DO UPDATE SET
+    inbox = EXCLUDED.inbox
+`
+
+type AddFederationFollowerParams struct {
+	UserID int64
+	Actor  string
+	Inbox  string
+}
+
+func (q *Queries) AddFederationFollower(ctx context.Context, arg AddFederationFollowerParams) error {
+	_, err := q.db.Exec(ctx, addFederationFollower, arg.UserID, arg.Actor, arg.Inbox)
+	return err
+}
+
+const addRecipeTag = `-- name: AddRecipeTag :exec
+INSERT INTO recipe_tags (recipe_id, tag)
+  VALUES ($1, $2)
+ON CONFLICT
+  DO NOTHING
+`
+
+type AddRecipeTagParams struct {
+	RecipeID int64
+	Tag      string
+}
+
+func (q *Queries) AddRecipeTag(ctx context.Context, arg AddRecipeTagParams) error {
+	_, err := q.db.Exec(ctx, addRecipeTag, arg.RecipeID, arg.Tag)
+	return err
+}
+
 const checkIngredientOwnership = `-- name: CheckIngredientOwnership :one
 SELECT
   EXISTS (
@@ -110,7 +169,7 @@ SELECT
     FROM
       information_schema.tables
     WHERE
-      table_schema = 'public'
+      table_schema = current_schema()
       AND table_name = 'users')
 `
 
@@ -121,6 +180,180 @@ func (q *Queries) CheckUsersTableExists(ctx context.Context) (bool, error) {
 	return exists, err
 }
 
+const claimDelivery = `-- name: ClaimDelivery :one
+UPDATE
+  deliveries
+SET
+  status = 'queued',
+  updated_at = $2
+WHERE
+  id = $1
+  AND status IN ('failed', 'dead')
+RETURNING
+  id, kind, recipient, subject, payload, status, retry, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at
+`
+
+type ClaimDeliveryParams struct {
+	ID  int64
+	Now pgtype.Timestamptz
+}
+
+func (q *Queries) ClaimDelivery(ctx context.Context, arg ClaimDeliveryParams) (Delivery, error) {
+	row := q.db.QueryRow(ctx, claimDelivery, arg.ID, arg.Now)
+	var i Delivery
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Recipient,
+		&i.Subject,
+		&i.Payload,
+		&i.Status,
+		&i.Retry,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.SentAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const claimDueDeliveries = `-- name: ClaimDueDeliveries :many
+UPDATE
+  deliveries
+SET
+  status = 'queued',
+  updated_at = $1
+WHERE
+  id IN (
+    SELECT
+      id
+    FROM
+      deliveries
+    WHERE
+      status = 'failed'
+      AND retry
+      AND next_attempt_at <= $1
+    ORDER BY
+      next_attempt_at
+    LIMIT $2
+    FOR UPDATE
+      SKIP LOCKED)
+RETURNING
+  id, kind, recipient, subject, payload, status, retry, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at
+`
+
+type ClaimDueDeliveriesParams struct {
+	Now       pgtype.Timestamptz
+	BatchSize int32
+}
+
+func (q *Queries) ClaimDueDeliveries(ctx context.Context, arg ClaimDueDeliveriesParams) ([]Delivery, error) {
+	rows, err := q.db.Query(ctx, claimDueDeliveries, arg.Now, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Delivery
+	for rows.Next() {
+		var i Delivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.Recipient,
+			&i.Subject,
+			&i.Payload,
+			&i.Status,
+			&i.Retry,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const consumeUndoToken = `-- name: ConsumeUndoToken :one
+DELETE FROM undo_tokens
+WHERE token = $1
+  AND user_id = $2
+  AND expires_at > $3
+RETURNING
+  token, user_id, kind, recipe_id, snapshot, image_key, expires_at, created_at
+`
+
+type ConsumeUndoTokenParams struct {
+	Token  string
+	UserID int64
+	Now    pgtype.Timestamptz
+}
+
+func (q *Queries) ConsumeUndoToken(ctx context.Context, arg ConsumeUndoTokenParams) (UndoToken, error) {
+	row := q.db.QueryRow(ctx, consumeUndoToken, arg.Token, arg.UserID, arg.Now)
+	var i UndoToken
+	err := row.Scan(
+		&i.Token,
+		&i.UserID,
+		&i.Kind,
+		&i.RecipeID,
+		&i.Snapshot,
+		&i.ImageKey,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const consumeUploadToken = `-- name: ConsumeUploadToken :one
+UPDATE
+  upload_tokens
+SET
+  used_at = $2
+WHERE
+  id = $1
+  AND used_at IS NULL
+  AND expires_at > $2
+RETURNING
+  user_id,
+  recipe_id,
+  target,
+  target_id
+`
+
+type ConsumeUploadTokenParams struct {
+	ID     string
+	UsedAt pgtype.Timestamptz
+}
+
+type ConsumeUploadTokenRow struct {
+	UserID   int64
+	RecipeID int64
+	Target   string
+	TargetID int64
+}
+
+func (q *Queries) ConsumeUploadToken(ctx context.Context, arg ConsumeUploadTokenParams) (ConsumeUploadTokenRow, error) {
+	row := q.db.QueryRow(ctx, consumeUploadToken, arg.ID, arg.UsedAt)
+	var i ConsumeUploadTokenRow
+	err := row.Scan(
+		&i.UserID,
+		&i.RecipeID,
+		&i.Target,
+		&i.TargetID,
+	)
+	return i, err
+}
+
 const createAdmin = `-- name: CreateAdmin :one
 INSERT INTO users (email, first_name, last_name, password_hash, role)
   VALUES (trim(lower($4::text)), $1, $2, $3, 'admin')
@@ -147,6 +380,119 @@ func (q *Queries) CreateAdmin(ctx context.Context, arg CreateAdminParams) (int64
 	return id, err
 }
 
+const createAppliance = `-- name: CreateAppliance :one
+INSERT INTO appliances (user_id, name, provider, endpoint, scopes, token)
+  VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING
+  id, user_id, name, provider, endpoint, scopes, token, created_at
+`
+
+type CreateApplianceParams struct {
+	UserID   int64
+	Name     string
+	Provider string
+	Endpoint string
+	Scopes   []string
+	Token    string
+}
+
+func (q *Queries) CreateAppliance(ctx context.Context, arg CreateApplianceParams) (Appliance, error) {
+	row := q.db.QueryRow(ctx, createAppliance,
+		arg.UserID,
+		arg.Name,
+		arg.Provider,
+		arg.Endpoint,
+		arg.Scopes,
+		arg.Token,
+	)
+	var i Appliance
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Provider,
+		&i.Endpoint,
+		&i.Scopes,
+		&i.Token,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createAuditEvent = `-- name: CreateAuditEvent :one
+INSERT INTO audit_events (actor_id, action, target_type, target_id, dry_run, metadata)
+  VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING
+  id
+`
+
+type CreateAuditEventParams struct {
+	ActorID    pgtype.Int8
+	Action     string
+	TargetType string
+	TargetID   pgtype.Int8
+	DryRun     bool
+	Metadata   []byte
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (int64, error) {
+	row := q.db.QueryRow(ctx, createAuditEvent,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.DryRun,
+		arg.Metadata,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createDelivery = `-- name: CreateDelivery :one
+INSERT INTO deliveries (kind, recipient, subject, payload, retry, created_at, updated_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $6)
+RETURNING
+  id, kind, recipient, subject, payload, status, retry, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at
+`
+
+type CreateDeliveryParams struct {
+	Kind      string
+	Recipient string
+	Subject   string
+	Payload   []byte
+	Retry     bool
+	Now       pgtype.Timestamptz
+}
+
+func (q *Queries) CreateDelivery(ctx context.Context, arg CreateDeliveryParams) (Delivery, error) {
+	row := q.db.QueryRow(ctx, createDelivery,
+		arg.Kind,
+		arg.Recipient,
+		arg.Subject,
+		arg.Payload,
+		arg.Retry,
+		arg.Now,
+	)
+	var i Delivery
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Recipient,
+		&i.Subject,
+		&i.Payload,
+		&i.Status,
+		&i.Retry,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.SentAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const createEmptyRecipeIngredient = `-- name: CreateEmptyRecipeIngredient :one
 INSERT INTO recipe_ingredients (recipe_id)
   VALUES ($1)
@@ -168,6 +514,76 @@ func (q *Queries) CreateEmptyRecipeIngredient(ctx context.Context, recipeID int6
 	return i, err
 }
 
+const createFederationFollowing = `-- name: CreateFederationFollowing :one
+INSERT INTO federation_following (user_id, actor, handle, name, inbox)
+  VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, actor)
+  DO UPDATE SET
+    handle = EXCLUDED.handle,
+    name = EXCLUDED.name,
+    inbox = EXCLUDED.inbox
+  RETURNING
+    id, user_id, actor, handle, name, inbox, accepted, created_at
+`
+
+type CreateFederationFollowingParams struct {
+	UserID int64
+	Actor  string
+	Handle string
+	Name   string
+	Inbox  string
+}
+
+func (q *Queries) CreateFederationFollowing(ctx context.Context, arg CreateFederationFollowingParams) (FederationFollowing, error) {
+	row := q.db.QueryRow(ctx, createFederationFollowing,
+		arg.UserID,
+		arg.Actor,
+		arg.Handle,
+		arg.Name,
+		arg.Inbox,
+	)
+	var i FederationFollowing
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Actor,
+		&i.Handle,
+		&i.Name,
+		&i.Inbox,
+		&i.Accepted,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createFederationKey = `-- name: CreateFederationKey :one
+INSERT INTO federation_keys (user_id, private_key, public_key)
+  VALUES ($1, $2, $3)
+ON CONFLICT (user_id)
+  DO UPDATE SET
+    user_id = EXCLUDED.user_id
+  RETURNING
+    user_id, private_key, public_key, created_at
+`
+
+type CreateFederationKeyParams struct {
+	UserID     int64
+	PrivateKey string
+	PublicKey  string
+}
+
+func (q *Queries) CreateFederationKey(ctx context.Context, arg CreateFederationKeyParams) (FederationKey, error) {
+	row := q.db.QueryRow(ctx, createFederationKey, arg.UserID, arg.PrivateKey, arg.PublicKey)
+	var i FederationKey
+	err := row.Scan(
+		&i.UserID,
+		&i.PrivateKey,
+		&i.PublicKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createInviteCode = `-- name: CreateInviteCode :one
 INSERT INTO invitation_codes (code_hash, invited_by)
   VALUES ($1, $2)
@@ -262,6 +678,76 @@ func (q *Queries) CreateRecipeStep(ctx context.Context, arg CreateRecipeStepPara
 	return i, err
 }
 
+const createRecipeTagSuggestion = `-- name: CreateRecipeTagSuggestion :exec
+INSERT INTO recipe_tag_suggestions (recipe_id, tag, score)
+  VALUES ($1, $2, $3)
+`
+
+type CreateRecipeTagSuggestionParams struct {
+	RecipeID int64
+	Tag      string
+	Score    int32
+}
+
+func (q *Queries) CreateRecipeTagSuggestion(ctx context.Context, arg CreateRecipeTagSuggestionParams) error {
+	_, err := q.db.Exec(ctx, createRecipeTagSuggestion, arg.RecipeID, arg.Tag, arg.Score)
+	return err
+}
+
+const createUndoToken = `-- name: CreateUndoToken :exec
+INSERT INTO undo_tokens (token, user_id, kind, recipe_id, snapshot, image_key, expires_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreateUndoTokenParams struct {
+	Token     string
+	UserID    int64
+	Kind      string
+	RecipeID  int64
+	Snapshot  []byte
+	ImageKey  pgtype.Text
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateUndoToken(ctx context.Context, arg CreateUndoTokenParams) error {
+	_, err := q.db.Exec(ctx, createUndoToken,
+		arg.Token,
+		arg.UserID,
+		arg.Kind,
+		arg.RecipeID,
+		arg.Snapshot,
+		arg.ImageKey,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createUploadToken = `-- name: CreateUploadToken :exec
+INSERT INTO upload_tokens (id, user_id, recipe_id, target, target_id, expires_at)
+  VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateUploadTokenParams struct {
+	ID        string
+	UserID    int64
+	RecipeID  int64
+	Target    string
+	TargetID  int64
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateUploadToken(ctx context.Context, arg CreateUploadTokenParams) error {
+	_, err := q.db.Exec(ctx, createUploadToken,
+		arg.ID,
+		arg.UserID,
+		arg.RecipeID,
+		arg.Target,
+		arg.TargetID,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (email, first_name, last_name, password_hash, role)
   VALUES (trim(lower($4::text)), $1, $2, $3, 'user')
@@ -288,70 +774,194 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (int64,
 	return id, err
 }
 
-const deleteRecipe = `-- name: DeleteRecipe :exec
-DELETE FROM recipes
+const deleteAppliance = `-- name: DeleteAppliance :execrows
+DELETE FROM appliances
 WHERE id = $1
+  AND user_id = $2
 `
 
-func (q *Queries) DeleteRecipe(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteRecipe, id)
-	return err
+type DeleteApplianceParams struct {
+	ID     int64
+	UserID int64
 }
 
-const deleteRecipeIngredient = `-- name: DeleteRecipeIngredient :exec
-DELETE FROM recipe_ingredients
-WHERE id = $1
-`
-
-func (q *Queries) DeleteRecipeIngredient(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteRecipeIngredient, id)
-	return err
+func (q *Queries) DeleteAppliance(ctx context.Context, arg DeleteApplianceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAppliance, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const deleteRecipeIngredientImageKey = `-- name: DeleteRecipeIngredientImageKey :exec
-UPDATE
-  recipe_ingredients
-SET
-  image_key = NULL
-WHERE
-  id = $1
+const deleteExpiredUploadTokens = `-- name: DeleteExpiredUploadTokens :execrows
+DELETE FROM upload_tokens
+WHERE expires_at <= $1
 `
 
-func (q *Queries) DeleteRecipeIngredientImageKey(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteRecipeIngredientImageKey, id)
-	return err
+func (q *Queries) DeleteExpiredUploadTokens(ctx context.Context, expiresAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredUploadTokens, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const deleteRecipeIngredientsByIDs = `-- name: DeleteRecipeIngredientsByIDs :exec
-DELETE FROM recipe_ingredients
-WHERE recipe_id = $1
-  AND id = ANY ($2::bigint[])
+const deleteFederatedRecipe = `-- name: DeleteFederatedRecipe :execrows
+DELETE FROM federated_recipes
+WHERE object_id = $1
+  AND actor = $2
 `
 
-type DeleteRecipeIngredientsByIDsParams struct {
-	RecipeID int64
-	Ids      []int64
+type DeleteFederatedRecipeParams struct {
+	ObjectID string
+	Actor    string
 }
 
-func (q *Queries) DeleteRecipeIngredientsByIDs(ctx context.Context, arg DeleteRecipeIngredientsByIDsParams) error {
-	_, err := q.db.Exec(ctx, deleteRecipeIngredientsByIDs, arg.RecipeID, arg.Ids)
-	return err
+func (q *Queries) DeleteFederatedRecipe(ctx context.Context, arg DeleteFederatedRecipeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFederatedRecipe, arg.ObjectID, arg.Actor)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const deleteRecipeStep = `-- name: DeleteRecipeStep :exec
-DELETE FROM recipe_steps
-WHERE id = $1
+const deleteFederationFollower = `-- name: DeleteFederationFollower :execrows
+DELETE FROM federation_followers
+WHERE user_id = $1
+  AND actor = $2
 `
 
-func (q *Queries) DeleteRecipeStep(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteRecipeStep, id)
-	return err
+type DeleteFederationFollowerParams struct {
+	UserID int64
+	Actor  string
 }
 
-const deleteRecipeStepImageKey = `-- name: DeleteRecipeStepImageKey :exec
-UPDATE
-  recipe_steps
-SET
+func (q *Queries) DeleteFederationFollower(ctx context.Context, arg DeleteFederationFollowerParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFederationFollower, arg.UserID, arg.Actor)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteFederationFollowing = `-- name: DeleteFederationFollowing :one
+DELETE FROM federation_following
+WHERE id = $1
+  AND user_id = $2
+RETURNING
+  id, user_id, actor, handle, name, inbox, accepted, created_at
+`
+
+type DeleteFederationFollowingParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) DeleteFederationFollowing(ctx context.Context, arg DeleteFederationFollowingParams) (FederationFollowing, error) {
+	row := q.db.QueryRow(ctx, deleteFederationFollowing, arg.ID, arg.UserID)
+	var i FederationFollowing
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Actor,
+		&i.Handle,
+		&i.Name,
+		&i.Inbox,
+		&i.Accepted,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteRecipe = `-- name: DeleteRecipe :exec
+DELETE FROM recipes
+WHERE id = $1
+`
+
+func (q *Queries) DeleteRecipe(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteRecipe, id)
+	return err
+}
+
+const deleteRecipeIngredient = `-- name: DeleteRecipeIngredient :one
+DELETE FROM recipe_ingredients
+WHERE id = $1
+RETURNING
+  id, recipe_id, description, image_key, created_at, updated_at
+`
+
+func (q *Queries) DeleteRecipeIngredient(ctx context.Context, id int64) (RecipeIngredient, error) {
+	row := q.db.QueryRow(ctx, deleteRecipeIngredient, id)
+	var i RecipeIngredient
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.Description,
+		&i.ImageKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteRecipeIngredientImageKey = `-- name: DeleteRecipeIngredientImageKey :exec
+UPDATE
+  recipe_ingredients
+SET
+  image_key = NULL
+WHERE
+  id = $1
+`
+
+func (q *Queries) DeleteRecipeIngredientImageKey(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteRecipeIngredientImageKey, id)
+	return err
+}
+
+const deleteRecipeIngredientsByIDs = `-- name: DeleteRecipeIngredientsByIDs :exec
+DELETE FROM recipe_ingredients
+WHERE recipe_id = $1
+  AND id = ANY ($2::bigint[])
+`
+
+type DeleteRecipeIngredientsByIDsParams struct {
+	RecipeID int64
+	Ids      []int64
+}
+
+func (q *Queries) DeleteRecipeIngredientsByIDs(ctx context.Context, arg DeleteRecipeIngredientsByIDsParams) error {
+	_, err := q.db.Exec(ctx, deleteRecipeIngredientsByIDs, arg.RecipeID, arg.Ids)
+	return err
+}
+
+const deleteRecipeStep = `-- name: DeleteRecipeStep :one
+DELETE FROM recipe_steps
+WHERE id = $1
+RETURNING
+  id, recipe_id, step_number, instruction, image_key, created_at, updated_at, temperature_value, temperature_unit
+`
+
+func (q *Queries) DeleteRecipeStep(ctx context.Context, id int64) (RecipeStep, error) {
+	row := q.db.QueryRow(ctx, deleteRecipeStep, id)
+	var i RecipeStep
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.StepNumber,
+		&i.Instruction,
+		&i.ImageKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TemperatureValue,
+		&i.TemperatureUnit,
+	)
+	return i, err
+}
+
+const deleteRecipeStepImageKey = `-- name: DeleteRecipeStepImageKey :exec
+UPDATE
+  recipe_steps
+SET
   image_key = NULL
 WHERE
   id = $1
@@ -378,6 +988,71 @@ func (q *Queries) DeleteRecipeStepsByIDs(ctx context.Context, arg DeleteRecipeSt
 	return err
 }
 
+const deleteRecipeTagSuggestions = `-- name: DeleteRecipeTagSuggestions :exec
+DELETE FROM recipe_tag_suggestions
+WHERE recipe_id = $1
+`
+
+func (q *Queries) DeleteRecipeTagSuggestions(ctx context.Context, recipeID int64) error {
+	_, err := q.db.Exec(ctx, deleteRecipeTagSuggestions, recipeID)
+	return err
+}
+
+const deleteSentDeliveriesBefore = `-- name: DeleteSentDeliveriesBefore :exec
+DELETE FROM deliveries
+WHERE status = 'sent'
+  AND sent_at < $1
+`
+
+func (q *Queries) DeleteSentDeliveriesBefore(ctx context.Context, before pgtype.Timestamptz) error {
+	_, err := q.db.Exec(ctx, deleteSentDeliveriesBefore, before)
+	return err
+}
+
+const deleteStockImage = `-- name: DeleteStockImage :one
+DELETE FROM stock_images
+WHERE id = $1
+RETURNING
+  image_key
+`
+
+func (q *Queries) DeleteStockImage(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRow(ctx, deleteStockImage, id)
+	var image_key string
+	err := row.Scan(&image_key)
+	return image_key, err
+}
+
+const deleteUndoToken = `-- name: DeleteUndoToken :execrows
+DELETE FROM undo_tokens
+WHERE token = $1
+`
+
+func (q *Queries) DeleteUndoToken(ctx context.Context, token string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUndoToken, token)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUnfollowedFederatedRecipes = `-- name: DeleteUnfollowedFederatedRecipes :exec
+DELETE FROM federated_recipes
+WHERE actor = $1
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      federation_following
+    WHERE
+      federation_following.actor = federated_recipes.actor)
+`
+
+func (q *Queries) DeleteUnfollowedFederatedRecipes(ctx context.Context, actor string) error {
+	_, err := q.db.Exec(ctx, deleteUnfollowedFederatedRecipes, actor)
+	return err
+}
+
 const deleteUser = `-- name: DeleteUser :execrows
 DELETE FROM users
 WHERE id = $1
@@ -391,141 +1066,75 @@ func (q *Queries) DeleteUser(ctx context.Context, id int64) (int64, error) {
 	return result.RowsAffected(), nil
 }
 
-const getAdminCount = `-- name: GetAdminCount :one
+const deleteUserTag = `-- name: DeleteUserTag :one
+WITH deleted AS (
+  DELETE FROM recipe_tags rt USING recipes r
+  WHERE r.id = rt.recipe_id
+    AND r.user_id = $1
+    AND rt.tag = $2
+  RETURNING
+    rt.recipe_id)
 SELECT
   count(*)
 FROM
-  users
-WHERE
-  ROLE = 'admin'
+  deleted
 `
 
-func (q *Queries) GetAdminCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, getAdminCount)
+type DeleteUserTagParams struct {
+	UserID pgtype.Int8
+	Tag    string
+}
+
+func (q *Queries) DeleteUserTag(ctx context.Context, arg DeleteUserTagParams) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteUserTag, arg.UserID, arg.Tag)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
-const getAllowPublicSignupPreference = `-- name: GetAllowPublicSignupPreference :one
-SELECT
-  allow_public_signup
-FROM
-  preferences
-WHERE
-  id = $1
-`
-
-func (q *Queries) GetAllowPublicSignupPreference(ctx context.Context, id int32) (bool, error) {
-	row := q.db.QueryRow(ctx, getAllowPublicSignupPreference, id)
-	var allow_public_signup bool
-	err := row.Scan(&allow_public_signup)
-	return allow_public_signup, err
-}
-
-const getInvitationCode = `-- name: GetInvitationCode :one
+const getAdminCount = `-- name: GetAdminCount :one
 SELECT
-  code_hash
+  count(*)
 FROM
-  valid_invitation_codes
+  users
 WHERE
-  id = $1
+  ROLE = 'admin'
 `
 
-func (q *Queries) GetInvitationCode(ctx context.Context, id int64) (string, error) {
-	row := q.db.QueryRow(ctx, getInvitationCode, id)
-	var code_hash string
-	err := row.Scan(&code_hash)
-	return code_hash, err
+func (q *Queries) GetAdminCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getAdminCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-const getPreferences = `-- name: GetPreferences :one
+const getAllRecipeCoverImageKeys = `-- name: GetAllRecipeCoverImageKeys :many
 SELECT
   id,
-  allow_public_signup
-FROM
-  preferences
-WHERE
-  id = $1
-`
-
-func (q *Queries) GetPreferences(ctx context.Context, id int32) (Preference, error) {
-	row := q.db.QueryRow(ctx, getPreferences, id)
-	var i Preference
-	err := row.Scan(&i.ID, &i.AllowPublicSignup)
-	return i, err
-}
-
-const getPublicRecipes = `-- name: GetPublicRecipes :many
-SELECT
-  r.user_id,
-  r.image_key,
-  r.title,
-  r.description,
-  r.created_at,
-  r.updated_at,
-  r.published,
-  r.cook_time_amount,
-  r.cook_time_unit,
-  r.prep_time_amount,
-  r.prep_time_unit,
-  r.id AS recipe_id,
-  r.servings,
-  u.first_name,
-  u.last_name
+  image_key
 FROM
-  recipes r
-  JOIN users u ON r.user_id = u.id
+  recipes
 WHERE
-  r.published = TRUE
+  image_key IS NOT NULL
 ORDER BY
-  r.updated_at DESC
+  id;
 `
 
-type GetPublicRecipesRow struct {
-	UserID         pgtype.Int8
-	ImageKey       pgtype.Text
-	Title          string
-	Description    pgtype.Text
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
-	Published      bool
-	CookTimeAmount pgtype.Int4
-	CookTimeUnit   NullTimeUnit
-	PrepTimeAmount pgtype.Int4
-	PrepTimeUnit   NullTimeUnit
-	RecipeID       int64
-	Servings       pgtype.Float4
-	FirstName      string
-	LastName       string
+type GetAllRecipeCoverImageKeysRow struct {
+	ID       int64
+	ImageKey pgtype.Text
 }
 
-func (q *Queries) GetPublicRecipes(ctx context.Context) ([]GetPublicRecipesRow, error) {
-	rows, err := q.db.Query(ctx, getPublicRecipes)
+func (q *Queries) GetAllRecipeCoverImageKeys(ctx context.Context) ([]GetAllRecipeCoverImageKeysRow, error) {
+	rows, err := q.db.Query(ctx, getAllRecipeCoverImageKeys)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetPublicRecipesRow
+	var items []GetAllRecipeCoverImageKeysRow
 	for rows.Next() {
-		var i GetPublicRecipesRow
-		if err := rows.Scan(
-			&i.UserID,
-			&i.ImageKey,
-			&i.Title,
-			&i.Description,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Published,
-			&i.CookTimeAmount,
-			&i.CookTimeUnit,
-			&i.PrepTimeAmount,
-			&i.PrepTimeUnit,
-			&i.RecipeID,
-			&i.Servings,
-			&i.FirstName,
-			&i.LastName,
-		); err != nil {
+		var i GetAllRecipeCoverImageKeysRow
+		if err := rows.Scan(&i.ID, &i.ImageKey); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -536,199 +1145,108 @@ func (q *Queries) GetPublicRecipes(ctx context.Context) ([]GetPublicRecipesRow,
 	return items, nil
 }
 
-const getPublishedRecipeAndOwner = `-- name: GetPublishedRecipeAndOwner :one
+const getAllRecipeIngredientImageKeys = `-- name: GetAllRecipeIngredientImageKeys :many
 SELECT
-  r.user_id,
-  r.image_key,
-  r.title,
-  r.description,
-  r.created_at,
-  r.updated_at,
-  r.published,
-  r.id,
-  r.servings,
-  r.cook_time_amount,
-  r.cook_time_unit,
-  r.prep_time_amount,
-  r.prep_time_unit,
-  u.first_name,
-  u.last_name,
-  u.id
+  id,
+  image_key
 FROM
-  recipes r
-  JOIN users u ON r.user_id = u.id
+  recipe_ingredients
 WHERE
-  r.id = $1
-  AND published = TRUE
+  image_key IS NOT NULL
+ORDER BY
+  id;
 `
 
-type GetPublishedRecipeAndOwnerRow struct {
-	UserID         pgtype.Int8
-	ImageKey       pgtype.Text
-	Title          string
-	Description    pgtype.Text
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
-	Published      bool
-	ID             int64
-	Servings       pgtype.Float4
-	CookTimeAmount pgtype.Int4
-	CookTimeUnit   NullTimeUnit
-	PrepTimeAmount pgtype.Int4
-	PrepTimeUnit   NullTimeUnit
-	FirstName      string
-	LastName       string
-	ID_2           int64
+type GetAllRecipeIngredientImageKeysRow struct {
+	ID       int64
+	ImageKey pgtype.Text
 }
 
-func (q *Queries) GetPublishedRecipeAndOwner(ctx context.Context, id int64) (GetPublishedRecipeAndOwnerRow, error) {
-	row := q.db.QueryRow(ctx, getPublishedRecipeAndOwner, id)
-	var i GetPublishedRecipeAndOwnerRow
-	err := row.Scan(
-		&i.UserID,
-		&i.ImageKey,
-		&i.Title,
-		&i.Description,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Published,
-		&i.ID,
-		&i.Servings,
-		&i.CookTimeAmount,
-		&i.CookTimeUnit,
-		&i.PrepTimeAmount,
-		&i.PrepTimeUnit,
-		&i.FirstName,
-		&i.LastName,
-		&i.ID_2,
-	)
-	return i, err
+func (q *Queries) GetAllRecipeIngredientImageKeys(ctx context.Context) ([]GetAllRecipeIngredientImageKeysRow, error) {
+	rows, err := q.db.Query(ctx, getAllRecipeIngredientImageKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllRecipeIngredientImageKeysRow
+	for rows.Next() {
+		var i GetAllRecipeIngredientImageKeysRow
+		if err := rows.Scan(&i.ID, &i.ImageKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getRecipeAndOwner = `-- name: GetRecipeAndOwner :one
+const getAllRecipeStepImageKeys = `-- name: GetAllRecipeStepImageKeys :many
 SELECT
-  r.user_id,
-  r.image_key,
-  r.title,
-  r.description,
-  r.created_at,
-  r.updated_at,
-  r.published,
-  r.id,
-  r.cook_time_amount,
-  r.cook_time_unit,
-  r.prep_time_amount,
-  r.prep_time_unit,
-  r.servings,
-  u.first_name,
-  u.last_name,
-  u.id
+  id,
+  image_key
 FROM
-  recipes r
-  JOIN users u ON r.user_id = u.id
+  recipe_steps
 WHERE
-  r.id = $1
+  image_key IS NOT NULL
+ORDER BY
+  id;
 `
 
-type GetRecipeAndOwnerRow struct {
-	UserID         pgtype.Int8
-	ImageKey       pgtype.Text
-	Title          string
-	Description    pgtype.Text
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
-	Published      bool
-	ID             int64
-	CookTimeAmount pgtype.Int4
-	CookTimeUnit   NullTimeUnit
-	PrepTimeAmount pgtype.Int4
-	PrepTimeUnit   NullTimeUnit
-	Servings       pgtype.Float4
-	FirstName      string
-	LastName       string
-	ID_2           int64
+type GetAllRecipeStepImageKeysRow struct {
+	ID       int64
+	ImageKey pgtype.Text
 }
 
-func (q *Queries) GetRecipeAndOwner(ctx context.Context, id int64) (GetRecipeAndOwnerRow, error) {
-	row := q.db.QueryRow(ctx, getRecipeAndOwner, id)
-	var i GetRecipeAndOwnerRow
-	err := row.Scan(
-		&i.UserID,
-		&i.ImageKey,
-		&i.Title,
-		&i.Description,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Published,
-		&i.ID,
-		&i.CookTimeAmount,
-		&i.CookTimeUnit,
-		&i.PrepTimeAmount,
-		&i.PrepTimeUnit,
-		&i.Servings,
-		&i.FirstName,
-		&i.LastName,
-		&i.ID_2,
-	)
-	return i, err
+func (q *Queries) GetAllRecipeStepImageKeys(ctx context.Context) ([]GetAllRecipeStepImageKeysRow, error) {
+	rows, err := q.db.Query(ctx, getAllRecipeStepImageKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllRecipeStepImageKeysRow
+	for rows.Next() {
+		var i GetAllRecipeStepImageKeysRow
+		if err := rows.Scan(&i.ID, &i.ImageKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getRecipeImageKey = `-- name: GetRecipeImageKey :one
+const getAllStockImageKeys = `-- name: GetAllStockImageKeys :many
 SELECT
+  id,
   image_key
 FROM
-  recipes
-WHERE
-  id = $1
-`
-
-func (q *Queries) GetRecipeImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
-	row := q.db.QueryRow(ctx, getRecipeImageKey, id)
-	var image_key pgtype.Text
-	err := row.Scan(&image_key)
-	return image_key, err
-}
-
-const getRecipeIngredientExistence = `-- name: GetRecipeIngredientExistence :one
-SELECT
-  EXISTS (
-    SELECT
-      1
-    FROM
-      recipe_ingredients
-    WHERE
-      id = $1)
+  stock_images
+ORDER BY
+  id
 `
 
-func (q *Queries) GetRecipeIngredientExistence(ctx context.Context, id int64) (bool, error) {
-	row := q.db.QueryRow(ctx, getRecipeIngredientExistence, id)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+type GetAllStockImageKeysRow struct {
+	ID       int64
+	ImageKey string
 }
 
-const getRecipeIngredientIDs = `-- name: GetRecipeIngredientIDs :many
-SELECT
-  id
-FROM
-  recipe_ingredients
-WHERE
-  recipe_id = $1
-`
-
-func (q *Queries) GetRecipeIngredientIDs(ctx context.Context, recipeID int64) ([]int64, error) {
-	rows, err := q.db.Query(ctx, getRecipeIngredientIDs, recipeID)
+func (q *Queries) GetAllStockImageKeys(ctx context.Context) ([]GetAllStockImageKeysRow, error) {
+	rows, err := q.db.Query(ctx, getAllStockImageKeys)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []int64
+	var items []GetAllStockImageKeysRow
 	for rows.Next() {
-		var id int64
-		if err := rows.Scan(&id); err != nil {
+		var i GetAllStockImageKeysRow
+		if err := rows.Scan(&i.ID, &i.ImageKey); err != nil {
 			return nil, err
 		}
-		items = append(items, id)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -736,55 +1254,35 @@ func (q *Queries) GetRecipeIngredientIDs(ctx context.Context, recipeID int64) ([
 	return items, nil
 }
 
-const getRecipeIngredientImageKey = `-- name: GetRecipeIngredientImageKey :one
+const getAllUndoTokenImageKeys = `-- name: GetAllUndoTokenImageKeys :many
 SELECT
+  token,
+  kind,
   image_key
 FROM
-  recipe_ingredients
+  undo_tokens
 WHERE
-  id = $1
+  image_key IS NOT NULL
+ORDER BY
+  token
 `
 
-func (q *Queries) GetRecipeIngredientImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
-	row := q.db.QueryRow(ctx, getRecipeIngredientImageKey, id)
-	var image_key pgtype.Text
-	err := row.Scan(&image_key)
-	return image_key, err
+type GetAllUndoTokenImageKeysRow struct {
+	Token    string
+	Kind     string
+	ImageKey pgtype.Text
 }
 
-const getRecipeIngredients = `-- name: GetRecipeIngredients :many
-SELECT
-  id,
-  recipe_id,
-  description,
-  image_key,
-  created_at,
-  updated_at
-FROM
-  recipe_ingredients
-WHERE
-  recipe_id = $1
-ORDER BY
-  created_at ASC
-`
-
-func (q *Queries) GetRecipeIngredients(ctx context.Context, recipeID int64) ([]RecipeIngredient, error) {
-	rows, err := q.db.Query(ctx, getRecipeIngredients, recipeID)
+func (q *Queries) GetAllUndoTokenImageKeys(ctx context.Context) ([]GetAllUndoTokenImageKeysRow, error) {
+	rows, err := q.db.Query(ctx, getAllUndoTokenImageKeys)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []RecipeIngredient
+	var items []GetAllUndoTokenImageKeysRow
 	for rows.Next() {
-		var i RecipeIngredient
-		if err := rows.Scan(
-			&i.ID,
-			&i.RecipeID,
-			&i.Description,
-			&i.ImageKey,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
+		var i GetAllUndoTokenImageKeysRow
+		if err := rows.Scan(&i.Token, &i.Kind, &i.ImageKey); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -795,62 +1293,112 @@ func (q *Queries) GetRecipeIngredients(ctx context.Context, recipeID int64) ([]R
 	return items, nil
 }
 
-const getRecipeOwner = `-- name: GetRecipeOwner :one
+const getAllowPublicSignupPreference = `-- name: GetAllowPublicSignupPreference :one
 SELECT
-  user_id
+  allow_public_signup
 FROM
-  recipes
+  preferences
 WHERE
   id = $1
 `
 
-func (q *Queries) GetRecipeOwner(ctx context.Context, id int64) (pgtype.Int8, error) {
-	row := q.db.QueryRow(ctx, getRecipeOwner, id)
-	var user_id pgtype.Int8
-	err := row.Scan(&user_id)
-	return user_id, err
+func (q *Queries) GetAllowPublicSignupPreference(ctx context.Context, id int32) (bool, error) {
+	row := q.db.QueryRow(ctx, getAllowPublicSignupPreference, id)
+	var allow_public_signup bool
+	err := row.Scan(&allow_public_signup)
+	return allow_public_signup, err
 }
 
-const getRecipeStepExistence = `-- name: GetRecipeStepExistence :one
+const getAppliance = `-- name: GetAppliance :one
 SELECT
-  EXISTS (
-    SELECT
-      1
-    FROM
-      recipe_steps
-    WHERE
-      id = $1)
+  id, user_id, name, provider, endpoint, scopes, token, created_at
+FROM
+  appliances
+WHERE
+  id = $1
+  AND user_id = $2
 `
 
-func (q *Queries) GetRecipeStepExistence(ctx context.Context, id int64) (bool, error) {
-	row := q.db.QueryRow(ctx, getRecipeStepExistence, id)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+type GetApplianceParams struct {
+	ID     int64
+	UserID int64
 }
 
-const getRecipeStepIDs = `-- name: GetRecipeStepIDs :many
+func (q *Queries) GetAppliance(ctx context.Context, arg GetApplianceParams) (Appliance, error) {
+	row := q.db.QueryRow(ctx, getAppliance, arg.ID, arg.UserID)
+	var i Appliance
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Provider,
+		&i.Endpoint,
+		&i.Scopes,
+		&i.Token,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getApplianceByID = `-- name: GetApplianceByID :one
 SELECT
-  id
+  id, user_id, name, provider, endpoint, scopes, token, created_at
 FROM
-  recipe_steps
+  appliances
 WHERE
-  recipe_id = $1
+  id = $1
 `
 
-func (q *Queries) GetRecipeStepIDs(ctx context.Context, recipeID int64) ([]int64, error) {
-	rows, err := q.db.Query(ctx, getRecipeStepIDs, recipeID)
+func (q *Queries) GetApplianceByID(ctx context.Context, id int64) (Appliance, error) {
+	row := q.db.QueryRow(ctx, getApplianceByID, id)
+	var i Appliance
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Provider,
+		&i.Endpoint,
+		&i.Scopes,
+		&i.Token,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAppliances = `-- name: GetAppliances :many
+SELECT
+  id, user_id, name, provider, endpoint, scopes, token, created_at
+FROM
+  appliances
+WHERE
+  user_id = $1
+ORDER BY
+  created_at ASC,
+  id ASC
+`
+
+func (q *Queries) GetAppliances(ctx context.Context, userID int64) ([]Appliance, error) {
+	rows, err := q.db.Query(ctx, getAppliances, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []int64
+	var items []Appliance
 	for rows.Next() {
-		var id int64
-		if err := rows.Scan(&id); err != nil {
+		var i Appliance
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Provider,
+			&i.Endpoint,
+			&i.Scopes,
+			&i.Token,
+			&i.CreatedAt,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, id)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -858,48 +1406,93 @@ func (q *Queries) GetRecipeStepIDs(ctx context.Context, recipeID int64) ([]int64
 	return items, nil
 }
 
-const getRecipeStepImageKey = `-- name: GetRecipeStepImageKey :one
+const getCookingStep = `-- name: GetCookingStep :one
 SELECT
-  image_key
+  rs.instruction,
+  rs.temperature_value,
+  rs.temperature_unit
 FROM
-  recipe_steps
+  recipe_steps rs
+  JOIN recipes r ON r.id = rs.recipe_id
 WHERE
-  id = $1
+  rs.id = $1
+  AND r.id = $2
+  AND (r.user_id = $3
+    OR r.published)
 `
 
-func (q *Queries) GetRecipeStepImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
-	row := q.db.QueryRow(ctx, getRecipeStepImageKey, id)
-	var image_key pgtype.Text
-	err := row.Scan(&image_key)
-	return image_key, err
+type GetCookingStepParams struct {
+	StepID   int64
+	RecipeID int64
+	UserID   pgtype.Int8
 }
 
-const getRecipeSteps = `-- name: GetRecipeSteps :many
+type GetCookingStepRow struct {
+	Instruction      pgtype.Text
+	TemperatureValue pgtype.Float4
+	TemperatureUnit  NullTemperatureUnit
+}
+
+func (q *Queries) GetCookingStep(ctx context.Context, arg GetCookingStepParams) (GetCookingStepRow, error) {
+	row := q.db.QueryRow(ctx, getCookingStep, arg.StepID, arg.RecipeID, arg.UserID)
+	var i GetCookingStepRow
+	err := row.Scan(
+		&i.Instruction,
+		&i.TemperatureValue,
+		&i.TemperatureUnit,
+	)
+	return i, err
+}
+
+const getDeliveries = `-- name: GetDeliveries :many
 SELECT
-  id, recipe_id, step_number, instruction, image_key, created_at, updated_at
+  id, kind, recipient, subject, payload, status, retry, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at
 FROM
-  recipe_steps
+  deliveries
 WHERE
-  recipe_id = $1
+  id < coalesce($1, 9223372036854775807)
+  AND ($2::text IS NULL
+    OR status = $2)
+  AND ($3::text IS NULL
+    OR kind = $3)
 ORDER BY
-  step_number ASC
+  id DESC
+LIMIT $4
 `
 
-func (q *Queries) GetRecipeSteps(ctx context.Context, recipeID int64) ([]RecipeStep, error) {
-	rows, err := q.db.Query(ctx, getRecipeSteps, recipeID)
+type GetDeliveriesParams struct {
+	Before pgtype.Int8
+	Status pgtype.Text
+	Kind   pgtype.Text
+	Limit  int32
+}
+
+func (q *Queries) GetDeliveries(ctx context.Context, arg GetDeliveriesParams) ([]Delivery, error) {
+	rows, err := q.db.Query(ctx, getDeliveries,
+		arg.Before,
+		arg.Status,
+		arg.Kind,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []RecipeStep
+	var items []Delivery
 	for rows.Next() {
-		var i RecipeStep
+		var i Delivery
 		if err := rows.Scan(
 			&i.ID,
-			&i.RecipeID,
-			&i.StepNumber,
-			&i.Instruction,
-			&i.ImageKey,
+			&i.Kind,
+			&i.Recipient,
+			&i.Subject,
+			&i.Payload,
+			&i.Status,
+			&i.Retry,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.SentAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -913,75 +1506,100 @@ func (q *Queries) GetRecipeSteps(ctx context.Context, recipeID int64) ([]RecipeS
 	return items, nil
 }
 
-const getRecipesByOwner = `-- name: GetRecipesByOwner :many
+const getDelivery = `-- name: GetDelivery :one
 SELECT
-  r.user_id,
-  r.image_key,
-  r.title,
-  r.description,
-  r.created_at,
-  r.updated_at,
-  r.published,
-  r.cook_time_amount,
-  r.cook_time_unit,
-  r.prep_time_amount,
-  r.prep_time_unit,
-  r.id AS recipe_id,
-  r.servings,
-  u.first_name,
-  u.last_name
+  id, kind, recipient, subject, payload, status, retry, attempts, last_error, next_attempt_at, sent_at, created_at, updated_at
 FROM
-  recipes r
-  JOIN users u ON r.user_id = u.id
+  deliveries
 WHERE
-  u.id = $1
+  id = $1
+`
+
+func (q *Queries) GetDelivery(ctx context.Context, id int64) (Delivery, error) {
+	row := q.db.QueryRow(ctx, getDelivery, id)
+	var i Delivery
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Recipient,
+		&i.Subject,
+		&i.Payload,
+		&i.Status,
+		&i.Retry,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.SentAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getDeliveryCount = `-- name: GetDeliveryCount :one
+SELECT
+  count(*)
+FROM
+  deliveries
+WHERE ($1::text IS NULL
+  OR status = $1)
+AND ($2::text IS NULL
+  OR kind = $2)
+`
+
+type GetDeliveryCountParams struct {
+	Status pgtype.Text
+	Kind   pgtype.Text
+}
+
+func (q *Queries) GetDeliveryCount(ctx context.Context, arg GetDeliveryCountParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getDeliveryCount, arg.Status, arg.Kind)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getDeliveryStats = `-- name: GetDeliveryStats :many
+SELECT
+  kind,
+  count(*) FILTER (WHERE status = 'queued') AS queued,
+  count(*) FILTER (WHERE status = 'sent') AS sent,
+  count(*) FILTER (WHERE status = 'failed') AS failed,
+  count(*) FILTER (WHERE status = 'dead') AS dead,
+  count(*) FILTER (WHERE attempts > 1) AS retried
+FROM
+  deliveries
+GROUP BY
+  kind
 ORDER BY
-  r.updated_at DESC
+  kind
 `
 
-type GetRecipesByOwnerRow struct {
-	UserID         pgtype.Int8
-	ImageKey       pgtype.Text
-	Title          string
-	Description    pgtype.Text
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
-	Published      bool
-	CookTimeAmount pgtype.Int4
-	CookTimeUnit   NullTimeUnit
-	PrepTimeAmount pgtype.Int4
-	PrepTimeUnit   NullTimeUnit
-	RecipeID       int64
-	Servings       pgtype.Float4
-	FirstName      string
-	LastName       string
+type GetDeliveryStatsRow struct {
+	Kind    string
+	Queued  int64
+	Sent    int64
+	Failed  int64
+	Dead    int64
+	Retried int64
 }
 
-func (q *Queries) GetRecipesByOwner(ctx context.Context, id int64) ([]GetRecipesByOwnerRow, error) {
-	rows, err := q.db.Query(ctx, getRecipesByOwner, id)
+func (q *Queries) GetDeliveryStats(ctx context.Context) ([]GetDeliveryStatsRow, error) {
+	rows, err := q.db.Query(ctx, getDeliveryStats)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetRecipesByOwnerRow
+	var items []GetDeliveryStatsRow
 	for rows.Next() {
-		var i GetRecipesByOwnerRow
+		var i GetDeliveryStatsRow
 		if err := rows.Scan(
-			&i.UserID,
-			&i.ImageKey,
-			&i.Title,
-			&i.Description,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Published,
-			&i.CookTimeAmount,
-			&i.CookTimeUnit,
-			&i.PrepTimeAmount,
-			&i.PrepTimeUnit,
-			&i.RecipeID,
-			&i.Servings,
-			&i.FirstName,
-			&i.LastName,
+			&i.Kind,
+			&i.Queued,
+			&i.Sent,
+			&i.Failed,
+			&i.Dead,
+			&i.Retried,
 		); err != nil {
 			return nil, err
 		}
@@ -993,103 +1611,110 @@ func (q *Queries) GetRecipesByOwner(ctx context.Context, id int64) ([]GetRecipes
 	return items, nil
 }
 
-const getUser = `-- name: GetUser :one
+const getDensities = `-- name: GetDensities :many
 SELECT
-  id,
-  password_hash,
-  ROLE
+  ingredient,
+  ml_per_gram
 FROM
-  users
+  ingredient_densities
 WHERE
-  email = trim(lower($1))
+  version_id = $1
+ORDER BY
+  ingredient
 `
 
-type GetUserRow struct {
-	ID           int64
-	PasswordHash string
-	Role         Role
+type GetDensitiesRow struct {
+	Ingredient string
+	MlPerGram  float64
 }
 
-func (q *Queries) GetUser(ctx context.Context, lower string) (GetUserRow, error) {
-	row := q.db.QueryRow(ctx, getUser, lower)
-	var i GetUserRow
-	err := row.Scan(&i.ID, &i.PasswordHash, &i.Role)
-	return i, err
+func (q *Queries) GetDensities(ctx context.Context, versionID int64) ([]GetDensitiesRow, error) {
+	rows, err := q.db.Query(ctx, getDensities, versionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDensitiesRow
+	for rows.Next() {
+		var i GetDensitiesRow
+		if err := rows.Scan(&i.Ingredient, &i.MlPerGram); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getUserById = `-- name: GetUserById :one
+const getDensityVersion = `-- name: GetDensityVersion :one
 SELECT
   id,
-  email,
-  first_name,
-  last_name,
-  ROLE
+  note,
+  created_by,
+  created_at
 FROM
-  users
+  density_versions
 WHERE
   id = $1
 `
 
-type GetUserByIdRow struct {
-	ID        int64
-	Email     string
-	FirstName string
-	LastName  string
-	Role      Role
-}
-
-func (q *Queries) GetUserById(ctx context.Context, id int64) (GetUserByIdRow, error) {
-	row := q.db.QueryRow(ctx, getUserById, id)
-	var i GetUserByIdRow
+func (q *Queries) GetDensityVersion(ctx context.Context, id int64) (DensityVersion, error) {
+	row := q.db.QueryRow(ctx, getDensityVersion, id)
+	var i DensityVersion
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.FirstName,
-		&i.LastName,
-		&i.Role,
+		&i.Note,
+		&i.CreatedBy,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
-const getUserPasswordHash = `-- name: GetUserPasswordHash :one
+const getDensityVersions = `-- name: GetDensityVersions :many
 SELECT
-  password_hash
+  dv.id,
+  dv.note,
+  dv.created_by,
+  dv.created_at,
+  count(d.ingredient) AS entries
 FROM
-  users
-WHERE
-  id = $1
+  density_versions dv
+  LEFT JOIN ingredient_densities d ON d.version_id = dv.id
+GROUP BY
+  dv.id
+ORDER BY
+  dv.id DESC
 `
 
-func (q *Queries) GetUserPasswordHash(ctx context.Context, id int64) (string, error) {
-	row := q.db.QueryRow(ctx, getUserPasswordHash, id)
-	var password_hash string
-	err := row.Scan(&password_hash)
-	return password_hash, err
+type GetDensityVersionsRow struct {
+	ID        int64
+	Note      string
+	CreatedBy pgtype.Int8
+	CreatedAt pgtype.Timestamptz
+	Entries   int64
 }
 
-const getUserRecipeImages = `-- name: GetUserRecipeImages :many
-SELECT
-  image_key
-FROM
-  recipes
-WHERE
-  user_id = $1
-  AND image_key IS NOT NULL
-`
-
-func (q *Queries) GetUserRecipeImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
-	rows, err := q.db.Query(ctx, getUserRecipeImages, userID)
+func (q *Queries) GetDensityVersions(ctx context.Context) ([]GetDensityVersionsRow, error) {
+	rows, err := q.db.Query(ctx, getDensityVersions)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []pgtype.Text
+	var items []GetDensityVersionsRow
 	for rows.Next() {
-		var image_key pgtype.Text
-		if err := rows.Scan(&image_key); err != nil {
+		var i GetDensityVersionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Note,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.Entries,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, image_key)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -1097,30 +1722,42 @@ func (q *Queries) GetUserRecipeImages(ctx context.Context, userID pgtype.Int8) (
 	return items, nil
 }
 
-const getUserRecipeIngredientImages = `-- name: GetUserRecipeIngredientImages :many
+const getExpiredUndoTokens = `-- name: GetExpiredUndoTokens :many
 SELECT
-  ri.image_key
+  token,
+  image_key
 FROM
-  recipes r
-  JOIN recipe_ingredients ri ON r.id = ri.recipe_id
+  undo_tokens
 WHERE
-  r.user_id = $1
-  AND ri.image_key IS NOT NULL
+  expires_at <= $1
+ORDER BY
+  expires_at
+LIMIT $2
 `
 
-func (q *Queries) GetUserRecipeIngredientImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
-	rows, err := q.db.Query(ctx, getUserRecipeIngredientImages, userID)
+type GetExpiredUndoTokensParams struct {
+	Now       pgtype.Timestamptz
+	BatchSize int32
+}
+
+type GetExpiredUndoTokensRow struct {
+	Token    string
+	ImageKey pgtype.Text
+}
+
+func (q *Queries) GetExpiredUndoTokens(ctx context.Context, arg GetExpiredUndoTokensParams) ([]GetExpiredUndoTokensRow, error) {
+	rows, err := q.db.Query(ctx, getExpiredUndoTokens, arg.Now, arg.BatchSize)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []pgtype.Text
+	var items []GetExpiredUndoTokensRow
 	for rows.Next() {
-		var image_key pgtype.Text
-		if err := rows.Scan(&image_key); err != nil {
+		var i GetExpiredUndoTokensRow
+		if err := rows.Scan(&i.Token, &i.ImageKey); err != nil {
 			return nil, err
 		}
-		items = append(items, image_key)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -1128,147 +1765,2432 @@ func (q *Queries) GetUserRecipeIngredientImages(ctx context.Context, userID pgty
 	return items, nil
 }
 
-const getUserRecipeStepImages = `-- name: GetUserRecipeStepImages :many
+const getFederatedRecipeCount = `-- name: GetFederatedRecipeCount :one
 SELECT
-  rs.image_key
+  count(*)
+FROM
+  federated_recipes
+`
+
+func (q *Queries) GetFederatedRecipeCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getFederatedRecipeCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getFederatedRecipes = `-- name: GetFederatedRecipes :many
+SELECT
+  id, object_id, actor, author, url, title, description, ingredients, steps, image_url, published_at, received_at
+FROM
+  federated_recipes
+ORDER BY
+  published_at DESC,
+  id DESC
+LIMIT $1 OFFSET $2
+`
+
+type GetFederatedRecipesParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) GetFederatedRecipes(ctx context.Context, arg GetFederatedRecipesParams) ([]FederatedRecipe, error) {
+	rows, err := q.db.Query(ctx, getFederatedRecipes, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FederatedRecipe
+	for rows.Next() {
+		var i FederatedRecipe
+		if err := rows.Scan(
+			&i.ID,
+			&i.ObjectID,
+			&i.Actor,
+			&i.Author,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.Ingredients,
+			&i.Steps,
+			&i.ImageUrl,
+			&i.PublishedAt,
+			&i.ReceivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFederationActor = `-- name: GetFederationActor :one
+SELECT
+  u.id,
+  u.first_name,
+  u.last_name,
+  u.created_at
+FROM
+  users u
+WHERE
+  u.id = $1
+  AND EXISTS (
+    SELECT
+      1
+    FROM
+      recipes r
+    WHERE
+      r.user_id = u.id
+      AND r.published = TRUE)
+`
+
+type GetFederationActorRow struct {
+	ID        int64
+	FirstName string
+	LastName  string
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetFederationActor(ctx context.Context, id int64) (GetFederationActorRow, error) {
+	row := q.db.QueryRow(ctx, getFederationActor, id)
+	var i GetFederationActorRow
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getFederationFollowers = `-- name: GetFederationFollowers :many
+SELECT
+  user_id, actor, inbox, created_at
+FROM
+  federation_followers
+WHERE
+  user_id = $1
+ORDER BY
+  created_at,
+  actor
+`
+
+func (q *Queries) GetFederationFollowers(ctx context.Context, userID int64) ([]FederationFollower, error) {
+	rows, err := q.db.Query(ctx, getFederationFollowers, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FederationFollower
+	for rows.Next() {
+		var i FederationFollower
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Actor,
+			&i.Inbox,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFederationFollowing = `-- name: GetFederationFollowing :many
+SELECT
+  id, user_id, actor, handle, name, inbox, accepted, created_at
+FROM
+  federation_following
+WHERE
+  user_id = $1
+ORDER BY
+  handle
+`
+
+func (q *Queries) GetFederationFollowing(ctx context.Context, userID int64) ([]FederationFollowing, error) {
+	rows, err := q.db.Query(ctx, getFederationFollowing, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FederationFollowing
+	for rows.Next() {
+		var i FederationFollowing
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Actor,
+			&i.Handle,
+			&i.Name,
+			&i.Inbox,
+			&i.Accepted,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFederationKey = `-- name: GetFederationKey :one
+SELECT
+  user_id, private_key, public_key, created_at
+FROM
+  federation_keys
+WHERE
+  user_id = $1
+`
+
+func (q *Queries) GetFederationKey(ctx context.Context, userID int64) (FederationKey, error) {
+	row := q.db.QueryRow(ctx, getFederationKey, userID)
+	var i FederationKey
+	err := row.Scan(
+		&i.UserID,
+		&i.PrivateKey,
+		&i.PublicKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getInstanceBranding = `-- name: GetInstanceBranding :one
+SELECT
+  id, name, logo_key, accent_color, contact_email, updated_at
+FROM
+  instance_branding
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetInstanceBranding(ctx context.Context, id int32) (InstanceBranding, error) {
+	row := q.db.QueryRow(ctx, getInstanceBranding, id)
+	var i InstanceBranding
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.LogoKey,
+		&i.AccentColor,
+		&i.ContactEmail,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getInvitationCode = `-- name: GetInvitationCode :one
+SELECT
+  code_hash
+FROM
+  valid_invitation_codes
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetInvitationCode(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRow(ctx, getInvitationCode, id)
+	var code_hash string
+	err := row.Scan(&code_hash)
+	return code_hash, err
+}
+
+const getLatestDensityVersion = `-- name: GetLatestDensityVersion :one
+SELECT
+  id,
+  note,
+  created_by,
+  created_at
+FROM
+  density_versions
+ORDER BY
+  id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestDensityVersion(ctx context.Context) (DensityVersion, error) {
+	row := q.db.QueryRow(ctx, getLatestDensityVersion)
+	var i DensityVersion
+	err := row.Scan(
+		&i.ID,
+		&i.Note,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPreferences = `-- name: GetPreferences :one
+SELECT
+  id,
+  allow_public_signup
+FROM
+  preferences
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetPreferences(ctx context.Context, id int32) (Preference, error) {
+	row := q.db.QueryRow(ctx, getPreferences, id)
+	var i Preference
+	err := row.Scan(&i.ID, &i.AllowPublicSignup)
+	return i, err
+}
+
+const getPublicRecipeCount = `-- name: GetPublicRecipeCount :one
+SELECT
+  count(*)
 FROM
   recipes r
-  JOIN recipe_steps rs ON r.id = rs.recipe_id
 WHERE
-  r.user_id = $1
-  AND rs.image_key IS NOT NULL
+  r.published = TRUE
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      recipe_ingredients i
+    WHERE
+      i.recipe_id = r.id
+      AND i.description ~* ANY ($1::text[]))
+`
+
+func (q *Queries) GetPublicRecipeCount(ctx context.Context, allergenPatterns []string) (int64, error) {
+	row := q.db.QueryRow(ctx, getPublicRecipeCount, allergenPatterns)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getPublicRecipes = `-- name: GetPublicRecipes :many
+SELECT
+  r.user_id,
+  r.image_key,
+  r.title,
+  r.description,
+  r.created_at,
+  r.updated_at,
+  r.published,
+  r.cook_time_amount,
+  r.cook_time_unit,
+  r.prep_time_amount,
+  r.prep_time_unit,
+  r.id AS recipe_id,
+  r.servings,
+  u.first_name,
+  u.last_name
+FROM
+  recipes r
+  JOIN users u ON r.user_id = u.id
+WHERE
+  r.published = TRUE
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      recipe_ingredients i
+    WHERE
+      i.recipe_id = r.id
+      AND i.description ~* ANY ($1::text[]))
+ORDER BY
+  r.updated_at DESC,
+  r.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetPublicRecipesParams struct {
+	AllergenPatterns []string
+	Limit            int32
+	Offset           int32
+}
+
+type GetPublicRecipesRow struct {
+	UserID         pgtype.Int8
+	ImageKey       pgtype.Text
+	Title          string
+	Description    pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	Published      bool
+	CookTimeAmount pgtype.Int4
+	CookTimeUnit   NullTimeUnit
+	PrepTimeAmount pgtype.Int4
+	PrepTimeUnit   NullTimeUnit
+	RecipeID       int64
+	Servings       pgtype.Float4
+	FirstName      string
+	LastName       string
+}
+
+func (q *Queries) GetPublicRecipes(ctx context.Context, arg GetPublicRecipesParams) ([]GetPublicRecipesRow, error) {
+	rows, err := q.db.Query(ctx, getPublicRecipes, arg.AllergenPatterns, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPublicRecipesRow
+	for rows.Next() {
+		var i GetPublicRecipesRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ImageKey,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Published,
+			&i.CookTimeAmount,
+			&i.CookTimeUnit,
+			&i.PrepTimeAmount,
+			&i.PrepTimeUnit,
+			&i.RecipeID,
+			&i.Servings,
+			&i.FirstName,
+			&i.LastName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublishedRecipeAndOwner = `-- name: GetPublishedRecipeAndOwner :one
+SELECT
+  r.user_id,
+  r.image_key,
+  r.title,
+  r.description,
+  r.created_at,
+  r.updated_at,
+  r.published,
+  r.id,
+  r.servings,
+  r.cook_time_amount,
+  r.cook_time_unit,
+  r.prep_time_amount,
+  r.prep_time_unit,
+  u.first_name,
+  u.last_name,
+  u.id
+FROM
+  recipes r
+  JOIN users u ON r.user_id = u.id
+WHERE
+  r.id = $1
+  AND published = TRUE
+`
+
+type GetPublishedRecipeAndOwnerRow struct {
+	UserID         pgtype.Int8
+	ImageKey       pgtype.Text
+	Title          string
+	Description    pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	Published      bool
+	ID             int64
+	Servings       pgtype.Float4
+	CookTimeAmount pgtype.Int4
+	CookTimeUnit   NullTimeUnit
+	PrepTimeAmount pgtype.Int4
+	PrepTimeUnit   NullTimeUnit
+	FirstName      string
+	LastName       string
+	ID_2           int64
+}
+
+func (q *Queries) GetPublishedRecipeAndOwner(ctx context.Context, id int64) (GetPublishedRecipeAndOwnerRow, error) {
+	row := q.db.QueryRow(ctx, getPublishedRecipeAndOwner, id)
+	var i GetPublishedRecipeAndOwnerRow
+	err := row.Scan(
+		&i.UserID,
+		&i.ImageKey,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Published,
+		&i.ID,
+		&i.Servings,
+		&i.CookTimeAmount,
+		&i.CookTimeUnit,
+		&i.PrepTimeAmount,
+		&i.PrepTimeUnit,
+		&i.FirstName,
+		&i.LastName,
+		&i.ID_2,
+	)
+	return i, err
+}
+
+const getPublishedRecipesByOwner = `-- name: GetPublishedRecipesByOwner :many
+SELECT
+  id, user_id, image_key, title, description, created_at, updated_at, published, cook_time_amount, cook_time_unit, prep_time_amount, prep_time_unit, servings
+FROM
+  recipes
+WHERE
+  user_id = $1
+  AND published = TRUE
+ORDER BY
+  updated_at DESC,
+  id DESC
+LIMIT $2
+`
+
+type GetPublishedRecipesByOwnerParams struct {
+	UserID pgtype.Int8
+	Limit  int32
+}
+
+func (q *Queries) GetPublishedRecipesByOwner(ctx context.Context, arg GetPublishedRecipesByOwnerParams) ([]Recipe, error) {
+	rows, err := q.db.Query(ctx, getPublishedRecipesByOwner, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Recipe
+	for rows.Next() {
+		var i Recipe
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ImageKey,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Published,
+			&i.CookTimeAmount,
+			&i.CookTimeUnit,
+			&i.PrepTimeAmount,
+			&i.PrepTimeUnit,
+			&i.Servings,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublishedRecipesCreatedSince = `-- name: GetPublishedRecipesCreatedSince :many
+SELECT
+  r.id,
+  r.title,
+  u.first_name,
+  u.last_name
+FROM
+  recipes r
+  JOIN users u ON r.user_id = u.id
+WHERE
+  r.published
+  AND r.user_id <> $1
+  AND r.created_at >= $2
+ORDER BY
+  r.created_at DESC
+LIMIT $3
+`
+
+type GetPublishedRecipesCreatedSinceParams struct {
+	UserID     pgtype.Int8
+	Since      pgtype.Timestamptz
+	MaxRecipes int32
+}
+
+type GetPublishedRecipesCreatedSinceRow struct {
+	ID        int64
+	Title     string
+	FirstName string
+	LastName  string
+}
+
+func (q *Queries) GetPublishedRecipesCreatedSince(ctx context.Context, arg GetPublishedRecipesCreatedSinceParams) ([]GetPublishedRecipesCreatedSinceRow, error) {
+	rows, err := q.db.Query(ctx, getPublishedRecipesCreatedSince, arg.UserID, arg.Since, arg.MaxRecipes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPublishedRecipesCreatedSinceRow
+	for rows.Next() {
+		var i GetPublishedRecipesCreatedSinceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.FirstName,
+			&i.LastName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipeAndOwner = `-- name: GetRecipeAndOwner :one
+SELECT
+  r.user_id,
+  r.image_key,
+  r.title,
+  r.description,
+  r.created_at,
+  r.updated_at,
+  r.published,
+  r.id,
+  r.cook_time_amount,
+  r.cook_time_unit,
+  r.prep_time_amount,
+  r.prep_time_unit,
+  r.servings,
+  u.first_name,
+  u.last_name,
+  u.id
+FROM
+  recipes r
+  JOIN users u ON r.user_id = u.id
+WHERE
+  r.id = $1
+`
+
+type GetRecipeAndOwnerRow struct {
+	UserID         pgtype.Int8
+	ImageKey       pgtype.Text
+	Title          string
+	Description    pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	Published      bool
+	ID             int64
+	CookTimeAmount pgtype.Int4
+	CookTimeUnit   NullTimeUnit
+	PrepTimeAmount pgtype.Int4
+	PrepTimeUnit   NullTimeUnit
+	Servings       pgtype.Float4
+	FirstName      string
+	LastName       string
+	ID_2           int64
+}
+
+func (q *Queries) GetRecipeAndOwner(ctx context.Context, id int64) (GetRecipeAndOwnerRow, error) {
+	row := q.db.QueryRow(ctx, getRecipeAndOwner, id)
+	var i GetRecipeAndOwnerRow
+	err := row.Scan(
+		&i.UserID,
+		&i.ImageKey,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Published,
+		&i.ID,
+		&i.CookTimeAmount,
+		&i.CookTimeUnit,
+		&i.PrepTimeAmount,
+		&i.PrepTimeUnit,
+		&i.Servings,
+		&i.FirstName,
+		&i.LastName,
+		&i.ID_2,
+	)
+	return i, err
+}
+
+const getRecipeImageKey = `-- name: GetRecipeImageKey :one
+SELECT
+  image_key
+FROM
+  recipes
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetRecipeImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getRecipeImageKey, id)
+	var image_key pgtype.Text
+	err := row.Scan(&image_key)
+	return image_key, err
+}
+
+const getRecipeIngredientExistence = `-- name: GetRecipeIngredientExistence :one
+SELECT
+  EXISTS (
+    SELECT
+      1
+    FROM
+      recipe_ingredients
+    WHERE
+      id = $1)
+`
+
+func (q *Queries) GetRecipeIngredientExistence(ctx context.Context, id int64) (bool, error) {
+	row := q.db.QueryRow(ctx, getRecipeIngredientExistence, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const getRecipeIngredientIDs = `-- name: GetRecipeIngredientIDs :many
+SELECT
+  id
+FROM
+  recipe_ingredients
+WHERE
+  recipe_id = $1
+`
+
+func (q *Queries) GetRecipeIngredientIDs(ctx context.Context, recipeID int64) ([]int64, error) {
+	rows, err := q.db.Query(ctx, getRecipeIngredientIDs, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipeIngredientImageKey = `-- name: GetRecipeIngredientImageKey :one
+SELECT
+  image_key
+FROM
+  recipe_ingredients
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetRecipeIngredientImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getRecipeIngredientImageKey, id)
+	var image_key pgtype.Text
+	err := row.Scan(&image_key)
+	return image_key, err
+}
+
+const getRecipeIngredients = `-- name: GetRecipeIngredients :many
+SELECT
+  id,
+  recipe_id,
+  description,
+  image_key,
+  created_at,
+  updated_at
+FROM
+  recipe_ingredients
+WHERE
+  recipe_id = $1
+ORDER BY
+  created_at ASC
+`
+
+func (q *Queries) GetRecipeIngredients(ctx context.Context, recipeID int64) ([]RecipeIngredient, error) {
+	rows, err := q.db.Query(ctx, getRecipeIngredients, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RecipeIngredient
+	for rows.Next() {
+		var i RecipeIngredient
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecipeID,
+			&i.Description,
+			&i.ImageKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipeOwner = `-- name: GetRecipeOwner :one
+SELECT
+  user_id
+FROM
+  recipes
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetRecipeOwner(ctx context.Context, id int64) (pgtype.Int8, error) {
+	row := q.db.QueryRow(ctx, getRecipeOwner, id)
+	var user_id pgtype.Int8
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getRecipeStepExistence = `-- name: GetRecipeStepExistence :one
+SELECT
+  EXISTS (
+    SELECT
+      1
+    FROM
+      recipe_steps
+    WHERE
+      id = $1)
+`
+
+func (q *Queries) GetRecipeStepExistence(ctx context.Context, id int64) (bool, error) {
+	row := q.db.QueryRow(ctx, getRecipeStepExistence, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const getRecipeStepIDs = `-- name: GetRecipeStepIDs :many
+SELECT
+  id
+FROM
+  recipe_steps
+WHERE
+  recipe_id = $1
+`
+
+func (q *Queries) GetRecipeStepIDs(ctx context.Context, recipeID int64) ([]int64, error) {
+	rows, err := q.db.Query(ctx, getRecipeStepIDs, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipeStepImageKey = `-- name: GetRecipeStepImageKey :one
+SELECT
+  image_key
+FROM
+  recipe_steps
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetRecipeStepImageKey(ctx context.Context, id int64) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getRecipeStepImageKey, id)
+	var image_key pgtype.Text
+	err := row.Scan(&image_key)
+	return image_key, err
+}
+
+const getRecipeSteps = `-- name: GetRecipeSteps :many
+SELECT
+  id, recipe_id, step_number, instruction, image_key, created_at, updated_at, temperature_value, temperature_unit
+FROM
+  recipe_steps
+WHERE
+  recipe_id = $1
+ORDER BY
+  step_number ASC
+`
+
+func (q *Queries) GetRecipeSteps(ctx context.Context, recipeID int64) ([]RecipeStep, error) {
+	rows, err := q.db.Query(ctx, getRecipeSteps, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RecipeStep
+	for rows.Next() {
+		var i RecipeStep
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecipeID,
+			&i.StepNumber,
+			&i.Instruction,
+			&i.ImageKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TemperatureValue,
+			&i.TemperatureUnit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipeTagSuggestions = `-- name: GetRecipeTagSuggestions :many
+SELECT
+  tag,
+  score
+FROM
+  recipe_tag_suggestions
+WHERE
+  recipe_id = $1
+ORDER BY
+  score DESC,
+  tag
+`
+
+type GetRecipeTagSuggestionsRow struct {
+	Tag   string
+	Score int32
+}
+
+func (q *Queries) GetRecipeTagSuggestions(ctx context.Context, recipeID int64) ([]GetRecipeTagSuggestionsRow, error) {
+	rows, err := q.db.Query(ctx, getRecipeTagSuggestions, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecipeTagSuggestionsRow
+	for rows.Next() {
+		var i GetRecipeTagSuggestionsRow
+		if err := rows.Scan(&i.Tag, &i.Score); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipeTags = `-- name: GetRecipeTags :many
+SELECT
+  tag
+FROM
+  recipe_tags
+WHERE
+  recipe_id = $1
+ORDER BY
+  tag
+`
+
+func (q *Queries) GetRecipeTags(ctx context.Context, recipeID int64) ([]string, error) {
+	rows, err := q.db.Query(ctx, getRecipeTags, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipesByOwner = `-- name: GetRecipesByOwner :many
+SELECT
+  r.user_id,
+  r.image_key,
+  r.title,
+  r.description,
+  r.created_at,
+  r.updated_at,
+  r.published,
+  r.cook_time_amount,
+  r.cook_time_unit,
+  r.prep_time_amount,
+  r.prep_time_unit,
+  r.id AS recipe_id,
+  r.servings,
+  u.first_name,
+  u.last_name,
+  (
+    SELECT
+      count(*)
+    FROM
+      recipe_tags rt
+    WHERE
+      rt.recipe_id = r.id) AS tag_count,
+  (
+    SELECT
+      count(*)
+    FROM
+      recipe_steps s
+    WHERE
+      s.recipe_id = r.id) AS step_count,
+  (
+    SELECT
+      count(*)
+    FROM
+      recipe_steps s
+    WHERE
+      s.recipe_id = r.id
+      AND coalesce(btrim(s.instruction), '') = '') AS blank_step_count
+FROM
+  recipes r
+  JOIN users u ON r.user_id = u.id
+WHERE
+  u.id = $1
+ORDER BY
+  r.updated_at DESC,
+  r.id DESC
+LIMIT $2::int OFFSET $3
+`
+
+type GetRecipesByOwnerParams struct {
+	UserID int64
+	Limit  pgtype.Int4
+	Offset int32
+}
+
+type GetRecipesByOwnerRow struct {
+	UserID         pgtype.Int8
+	ImageKey       pgtype.Text
+	Title          string
+	Description    pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	Published      bool
+	CookTimeAmount pgtype.Int4
+	CookTimeUnit   NullTimeUnit
+	PrepTimeAmount pgtype.Int4
+	PrepTimeUnit   NullTimeUnit
+	RecipeID       int64
+	Servings       pgtype.Float4
+	FirstName      string
+	LastName       string
+	TagCount       int64
+	StepCount      int64
+	BlankStepCount int64
+}
+
+func (q *Queries) GetRecipesByOwner(ctx context.Context, arg GetRecipesByOwnerParams) ([]GetRecipesByOwnerRow, error) {
+	rows, err := q.db.Query(ctx, getRecipesByOwner, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecipesByOwnerRow
+	for rows.Next() {
+		var i GetRecipesByOwnerRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ImageKey,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Published,
+			&i.CookTimeAmount,
+			&i.CookTimeUnit,
+			&i.PrepTimeAmount,
+			&i.PrepTimeUnit,
+			&i.RecipeID,
+			&i.Servings,
+			&i.FirstName,
+			&i.LastName,
+			&i.TagCount,
+			&i.StepCount,
+			&i.BlankStepCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStockImage = `-- name: GetStockImage :one
+SELECT
+  id, ingredient, image_key, created_by, created_at, updated_at
+FROM
+  stock_images
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetStockImage(ctx context.Context, id int64) (StockImage, error) {
+	row := q.db.QueryRow(ctx, getStockImage, id)
+	var i StockImage
+	err := row.Scan(
+		&i.ID,
+		&i.Ingredient,
+		&i.ImageKey,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getStockImages = `-- name: GetStockImages :many
+SELECT
+  id, ingredient, image_key, created_by, created_at, updated_at
+FROM
+  stock_images
+ORDER BY
+  ingredient
+`
+
+func (q *Queries) GetStockImages(ctx context.Context) ([]StockImage, error) {
+	rows, err := q.db.Query(ctx, getStockImages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StockImage
+	for rows.Next() {
+		var i StockImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Ingredient,
+			&i.ImageKey,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUntaggedRecipes = `-- name: GetUntaggedRecipes :many
+SELECT
+  r.id,
+  r.user_id,
+  r.title,
+  si.fingerprint
+FROM
+  recipes r
+  LEFT JOIN recipe_tag_suggestion_inputs si ON si.recipe_id = r.id
+WHERE
+  r.id > $1
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      recipe_tags rt
+    WHERE
+      rt.recipe_id = r.id)
+ORDER BY
+  r.id
+LIMIT $2
+`
+
+type GetUntaggedRecipesParams struct {
+	After     int64
+	BatchSize int32
+}
+
+type GetUntaggedRecipesRow struct {
+	ID          int64
+	UserID      pgtype.Int8
+	Title       string
+	Fingerprint pgtype.Text
+}
+
+func (q *Queries) GetUntaggedRecipes(ctx context.Context, arg GetUntaggedRecipesParams) ([]GetUntaggedRecipesRow, error) {
+	rows, err := q.db.Query(ctx, getUntaggedRecipes, arg.After, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUntaggedRecipesRow
+	for rows.Next() {
+		var i GetUntaggedRecipesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Fingerprint,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUploadTokenUser = `-- name: GetUploadTokenUser :one
+SELECT
+  user_id
+FROM
+  upload_tokens
+WHERE
+  id = $1
+  AND used_at IS NULL
+  AND expires_at > $2
+`
+
+type GetUploadTokenUserParams struct {
+	ID        string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetUploadTokenUser(ctx context.Context, arg GetUploadTokenUserParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getUploadTokenUser, arg.ID, arg.ExpiresAt)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT
+  id,
+  password_hash,
+  ROLE
+FROM
+  users
+WHERE
+  email = trim(lower($1))
+`
+
+type GetUserRow struct {
+	ID           int64
+	PasswordHash string
+	Role         Role
+}
+
+func (q *Queries) GetUser(ctx context.Context, lower string) (GetUserRow, error) {
+	row := q.db.QueryRow(ctx, getUser, lower)
+	var i GetUserRow
+	err := row.Scan(&i.ID, &i.PasswordHash, &i.Role)
+	return i, err
+}
+
+const getUserActivity = `-- name: GetUserActivity :many
+SELECT
+  a.id,
+  a.action,
+  a.target_type,
+  a.target_id,
+  a.metadata,
+  a.created_at,
+  r.title AS recipe_title
+FROM
+  audit_events a
+  LEFT JOIN recipes r ON a.target_type = 'recipe'
+    AND r.id = a.target_id
+WHERE
+  a.actor_id = $1
+  AND NOT a.dry_run
+  AND a.id < coalesce($2, 9223372036854775807)
+ORDER BY
+  a.id DESC
+LIMIT $3
+`
+
+type GetUserActivityParams struct {
+	ActorID pgtype.Int8
+	Before  pgtype.Int8
+	Limit   int32
+}
+
+type GetUserActivityRow struct {
+	ID          int64
+	Action      string
+	TargetType  string
+	TargetID    pgtype.Int8
+	Metadata    []byte
+	CreatedAt   pgtype.Timestamptz
+	RecipeTitle pgtype.Text
+}
+
+func (q *Queries) GetUserActivity(ctx context.Context, arg GetUserActivityParams) ([]GetUserActivityRow, error) {
+	rows, err := q.db.Query(ctx, getUserActivity, arg.ActorID, arg.Before, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserActivityRow
+	for rows.Next() {
+		var i GetUserActivityRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.RecipeTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserActivityCount = `-- name: GetUserActivityCount :one
+SELECT
+  count(*)
+FROM
+  audit_events
+WHERE
+  actor_id = $1
+  AND NOT dry_run
+`
+
+func (q *Queries) GetUserActivityCount(ctx context.Context, actorID pgtype.Int8) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserActivityCount, actorID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getUserAllergies = `-- name: GetUserAllergies :one
+SELECT
+  allergies
+FROM
+  users
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetUserAllergies(ctx context.Context, id int64) ([]string, error) {
+	row := q.db.QueryRow(ctx, getUserAllergies, id)
+	var allergies []string
+	err := row.Scan(&allergies)
+	return allergies, err
+}
+
+const getUserById = `-- name: GetUserById :one
+SELECT
+  id,
+  email,
+  first_name,
+  last_name,
+  ROLE
+FROM
+  users
+WHERE
+  id = $1
+`
+
+type GetUserByIdRow struct {
+	ID        int64
+	Email     string
+	FirstName string
+	LastName  string
+	Role      Role
+}
+
+func (q *Queries) GetUserById(ctx context.Context, id int64) (GetUserByIdRow, error) {
+	row := q.db.QueryRow(ctx, getUserById, id)
+	var i GetUserByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Role,
+	)
+	return i, err
+}
+
+const getUserCount = `-- name: GetUserCount :one
+SELECT
+  count(*)
+FROM
+  users
+`
+
+func (q *Queries) GetUserCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getUserForExport = `-- name: GetUserForExport :one
+SELECT
+  id,
+  email,
+  first_name,
+  last_name,
+  ROLE,
+  temperature_unit,
+  weekly_report,
+  allergies,
+  time_zone,
+  created_at,
+  updated_at
+FROM
+  users
+WHERE
+  id = $1
+`
+
+type GetUserForExportRow struct {
+	ID              int64
+	Email           string
+	FirstName       string
+	LastName        string
+	Role            Role
+	TemperatureUnit NullTemperatureUnit
+	WeeklyReport    bool
+	Allergies       []string
+	TimeZone        string
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+}
+
+func (q *Queries) GetUserForExport(ctx context.Context, id int64) (GetUserForExportRow, error) {
+	row := q.db.QueryRow(ctx, getUserForExport, id)
+	var i GetUserForExportRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Role,
+		&i.TemperatureUnit,
+		&i.WeeklyReport,
+		&i.Allergies,
+		&i.TimeZone,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserPasswordHash = `-- name: GetUserPasswordHash :one
+SELECT
+  password_hash
+FROM
+  users
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetUserPasswordHash(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRow(ctx, getUserPasswordHash, id)
+	var password_hash string
+	err := row.Scan(&password_hash)
+	return password_hash, err
+}
+
+const getUserPreferences = `-- name: GetUserPreferences :one
+SELECT
+  temperature_unit,
+  weekly_report,
+  allergies,
+  time_zone
+FROM
+  users
+WHERE
+  id = $1
+`
+
+type GetUserPreferencesRow struct {
+	TemperatureUnit NullTemperatureUnit
+	WeeklyReport    bool
+	Allergies       []string
+	TimeZone        string
+}
+
+func (q *Queries) GetUserPreferences(ctx context.Context, id int64) (GetUserPreferencesRow, error) {
+	row := q.db.QueryRow(ctx, getUserPreferences, id)
+	var i GetUserPreferencesRow
+	err := row.Scan(
+		&i.TemperatureUnit,
+		&i.WeeklyReport,
+		&i.Allergies,
+		&i.TimeZone,
+	)
+	return i, err
+}
+
+const getUserRecipeCount = `-- name: GetUserRecipeCount :one
+SELECT
+  count(*)
+FROM
+  recipes
+WHERE
+  user_id = $1
+`
+
+func (q *Queries) GetUserRecipeCount(ctx context.Context, userID pgtype.Int8) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserRecipeCount, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getUserRecipeIDSample = `-- name: GetUserRecipeIDSample :many
+SELECT
+  id
+FROM
+  recipes
+WHERE
+  user_id = $1
+ORDER BY
+  id
+LIMIT $2
+`
+
+type GetUserRecipeIDSampleParams struct {
+	UserID pgtype.Int8
+	Limit  int32
+}
+
+func (q *Queries) GetUserRecipeIDSample(ctx context.Context, arg GetUserRecipeIDSampleParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, getUserRecipeIDSample, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserRecipeImages = `-- name: GetUserRecipeImages :many
+SELECT
+  image_key
+FROM
+  recipes
+WHERE
+  user_id = $1
+  AND image_key IS NOT NULL
+`
+
+func (q *Queries) GetUserRecipeImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
+	rows, err := q.db.Query(ctx, getUserRecipeImages, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.Text
+	for rows.Next() {
+		var image_key pgtype.Text
+		if err := rows.Scan(&image_key); err != nil {
+			return nil, err
+		}
+		items = append(items, image_key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserRecipeIngredientImages = `-- name: GetUserRecipeIngredientImages :many
+SELECT
+  ri.image_key
+FROM
+  recipes r
+  JOIN recipe_ingredients ri ON r.id = ri.recipe_id
+WHERE
+  r.user_id = $1
+  AND ri.image_key IS NOT NULL
+`
+
+func (q *Queries) GetUserRecipeIngredientImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
+	rows, err := q.db.Query(ctx, getUserRecipeIngredientImages, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.Text
+	for rows.Next() {
+		var image_key pgtype.Text
+		if err := rows.Scan(&image_key); err != nil {
+			return nil, err
+		}
+		items = append(items, image_key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserRecipeStepImages = `-- name: GetUserRecipeStepImages :many
+SELECT
+  rs.image_key
+FROM
+  recipes r
+  JOIN recipe_steps rs ON r.id = rs.recipe_id
+WHERE
+  r.user_id = $1
+  AND rs.image_key IS NOT NULL
+`
+
+func (q *Queries) GetUserRecipeStepImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
+	rows, err := q.db.Query(ctx, getUserRecipeStepImages, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.Text
+	for rows.Next() {
+		var image_key pgtype.Text
+		if err := rows.Scan(&image_key); err != nil {
+			return nil, err
+		}
+		items = append(items, image_key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserRecipesCreatedSince = `-- name: GetUserRecipesCreatedSince :many
+SELECT
+  id,
+  title
+FROM
+  recipes
+WHERE
+  user_id = $1
+  AND created_at >= $2
+ORDER BY
+  created_at ASC
+`
+
+type GetUserRecipesCreatedSinceParams struct {
+	UserID pgtype.Int8
+	Since  pgtype.Timestamptz
+}
+
+type GetUserRecipesCreatedSinceRow struct {
+	ID    int64
+	Title string
+}
+
+func (q *Queries) GetUserRecipesCreatedSince(ctx context.Context, arg GetUserRecipesCreatedSinceParams) ([]GetUserRecipesCreatedSinceRow, error) {
+	rows, err := q.db.Query(ctx, getUserRecipesCreatedSince, arg.UserID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserRecipesCreatedSinceRow
+	for rows.Next() {
+		var i GetUserRecipesCreatedSinceRow
+		if err := rows.Scan(&i.ID, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserRefreshTokenHash = `-- name: GetUserRefreshTokenHash :one
+SELECT
+  refresh_token_hash,
+  refresh_token_expires_at
+FROM
+  users
+WHERE
+  id = $1
+`
+
+type GetUserRefreshTokenHashRow struct {
+	RefreshTokenHash      pgtype.Text
+	RefreshTokenExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetUserRefreshTokenHash(ctx context.Context, id int64) (GetUserRefreshTokenHashRow, error) {
+	row := q.db.QueryRow(ctx, getUserRefreshTokenHash, id)
+	var i GetUserRefreshTokenHashRow
+	err := row.Scan(&i.RefreshTokenHash, &i.RefreshTokenExpiresAt)
+	return i, err
+}
+
+const getUserRole = `-- name: GetUserRole :one
+SELECT
+  ROLE
+FROM
+  users
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetUserRole(ctx context.Context, id int64) (Role, error) {
+	row := q.db.QueryRow(ctx, getUserRole, id)
+	var role Role
+	err := row.Scan(&role)
+	return role, err
+}
+
+const getUserTags = `-- name: GetUserTags :many
+SELECT DISTINCT
+  rt.tag
+FROM
+  recipe_tags rt
+  JOIN recipes r ON r.id = rt.recipe_id
+WHERE
+  r.user_id = $1
+ORDER BY
+  rt.tag
+`
+
+func (q *Queries) GetUserTags(ctx context.Context, userID pgtype.Int8) ([]string, error) {
+	rows, err := q.db.Query(ctx, getUserTags, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserTemperatureUnit = `-- name: GetUserTemperatureUnit :one
+SELECT
+  temperature_unit
+FROM
+  users
+WHERE
+  id = $1
+`
+
+func (q *Queries) GetUserTemperatureUnit(ctx context.Context, id int64) (NullTemperatureUnit, error) {
+	row := q.db.QueryRow(ctx, getUserTemperatureUnit, id)
+	var temperature_unit NullTemperatureUnit
+	err := row.Scan(&temperature_unit)
+	return temperature_unit, err
+}
+
+const getUsers = `-- name: GetUsers :many
+SELECT
+  id,
+  email,
+  first_name,
+  last_name,
+  ROLE
+FROM
+  users
+WHERE
+  id > coalesce($1, 0)
+ORDER BY
+  id
+LIMIT $2
+`
+
+type GetUsersParams struct {
+	After pgtype.Int8
+	Limit int32
+}
+
+type GetUsersRow struct {
+	ID        int64
+	Email     string
+	FirstName string
+	LastName  string
+	Role      Role
+}
+
+func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
+	rows, err := q.db.Query(ctx, getUsers, arg.After, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersRow
+	for rows.Next() {
+		var i GetUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.FirstName,
+			&i.LastName,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWeeklyReportRecipients = `-- name: GetWeeklyReportRecipients :many
+SELECT
+  id,
+  email,
+  first_name,
+  weekly_report_sent_at,
+  time_zone
+FROM
+  users
+WHERE
+  weekly_report
+  AND (weekly_report_due_at IS NULL
+    OR weekly_report_due_at <= $1)
+  AND id > $2
+ORDER BY
+  id ASC
+LIMIT $3
+`
+
+type GetWeeklyReportRecipientsParams struct {
+	DueBefore pgtype.Timestamptz
+	After     int64
+	BatchSize int32
+}
+
+type GetWeeklyReportRecipientsRow struct {
+	ID                 int64
+	Email              string
+	FirstName          string
+	WeeklyReportSentAt pgtype.Timestamptz
+	TimeZone           string
+}
+
+func (q *Queries) GetWeeklyReportRecipients(ctx context.Context, arg GetWeeklyReportRecipientsParams) ([]GetWeeklyReportRecipientsRow, error) {
+	rows, err := q.db.Query(ctx, getWeeklyReportRecipients, arg.DueBefore, arg.After, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWeeklyReportRecipientsRow
+	for rows.Next() {
+		var i GetWeeklyReportRecipientsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.FirstName,
+			&i.WeeklyReportSentAt,
+			&i.TimeZone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const importDensities = `-- name: ImportDensities :one
+WITH v AS (
+INSERT INTO density_versions (note, created_by)
+    VALUES ($1, $2)
+  RETURNING
+    id, created_at
+), d AS (
+INSERT INTO ingredient_densities (version_id, ingredient, ml_per_gram)
+  SELECT
+    v.id,
+    unnest($3::text[]),
+    unnest($4::float8[])
+  FROM
+    v)
+SELECT
+  id,
+  created_at
+FROM
+  v
+`
+
+type ImportDensitiesParams struct {
+	Note        string
+	CreatedBy   pgtype.Int8
+	Ingredients []string
+	MlPerGram   []float64
+}
+
+type ImportDensitiesRow struct {
+	ID        int64
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) ImportDensities(ctx context.Context, arg ImportDensitiesParams) (ImportDensitiesRow, error) {
+	row := q.db.QueryRow(ctx, importDensities,
+		arg.Note,
+		arg.CreatedBy,
+		arg.Ingredients,
+		arg.MlPerGram,
+	)
+	var i ImportDensitiesRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
+const isFederationActorFollowed = `-- name: IsFederationActorFollowed :one
+SELECT
+  EXISTS (
+    SELECT
+      1
+    FROM
+      federation_following
+    WHERE
+      actor = $1
+      AND accepted = TRUE)
+`
+
+func (q *Queries) IsFederationActorFollowed(ctx context.Context, actor string) (bool, error) {
+	row := q.db.QueryRow(ctx, isFederationActorFollowed, actor)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const markDeliveryFailed = `-- name: MarkDeliveryFailed :exec
+UPDATE
+  deliveries
+SET
+  status = $2,
+  attempts = attempts + 1,
+  last_error = $3,
+  next_attempt_at = $4,
+  updated_at = $5
+WHERE
+  id = $1
+`
+
+type MarkDeliveryFailedParams struct {
+	ID            int64
+	Status        string
+	LastError     pgtype.Text
+	NextAttemptAt pgtype.Timestamptz
+	Now           pgtype.Timestamptz
+}
+
+func (q *Queries) MarkDeliveryFailed(ctx context.Context, arg MarkDeliveryFailedParams) error {
+	_, err := q.db.Exec(ctx, markDeliveryFailed,
+		arg.ID,
+		arg.Status,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.Now,
+	)
+	return err
+}
+
+const markDeliverySent = `-- name: MarkDeliverySent :exec
+UPDATE
+  deliveries
+SET
+  status = 'sent',
+  attempts = attempts + 1,
+  last_error = NULL,
+  next_attempt_at = NULL,
+  sent_at = $2,
+  updated_at = $2
+WHERE
+  id = $1
+`
+
+type MarkDeliverySentParams struct {
+	ID  int64
+	Now pgtype.Timestamptz
+}
+
+func (q *Queries) MarkDeliverySent(ctx context.Context, arg MarkDeliverySentParams) error {
+	_, err := q.db.Exec(ctx, markDeliverySent, arg.ID, arg.Now)
+	return err
+}
+
+const markWeeklyReportSent = `-- name: MarkWeeklyReportSent :exec
+UPDATE
+  users
+SET
+  weekly_report_sent_at = $1,
+  weekly_report_due_at = $2
+WHERE
+  id = $3
+`
+
+type MarkWeeklyReportSentParams struct {
+	SentAt pgtype.Timestamptz
+	DueAt  pgtype.Timestamptz
+	ID     int64
+}
+
+func (q *Queries) MarkWeeklyReportSent(ctx context.Context, arg MarkWeeklyReportSentParams) error {
+	_, err := q.db.Exec(ctx, markWeeklyReportSent, arg.SentAt, arg.DueAt, arg.ID)
+	return err
+}
+
+const matchStockImage = `-- name: MatchStockImage :one
+SELECT
+  id, ingredient, image_key, created_by, created_at, updated_at
+FROM
+  stock_images
+WHERE
+  ingredient = ANY ($1::text[])
+ORDER BY
+  array_position($1::text[], ingredient)
+LIMIT 1
+`
+
+func (q *Queries) MatchStockImage(ctx context.Context, candidates []string) (StockImage, error) {
+	row := q.db.QueryRow(ctx, matchStockImage, candidates)
+	var i StockImage
+	err := row.Scan(
+		&i.ID,
+		&i.Ingredient,
+		&i.ImageKey,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const moveUserTag = `-- name: MoveUserTag :one
+WITH moved AS (
+  DELETE FROM recipe_tags rt USING recipes r
+  WHERE r.id = rt.recipe_id
+    AND r.user_id = $1
+    AND rt.tag = $2
+  RETURNING
+    rt.recipe_id,
+    rt.created_at
+),
+added AS (
+INSERT INTO recipe_tags (recipe_id, tag, created_at)
+  SELECT
+    recipe_id,
+    $3,
+    created_at
+  FROM
+    moved
+  ON CONFLICT
+    DO NOTHING
+  RETURNING
+    recipe_id)
+SELECT
+  (
+    SELECT
+      count(*)
+    FROM
+      moved)::bigint AS recipes,
+  (
+    SELECT
+      count(*)
+    FROM
+      moved)::bigint - (
+    SELECT
+      count(*)
+    FROM
+      added)::bigint AS merged
+`
+
+type MoveUserTagParams struct {
+	UserID pgtype.Int8
+	Tag    string
+	NewTag string
+}
+
+type MoveUserTagRow struct {
+	Recipes int64
+	Merged  int64
+}
+
+func (q *Queries) MoveUserTag(ctx context.Context, arg MoveUserTagParams) (MoveUserTagRow, error) {
+	row := q.db.QueryRow(ctx, moveUserTag, arg.UserID, arg.Tag, arg.NewTag)
+	var i MoveUserTagRow
+	err := row.Scan(&i.Recipes, &i.Merged)
+	return i, err
+}
+
+const redeemInvitationCode = `-- name: RedeemInvitationCode :execrows
+UPDATE
+  valid_invitation_codes
+SET
+  used_at = now()
+WHERE
+  id = $1
+`
+
+func (q *Queries) RedeemInvitationCode(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, redeemInvitationCode, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const renameUserTag = `-- name: RenameUserTag :one
+WITH used AS (
+  SELECT
+    1
+  FROM
+    recipe_tags rt
+    JOIN recipes r ON r.id = rt.recipe_id
+  WHERE
+    r.user_id = $1
+    AND rt.tag = $2
+),
+renamed AS (
+  UPDATE
+    recipe_tags rt
+  SET
+    tag = $2
+  FROM
+    recipes r
+  WHERE
+    r.id = rt.recipe_id
+    AND r.user_id = $1
+    AND rt.tag = $3
+    AND NOT EXISTS (
+      SELECT
+        1
+      FROM
+        used)
+  RETURNING
+    rt.recipe_id
+)
+SELECT
+  (
+    SELECT
+      count(*)
+    FROM
+      renamed)::bigint AS recipes,
+  EXISTS (
+    SELECT
+      1
+    FROM
+      used) AS in_use
+`
+
+type RenameUserTagParams struct {
+	UserID pgtype.Int8
+	NewTag string
+	Tag    string
+}
+
+type RenameUserTagRow struct {
+	Recipes int64
+	InUse   bool
+}
+
+func (q *Queries) RenameUserTag(ctx context.Context, arg RenameUserTagParams) (RenameUserTagRow, error) {
+	row := q.db.QueryRow(ctx, renameUserTag, arg.UserID, arg.NewTag, arg.Tag)
+	var i RenameUserTagRow
+	err := row.Scan(&i.Recipes, &i.InUse)
+	return i, err
+}
+
+const restoreRecipeCoverImage = `-- name: RestoreRecipeCoverImage :execrows
+UPDATE
+  recipes
+SET
+  image_key = $1
+WHERE
+  id = $2
+  AND image_key IS NULL
+`
+
+type RestoreRecipeCoverImageParams struct {
+	ImageKey pgtype.Text
+	ID       int64
+}
+
+func (q *Queries) RestoreRecipeCoverImage(ctx context.Context, arg RestoreRecipeCoverImageParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreRecipeCoverImage, arg.ImageKey, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreRecipeIngredient = `-- name: RestoreRecipeIngredient :exec
+INSERT INTO recipe_ingredients (id, recipe_id, description, image_key, created_at)
+  VALUES ($1, $2, $3, $4, $5)
+`
+
+type RestoreRecipeIngredientParams struct {
+	ID          int64
+	RecipeID    int64
+	Description pgtype.Text
+	ImageKey    pgtype.Text
+	CreatedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) RestoreRecipeIngredient(ctx context.Context, arg RestoreRecipeIngredientParams) error {
+	_, err := q.db.Exec(ctx, restoreRecipeIngredient,
+		arg.ID,
+		arg.RecipeID,
+		arg.Description,
+		arg.ImageKey,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const restoreRecipeIngredientImage = `-- name: RestoreRecipeIngredientImage :execrows
+UPDATE
+  recipe_ingredients
+SET
+  image_key = $1
+WHERE
+  id = $2
+  AND recipe_id = $3
+  AND image_key IS NULL
 `
 
-func (q *Queries) GetUserRecipeStepImages(ctx context.Context, userID pgtype.Int8) ([]pgtype.Text, error) {
-	rows, err := q.db.Query(ctx, getUserRecipeStepImages, userID)
+type RestoreRecipeIngredientImageParams struct {
+	ImageKey pgtype.Text
+	ID       int64
+	RecipeID int64
+}
+
+func (q *Queries) RestoreRecipeIngredientImage(ctx context.Context, arg RestoreRecipeIngredientImageParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreRecipeIngredientImage, arg.ImageKey, arg.ID, arg.RecipeID)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []pgtype.Text
-	for rows.Next() {
-		var image_key pgtype.Text
-		if err := rows.Scan(&image_key); err != nil {
-			return nil, err
-		}
-		items = append(items, image_key)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected(), nil
 }
 
-const getUserRefreshTokenHash = `-- name: GetUserRefreshTokenHash :one
-SELECT
-  refresh_token_hash,
-  refresh_token_expires_at
-FROM
-  users
-WHERE
-  id = $1
+const restoreRecipeStep = `-- name: RestoreRecipeStep :exec
+INSERT INTO recipe_steps (id, recipe_id, step_number, instruction, image_key, temperature_value,
+  temperature_unit, created_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 `
 
-type GetUserRefreshTokenHashRow struct {
-	RefreshTokenHash      pgtype.Text
-	RefreshTokenExpiresAt pgtype.Timestamptz
+type RestoreRecipeStepParams struct {
+	ID               int64
+	RecipeID         int64
+	StepNumber       int32
+	Instruction      pgtype.Text
+	ImageKey         pgtype.Text
+	TemperatureValue pgtype.Float4
+	TemperatureUnit  NullTemperatureUnit
+	CreatedAt        pgtype.Timestamptz
 }
 
-func (q *Queries) GetUserRefreshTokenHash(ctx context.Context, id int64) (GetUserRefreshTokenHashRow, error) {
-	row := q.db.QueryRow(ctx, getUserRefreshTokenHash, id)
-	var i GetUserRefreshTokenHashRow
-	err := row.Scan(&i.RefreshTokenHash, &i.RefreshTokenExpiresAt)
-	return i, err
+func (q *Queries) RestoreRecipeStep(ctx context.Context, arg RestoreRecipeStepParams) error {
+	_, err := q.db.Exec(ctx, restoreRecipeStep,
+		arg.ID,
+		arg.RecipeID,
+		arg.StepNumber,
+		arg.Instruction,
+		arg.ImageKey,
+		arg.TemperatureValue,
+		arg.TemperatureUnit,
+		arg.CreatedAt,
+	)
+	return err
 }
 
-const getUserRole = `-- name: GetUserRole :one
-SELECT
-  ROLE
-FROM
-  users
+const restoreRecipeStepImage = `-- name: RestoreRecipeStepImage :execrows
+UPDATE
+  recipe_steps
+SET
+  image_key = $1
 WHERE
-  id = $1
+  id = $2
+  AND recipe_id = $3
+  AND image_key IS NULL
 `
 
-func (q *Queries) GetUserRole(ctx context.Context, id int64) (Role, error) {
-	row := q.db.QueryRow(ctx, getUserRole, id)
-	var role Role
-	err := row.Scan(&role)
-	return role, err
+type RestoreRecipeStepImageParams struct {
+	ImageKey pgtype.Text
+	ID       int64
+	RecipeID int64
 }
 
-const getUsers = `-- name: GetUsers :many
-SELECT
-  id,
-  email,
-  first_name,
-  last_name,
-  ROLE
-FROM
-  users
+func (q *Queries) RestoreRecipeStepImage(ctx context.Context, arg RestoreRecipeStepImageParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreRecipeStepImage, arg.ImageKey, arg.ID, arg.RecipeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setInstanceLogo = `-- name: SetInstanceLogo :one
+WITH old AS (
+  SELECT
+    logo_key
+  FROM
+    instance_branding
+  WHERE
+    id = $1)
+UPDATE
+  instance_branding
+SET
+  logo_key = $2,
+  updated_at = now()
 WHERE
-  id > coalesce($1, 0)
-ORDER BY
-  id
-LIMIT LEAST (100, GREATEST (1, coalesce($2::int, 20)))
+  id = $1
+RETURNING
+  (
+    SELECT
+      logo_key
+    FROM
+      old)::text AS old_logo_key
 `
 
-type GetUsersParams struct {
-	After pgtype.Int8
-	Limit pgtype.Int4
+type SetInstanceLogoParams struct {
+	ID      int32
+	LogoKey pgtype.Text
 }
 
-type GetUsersRow struct {
-	ID        int64
-	Email     string
-	FirstName string
-	LastName  string
-	Role      Role
+func (q *Queries) SetInstanceLogo(ctx context.Context, arg SetInstanceLogoParams) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, setInstanceLogo, arg.ID, arg.LogoKey)
+	var old_logo_key pgtype.Text
+	err := row.Scan(&old_logo_key)
+	return old_logo_key, err
 }
 
-func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
-	rows, err := q.db.Query(ctx, getUsers, arg.After, arg.Limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetUsersRow
-	for rows.Next() {
-		var i GetUsersRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Email,
-			&i.FirstName,
-			&i.LastName,
-			&i.Role,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+const setRecipeTagSuggestionInputs = `-- name: SetRecipeTagSuggestionInputs :exec
+INSERT INTO recipe_tag_suggestion_inputs (recipe_id, fingerprint)
+  VALUES ($1, $2)
+ON CONFLICT (recipe_id)
+  DO UPDATE SET
+    fingerprint = EXCLUDED.fingerprint, suggested_at = now()
+`
+
+type SetRecipeTagSuggestionInputsParams struct {
+	RecipeID    int64
+	Fingerprint string
 }
 
-const redeemInvitationCode = `-- name: RedeemInvitationCode :execrows
+func (q *Queries) SetRecipeTagSuggestionInputs(ctx context.Context, arg SetRecipeTagSuggestionInputsParams) error {
+	_, err := q.db.Exec(ctx, setRecipeTagSuggestionInputs, arg.RecipeID, arg.Fingerprint)
+	return err
+}
+
+const unsubscribeWeeklyReport = `-- name: UnsubscribeWeeklyReport :execrows
 UPDATE
-  valid_invitation_codes
+  users
 SET
-  used_at = now()
+  weekly_report = FALSE
 WHERE
   id = $1
 `
 
-func (q *Queries) RedeemInvitationCode(ctx context.Context, id int64) (int64, error) {
-	result, err := q.db.Exec(ctx, redeemInvitationCode, id)
+func (q *Queries) UnsubscribeWeeklyReport(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, unsubscribeWeeklyReport, id)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
+const updateInstanceBranding = `-- name: UpdateInstanceBranding :one
+UPDATE
+  instance_branding
+SET
+  name = coalesce($1, name),
+  accent_color = CASE WHEN $2::boolean THEN
+    $3
+  ELSE
+    accent_color
+  END,
+  contact_email = CASE WHEN $4::boolean THEN
+    $5
+  ELSE
+    contact_email
+  END,
+  updated_at = now()
+WHERE
+  id = $6
+RETURNING
+  id, name, logo_key, accent_color, contact_email, updated_at
+`
+
+type UpdateInstanceBrandingParams struct {
+	Name               pgtype.Text
+	UpdateAccentColor  bool
+	AccentColor        pgtype.Text
+	UpdateContactEmail bool
+	ContactEmail       pgtype.Text
+	ID                 int32
+}
+
+func (q *Queries) UpdateInstanceBranding(ctx context.Context, arg UpdateInstanceBrandingParams) (InstanceBranding, error) {
+	row := q.db.QueryRow(ctx, updateInstanceBranding,
+		arg.Name,
+		arg.UpdateAccentColor,
+		arg.AccentColor,
+		arg.UpdateContactEmail,
+		arg.ContactEmail,
+		arg.ID,
+	)
+	var i InstanceBranding
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.LogoKey,
+		&i.AccentColor,
+		&i.ContactEmail,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const updatePreferences = `-- name: UpdatePreferences :one
 UPDATE
   preferences
@@ -1550,6 +4472,16 @@ SET
     $7
   ELSE
     image_key
+  END,
+  temperature_value = CASE WHEN $8::boolean THEN
+    $9
+  ELSE
+    temperature_value
+  END,
+  temperature_unit = CASE WHEN $8::boolean THEN
+    $10
+  ELSE
+    temperature_unit
   END
 WHERE
   id = $1
@@ -1557,7 +4489,9 @@ RETURNING
   id,
   instruction,
   step_number,
-  image_key
+  image_key,
+  temperature_value,
+  temperature_unit
 `
 
 type UpdateRecipeStepParams struct {
@@ -1568,13 +4502,18 @@ type UpdateRecipeStepParams struct {
 	StepNumber        pgtype.Int4
 	UpdateImageKey    pgtype.Bool
 	ImageKey          pgtype.Text
+	UpdateTemperature pgtype.Bool
+	TemperatureValue  pgtype.Float4
+	TemperatureUnit   NullTemperatureUnit
 }
 
 type UpdateRecipeStepRow struct {
-	ID          int64
-	Instruction pgtype.Text
-	StepNumber  int32
-	ImageKey    pgtype.Text
+	ID               int64
+	Instruction      pgtype.Text
+	StepNumber       int32
+	ImageKey         pgtype.Text
+	TemperatureValue pgtype.Float4
+	TemperatureUnit  NullTemperatureUnit
 }
 
 func (q *Queries) UpdateRecipeStep(ctx context.Context, arg UpdateRecipeStepParams) (UpdateRecipeStepRow, error) {
@@ -1586,6 +4525,9 @@ func (q *Queries) UpdateRecipeStep(ctx context.Context, arg UpdateRecipeStepPara
 		arg.StepNumber,
 		arg.UpdateImageKey,
 		arg.ImageKey,
+		arg.UpdateTemperature,
+		arg.TemperatureValue,
+		arg.TemperatureUnit,
 	)
 	var i UpdateRecipeStepRow
 	err := row.Scan(
@@ -1593,6 +4535,8 @@ func (q *Queries) UpdateRecipeStep(ctx context.Context, arg UpdateRecipeStepPara
 		&i.Instruction,
 		&i.StepNumber,
 		&i.ImageKey,
+		&i.TemperatureValue,
+		&i.TemperatureUnit,
 	)
 	return i, err
 }
@@ -1616,6 +4560,44 @@ func (q *Queries) UpdateRecipeStepImage(ctx context.Context, arg UpdateRecipeSte
 	return err
 }
 
+const updateStockImageKey = `-- name: UpdateStockImageKey :exec
+UPDATE
+  stock_images
+SET
+  image_key = $1
+WHERE
+  id = $2
+`
+
+type UpdateStockImageKeyParams struct {
+	ImageKey string
+	ID       int64
+}
+
+func (q *Queries) UpdateStockImageKey(ctx context.Context, arg UpdateStockImageKeyParams) error {
+	_, err := q.db.Exec(ctx, updateStockImageKey, arg.ImageKey, arg.ID)
+	return err
+}
+
+const updateUndoTokenImage = `-- name: UpdateUndoTokenImage :exec
+UPDATE
+  undo_tokens
+SET
+  image_key = $1
+WHERE
+  token = $2
+`
+
+type UpdateUndoTokenImageParams struct {
+	ImageKey pgtype.Text
+	Token    string
+}
+
+func (q *Queries) UpdateUndoTokenImage(ctx context.Context, arg UpdateUndoTokenImageParams) error {
+	_, err := q.db.Exec(ctx, updateUndoTokenImage, arg.ImageKey, arg.Token)
+	return err
+}
+
 const updateUserPasswordHash = `-- name: UpdateUserPasswordHash :exec
 UPDATE
   users
@@ -1635,6 +4617,65 @@ func (q *Queries) UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPass
 	return err
 }
 
+const updateUserPreferences = `-- name: UpdateUserPreferences :one
+UPDATE
+  users
+SET
+  temperature_unit = CASE WHEN $1::boolean THEN
+    $2
+  ELSE
+    temperature_unit
+  END,
+  weekly_report = coalesce($3, weekly_report),
+  allergies = coalesce($4::text[], allergies),
+  time_zone = coalesce($5, time_zone),
+  weekly_report_due_at = coalesce($6, weekly_report_due_at)
+WHERE
+  id = $7
+RETURNING
+  temperature_unit,
+  weekly_report,
+  allergies,
+  time_zone
+`
+
+type UpdateUserPreferencesParams struct {
+	UpdateTemperatureUnit bool
+	TemperatureUnit       NullTemperatureUnit
+	WeeklyReport          pgtype.Bool
+	Allergies             []string
+	TimeZone              pgtype.Text
+	WeeklyReportDueAt     pgtype.Timestamptz
+	ID                    int64
+}
+
+type UpdateUserPreferencesRow struct {
+	TemperatureUnit NullTemperatureUnit
+	WeeklyReport    bool
+	Allergies       []string
+	TimeZone        string
+}
+
+func (q *Queries) UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) (UpdateUserPreferencesRow, error) {
+	row := q.db.QueryRow(ctx, updateUserPreferences,
+		arg.UpdateTemperatureUnit,
+		arg.TemperatureUnit,
+		arg.WeeklyReport,
+		arg.Allergies,
+		arg.TimeZone,
+		arg.WeeklyReportDueAt,
+		arg.ID,
+	)
+	var i UpdateUserPreferencesRow
+	err := row.Scan(
+		&i.TemperatureUnit,
+		&i.WeeklyReport,
+		&i.Allergies,
+		&i.TimeZone,
+	)
+	return i, err
+}
+
 const updateUserRefreshTokenHash = `-- name: UpdateUserRefreshTokenHash :exec
 UPDATE
   users
@@ -1653,3 +4694,113 @@ func (q *Queries) UpdateUserRefreshTokenHash(ctx context.Context, arg UpdateUser
 	_, err := q.db.Exec(ctx, updateUserRefreshTokenHash, arg.RefreshTokenHash, arg.ID)
 	return err
 }
+
+const upsertFederatedRecipe = `-- name: UpsertFederatedRecipe :execrows
+INSERT INTO federated_recipes (object_id, actor, author, url, title, description, ingredients, steps, image_url, published_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7::text[], $8::text[], $9, $10)
+ON CONFLICT (object_id)
+  DO UPDATE SET
+    author = EXCLUDED.author,
+    url = EXCLUDED.url,
+    title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    ingredients = EXCLUDED.ingredients,
+    steps = EXCLUDED.steps,
+    image_url = EXCLUDED.image_url,
+    published_at = EXCLUDED.published_at,
+    received_at = now()
+  WHERE
+    federated_recipes.actor = EXCLUDED.actor
+`
+
+type UpsertFederatedRecipeParams struct {
+	ObjectID    string
+	Actor       string
+	Author      string
+	Url         string
+	Title       string
+	Description pgtype.Text
+	Ingredients []string
+	Steps       []string
+	ImageUrl    pgtype.Text
+	PublishedAt pgtype.Timestamptz
+}
+
+func (q *Queries) UpsertFederatedRecipe(ctx context.Context, arg UpsertFederatedRecipeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertFederatedRecipe,
+		arg.ObjectID,
+		arg.Actor,
+		arg.Author,
+		arg.Url,
+		arg.Title,
+		arg.Description,
+		arg.Ingredients,
+		arg.Steps,
+		arg.ImageUrl,
+		arg.PublishedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertStockImage = `-- name: UpsertStockImage :one
+WITH old AS (
+  SELECT
+    image_key
+  FROM
+    stock_images
+  WHERE
+    ingredient = $1)
+INSERT INTO stock_images (ingredient, image_key, created_by)
+  VALUES ($1, $2, $3)
+ON CONFLICT (ingredient)
+  DO UPDATE SET
+    image_key = EXCLUDED.image_key,
+    created_by = EXCLUDED.created_by,
+    updated_at = now()
+  RETURNING
+    id,
+    ingredient,
+    image_key,
+    created_by,
+    created_at,
+    updated_at,
+    (
+      SELECT
+        image_key
+      FROM
+        old)::text AS old_image_key
+`
+
+type UpsertStockImageParams struct {
+	Ingredient string
+	ImageKey   string
+	CreatedBy  pgtype.Int8
+}
+
+type UpsertStockImageRow struct {
+	ID          int64
+	Ingredient  string
+	ImageKey    string
+	CreatedBy   pgtype.Int8
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	OldImageKey pgtype.Text
+}
+
+func (q *Queries) UpsertStockImage(ctx context.Context, arg UpsertStockImageParams) (UpsertStockImageRow, error) {
+	row := q.db.QueryRow(ctx, upsertStockImage, arg.Ingredient, arg.ImageKey, arg.CreatedBy)
+	var i UpsertStockImageRow
+	err := row.Scan(
+		&i.ID,
+		&i.Ingredient,
+		&i.ImageKey,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OldImageKey,
+	)
+	return i, err
+}
@@ -0,0 +1,297 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// RowLevelSecurityTables are the tables guarded by the row-level
+// security policies of migration 0009_row_level_security.
+var RowLevelSecurityTables = []string{
+	"recipes",
+	"recipe_ingredients",
+	"recipe_steps",
+	"recipe_tags",
+	"recipe_tag_suggestions",
+}
+
+const setActor = `SELECT set_config('wecook.user_id', $1, true), set_config('wecook.admin', $2, true),
+  set_config('wecook.system', $3, true)`
+
+type actorKeyType struct{}
+
+var actorKey actorKeyType
+
+// Actor is the user on whose behalf statements run. Under row-level
+// security an actor only sees their own and published recipes, and only
+// changes their own. Admins and the system see and change every recipe.
+type Actor struct {
+	UserID int64
+	Admin  bool
+	// System marks statements the backend runs on its own behalf, such as
+	// those of setup and background jobs.
+	System bool
+}
+
+// System is the actor of setup, maintenance commands, and background
+// jobs.
+var System = Actor{System: true}
+
+// Anonymous is the actor of requests without a signed-in user. No user
+// has ID 0, so it only sees published recipes.
+var Anonymous = Actor{}
+
+// WithActor returns a context whose statements run as actor. Statements
+// without an actor only see published recipes and change none, so one
+// that misses its actor fails closed.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromCtx returns the actor of ctx, if any.
+func ActorFromCtx(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey).(Actor)
+	return actor, ok
+}
+
+// TxDBTX is a connection that can also begin transactions, such as a
+// pgxpool.Pool.
+type TxDBTX interface {
+	DBTX
+	Pool
+}
+
+// RowLevelSecurity runs the statements of a context with an actor in a
+// transaction that first sets the actor, the equivalent of SET LOCAL, so
+// the row-level security policies can read it. Transactions begun
+// through it are also bound to the actor of their context.
+type RowLevelSecurity struct {
+	db TxDBTX
+}
+
+var (
+	_ DBTX = (*RowLevelSecurity)(nil)
+	_ Pool = (*RowLevelSecurity)(nil)
+)
+
+// WithRowLevelSecurity wraps db so that statements run as the actor of
+// their context.
+func WithRowLevelSecurity(db TxDBTX) *RowLevelSecurity {
+	return &RowLevelSecurity{db: db}
+}
+
+// Begin begins a transaction bound to the actor of ctx.
+func (r *RowLevelSecurity) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if actor, ok := ActorFromCtx(ctx); ok {
+		if err := bindActor(ctx, tx, actor); err != nil {
+			_ = tx.Rollback(ctx)
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+func (r *RowLevelSecurity) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if _, ok := ActorFromCtx(ctx); !ok {
+		return r.db.Exec(ctx, sql, args...)
+	}
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return tag, err
+	}
+	return tag, tx.Commit(ctx)
+}
+
+func (r *RowLevelSecurity) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if _, ok := ActorFromCtx(ctx); !ok {
+		return r.db.Query(ctx, sql, args...)
+	}
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, err
+	}
+	return &txRows{Rows: rows, ctx: ctx, tx: tx}, nil
+}
+
+func (r *RowLevelSecurity) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if _, ok := ActorFromCtx(ctx); !ok {
+		return r.db.QueryRow(ctx, sql, args...)
+	}
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return errRow{err: err}
+	}
+	return txRow{row: tx.QueryRow(ctx, sql, args...), ctx: ctx, tx: tx}
+}
+
+func (r *RowLevelSecurity) CopyFrom(ctx context.Context, tableName pgx.Identifier,
+	columnNames []string, rowSrc pgx.CopyFromSource,
+) (int64, error) {
+	if _, ok := ActorFromCtx(ctx); !ok {
+		return r.db.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	}
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, err := tx.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return n, err
+	}
+	return n, tx.Commit(ctx)
+}
+
+func (r *RowLevelSecurity) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults {
+	if _, ok := ActorFromCtx(ctx); !ok {
+		return r.db.SendBatch(ctx, batch)
+	}
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return errBatch{err: err}
+	}
+	return &txBatch{BatchResults: tx.SendBatch(ctx, batch), ctx: ctx, tx: tx}
+}
+
+func bindActor(ctx context.Context, tx pgx.Tx, actor Actor) error {
+	if _, err := tx.Exec(ctx, setActor, strconv.FormatInt(actor.UserID, 10),
+		strconv.FormatBool(actor.Admin), strconv.FormatBool(actor.System)); err != nil {
+		return fmt.Errorf("setting row-level security actor: %w", err)
+	}
+	return nil
+}
+
+// txRows commits its transaction once the rows are read.
+type txRows struct {
+	pgx.Rows
+	ctx  context.Context
+	tx   pgx.Tx
+	done bool
+	err  error
+}
+
+func (r *txRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.finish()
+	return false
+}
+
+func (r *txRows) Close() {
+	r.finish()
+}
+
+func (r *txRows) Err() error {
+	if err := r.Rows.Err(); err != nil {
+		return err
+	}
+	return r.err
+}
+
+func (r *txRows) finish() {
+	if r.done {
+		return
+	}
+	r.done = true
+	r.Rows.Close()
+	if r.Rows.Err() != nil {
+		_ = r.tx.Rollback(r.ctx)
+		return
+	}
+	r.err = r.tx.Commit(r.ctx)
+}
+
+// txRow commits its transaction once the row is scanned.
+type txRow struct {
+	row pgx.Row
+	ctx context.Context
+	tx  pgx.Tx
+}
+
+func (r txRow) Scan(dest ...any) error {
+	if err := r.row.Scan(dest...); err != nil {
+		_ = r.tx.Rollback(r.ctx)
+		return err
+	}
+	return r.tx.Commit(r.ctx)
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
+
+// txBatch commits its transaction once the results are closed.
+type txBatch struct {
+	pgx.BatchResults
+	ctx context.Context
+	tx  pgx.Tx
+}
+
+func (b *txBatch) Close() error {
+	if err := b.BatchResults.Close(); err != nil {
+		_ = b.tx.Rollback(b.ctx)
+		return err
+	}
+	return b.tx.Commit(b.ctx)
+}
+
+type errBatch struct {
+	err error
+}
+
+func (b errBatch) Exec() (pgconn.CommandTag, error) { return pgconn.CommandTag{}, b.err }
+func (b errBatch) Query() (pgx.Rows, error)         { return nil, b.err }
+func (b errBatch) QueryRow() pgx.Row                { return errRow{err: b.err} }
+func (b errBatch) Close() error                     { return b.err }
+
+// SetRowLevelSecurity enables or disables the row-level security
+// policies on RowLevelSecurityTables. Policies are forced so that they
+// also apply to the table owner the backend connects as. Tables already
+// in the requested state are left alone.
+func (db *Database) SetRowLevelSecurity(ctx context.Context, enabled bool) error {
+	action := "DISABLE ROW LEVEL SECURITY, NO FORCE ROW LEVEL SECURITY"
+	if enabled {
+		action = "ENABLE ROW LEVEL SECURITY, FORCE ROW LEVEL SECURITY"
+	}
+	for _, table := range RowLevelSecurityTables {
+		var current bool
+		err := db.db.QueryRow(ctx,
+			"SELECT relrowsecurity AND relforcerowsecurity FROM pg_class WHERE oid = $1::regclass",
+			table).Scan(&current)
+		if err != nil {
+			return fmt.Errorf("checking row-level security of %s: %w", table, err)
+		}
+		if current == enabled {
+			continue
+		}
+
+		stmt := "ALTER TABLE " + pgx.Identifier{table}.Sanitize() + " " + action
+		if _, err := db.db.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("altering row-level security of %s: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/matt-dz/wecook/internal/sql"
+)
+
+// testDatabaseURLEnv names the Postgres database the row-level security
+// compatibility suite runs against. The suite is skipped when it is unset.
+const testDatabaseURLEnv = "WECOOK_TEST_DATABASE_URL"
+
+type rlsFixture struct {
+	db        *Database
+	alice     int64
+	bob       int64
+	private   int64
+	published int64
+}
+
+// newRLSFixture applies the schema to a fresh Postgres schema with
+// row-level security enabled, holding a private and a published recipe
+// of alice.
+func newRLSFixture(t *testing.T) rlsFixture {
+	t.Helper()
+	url := os.Getenv(testDatabaseURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+	ctx := context.Background()
+
+	schema := fmt.Sprintf("wecook_rls_test_%d", time.Now().UnixNano())
+	admin, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatalf("connecting to database: %v", err)
+	}
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("creating schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE")
+		_ = admin.Close(ctx)
+	})
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("parsing database url: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("creating pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	db := NewDatabase(WithRowLevelSecurity(pool))
+	if _, err := pool.Exec(ctx, sql.Schema()); err != nil {
+		t.Fatalf("applying schema: %v", err)
+	}
+	if err := db.Migrate(ctx); err != nil {
+		t.Fatalf("migrating: %v", err)
+	}
+	if err := db.SetRowLevelSecurity(ctx, true); err != nil {
+		t.Fatalf("enabling row-level security: %v", err)
+	}
+
+	f := rlsFixture{db: db}
+	for _, user := range []struct {
+		id    *int64
+		email string
+	}{{&f.alice, "alice@example.com"}, {&f.bob, "bob@example.com"}} {
+		*user.id, err = db.CreateUser(ctx, CreateUserParams{
+			FirstName: "Test", LastName: "User", PasswordHash: "hash", Email: user.email,
+		})
+		if err != nil {
+			t.Fatalf("creating user: %v", err)
+		}
+	}
+
+	asAlice := WithActor(ctx, Actor{UserID: f.alice})
+	for _, recipe := range []*int64{&f.private, &f.published} {
+		*recipe, err = db.CreateRecipe(asAlice, CreateRecipeParams{
+			UserID: pgtype.Int8{Int64: f.alice, Valid: true},
+			Title:  "Soup",
+		})
+		if err != nil {
+			t.Fatalf("creating recipe: %v", err)
+		}
+		_, err = db.CreateRecipeIngredient(asAlice, CreateRecipeIngredientParams{
+			RecipeID:    *recipe,
+			Description: pgtype.Text{String: "Water", Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("creating ingredient: %v", err)
+		}
+	}
+	asSystem := WithActor(ctx, System)
+	if _, err := db.db.Exec(asSystem, "UPDATE recipes SET published = true WHERE id = $1", f.published); err != nil {
+		t.Fatalf("publishing recipe: %v", err)
+	}
+	return f
+}
+
+// visibleRecipes returns the recipes ctx can see with no ownership
+// check in the query, as a handler missing one would.
+func (f rlsFixture) visibleRecipes(t *testing.T, ctx context.Context) map[int64]bool {
+	t.Helper()
+	rows, err := f.db.db.Query(ctx, "SELECT id FROM recipes")
+	if err != nil {
+		t.Fatalf("querying recipes: %v", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		t.Fatalf("collecting recipes: %v", err)
+	}
+	visible := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		visible[id] = true
+	}
+	return visible
+}
+
+func TestRowLevelSecurityPostgres_Reads(t *testing.T) {
+	f := newRLSFixture(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		seesPrivate   bool
+		seesPublished bool
+	}{
+		{"owner", WithActor(ctx, Actor{UserID: f.alice}), true, true},
+		{"other user", WithActor(ctx, Actor{UserID: f.bob}), false, true},
+		{"anonymous", WithActor(ctx, Anonymous), false, true},
+		{"admin", WithActor(ctx, Actor{UserID: f.bob, Admin: true}), true, true},
+		{"system", WithActor(ctx, System), true, true},
+		{"no actor", ctx, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visible := f.visibleRecipes(t, tt.ctx)
+			if visible[f.private] != tt.seesPrivate || visible[f.published] != tt.seesPublished {
+				t.Errorf("expected private %v and published %v, got %v",
+					tt.seesPrivate, tt.seesPublished, visible)
+			}
+
+			_, err := f.db.GetRecipeAndOwner(tt.ctx, f.private)
+			if tt.seesPrivate && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if !tt.seesPrivate && !errors.Is(err, pgx.ErrNoRows) {
+				t.Errorf("expected %v, got %v", pgx.ErrNoRows, err)
+			}
+
+			ingredients, err := f.db.GetRecipeIngredients(tt.ctx, f.private)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (len(ingredients) > 0) != tt.seesPrivate {
+				t.Errorf("expected private ingredients visible %v, got %d", tt.seesPrivate, len(ingredients))
+			}
+		})
+	}
+}
+
+func TestRowLevelSecurityPostgres_Writes(t *testing.T) {
+	f := newRLSFixture(t)
+	ctx := context.Background()
+	asBob := WithActor(ctx, Actor{UserID: f.bob})
+
+	// Published recipes can be read but not changed by other users.
+	tag, err := f.db.db.Exec(asBob, "UPDATE recipes SET title = 'Mine' WHERE id = $1", f.published)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag.RowsAffected() != 0 {
+		t.Errorf("expected no recipes updated, got %d", tag.RowsAffected())
+	}
+	if err := f.db.DeleteRecipe(asBob, f.published); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tag, err = f.db.db.Exec(asBob, "DELETE FROM recipe_ingredients WHERE recipe_id = $1", f.published)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag.RowsAffected() != 0 {
+		t.Errorf("expected no ingredients deleted, got %d", tag.RowsAffected())
+	}
+
+	_, err = f.db.CreateRecipeIngredient(asBob, CreateRecipeIngredientParams{
+		RecipeID:    f.published,
+		Description: pgtype.Text{String: "Salt", Valid: true},
+	})
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "42501" {
+		t.Errorf("expected insufficient privilege error, got %v", err)
+	}
+
+	ingredients, err := f.db.GetRecipeIngredients(ctx, f.published)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ingredients) != 1 {
+		t.Errorf("expected recipe to be untouched, got %d ingredients", len(ingredients))
+	}
+
+	// Owners change their own recipes as before.
+	asAlice := WithActor(ctx, Actor{UserID: f.alice})
+	if err := f.db.DeleteRecipe(asAlice, f.private); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.visibleRecipes(t, WithActor(ctx, System))[f.private] {
+		t.Error("expected owner to delete private recipe")
+	}
+
+	// Statements without an actor change nothing.
+	tag, err = f.db.db.Exec(ctx, "UPDATE recipes SET title = 'Mine' WHERE id = $1", f.published)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag.RowsAffected() != 0 {
+		t.Errorf("expected no recipes updated without an actor, got %d", tag.RowsAffected())
+	}
+}
+
+func TestRowLevelSecurityPostgres_Transactions(t *testing.T) {
+	f := newRLSFixture(t)
+	ctx := WithActor(context.Background(), Actor{UserID: f.bob})
+
+	tx, err := f.db.db.(Pool).Begin(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := New(tx).GetRecipeAndOwner(ctx, f.private); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected %v, got %v", pgx.ErrNoRows, err)
+	}
+}
+
+func TestRowLevelSecurityPostgres_Disable(t *testing.T) {
+	f := newRLSFixture(t)
+	ctx := context.Background()
+
+	if err := f.db.SetRowLevelSecurity(ctx, false); err != nil {
+		t.Fatalf("disabling row-level security: %v", err)
+	}
+	// Disabling twice leaves the tables alone.
+	if err := f.db.SetRowLevelSecurity(ctx, false); err != nil {
+		t.Fatalf("disabling row-level security again: %v", err)
+	}
+
+	if !f.visibleRecipes(t, WithActor(ctx, Actor{UserID: f.bob}))[f.private] {
+		t.Error("expected policies to be lifted once disabled")
+	}
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeConn records the statements it runs and the fate of its
+// transactions.
+type fakeConn struct {
+	TxDBTX
+
+	statements []string
+	begun      int
+	committed  int
+	rolledBack int
+	execErr    error
+}
+
+func (c *fakeConn) Begin(context.Context) (pgx.Tx, error) {
+	c.begun++
+	return &fakeTx{conn: c}, nil
+}
+
+func (c *fakeConn) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	c.statements = append(c.statements, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (c *fakeConn) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	c.statements = append(c.statements, sql)
+	return fakeRow{}
+}
+
+type fakeTx struct {
+	pgx.Tx
+
+	conn *fakeConn
+}
+
+func (t *fakeTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	t.conn.statements = append(t.conn.statements, sql)
+	if sql == setActor {
+		t.conn.statements[len(t.conn.statements)-1] = sql + " " + args[0].(string) + " " +
+			args[1].(string) + " " + args[2].(string)
+		return pgconn.CommandTag{}, nil
+	}
+	return pgconn.CommandTag{}, t.conn.execErr
+}
+
+func (t *fakeTx) Query(_ context.Context, sql string, _ ...any) (pgx.Rows, error) {
+	t.conn.statements = append(t.conn.statements, sql)
+	return &fakeRows{remaining: 2}, nil
+}
+
+func (t *fakeTx) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	t.conn.statements = append(t.conn.statements, sql)
+	return fakeRow{err: t.conn.execErr}
+}
+
+func (t *fakeTx) Commit(context.Context) error {
+	t.conn.committed++
+	return nil
+}
+
+func (t *fakeTx) Rollback(context.Context) error {
+	t.conn.rolledBack++
+	return nil
+}
+
+type fakeRows struct {
+	pgx.Rows
+
+	remaining int
+}
+
+func (r *fakeRows) Next() bool {
+	if r.remaining == 0 {
+		return false
+	}
+	r.remaining--
+	return true
+}
+
+func (r *fakeRows) Close()     {}
+func (r *fakeRows) Err() error { return nil }
+
+type fakeRow struct {
+	err error
+}
+
+func (r fakeRow) Scan(...any) error { return r.err }
+
+func TestRowLevelSecurity_BindsActor(t *testing.T) {
+	conn := &fakeConn{}
+	db := WithRowLevelSecurity(conn)
+	ctx := WithActor(context.Background(), Actor{UserID: 42, Admin: true})
+
+	if _, err := db.Exec(ctx, "DELETE FROM recipes"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{setActor + " 42 true false", "DELETE FROM recipes"}
+	if !slices.Equal(conn.statements, want) {
+		t.Errorf("expected statements %q, got %q", want, conn.statements)
+	}
+	if conn.begun != 1 || conn.committed != 1 {
+		t.Errorf("expected one committed transaction, got %d begun and %d committed", conn.begun, conn.committed)
+	}
+}
+
+func TestRowLevelSecurity_BindsSystem(t *testing.T) {
+	conn := &fakeConn{}
+	db := WithRowLevelSecurity(conn)
+
+	if _, err := db.Exec(WithActor(context.Background(), System), "DELETE FROM recipes"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{setActor + " 0 false true", "DELETE FROM recipes"}
+	if !slices.Equal(conn.statements, want) {
+		t.Errorf("expected statements %q, got %q", want, conn.statements)
+	}
+}
+
+func TestRowLevelSecurity_WithoutActor(t *testing.T) {
+	conn := &fakeConn{}
+	db := WithRowLevelSecurity(conn)
+
+	if _, err := db.Exec(context.Background(), "DELETE FROM recipes"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := db.QueryRow(context.Background(), "SELECT 1").Scan(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"DELETE FROM recipes", "SELECT 1"}
+	if !slices.Equal(conn.statements, want) {
+		t.Errorf("expected statements %q, got %q", want, conn.statements)
+	}
+	if conn.begun != 0 {
+		t.Errorf("expected no transactions, got %d", conn.begun)
+	}
+}
+
+func TestRowLevelSecurity_CommitsAfterReading(t *testing.T) {
+	conn := &fakeConn{}
+	db := WithRowLevelSecurity(conn)
+	ctx := WithActor(context.Background(), Anonymous)
+
+	if err := db.QueryRow(ctx, "SELECT 1").Scan(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conn.committed != 1 {
+		t.Fatalf("expected row to commit after scan, got %d commits", conn.committed)
+	}
+
+	rows, err := db.Query(ctx, "SELECT 1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for rows.Next() {
+		if conn.committed != 1 {
+			t.Fatal("expected rows to commit only once read")
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conn.committed != 2 {
+		t.Errorf("expected rows to commit once, got %d commits", conn.committed-1)
+	}
+}
+
+func TestRowLevelSecurity_RollsBackOnError(t *testing.T) {
+	queryErr := errors.New("permission denied")
+	conn := &fakeConn{execErr: queryErr}
+	db := WithRowLevelSecurity(conn)
+	ctx := WithActor(context.Background(), Actor{UserID: 1})
+
+	if _, err := db.Exec(ctx, "UPDATE recipes SET title = ''"); !errors.Is(err, queryErr) {
+		t.Errorf("expected %v, got %v", queryErr, err)
+	}
+	if err := db.QueryRow(ctx, "SELECT 1").Scan(); !errors.Is(err, queryErr) {
+		t.Errorf("expected %v, got %v", queryErr, err)
+	}
+	if conn.committed != 0 || conn.rolledBack != 2 {
+		t.Errorf("expected two rollbacks and no commits, got %d and %d", conn.rolledBack, conn.committed)
+	}
+}
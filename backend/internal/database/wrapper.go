@@ -3,12 +3,23 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/matt-dz/wecook/internal/sql"
 )
 
+// migrationLockID is the advisory lock key held while applying
+// migrations so that concurrent instances do not race each other.
+const migrationLockID = 7_301_942
+
+const createSchemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version text PRIMARY KEY,
+  applied_at timestamptz NOT NULL DEFAULT now()
+)`
+
 type Pool interface {
 	Begin(ctx context.Context) (pgx.Tx, error)
 }
@@ -28,19 +39,160 @@ func NewDatabase(db DBTX) *Database {
 
 // EnsureSchema ensures the database schema is applied to the
 // Postgres database. The schema is applied to the database
-// if the schema is not detected.
+// if the schema is not detected, after which any pending
+// migrations are applied.
 func (db *Database) EnsureSchema(ctx context.Context) error {
 	exists, err := db.CheckUsersTableExists(ctx)
 	if err != nil {
 		return fmt.Errorf("ensuring schema exists: %w", err)
 	}
 
-	if exists {
+	if !exists {
+		if _, err := db.db.Exec(ctx, sql.Schema()); err != nil {
+			return fmt.Errorf("applying database schema: %w", err)
+		}
+	}
+
+	if err := db.Migrate(ctx); err != nil {
+		return fmt.Errorf("migrating database schema: %w", err)
+	}
+
+	return nil
+}
+
+// Migrate applies every embedded migration that has not yet been
+// recorded in the schema_migrations table. Each migration runs in
+// its own transaction.
+func (db *Database) Migrate(ctx context.Context) error {
+	pool, ok := db.db.(Pool)
+	if !ok {
+		return errors.New("database connection does not support transactions")
+	}
+
+	if _, err := db.db.Exec(ctx, createSchemaMigrationsTable); err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+
+	migrations, err := sql.Migrations()
+	if err != nil {
+		return fmt.Errorf("loading migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := applyMigration(ctx, pool, m); err != nil {
+			return fmt.Errorf("applying migration %q: %w", m.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// PendingMigrations returns the versions of the embedded migrations that
+// have not yet been recorded in the schema_migrations table, without
+// applying them.
+func (db *Database) PendingMigrations(ctx context.Context) ([]string, error) {
+	migrations, err := sql.Migrations()
+	if err != nil {
+		return nil, fmt.Errorf("loading migrations: %w", err)
+	}
+
+	var tracked bool
+	if err := db.db.QueryRow(ctx,
+		"SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&tracked); err != nil {
+		return nil, fmt.Errorf("checking schema_migrations table: %w", err)
+	}
+
+	applied := make(map[string]bool)
+	if tracked {
+		rows, err := db.db.Query(ctx, "SELECT version FROM schema_migrations")
+		if err != nil {
+			return nil, fmt.Errorf("getting applied migrations: %w", err)
+		}
+		versions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return nil, fmt.Errorf("getting applied migrations: %w", err)
+		}
+		for _, version := range versions {
+			applied[version] = true
+		}
+	}
+
+	var pending []string
+	for _, m := range migrations {
+		if !applied[m.Version] {
+			pending = append(pending, m.Version)
+		}
+	}
+	return pending, nil
+}
+
+func applyMigration(ctx context.Context, pool Pool, m sql.Migration) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Migrations may change rows of any user.
+	if err := bindActor(ctx, tx, System); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("acquiring migration lock: %w", err)
+	}
+
+	var applied bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.Version).Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("checking migration status: %w", err)
+	}
+	if applied {
 		return nil
 	}
 
-	if _, err := db.db.Exec(ctx, sql.Schema()); err != nil {
-		return fmt.Errorf("applying database schema: %w", err)
+	if _, err := tx.Exec(ctx, m.SQL); err != nil {
+		return fmt.Errorf("executing migration: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.Version); err != nil {
+		return fmt.Errorf("recording migration: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing migration: %w", err)
+	}
+
+	return nil
+}
+
+// Transactor runs a function against a Querier bound to a single
+// transaction.
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(q Querier) error) error
+}
+
+var _ Transactor = (*Database)(nil)
+
+// WithTx runs fn inside a transaction. The transaction is committed
+// if fn returns nil and rolled back otherwise.
+func (db *Database) WithTx(ctx context.Context, fn func(q Querier) error) error {
+	pool, ok := db.db.(Pool)
+	if !ok {
+		return errors.New("database connection does not support transactions")
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(New(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
 	}
 
 	return nil
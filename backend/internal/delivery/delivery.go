@@ -0,0 +1,399 @@
+// Package delivery records the emails, appliance webhooks, and federation
+// activities the backend sends, so operators can see whether they arrived
+// and retry the ones that did not.
+//
+// Every delivery is recorded before its first attempt. Queued deliveries,
+// such as weekly reports, are retried by the delivery job with growing
+// delays until MaxAttempts. Deliveries sent on behalf of a waiting request,
+// such as invites and appliance commands, report their failure to the
+// caller instead and are dead-lettered straight away. Failed and
+// dead-lettered deliveries can be retried by an admin.
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/matt-dz/wecook/internal/appliance"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/email"
+	"github.com/matt-dz/wecook/internal/env"
+	wcHttp "github.com/matt-dz/wecook/internal/http"
+	"github.com/matt-dz/wecook/internal/httpsig"
+)
+
+var (
+	ErrNotFound = errors.New("delivery not found")
+	// ErrNotRetryable is returned when retrying a delivery that was sent
+	// or is being sent.
+	ErrNotRetryable = errors.New("delivery is not failed")
+	// ErrNotDelivered wraps the last error of a failed attempt.
+	ErrNotDelivered = errors.New("delivery failed")
+	// ErrApplianceGone is recorded when the appliance of a webhook was
+	// deleted before a retry.
+	ErrApplianceGone = errors.New("appliance no longer exists")
+	// ErrKeyGone is recorded when the user of an activity was deleted
+	// before a retry.
+	ErrKeyGone = errors.New("federation key no longer exists")
+)
+
+// ActivityContentType is the media type activities are posted as.
+const ActivityContentType = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+// Kind is what is delivered.
+type Kind string
+
+const (
+	KindEmail   Kind = "email"
+	KindWebhook Kind = "webhook"
+	// KindActivity deliveries are ActivityPub activities posted to the
+	// inbox of a remote actor.
+	KindActivity Kind = "activity"
+)
+
+// Valid reports whether k is a known kind.
+func (k Kind) Valid() bool {
+	return k == KindEmail || k == KindWebhook || k == KindActivity
+}
+
+// Status is where a delivery is in its life.
+type Status string
+
+const (
+	// StatusQueued deliveries are being attempted.
+	StatusQueued Status = "queued"
+	StatusSent   Status = "sent"
+	// StatusFailed deliveries are waiting for the delivery job to retry
+	// them.
+	StatusFailed Status = "failed"
+	// StatusDead deliveries are not retried unless an admin asks to.
+	StatusDead Status = "dead"
+)
+
+// Valid reports whether s is a known status.
+func (s Status) Valid() bool {
+	switch s {
+	case StatusQueued, StatusSent, StatusFailed, StatusDead:
+		return true
+	}
+	return false
+}
+
+const (
+	// MaxAttempts is how many times a queued delivery is attempted before
+	// it is dead-lettered.
+	MaxAttempts = 5
+	// RetryDelay is the delay before the first retry. It doubles with
+	// every further attempt.
+	RetryDelay = time.Minute
+)
+
+// emailPayload is what is needed to send an email again.
+type emailPayload struct {
+	To      []string       `json:"to"`
+	Body    string         `json:"body"`
+	Headers []email.Header `json:"headers,omitempty"`
+}
+
+// activityPayload is what is needed to post an activity again.
+type activityPayload struct {
+	UserID   int64           `json:"user_id"`
+	KeyID    string          `json:"key_id"`
+	Inbox    string          `json:"inbox"`
+	Activity json.RawMessage `json:"activity"`
+}
+
+// SendEmail records an email and sends it. A failed email is
+// dead-lettered and its error returned.
+func SendEmail(ctx context.Context, env *env.Env, to []string, subject, body string) error {
+	d, err := createEmail(ctx, env, to, subject, body, nil, false)
+	if err != nil {
+		return err
+	}
+	return result(attempt(ctx, env, d))
+}
+
+// QueueEmail records an email and sends it with any extra headers. A
+// failed email is retried by the delivery job, so only errors recording it
+// are returned.
+func QueueEmail(ctx context.Context, env *env.Env, to []string, subject, body string,
+	headers ...email.Header,
+) error {
+	d, err := createEmail(ctx, env, to, subject, body, headers, true)
+	if err != nil {
+		return err
+	}
+	d, err = attempt(ctx, env, d)
+	if err != nil {
+		return err
+	}
+	if Status(d.Status) == StatusFailed {
+		env.Logger.WarnContext(ctx, "email failed, will retry",
+			slog.Int64("delivery_id", d.ID), slog.String("error", d.LastError.String))
+	}
+	return nil
+}
+
+// SendCommand records an appliance command and sends it through
+// provider. Commands the appliance does not allow are rejected without
+// being recorded. A failed command is dead-lettered and its error
+// returned.
+func SendCommand(ctx context.Context, env *env.Env, provider appliance.Provider,
+	target appliance.Appliance, command appliance.Command,
+) error {
+	if err := appliance.Check(target, command); err != nil {
+		return err
+	}
+	payload, err := json.Marshal(appliance.NewWebhookPayload(target, command))
+	if err != nil {
+		return fmt.Errorf("encoding command: %w", err)
+	}
+	d, err := env.Database.CreateDelivery(ctx, database.CreateDeliveryParams{
+		Kind:      string(KindWebhook),
+		Recipient: strconv.FormatInt(target.ID, 10),
+		Subject:   string(command.Action),
+		Payload:   payload,
+		Now:       pgtype.Timestamptz{Time: env.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("recording delivery: %w", err)
+	}
+
+	return result(record(ctx, env, d, provider.Send(ctx, target, command)))
+}
+
+// QueueActivity records an ActivityPub activity of a user and posts it to
+// inbox, signed with the user's federation key identified by keyID. A
+// failed activity is retried by the delivery job, so only errors recording
+// it are returned.
+func QueueActivity(ctx context.Context, env *env.Env, userID int64, keyID, inbox, activityType string,
+	activity []byte,
+) error {
+	payload, err := json.Marshal(activityPayload{
+		UserID:   userID,
+		KeyID:    keyID,
+		Inbox:    inbox,
+		Activity: activity,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding activity: %w", err)
+	}
+	d, err := env.Database.CreateDelivery(ctx, database.CreateDeliveryParams{
+		Kind:      string(KindActivity),
+		Recipient: inbox,
+		Subject:   activityType,
+		Payload:   payload,
+		Retry:     true,
+		Now:       pgtype.Timestamptz{Time: env.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("recording delivery: %w", err)
+	}
+	d, err = attempt(ctx, env, d)
+	if err != nil {
+		return err
+	}
+	if Status(d.Status) == StatusFailed {
+		env.Logger.WarnContext(ctx, "activity failed, will retry",
+			slog.Int64("delivery_id", d.ID), slog.String("error", d.LastError.String))
+	}
+	return nil
+}
+
+// Retry attempts a failed or dead-lettered delivery now and returns it
+// with the outcome.
+func Retry(ctx context.Context, env *env.Env, id int64) (database.Delivery, error) {
+	d, err := env.Database.ClaimDelivery(ctx, database.ClaimDeliveryParams{
+		ID:  id,
+		Now: pgtype.Timestamptz{Time: env.Now(), Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := env.Database.GetDelivery(ctx, id); errors.Is(err, pgx.ErrNoRows) {
+			return database.Delivery{}, ErrNotFound
+		} else if err != nil {
+			return database.Delivery{}, fmt.Errorf("getting delivery: %w", err)
+		}
+		return database.Delivery{}, ErrNotRetryable
+	}
+	if err != nil {
+		return database.Delivery{}, fmt.Errorf("claiming delivery: %w", err)
+	}
+	return attempt(ctx, env, d)
+}
+
+func createEmail(ctx context.Context, env *env.Env, to []string, subject, body string,
+	headers []email.Header, retry bool,
+) (database.Delivery, error) {
+	payload, err := json.Marshal(emailPayload{To: to, Body: body, Headers: headers})
+	if err != nil {
+		return database.Delivery{}, fmt.Errorf("encoding email: %w", err)
+	}
+	d, err := env.Database.CreateDelivery(ctx, database.CreateDeliveryParams{
+		Kind:      string(KindEmail),
+		Recipient: strings.Join(to, ", "),
+		Subject:   subject,
+		Payload:   payload,
+		Retry:     retry,
+		Now:       pgtype.Timestamptz{Time: env.Now(), Valid: true},
+	})
+	if err != nil {
+		return database.Delivery{}, fmt.Errorf("recording delivery: %w", err)
+	}
+	return d, nil
+}
+
+// attempt sends a claimed delivery and records the outcome.
+func attempt(ctx context.Context, env *env.Env, d database.Delivery) (database.Delivery, error) {
+	env.Logger.DebugContext(ctx, "attempting delivery",
+		slog.Int64("delivery_id", d.ID), slog.String("kind", d.Kind))
+	return record(ctx, env, d, send(ctx, env, d))
+}
+
+func send(ctx context.Context, env *env.Env, d database.Delivery) error {
+	switch Kind(d.Kind) {
+	case KindEmail:
+		var payload emailPayload
+		if err := json.Unmarshal(d.Payload, &payload); err != nil {
+			return fmt.Errorf("decoding email: %w", err)
+		}
+		return env.SMTP.Send(payload.To, d.Subject, payload.Body, payload.Headers...)
+	case KindWebhook:
+		var payload appliance.WebhookPayload
+		if err := json.Unmarshal(d.Payload, &payload); err != nil {
+			return fmt.Errorf("decoding command: %w", err)
+		}
+		stored, err := env.Database.GetApplianceByID(ctx, payload.ApplianceID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrApplianceGone
+		} else if err != nil {
+			return fmt.Errorf("getting appliance: %w", err)
+		}
+		provider, err := appliance.NewProvider(appliance.ProviderName(stored.Provider), env.HTTP)
+		if err != nil {
+			return err
+		}
+		target := appliance.Appliance{
+			ID:       stored.ID,
+			Name:     stored.Name,
+			Endpoint: stored.Endpoint,
+			Token:    stored.Token,
+		}
+		for _, scope := range stored.Scopes {
+			target.Scopes = append(target.Scopes, appliance.Action(scope))
+		}
+		return appliance.Send(ctx, provider, target, payload.Command())
+	case KindActivity:
+		var payload activityPayload
+		if err := json.Unmarshal(d.Payload, &payload); err != nil {
+			return fmt.Errorf("decoding activity: %w", err)
+		}
+		return postActivity(ctx, env, payload)
+	default:
+		return fmt.Errorf("unknown delivery kind %q", d.Kind)
+	}
+}
+
+// postActivity signs an activity with the key of its user and posts it to
+// its inbox.
+func postActivity(ctx context.Context, env *env.Env, payload activityPayload) error {
+	stored, err := env.Database.GetFederationKey(ctx, payload.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrKeyGone
+	} else if err != nil {
+		return fmt.Errorf("getting federation key: %w", err)
+	}
+	key, err := httpsig.ParsePrivateKey(stored.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, payload.Inbox, []byte(payload.Activity))
+	if err != nil {
+		return fmt.Errorf("creating activity request: %w", err)
+	}
+	req.Header.Set("Content-Type", ActivityContentType)
+	if err := httpsig.Sign(req.Request, payload.KeyID, key, payload.Activity, env.Now()); err != nil {
+		return err
+	}
+
+	res, err := env.FederationHTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting activity: %w", err)
+	}
+	if err := wcHttp.ExpectStatus2xx(res); err != nil {
+		return fmt.Errorf("inbox rejected activity: %w", err)
+	}
+	return res.Body.Close()
+}
+
+// record stores the outcome of an attempt and returns the delivery as
+// updated. Only errors storing a failure are returned; a failed attempt is
+// reported through the delivery's status.
+func record(ctx context.Context, env *env.Env, d database.Delivery, sendErr error) (database.Delivery, error) {
+	now := pgtype.Timestamptz{Time: env.Now(), Valid: true}
+	d.Attempts++
+	d.UpdatedAt = now
+
+	if sendErr == nil {
+		if err := env.Database.MarkDeliverySent(ctx, database.MarkDeliverySentParams{
+			ID:  d.ID,
+			Now: now,
+		}); err != nil {
+			// The message went out; failing the caller would only invite
+			// sending it twice.
+			env.Logger.ErrorContext(ctx, "failed to mark delivery sent",
+				slog.Int64("delivery_id", d.ID), slog.Any("error", err))
+		}
+		d.Status = string(StatusSent)
+		d.LastError = pgtype.Text{}
+		d.NextAttemptAt = pgtype.Timestamptz{}
+		d.SentAt = now
+		return d, nil
+	}
+
+	d.Status = string(StatusDead)
+	d.LastError = pgtype.Text{String: sendErr.Error(), Valid: true}
+	d.NextAttemptAt = pgtype.Timestamptz{}
+	if d.Retry && d.Attempts < MaxAttempts {
+		d.Status = string(StatusFailed)
+		d.NextAttemptAt = pgtype.Timestamptz{Time: now.Time.Add(Backoff(int(d.Attempts))), Valid: true}
+	}
+	if err := env.Database.MarkDeliveryFailed(ctx, database.MarkDeliveryFailedParams{
+		ID:            d.ID,
+		Status:        d.Status,
+		LastError:     d.LastError,
+		NextAttemptAt: d.NextAttemptAt,
+		Now:           now,
+	}); err != nil {
+		return d, fmt.Errorf("marking delivery %d failed: %w", d.ID, err)
+	}
+	return d, nil
+}
+
+// result returns the error of an attempt, if any.
+func result(d database.Delivery, err error) error {
+	if err != nil {
+		return err
+	}
+	if Status(d.Status) != StatusSent {
+		return fmt.Errorf("%w: %s", ErrNotDelivered, d.LastError.String)
+	}
+	return nil
+}
+
+// Backoff returns the delay before retrying a delivery that failed
+// attempts times.
+func Backoff(attempts int) time.Duration {
+	return RetryDelay << (max(attempts, 1) - 1)
+}
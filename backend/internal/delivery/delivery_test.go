@@ -0,0 +1,297 @@
+package delivery
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/clock"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/email"
+	"github.com/matt-dz/wecook/internal/env"
+	wcHttp "github.com/matt-dz/wecook/internal/http"
+	"github.com/matt-dz/wecook/internal/httpsig"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+var now = time.Date(2026, time.April, 6, 9, 0, 0, 0, time.UTC)
+
+func newEnv(mockDB *database.MockQuerier, mockSMTP *email.MockSender) *env.Env {
+	return &env.Env{
+		Logger:   log.NullLogger(),
+		Database: &database.Database{Querier: mockDB},
+		SMTP:     mockSMTP,
+		Clock:    clock.NewFrozen(now),
+	}
+}
+
+// expectCreate records the delivery a test creates.
+func expectCreate(mockDB *database.MockQuerier, id int64) {
+	mockDB.EXPECT().
+		CreateDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params database.CreateDeliveryParams) (database.Delivery, error) {
+			return database.Delivery{
+				ID:        id,
+				Kind:      params.Kind,
+				Recipient: params.Recipient,
+				Subject:   params.Subject,
+				Payload:   params.Payload,
+				Status:    string(StatusQueued),
+				Retry:     params.Retry,
+			}, nil
+		})
+}
+
+func TestSendEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockSMTP := email.NewMockSender(ctrl)
+
+	expectCreate(mockDB, 1)
+	mockSMTP.EXPECT().Send([]string{"ada@example.com"}, "Hello", "Hi Ada").Return(nil)
+	mockDB.EXPECT().
+		MarkDeliverySent(gomock.Any(), database.MarkDeliverySentParams{
+			ID:  1,
+			Now: pgtype.Timestamptz{Time: now, Valid: true},
+		}).
+		Return(nil)
+
+	if err := SendEmail(context.Background(), newEnv(mockDB, mockSMTP),
+		[]string{"ada@example.com"}, "Hello", "Hi Ada"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSendEmail_DeadLettersFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockSMTP := email.NewMockSender(ctrl)
+
+	expectCreate(mockDB, 1)
+	mockSMTP.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("mailbox full"))
+	mockDB.EXPECT().
+		MarkDeliveryFailed(gomock.Any(), database.MarkDeliveryFailedParams{
+			ID:        1,
+			Status:    string(StatusDead),
+			LastError: pgtype.Text{String: "mailbox full", Valid: true},
+			Now:       pgtype.Timestamptz{Time: now, Valid: true},
+		}).
+		Return(nil)
+
+	err := SendEmail(context.Background(), newEnv(mockDB, mockSMTP), []string{"ada@example.com"}, "Hello", "Hi")
+	if !errors.Is(err, ErrNotDelivered) {
+		t.Errorf("expected %v, got %v", ErrNotDelivered, err)
+	}
+}
+
+func TestQueueEmail_SchedulesRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockSMTP := email.NewMockSender(ctrl)
+
+	expectCreate(mockDB, 1)
+	mockSMTP.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+	mockDB.EXPECT().
+		MarkDeliveryFailed(gomock.Any(), database.MarkDeliveryFailedParams{
+			ID:            1,
+			Status:        string(StatusFailed),
+			LastError:     pgtype.Text{String: "connection refused", Valid: true},
+			NextAttemptAt: pgtype.Timestamptz{Time: now.Add(RetryDelay), Valid: true},
+			Now:           pgtype.Timestamptz{Time: now, Valid: true},
+		}).
+		Return(nil)
+
+	if err := QueueEmail(context.Background(), newEnv(mockDB, mockSMTP),
+		[]string{"ada@example.com"}, "Hello", "Hi"); err != nil {
+		t.Errorf("expected failed send to be left to the job, got %v", err)
+	}
+}
+
+func TestQueueActivity_SignsRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	privateKey, publicKey, err := httpsig.GenerateKey()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	const keyID = "https://a.example/federation/users/7#main-key"
+	activity := []byte(`{"type":"Create"}`)
+
+	var verified bool
+	inbox := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if _, err := httpsig.Verify(r, body, now, func(string) (*rsa.PublicKey, error) {
+			return httpsig.ParsePublicKey(publicKey)
+		}); err != nil {
+			t.Errorf("verifying signature: %v", err)
+		}
+		verified = string(body) == string(activity)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer inbox.Close()
+
+	expectCreate(mockDB, 1)
+	mockDB.EXPECT().
+		GetFederationKey(gomock.Any(), int64(7)).
+		Return(database.FederationKey{UserID: 7, PrivateKey: privateKey, PublicKey: publicKey}, nil)
+	mockDB.EXPECT().MarkDeliverySent(gomock.Any(), gomock.Any()).Return(nil)
+
+	e := newEnv(mockDB, nil)
+	e.FederationHTTP = retryablehttp.NewClient()
+	if err := QueueActivity(context.Background(), e, 7, keyID, inbox.URL+"/inbox", "Create",
+		activity); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !verified {
+		t.Error("expected the inbox to receive the activity")
+	}
+}
+
+func TestQueueActivity_RefusesNonPublicInbox(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	privateKey, publicKey, err := httpsig.GenerateKey()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	var posted bool
+	inbox := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted = true
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer inbox.Close()
+
+	expectCreate(mockDB, 1)
+	mockDB.EXPECT().
+		GetFederationKey(gomock.Any(), int64(7)).
+		Return(database.FederationKey{UserID: 7, PrivateKey: privateKey, PublicKey: publicKey}, nil)
+	mockDB.EXPECT().
+		MarkDeliveryFailed(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params database.MarkDeliveryFailedParams) error {
+			if !strings.Contains(params.LastError.String, wcHttp.ErrForbiddenAddress.Error()) {
+				t.Errorf("expected forbidden address error, got %q", params.LastError.String)
+			}
+			return nil
+		})
+
+	e := newEnv(mockDB, nil)
+	e.FederationHTTP = wcHttp.NewGuarded(wcHttp.PublicAddress, time.Second)
+	if err := QueueActivity(context.Background(), e, 7, "https://a.example/federation/users/7#main-key",
+		inbox.URL+"/inbox", "Accept", []byte(`{"type":"Accept"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if posted {
+		t.Error("expected the loopback inbox not to be posted to")
+	}
+}
+
+func TestRetryDue_DeadLettersAfterMaxAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockSMTP := email.NewMockSender(ctrl)
+
+	mockDB.EXPECT().
+		ClaimDueDeliveries(gomock.Any(), gomock.Any()).
+		Return([]database.Delivery{
+			{ID: 1, Kind: "email", Payload: []byte(`{"to":["a@example.com"],"body":"a"}`), Retry: true, Attempts: 1},
+			{ID: 2, Kind: "email", Payload: []byte(`{"to":["b@example.com"],"body":"b"}`), Retry: true,
+				Attempts: MaxAttempts - 1},
+		}, nil)
+	mockDB.EXPECT().ClaimDueDeliveries(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockSMTP.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("timeout")).Times(2)
+
+	statuses := map[int64]database.MarkDeliveryFailedParams{}
+	mockDB.EXPECT().
+		MarkDeliveryFailed(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params database.MarkDeliveryFailedParams) error {
+			statuses[params.ID] = params
+			return nil
+		}).
+		Times(2)
+
+	if err := RetryDue(context.Background(), newEnv(mockDB, mockSMTP)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := statuses[1]; got.Status != string(StatusFailed) || !got.NextAttemptAt.Time.Equal(now.Add(2*RetryDelay)) {
+		t.Errorf("expected second attempt to be retried in %s, got %+v", 2*RetryDelay, got)
+	}
+	if got := statuses[2]; got.Status != string(StatusDead) || got.NextAttemptAt.Valid {
+		t.Errorf("expected last attempt to be dead-lettered, got %+v", got)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().ClaimDelivery(gomock.Any(), gomock.Any()).Return(database.Delivery{}, pgx.ErrNoRows)
+		mockDB.EXPECT().GetDelivery(gomock.Any(), int64(9)).Return(database.Delivery{}, pgx.ErrNoRows)
+
+		if _, err := Retry(context.Background(), newEnv(mockDB, nil), 9); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected %v, got %v", ErrNotFound, err)
+		}
+	})
+
+	t.Run("already sent", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().ClaimDelivery(gomock.Any(), gomock.Any()).Return(database.Delivery{}, pgx.ErrNoRows)
+		mockDB.EXPECT().GetDelivery(gomock.Any(), int64(9)).Return(database.Delivery{ID: 9, Status: "sent"}, nil)
+
+		if _, err := Retry(context.Background(), newEnv(mockDB, nil), 9); !errors.Is(err, ErrNotRetryable) {
+			t.Errorf("expected %v, got %v", ErrNotRetryable, err)
+		}
+	})
+
+	t.Run("webhook of deleted appliance", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := database.NewMockQuerier(ctrl)
+		mockDB.EXPECT().
+			ClaimDelivery(gomock.Any(), database.ClaimDeliveryParams{
+				ID:  9,
+				Now: pgtype.Timestamptz{Time: now, Valid: true},
+			}).
+			Return(database.Delivery{
+				ID:       9,
+				Kind:     "webhook",
+				Payload:  []byte(`{"appliance_id":4,"action":"set_timer","duration_seconds":60}`),
+				Status:   "queued",
+				Attempts: 1,
+			}, nil)
+		mockDB.EXPECT().GetApplianceByID(gomock.Any(), int64(4)).Return(database.Appliance{}, pgx.ErrNoRows)
+		mockDB.EXPECT().MarkDeliveryFailed(gomock.Any(), gomock.Any()).Return(nil)
+
+		d, err := Retry(context.Background(), newEnv(mockDB, nil), 9)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Status != string(StatusDead) || d.Attempts != 2 || d.LastError.String != ErrApplianceGone.Error() {
+			t.Errorf("unexpected delivery %+v", d)
+		}
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		0: RetryDelay,
+		1: RetryDelay,
+		2: 2 * RetryDelay,
+		4: 8 * RetryDelay,
+	} {
+		if got := Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
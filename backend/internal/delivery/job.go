@@ -0,0 +1,75 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+)
+
+const (
+	// DefaultInterval is how often due retries are looked for.
+	DefaultInterval = time.Minute
+	// Retention is how long sent deliveries are kept. Failed and
+	// dead-lettered deliveries are kept until they are sent.
+	Retention = 30 * 24 * time.Hour
+	batchSize = 100
+)
+
+// RunRetryJob retries due deliveries and removes old sent ones every
+// interval until ctx is cancelled.
+func RunRetryJob(ctx context.Context, env *env.Env, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		env.Logger.DebugContext(ctx, "retrying deliveries")
+		if err := RetryDue(ctx, env); err != nil {
+			env.Logger.ErrorContext(ctx, "failed to retry deliveries", slog.Any("error", err))
+		}
+		if err := env.Database.DeleteSentDeliveriesBefore(ctx, pgtype.Timestamptz{
+			Time:  env.Now().Add(-Retention),
+			Valid: true,
+		}); err != nil {
+			env.Logger.ErrorContext(ctx, "failed to delete old deliveries", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RetryDue attempts every failed delivery whose retry is due.
+func RetryDue(ctx context.Context, env *env.Env) error {
+	for {
+		deliveries, err := env.Database.ClaimDueDeliveries(ctx, database.ClaimDueDeliveriesParams{
+			Now:       pgtype.Timestamptz{Time: env.Now(), Valid: true},
+			BatchSize: batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("claiming due deliveries: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		for _, d := range deliveries {
+			d, err := attempt(ctx, env, d)
+			if err != nil {
+				return err
+			}
+			if Status(d.Status) == StatusDead {
+				env.Logger.WarnContext(ctx, "delivery dead-lettered",
+					slog.Int64("delivery_id", d.ID), slog.String("error", d.LastError.String))
+			}
+		}
+	}
+}
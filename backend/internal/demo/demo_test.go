@@ -0,0 +1,123 @@
+package demo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/config"
+	"github.com/matt-dz/wecook/internal/database"
+)
+
+func TestAPIKey(t *testing.T) {
+	secret := config.AppSecretValue("0123456789abcdef0123456789abcdef")
+	other := config.AppSecretValue("fedcba9876543210fedcba9876543210")
+
+	conf := config.Config{AppSecret: config.AppSecret{Value: &secret}}
+	key := APIKey(conf)
+	if !strings.HasPrefix(key, KeyPrefix) {
+		t.Errorf("APIKey() = %q, want prefix %q", key, KeyPrefix)
+	}
+	if APIKey(conf) != key {
+		t.Error("expected derived key to be stable")
+	}
+	if APIKey(config.Config{AppSecret: config.AppSecret{Value: &other}}) == key {
+		t.Error("expected derived key to depend on the app secret")
+	}
+
+	conf.Demo.APIKey = "demo_try-me"
+	if got := APIKey(conf); got != "demo_try-me" {
+		t.Errorf("APIKey() = %q, want configured key", got)
+	}
+
+	sandbox := &Sandbox{APIKey: key}
+	if !sandbox.Matches(key) || sandbox.Matches(key+"x") || sandbox.Matches("") {
+		t.Error("expected Matches to accept only the sandbox key")
+	}
+}
+
+func TestCtx(t *testing.T) {
+	if FromCtx(context.Background()) {
+		t.Error("expected plain context not to be a sandbox request")
+	}
+	if !FromCtx(WithCtx(context.Background())) {
+		t.Error("expected marked context to be a sandbox request")
+	}
+}
+
+func TestSeed_AlreadySeeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().GetUser(gomock.Any(), UserEmail).Return(database.GetUserRow{ID: 7}, nil)
+
+	userID, err := Seed(context.Background(), mockDB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userID != 7 {
+		t.Errorf("Seed() = %d, want 7", userID)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+
+	var ingredients, steps, tags int
+	for _, recipe := range Recipes {
+		ingredients += len(recipe.Ingredients)
+		steps += len(recipe.Steps)
+		tags += len(recipe.Tags)
+	}
+
+	mockDB.EXPECT().GetUser(gomock.Any(), UserEmail).Return(database.GetUserRow{}, pgx.ErrNoRows)
+	mockDB.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg database.CreateUserParams) (int64, error) {
+			if arg.Email != UserEmail || arg.PasswordHash == "" {
+				t.Errorf("unexpected user %+v", arg)
+			}
+			return 7, nil
+		})
+	mockDB.EXPECT().CreateRecipe(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg database.CreateRecipeParams) (int64, error) {
+			if arg.UserID.Int64 != 7 {
+				t.Errorf("expected recipe of user 7, got %+v", arg.UserID)
+			}
+			return 1, nil
+		}).Times(len(Recipes))
+	mockDB.EXPECT().UpdateRecipe(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg database.UpdateRecipeParams) (database.UpdateRecipeRow, error) {
+			if !arg.Published.Bool {
+				t.Error("expected example recipe to be published")
+			}
+			return database.UpdateRecipeRow{}, nil
+		}).Times(len(Recipes))
+	mockDB.EXPECT().CreateRecipeIngredient(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(ingredients)
+	mockDB.EXPECT().CreateRecipeStep(gomock.Any(), gomock.Any()).
+		Return(database.CreateRecipeStepRow{}, nil).Times(steps)
+	mockDB.EXPECT().AddRecipeTag(gomock.Any(), gomock.Any()).Return(nil).Times(tags)
+
+	userID, err := Seed(context.Background(), mockDB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userID != 7 {
+		t.Errorf("Seed() = %d, want 7", userID)
+	}
+}
+
+func TestSeed_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().GetUser(gomock.Any(), UserEmail).Return(database.GetUserRow{}, pgx.ErrNoRows)
+	mockDB.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(int64(7), nil)
+	mockDB.EXPECT().CreateRecipe(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("database error"))
+
+	if _, err := Seed(context.Background(), mockDB); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
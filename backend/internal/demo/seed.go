@@ -0,0 +1,236 @@
+package demo
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/matt-dz/wecook/internal/argon2id"
+	"github.com/matt-dz/wecook/internal/database"
+)
+
+// UserEmail is the email of the example user. The user's password is
+// random and discarded, so nobody can sign in as it.
+const UserEmail = "demo@wecook.invalid"
+
+// Recipe is an example recipe.
+type Recipe struct {
+	Title       string
+	Description string
+	PrepMinutes int32
+	CookMinutes int32
+	Servings    float32
+	Ingredients []string
+	Steps       []string
+	Tags        []string
+}
+
+// Recipes are the example recipes seeded into the sandbox. They are
+// published, so they also show up in the public recipe endpoints.
+var Recipes = []Recipe{
+	{
+		Title:       "Shakshuka",
+		Description: "Eggs poached in a spiced tomato and pepper sauce.",
+		PrepMinutes: 10,
+		CookMinutes: 25,
+		Servings:    4,
+		Ingredients: []string{
+			"2 tbsp olive oil",
+			"1 onion, diced",
+			"1 red bell pepper, diced",
+			"3 cloves garlic, minced",
+			"1 tsp ground cumin",
+			"1 tsp smoked paprika",
+			"28 oz crushed tomatoes",
+			"6 eggs",
+			"1/4 cup crumbled feta",
+		},
+		Steps: []string{
+			"Warm the olive oil in a wide pan over medium heat and soften the onion and pepper for 6 minutes.",
+			"Stir in the garlic, cumin, and paprika and cook for 1 minute.",
+			"Add the tomatoes and simmer for 10 minutes until thickened.",
+			"Make six wells in the sauce, crack an egg into each, cover, and cook for 6 minutes.",
+			"Scatter the feta over the top and serve from the pan.",
+		},
+		Tags: []string{"breakfast", "vegetarian"},
+	},
+	{
+		Title:       "Red Lentil Dal",
+		Description: "A quick, warming dal finished with a spiced butter.",
+		PrepMinutes: 5,
+		CookMinutes: 30,
+		Servings:    4,
+		Ingredients: []string{
+			"1 cup red lentils, rinsed",
+			"4 cups water",
+			"1 tsp ground turmeric",
+			"1 tsp salt",
+			"2 tbsp ghee",
+			"1 tsp cumin seeds",
+			"2 cloves garlic, sliced",
+			"1 lemon",
+		},
+		Steps: []string{
+			"Simmer the lentils, water, and turmeric for 25 minutes, stirring now and then, until soft.",
+			"Season with the salt.",
+			"Heat the ghee in a small pan and fry the cumin seeds and garlic until golden.",
+			"Pour the spiced butter over the dal and squeeze in the lemon.",
+		},
+		Tags: []string{"dinner", "vegetarian", "gluten-free"},
+	},
+	{
+		Title:       "Weeknight Spaghetti Aglio e Olio",
+		Description: "Spaghetti tossed with garlic, chili, and olive oil.",
+		PrepMinutes: 5,
+		CookMinutes: 15,
+		Servings:    2,
+		Ingredients: []string{
+			"8 oz spaghetti",
+			"1/3 cup olive oil",
+			"6 cloves garlic, thinly sliced",
+			"1/2 tsp red pepper flakes",
+			"1/4 cup chopped parsley",
+			"1/4 cup grated parmesan",
+		},
+		Steps: []string{
+			"Cook the spaghetti in salted water until al dente, keeping a cup of the cooking water.",
+			"Gently fry the garlic and pepper flakes in the olive oil until the garlic is pale gold.",
+			"Toss the spaghetti in the oil with a splash of the cooking water.",
+			"Finish with the parsley and parmesan.",
+		},
+		Tags: []string{"dinner", "pasta", "quick"},
+	},
+	{
+		Title:       "Banana Oat Muffins",
+		Description: "Lightly sweet muffins that use up ripe bananas.",
+		PrepMinutes: 15,
+		CookMinutes: 22,
+		Servings:    12,
+		Ingredients: []string{
+			"3 ripe bananas, mashed",
+			"2 eggs",
+			"1/3 cup honey",
+			"1/3 cup melted butter",
+			"1 1/2 cups flour",
+			"1 cup rolled oats",
+			"1 tsp baking soda",
+			"1/2 tsp salt",
+		},
+		Steps: []string{
+			"Heat the oven to 350°F and line a 12-cup muffin tin.",
+			"Whisk the bananas, eggs, honey, and butter together.",
+			"Fold in the flour, oats, baking soda, and salt until just combined.",
+			"Divide between the cups and bake for 22 minutes.",
+		},
+		Tags: []string{"baking", "breakfast"},
+	},
+}
+
+// Seed creates the example user and recipes unless they already exist,
+// and returns the ID of the example user. When db supports transactions
+// everything is created in one, so an interrupted seed is retried from
+// scratch on the next start.
+func Seed(ctx context.Context, db database.Querier) (int64, error) {
+	user, err := db.GetUser(ctx, UserEmail)
+	if err == nil {
+		return user.ID, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("getting example user: %w", err)
+	}
+
+	passwordHash, err := argon2id.EncodeHash(rand.Text(), argon2id.DefaultParams)
+	if err != nil {
+		return 0, fmt.Errorf("hashing example user password: %w", err)
+	}
+
+	var userID int64
+	seed := func(q database.Querier) error {
+		userID, err = q.CreateUser(ctx, database.CreateUserParams{
+			FirstName:    "Demo",
+			LastName:     "Cook",
+			PasswordHash: passwordHash,
+			Email:        UserEmail,
+		})
+		if err != nil {
+			return fmt.Errorf("creating example user: %w", err)
+		}
+		for _, recipe := range Recipes {
+			if err := createRecipe(ctx, q, userID, recipe); err != nil {
+				return fmt.Errorf("creating example recipe %q: %w", recipe.Title, err)
+			}
+		}
+		return nil
+	}
+
+	if transactor, ok := db.(database.Transactor); ok {
+		err = transactor.WithTx(ctx, seed)
+	} else {
+		err = seed(db)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+func createRecipe(ctx context.Context, db database.Querier, userID int64, recipe Recipe) error {
+	recipeID, err := db.CreateRecipe(ctx, database.CreateRecipeParams{
+		UserID: pgtype.Int8{Int64: userID, Valid: true},
+		Title:  recipe.Title,
+	})
+	if err != nil {
+		return fmt.Errorf("creating recipe: %w", err)
+	}
+
+	minutes := database.NullTimeUnit{TimeUnit: database.TimeUnitMinutes, Valid: true}
+	_, err = db.UpdateRecipe(ctx, database.UpdateRecipeParams{
+		ID:                   recipeID,
+		UpdateDescription:    pgtype.Bool{Bool: true, Valid: true},
+		Description:          pgtype.Text{String: recipe.Description, Valid: true},
+		UpdatePublished:      pgtype.Bool{Bool: true, Valid: true},
+		Published:            pgtype.Bool{Bool: true, Valid: true},
+		UpdatePrepTimeAmount: pgtype.Bool{Bool: true, Valid: true},
+		PrepTimeAmount:       pgtype.Int4{Int32: recipe.PrepMinutes, Valid: true},
+		UpdatePrepTimeUnit:   pgtype.Bool{Bool: true, Valid: true},
+		PrepTimeUnit:         minutes,
+		UpdateCookTimeAmount: pgtype.Bool{Bool: true, Valid: true},
+		CookTimeAmount:       pgtype.Int4{Int32: recipe.CookMinutes, Valid: true},
+		UpdateCookTimeUnit:   pgtype.Bool{Bool: true, Valid: true},
+		CookTimeUnit:         minutes,
+		UpdateServings:       pgtype.Bool{Bool: true, Valid: true},
+		Servings:             pgtype.Float4{Float32: recipe.Servings, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("updating recipe: %w", err)
+	}
+
+	for _, ingredient := range recipe.Ingredients {
+		_, err := db.CreateRecipeIngredient(ctx, database.CreateRecipeIngredientParams{
+			RecipeID:    recipeID,
+			Description: pgtype.Text{String: ingredient, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("creating ingredient: %w", err)
+		}
+	}
+	for _, step := range recipe.Steps {
+		_, err := db.CreateRecipeStep(ctx, database.CreateRecipeStepParams{
+			RecipeID:    recipeID,
+			Instruction: pgtype.Text{String: step, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("creating step: %w", err)
+		}
+	}
+	for _, tag := range recipe.Tags {
+		if err := db.AddRecipeTag(ctx, database.AddRecipeTagParams{RecipeID: recipeID, Tag: tag}); err != nil {
+			return fmt.Errorf("adding tag: %w", err)
+		}
+	}
+	return nil
+}
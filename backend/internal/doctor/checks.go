@@ -0,0 +1,209 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// MinFreeSpace is the free space of the file store volume below which
+	// the file store check warns.
+	MinFreeSpace = 1 << 30
+
+	// MaxClockSkew is the clock difference with the database above which
+	// the clock check warns. Above FailClockSkew it fails, as token expiry
+	// and scheduled jobs would be visibly off.
+	MaxClockSkew  = 2 * time.Second
+	FailClockSkew = time.Minute
+)
+
+// Pinger is a database connection that can be pinged.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// MigrationStatus reports the state of the database schema.
+type MigrationStatus interface {
+	CheckUsersTableExists(ctx context.Context) (bool, error)
+	PendingMigrations(ctx context.Context) ([]string, error)
+}
+
+// RowQuerier runs a query returning a single row.
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Verifier logs in to a service without using it, such as an SMTP server.
+type Verifier interface {
+	Verify() error
+}
+
+// Config reports whether the configuration loaded.
+func Config(err error) Check {
+	return Check{
+		Name: "config",
+		Run: func(context.Context) (Status, string) {
+			if err != nil {
+				return StatusFail, err.Error()
+			}
+			return StatusOK, "configuration is valid"
+		},
+	}
+}
+
+// Database checks that the database accepts connections.
+func Database(db Pinger, address string) Check {
+	return Check{
+		Name:     "database",
+		Requires: "config",
+		Run: func(ctx context.Context) (Status, string) {
+			if err := db.Ping(ctx); err != nil {
+				return StatusFail, fmt.Sprintf("cannot connect to %s: %v", address, err)
+			}
+			return StatusOK, "connected to " + address
+		},
+	}
+}
+
+// Migrations checks that the schema and every migration are applied.
+// Anything missing is applied on the next start, so it only warns.
+func Migrations(db MigrationStatus) Check {
+	return Check{
+		Name:     "migrations",
+		Requires: "database",
+		Run: func(ctx context.Context) (Status, string) {
+			exists, err := db.CheckUsersTableExists(ctx)
+			if err != nil {
+				return StatusFail, fmt.Sprintf("checking schema: %v", err)
+			}
+			if !exists {
+				return StatusWarn, "schema is not applied yet; it is applied on the next start"
+			}
+
+			pending, err := db.PendingMigrations(ctx)
+			if err != nil {
+				return StatusFail, fmt.Sprintf("checking migrations: %v", err)
+			}
+			if len(pending) > 0 {
+				return StatusWarn, fmt.Sprintf("%d pending (%s); they are applied on the next start",
+					len(pending), strings.Join(pending, ", "))
+			}
+			return StatusOK, "all migrations applied"
+		},
+	}
+}
+
+// Clock checks that the local clock agrees with the database's.
+func Clock(db RowQuerier, now func() time.Time) Check {
+	return Check{
+		Name:     "clock",
+		Requires: "database",
+		Run: func(ctx context.Context) (Status, string) {
+			before := now()
+			var dbNow time.Time
+			if err := db.QueryRow(ctx, "SELECT now()").Scan(&dbNow); err != nil {
+				return StatusFail, fmt.Sprintf("reading database clock: %v", err)
+			}
+			after := now()
+
+			// Compare against the middle of the round trip.
+			local := before.Add(after.Sub(before) / 2)
+			skew := local.Sub(dbNow)
+			detail := fmt.Sprintf("%s ahead of the database", skew.Round(time.Millisecond))
+			if skew < 0 {
+				skew = -skew
+				detail = fmt.Sprintf("%s behind the database", skew.Round(time.Millisecond))
+			}
+
+			switch {
+			case skew > FailClockSkew:
+				return StatusFail, detail
+			case skew > MaxClockSkew:
+				return StatusWarn, detail
+			}
+			return StatusOK, detail
+		},
+	}
+}
+
+// FileStore checks that the file store volume is a writable directory
+// with at least minFree bytes free.
+func FileStore(volume string, minFree uint64) Check {
+	return Check{
+		Name:     "file store",
+		Requires: "config",
+		Run: func(context.Context) (Status, string) {
+			info, err := os.Stat(volume)
+			if err != nil {
+				return StatusFail, err.Error()
+			}
+			if !info.IsDir() {
+				return StatusFail, volume + " is not a directory"
+			}
+
+			f, err := os.CreateTemp(volume, ".wecook-doctor-*")
+			if err != nil {
+				return StatusFail, fmt.Sprintf("%s is not writable: %v", volume, err)
+			}
+			_ = f.Close()
+			if err := os.Remove(f.Name()); err != nil {
+				return StatusFail, fmt.Sprintf("removing test file: %v", err)
+			}
+
+			free, err := freeSpace(volume)
+			if err != nil {
+				return StatusWarn, fmt.Sprintf("%s is writable, free space unknown: %v", volume, err)
+			}
+			detail := fmt.Sprintf("%s is writable, %s free", volume, formatBytes(free))
+			if free < minFree {
+				return StatusWarn, detail
+			}
+			return StatusOK, detail
+		},
+	}
+}
+
+// SMTP checks that the SMTP server accepts the configured login. It is
+// skipped when SMTP is not configured.
+func SMTP(smtp Verifier, configured bool) Check {
+	return Check{
+		Name:     "smtp",
+		Requires: "config",
+		Run: func(ctx context.Context) (Status, string) {
+			if !configured {
+				return StatusSkipped, "SMTP is not configured"
+			}
+
+			// The SMTP client has no deadline of its own.
+			done := make(chan error, 1)
+			go func() { done <- smtp.Verify() }()
+			select {
+			case err := <-done:
+				if err != nil {
+					return StatusFail, err.Error()
+				}
+				return StatusOK, "logged in"
+			case <-ctx.Done():
+				return StatusFail, "timed out logging in"
+			}
+		},
+	}
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1 << 10
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
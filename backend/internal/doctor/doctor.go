@@ -0,0 +1,127 @@
+// Package doctor checks that a deployment is ready to run and reports the
+// results for people and deploy gates alike.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Status is the outcome of a check. Statuses are ordered by severity.
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusSkipped
+	StatusWarn
+	StatusFail
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "ok"
+	case StatusSkipped:
+		return "skip"
+	case StatusWarn:
+		return "warn"
+	default:
+		return "FAIL"
+	}
+}
+
+// Exit codes of a report.
+const (
+	ExitOK      = 0
+	ExitFail    = 1
+	ExitWarning = 2
+)
+
+// CheckTimeout bounds how long a single check may run.
+const CheckTimeout = 10 * time.Second
+
+// Check is a single readiness check.
+type Check struct {
+	Name string
+	// Requires names a check that must pass for this one to run.
+	Requires string
+	Run      func(ctx context.Context) (Status, string)
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+}
+
+// Report holds the results of a run in the order the checks were given.
+type Report []Result
+
+// Run runs checks in order. A check whose requirement did not pass is
+// skipped.
+func Run(ctx context.Context, checks []Check) Report {
+	report := make(Report, 0, len(checks))
+	passed := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		if check.Requires != "" && !passed[check.Requires] {
+			report = append(report, Result{
+				Name:   check.Name,
+				Status: StatusSkipped,
+				Detail: check.Requires + " check did not pass",
+			})
+			continue
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, CheckTimeout)
+		status, detail := check.Run(checkCtx)
+		cancel()
+
+		passed[check.Name] = status == StatusOK
+		report = append(report, Result{Name: check.Name, Status: status, Detail: detail})
+	}
+	return report
+}
+
+// Count returns the number of results with the given status.
+func (r Report) Count(status Status) int {
+	var n int
+	for _, result := range r {
+		if result.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// ExitCode is ExitFail if any check failed, ExitWarning if any check
+// warned, and ExitOK otherwise.
+func (r Report) ExitCode() int {
+	switch {
+	case r.Count(StatusFail) > 0:
+		return ExitFail
+	case r.Count(StatusWarn) > 0:
+		return ExitWarning
+	}
+	return ExitOK
+}
+
+// Write writes the report as a table followed by a summary line.
+func (r Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, result := range r {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Status, result.Name, result.Detail); err != nil {
+			return err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed, %d skipped\n",
+		r.Count(StatusOK), r.Count(StatusWarn), r.Count(StatusFail), r.Count(StatusSkipped))
+	return err
+}
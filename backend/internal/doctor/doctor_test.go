@@ -0,0 +1,222 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func check(name, requires string, status Status) Check {
+	return Check{
+		Name:     name,
+		Requires: requires,
+		Run: func(context.Context) (Status, string) {
+			return status, name + " ran"
+		},
+	}
+}
+
+func TestRun_SkipsChecksWhoseRequirementDidNotPass(t *testing.T) {
+	report := Run(context.Background(), []Check{
+		check("config", "", StatusOK),
+		check("database", "config", StatusFail),
+		check("migrations", "database", StatusOK),
+		check("file store", "config", StatusWarn),
+	})
+
+	want := []Status{StatusOK, StatusFail, StatusSkipped, StatusWarn}
+	for i, result := range report {
+		if result.Status != want[i] {
+			t.Errorf("expected %s to be %s, got %s", result.Name, want[i], result.Status)
+		}
+	}
+	if report[2].Detail != "database check did not pass" {
+		t.Errorf("unexpected skip detail %q", report[2].Detail)
+	}
+}
+
+func TestReport_ExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		report Report
+		want   int
+	}{
+		{"all passed", Report{{Status: StatusOK}, {Status: StatusSkipped}}, ExitOK},
+		{"warning", Report{{Status: StatusOK}, {Status: StatusWarn}}, ExitWarning},
+		{"failure", Report{{Status: StatusWarn}, {Status: StatusFail}}, ExitFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.ExitCode(); got != tt.want {
+				t.Errorf("expected exit code %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	var b strings.Builder
+	report := Report{
+		{Name: "config", Status: StatusOK, Detail: "configuration is valid"},
+		{Name: "smtp", Status: StatusFail, Detail: "failed to authenticate"},
+	}
+	if err := report.Write(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "ok    config  configuration is valid\n" +
+		"FAIL  smtp    failed to authenticate\n" +
+		"\n1 passed, 0 warnings, 1 failed, 0 skipped\n"
+	if b.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, b.String())
+	}
+}
+
+type fakeMigrations struct {
+	exists  bool
+	pending []string
+	err     error
+}
+
+func (f fakeMigrations) CheckUsersTableExists(context.Context) (bool, error) {
+	return f.exists, f.err
+}
+
+func (f fakeMigrations) PendingMigrations(context.Context) ([]string, error) {
+	return f.pending, nil
+}
+
+func TestMigrations(t *testing.T) {
+	tests := []struct {
+		name string
+		db   fakeMigrations
+		want Status
+	}{
+		{"up to date", fakeMigrations{exists: true}, StatusOK},
+		{"pending", fakeMigrations{exists: true, pending: []string{"0009_row_level_security"}}, StatusWarn},
+		{"no schema", fakeMigrations{}, StatusWarn},
+		{"error", fakeMigrations{err: errors.New("permission denied")}, StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, detail := Migrations(tt.db).Run(context.Background())
+			if status != tt.want {
+				t.Errorf("expected %s, got %s (%s)", tt.want, status, detail)
+			}
+		})
+	}
+}
+
+type fakeRow struct {
+	now time.Time
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	*dest[0].(*time.Time) = r.now
+	return nil
+}
+
+type fakeClockDB struct {
+	now time.Time
+}
+
+func (db fakeClockDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return fakeRow(db)
+}
+
+func TestClock(t *testing.T) {
+	local := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+	now := func() time.Time { return local }
+
+	tests := []struct {
+		name       string
+		database   time.Time
+		want       Status
+		wantDetail string
+	}{
+		{"in sync", local.Add(-500 * time.Millisecond), StatusOK, "500ms ahead of the database"},
+		{"skewed", local.Add(10 * time.Second), StatusWarn, "10s behind the database"},
+		{"far off", local.Add(-time.Hour), StatusFail, "1h0m0s ahead of the database"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, detail := Clock(fakeClockDB{now: tt.database}, now).Run(context.Background())
+			if status != tt.want || detail != tt.wantDetail {
+				t.Errorf("expected %s %q, got %s %q", tt.want, tt.wantDetail, status, detail)
+			}
+		})
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		volume  string
+		minFree uint64
+		want    Status
+	}{
+		{"writable", dir, 0, StatusOK},
+		{"low on space", dir, math.MaxUint64, StatusWarn},
+		{"missing", filepath.Join(dir, "missing"), 0, StatusFail},
+		{"not a directory", file, 0, StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, detail := FileStore(tt.volume, tt.minFree).Run(context.Background())
+			if status != tt.want {
+				t.Errorf("expected %s, got %s (%s)", tt.want, status, detail)
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("reading volume: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected test files to be removed, found %d entries", len(entries))
+	}
+}
+
+type verifierFunc func() error
+
+func (f verifierFunc) Verify() error { return f() }
+
+func TestSMTP(t *testing.T) {
+	status, _ := SMTP(nil, false).Run(context.Background())
+	if status != StatusSkipped {
+		t.Errorf("expected unconfigured SMTP to be skipped, got %s", status)
+	}
+
+	status, detail := SMTP(verifierFunc(func() error { return errors.New("bad login") }), true).
+		Run(context.Background())
+	if status != StatusFail || detail != "bad login" {
+		t.Errorf("expected failed login, got %s %q", status, detail)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	block := make(chan struct{})
+	defer close(block)
+	status, _ = SMTP(verifierFunc(func() error { <-block; return nil }), true).Run(ctx)
+	if status != StatusFail {
+		t.Errorf("expected timeout to fail, got %s", status)
+	}
+}
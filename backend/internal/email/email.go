@@ -11,9 +11,17 @@ import (
 
 // Sender defines the interface for sending emails.
 type Sender interface {
-	Send(to []string, subject, body string) error
+	Send(to []string, subject, body string, headers ...Header) error
 }
 
+// Header is an additional header of an email, such as List-Unsubscribe.
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+var headerReplacer = strings.NewReplacer("\r", "", "\n", "")
+
 // TLSMode controls how TLS is negotiated with the SMTP server.
 type TLSMode string
 
@@ -79,32 +87,64 @@ func NewSMTPSender(config Config) *SMTPSender {
 }
 
 // Send sends an email to the specified recipients.
-func (s *SMTPSender) Send(to []string, subject, body string) error {
+func (s *SMTPSender) Send(to []string, subject, body string, headers ...Header) error {
 	if len(to) == 0 {
 		return fmt.Errorf("no recipients specified")
 	}
 
 	// Build the message
-	message := s.buildMessage(to, subject, body)
+	message := s.buildMessage(to, subject, body, headers...)
+
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	return s.authenticateAndSend(client, s.auth(), to, message)
+}
+
+// Verify connects and authenticates to the SMTP server without sending
+// any mail.
+func (s *SMTPSender) Verify() error {
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
 
-	// Create SMTP authentication
-	auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	if err := client.Auth(s.auth()); err != nil {
+		return fmt.Errorf("failed to authenticate: %w", err)
+	}
+	return client.Quit()
+}
 
+// connect opens a client to the SMTP server, secured as configured.
+func (s *SMTPSender) connect() (*smtp.Client, error) {
 	// Build server address
 	addr := s.config.Host + ":" + strconv.Itoa(s.config.Port)
 
 	switch s.resolveTLSMode() {
 	case TLSModeImplicit:
-		return s.sendWithImplicitTLS(addr, auth, to, message)
+		return s.dialImplicitTLS(addr)
 	case TLSModeStartTLS:
-		return s.sendWithStartTLS(addr, auth, to, message)
+		return s.dialStartTLS(addr)
+	}
+
+	client, err := smtp.Dial(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
 	}
+	return client, nil
+}
 
-	return s.sendWithoutTLS(addr, auth, to, message)
+func (s *SMTPSender) auth() smtp.Auth {
+	return smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
 }
 
-// buildMessage constructs the email message with headers.
-func (s *SMTPSender) buildMessage(to []string, subject, body string) []byte {
+// buildMessage constructs the email message with headers. Line breaks are
+// removed from extra headers so they cannot add headers of their own.
+func (s *SMTPSender) buildMessage(to []string, subject, body string, extra ...Header) []byte {
 	headers := make(map[string]string)
 	headers["From"] = s.config.From
 	headers["To"] = to[0]
@@ -116,6 +156,9 @@ func (s *SMTPSender) buildMessage(to []string, subject, body string) []byte {
 	headers["Subject"] = subject
 	headers["MIME-Version"] = "1.0"
 	headers["Content-Type"] = "text/html; charset=UTF-8"
+	for _, header := range extra {
+		headers[headerReplacer.Replace(header.Name)] = headerReplacer.Replace(header.Value)
+	}
 
 	message := ""
 	for k, v := range headers {
@@ -126,53 +169,44 @@ func (s *SMTPSender) buildMessage(to []string, subject, body string) []byte {
 	return []byte(message)
 }
 
-func (s *SMTPSender) sendWithImplicitTLS(addr string, auth smtp.Auth, to []string, message []byte) error {
+func (s *SMTPSender) dialImplicitTLS(addr string) (*smtp.Client, error) {
 	conn, err := tls.Dial("tcp", addr, s.tlsConfig())
 	if err != nil {
-		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+		return nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
 	}
-	defer func() { _ = conn.Close() }()
 
 	// Create SMTP client
 	client, err := smtp.NewClient(conn, s.config.Host)
 	if err != nil {
-		return fmt.Errorf("failed to create SMTP client: %w", err)
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to create SMTP client: %w", err)
 	}
-	defer func() { _ = client.Close() }()
 
-	return s.authenticateAndSend(client, auth, to, message)
+	return client, nil
 }
 
-func (s *SMTPSender) sendWithStartTLS(addr string, auth smtp.Auth, to []string, message []byte) error {
+func (s *SMTPSender) dialStartTLS(addr string) (*smtp.Client, error) {
 	client, err := smtp.Dial(addr)
 	if err != nil {
-		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+		return nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
 	}
-	defer func() { _ = client.Close() }()
 
 	if err := client.Hello(s.config.Host); err != nil {
-		return fmt.Errorf("failed to introduce client: %w", err)
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to introduce client: %w", err)
 	}
 
 	if ok, _ := client.Extension("STARTTLS"); !ok {
-		return fmt.Errorf("SMTP server does not support STARTTLS")
+		_ = client.Close()
+		return nil, fmt.Errorf("SMTP server does not support STARTTLS")
 	}
 
 	if err := client.StartTLS(s.tlsConfig()); err != nil {
-		return fmt.Errorf("failed to start TLS: %w", err)
-	}
-
-	return s.authenticateAndSend(client, auth, to, message)
-}
-
-func (s *SMTPSender) sendWithoutTLS(addr string, auth smtp.Auth, to []string, message []byte) error {
-	client, err := smtp.Dial(addr)
-	if err != nil {
-		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to start TLS: %w", err)
 	}
-	defer func() { _ = client.Close() }()
 
-	return s.authenticateAndSend(client, auth, to, message)
+	return client, nil
 }
 
 func (s *SMTPSender) authenticateAndSend(client *smtp.Client, auth smtp.Auth, to []string, message []byte) error {
@@ -40,15 +40,20 @@ func (m *MockSender) EXPECT() *MockSenderMockRecorder {
 }
 
 // Send mocks base method.
-func (m *MockSender) Send(to []string, subject, body string) error {
+func (m *MockSender) Send(to []string, subject, body string, headers ...Header) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Send", to, subject, body)
+	varargs := []any{to, subject, body}
+	for _, a := range headers {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Send", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Send indicates an expected call of Send.
-func (mr *MockSenderMockRecorder) Send(to, subject, body any) *gomock.Call {
+func (mr *MockSenderMockRecorder) Send(to, subject, body any, headers ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSender)(nil).Send), to, subject, body)
+	varargs := append([]any{to, subject, body}, headers...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSender)(nil).Send), varargs...)
 }
@@ -5,27 +5,41 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"time"
 
+	"github.com/matt-dz/wecook/internal/clock"
 	"github.com/matt-dz/wecook/internal/config"
 	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/demo"
 	"github.com/matt-dz/wecook/internal/email"
 	"github.com/matt-dz/wecook/internal/filestore"
 	"github.com/matt-dz/wecook/internal/http"
+	"github.com/matt-dz/wecook/internal/idgen"
 	"github.com/matt-dz/wecook/internal/log"
 )
 
-type envKeyType struct{}
+type (
+	envKeyType struct{}
+	txKeyType  struct{}
+)
 
-var envKey envKeyType
+var (
+	envKey envKeyType
+	txKey  txKeyType
+)
 
 type Env struct {
-	Logger    *slog.Logger
-	Database  database.Querier
-	HTTP      http.HTTPDoer
-	SMTP      email.Sender
-	FileStore filestore.FileStoreInterface
-	Config    config.Config
-	vars      map[string]string
+	Logger         *slog.Logger
+	Database       database.Querier
+	HTTP           http.HTTPDoer
+	FederationHTTP http.HTTPDoer
+	SMTP           email.Sender
+	FileStore      filestore.FileStoreInterface
+	Clock          clock.Clock
+	IDGen          idgen.IDGenerator
+	Config         config.Config
+	Demo           *demo.Sandbox
+	vars           map[string]string
 }
 
 func (e *Env) Get(key string) string {
@@ -42,13 +56,72 @@ func (e *Env) Set(key string, value string) {
 	e.vars[key] = value
 }
 
+// Now returns the current time according to the env clock, falling
+// back to the system clock if none is set.
+func (e *Env) Now() time.Time {
+	if e.Clock == nil {
+		return clock.New().Now()
+	}
+	return e.Clock.Now()
+}
+
+// NewID returns a new identifier from the env ID generator, falling
+// back to a random identifier if none is set.
+func (e *Env) NewID() string {
+	if e.IDGen == nil {
+		return idgen.New().NewID()
+	}
+	return e.IDGen.NewID()
+}
+
+// WithDatabase returns a copy of e that uses db. e is not modified, so it
+// is safe to call on the Env shared by concurrent requests.
+func (e *Env) WithDatabase(db database.Querier) *Env {
+	c := *e
+	c.Database = db
+	return &c
+}
+
+// WithTransaction runs fn in a database transaction, which is committed
+// if fn returns nil and rolled back otherwise. fn is passed the
+// transaction and a context whose Env is a copy of e bound to it, so
+// helpers that take the Env from the context write in the transaction
+// too. e itself is not modified: concurrent requests are unaffected, but
+// the transaction must not be used by more than one goroutine.
+//
+// Within a transaction, fn joins it instead of beginning another. If the
+// database does not support transactions, as with the mocks of tests, fn
+// runs against it directly.
+//
+// Writes whose failure is only logged, such as audit events, belong
+// outside fn: a failed statement aborts the whole transaction.
+func (e *Env) WithTransaction(ctx context.Context,
+	fn func(ctx context.Context, tx database.Querier) error,
+) error {
+	if tx, ok := ctx.Value(txKey).(database.Querier); ok {
+		return fn(ctx, tx)
+	}
+	transactor, ok := e.Database.(database.Transactor)
+	if !ok {
+		return fn(ctx, e.Database)
+	}
+	return transactor.WithTx(ctx, func(tx database.Querier) error {
+		ctx := context.WithValue(ctx, txKey, tx)
+		return fn(WithCtx(ctx, e.WithDatabase(tx)), tx)
+	})
+}
+
 func (e *Env) IsProd() bool {
 	return e.Config.Env == config.EnvProd
 }
 
+// New returns an Env with the system clock and a random ID generator.
+// Remaining dependencies must be set by the caller.
 func New(vars map[string]string) *Env {
 	return &Env{
-		vars: vars,
+		Clock: clock.New(),
+		IDGen: idgen.New(),
+		vars:  vars,
 	}
 }
 
@@ -58,6 +131,8 @@ func Null() *Env {
 		Database:  nil,
 		HTTP:      nil,
 		FileStore: nil,
+		Clock:     clock.New(),
+		IDGen:     idgen.New(),
 		vars:      make(map[string]string),
 	}
 }
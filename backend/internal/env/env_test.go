@@ -0,0 +1,93 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/database"
+)
+
+// fakeTransactor hands fn a separate Querier as its transaction and
+// records whether it was committed.
+type fakeTransactor struct {
+	database.Querier
+	tx        database.Querier
+	begun     int
+	committed bool
+}
+
+func (f *fakeTransactor) WithTx(_ context.Context, fn func(q database.Querier) error) error {
+	f.begun++
+	if err := fn(f.tx); err != nil {
+		return err
+	}
+	f.committed = true
+	return nil
+}
+
+func TestWithTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := &fakeTransactor{Querier: database.NewMockQuerier(ctrl), tx: database.NewMockQuerier(ctrl)}
+	e := &Env{Database: db}
+
+	err := e.WithTransaction(context.Background(), func(ctx context.Context, tx database.Querier) error {
+		if tx != db.tx {
+			t.Error("expected fn to be passed the transaction")
+		}
+		if EnvFromCtx(ctx).Database != db.tx {
+			t.Error("expected the Env of the context to use the transaction")
+		}
+		// Nested transactions join the outer one.
+		return e.WithTransaction(ctx, func(_ context.Context, nested database.Querier) error {
+			if nested != db.tx {
+				t.Error("expected nested fn to be passed the outer transaction")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.begun != 1 || !db.committed {
+		t.Errorf("expected one committed transaction, got %d begun, committed %v", db.begun, db.committed)
+	}
+	if e.Database != db {
+		t.Error("expected the Env to be left unchanged")
+	}
+}
+
+func TestWithTransaction_RollsBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := &fakeTransactor{Querier: database.NewMockQuerier(ctrl), tx: database.NewMockQuerier(ctrl)}
+	e := &Env{Database: db}
+	want := errors.New("write failed")
+
+	err := e.WithTransaction(context.Background(), func(context.Context, database.Querier) error {
+		return want
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("expected %v, got %v", want, err)
+	}
+	if db.committed {
+		t.Error("expected the transaction to be rolled back")
+	}
+}
+
+func TestWithTransaction_NoTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := database.NewMockQuerier(ctrl)
+	e := &Env{Database: db}
+
+	err := e.WithTransaction(context.Background(), func(_ context.Context, tx database.Querier) error {
+		if tx != db {
+			t.Error("expected fn to run against the database")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
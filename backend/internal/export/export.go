@@ -0,0 +1,379 @@
+// Package export writes everything stored about a user to a zip archive.
+//
+// An archive holds manifest.json, user.json, recipes.json,
+// appliances.json, and the files of the user's images under images/,
+// which recipes.json refers to by path. RecipeSchema describes the
+// recipes in recipes.json.
+package export
+
+import (
+	"archive/zip"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/matt-dz/wecook/internal/branding"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/fileserver"
+)
+
+// FormatVersion is the version of the archive layout. It changes whenever
+// a file or field is removed or changes meaning.
+const FormatVersion = 1
+
+const imagesDir = "images/"
+
+// RecipeSchema is the JSON Schema of a recipe in recipes.json. It changes
+// with FormatVersion.
+//
+//go:embed recipe.schema.json
+var RecipeSchema []byte
+
+var ErrUserNotFound = errors.New("user not found")
+
+// Manifest describes an archive.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	UserID        int64     `json:"user_id"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Instance      Instance  `json:"instance"`
+	// MissingImages are the images referenced by recipes whose files no
+	// longer exist.
+	MissingImages []string `json:"missing_images"`
+}
+
+// Instance is the instance the archive was exported from, as it is
+// branded.
+type Instance struct {
+	Name         string  `json:"name"`
+	ContactEmail *string `json:"contact_email"`
+}
+
+// User is the account and preferences of the user.
+type User struct {
+	ID              int64     `json:"id"`
+	Email           string    `json:"email"`
+	FirstName       string    `json:"first_name"`
+	LastName        string    `json:"last_name"`
+	Role            string    `json:"role"`
+	TemperatureUnit *string   `json:"temperature_unit"`
+	WeeklyReport    bool      `json:"weekly_report"`
+	Allergies       []string  `json:"allergies"`
+	TimeZone        string    `json:"time_zone"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Recipe is a recipe owned by the user.
+type Recipe struct {
+	ID             int64        `json:"id"`
+	Title          string       `json:"title"`
+	Description    *string      `json:"description"`
+	Image          *string      `json:"image"`
+	Published      bool         `json:"published"`
+	Servings       *float32     `json:"servings"`
+	CookTimeAmount *int32       `json:"cook_time_amount"`
+	CookTimeUnit   *string      `json:"cook_time_unit"`
+	PrepTimeAmount *int32       `json:"prep_time_amount"`
+	PrepTimeUnit   *string      `json:"prep_time_unit"`
+	Tags           []string     `json:"tags"`
+	Ingredients    []Ingredient `json:"ingredients"`
+	Steps          []Step       `json:"steps"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// Ingredient is an ingredient of a recipe.
+type Ingredient struct {
+	ID          int64     `json:"id"`
+	Description *string   `json:"description"`
+	Image       *string   `json:"image"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Step is a step of a recipe.
+type Step struct {
+	ID               int64     `json:"id"`
+	StepNumber       int32     `json:"step_number"`
+	Instruction      *string   `json:"instruction"`
+	Image            *string   `json:"image"`
+	TemperatureValue *float32  `json:"temperature_value"`
+	TemperatureUnit  *string   `json:"temperature_unit"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Appliance is an appliance registered by the user. Its token is a
+// credential and is left out.
+type Appliance struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Provider  string    `json:"provider"`
+	Endpoint  string    `json:"endpoint"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Summary counts what an archive holds.
+type Summary struct {
+	Recipes int `json:"recipes"`
+	Images  int `json:"images"`
+}
+
+// Write writes the archive of a user to w.
+func Write(ctx context.Context, env *env.Env, userID int64, w io.Writer) (Summary, error) {
+	// Get user
+	env.Logger.DebugContext(ctx, "getting user")
+	user, err := env.Database.GetUserForExport(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Summary{}, ErrUserNotFound
+	}
+	if err != nil {
+		return Summary{}, fmt.Errorf("getting user: %w", err)
+	}
+
+	// Get recipes
+	env.Logger.DebugContext(ctx, "getting recipes")
+	recipes, imageKeys, err := getRecipes(ctx, env, userID)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	// Get appliances
+	env.Logger.DebugContext(ctx, "getting appliances")
+	rows, err := env.Database.GetAppliances(ctx, userID)
+	if err != nil {
+		return Summary{}, fmt.Errorf("getting appliances: %w", err)
+	}
+	appliances := make([]Appliance, len(rows))
+	for i, a := range rows {
+		appliances[i] = Appliance{
+			ID:        a.ID,
+			Name:      a.Name,
+			Provider:  a.Provider,
+			Endpoint:  a.Endpoint,
+			Scopes:    a.Scopes,
+			CreatedAt: a.CreatedAt.Time,
+		}
+	}
+
+	// Get branding
+	env.Logger.DebugContext(ctx, "getting instance branding")
+	instance, err := branding.Load(ctx, env.Database)
+	if err != nil {
+		env.Logger.WarnContext(ctx, "failed to load branding, using default", slog.Any("error", err))
+		instance = branding.Default()
+	}
+
+	archive := zip.NewWriter(w)
+	manifest := Manifest{
+		FormatVersion: FormatVersion,
+		UserID:        userID,
+		ExportedAt:    env.Now().UTC(),
+		Instance:      Instance{Name: instance.Name},
+		MissingImages: []string{},
+	}
+	if instance.ContactEmail != "" {
+		manifest.Instance.ContactEmail = &instance.ContactEmail
+	}
+
+	// Write images
+	env.Logger.DebugContext(ctx, "writing images", slog.Int("count", len(imageKeys)))
+	summary := Summary{Recipes: len(recipes)}
+	for _, key := range imageKeys {
+		data, err := env.FileStore.ReadKey(key)
+		if errors.Is(err, fileserver.ErrNotExist) {
+			env.Logger.WarnContext(ctx, "image not found", slog.String("key", key))
+			manifest.MissingImages = append(manifest.MissingImages, imagePath(key))
+			continue
+		}
+		if err != nil {
+			return Summary{}, fmt.Errorf("reading image %q: %w", key, err)
+		}
+		f, err := archive.Create(imagePath(key))
+		if err != nil {
+			return Summary{}, fmt.Errorf("adding image %q: %w", key, err)
+		}
+		if _, err := f.Write(data); err != nil {
+			return Summary{}, fmt.Errorf("writing image %q: %w", key, err)
+		}
+		summary.Images++
+	}
+
+	// Write documents
+	env.Logger.DebugContext(ctx, "writing documents")
+	documents := []struct {
+		name  string
+		value any
+	}{
+		{"user.json", newUser(user)},
+		{"recipes.json", recipes},
+		{"appliances.json", appliances},
+		{"manifest.json", manifest},
+	}
+	for _, doc := range documents {
+		f, err := archive.Create(doc.name)
+		if err != nil {
+			return Summary{}, fmt.Errorf("adding %s: %w", doc.name, err)
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc.value); err != nil {
+			return Summary{}, fmt.Errorf("writing %s: %w", doc.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return Summary{}, fmt.Errorf("closing archive: %w", err)
+	}
+	return summary, nil
+}
+
+// getRecipes returns the recipes of a user and the keys of their images.
+func getRecipes(ctx context.Context, env *env.Env, userID int64) ([]Recipe, []string, error) {
+	// Without a limit, every recipe is returned.
+	rows, err := env.Database.GetRecipesByOwner(ctx, database.GetRecipesByOwnerParams{UserID: userID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting recipes: %w", err)
+	}
+
+	var keys []string
+	image := func(key pgtype.Text) *string {
+		if !key.Valid {
+			return nil
+		}
+		keys = append(keys, key.String)
+		path := imagePath(key.String)
+		return &path
+	}
+
+	recipes := make([]Recipe, len(rows))
+	for i, r := range rows {
+		ingredients, err := env.Database.GetRecipeIngredients(ctx, r.RecipeID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting ingredients of recipe %d: %w", r.RecipeID, err)
+		}
+		steps, err := env.Database.GetRecipeSteps(ctx, r.RecipeID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting steps of recipe %d: %w", r.RecipeID, err)
+		}
+		tags, err := env.Database.GetRecipeTags(ctx, r.RecipeID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting tags of recipe %d: %w", r.RecipeID, err)
+		}
+		if tags == nil {
+			tags = []string{}
+		}
+
+		recipe := Recipe{
+			ID:             r.RecipeID,
+			Title:          r.Title,
+			Description:    text(r.Description),
+			Image:          image(r.ImageKey),
+			Published:      r.Published,
+			Servings:       float(r.Servings),
+			CookTimeAmount: integer(r.CookTimeAmount),
+			PrepTimeAmount: integer(r.PrepTimeAmount),
+			Tags:           tags,
+			Ingredients:    make([]Ingredient, len(ingredients)),
+			Steps:          make([]Step, len(steps)),
+			CreatedAt:      r.CreatedAt.Time,
+			UpdatedAt:      r.UpdatedAt.Time,
+		}
+		if r.CookTimeUnit.Valid {
+			unit := string(r.CookTimeUnit.TimeUnit)
+			recipe.CookTimeUnit = &unit
+		}
+		if r.PrepTimeUnit.Valid {
+			unit := string(r.PrepTimeUnit.TimeUnit)
+			recipe.PrepTimeUnit = &unit
+		}
+		for j, ing := range ingredients {
+			recipe.Ingredients[j] = Ingredient{
+				ID:          ing.ID,
+				Description: text(ing.Description),
+				Image:       image(ing.ImageKey),
+				CreatedAt:   ing.CreatedAt.Time,
+				UpdatedAt:   ing.UpdatedAt.Time,
+			}
+		}
+		for j, step := range steps {
+			recipe.Steps[j] = Step{
+				ID:               step.ID,
+				StepNumber:       step.StepNumber,
+				Instruction:      text(step.Instruction),
+				Image:            image(step.ImageKey),
+				TemperatureValue: float(step.TemperatureValue),
+				CreatedAt:        step.CreatedAt.Time,
+				UpdatedAt:        step.UpdatedAt.Time,
+			}
+			if step.TemperatureUnit.Valid {
+				unit := string(step.TemperatureUnit.TemperatureUnit)
+				recipe.Steps[j].TemperatureUnit = &unit
+			}
+		}
+		recipes[i] = recipe
+	}
+
+	return recipes, keys, nil
+}
+
+func newUser(u database.GetUserForExportRow) User {
+	user := User{
+		ID:           u.ID,
+		Email:        u.Email,
+		FirstName:    u.FirstName,
+		LastName:     u.LastName,
+		Role:         string(u.Role),
+		WeeklyReport: u.WeeklyReport,
+		Allergies:    u.Allergies,
+		TimeZone:     u.TimeZone,
+		CreatedAt:    u.CreatedAt.Time,
+		UpdatedAt:    u.UpdatedAt.Time,
+	}
+	if user.Allergies == nil {
+		user.Allergies = []string{}
+	}
+	if u.TemperatureUnit.Valid {
+		unit := string(u.TemperatureUnit.TemperatureUnit)
+		user.TemperatureUnit = &unit
+	}
+	return user
+}
+
+// imagePath returns the path of an image within the archive.
+func imagePath(key string) string {
+	return imagesDir + strings.TrimLeft(key, "/")
+}
+
+func text(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+func integer(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+func float(f pgtype.Float4) *float32 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float32
+}
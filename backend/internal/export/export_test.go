@@ -0,0 +1,229 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/branding"
+	"github.com/matt-dz/wecook/internal/clock"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/fileserver"
+	"github.com/matt-dz/wecook/internal/filestore"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+var now = time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+
+func newEnv(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) *env.Env {
+	return &env.Env{
+		Logger:    log.NullLogger(),
+		Database:  &database.Database{Querier: mockDB},
+		FileStore: mockFS,
+		Clock:     clock.NewFrozen(now),
+	}
+}
+
+func readArchive(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("reading archive: %v", err)
+	}
+	files := make(map[string][]byte, len(r.File))
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("opening %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatalf("reading %s: %v", f.Name, err)
+		}
+		files[f.Name] = content
+	}
+	return files
+}
+
+func TestWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockFS := filestore.NewMockFileStoreInterface(ctrl)
+
+	mockDB.EXPECT().
+		GetUserForExport(gomock.Any(), int64(7)).
+		Return(database.GetUserForExportRow{
+			ID:        7,
+			Email:     "cook@example.com",
+			FirstName: "Ada",
+			Role:      database.RoleUser,
+		}, nil)
+	mockDB.EXPECT().
+		GetRecipesByOwner(gomock.Any(), database.GetRecipesByOwnerParams{UserID: 7}).
+		Return([]database.GetRecipesByOwnerRow{{
+			RecipeID: 3,
+			Title:    "Bread",
+			ImageKey: pgtype.Text{String: "/files/covers/bread.png", Valid: true},
+		}}, nil)
+	mockDB.EXPECT().
+		GetRecipeIngredients(gomock.Any(), int64(3)).
+		Return([]database.RecipeIngredient{{
+			ID:          4,
+			Description: pgtype.Text{String: "Flour", Valid: true},
+			ImageKey:    pgtype.Text{String: "/files/ingredients/flour.png", Valid: true},
+		}}, nil)
+	mockDB.EXPECT().
+		GetRecipeSteps(gomock.Any(), int64(3)).
+		Return([]database.RecipeStep{{
+			ID:               5,
+			StepNumber:       1,
+			Instruction:      pgtype.Text{String: "Bake", Valid: true},
+			TemperatureValue: pgtype.Float4{Float32: 220, Valid: true},
+			TemperatureUnit:  database.NullTemperatureUnit{TemperatureUnit: "C", Valid: true},
+		}}, nil)
+	mockDB.EXPECT().GetRecipeTags(gomock.Any(), int64(3)).Return([]string{"baking"}, nil)
+	mockDB.EXPECT().
+		GetAppliances(gomock.Any(), int64(7)).
+		Return([]database.Appliance{{ID: 9, Name: "Oven", Token: "secret"}}, nil)
+	mockDB.EXPECT().
+		GetInstanceBranding(gomock.Any(), int32(branding.ID)).
+		Return(database.InstanceBranding{
+			ID:           branding.ID,
+			Name:         "Test Kitchen",
+			ContactEmail: pgtype.Text{String: "admin@example.com", Valid: true},
+		}, nil)
+	mockFS.EXPECT().ReadKey("/files/covers/bread.png").Return([]byte("cover"), nil)
+	// Missing images are listed in the manifest instead of failing the export.
+	mockFS.EXPECT().ReadKey("/files/ingredients/flour.png").Return(nil, fileserver.ErrNotExist)
+
+	var buf bytes.Buffer
+	summary, err := Write(context.Background(), newEnv(mockDB, mockFS), 7, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary != (Summary{Recipes: 1, Images: 1}) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	files := readArchive(t, buf.Bytes())
+	if string(files["images/files/covers/bread.png"]) != "cover" {
+		t.Errorf("expected cover image in archive, got %q", files["images/files/covers/bread.png"])
+	}
+	if bytes.Contains(files["appliances.json"], []byte("secret")) {
+		t.Error("expected appliance token to be left out")
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+		t.Fatalf("decoding manifest: %v", err)
+	}
+	if manifest.FormatVersion != FormatVersion || manifest.UserID != 7 || !manifest.ExportedAt.Equal(now) {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+	if manifest.Instance.Name != "Test Kitchen" || manifest.Instance.ContactEmail == nil ||
+		*manifest.Instance.ContactEmail != "admin@example.com" {
+		t.Errorf("unexpected instance %+v", manifest.Instance)
+	}
+	if len(manifest.MissingImages) != 1 || manifest.MissingImages[0] != "images/files/ingredients/flour.png" {
+		t.Errorf("expected missing ingredient image, got %v", manifest.MissingImages)
+	}
+
+	var recipes []Recipe
+	if err := json.Unmarshal(files["recipes.json"], &recipes); err != nil {
+		t.Fatalf("decoding recipes: %v", err)
+	}
+	if len(recipes) != 1 || len(recipes[0].Ingredients) != 1 || len(recipes[0].Steps) != 1 {
+		t.Fatalf("unexpected recipes %+v", recipes)
+	}
+	if recipes[0].Image == nil || *recipes[0].Image != "images/files/covers/bread.png" {
+		t.Errorf("expected cover image path, got %v", recipes[0].Image)
+	}
+	if unit := recipes[0].Steps[0].TemperatureUnit; unit == nil || *unit != "C" {
+		t.Errorf("expected step temperature unit C, got %v", unit)
+	}
+
+	var user User
+	if err := json.Unmarshal(files["user.json"], &user); err != nil {
+		t.Fatalf("decoding user: %v", err)
+	}
+	if user.Email != "cook@example.com" || user.Role != "user" {
+		t.Errorf("unexpected user %+v", user)
+	}
+}
+
+func TestWrite_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().
+		GetUserForExport(gomock.Any(), int64(7)).
+		Return(database.GetUserForExportRow{}, pgx.ErrNoRows)
+
+	_, err := Write(context.Background(), newEnv(mockDB, nil), 7, io.Discard)
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("expected %v, got %v", ErrUserNotFound, err)
+	}
+}
+
+// jsonFields returns the JSON names of the fields of v.
+func jsonFields(v any) []string {
+	t := reflect.TypeOf(v)
+	fields := make([]string, t.NumField())
+	for i := range fields {
+		fields[i], _, _ = strings.Cut(t.Field(i).Tag.Get("json"), ",")
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+func TestRecipeSchema(t *testing.T) {
+	type object struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+	}
+	var schema struct {
+		object
+		Defs map[string]object `json:"$defs"`
+	}
+	if err := json.Unmarshal(RecipeSchema, &schema); err != nil {
+		t.Fatalf("decoding schema: %v", err)
+	}
+
+	// Every field is described and required, as every field is written.
+	tests := []struct {
+		name   string
+		schema object
+		value  any
+	}{
+		{"recipe", schema.object, Recipe{}},
+		{"ingredient", schema.Defs["ingredient"], Ingredient{}},
+		{"step", schema.Defs["step"], Step{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := jsonFields(tt.value)
+			properties := slices.Sorted(maps.Keys(tt.schema.Properties))
+			if !slices.Equal(properties, want) {
+				t.Errorf("expected properties %v, got %v", want, properties)
+			}
+			required := slices.Sorted(slices.Values(tt.schema.Required))
+			if !slices.Equal(required, want) {
+				t.Errorf("expected required %v, got %v", want, required)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Package federation publishes public recipes as ActivityPub objects and
+// lets users follow users of other wecook instances, whose recipes are
+// then shown in the federated feed. It is experimental and only served
+// when FEDERATION_ENABLED=true.
+//
+// Users have no usernames, so actors are identified by user ID: the actor
+// at {origin}/federation/users/{id} is followed as {id}@{host}. Recipes
+// are Article objects at {origin}/federation/recipes/{id} carrying the
+// schema.org recipeIngredient and recipeInstructions properties, so other
+// wecook instances can show them as recipes and other servers as
+// articles.
+//
+// Only the activities needed between wecook instances are implemented:
+// Follow, Undo of a Follow, Accept, Create, Update, and Delete. Incoming
+// activities must be signed with HTTP Signatures; outgoing ones are
+// signed with the key of their user and sent through the delivery
+// package, so failed deliveries are retried.
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ContentType is the media type of ActivityPub documents.
+	ContentType = "application/activity+json"
+	// Public addresses an activity to everyone.
+	Public = "https://www.w3.org/ns/activitystreams#Public"
+	// Prefix is the path prefix of actors and objects.
+	Prefix = "/federation"
+	// WebFingerPath is where actors are looked up by handle.
+	WebFingerPath = "/.well-known/webfinger"
+	// OutboxSize is how many recent recipes an outbox lists.
+	OutboxSize = 20
+	// MaxDocumentSize is the largest activity or remote document read.
+	MaxDocumentSize = 1 << 20
+)
+
+var activityStreams = []string{
+	"https://www.w3.org/ns/activitystreams",
+	"https://w3id.org/security/v1",
+}
+
+var (
+	ErrInvalidHandle = errors.New("handle must look like user@host")
+	// ErrActorNotFound is returned when a handle or actor can not be
+	// resolved.
+	ErrActorNotFound = errors.New("actor not found")
+	// ErrLocalActor is returned when following an actor of this instance.
+	ErrLocalActor = errors.New("actor is on this instance")
+	// ErrNotFollowing is returned when unfollowing an actor that is not
+	// followed.
+	ErrNotFollowing = errors.New("not following actor")
+)
+
+// Actor is an ActivityPub Person.
+type Actor struct {
+	Context           any       `json:"@context,omitempty"`
+	ID                string    `json:"id"`
+	Type              string    `json:"type"`
+	PreferredUsername string    `json:"preferredUsername,omitempty"`
+	Name              string    `json:"name,omitempty"`
+	URL               string    `json:"url,omitempty"`
+	Inbox             string    `json:"inbox"`
+	Outbox            string    `json:"outbox,omitempty"`
+	Followers         string    `json:"followers,omitempty"`
+	PublicKey         PublicKey `json:"publicKey"`
+}
+
+// PublicKey is the key an actor signs its activities with.
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// Recipe is a recipe published as an ActivityPub Article.
+type Recipe struct {
+	Context      any        `json:"@context,omitempty"`
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	AttributedTo string     `json:"attributedTo"`
+	Name         string     `json:"name"`
+	Summary      string     `json:"summary,omitempty"`
+	Content      string     `json:"content,omitempty"`
+	URL          string     `json:"url"`
+	Image        *Image     `json:"image,omitempty"`
+	Published    time.Time  `json:"published"`
+	Updated      *time.Time `json:"updated,omitempty"`
+	To           []string   `json:"to,omitempty"`
+	// Ingredients and Instructions are the schema.org Recipe
+	// properties.
+	Ingredients  []string `json:"recipeIngredient,omitempty"`
+	Instructions []string `json:"recipeInstructions,omitempty"`
+}
+
+// Image is the cover image of a recipe.
+type Image struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Activity is an activity sent to a remote inbox. Object is encoded as
+// is.
+type Activity struct {
+	Context any      `json:"@context,omitempty"`
+	ID      string   `json:"id"`
+	Type    string   `json:"type"`
+	Actor   string   `json:"actor"`
+	To      []string `json:"to,omitempty"`
+	Object  any      `json:"object"`
+}
+
+// inboundActivity is an activity received in an inbox. Object is an ID or
+// an embedded object.
+type inboundActivity struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	Actor  string          `json:"actor"`
+	Object json.RawMessage `json:"object"`
+}
+
+// objectID returns the ID of an object given as an ID or embedded.
+func objectID(object json.RawMessage) string {
+	var id string
+	if err := json.Unmarshal(object, &id); err == nil {
+		return id
+	}
+	var embedded struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(object, &embedded)
+	return embedded.ID
+}
+
+// OrderedCollection lists the items of an outbox or counts followers.
+type OrderedCollection struct {
+	Context      any    `json:"@context,omitempty"`
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	TotalItems   int    `json:"totalItems"`
+	OrderedItems []any  `json:"orderedItems,omitempty"`
+}
+
+// ActorID returns the ID of the actor of a local user.
+func ActorID(origin string, userID int64) string {
+	return strings.TrimRight(origin, "/") + Prefix + "/users/" + strconv.FormatInt(userID, 10)
+}
+
+// KeyID returns the ID of the key a local user signs activities with.
+func KeyID(origin string, userID int64) string {
+	return ActorID(origin, userID) + "#main-key"
+}
+
+// RecipeID returns the ID of the object of a published recipe.
+func RecipeID(origin string, recipeID int64) string {
+	return strings.TrimRight(origin, "/") + Prefix + "/recipes/" + strconv.FormatInt(recipeID, 10)
+}
+
+// recipePage returns the page a recipe is shown on.
+func recipePage(origin string, recipeID int64) string {
+	return strings.TrimRight(origin, "/") + "/recipes/" + strconv.FormatInt(recipeID, 10)
+}
+
+// Handle returns the handle a local user is followed as.
+func Handle(origin string, userID int64) string {
+	return strconv.FormatInt(userID, 10) + "@" + host(origin)
+}
+
+// localUser returns the user of a local actor ID.
+func localUser(origin, actorID string) (int64, bool) {
+	rest, ok := strings.CutPrefix(actorID, strings.TrimRight(origin, "/")+Prefix+"/users/")
+	if !ok {
+		return 0, false
+	}
+	userID, err := strconv.ParseInt(rest, 10, 64)
+	return userID, err == nil && userID > 0
+}
+
+// host returns the host of an origin or URL.
+func host(origin string) string {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// sameHost reports whether two URLs are on the same host.
+func sameHost(a, b string) bool {
+	hostA := host(a)
+	return hostA != "" && hostA == host(b)
+}
+
+// Handles reports whether path is served by the federation handler.
+func Handles(path string) bool {
+	return path == WebFingerPath || strings.HasPrefix(path, Prefix+"/")
+}
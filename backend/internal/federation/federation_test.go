@@ -0,0 +1,420 @@
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/clock"
+	"github.com/matt-dz/wecook/internal/config"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	wcHttp "github.com/matt-dz/wecook/internal/http"
+	"github.com/matt-dz/wecook/internal/httpsig"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+const origin = "http://wecook.test"
+
+var now = time.Date(2026, time.April, 6, 9, 0, 0, 0, time.UTC)
+
+func newEnv(mockDB database.Querier) *env.Env {
+	client := retryablehttp.NewClient()
+	client.RetryMax = 0
+	client.Logger = nil
+	return &env.Env{
+		Logger:   log.NullLogger(),
+		Database: mockDB,
+		HTTP:     client,
+		// The remotes of the tests listen on loopback addresses.
+		FederationHTTP: client,
+		Clock:          clock.NewFrozen(now),
+		Config: config.Config{
+			HostOrigin: origin,
+			Federation: config.Federation{Enabled: true},
+		},
+	}
+}
+
+func newKey(t *testing.T, userID int64) database.FederationKey {
+	t.Helper()
+	privateKey, publicKey, err := httpsig.GenerateKey()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	return database.FederationKey{UserID: userID, PrivateKey: privateKey, PublicKey: publicKey}
+}
+
+// remote is another instance with a single actor.
+type remote struct {
+	*httptest.Server
+	key database.FederationKey
+
+	mu       sync.Mutex
+	received []inboundActivity
+	fetches  int
+}
+
+func newRemote(t *testing.T) *remote {
+	t.Helper()
+	r := &remote{key: newKey(t, 0)}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/actor":
+			r.mu.Lock()
+			r.fetches++
+			r.mu.Unlock()
+			w.Header().Set("Content-Type", ContentType)
+			_ = json.NewEncoder(w).Encode(Actor{
+				ID:    r.actorID(),
+				Type:  "Person",
+				Name:  "Grace Hopper",
+				Inbox: r.URL + "/inbox",
+				PublicKey: PublicKey{
+					ID:           r.actorID() + "#main-key",
+					Owner:        r.actorID(),
+					PublicKeyPem: r.key.PublicKey,
+				},
+			})
+		case "/inbox":
+			var activity inboundActivity
+			_ = json.NewDecoder(req.Body).Decode(&activity)
+			r.mu.Lock()
+			r.received = append(r.received, activity)
+			r.mu.Unlock()
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *remote) actorID() string {
+	return r.URL + "/actor"
+}
+
+// post signs an activity as the remote actor and posts it to the inbox of
+// local user 1.
+func (r *remote) post(t *testing.T, h http.Handler, activity map[string]any, sign bool) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(activity)
+	if err != nil {
+		t.Fatalf("encoding activity: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, origin+"/federation/users/1/inbox", bytes.NewReader(body))
+	if sign {
+		key, err := httpsig.ParsePrivateKey(r.key.PrivateKey)
+		if err != nil {
+			t.Fatalf("parsing key: %v", err)
+		}
+		if err := httpsig.Sign(req, r.actorID()+"#main-key", key, body, now); err != nil {
+			t.Fatalf("signing: %v", err)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func expectLocalUser(mockDB *database.MockQuerier, key database.FederationKey) {
+	mockDB.EXPECT().
+		GetFederationActor(gomock.Any(), int64(1)).
+		Return(database.GetFederationActorRow{ID: 1, FirstName: "Ada", LastName: "Lovelace"}, nil).
+		AnyTimes()
+	mockDB.EXPECT().GetFederationKey(gomock.Any(), int64(1)).Return(key, nil).AnyTimes()
+}
+
+func TestParseHandle(t *testing.T) {
+	tests := []struct {
+		handle   string
+		wantUser string
+		wantHost string
+		wantErr  bool
+	}{
+		{handle: "7@cook.example", wantUser: "7", wantHost: "cook.example"},
+		{handle: "@7@Cook.Example", wantUser: "7", wantHost: "cook.example"},
+		{handle: "acct:7@cook.example:8443", wantUser: "7", wantHost: "cook.example:8443"},
+		{handle: "cook.example", wantErr: true},
+		{handle: "7@", wantErr: true},
+		{handle: "7@cook.example/path", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.handle, func(t *testing.T) {
+			user, host, err := ParseHandle(tt.handle)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHandle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if user != tt.wantUser || host != tt.wantHost {
+				t.Errorf("ParseHandle() = %q, %q, want %q, %q", user, host, tt.wantUser, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestHandler_WebFingerAndActor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	key := newKey(t, 1)
+	expectLocalUser(mockDB, key)
+	handler := Handler(newEnv(mockDB))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+		WebFingerPath+"?resource=acct:1@wecook.test", nil))
+	var jrd webFinger
+	if err := json.NewDecoder(rec.Body).Decode(&jrd); err != nil {
+		t.Fatalf("decoding webfinger: %v", err)
+	}
+	if len(jrd.Links) != 1 || jrd.Links[0].Href != origin+"/federation/users/1" {
+		t.Fatalf("unexpected webfinger response %+v", jrd)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, jrd.Links[0].Href, nil))
+	var actor Actor
+	if err := json.NewDecoder(rec.Body).Decode(&actor); err != nil {
+		t.Fatalf("decoding actor: %v", err)
+	}
+	if actor.Inbox != jrd.Links[0].Href+"/inbox" || actor.Name != "Ada Lovelace" ||
+		actor.PublicKey.PublicKeyPem != key.PublicKey {
+		t.Errorf("unexpected actor %+v", actor)
+	}
+
+	// Users of other hosts are not found.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+		WebFingerPath+"?resource=acct:1@cook.example", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another host, got %d", rec.Code)
+	}
+}
+
+func TestHandler_WebFingerUnknownUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockDB.EXPECT().
+		GetFederationActor(gomock.Any(), int64(2)).
+		Return(database.GetFederationActorRow{}, pgx.ErrNoRows)
+
+	rec := httptest.NewRecorder()
+	Handler(newEnv(mockDB)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+		WebFingerPath+"?resource=acct:2@wecook.test", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestInbox_Follow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	remote := newRemote(t)
+	expectLocalUser(mockDB, newKey(t, 1))
+	mockDB.EXPECT().
+		AddFederationFollower(gomock.Any(), database.AddFederationFollowerParams{
+			UserID: 1,
+			Actor:  remote.actorID(),
+			Inbox:  remote.URL + "/inbox",
+		}).
+		Return(nil)
+	mockDB.EXPECT().
+		CreateDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params database.CreateDeliveryParams) (database.Delivery, error) {
+			return database.Delivery{ID: 1, Kind: params.Kind, Payload: params.Payload, Retry: params.Retry}, nil
+		})
+	mockDB.EXPECT().MarkDeliverySent(gomock.Any(), gomock.Any()).Return(nil)
+
+	rec := remote.post(t, Handler(newEnv(mockDB)), map[string]any{
+		"id":     remote.actorID() + "#follow",
+		"type":   "Follow",
+		"actor":  remote.actorID(),
+		"object": origin + "/federation/users/1",
+	}, true)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body)
+	}
+	if len(remote.received) != 1 || remote.received[0].Type != "Accept" {
+		t.Errorf("expected the follow to be accepted, got %+v", remote.received)
+	}
+}
+
+func TestInbox_CachesActorKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	remote := newRemote(t)
+	expectLocalUser(mockDB, newKey(t, 1))
+	mockDB.EXPECT().AddFederationFollower(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockDB.EXPECT().
+		CreateDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params database.CreateDeliveryParams) (database.Delivery, error) {
+			return database.Delivery{ID: 1, Kind: params.Kind, Payload: params.Payload, Retry: params.Retry}, nil
+		}).
+		Times(2)
+	mockDB.EXPECT().MarkDeliverySent(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	h := Handler(newEnv(mockDB))
+	for range 2 {
+		rec := remote.post(t, h, map[string]any{
+			"id":     remote.actorID() + "#follow",
+			"type":   "Follow",
+			"actor":  remote.actorID(),
+			"object": origin + "/federation/users/1",
+		}, true)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body)
+		}
+	}
+	if remote.fetches != 1 {
+		t.Errorf("expected the actor to be fetched once, got %d", remote.fetches)
+	}
+}
+
+func TestFetchActor_RefusesNonPublicAddresses(t *testing.T) {
+	remote := newRemote(t)
+	redirect := httptest.NewServer(http.RedirectHandler(remote.actorID(), http.StatusFound))
+	t.Cleanup(redirect.Close)
+
+	tests := []struct {
+		name string
+		url  string
+		// dials is the number of connections allowed before the
+		// addresses of the tests are refused.
+		dials int
+	}{
+		{name: "loopback", url: remote.actorID()},
+		{name: "redirect to loopback", url: redirect.URL, dials: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dials := 0
+			e := newEnv(nil)
+			e.FederationHTTP = wcHttp.NewGuarded(func(netip.Addr) bool {
+				dials++
+				return dials <= tt.dials
+			}, time.Second)
+
+			_, err := FetchActor(context.Background(), e, tt.url)
+			if !errors.Is(err, wcHttp.ErrForbiddenAddress) {
+				t.Errorf("expected %v, got %v", wcHttp.ErrForbiddenAddress, err)
+			}
+			if remote.fetches != 0 {
+				t.Errorf("expected the actor not to be fetched, got %d fetches", remote.fetches)
+			}
+		})
+	}
+}
+
+func TestInbox_Create(t *testing.T) {
+	remoteRecipe := func(remote *remote, attributedTo string) map[string]any {
+		return map[string]any{
+			"id":    remote.actorID() + "#create",
+			"type":  "Create",
+			"actor": remote.actorID(),
+			"object": map[string]any{
+				"id":                 remote.URL + "/federation/recipes/9",
+				"type":               "Article",
+				"attributedTo":       attributedTo,
+				"name":               "Shakshuka",
+				"url":                remote.URL + "/recipes/9",
+				"published":          now.Add(-time.Hour),
+				"recipeIngredient":   []string{"6 eggs"},
+				"recipeInstructions": []string{"Simmer the sauce.", "Poach the eggs."},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		sign       bool
+		other      bool
+		setup      func(mockDB *database.MockQuerier, remote *remote)
+		wantStatus int
+	}{
+		{
+			name: "followed actor",
+			sign: true,
+			setup: func(mockDB *database.MockQuerier, remote *remote) {
+				mockDB.EXPECT().IsFederationActorFollowed(gomock.Any(), remote.actorID()).Return(true, nil)
+				mockDB.EXPECT().
+					UpsertFederatedRecipe(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params database.UpsertFederatedRecipeParams) (int64, error) {
+						if params.Title != "Shakshuka" || params.Author != "Grace Hopper" ||
+							params.Url != remote.URL+"/recipes/9" || len(params.Steps) != 2 ||
+							!params.PublishedAt.Time.Equal(now.Add(-time.Hour)) {
+							t.Errorf("unexpected recipe %+v", params)
+						}
+						return 1, nil
+					})
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name: "unfollowed actor",
+			sign: true,
+			setup: func(mockDB *database.MockQuerier, remote *remote) {
+				mockDB.EXPECT().IsFederationActorFollowed(gomock.Any(), remote.actorID()).Return(false, nil)
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "attributed to another actor",
+			sign:       true,
+			other:      true,
+			setup:      func(*database.MockQuerier, *remote) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unsigned",
+			setup:      func(*database.MockQuerier, *remote) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := database.NewMockQuerier(ctrl)
+			remote := newRemote(t)
+			expectLocalUser(mockDB, newKey(t, 1))
+			tt.setup(mockDB, remote)
+
+			attributedTo := remote.actorID()
+			if tt.other {
+				attributedTo = strings.Replace(attributedTo, "/actor", "/someone-else", 1)
+			}
+			rec := remote.post(t, Handler(newEnv(mockDB)), remoteRecipe(remote, attributedTo), tt.sign)
+			if rec.Code != tt.wantStatus {
+				body, _ := io.ReadAll(rec.Body)
+				t.Errorf("expected %d, got %d: %s", tt.wantStatus, rec.Code, body)
+			}
+		})
+	}
+}
+
+func TestRecipeContent(t *testing.T) {
+	got := recipeContent(Recipe{
+		Summary:      "Eggs & tomatoes",
+		Ingredients:  []string{"6 eggs"},
+		Instructions: []string{"Poach the <eggs>."},
+	})
+	want := "<p>Eggs &amp; tomatoes</p><ul><li>6 eggs</li></ul><ol><li>Poach the &lt;eggs&gt;.</li></ol>"
+	if got != want {
+		t.Errorf("recipeContent() = %q, want %q", got, want)
+	}
+}
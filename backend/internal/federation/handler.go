@@ -0,0 +1,293 @@
+package federation
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	apiError "github.com/matt-dz/wecook/internal/api/error"
+	"github.com/matt-dz/wecook/internal/api/requestid"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/httpsig"
+)
+
+// Handler serves WebFinger, the actors of users with published recipes,
+// their outboxes, followers, and inboxes, and the objects of published
+// recipes.
+func Handler(env *env.Env) http.Handler {
+	h := handler{env: env, actors: newActorCache()}
+	router := chi.NewRouter()
+	router.Get(WebFingerPath, h.webFinger)
+	router.Route(Prefix, func(r chi.Router) {
+		r.Get("/users/{userID}", h.actor)
+		r.Get("/users/{userID}/outbox", h.outbox)
+		r.Get("/users/{userID}/followers", h.followers)
+		r.Post("/users/{userID}/inbox", h.inbox)
+		r.Get("/recipes/{recipeID}", h.recipe)
+	})
+	return router
+}
+
+type handler struct {
+	env    *env.Env
+	actors *actorCache
+}
+
+func (h handler) webFinger(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	user, domain, err := ParseHandle(r.URL.Query().Get("resource"))
+	if err != nil || domain != strings.ToLower(host(h.env.Config.HostOrigin)) {
+		_ = apiError.EncodeError(w, apiError.UserNotFound, "unknown resource", requestID)
+		return
+	}
+	userID, err := strconv.ParseInt(user, 10, 64)
+	if err != nil {
+		_ = apiError.EncodeError(w, apiError.UserNotFound, "unknown resource", requestID)
+		return
+	}
+	if _, ok := h.getActor(w, r, userID); !ok {
+		return
+	}
+
+	writeJSON(w, "application/jrd+json", webFinger{
+		Subject: "acct:" + Handle(h.env.Config.HostOrigin, userID),
+		Links: []webFingerLink{{
+			Rel:  "self",
+			Type: ContentType,
+			Href: ActorID(h.env.Config.HostOrigin, userID),
+		}},
+	})
+}
+
+func (h handler) actor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	if actor, ok := h.getActor(w, r, userID); ok {
+		writeJSON(w, ContentType, actor)
+	}
+}
+
+func (h handler) outbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getActor(w, r, userID); !ok {
+		return
+	}
+
+	// Get recent recipes
+	h.env.Logger.DebugContext(ctx, "getting published recipes")
+	recipes, err := h.env.Database.GetPublishedRecipesByOwner(ctx, database.GetPublishedRecipesByOwnerParams{
+		UserID: pgtype.Int8{Int64: userID, Valid: true},
+		Limit:  OutboxSize,
+	})
+	if err != nil {
+		h.env.Logger.ErrorContext(ctx, "failed to get published recipes", slog.Any("error", err))
+		_ = apiError.EncodeInternalError(w, requestID)
+		return
+	}
+
+	actorID := ActorID(h.env.Config.HostOrigin, userID)
+	items := make([]any, 0, len(recipes))
+	for _, recipe := range recipes {
+		// The outbox is a preview; ingredients and steps are fetched with
+		// the object.
+		object := newRecipe(h.env, recipe, nil, nil)
+		items = append(items, Activity{
+			ID:     object.ID + "#create",
+			Type:   "Create",
+			Actor:  actorID,
+			To:     []string{Public},
+			Object: object,
+		})
+	}
+	writeJSON(w, ContentType, OrderedCollection{
+		Context:      activityStreams[0],
+		ID:           actorID + "/outbox",
+		Type:         "OrderedCollection",
+		TotalItems:   len(items),
+		OrderedItems: items,
+	})
+}
+
+func (h handler) followers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getActor(w, r, userID); !ok {
+		return
+	}
+
+	// Followers are counted, not listed.
+	h.env.Logger.DebugContext(ctx, "getting followers")
+	followers, err := h.env.Database.GetFederationFollowers(ctx, userID)
+	if err != nil {
+		h.env.Logger.ErrorContext(ctx, "failed to get followers", slog.Any("error", err))
+		_ = apiError.EncodeInternalError(w, requestID)
+		return
+	}
+	writeJSON(w, ContentType, OrderedCollection{
+		Context:    activityStreams[0],
+		ID:         ActorID(h.env.Config.HostOrigin, userID) + "/followers",
+		Type:       "OrderedCollection",
+		TotalItems: len(followers),
+	})
+}
+
+func (h handler) recipe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	recipeID, err := strconv.ParseInt(chi.URLParam(r, "recipeID"), 10, 64)
+	if err != nil {
+		_ = apiError.EncodeError(w, apiError.RecipeNotFound, "recipe not found", requestID)
+		return
+	}
+
+	h.env.Logger.DebugContext(ctx, "getting published recipe")
+	recipe, err := getRecipe(ctx, h.env, recipeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		_ = apiError.EncodeError(w, apiError.RecipeNotFound, "recipe not found", requestID)
+		return
+	} else if err != nil {
+		h.env.Logger.ErrorContext(ctx, "failed to get recipe", slog.Any("error", err))
+		_ = apiError.EncodeInternalError(w, requestID)
+		return
+	}
+	recipe.Context = activityStreams[0]
+	writeJSON(w, ContentType, recipe)
+}
+
+func (h handler) inbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getActor(w, r, userID); !ok {
+		return
+	}
+
+	// Read activity
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxDocumentSize+1))
+	if err != nil || len(body) > MaxDocumentSize {
+		_ = apiError.EncodeError(w, apiError.BadRequest, "activity is too large or unreadable", requestID)
+		return
+	}
+	var activity inboundActivity
+	if err := json.Unmarshal(body, &activity); err != nil || activity.Type == "" || activity.Actor == "" {
+		_ = apiError.EncodeError(w, apiError.BadRequest, "invalid activity", requestID)
+		return
+	}
+
+	// Verify signature
+	h.env.Logger.DebugContext(ctx, "verifying activity signature",
+		slog.String("type", activity.Type), slog.String("actor", activity.Actor))
+	sender, err := h.verify(ctx, r, body)
+	if err != nil {
+		h.env.Logger.WarnContext(ctx, "rejected activity", slog.Any("error", err))
+		_ = apiError.EncodeError(w, apiError.InvalidSignature, "invalid signature", requestID)
+		return
+	}
+	if sender.ID != activity.Actor {
+		_ = apiError.EncodeError(w, apiError.InvalidSignature, "activity not signed by its actor", requestID)
+		return
+	}
+
+	if err := h.receive(ctx, userID, sender, activity); err != nil {
+		if errors.Is(err, errInvalidActivity) {
+			_ = apiError.EncodeError(w, apiError.BadRequest, err.Error(), requestID)
+			return
+		}
+		h.env.Logger.ErrorContext(ctx, "failed to process activity", slog.Any("error", err))
+		_ = apiError.EncodeInternalError(w, requestID)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// verify checks the signature of an activity and returns the actor who
+// signed it.
+func (h handler) verify(ctx context.Context, r *http.Request, body []byte) (Actor, error) {
+	// Activities are sent to the inbox published on the actor, which may
+	// be behind a proxy that changes the Host header.
+	r.Host = host(h.env.Config.HostOrigin)
+
+	var signer Actor
+	_, err := httpsig.Verify(r, body, h.env.Now(), func(keyID string) (*rsa.PublicKey, error) {
+		actorID, _, _ := strings.Cut(keyID, "#")
+		actor, err := h.actors.get(ctx, h.env, actorID, keyID)
+		if err != nil {
+			return nil, err
+		}
+		if actor.PublicKey.ID != keyID || actor.PublicKey.Owner != actor.ID {
+			return nil, fmt.Errorf("%w: key %s is not the key of %s", httpsig.ErrInvalidSignature, keyID, actor.ID)
+		}
+		signer = actor
+		return httpsig.ParsePublicKey(actor.PublicKey.PublicKeyPem)
+	})
+	return signer, err
+}
+
+// userID returns the user of a federation path, writing an error if it is
+// malformed.
+func (h handler) userID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		requestID := strconv.FormatUint(requestid.ExtractRequestID(r.Context()), 10)
+		_ = apiError.EncodeError(w, apiError.UserNotFound, "user not found", requestID)
+		return 0, false
+	}
+	return userID, true
+}
+
+// getActor returns the actor of a user, writing an error if the user does
+// not exist or has no published recipes.
+func (h handler) getActor(w http.ResponseWriter, r *http.Request, userID int64) (Actor, bool) {
+	ctx := r.Context()
+	requestID := strconv.FormatUint(requestid.ExtractRequestID(ctx), 10)
+
+	h.env.Logger.DebugContext(ctx, "getting federated user", slog.Int64("user_id", userID))
+	user, err := h.env.Database.GetFederationActor(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		_ = apiError.EncodeError(w, apiError.UserNotFound, "user not found", requestID)
+		return Actor{}, false
+	} else if err != nil {
+		h.env.Logger.ErrorContext(ctx, "failed to get user", slog.Any("error", err))
+		_ = apiError.EncodeInternalError(w, requestID)
+		return Actor{}, false
+	}
+	key, err := userKey(ctx, h.env, userID)
+	if err != nil {
+		h.env.Logger.ErrorContext(ctx, "failed to get federation key", slog.Any("error", err))
+		_ = apiError.EncodeInternalError(w, requestID)
+		return Actor{}, false
+	}
+	return newActor(h.env, user, key), true
+}
+
+func writeJSON(w http.ResponseWriter, contentType string, v any) {
+	w.Header().Set("Content-Type", contentType)
+	_ = json.NewEncoder(w).Encode(v)
+}
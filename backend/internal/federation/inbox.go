@@ -0,0 +1,159 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/matt-dz/wecook/internal/database"
+)
+
+// errInvalidActivity is returned for activities that are understood but
+// not acceptable, such as a recipe attributed to another actor.
+var errInvalidActivity = errors.New("invalid activity")
+
+// receive processes an activity sent by sender to the inbox of a user.
+// Activities that are not understood are ignored.
+func (h handler) receive(ctx context.Context, userID int64, sender Actor, activity inboundActivity) error {
+	env := h.env
+	local := ActorID(env.Config.HostOrigin, userID)
+
+	switch activity.Type {
+	case "Follow":
+		if objectID(activity.Object) != local {
+			return fmt.Errorf("%w: follow of another actor", errInvalidActivity)
+		}
+		env.Logger.DebugContext(ctx, "adding follower", slog.String("actor", sender.ID))
+		if err := env.Database.AddFederationFollower(ctx, database.AddFederationFollowerParams{
+			UserID: userID,
+			Actor:  sender.ID,
+			Inbox:  sender.Inbox,
+		}); err != nil {
+			return fmt.Errorf("adding follower: %w", err)
+		}
+		// Follows are accepted straight away; recipes are public anyway.
+		return send(ctx, env, userID, sender.Inbox, Activity{
+			ID:     local + "#accepts-" + env.NewID(),
+			Type:   "Accept",
+			Actor:  local,
+			Object: activity,
+		})
+
+	case "Undo":
+		var undone inboundActivity
+		if err := json.Unmarshal(activity.Object, &undone); err != nil || undone.Type != "Follow" {
+			return nil
+		}
+		if undone.Actor != sender.ID {
+			return fmt.Errorf("%w: undo of another actor's follow", errInvalidActivity)
+		}
+		env.Logger.DebugContext(ctx, "removing follower", slog.String("actor", sender.ID))
+		if _, err := env.Database.DeleteFederationFollower(ctx, database.DeleteFederationFollowerParams{
+			UserID: userID,
+			Actor:  sender.ID,
+		}); err != nil {
+			return fmt.Errorf("removing follower: %w", err)
+		}
+		return nil
+
+	case "Accept":
+		// The follow may be embedded or referenced by ID.
+		var follow inboundActivity
+		_ = json.Unmarshal(activity.Object, &follow)
+		if follow.Actor != local && !strings.HasPrefix(objectID(activity.Object), local+"#follows-") {
+			return nil
+		}
+		env.Logger.DebugContext(ctx, "follow accepted", slog.String("actor", sender.ID))
+		if _, err := env.Database.AcceptFederationFollowing(ctx, database.AcceptFederationFollowingParams{
+			UserID: userID,
+			Actor:  sender.ID,
+		}); err != nil {
+			return fmt.Errorf("accepting follow: %w", err)
+		}
+		return nil
+
+	case "Create", "Update":
+		var recipe Recipe
+		if err := json.Unmarshal(activity.Object, &recipe); err != nil || recipe.Type != "Article" {
+			return nil
+		}
+		if recipe.AttributedTo != sender.ID || !sameHost(recipe.ID, sender.ID) {
+			return fmt.Errorf("%w: recipe attributed to another actor", errInvalidActivity)
+		}
+		if recipe.Name == "" {
+			return fmt.Errorf("%w: recipe has no name", errInvalidActivity)
+		}
+		followed, err := env.Database.IsFederationActorFollowed(ctx, sender.ID)
+		if err != nil {
+			return fmt.Errorf("checking follow: %w", err)
+		}
+		if !followed {
+			env.Logger.DebugContext(ctx, "ignoring recipe of unfollowed actor", slog.String("actor", sender.ID))
+			return nil
+		}
+		return h.store(ctx, sender, recipe)
+
+	case "Delete":
+		env.Logger.DebugContext(ctx, "deleting federated recipe", slog.String("actor", sender.ID))
+		if _, err := env.Database.DeleteFederatedRecipe(ctx, database.DeleteFederatedRecipeParams{
+			ObjectID: objectID(activity.Object),
+			Actor:    sender.ID,
+		}); err != nil {
+			return fmt.Errorf("deleting federated recipe: %w", err)
+		}
+		return nil
+
+	default:
+		env.Logger.DebugContext(ctx, "ignoring activity", slog.String("type", activity.Type))
+		return nil
+	}
+}
+
+// store saves a recipe of a followed actor for the federated feed.
+func (h handler) store(ctx context.Context, sender Actor, recipe Recipe) error {
+	author := sender.Name
+	if author == "" {
+		author = sender.PreferredUsername
+	}
+	page := recipe.URL
+	if !sameHost(page, sender.ID) {
+		page = recipe.ID
+	}
+	published := recipe.Published
+	if published.IsZero() {
+		published = h.env.Now()
+	}
+
+	params := database.UpsertFederatedRecipeParams{
+		ObjectID:    recipe.ID,
+		Actor:       sender.ID,
+		Author:      author,
+		Url:         page,
+		Title:       recipe.Name,
+		Description: pgtype.Text{String: recipe.Summary, Valid: recipe.Summary != ""},
+		Ingredients: recipe.Ingredients,
+		Steps:       recipe.Instructions,
+		PublishedAt: pgtype.Timestamptz{Time: published, Valid: true},
+	}
+	if params.Ingredients == nil {
+		params.Ingredients = []string{}
+	}
+	if params.Steps == nil {
+		params.Steps = []string{}
+	}
+	if recipe.Image != nil && (strings.HasPrefix(recipe.Image.URL, "https://") ||
+		strings.HasPrefix(recipe.Image.URL, "http://")) {
+		params.ImageUrl = pgtype.Text{String: recipe.Image.URL, Valid: true}
+	}
+
+	h.env.Logger.DebugContext(ctx, "storing federated recipe", slog.String("object_id", recipe.ID))
+	if _, err := h.env.Database.UpsertFederatedRecipe(ctx, params); err != nil {
+		return fmt.Errorf("storing federated recipe: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/httpsig"
+)
+
+// userKey returns the federation key of a user, creating it the first
+// time it is needed.
+func userKey(ctx context.Context, env *env.Env, userID int64) (database.FederationKey, error) {
+	key, err := env.Database.GetFederationKey(ctx, userID)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return database.FederationKey{}, fmt.Errorf("getting federation key: %w", err)
+	}
+
+	env.Logger.DebugContext(ctx, "creating federation key")
+	privateKey, publicKey, err := httpsig.GenerateKey()
+	if err != nil {
+		return database.FederationKey{}, err
+	}
+	// A key created concurrently wins; the one created here is dropped.
+	key, err = env.Database.CreateFederationKey(ctx, database.CreateFederationKeyParams{
+		UserID:     userID,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	})
+	if err != nil {
+		return database.FederationKey{}, fmt.Errorf("creating federation key: %w", err)
+	}
+	return key, nil
+}
+
+// newActor returns the actor of a local user.
+func newActor(env *env.Env, user database.GetFederationActorRow, key database.FederationKey) Actor {
+	origin := env.Config.HostOrigin
+	id := ActorID(origin, user.ID)
+	return Actor{
+		Context:           activityStreams,
+		ID:                id,
+		Type:              "Person",
+		PreferredUsername: strconv.FormatInt(user.ID, 10),
+		Name:              strings.TrimSpace(user.FirstName + " " + user.LastName),
+		Inbox:             id + "/inbox",
+		Outbox:            id + "/outbox",
+		Followers:         id + "/followers",
+		PublicKey: PublicKey{
+			ID:           KeyID(origin, user.ID),
+			Owner:        id,
+			PublicKeyPem: key.PublicKey,
+		},
+	}
+}
+
+// newRecipe returns the object of a published recipe.
+func newRecipe(env *env.Env, recipe database.Recipe, ingredients []database.RecipeIngredient,
+	steps []database.RecipeStep,
+) Recipe {
+	origin := env.Config.HostOrigin
+	actor := ActorID(origin, recipe.UserID.Int64)
+	object := Recipe{
+		ID:           RecipeID(origin, recipe.ID),
+		Type:         "Article",
+		AttributedTo: actor,
+		Name:         recipe.Title,
+		Summary:      recipe.Description.String,
+		URL:          recipePage(origin, recipe.ID),
+		Published:    recipe.CreatedAt.Time.UTC(),
+		To:           []string{Public},
+	}
+	if recipe.UpdatedAt.Valid && recipe.UpdatedAt.Time.After(recipe.CreatedAt.Time) {
+		updated := recipe.UpdatedAt.Time.UTC()
+		object.Updated = &updated
+	}
+	if recipe.ImageKey.Valid {
+		object.Image = &Image{Type: "Image", URL: env.FileStore.FileURL(recipe.ImageKey.String)}
+	}
+	for _, ingredient := range ingredients {
+		if ingredient.Description.Valid && ingredient.Description.String != "" {
+			object.Ingredients = append(object.Ingredients, ingredient.Description.String)
+		}
+	}
+	for _, step := range steps {
+		if step.Instruction.Valid && step.Instruction.String != "" {
+			object.Instructions = append(object.Instructions, step.Instruction.String)
+		}
+	}
+	object.Content = recipeContent(object)
+	return object
+}
+
+// recipeContent renders a recipe as HTML for servers that do not know
+// the schema.org properties.
+func recipeContent(recipe Recipe) string {
+	var b strings.Builder
+	if recipe.Summary != "" {
+		fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(recipe.Summary))
+	}
+	if len(recipe.Ingredients) > 0 {
+		b.WriteString("<ul>")
+		for _, ingredient := range recipe.Ingredients {
+			fmt.Fprintf(&b, "<li>%s</li>", html.EscapeString(ingredient))
+		}
+		b.WriteString("</ul>")
+	}
+	if len(recipe.Instructions) > 0 {
+		b.WriteString("<ol>")
+		for _, instruction := range recipe.Instructions {
+			fmt.Fprintf(&b, "<li>%s</li>", html.EscapeString(instruction))
+		}
+		b.WriteString("</ol>")
+	}
+	return b.String()
+}
+
+// getRecipe returns the object of a published recipe.
+func getRecipe(ctx context.Context, env *env.Env, recipeID int64) (Recipe, error) {
+	row, err := env.Database.GetPublishedRecipeAndOwner(ctx, recipeID)
+	if err != nil {
+		return Recipe{}, err
+	}
+	ingredients, err := env.Database.GetRecipeIngredients(ctx, recipeID)
+	if err != nil {
+		return Recipe{}, fmt.Errorf("getting ingredients: %w", err)
+	}
+	steps, err := env.Database.GetRecipeSteps(ctx, recipeID)
+	if err != nil {
+		return Recipe{}, fmt.Errorf("getting steps: %w", err)
+	}
+	return newRecipe(env, database.Recipe{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		ImageKey:    row.ImageKey,
+		Title:       row.Title,
+		Description: row.Description,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		Published:   row.Published,
+	}, ingredients, steps), nil
+}
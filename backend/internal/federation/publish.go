@@ -0,0 +1,167 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/delivery"
+	"github.com/matt-dz/wecook/internal/env"
+)
+
+// Publish sends a published recipe to the followers of its owner.
+func Publish(ctx context.Context, env *env.Env, recipeID int64) error {
+	recipe, err := getRecipe(ctx, env, recipeID)
+	if err != nil {
+		return fmt.Errorf("getting recipe: %w", err)
+	}
+	userID, _ := localUser(env.Config.HostOrigin, recipe.AttributedTo)
+	return broadcast(ctx, env, userID, Activity{
+		ID:     recipe.ID + "#create-" + env.NewID(),
+		Type:   "Create",
+		Actor:  recipe.AttributedTo,
+		To:     []string{Public},
+		Object: recipe,
+	})
+}
+
+// Retract tells the followers of a user that a recipe was unpublished or
+// deleted.
+func Retract(ctx context.Context, env *env.Env, userID, recipeID int64) error {
+	actor := ActorID(env.Config.HostOrigin, userID)
+	id := RecipeID(env.Config.HostOrigin, recipeID)
+	return broadcast(ctx, env, userID, Activity{
+		ID:     id + "#delete-" + env.NewID(),
+		Type:   "Delete",
+		Actor:  actor,
+		To:     []string{Public},
+		Object: map[string]string{"id": id, "type": "Tombstone"},
+	})
+}
+
+// broadcast sends an activity of a user to each of their followers.
+func broadcast(ctx context.Context, env *env.Env, userID int64, activity Activity) error {
+	followers, err := env.Database.GetFederationFollowers(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("getting followers: %w", err)
+	}
+	if len(followers) == 0 {
+		return nil
+	}
+
+	env.Logger.DebugContext(ctx, "sending activity to followers",
+		slog.String("type", activity.Type), slog.Int("followers", len(followers)))
+	inboxes := make(map[string]bool, len(followers))
+	for _, follower := range followers {
+		if inboxes[follower.Inbox] {
+			continue
+		}
+		inboxes[follower.Inbox] = true
+		if err := send(ctx, env, userID, follower.Inbox, activity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Follow follows the actor of a handle on behalf of a user. The follow is
+// pending until the remote instance accepts it.
+func Follow(ctx context.Context, env *env.Env, userID int64, handle string) (database.FederationFollowing, error) {
+	env.Logger.DebugContext(ctx, "resolving handle", slog.String("handle", handle))
+	actor, err := Resolve(ctx, env, handle)
+	if err != nil {
+		return database.FederationFollowing{}, err
+	}
+	if sameHost(actor.ID, env.Config.HostOrigin) {
+		return database.FederationFollowing{}, ErrLocalActor
+	}
+
+	user, host, _ := ParseHandle(handle)
+	name := actor.Name
+	if name == "" {
+		name = actor.PreferredUsername
+	}
+	following, err := env.Database.CreateFederationFollowing(ctx, database.CreateFederationFollowingParams{
+		UserID: userID,
+		Actor:  actor.ID,
+		Handle: user + "@" + host,
+		Name:   name,
+		Inbox:  actor.Inbox,
+	})
+	if err != nil {
+		return database.FederationFollowing{}, fmt.Errorf("recording follow: %w", err)
+	}
+
+	if err := send(ctx, env, userID, actor.Inbox, followActivity(env, following)); err != nil {
+		return database.FederationFollowing{}, err
+	}
+	return following, nil
+}
+
+// Unfollow stops following a remote actor on behalf of a user. Federated
+// recipes of the actor are removed once no one follows it.
+func Unfollow(ctx context.Context, env *env.Env, userID, followingID int64) error {
+	var following database.FederationFollowing
+	err := env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		var err error
+		following, err = tx.DeleteFederationFollowing(ctx, database.DeleteFederationFollowingParams{
+			ID:     followingID,
+			UserID: userID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFollowing
+		} else if err != nil {
+			return fmt.Errorf("deleting follow: %w", err)
+		}
+
+		if err := tx.DeleteUnfollowedFederatedRecipes(ctx, following.Actor); err != nil {
+			return fmt.Errorf("deleting federated recipes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	follow := followActivity(env, following)
+	return send(ctx, env, userID, following.Inbox, Activity{
+		ID:     follow.Actor + "#undo-follows-" + strconv.FormatInt(following.ID, 10),
+		Type:   "Undo",
+		Actor:  follow.Actor,
+		Object: follow,
+	})
+}
+
+// followActivity returns the Follow activity of a follow.
+func followActivity(env *env.Env, following database.FederationFollowing) Activity {
+	actor := ActorID(env.Config.HostOrigin, following.UserID)
+	return Activity{
+		ID:     actor + "#follows-" + strconv.FormatInt(following.ID, 10),
+		Type:   "Follow",
+		Actor:  actor,
+		Object: following.Actor,
+	}
+}
+
+// send signs an activity of a user and delivers it to inbox.
+func send(ctx context.Context, env *env.Env, userID int64, inbox string, activity Activity) error {
+	if _, err := userKey(ctx, env, userID); err != nil {
+		return err
+	}
+	activity.Context = activityStreams[0]
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("encoding activity: %w", err)
+	}
+	env.Logger.DebugContext(ctx, "delivering activity",
+		slog.String("type", activity.Type), slog.String("inbox", inbox))
+	return delivery.QueueActivity(ctx, env, userID, KeyID(env.Config.HostOrigin, userID),
+		strings.TrimSpace(inbox), activity.Type, body)
+}
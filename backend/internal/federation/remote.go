@@ -0,0 +1,169 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/matt-dz/wecook/internal/env"
+	wcHttp "github.com/matt-dz/wecook/internal/http"
+)
+
+// webFinger is a WebFinger JRD document.
+type webFinger struct {
+	Subject string          `json:"subject"`
+	Links   []webFingerLink `json:"links"`
+}
+
+type webFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href,omitempty"`
+}
+
+// ParseHandle splits a handle such as "7@cook.example" or
+// "@7@cook.example" into its user and host.
+func ParseHandle(handle string) (user, host string, err error) {
+	handle = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(handle), "acct:"), "@")
+	user, host, ok := strings.Cut(handle, "@")
+	if !ok || user == "" || host == "" || strings.ContainsAny(host, "/?#@ ") {
+		return "", "", ErrInvalidHandle
+	}
+	return user, strings.ToLower(host), nil
+}
+
+// Resolve looks up the actor of a handle with WebFinger.
+func Resolve(ctx context.Context, env *env.Env, handle string) (Actor, error) {
+	user, host, err := ParseHandle(handle)
+	if err != nil {
+		return Actor{}, err
+	}
+
+	finger := url.URL{
+		Scheme:   remoteScheme(env),
+		Host:     host,
+		Path:     WebFingerPath,
+		RawQuery: url.Values{"resource": {"acct:" + user + "@" + host}}.Encode(),
+	}
+	var jrd webFinger
+	if err := fetch(ctx, env, finger.String(), "application/jrd+json", &jrd); err != nil {
+		return Actor{}, fmt.Errorf("%w: %w", ErrActorNotFound, err)
+	}
+	for _, link := range jrd.Links {
+		if link.Rel == "self" && link.Href != "" {
+			return FetchActor(ctx, env, link.Href)
+		}
+	}
+	return Actor{}, fmt.Errorf("%w: %s has no actor", ErrActorNotFound, handle)
+}
+
+// FetchActor fetches the actor document at id.
+func FetchActor(ctx context.Context, env *env.Env, id string) (Actor, error) {
+	var actor Actor
+	if err := fetch(ctx, env, id, ContentType, &actor); err != nil {
+		return Actor{}, fmt.Errorf("%w: %w", ErrActorNotFound, err)
+	}
+	if actor.ID != id || actor.Inbox == "" || !sameHost(actor.ID, actor.Inbox) {
+		return Actor{}, fmt.Errorf("%w: invalid actor document at %s", ErrActorNotFound, id)
+	}
+	return actor, nil
+}
+
+// fetch gets a remote JSON document and decodes it into v.
+func fetch(ctx context.Context, env *env.Env, rawURL, accept string, v any) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != remoteScheme(env)) {
+		return fmt.Errorf("invalid url %q", rawURL)
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	res, err := env.FederationHTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", u, err)
+	}
+	if err := wcHttp.ExpectStatus2xx(res); err != nil {
+		return fmt.Errorf("fetching %s: %w", u, err)
+	}
+	defer res.Body.Close()
+	if err := json.NewDecoder(io.LimitReader(res.Body, MaxDocumentSize)).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s: %w", u, err)
+	}
+	return nil
+}
+
+// actorCacheTTL is how long a fetched actor, and with it their key, is
+// used to verify their activities before it is fetched again.
+const actorCacheTTL = time.Hour
+
+// maxCachedActors bounds the number of actors held in an actorCache.
+const maxCachedActors = 1024
+
+// actorCache holds fetched actors so that the key of a sender is not
+// fetched again for every activity they send.
+type actorCache struct {
+	mu     sync.Mutex
+	actors map[string]cachedActor
+}
+
+type cachedActor struct {
+	actor   Actor
+	expires time.Time
+}
+
+func newActorCache() *actorCache {
+	return &actorCache{actors: make(map[string]cachedActor)}
+}
+
+// get returns the actor with id, fetching them if they are not cached or
+// their cached key is not keyID.
+func (c *actorCache) get(ctx context.Context, env *env.Env, id, keyID string) (Actor, error) {
+	now := env.Now()
+	c.mu.Lock()
+	cached, ok := c.actors[id]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expires) && cached.actor.PublicKey.ID == keyID {
+		return cached.actor, nil
+	}
+
+	actor, err := FetchActor(ctx, env, id)
+	if err != nil {
+		return Actor{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.actors) >= maxCachedActors {
+		for id, cached := range c.actors {
+			if !now.Before(cached.expires) {
+				delete(c.actors, id)
+			}
+		}
+	}
+	if len(c.actors) >= maxCachedActors {
+		clear(c.actors)
+	}
+	c.actors[id] = cachedActor{actor: actor, expires: now.Add(actorCacheTTL)}
+	return actor, nil
+}
+
+// remoteScheme is the scheme remote instances are reached with. Outside
+// of production, instances served over plain HTTP can federate with each
+// other for testing.
+func remoteScheme(env *env.Env) string {
+	if !env.IsProd() && strings.HasPrefix(env.Config.HostOrigin, "http://") {
+		return "http"
+	}
+	return "https"
+}
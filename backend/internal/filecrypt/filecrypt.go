@@ -0,0 +1,152 @@
+// Package filecrypt encrypts stored files at rest with AES-GCM. Keys are
+// derived from the app secret, and every file records the version of the
+// secret it was encrypted under so files survive secret rotation as long
+// as the previous secret is kept.
+package filecrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/matt-dz/wecook/internal/config"
+)
+
+var (
+	ErrUnknownKey = errors.New("unknown encryption key")
+	ErrMalformed  = errors.New("malformed encrypted file")
+)
+
+// magic starts every encrypted file. Files without it are stored in
+// plaintext and returned as-is by Decrypt.
+var magic = []byte("WCE\x01")
+
+const (
+	keyBytes = 32
+	keyInfo  = "wecook file encryption"
+)
+
+// Keyring encrypts with the current key and decrypts with any known key.
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring returns a keyring encrypting under the key with ID current.
+// keys maps key IDs to 32-byte AES keys.
+func NewKeyring(current string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, current)
+	}
+	k := &Keyring{current: current, keys: make(map[string]cipher.AEAD, len(keys))}
+	for _, id := range slices.Sorted(maps.Keys(keys)) {
+		if id == "" || len(id) > math.MaxUint8 {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		block, err := aes.NewCipher(keys[id])
+		if err != nil {
+			return nil, fmt.Errorf("creating cipher for key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("creating gcm for key %q: %w", id, err)
+		}
+		k.keys[id] = aead
+	}
+	return k, nil
+}
+
+// DeriveKey derives a file encryption key from an app secret.
+func DeriveKey(secret []byte) ([]byte, error) {
+	return hkdf.Key(sha256.New, secret, nil, keyInfo, keyBytes)
+}
+
+// FromConfig returns the keyring of the app secret and its previous
+// versions, or nil if file encryption is disabled.
+func FromConfig(conf config.Config) (*Keyring, error) {
+	if !conf.Fileserver.Encrypt {
+		return nil, nil
+	}
+	if conf.AppSecret.Value == nil {
+		return nil, errors.New("app secret is not loaded")
+	}
+
+	secrets := map[string][]byte{conf.AppSecret.Version: []byte(*conf.AppSecret.Value)}
+	for version, secret := range conf.AppSecret.Previous {
+		if version == conf.AppSecret.Version {
+			return nil, fmt.Errorf("previous app secret has the current version %q", version)
+		}
+		secrets[version] = []byte(secret)
+	}
+
+	keys := make(map[string][]byte, len(secrets))
+	for version, secret := range secrets {
+		key, err := DeriveKey(secret)
+		if err != nil {
+			return nil, fmt.Errorf("deriving key %q: %w", version, err)
+		}
+		keys[version] = key
+	}
+	return NewKeyring(conf.AppSecret.Version, keys)
+}
+
+// Encrypt encrypts plaintext under the current key. The result is the
+// magic, the key ID length and key ID, the nonce, and the sealed data.
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	aead := k.keys[k.current]
+	header := append(slices.Clone(magic), byte(len(k.current)))
+	header = append(header, k.current...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, header...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, header), nil
+}
+
+// Decrypt decrypts data written by Encrypt. Data that is not encrypted
+// is returned unchanged.
+func (k *Keyring) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+
+	rest := data[len(magic):]
+	if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+		return nil, ErrMalformed
+	}
+	idLen := int(rest[0])
+	id := string(rest[1 : 1+idLen])
+	headerLen := len(magic) + 1 + idLen
+
+	aead, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+	if len(data) < headerLen+aead.NonceSize()+aead.Overhead() {
+		return nil, ErrMalformed
+	}
+	nonce := data[headerLen : headerLen+aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, data[headerLen+aead.NonceSize():], data[:headerLen])
+	if err != nil {
+		return nil, errors.Join(ErrMalformed, err)
+	}
+	return plaintext, nil
+}
+
+// IsEncrypted reports whether data was written by Encrypt.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
@@ -0,0 +1,133 @@
+package filecrypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/matt-dz/wecook/internal/config"
+)
+
+func newKeyring(t *testing.T, current string, secrets map[string]string) *Keyring {
+	t.Helper()
+	keys := make(map[string][]byte, len(secrets))
+	for id, secret := range secrets {
+		key, err := DeriveKey([]byte(secret))
+		if err != nil {
+			t.Fatalf("DeriveKey() error = %v", err)
+		}
+		keys[id] = key
+	}
+	keyring, err := NewKeyring(current, keys)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	return keyring
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	keyring := newKeyring(t, "1", map[string]string{"1": "secret"})
+	plaintext := []byte("\x89PNG image data")
+
+	encrypted, err := keyring.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !IsEncrypted(encrypted) {
+		t.Error("expected encrypted data to be recognized")
+	}
+	if bytes.Contains(encrypted, plaintext) {
+		t.Error("expected encrypted data not to contain the plaintext")
+	}
+
+	decrypted, err := keyring.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypt() = %q, want %q", decrypted, plaintext)
+	}
+}
+
+func TestDecrypt_Plaintext(t *testing.T) {
+	keyring := newKeyring(t, "1", map[string]string{"1": "secret"})
+	plaintext := []byte("\x89PNG image data")
+
+	decrypted, err := keyring.Decrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypt() = %q, want plaintext unchanged", decrypted)
+	}
+}
+
+func TestDecrypt_Rotation(t *testing.T) {
+	old := newKeyring(t, "1", map[string]string{"1": "first"})
+	encrypted, err := old.Encrypt([]byte("image"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	rotated := newKeyring(t, "2", map[string]string{"1": "first", "2": "second"})
+	if decrypted, err := rotated.Decrypt(encrypted); err != nil || string(decrypted) != "image" {
+		t.Errorf("Decrypt() = %q, %v; want file of previous key", decrypted, err)
+	}
+
+	dropped := newKeyring(t, "2", map[string]string{"2": "second"})
+	if _, err := dropped.Decrypt(encrypted); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt() error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	keyring := newKeyring(t, "1", map[string]string{"1": "secret"})
+	encrypted, err := keyring.Encrypt([]byte("image"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tampered := bytes.Clone(encrypted)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := keyring.Decrypt(tampered); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Decrypt() error = %v, want ErrMalformed", err)
+	}
+	if _, err := keyring.Decrypt(encrypted[:len(magic)+2]); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Decrypt() of truncated file error = %v, want ErrMalformed", err)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	secret := config.AppSecretValue("current-secret")
+	conf := config.Config{
+		AppSecret: config.AppSecret{
+			Value:    &secret,
+			Version:  "2",
+			Previous: map[string]string{"1": "old-secret"},
+		},
+	}
+
+	keyring, err := FromConfig(conf)
+	if err != nil || keyring != nil {
+		t.Fatalf("FromConfig() = %v, %v; want nil keyring when disabled", keyring, err)
+	}
+
+	conf.Fileserver.Encrypt = true
+	keyring, err = FromConfig(conf)
+	if err != nil {
+		t.Fatalf("FromConfig() error = %v", err)
+	}
+	old := newKeyring(t, "1", map[string]string{"1": "old-secret"})
+	encrypted, err := old.Encrypt([]byte("image"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if _, err := keyring.Decrypt(encrypted); err != nil {
+		t.Errorf("Decrypt() of previous secret error = %v", err)
+	}
+
+	conf.AppSecret.Previous = map[string]string{"2": "clash"}
+	if _, err := FromConfig(conf); err == nil {
+		t.Error("FromConfig() expected error for previous secret with current version")
+	}
+}
@@ -24,6 +24,8 @@ var (
 type FileServerInterface interface {
 	Delete(path string) error
 	Write(path string, data []byte) (fullpath string, n int, err error)
+	Read(path string) ([]byte, error)
+	Move(from, to string) error
 	BaseDirectory() string
 }
 
@@ -71,6 +73,28 @@ func (f *FileServer) Write(path string, data []byte) (fullpath string, n int, er
 	return fullpath, n, nil
 }
 
+// Read returns the contents of the file at path.
+func (f *FileServer) Read(path string) ([]byte, error) {
+	if f == nil {
+		return nil, ErrNotExist
+	}
+
+	// Clean path
+	full, err := cleanPath(f.baseDirectory, path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(full)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	return data, nil
+}
+
 func (f *FileServer) Delete(path string) error {
 	if f == nil {
 		return nil
@@ -114,23 +138,52 @@ func (f *FileServer) Delete(path string) error {
 	}
 
 	// Prune empty directories
-	dir := filepath.Dir(full)
-	for dir != base && dir != "." && dir != string(filepath.Separator) {
-		empty, err := isEmptyDirectory(dir)
-		if err != nil {
-			return fmt.Errorf("checking empty directory: %w", err)
-		}
-		if !empty {
-			break
-		}
+	return pruneEmptyDirectories(base, filepath.Dir(full))
+}
 
-		if err := os.Remove(dir); err != nil {
-			return fmt.Errorf("removing empty directory: %w", err)
-		}
-		dir = filepath.Dir(dir)
+// Move renames the file at from to to, creating parent directories of
+// the destination and pruning emptied directories of the source. An
+// existing file at the destination is not overwritten.
+func (f *FileServer) Move(from, to string) error {
+	if f == nil {
+		return nil
 	}
 
-	return nil
+	// Clean paths
+	src, err := cleanPath(f.baseDirectory, from)
+	if err != nil {
+		return err
+	}
+	dst, err := cleanPath(f.baseDirectory, to)
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return nil
+	}
+
+	// Check source and destination
+	if _, err := os.Stat(src); errors.Is(err, os.ErrNotExist) {
+		return ErrNotExist
+	} else if err != nil {
+		return fmt.Errorf("checking source: %w", err)
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return errors.Join(fmt.Errorf("destination %q already exists", to), ErrInvalidPath)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("checking destination: %w", err)
+	}
+
+	// Move file
+	if err := os.MkdirAll(filepath.Dir(dst), directoryPerms); err != nil {
+		return fmt.Errorf("creating parent directories: %w", err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("renaming file: %w", err)
+	}
+
+	// Prune empty directories
+	return pruneEmptyDirectories(filepath.Clean(f.baseDirectory), filepath.Dir(src))
 }
 
 func (f *FileServer) BaseDirectory() string {
@@ -162,6 +215,27 @@ func cleanPath(baseDir, path string) (string, error) {
 	return fullAbs, nil
 }
 
+// pruneEmptyDirectories removes dir and its parents while they are
+// empty, stopping at base.
+func pruneEmptyDirectories(base, dir string) error {
+	for dir != base && dir != "." && dir != string(filepath.Separator) {
+		empty, err := isEmptyDirectory(dir)
+		if err != nil {
+			return fmt.Errorf("checking empty directory: %w", err)
+		}
+		if !empty {
+			break
+		}
+
+		if err := os.Remove(dir); err != nil {
+			return fmt.Errorf("removing empty directory: %w", err)
+		}
+		dir = filepath.Dir(dir)
+	}
+
+	return nil
+}
+
 func topLevelDirectory(path string) string {
 	path = strings.TrimPrefix(filepath.Clean(path), string(filepath.Separator))
 	tld, _, _ := strings.Cut(path, string(filepath.Separator))
@@ -1,8 +1,11 @@
 package fileserver
 
 import (
+	"bytes"
 	"errors"
 	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -436,3 +439,176 @@ func TestFileServer_Write_CreatesNestedDirectories(t *testing.T) {
 		t.Fatalf("expected directory %q to exist, got error: %v", nestedDir, err)
 	}
 }
+
+func TestFileServerMove_SuccessAndPruneEmptyDirs(t *testing.T) {
+	fs, base := newTestFileServer(t)
+
+	from := filepath.Join("covers", "abc.png")
+	to := filepath.Join("covers", "ab", "cd", "abc.png")
+	if err := os.MkdirAll(filepath.Join(base, "covers"), 0o755); err != nil {
+		t.Fatalf("failed to create dirs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(base, from), []byte("data"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if err := fs.Move(from, to); err != nil {
+		t.Fatalf("Move() returned error: %v", err)
+	}
+
+	// Source must be gone
+	if _, err := os.Stat(filepath.Join(base, from)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected source to be removed, got err=%v", err)
+	}
+
+	// Destination must have the original contents
+	data, err := os.ReadFile(filepath.Join(base, to))
+	if err != nil {
+		t.Fatalf("failed to read destination: %v", err)
+	}
+	if string(data) != "data" {
+		t.Fatalf("destination content = %q, want %q", data, "data")
+	}
+
+	// Moving back should prune the emptied shard directories
+	if err := fs.Move(to, from); err != nil {
+		t.Fatalf("Move() back returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "covers", "ab")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected shard directory to be removed, got err=%v", err)
+	}
+}
+
+func TestFileServerMove_SourceDoesNotExist(t *testing.T) {
+	fs, _ := newTestFileServer(t)
+
+	err := fs.Move(filepath.Join("covers", "missing.png"), filepath.Join("covers", "ab", "missing.png"))
+	if !errors.Is(err, ErrNotExist) {
+		t.Fatalf("expected ErrNotExist for missing file, got %v", err)
+	}
+}
+
+func TestFileServerMove_DoesNotOverwrite(t *testing.T) {
+	fs, base := newTestFileServer(t)
+
+	for _, name := range []string{"a.png", "b.png"} {
+		if err := os.WriteFile(filepath.Join(base, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	if err := fs.Move("a.png", "b.png"); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("expected ErrInvalidPath, got %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(base, "b.png"))
+	if err != nil {
+		t.Fatalf("failed to read destination: %v", err)
+	}
+	if string(data) != "b.png" {
+		t.Fatalf("destination was overwritten: %q", data)
+	}
+}
+
+func TestFileServerRead(t *testing.T) {
+	fs, _ := newTestFileServer(t)
+
+	if _, _, err := fs.Write(filepath.Join("covers", "a.png"), []byte("image")); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	data, err := fs.Read(filepath.Join("covers", "a.png"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "image" {
+		t.Errorf("expected %q, got %q", "image", data)
+	}
+
+	if _, err := fs.Read(filepath.Join("covers", "missing.png")); !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected ErrNotExist for missing file, got %v", err)
+	}
+	if _, err := fs.Read(filepath.Join("..", "a.png")); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("expected ErrInvalidPath for escaping path, got %v", err)
+	}
+}
+
+func TestHandler_ServesFilesButNotDirectories(t *testing.T) {
+	base := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(base, "recipes"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "recipes", "cover.png"), []byte("image"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	handler := Handler(base, nil)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{path: "/recipes/cover.png", wantStatus: http.StatusOK, wantBody: "image"},
+		{path: "/recipes/", wantStatus: http.StatusNotFound},
+		{path: "/recipes", wantStatus: http.StatusNotFound},
+		{path: "/", wantStatus: http.StatusNotFound},
+		{path: "/recipes/missing.png", wantStatus: http.StatusNotFound},
+		{path: "/../etc/passwd", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.wantStatus {
+			t.Errorf("GET %s: status = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+		}
+		if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+			t.Errorf("GET %s: body = %q, want %q", tt.path, rec.Body.String(), tt.wantBody)
+		}
+		if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+			t.Errorf("GET %s: X-Content-Type-Options = %q, want nosniff", tt.path, got)
+		}
+	}
+}
+
+type prefixDecrypter struct{}
+
+func (prefixDecrypter) Decrypt(data []byte) ([]byte, error) {
+	plaintext, ok := bytes.CutPrefix(data, []byte("sealed:"))
+	if !ok {
+		return nil, errors.New("not sealed")
+	}
+	return plaintext, nil
+}
+
+func TestHandler_Decrypts(t *testing.T) {
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "cover.png"), []byte("sealed:image"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "broken.png"), []byte("image"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	handler := Handler(base, prefixDecrypter{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cover.png", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "image" {
+		t.Errorf("GET /cover.png: status = %d, body = %q; want 200 image", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Length"); got != "5" {
+		t.Errorf("GET /cover.png: Content-Length = %q, want 5", got)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/cover.png", nil)
+	req.Header.Set("Range", "bytes=1-2")
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "ma" {
+		t.Errorf("GET /cover.png range: status = %d, body = %q; want 206 ma", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/broken.png", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("GET /broken.png: status = %d, want 500", rec.Code)
+	}
+}
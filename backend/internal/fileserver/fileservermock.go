@@ -67,6 +67,35 @@ func (mr *MockFileServerInterfaceMockRecorder) Delete(path any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFileServerInterface)(nil).Delete), path)
 }
 
+// Move mocks base method.
+func (m *MockFileServerInterface) Move(from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockFileServerInterfaceMockRecorder) Move(from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockFileServerInterface)(nil).Move), from, to)
+}
+
+// Read mocks base method.
+func (m *MockFileServerInterface) Read(path string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", path)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockFileServerInterfaceMockRecorder) Read(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockFileServerInterface)(nil).Read), path)
+}
+
 // Write mocks base method.
 func (m *MockFileServerInterface) Write(path string, data []byte) (string, int, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,86 @@
+package fileserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+)
+
+// Decrypter decrypts files encrypted at rest, returning files that are
+// not encrypted unchanged.
+type Decrypter interface {
+	Decrypt(data []byte) ([]byte, error)
+}
+
+// Handler serves the files under baseDirectory. Directories are reported
+// as missing so their contents cannot be listed. Files are decrypted
+// before they are served if decrypter is not nil.
+func Handler(baseDirectory string, decrypter Decrypter) http.Handler {
+	fileServer := http.FileServer(fileOnlyFS{fs: http.Dir(baseDirectory), decrypter: decrypter})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+type fileOnlyFS struct {
+	fs        http.FileSystem
+	decrypter Decrypter
+}
+
+func (f fileOnlyFS) Open(name string) (http.File, error) {
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+	if info.IsDir() {
+		return nil, errors.Join(fs.ErrNotExist, file.Close())
+	}
+	if f.decrypter == nil {
+		return file, nil
+	}
+
+	// Decrypt file
+	data, err := io.ReadAll(file)
+	if err := errors.Join(err, file.Close()); err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	data, err = f.decrypter.Decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting file: %w", err)
+	}
+	return decryptedFile{
+		Reader: bytes.NewReader(data),
+		info:   decryptedInfo{FileInfo: info, size: int64(len(data))},
+	}, nil
+}
+
+// decryptedFile is a decrypted file held in memory.
+type decryptedFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func (decryptedFile) Close() error { return nil }
+
+func (decryptedFile) Readdir(int) ([]fs.FileInfo, error) {
+	return nil, fs.ErrInvalid
+}
+
+func (d decryptedFile) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+// decryptedInfo reports the size of the decrypted file.
+type decryptedInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (d decryptedInfo) Size() int64 { return d.size }
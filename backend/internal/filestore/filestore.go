@@ -5,7 +5,6 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/matt-dz/wecook/internal/fileserver"
@@ -15,6 +14,7 @@ const (
 	ingredientsDir = "ingredients"
 	stepsDir       = "steps"
 	coverDir       = "covers"
+	brandingDir    = "branding"
 )
 
 const keyIDBytes = 22 // allows for 10^18 ids before likelihood of collision
@@ -27,15 +27,36 @@ type FileStoreInterface interface {
 	WriteRecipeCoverImage(suffix string, data []byte) (key string, n int, err error)
 	WriteIngredientImage(suffix string, data []byte) (key string, n int, err error)
 	WriteStepImage(suffix string, data []byte) (key string, n int, err error)
+	WriteBrandingImage(suffix string, data []byte) (key string, n int, err error)
 
+	ReadKey(key string) ([]byte, error)
 	DeleteKey(key string) error
 
 	FileURL(key string) string
 }
 
+// Encrypter encrypts files before they are written.
+type Encrypter interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+}
+
+// Decrypter decrypts files after they are read, returning files that are
+// not encrypted unchanged.
+type Decrypter interface {
+	Decrypt(data []byte) ([]byte, error)
+}
+
+// Cipher encrypts and decrypts stored files.
+type Cipher interface {
+	Encrypter
+	Decrypter
+}
+
 type FileStore struct {
 	keyPrefix string
 	host      string
+	template  PathTemplate
+	cipher    Cipher
 	fs        fileserver.FileServerInterface
 }
 
@@ -49,6 +70,20 @@ func New(baseDirectory, keyPrefix, host string) FileStore {
 	}
 }
 
+// WithPathTemplate returns a copy of the store that writes new files
+// using the given layout. Existing keys are unaffected.
+func (f FileStore) WithPathTemplate(template PathTemplate) FileStore {
+	f.template = template
+	return f
+}
+
+// WithEncryption returns a copy of the store that encrypts new files
+// and decrypts files it reads. Existing files are unaffected.
+func (f FileStore) WithEncryption(cipher Cipher) FileStore {
+	f.cipher = cipher
+	return f
+}
+
 func (f FileStore) WriteRecipeCoverImage(suffix string, data []byte) (
 	key string, n int, err error,
 ) {
@@ -57,7 +92,13 @@ func (f FileStore) WriteRecipeCoverImage(suffix string, data []byte) (
 	if err != nil {
 		return key, 0, fmt.Errorf("generating key id: %w", err)
 	}
-	key = coverImageKey(id, suffix)
+	key = f.template.Key(KindCover, id, suffix)
+
+	// Encrypt image
+	data, err = f.encrypt(data)
+	if err != nil {
+		return "", 0, err
+	}
 
 	// write image
 	_, n, err = f.fs.Write(extractKeyPrefix(key, KeyPrefix), data)
@@ -75,7 +116,13 @@ func (f FileStore) WriteIngredientImage(suffix string, data []byte) (
 	if err != nil {
 		return key, 0, fmt.Errorf("generating key id: %w", err)
 	}
-	key = ingredientsImageKey(id, suffix)
+	key = f.template.Key(KindIngredient, id, suffix)
+
+	// Encrypt image
+	data, err = f.encrypt(data)
+	if err != nil {
+		return "", 0, err
+	}
 
 	// write image
 	_, n, err = f.fs.Write(extractKeyPrefix(key, KeyPrefix), data)
@@ -92,7 +139,13 @@ func (f FileStore) WriteStepImage(suffix string, data []byte) (key string, n int
 	if err != nil {
 		return key, 0, fmt.Errorf("generating key id: %w", err)
 	}
-	key = ingredientsStepKey(id, suffix)
+	key = f.template.Key(KindStep, id, suffix)
+
+	// Encrypt image
+	data, err = f.encrypt(data)
+	if err != nil {
+		return "", 0, err
+	}
 
 	// write key
 	_, n, err = f.fs.Write(extractKeyPrefix(key, KeyPrefix), data)
@@ -103,24 +156,88 @@ func (f FileStore) WriteStepImage(suffix string, data []byte) (key string, n int
 	return key, n, err
 }
 
+// WriteBrandingImage writes an image of the instance branding, such as
+// its logo.
+func (f FileStore) WriteBrandingImage(suffix string, data []byte) (key string, n int, err error) {
+	// Generate key
+	id, err := generateKeyID()
+	if err != nil {
+		return key, 0, fmt.Errorf("generating key id: %w", err)
+	}
+	key = f.template.Key(KindBranding, id, suffix)
+
+	// Encrypt image
+	data, err = f.encrypt(data)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// write image
+	_, n, err = f.fs.Write(extractKeyPrefix(key, KeyPrefix), data)
+	if err != nil {
+		return "", n, err
+	}
+
+	return key, n, err
+}
+
 func (f FileStore) FileURL(key string) string {
 	return f.host + "/" + strings.TrimLeft(key, "/")
 }
 
+// ReadKey returns the contents of the file stored at key.
+func (f FileStore) ReadKey(key string) ([]byte, error) {
+	data, err := f.fs.Read(extractKeyPrefix(key, f.keyPrefix))
+	if err != nil {
+		return nil, err
+	}
+	if f.cipher == nil {
+		return data, nil
+	}
+	decrypted, err := f.cipher.Decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting file: %w", err)
+	}
+	return decrypted, nil
+}
+
 func (f FileStore) DeleteKey(key string) error {
 	return f.fs.Delete(extractKeyPrefix(key, f.keyPrefix))
 }
 
+// RelocatedKey returns where an existing key of the given kind belongs
+// under the store's current path template.
+func (f FileStore) RelocatedKey(kind, key string) string {
+	id, ext := splitKeyName(key)
+	return f.template.Key(kind, id, ext)
+}
+
+// MoveKey moves the file stored at one key to another.
+func (f FileStore) MoveKey(from, to string) error {
+	return f.fs.Move(extractKeyPrefix(from, f.keyPrefix), extractKeyPrefix(to, f.keyPrefix))
+}
+
+func (f FileStore) encrypt(data []byte) ([]byte, error) {
+	if f.cipher == nil {
+		return data, nil
+	}
+	encrypted, err := f.cipher.Encrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting file: %w", err)
+	}
+	return encrypted, nil
+}
+
 func coverImageKey(id, suffix string) string {
-	return filepath.Join(KeyPrefix, coverDir, fmt.Sprintf("%s%s", id, suffix))
+	return PathTemplate{}.Key(KindCover, id, suffix)
 }
 
 func ingredientsImageKey(id, suffix string) string {
-	return filepath.Join(KeyPrefix, ingredientsDir, fmt.Sprintf("%s%s", id, suffix))
+	return PathTemplate{}.Key(KindIngredient, id, suffix)
 }
 
 func ingredientsStepKey(id, suffix string) string {
-	return filepath.Join(KeyPrefix, stepsDir, fmt.Sprintf("%s%s", id, suffix))
+	return PathTemplate{}.Key(KindStep, id, suffix)
 }
 
 // extractKeyPrefix removes a leading prefix from a slash-delimited key and
@@ -1,6 +1,7 @@
 package filestore
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"path/filepath"
@@ -151,6 +152,84 @@ func TestWriteStepImage(t *testing.T) {
 	}
 }
 
+func TestWriteBrandingImage(t *testing.T) {
+	store, baseDir := newTestFileStore(t)
+	data := []byte("test logo")
+	suffix := ".png"
+
+	key, n, err := store.WriteBrandingImage(suffix, data)
+	if err != nil {
+		t.Fatalf("WriteBrandingImage() error = %v", err)
+	}
+
+	if n != len(data) {
+		t.Errorf("WriteBrandingImage() n = %d, want %d", n, len(data))
+	}
+
+	// Verify key format: /files/branding/<random-id>.png
+	expectedPrefix := filepath.Join(KeyPrefix, brandingDir)
+	if !strings.HasPrefix(key, expectedPrefix) {
+		t.Errorf("WriteBrandingImage() key = %q, should start with %q", key, expectedPrefix)
+	}
+
+	content, err := os.ReadFile(filepath.Join(baseDir, extractKeyPrefix(key, store.keyPrefix)))
+	if err != nil {
+		t.Fatalf("failed to read written file: %v", err)
+	}
+	if string(content) != string(data) {
+		t.Errorf("file content = %q, want %q", string(content), string(data))
+	}
+}
+
+type prefixCipher struct {
+	err error
+}
+
+func (p prefixCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return append([]byte("sealed:"), plaintext...), nil
+}
+
+func (p prefixCipher) Decrypt(data []byte) ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return bytes.TrimPrefix(data, []byte("sealed:")), nil
+}
+
+func TestWriteStepImage_Encrypted(t *testing.T) {
+	store, baseDir := newTestFileStore(t)
+	store = store.WithEncryption(prefixCipher{})
+
+	key, _, err := store.WriteStepImage(".png", []byte("step image"))
+	if err != nil {
+		t.Fatalf("WriteStepImage() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(baseDir, extractKeyPrefix(key, store.keyPrefix)))
+	if err != nil {
+		t.Fatalf("failed to read written file: %v", err)
+	}
+	if string(content) != "sealed:step image" {
+		t.Errorf("file content = %q, want encrypted content", string(content))
+	}
+
+	read, err := store.ReadKey(key)
+	if err != nil {
+		t.Fatalf("ReadKey() error = %v", err)
+	}
+	if string(read) != "step image" {
+		t.Errorf("ReadKey() = %q, want decrypted content", string(read))
+	}
+
+	store = store.WithEncryption(prefixCipher{err: errors.New("no key")})
+	if _, _, err := store.WriteStepImage(".png", []byte("step image")); err == nil {
+		t.Error("WriteStepImage() expected encryption error")
+	}
+}
+
 func TestFileURL(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,101 @@
+package filestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Kinds of stored images. Each kind is also the directory of the
+// default layout.
+const (
+	KindCover      = coverDir
+	KindIngredient = ingredientsDir
+	KindStep       = stepsDir
+	KindBranding   = brandingDir
+)
+
+// DefaultPathTemplate is the original {kind}/{id}{ext} layout.
+const DefaultPathTemplate = "{kind}/{id}{ext}"
+
+const shardLength = 2
+
+var (
+	ErrInvalidPathTemplate = errors.New("invalid path template")
+
+	placeholderPattern = regexp.MustCompile(`\{[^}]*\}`)
+	placeholders       = []string{"{kind}", "{id}", "{ext}", "{shard}", "{shard2}"}
+)
+
+// PathTemplate describes where files are written relative to the key prefix.
+//
+// Supported placeholders:
+//   - {kind}: the kind of image (covers, ingredients, steps, or branding).
+//   - {id}: the random file ID.
+//   - {ext}: the file extension, including the leading dot.
+//   - {shard}, {shard2}: the first and second pairs of hex digits of the
+//     SHA-256 hash of the ID, for spreading files across directories.
+//
+// For example, "{kind}/{shard}/{shard2}/{id}{ext}".
+type PathTemplate struct {
+	template string
+}
+
+// ParsePathTemplate validates a path template. The template must be
+// relative and contain {id} followed by {ext} in its final segment so
+// that files can be located again from their keys.
+func ParsePathTemplate(template string) (PathTemplate, error) {
+	for _, placeholder := range placeholderPattern.FindAllString(template, -1) {
+		if !slices.Contains(placeholders, placeholder) {
+			return PathTemplate{}, fmt.Errorf("%w: unknown placeholder %s", ErrInvalidPathTemplate, placeholder)
+		}
+	}
+	if path.IsAbs(template) {
+		return PathTemplate{}, fmt.Errorf("%w: template must be relative", ErrInvalidPathTemplate)
+	}
+	for _, segment := range strings.Split(template, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return PathTemplate{}, fmt.Errorf("%w: invalid segment %q", ErrInvalidPathTemplate, segment)
+		}
+	}
+	if path.Base(template) != "{id}{ext}" {
+		return PathTemplate{}, fmt.Errorf("%w: final segment must be {id}{ext}", ErrInvalidPathTemplate)
+	}
+
+	return PathTemplate{template: template}, nil
+}
+
+// String returns the raw template.
+func (t PathTemplate) String() string {
+	if t.template == "" {
+		return DefaultPathTemplate
+	}
+	return t.template
+}
+
+// Key returns the key of a file of the given kind, ID, and extension.
+func (t PathTemplate) Key(kind, id, ext string) string {
+	sum := sha256.Sum256([]byte(id))
+	hash := hex.EncodeToString(sum[:])
+
+	replacer := strings.NewReplacer(
+		"{kind}", kind,
+		"{id}", id,
+		"{ext}", ext,
+		"{shard}", hash[:shardLength],
+		"{shard2}", hash[shardLength:2*shardLength],
+	)
+	return path.Join(KeyPrefix, replacer.Replace(t.String()))
+}
+
+// splitKeyName returns the ID and extension of a key created by any template.
+func splitKeyName(key string) (id, ext string) {
+	name := path.Base(key)
+	ext = path.Ext(name)
+	return strings.TrimSuffix(name, ext), ext
+}
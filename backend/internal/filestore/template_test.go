@@ -0,0 +1,104 @@
+package filestore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePathTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "default", template: DefaultPathTemplate},
+		{name: "sharded", template: "{kind}/{shard}/{shard2}/{id}{ext}"},
+		{name: "flat", template: "{id}{ext}"},
+		{name: "static directory", template: "images/{kind}/{id}{ext}"},
+		{name: "unknown placeholder", template: "{kind}/{user}/{id}{ext}", wantErr: true},
+		{name: "absolute", template: "/{kind}/{id}{ext}", wantErr: true},
+		{name: "parent segment", template: "../{kind}/{id}{ext}", wantErr: true},
+		{name: "empty segment", template: "{kind}//{id}{ext}", wantErr: true},
+		{name: "missing extension", template: "{kind}/{id}", wantErr: true},
+		{name: "id not in final segment", template: "{id}/{kind}{ext}", wantErr: true},
+		{name: "empty", template: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePathTemplate(tt.template)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPathTemplate) {
+					t.Fatalf("ParsePathTemplate(%q) error = %v, want ErrInvalidPathTemplate", tt.template, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePathTemplate(%q) error = %v", tt.template, err)
+			}
+			if got.String() != tt.template {
+				t.Errorf("String() = %q, want %q", got.String(), tt.template)
+			}
+		})
+	}
+}
+
+func TestPathTemplateKey(t *testing.T) {
+	// The zero value must keep producing the original layout.
+	if got, want := (PathTemplate{}).Key(KindCover, "abc", ".png"), "/files/covers/abc.png"; got != want {
+		t.Errorf("Key() = %q, want %q", got, want)
+	}
+
+	sharded, err := ParsePathTemplate("{kind}/{shard}/{shard2}/{id}{ext}")
+	if err != nil {
+		t.Fatalf("ParsePathTemplate() error = %v", err)
+	}
+	key := sharded.Key(KindStep, "abc", ".png")
+	// sha256("abc") = ba7816bf...
+	if want := "/files/steps/ba/78/abc.png"; key != want {
+		t.Errorf("Key() = %q, want %q", key, want)
+	}
+	if again := sharded.Key(KindStep, "abc", ".png"); again != key {
+		t.Errorf("Key() is not deterministic: %q != %q", again, key)
+	}
+}
+
+func TestRelocatedKeyAndMoveKey(t *testing.T) {
+	store, baseDir := newTestFileStore(t)
+
+	key, _, err := store.WriteRecipeCoverImage(".png", []byte("cover"))
+	if err != nil {
+		t.Fatalf("WriteRecipeCoverImage() error = %v", err)
+	}
+
+	sharded, err := ParsePathTemplate("{kind}/{shard}/{id}{ext}")
+	if err != nil {
+		t.Fatalf("ParsePathTemplate() error = %v", err)
+	}
+	store = store.WithPathTemplate(sharded)
+
+	relocated := store.RelocatedKey(KindCover, key)
+	if relocated == key {
+		t.Fatalf("RelocatedKey() = %q, expected a new location", relocated)
+	}
+	if filepath.Base(relocated) != filepath.Base(key) {
+		t.Errorf("RelocatedKey() changed file name: %q -> %q", key, relocated)
+	}
+	if store.RelocatedKey(KindCover, relocated) != relocated {
+		t.Errorf("RelocatedKey() is not idempotent for %q", relocated)
+	}
+
+	if err := store.MoveKey(key, relocated); err != nil {
+		t.Fatalf("MoveKey() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(baseDir, strings.TrimPrefix(relocated, KeyPrefix)))
+	if err != nil {
+		t.Fatalf("reading relocated file: %v", err)
+	}
+	if string(data) != "cover" {
+		t.Errorf("relocated content = %q, want %q", data, "cover")
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/gabriel-vasile/mimetype"
 )
@@ -40,8 +43,24 @@ var mimeTypeSuffix = map[string]string{
 	"image/tiff":    ".tiff",
 }
 
+// mimeTypeExtensions lists the filename extensions a client may use for
+// each MIME type. The stored suffix always comes from mimeTypeSuffix.
+var mimeTypeExtensions = map[string][]string{
+	"image/jpeg":    {".jpg", ".jpeg", ".jpe", ".jfif", ".pjpeg", ".pjp"},
+	"image/png":     {".png"},
+	"image/svg+xml": {".svg"},
+	"image/webp":    {".webp"},
+	"image/gif":     {".gif"},
+	"image/avif":    {".avif"},
+	"image/heic":    {".heic", ".heif"},
+	"image/heif":    {".heif", ".heic"},
+	"image/bmp":     {".bmp", ".dib"},
+	"image/tiff":    {".tif", ".tiff"},
+}
+
 var (
 	ErrUnsupportedMimeType = errors.New("unsupported mime type")
+	ErrExtensionMismatch   = errors.New("file extension does not match content")
 	ErrNoImageUploaded     = errors.New("image not uploaded")
 )
 
@@ -52,7 +71,11 @@ type File struct {
 	MimeType string
 }
 
-func ReadImage(file io.ReadCloser) (*File, error) {
+// ReadImage reads an uploaded image and detects its type from its
+// content. The client-provided filename is only used to reject files
+// whose extension disagrees with their content; the returned Suffix is
+// always derived from the detected type.
+func ReadImage(file io.ReadCloser, filename string) (*File, error) {
 	data, err := io.ReadAll(file)
 	defer func() { _ = file.Close() }()
 	if err != nil {
@@ -66,6 +89,9 @@ func ReadImage(file io.ReadCloser) (*File, error) {
 	if !allowedImageTypes[contentType] {
 		return nil, fmt.Errorf("mime type %q: %w", contentType, ErrUnsupportedMimeType)
 	}
+	if !extensionMatches(filename, contentType) {
+		return nil, fmt.Errorf("extension of %q for mime type %q: %w", filename, contentType, ErrExtensionMismatch)
+	}
 
 	return &File{
 		Size:     int64(len(data)),
@@ -74,3 +100,13 @@ func ReadImage(file io.ReadCloser) (*File, error) {
 		Data:     data,
 	}, nil
 }
+
+// extensionMatches reports whether filename has no extension or one
+// that is expected for contentType.
+func extensionMatches(filename, contentType string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" {
+		return true
+	}
+	return slices.Contains(mimeTypeExtensions[contentType], ext)
+}
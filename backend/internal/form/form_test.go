@@ -0,0 +1,85 @@
+package form
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+var (
+	pngData = []byte{
+		0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, // PNG signature
+		0x00, 0x00, 0x00, 0x0D, 0x49, 0x48, 0x44, 0x52, // IHDR chunk
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+		0x08, 0x02, 0x00, 0x00, 0x00, 0x90, 0x77, 0x53,
+		0xDE,
+	}
+	jpegData = []byte{
+		0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 0x4A, 0x46,
+		0x49, 0x46, 0x00, 0x01, 0x01, 0x00, 0x00, 0x01,
+		0x00, 0x01, 0x00, 0x00, 0xFF, 0xD9,
+	}
+	svgData = []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="1" height="1"></svg>`)
+	// gifScriptData starts with GIF magic bytes but carries a script, as
+	// a GIF/JavaScript polyglot would.
+	gifScriptData = append([]byte("GIF89a"), []byte("=1;alert(document.cookie)//")...)
+)
+
+func TestReadImage(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		filename     string
+		wantMimeType string
+		wantSuffix   string
+		wantErr      error
+	}{
+		{name: "png", data: pngData, filename: "photo.png", wantMimeType: "image/png", wantSuffix: ".png"},
+		{name: "jpeg with .jpeg", data: jpegData, filename: "photo.jpeg", wantMimeType: "image/jpeg", wantSuffix: ".jpg"},
+		{name: "uppercase extension", data: jpegData, filename: "IMG_0001.JPG", wantMimeType: "image/jpeg", wantSuffix: ".jpg"},
+		{name: "no extension", data: pngData, filename: "blob", wantMimeType: "image/png", wantSuffix: ".png"},
+		{name: "empty filename", data: pngData, filename: "", wantMimeType: "image/png", wantSuffix: ".png"},
+		{name: "svg", data: svgData, filename: "icon.svg", wantMimeType: "image/svg+xml", wantSuffix: ".svg"},
+		{name: "jpeg named png", data: jpegData, filename: "photo.png", wantErr: ErrExtensionMismatch},
+		{name: "png named svg", data: pngData, filename: "icon.svg", wantErr: ErrExtensionMismatch},
+		{name: "svg named png", data: svgData, filename: "photo.png", wantErr: ErrExtensionMismatch},
+		{name: "gif polyglot named js", data: gifScriptData, filename: "payload.js", wantErr: ErrExtensionMismatch},
+		{name: "png with double extension", data: pngData, filename: "photo.png.html", wantErr: ErrExtensionMismatch},
+		{name: "html named png", data: []byte("<html><script>alert(1)</script></html>"), filename: "photo.png",
+			wantErr: ErrUnsupportedMimeType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := ReadImage(io.NopCloser(bytes.NewReader(tt.data)), tt.filename)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ReadImage() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadImage() error = %v", err)
+			}
+			if file.MimeType != tt.wantMimeType {
+				t.Errorf("MimeType = %q, want %q", file.MimeType, tt.wantMimeType)
+			}
+			if file.Suffix != tt.wantSuffix {
+				t.Errorf("Suffix = %q, want %q", file.Suffix, tt.wantSuffix)
+			}
+			if file.Size != int64(len(tt.data)) {
+				t.Errorf("Size = %d, want %d", file.Size, len(tt.data))
+			}
+		})
+	}
+}
+
+func TestMimeTypeExtensions(t *testing.T) {
+	// Every allowed type must accept its own stored suffix.
+	for mimeType := range allowedImageTypes {
+		if !extensionMatches("image"+mimeTypeSuffix[mimeType], mimeType) {
+			t.Errorf("%s does not accept its own suffix %q", mimeType, mimeTypeSuffix[mimeType])
+		}
+	}
+}
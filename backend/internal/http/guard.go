@@ -0,0 +1,122 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// ErrForbiddenAddress is returned when a guarded client dials an address
+// its policy does not allow.
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// MaxRedirects is the number of redirects a guarded client follows.
+const MaxRedirects = 3
+
+// AddressPolicy reports whether a client may connect to addr.
+type AddressPolicy func(addr netip.Addr) bool
+
+// nonPublicPrefixes are the globally routable looking ranges that are not
+// reachable on the internet.
+var nonPublicPrefixes = []netip.Prefix{
+	thisNetwork,
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// thisNetwork is the range that Linux dials as the host itself.
+var thisNetwork = netip.MustParsePrefix("0.0.0.0/8")
+
+// PublicAddress allows publicly routable addresses, refusing loopback,
+// private, link-local, and unique local addresses among others.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// NetworkAddress allows the addresses of other hosts, public or private,
+// refusing loopback, link-local, unspecified, and multicast addresses.
+func NetworkAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !thisNetwork.Contains(addr)
+}
+
+// GuardedTransport returns a transport that only connects to addresses
+// allowed by policy. Addresses are checked as they are dialed, after the
+// host is resolved, so neither redirects nor DNS rebinding reach others.
+// Proxies from the environment are not used, since the address of the
+// proxy would be checked instead of the destination.
+func GuardedTransport(policy AddressPolicy) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !policy(addrPort.Addr()) {
+				return fmt.Errorf("dialing %s: %w", address, ErrForbiddenAddress)
+			}
+			return nil
+		},
+	}
+	return &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// CheckRedirect follows at most MaxRedirects redirects, none of which may
+// change the scheme of the request.
+func CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > MaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", MaxRedirects)
+	}
+	if req.URL.Scheme != via[0].URL.Scheme {
+		return fmt.Errorf("redirected from %s to %s", via[0].URL.Scheme, req.URL.Scheme)
+	}
+	return nil
+}
+
+// Guard makes client connect only to addresses allowed by policy and check
+// the redirects it follows.
+func Guard(client *retryablehttp.Client, policy AddressPolicy) {
+	client.HTTPClient = &http.Client{
+		Timeout:       client.HTTPClient.Timeout,
+		Transport:     GuardedTransport(policy),
+		CheckRedirect: CheckRedirect,
+	}
+}
+
+// NewGuarded returns a client for URLs supplied by users or other servers.
+// It does not retry, and only connects to addresses allowed by policy.
+func NewGuarded(policy AddressPolicy, timeout time.Duration) *retryablehttp.Client {
+	client := retryablehttp.NewClient()
+	client.RetryMax = 0
+	client.Logger = nil
+	Guard(client, policy)
+	client.HTTPClient.Timeout = timeout
+	return client
+}
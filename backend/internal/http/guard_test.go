@@ -0,0 +1,93 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+func TestAddressPolicies(t *testing.T) {
+	tests := []struct {
+		addr        string
+		wantPublic  bool
+		wantNetwork bool
+	}{
+		{addr: "93.184.216.34", wantPublic: true, wantNetwork: true},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", wantPublic: true, wantNetwork: true},
+		{addr: "10.1.2.3", wantNetwork: true},
+		{addr: "172.16.0.1", wantNetwork: true},
+		{addr: "192.168.1.1", wantNetwork: true},
+		{addr: "fd00::1", wantNetwork: true},
+		{addr: "100.64.0.1", wantNetwork: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "169.254.169.254"},
+		{addr: "fe80::1"},
+		{addr: "0.0.0.0"},
+		{addr: "0.1.2.3"},
+		{addr: "224.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			addr := netip.MustParseAddr(tt.addr)
+			if got := PublicAddress(addr); got != tt.wantPublic {
+				t.Errorf("PublicAddress(%s) = %v, want %v", tt.addr, got, tt.wantPublic)
+			}
+			if got := NetworkAddress(addr); got != tt.wantNetwork {
+				t.Errorf("NetworkAddress(%s) = %v, want %v", tt.addr, got, tt.wantNetwork)
+			}
+		})
+	}
+}
+
+func TestNewGuarded(t *testing.T) {
+	var hits int
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer redirect.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		// dials is the number of connections allowed before addresses
+		// are refused.
+		dials int
+	}{
+		{name: "loopback", url: target.URL},
+		{name: "redirect to loopback", url: redirect.URL, dials: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dials := 0
+			client := NewGuarded(func(netip.Addr) bool {
+				dials++
+				return dials <= tt.dials
+			}, time.Second)
+
+			req, err := retryablehttp.NewRequest(http.MethodPost, tt.url, []byte("{}"))
+			if err != nil {
+				t.Fatalf("creating request: %v", err)
+			}
+			if _, err := client.Do(req); !errors.Is(err, ErrForbiddenAddress) {
+				t.Errorf("expected %v, got %v", ErrForbiddenAddress, err)
+			}
+			if dials != tt.dials+1 {
+				t.Errorf("expected %d dials, got %d", tt.dials+1, dials)
+			}
+			if hits != 0 {
+				t.Errorf("expected the target not to be reached, got %d requests", hits)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Package httpsig signs and verifies HTTP requests with the HTTP
+// Signatures draft (draft-cavage-http-signatures) used by ActivityPub
+// servers.
+//
+// Requests are signed with rsa-sha256 over the request target, Host,
+// Date, and Digest headers. The Digest header carries the SHA-256 of the
+// body, so a verified signature also covers the body.
+package httpsig
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// Algorithm is the only signature algorithm supported.
+	Algorithm = "rsa-sha256"
+	// MaxSkew is how far the Date of a signed request may be from the
+	// time it is verified at.
+	MaxSkew = 5 * time.Minute
+	// KeyBits is the size of generated keys.
+	KeyBits = 2048
+
+	requestTarget = "(request-target)"
+)
+
+var (
+	ErrMissingSignature = errors.New("request is not signed")
+	ErrInvalidSignature = errors.New("invalid request signature")
+	// ErrDigestMismatch is returned when the body does not match its
+	// Digest header.
+	ErrDigestMismatch = errors.New("request body does not match its digest")
+	// ErrExpired is returned when the Date of a request is more than
+	// MaxSkew from the time it is verified at.
+	ErrExpired = errors.New("request date is out of range")
+)
+
+// signedHeaders are the headers a request is signed over, in order.
+var signedHeaders = []string{requestTarget, "host", "date", "digest"}
+
+// KeyFunc returns the public key identified by keyID.
+type KeyFunc func(keyID string) (*rsa.PublicKey, error)
+
+// Sign sets the Date and Digest headers of r and signs it with key. body
+// must be the body r sends.
+func Sign(r *http.Request, keyID string, key *rsa.PrivateKey, body []byte, now time.Time) error {
+	r.Header.Set("Date", now.UTC().Format(http.TimeFormat))
+	r.Header.Set("Digest", Digest(body))
+
+	hashed := sha256.Sum256([]byte(signingString(r, signedHeaders)))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return fmt.Errorf("signing request: %w", err)
+	}
+	r.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		keyID, Algorithm, strings.Join(signedHeaders, " "),
+		base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// Verify checks the signature of r and returns the ID of the key it was
+// signed with. body must be the body r was sent with. The signature must
+// cover the request target, Host, Date, and Digest headers.
+func Verify(r *http.Request, body []byte, now time.Time, key KeyFunc) (string, error) {
+	header := r.Header.Get("Signature")
+	if header == "" {
+		return "", ErrMissingSignature
+	}
+	params := parseParams(header)
+	keyID := params["keyId"]
+	if keyID == "" || params["signature"] == "" {
+		return "", fmt.Errorf("%w: missing keyId or signature", ErrInvalidSignature)
+	}
+	if algorithm := params["algorithm"]; algorithm != "" && algorithm != Algorithm && algorithm != "hs2019" {
+		return "", fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, algorithm)
+	}
+	headers := strings.Fields(strings.ToLower(params["headers"]))
+	for _, required := range signedHeaders {
+		if !slices.Contains(headers, required) {
+			return "", fmt.Errorf("%w: %s is not signed", ErrInvalidSignature, required)
+		}
+	}
+
+	date, err := http.ParseTime(r.Header.Get("Date"))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if date.Before(now.Add(-MaxSkew)) || date.After(now.Add(MaxSkew)) {
+		return "", ErrExpired
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Digest")), []byte(Digest(body))) != 1 {
+		return "", ErrDigestMismatch
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	publicKey, err := key(keyID)
+	if err != nil {
+		return "", err
+	}
+	hashed := sha256.Sum256([]byte(signingString(r, headers)))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	return keyID, nil
+}
+
+// Digest returns the Digest header of body.
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// signingString returns the string signed for headers of r.
+func signingString(r *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers))
+	for _, name := range headers {
+		var value string
+		switch name {
+		case requestTarget:
+			value = strings.ToLower(r.Method) + " " + r.URL.RequestURI()
+		case "host":
+			value = r.Host
+			if value == "" {
+				value = r.URL.Host
+			}
+		default:
+			value = strings.Join(r.Header.Values(name), ", ")
+		}
+		lines = append(lines, name+": "+value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseParams parses the comma-separated key="value" pairs of a Signature
+// header.
+func parseParams(header string) map[string]string {
+	params := make(map[string]string)
+	for header != "" {
+		key, rest, ok := strings.Cut(header, "=")
+		if !ok {
+			break
+		}
+		key = strings.TrimSpace(key)
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			// Values are quoted and may contain commas.
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				break
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		params[key] = value
+		header = strings.TrimPrefix(rest, ",")
+	}
+	return params
+}
+
+// GenerateKey returns a new RSA key pair encoded as PEM: a PKCS #8
+// private key and a PKIX public key.
+func GenerateKey() (privateKey, publicKey string, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, KeyBits)
+	if err != nil {
+		return "", "", fmt.Errorf("generating key: %w", err)
+	}
+	private, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", "", fmt.Errorf("encoding private key: %w", err)
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", "", fmt.Errorf("encoding public key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: private})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})), nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS #8 RSA private key.
+func ParsePrivateKey(s string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// ParsePublicKey parses a PEM encoded PKIX or PKCS #1 RSA public key.
+func ParsePublicKey(s string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an RSA key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,133 @@
+package httpsig
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, time.April, 6, 9, 0, 0, 0, time.UTC)
+
+const keyID = "https://a.example/federation/users/1#main-key"
+
+func newKeys(t *testing.T) (*rsa.PrivateKey, *rsa.PublicKey) {
+	t.Helper()
+	privatePEM, publicPEM, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	private, err := ParsePrivateKey(privatePEM)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+	public, err := ParsePublicKey(publicPEM)
+	if err != nil {
+		t.Fatalf("ParsePublicKey() error = %v", err)
+	}
+	return private, public
+}
+
+func TestSignVerify(t *testing.T) {
+	private, public := newKeys(t)
+	_, otherPublic := newKeys(t)
+	body := []byte(`{"type":"Follow"}`)
+
+	tests := []struct {
+		name    string
+		tamper  func(r *http.Request) []byte
+		key     *rsa.PublicKey
+		now     time.Time
+		wantErr error
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name:    "other key",
+			key:     otherPublic,
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "body changed",
+			tamper:  func(*http.Request) []byte { return []byte(`{"type":"Undo"}`) },
+			wantErr: ErrDigestMismatch,
+		},
+		{
+			name: "path changed",
+			tamper: func(r *http.Request) []byte {
+				r.URL.Path = "/federation/users/2/inbox"
+				return body
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "too old",
+			now:     now.Add(MaxSkew + time.Second),
+			wantErr: ErrExpired,
+		},
+		{
+			name: "unsigned",
+			tamper: func(r *http.Request) []byte {
+				r.Header.Del("Signature")
+				return body
+			},
+			wantErr: ErrMissingSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "https://b.example/federation/users/1/inbox",
+				bytes.NewReader(body))
+			if err := Sign(r, keyID, private, body, now); err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			received := body
+			if tt.tamper != nil {
+				received = tt.tamper(r)
+			}
+			key := public
+			if tt.key != nil {
+				key = tt.key
+			}
+			at := now
+			if !tt.now.IsZero() {
+				at = tt.now
+			}
+
+			got, err := Verify(r, received, at, func(id string) (*rsa.PublicKey, error) {
+				if id != keyID {
+					t.Errorf("key requested = %q, want %q", id, keyID)
+				}
+				return key, nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != keyID {
+				t.Errorf("Verify() = %q, want %q", got, keyID)
+			}
+		})
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	got := parseParams(`keyId="https://a.example/u#k",algorithm="rsa-sha256",` +
+		`headers="(request-target) host date",signature="a,b=="`)
+	want := map[string]string{
+		"keyId":     "https://a.example/u#k",
+		"algorithm": "rsa-sha256",
+		"headers":   "(request-target) host date",
+		"signature": "a,b==",
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("parseParams()[%q] = %q, want %q", key, got[key], value)
+		}
+	}
+}
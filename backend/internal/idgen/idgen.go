@@ -0,0 +1,31 @@
+// Package idgen provides an abstraction over application generated identifiers.
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const idBytes = 16
+
+// IDGenerator generates unique, opaque identifiers.
+type IDGenerator interface {
+	NewID() string
+}
+
+// Random generates identifiers from a cryptographically secure source.
+type Random struct{}
+
+var _ IDGenerator = Random{}
+
+// New returns an IDGenerator backed by crypto/rand.
+func New() Random {
+	return Random{}
+}
+
+// NewID returns a random 128-bit identifier encoded as hex.
+func (Random) NewID() string {
+	b := make([]byte, idBytes)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,25 @@
+package idgen
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// Sequential generates predictable identifiers of the form prefix1,
+// prefix2, and so on. It is intended for tests.
+type Sequential struct {
+	prefix string
+	next   atomic.Uint64
+}
+
+var _ IDGenerator = (*Sequential)(nil)
+
+// NewSequential returns an IDGenerator that counts up from 1.
+func NewSequential(prefix string) *Sequential {
+	return &Sequential{prefix: prefix}
+}
+
+// NewID returns the next identifier in the sequence.
+func (s *Sequential) NewID() string {
+	return s.prefix + strconv.FormatUint(s.next.Add(1), 10)
+}
@@ -0,0 +1,117 @@
+// Package ingredient formats structured ingredients as human-friendly
+// lines, such as "1½ cups flour, sifted", so every rendering of a recipe
+// reads the same.
+package ingredient
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/matt-dz/wecook/internal/units"
+)
+
+// Line is a structured ingredient.
+type Line struct {
+	// Quantity is nil for ingredients without one, such as "salt".
+	Quantity *float64
+	// Unit is a unit name or abbreviation, such as "cups" or "g". Units
+	// known to the units package are shown in a canonical form.
+	Unit string
+	Name string
+	// Note follows the name after a comma, such as "sifted".
+	Note string
+}
+
+// fractionTolerance is how close a quantity must be to a vulgar fraction
+// to be shown as one.
+const fractionTolerance = 0.01
+
+var vulgarFractions = []struct {
+	value float64
+	glyph string
+}{
+	{1.0 / 8, "⅛"}, {1.0 / 4, "¼"}, {1.0 / 3, "⅓"}, {3.0 / 8, "⅜"},
+	{1.0 / 2, "½"}, {5.0 / 8, "⅝"}, {2.0 / 3, "⅔"}, {3.0 / 4, "¾"},
+	{7.0 / 8, "⅞"},
+}
+
+// Format renders line in locale. Unknown locales fall back to English.
+func Format(line Line, locale Locale) string {
+	rules := rulesFor(locale)
+
+	parts := make([]string, 0, 3)
+	plural := false
+	if line.Quantity != nil {
+		parts = append(parts, FormatQuantity(*line.Quantity, locale))
+		plural = *line.Quantity > 1
+	}
+	unit := strings.TrimSpace(line.Unit)
+	if unit != "" {
+		parts = append(parts, rules.unit(unit, plural))
+	}
+	name := strings.Join(strings.Fields(line.Name), " ")
+	if name != "" {
+		// Countable ingredients are pluralized when there is no unit, as
+		// in "2 eggs".
+		if unit == "" && plural && rules.pluralizeNames {
+			name = pluralizeLast(name)
+		}
+		parts = append(parts, name)
+	}
+
+	formatted := strings.Join(parts, " ")
+	if note := strings.TrimSpace(line.Note); note != "" {
+		formatted += ", " + note
+	}
+	return formatted
+}
+
+// FormatQuantity renders quantity in locale. English shows common
+// fractions as vulgar fractions, such as "1½"; other locales use decimals
+// with their decimal separator, such as "1,5".
+func FormatQuantity(quantity float64, locale Locale) string {
+	rules := rulesFor(locale)
+	if rules.fractions {
+		whole, fraction := math.Modf(quantity)
+		for _, vulgar := range vulgarFractions {
+			if math.Abs(fraction-vulgar.value) < fractionTolerance {
+				if whole == 0 {
+					return vulgar.glyph
+				}
+				return strconv.FormatFloat(whole, 'f', -1, 64) + vulgar.glyph
+			}
+		}
+	}
+	formatted := strconv.FormatFloat(math.Round(quantity*100)/100, 'f', -1, 64)
+	return strings.Replace(formatted, ".", rules.decimalSeparator, 1)
+}
+
+// SplitUnit splits a leading unit known to the units package from the
+// rest of an ingredient, as in "cups flour". ok is false if item does not
+// start with a known unit.
+func SplitUnit(item string) (unit, rest string, ok bool) {
+	fields := strings.Fields(item)
+	// Try two-word units such as "fl oz" first.
+	for n := min(2, len(fields)-1); n >= 1; n-- {
+		candidate := strings.Join(fields[:n], " ")
+		if _, known := units.LookupUnit(candidate); known {
+			return candidate, strings.Join(fields[n:], " "), true
+		}
+	}
+	return "", strings.Join(fields, " "), false
+}
+
+// ParseItem builds a line from a quantity and the rest of a free-text
+// ingredient, such as "cups flour, sifted". Text after the first comma
+// becomes the note.
+func ParseItem(quantity *float64, item string) Line {
+	unit, rest, _ := SplitUnit(item)
+	name, note, _ := strings.Cut(rest, ",")
+	return Line{
+		Quantity: quantity,
+		Unit:     unit,
+		Name:     strings.TrimSpace(name),
+		Note:     strings.TrimSpace(note),
+	}
+}
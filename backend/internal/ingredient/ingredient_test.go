@@ -0,0 +1,174 @@
+package ingredient
+
+import "testing"
+
+func quantity(q float64) *float64 {
+	return &q
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   Line
+		locale Locale
+		want   string
+	}{
+		{
+			name:   "mixed fraction with note",
+			line:   Line{Quantity: quantity(1.5), Unit: "cups", Name: "flour", Note: "sifted"},
+			locale: English,
+			want:   "1½ cups flour, sifted",
+		},
+		{
+			name:   "singular unit",
+			line:   Line{Quantity: quantity(1), Unit: "Cups", Name: "milk"},
+			locale: English,
+			want:   "1 cup milk",
+		},
+		{
+			name:   "fraction below one",
+			line:   Line{Quantity: quantity(1.0 / 3), Unit: "c", Name: "sugar"},
+			locale: English,
+			want:   "⅓ cup sugar",
+		},
+		{
+			name:   "abbreviated unit",
+			line:   Line{Quantity: quantity(2), Unit: "tablespoons", Name: "butter"},
+			locale: English,
+			want:   "2 tbsp butter",
+		},
+		{
+			name:   "countable name",
+			line:   Line{Quantity: quantity(3), Name: "large egg"},
+			locale: English,
+			want:   "3 large eggs",
+		},
+		{
+			name:   "irregular plural",
+			line:   Line{Quantity: quantity(2), Name: "tomato", Note: "diced"},
+			locale: English,
+			want:   "2 tomatoes, diced",
+		},
+		{
+			name:   "unknown unit",
+			line:   Line{Quantity: quantity(2), Unit: "pinch", Name: "salt"},
+			locale: English,
+			want:   "2 pinches salt",
+		},
+		{
+			name:   "no quantity",
+			line:   Line{Name: "salt", Note: "to taste"},
+			locale: English,
+			want:   "salt, to taste",
+		},
+		{
+			name:   "decimal",
+			line:   Line{Quantity: quantity(0.3), Unit: "kg", Name: "potatoes"},
+			locale: English,
+			want:   "0.3 kg potatoes",
+		},
+		{
+			name:   "french",
+			line:   Line{Quantity: quantity(1.5), Unit: "cups", Name: "farine", Note: "tamisée"},
+			locale: "fr-CA",
+			want:   "1,5 tasses farine, tamisée",
+		},
+		{
+			name:   "german",
+			line:   Line{Quantity: quantity(2), Unit: "tbsp", Name: "Butter"},
+			locale: "de_DE",
+			want:   "2 EL Butter",
+		},
+		{
+			name:   "spanish does not pluralize names",
+			line:   Line{Quantity: quantity(3), Name: "huevo"},
+			locale: Spanish,
+			want:   "3 huevo",
+		},
+		{
+			name:   "unknown locale falls back to english",
+			line:   Line{Quantity: quantity(0.5), Unit: "cup", Name: "rice"},
+			locale: "xx",
+			want:   "½ cup rice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.line, tt.locale); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitUnit(t *testing.T) {
+	tests := []struct {
+		item     string
+		wantUnit string
+		wantRest string
+		wantOK   bool
+	}{
+		{item: "cups flour", wantUnit: "cups", wantRest: "flour", wantOK: true},
+		{item: "fl oz cream", wantUnit: "fl oz", wantRest: "cream", wantOK: true},
+		{item: "eggs", wantRest: "eggs"},
+		{item: "cups", wantRest: "cups"},
+		{item: "large  onion", wantRest: "large onion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.item, func(t *testing.T) {
+			unit, rest, ok := SplitUnit(tt.item)
+			if ok != tt.wantOK || unit != tt.wantUnit || rest != tt.wantRest {
+				t.Errorf("SplitUnit() = (%q, %q, %v), want (%q, %q, %v)",
+					unit, rest, ok, tt.wantUnit, tt.wantRest, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	tests := map[string]string{
+		"egg":    "eggs",
+		"cherry": "cherries",
+		"bay":    "bays",
+		"pinch":  "pinches",
+		"leaf":   "leaves",
+		"Potato": "Potatoes",
+		"peas":   "peas",
+	}
+	for word, want := range tests {
+		if got := pluralize(word); got != want {
+			t.Errorf("pluralize(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestSingular(t *testing.T) {
+	tests := map[string]string{
+		"eggs":     "egg",
+		"cherries": "cherry",
+		"pinches":  "pinch",
+		"leaves":   "leaf",
+		"tomatoes": "tomato",
+		"peas":     "pea",
+		"couscous": "couscous",
+		"hummus":   "hummus",
+		"salt":     "salt",
+	}
+	for word, want := range tests {
+		if got := Singular(word); got != want {
+			t.Errorf("Singular(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestParseItem(t *testing.T) {
+	line := ParseItem(quantity(1.5), "cups flour, sifted")
+	if got := Format(line, English); got != "1½ cups flour, sifted" {
+		t.Errorf("Format(ParseItem()) = %q", got)
+	}
+	if line.Unit != "cups" || line.Name != "flour" || line.Note != "sifted" {
+		t.Errorf("ParseItem() = %+v", line)
+	}
+}
@@ -0,0 +1,115 @@
+package ingredient
+
+import (
+	"strings"
+
+	"github.com/matt-dz/wecook/internal/units"
+)
+
+// Locale is a language tag such as "en" or "fr-CA". Only the language is
+// used.
+type Locale string
+
+const (
+	English Locale = "en"
+	French  Locale = "fr"
+	German  Locale = "de"
+	Spanish Locale = "es"
+)
+
+// Locales lists the languages with their own formatting rules.
+var Locales = []Locale{English, French, German, Spanish}
+
+// Language returns the language of the locale, such as "fr" for "fr-CA".
+func (l Locale) Language() Locale {
+	language, _, _ := strings.Cut(strings.ReplaceAll(string(l), "_", "-"), "-")
+	return Locale(strings.ToLower(strings.TrimSpace(language)))
+}
+
+// unitName is the singular and plural display name of a unit.
+type unitName struct {
+	singular string
+	plural   string
+}
+
+type rules struct {
+	decimalSeparator string
+	// fractions shows common fractions as vulgar fractions.
+	fractions bool
+	// pluralizeNames pluralizes ingredient names without a unit, and
+	// units not in unitNames.
+	pluralizeNames bool
+	// unitNames maps canonical unit names to their display names. Units
+	// not listed are shown by their canonical abbreviation.
+	unitNames map[string]unitName
+}
+
+var localeRules = map[Locale]rules{
+	English: {
+		decimalSeparator: ".",
+		fractions:        true,
+		pluralizeNames:   true,
+		unitNames: map[string]unitName{
+			"cup": {"cup", "cups"},
+			"pt":  {"pint", "pints"},
+			"qt":  {"quart", "quarts"},
+			"gal": {"gallon", "gallons"},
+		},
+	},
+	French: {
+		decimalSeparator: ",",
+		unitNames: map[string]unitName{
+			"cup":  {"tasse", "tasses"},
+			"tbsp": {"c. à s.", "c. à s."},
+			"tsp":  {"c. à c.", "c. à c."},
+		},
+	},
+	German: {
+		decimalSeparator: ",",
+		unitNames: map[string]unitName{
+			"cup":  {"Tasse", "Tassen"},
+			"tbsp": {"EL", "EL"},
+			"tsp":  {"TL", "TL"},
+		},
+	},
+	Spanish: {
+		decimalSeparator: ",",
+		unitNames: map[string]unitName{
+			"cup":  {"taza", "tazas"},
+			"tbsp": {"cda.", "cdas."},
+			"tsp":  {"cdta.", "cdtas."},
+		},
+	},
+}
+
+// Resolve returns the language locale is formatted in: its own language
+// if it has formatting rules, English otherwise.
+func Resolve(locale Locale) Locale {
+	if _, ok := localeRules[locale.Language()]; ok {
+		return locale.Language()
+	}
+	return English
+}
+
+func rulesFor(locale Locale) rules {
+	return localeRules[Resolve(locale)]
+}
+
+// unit returns the display name of unit.
+func (r rules) unit(unit string, plural bool) string {
+	known, ok := units.LookupUnit(unit)
+	if !ok {
+		if plural && r.pluralizeNames {
+			return pluralize(unit)
+		}
+		return unit
+	}
+	name, ok := r.unitNames[known.Name]
+	if !ok {
+		return known.Name
+	}
+	if plural {
+		return name.plural
+	}
+	return name.singular
+}
@@ -0,0 +1,67 @@
+package ingredient
+
+import "strings"
+
+// irregularPlurals lists ingredient words not pluralized by the suffix
+// rules.
+var irregularPlurals = map[string]string{
+	"leaf":   "leaves",
+	"loaf":   "loaves",
+	"half":   "halves",
+	"potato": "potatoes",
+	"tomato": "tomatoes",
+	"mango":  "mangoes",
+}
+
+// pluralize returns the English plural of a word. Words that already end
+// in "s" are assumed to be plural.
+func pluralize(word string) string {
+	lower := strings.ToLower(word)
+	if plural, ok := irregularPlurals[lower]; ok {
+		// Keep a leading capital, as in "Tomatoes".
+		if lower != word {
+			return strings.ToUpper(plural[:1]) + plural[1:]
+		}
+		return plural
+	}
+	switch {
+	case strings.HasSuffix(lower, "s"):
+		return word
+	case strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return word + "es"
+	case len(lower) > 1 && strings.HasSuffix(lower, "y") && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return word[:len(word)-1] + "ies"
+	default:
+		return word + "s"
+	}
+}
+
+// pluralizeLast pluralizes the last word of name, as in "egg yolk".
+func pluralizeLast(name string) string {
+	i := strings.LastIndexByte(name, ' ')
+	return name[:i+1] + pluralize(name[i+1:])
+}
+
+// Singular returns the English singular of a lowercase word, undoing
+// pluralize. Words that do not look plural, such as "couscous" or
+// "hummus", are returned unchanged.
+func Singular(word string) string {
+	for singular, plural := range irregularPlurals {
+		if word == plural {
+			return singular
+		}
+	}
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") &&
+		!strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
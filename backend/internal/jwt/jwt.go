@@ -19,13 +19,14 @@ const (
 	JWTDuration = time.Hour
 )
 
-func GenerateJWT(params JWTParams, secret []byte, version string) (string, error) {
+// GenerateJWT signs a token issued at issuedAt that expires after JWTDuration.
+func GenerateJWT(params JWTParams, secret []byte, version string, issuedAt time.Time) (string, error) {
 	// Build token
 	claims := jwt.MapClaims{
 		"sub":  params.UserID,
 		"role": params.Role.String(),
-		"iat":  time.Now().Unix(),
-		"exp":  time.Now().Add(JWTDuration).Unix(),
+		"iat":  issuedAt.Unix(),
+		"exp":  issuedAt.Add(JWTDuration).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	token.Header["kid"] = version
@@ -39,7 +40,8 @@ func GenerateJWT(params JWTParams, secret []byte, version string) (string, error
 	return signedKey, nil
 }
 
-func ValidateJWT(rawToken, version string, secret []byte) (*jwt.Token, error) {
+// ValidateJWT parses the token, checking time based claims against now.
+func ValidateJWT(rawToken, version string, secret []byte, now time.Time) (*jwt.Token, error) {
 	parserFunc := func(token *jwt.Token) (any, error) {
 		kidVal, ok := token.Header["kid"].(string)
 		if !ok {
@@ -54,7 +56,9 @@ func ValidateJWT(rawToken, version string, secret []byte) (*jwt.Token, error) {
 	}
 
 	// Parse the token
-	token, err := jwt.Parse(rawToken, parserFunc)
+	token, err := jwt.Parse(rawToken, parserFunc, jwt.WithTimeFunc(func() time.Time {
+		return now
+	}))
 	if err != nil {
 		return nil, err
 	}
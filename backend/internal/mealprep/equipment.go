@@ -0,0 +1,39 @@
+package mealprep
+
+import (
+	"github.com/matt-dz/wecook/internal/tagging"
+)
+
+// EquipmentVocabulary maps equipment to the keywords in step
+// instructions that indicate it is needed.
+var EquipmentVocabulary = tagging.Vocabulary{
+	"oven":            {"oven", "bake", "baking", "roast", "roasting", "broil", "preheat"},
+	"stovetop":        {"stovetop", "simmer", "simmering", "boil", "boiling", "saute", "sauté", "fry", "frying", "sear"},
+	"skillet":         {"skillet", "frying pan", "fry pan"},
+	"saucepan":        {"saucepan", "sauce pan"},
+	"stockpot":        {"stockpot", "stock pot", "large pot", "dutch oven"},
+	"baking sheet":    {"baking sheet", "sheet pan", "baking tray", "cookie sheet"},
+	"baking dish":     {"baking dish", "casserole dish", "loaf pan", "cake pan", "muffin tin"},
+	"mixing bowl":     {"bowl", "mixing bowl"},
+	"whisk":           {"whisk"},
+	"blender":         {"blender", "blend", "purée", "puree"},
+	"food processor":  {"food processor"},
+	"stand mixer":     {"stand mixer", "mixer"},
+	"grill":           {"grill"},
+	"slow cooker":     {"slow cooker", "crockpot", "crock pot"},
+	"pressure cooker": {"pressure cooker", "instant pot"},
+	"air fryer":       {"air fryer"},
+	"microwave":       {"microwave"},
+	"cutting board":   {"chop", "chopped", "dice", "diced", "mince", "minced", "slice", "sliced", "cutting board"},
+}
+
+// DetectEquipment returns the equipment mentioned by the given step
+// instructions, in descending order of mentions.
+func DetectEquipment(instructions []string) []string {
+	suggestions := tagging.Suggest("", instructions, EquipmentVocabulary)
+	equipment := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		equipment = append(equipment, suggestion.Tag)
+	}
+	return equipment
+}
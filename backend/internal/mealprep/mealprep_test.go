@@ -0,0 +1,133 @@
+package mealprep
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		description  string
+		wantQuantity float64
+		wantRest     string
+		wantOK       bool
+	}{
+		{description: "2 cups flour", wantQuantity: 2, wantRest: "cups flour", wantOK: true},
+		{description: "1.5 tbsp butter", wantQuantity: 1.5, wantRest: "tbsp butter", wantOK: true},
+		{description: "1/2 tsp salt", wantQuantity: 0.5, wantRest: "tsp salt", wantOK: true},
+		{description: "1 1/2 cups milk", wantQuantity: 1.5, wantRest: "cups milk", wantOK: true},
+		{description: "½ cup sugar", wantQuantity: 0.5, wantRest: "cup sugar", wantOK: true},
+		{description: "1½ cups rice", wantQuantity: 1.5, wantRest: "cups rice", wantOK: true},
+		{description: "3 eggs", wantQuantity: 3, wantRest: "eggs", wantOK: true},
+		{description: "2 3 eggs", wantQuantity: 2, wantRest: "3 eggs", wantOK: true},
+		{description: "salt to taste", wantRest: "salt to taste"},
+		{description: "  pinch of pepper ", wantRest: "pinch of pepper"},
+		{description: "1/0 cup water", wantRest: "1/0 cup water"},
+		{description: "", wantRest: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			quantity, rest, ok := ParseQuantity(tt.description)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if quantity != tt.wantQuantity {
+				t.Errorf("quantity = %v, want %v", quantity, tt.wantQuantity)
+			}
+			if rest != tt.wantRest {
+				t.Errorf("rest = %q, want %q", rest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestDetectEquipment(t *testing.T) {
+	got := DetectEquipment([]string{
+		"Preheat the oven to 200C.",
+		"Whisk the eggs in a large bowl.",
+		"Bake on a sheet pan for 20 minutes.",
+	})
+	want := []string{"oven", "baking sheet", "mixing bowl", "whisk"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectEquipment() = %v, want %v", got, want)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	slots, total := Schedule([]Task{
+		{RecipeID: 1, Title: "Salad", Prep: 15 * time.Minute},
+		{RecipeID: 2, Title: "Stew", Prep: 20 * time.Minute, Cook: 2 * time.Hour},
+		{RecipeID: 3, Title: "Rice", Prep: 5 * time.Minute, Cook: 20 * time.Minute},
+	})
+
+	want := []Slot{
+		{RecipeID: 2, Title: "Stew", Phase: PhasePrep, Start: 0, End: 20 * time.Minute},
+		{RecipeID: 2, Title: "Stew", Phase: PhaseCook, Start: 20 * time.Minute, End: 140 * time.Minute},
+		{RecipeID: 3, Title: "Rice", Phase: PhasePrep, Start: 20 * time.Minute, End: 25 * time.Minute},
+		{RecipeID: 3, Title: "Rice", Phase: PhaseCook, Start: 25 * time.Minute, End: 45 * time.Minute},
+		{RecipeID: 1, Title: "Salad", Phase: PhasePrep, Start: 25 * time.Minute, End: 40 * time.Minute},
+	}
+	if !reflect.DeepEqual(slots, want) {
+		t.Errorf("Schedule() slots = %+v, want %+v", slots, want)
+	}
+	if total != 140*time.Minute {
+		t.Errorf("Schedule() total = %v, want %v", total, 140*time.Minute)
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	plan := NewPlan([]Recipe{
+		{
+			ID:             1,
+			Title:          "Pancakes",
+			Servings:       4,
+			TargetServings: 8,
+			Prep:           10 * time.Minute,
+			Cook:           15 * time.Minute,
+			Ingredients:    []string{"2 cups flour", "1 1/2 cups milk", "salt to taste"},
+			Instructions:   []string{"Whisk everything in a bowl.", "Fry in a skillet."},
+		},
+		{
+			ID:             2,
+			Title:          "Bread",
+			Servings:       0,
+			TargetServings: 12,
+			Prep:           30 * time.Minute,
+			Cook:           time.Hour,
+			Ingredients:    []string{"3 Cups Flour", "Salt to taste", "1 packet yeast"},
+			Instructions:   []string{"Knead in a bowl.", "Bake in the oven."},
+		},
+	})
+
+	// Pancakes are doubled; bread has no servings so is not scaled.
+	seven, three, one := 7.0, 3.0, 1.0
+	wantIngredients := []Ingredient{
+		{Quantity: &seven, Item: "cups flour", RecipeIDs: []int64{1, 2}},
+		{Quantity: &three, Item: "cups milk", RecipeIDs: []int64{1}},
+		{Item: "salt to taste", RecipeIDs: []int64{1, 2}},
+		{Quantity: &one, Item: "packet yeast", RecipeIDs: []int64{2}},
+	}
+	if !reflect.DeepEqual(plan.Ingredients, wantIngredients) {
+		t.Errorf("Ingredients = %+v, want %+v", plan.Ingredients, wantIngredients)
+	}
+
+	wantEquipment := []Equipment{
+		{Name: "mixing bowl", RecipeIDs: []int64{1, 2}},
+		{Name: "oven", RecipeIDs: []int64{2}},
+		{Name: "skillet", RecipeIDs: []int64{1}},
+		{Name: "stovetop", RecipeIDs: []int64{1}},
+		{Name: "whisk", RecipeIDs: []int64{1}},
+	}
+	if !reflect.DeepEqual(plan.Equipment, wantEquipment) {
+		t.Errorf("Equipment = %+v, want %+v", plan.Equipment, wantEquipment)
+	}
+
+	if plan.Total != 90*time.Minute {
+		t.Errorf("Total = %v, want %v", plan.Total, 90*time.Minute)
+	}
+	if len(plan.Timeline) != 4 || plan.Timeline[0].RecipeID != 2 {
+		t.Errorf("Timeline = %+v, expected bread to be prepped first", plan.Timeline)
+	}
+}
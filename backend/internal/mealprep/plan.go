@@ -0,0 +1,150 @@
+// Package mealprep combines several recipes into a batch cooking plan.
+package mealprep
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Recipe is a recipe to include in a plan.
+type Recipe struct {
+	ID    int64
+	Title string
+	// Servings is the number of servings the recipe makes, or 0 if unknown.
+	Servings float64
+	// TargetServings is the number of servings to make.
+	TargetServings float64
+	Prep           time.Duration
+	Cook           time.Duration
+	Ingredients    []string
+	Instructions   []string
+}
+
+// Scale is the factor the recipe's ingredients are multiplied by.
+// Recipes without servings are not scaled.
+func (r Recipe) Scale() float64 {
+	if r.Servings <= 0 || r.TargetServings <= 0 {
+		return 1
+	}
+	return r.TargetServings / r.Servings
+}
+
+// Ingredient is a combined ingredient requirement.
+type Ingredient struct {
+	// Quantity is the total scaled quantity. It is nil if the
+	// ingredient had no leading quantity.
+	Quantity *float64
+	// Item is the rest of the description, such as "cups flour".
+	Item      string
+	RecipeIDs []int64
+}
+
+// Equipment is a piece of equipment needed by one or more recipes.
+type Equipment struct {
+	Name      string
+	RecipeIDs []int64
+}
+
+// Plan is a combined batch cooking plan.
+type Plan struct {
+	Ingredients []Ingredient
+	Equipment   []Equipment
+	Timeline    []Slot
+	Total       time.Duration
+}
+
+// NewPlan scales and merges the ingredients of recipes, lists the
+// equipment they need, and schedules their prep and cook times.
+// Ingredients are merged when their descriptions match after the
+// quantity, ignoring case and plurals; unquantified ingredients are
+// merged by description alone.
+func NewPlan(recipes []Recipe) Plan {
+	var plan Plan
+
+	ingredients := make(map[string]*Ingredient)
+	var ingredientOrder []string
+	equipment := make(map[string]*Equipment)
+	tasks := make([]Task, 0, len(recipes))
+
+	for _, recipe := range recipes {
+		scale := recipe.Scale()
+
+		for _, description := range recipe.Ingredients {
+			quantity, item, ok := ParseQuantity(description)
+			if item == "" {
+				continue
+			}
+			key := itemKey(item)
+			if !ok {
+				key = "\x00" + key
+			}
+
+			ingredient, found := ingredients[key]
+			if !found {
+				ingredient = &Ingredient{Item: item}
+				if ok {
+					ingredient.Quantity = new(float64)
+				}
+				ingredients[key] = ingredient
+				ingredientOrder = append(ingredientOrder, key)
+			}
+			if ok {
+				*ingredient.Quantity += quantity * scale
+			}
+			if !slices.Contains(ingredient.RecipeIDs, recipe.ID) {
+				ingredient.RecipeIDs = append(ingredient.RecipeIDs, recipe.ID)
+			}
+		}
+
+		for _, name := range DetectEquipment(recipe.Instructions) {
+			item, found := equipment[name]
+			if !found {
+				item = &Equipment{Name: name}
+				equipment[name] = item
+			}
+			item.RecipeIDs = append(item.RecipeIDs, recipe.ID)
+		}
+
+		tasks = append(tasks, Task{
+			RecipeID: recipe.ID,
+			Title:    recipe.Title,
+			Prep:     recipe.Prep,
+			Cook:     recipe.Cook,
+		})
+	}
+
+	for _, key := range ingredientOrder {
+		ingredient := ingredients[key]
+		if ingredient.Quantity != nil {
+			*ingredient.Quantity = roundQuantity(*ingredient.Quantity)
+		}
+		plan.Ingredients = append(plan.Ingredients, *ingredient)
+	}
+
+	for _, item := range equipment {
+		plan.Equipment = append(plan.Equipment, *item)
+	}
+	slices.SortFunc(plan.Equipment, func(a, b Equipment) int {
+		if c := cmp.Compare(len(b.RecipeIDs), len(a.RecipeIDs)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	plan.Timeline, plan.Total = Schedule(tasks)
+	return plan
+}
+
+// itemKey normalizes an ingredient description for merging by
+// lowercasing it and removing plural "s" suffixes.
+func itemKey(item string) string {
+	fields := strings.Fields(strings.ToLower(item))
+	for i, field := range fields {
+		if len(field) > 3 && strings.HasSuffix(field, "s") && !strings.HasSuffix(field, "ss") {
+			fields[i] = strings.TrimSuffix(field, "s")
+		}
+	}
+	return strings.Join(fields, " ")
+}
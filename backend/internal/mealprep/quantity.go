@@ -0,0 +1,85 @@
+package mealprep
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// quantityPrecision is the number of decimal places scaled quantities
+// are rounded to.
+const quantityPrecision = 100
+
+var unicodeFractions = map[rune]float64{
+	'¼': 0.25, '½': 0.5, '¾': 0.75,
+	'⅓': 1.0 / 3, '⅔': 2.0 / 3,
+	'⅛': 0.125, '⅜': 0.375, '⅝': 0.625, '⅞': 0.875,
+}
+
+// ParseQuantity splits a leading quantity from an ingredient description.
+// Whole numbers, decimals, fractions ("1/2"), mixed numbers ("1 1/2"),
+// and unicode fractions ("1½") are understood. ok is false if the
+// description does not start with a quantity.
+func ParseQuantity(description string) (quantity float64, rest string, ok bool) {
+	fields := strings.Fields(description)
+	used := 0
+	for used < len(fields) {
+		value, valid := parseNumber(fields[used])
+		if !valid {
+			break
+		}
+		// Only a fraction may follow a whole number, as in "1 1/2".
+		if used > 0 && (value >= 1 || quantity != math.Trunc(quantity)) {
+			break
+		}
+		quantity += value
+		used++
+	}
+	if used == 0 {
+		return 0, strings.TrimSpace(description), false
+	}
+	return quantity, strings.Join(fields[used:], " "), true
+}
+
+// parseNumber parses a single whole number, decimal, fraction, or a
+// number followed by a unicode fraction.
+func parseNumber(s string) (float64, bool) {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return 0, false
+	}
+	if fraction, ok := unicodeFractions[runes[len(runes)-1]]; ok {
+		if len(runes) == 1 {
+			return fraction, true
+		}
+		whole, err := strconv.ParseUint(string(runes[:len(runes)-1]), 10, 32)
+		if err != nil {
+			return 0, false
+		}
+		return float64(whole) + fraction, true
+	}
+	if numerator, denominator, found := strings.Cut(s, "/"); found {
+		n, err := strconv.ParseUint(numerator, 10, 32)
+		if err != nil {
+			return 0, false
+		}
+		d, err := strconv.ParseUint(denominator, 10, 32)
+		if err != nil || d == 0 {
+			return 0, false
+		}
+		return float64(n) / float64(d), true
+	}
+	if !unicode.IsDigit(runes[0]) {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	return value, true
+}
+
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*quantityPrecision) / quantityPrecision
+}
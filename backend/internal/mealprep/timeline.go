@@ -0,0 +1,73 @@
+package mealprep
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Phase is a stage of preparing a recipe.
+type Phase string
+
+const (
+	PhasePrep Phase = "prep"
+	PhaseCook Phase = "cook"
+)
+
+// Task is the prep and cook time of one recipe.
+type Task struct {
+	RecipeID int64
+	Title    string
+	Prep     time.Duration
+	Cook     time.Duration
+}
+
+// Slot is a scheduled phase of a recipe, relative to the start of the session.
+type Slot struct {
+	RecipeID int64
+	Title    string
+	Phase    Phase
+	Start    time.Duration
+	End      time.Duration
+}
+
+// Schedule interleaves tasks for a single cook. Prep is hands-on, so
+// only one recipe is prepped at a time; cooking is hands-off and may
+// overlap with other recipes. Recipes with the longest cook time are
+// prepped first so that the session ends as early as possible. Slots
+// are ordered by start time.
+func Schedule(tasks []Task) (slots []Slot, total time.Duration) {
+	ordered := slices.Clone(tasks)
+	slices.SortStableFunc(ordered, func(a, b Task) int {
+		return cmp.Compare(b.Cook, a.Cook)
+	})
+
+	var cursor time.Duration
+	for _, task := range ordered {
+		if task.Prep > 0 {
+			slots = append(slots, Slot{
+				RecipeID: task.RecipeID,
+				Title:    task.Title,
+				Phase:    PhasePrep,
+				Start:    cursor,
+				End:      cursor + task.Prep,
+			})
+		}
+		cursor += task.Prep
+		if task.Cook > 0 {
+			slots = append(slots, Slot{
+				RecipeID: task.RecipeID,
+				Title:    task.Title,
+				Phase:    PhaseCook,
+				Start:    cursor,
+				End:      cursor + task.Cook,
+			})
+		}
+		total = max(total, cursor+task.Cook)
+	}
+
+	slices.SortStableFunc(slots, func(a, b Slot) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+	return slots, total
+}
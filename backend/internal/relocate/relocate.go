@@ -0,0 +1,260 @@
+// Package relocate moves stored images to the layout described by the
+// configured path template and updates their keys in the database.
+package relocate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/matt-dz/wecook/internal/branding"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/fileserver"
+	"github.com/matt-dz/wecook/internal/filestore"
+	"github.com/matt-dz/wecook/internal/undo"
+)
+
+// Store locates and moves stored files.
+type Store interface {
+	RelocatedKey(kind, key string) string
+	MoveKey(from, to string) error
+}
+
+// Result summarizes a run.
+type Result struct {
+	// Moved is the number of files moved, or that would be moved on a dry run.
+	Moved int
+	// Unchanged is the number of files already in place.
+	Unchanged int
+	// Missing is the number of keys whose file does not exist.
+	Missing int
+}
+
+type image struct {
+	id int64
+	// token identifies the images held by undo tokens, which have no id.
+	token string
+	key   string
+	// file is the filestore kind of the image, if it differs from the
+	// kind of its table.
+	file string
+}
+
+type move struct {
+	from, to string
+}
+
+// kind describes how to list and update the image keys of one table.
+type kind struct {
+	name string
+	// file is the filestore kind the images are written as, if not name.
+	file   string
+	list   func(ctx context.Context, q database.Querier) ([]image, error)
+	update func(ctx context.Context, q database.Querier, img image, key string) error
+}
+
+// fileKind returns the filestore kind img is written as.
+func (k kind) fileKind(img image) string {
+	switch {
+	case img.file != "":
+		return img.file
+	case k.file != "":
+		return k.file
+	default:
+		return k.name
+	}
+}
+
+// undoFileKinds are the filestore kinds of the images held by each kind of
+// undo token.
+var undoFileKinds = map[undo.Kind]string{
+	undo.KindIngredient:      filestore.KindIngredient,
+	undo.KindStep:            filestore.KindStep,
+	undo.KindRecipeImage:     filestore.KindCover,
+	undo.KindIngredientImage: filestore.KindIngredient,
+	undo.KindStepImage:       filestore.KindStep,
+}
+
+var kinds = []kind{
+	{
+		name: filestore.KindCover,
+		list: func(ctx context.Context, q database.Querier) ([]image, error) {
+			rows, err := q.GetAllRecipeCoverImageKeys(ctx)
+			images := make([]image, 0, len(rows))
+			for _, row := range rows {
+				images = append(images, image{id: row.ID, key: row.ImageKey.String})
+			}
+			return images, err
+		},
+		update: func(ctx context.Context, q database.Querier, img image, key string) error {
+			return q.UpdateRecipeCoverImage(ctx, database.UpdateRecipeCoverImageParams{
+				ImageKey: pgtype.Text{String: key, Valid: true},
+				ID:       img.id,
+			})
+		},
+	},
+	{
+		name: filestore.KindIngredient,
+		list: func(ctx context.Context, q database.Querier) ([]image, error) {
+			rows, err := q.GetAllRecipeIngredientImageKeys(ctx)
+			images := make([]image, 0, len(rows))
+			for _, row := range rows {
+				images = append(images, image{id: row.ID, key: row.ImageKey.String})
+			}
+			return images, err
+		},
+		update: func(ctx context.Context, q database.Querier, img image, key string) error {
+			return q.UpdateRecipeIngredientImage(ctx, database.UpdateRecipeIngredientImageParams{
+				ImageKey: pgtype.Text{String: key, Valid: true},
+				ID:       img.id,
+			})
+		},
+	},
+	{
+		name: filestore.KindStep,
+		list: func(ctx context.Context, q database.Querier) ([]image, error) {
+			rows, err := q.GetAllRecipeStepImageKeys(ctx)
+			images := make([]image, 0, len(rows))
+			for _, row := range rows {
+				images = append(images, image{id: row.ID, key: row.ImageKey.String})
+			}
+			return images, err
+		},
+		update: func(ctx context.Context, q database.Querier, img image, key string) error {
+			return q.UpdateRecipeStepImage(ctx, database.UpdateRecipeStepImageParams{
+				ImageKey: pgtype.Text{String: key, Valid: true},
+				ID:       img.id,
+			})
+		},
+	},
+	{
+		name: "stock",
+		file: filestore.KindIngredient,
+		list: func(ctx context.Context, q database.Querier) ([]image, error) {
+			rows, err := q.GetAllStockImageKeys(ctx)
+			images := make([]image, 0, len(rows))
+			for _, row := range rows {
+				images = append(images, image{id: row.ID, key: row.ImageKey})
+			}
+			return images, err
+		},
+		update: func(ctx context.Context, q database.Querier, img image, key string) error {
+			return q.UpdateStockImageKey(ctx, database.UpdateStockImageKeyParams{
+				ImageKey: key,
+				ID:       img.id,
+			})
+		},
+	},
+	{
+		// Images held by undo tokens are restored with their key, so they
+		// move with the rest.
+		name: "undo",
+		list: func(ctx context.Context, q database.Querier) ([]image, error) {
+			rows, err := q.GetAllUndoTokenImageKeys(ctx)
+			images := make([]image, 0, len(rows))
+			for _, row := range rows {
+				file, ok := undoFileKinds[undo.Kind(row.Kind)]
+				if !ok {
+					return nil, fmt.Errorf("unknown undo token kind %q", row.Kind)
+				}
+				images = append(images, image{token: row.Token, key: row.ImageKey.String, file: file})
+			}
+			return images, err
+		},
+		update: func(ctx context.Context, q database.Querier, img image, key string) error {
+			return q.UpdateUndoTokenImage(ctx, database.UpdateUndoTokenImageParams{
+				ImageKey: pgtype.Text{String: key, Valid: true},
+				Token:    img.token,
+			})
+		},
+	},
+	{
+		name: filestore.KindBranding,
+		list: func(ctx context.Context, q database.Querier) ([]image, error) {
+			row, err := q.GetInstanceBranding(ctx, branding.ID)
+			if errors.Is(err, pgx.ErrNoRows) || (err == nil && !row.LogoKey.Valid) {
+				return nil, nil
+			}
+			return []image{{id: int64(row.ID), key: row.LogoKey.String}}, err
+		},
+		update: func(ctx context.Context, q database.Querier, img image, key string) error {
+			_, err := q.SetInstanceLogo(ctx, database.SetInstanceLogoParams{
+				ID:      int32(img.id),
+				LogoKey: pgtype.Text{String: key, Valid: true},
+			})
+			return err
+		},
+	},
+}
+
+// Run moves every stored image whose key does not match the store's
+// path template and updates the key in the same transaction. If the
+// transaction fails, files already moved are moved back. On a dry run
+// nothing is changed and Result reports what would be moved.
+func Run(ctx context.Context, db database.Transactor, store Store, logger *slog.Logger, dryRun bool) (
+	Result, error,
+) {
+	var (
+		result Result
+		moves  []move
+	)
+
+	err := db.WithTx(ctx, func(q database.Querier) error {
+		for _, k := range kinds {
+			images, err := k.list(ctx, q)
+			if err != nil {
+				return fmt.Errorf("listing %s images: %w", k.name, err)
+			}
+
+			for _, img := range images {
+				to := store.RelocatedKey(k.fileKind(img), img.key)
+				if to == img.key {
+					result.Unchanged++
+					continue
+				}
+				if dryRun {
+					logger.InfoContext(ctx, "would move image", slog.String("from", img.key), slog.String("to", to))
+					result.Moved++
+					continue
+				}
+
+				// Move file
+				if err := store.MoveKey(img.key, to); errors.Is(err, fileserver.ErrNotExist) {
+					logger.WarnContext(ctx, "image file is missing, skipping", slog.String("key", img.key))
+					result.Missing++
+					continue
+				} else if err != nil {
+					return fmt.Errorf("moving %q to %q: %w", img.key, to, err)
+				}
+				moves = append(moves, move{from: img.key, to: to})
+
+				// Update key
+				if err := k.update(ctx, q, img, to); err != nil {
+					return fmt.Errorf("updating %s image %q: %w", k.name, img.key, err)
+				}
+				result.Moved++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		rollback(ctx, store, logger, moves)
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// rollback moves files back to their original keys in reverse order.
+func rollback(ctx context.Context, store Store, logger *slog.Logger, moves []move) {
+	for i := len(moves) - 1; i >= 0; i-- {
+		m := moves[i]
+		if err := store.MoveKey(m.to, m.from); err != nil {
+			logger.ErrorContext(ctx, "failed to restore image",
+				slog.String("from", m.to), slog.String("to", m.from), slog.Any("error", err))
+		}
+	}
+}
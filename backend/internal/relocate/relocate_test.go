@@ -0,0 +1,229 @@
+package relocate
+
+import (
+	"context"
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/matt-dz/wecook/internal/branding"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/fileserver"
+	"github.com/matt-dz/wecook/internal/log"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeTx runs fn against q and optionally fails afterwards, as a failed
+// commit would.
+type fakeTx struct {
+	q         database.Querier
+	commitErr error
+}
+
+func (f fakeTx) WithTx(_ context.Context, fn func(q database.Querier) error) error {
+	if err := fn(f.q); err != nil {
+		return err
+	}
+	return f.commitErr
+}
+
+// fakeStore relocates keys into a "new" directory.
+type fakeStore struct {
+	files map[string]bool
+}
+
+func (s *fakeStore) RelocatedKey(kind, key string) string {
+	return path.Join("/files", kind, "new", path.Base(key))
+}
+
+func (s *fakeStore) MoveKey(from, to string) error {
+	if !s.files[from] {
+		return fileserver.ErrNotExist
+	}
+	delete(s.files, from)
+	s.files[to] = true
+	return nil
+}
+
+func text(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: true}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		dryRun     bool
+		commitErr  error
+		setupMock  func(m *database.MockQuerier)
+		wantFiles  map[string]bool
+		wantResult Result
+		wantErr    bool
+	}{
+		{
+			name: "moves files and updates keys",
+			setupMock: func(m *database.MockQuerier) {
+				m.EXPECT().GetAllRecipeCoverImageKeys(ctx).Return([]database.GetAllRecipeCoverImageKeysRow{
+					{ID: 1, ImageKey: text("/files/covers/a.png")},
+					{ID: 2, ImageKey: text("/files/covers/new/b.png")},
+				}, nil)
+				m.EXPECT().UpdateRecipeCoverImage(ctx, database.UpdateRecipeCoverImageParams{
+					ImageKey: text("/files/covers/new/a.png"), ID: 1,
+				}).Return(nil)
+				m.EXPECT().GetAllRecipeIngredientImageKeys(ctx).Return([]database.GetAllRecipeIngredientImageKeysRow{
+					{ID: 3, ImageKey: text("/files/ingredients/missing.png")},
+				}, nil)
+				m.EXPECT().GetAllRecipeStepImageKeys(ctx).Return([]database.GetAllRecipeStepImageKeysRow{
+					{ID: 4, ImageKey: text("/files/steps/c.png")},
+				}, nil)
+				m.EXPECT().UpdateRecipeStepImage(ctx, database.UpdateRecipeStepImageParams{
+					ImageKey: text("/files/steps/new/c.png"), ID: 4,
+				}).Return(nil)
+				m.EXPECT().GetAllStockImageKeys(ctx).Return([]database.GetAllStockImageKeysRow{
+					{ID: 5, ImageKey: "/files/ingredients/stock.png"},
+				}, nil)
+				m.EXPECT().UpdateStockImageKey(ctx, database.UpdateStockImageKeyParams{
+					ImageKey: "/files/ingredients/new/stock.png", ID: 5,
+				}).Return(nil)
+				m.EXPECT().GetAllUndoTokenImageKeys(ctx).Return([]database.GetAllUndoTokenImageKeysRow{
+					{Token: "t", Kind: "step_image", ImageKey: text("/files/steps/held.png")},
+				}, nil)
+				m.EXPECT().UpdateUndoTokenImage(ctx, database.UpdateUndoTokenImageParams{
+					ImageKey: text("/files/steps/new/held.png"), Token: "t",
+				}).Return(nil)
+				m.EXPECT().GetInstanceBranding(ctx, int32(branding.ID)).Return(database.InstanceBranding{
+					ID: branding.ID, LogoKey: text("/files/branding/logo.png"),
+				}, nil)
+				m.EXPECT().SetInstanceLogo(ctx, database.SetInstanceLogoParams{
+					ID: branding.ID, LogoKey: text("/files/branding/new/logo.png"),
+				}).Return(pgtype.Text{}, nil)
+			},
+			wantFiles: map[string]bool{
+				"/files/covers/new/a.png":          true,
+				"/files/covers/new/b.png":          true,
+				"/files/steps/new/c.png":           true,
+				"/files/ingredients/new/stock.png": true,
+				"/files/steps/new/held.png":        true,
+				"/files/branding/new/logo.png":     true,
+			},
+			wantResult: Result{Moved: 5, Unchanged: 1, Missing: 1},
+		},
+		{
+			name:   "dry run changes nothing",
+			dryRun: true,
+			setupMock: func(m *database.MockQuerier) {
+				m.EXPECT().GetAllRecipeCoverImageKeys(ctx).Return([]database.GetAllRecipeCoverImageKeysRow{
+					{ID: 1, ImageKey: text("/files/covers/a.png")},
+				}, nil)
+				m.EXPECT().GetAllRecipeIngredientImageKeys(ctx).Return(nil, nil)
+				m.EXPECT().GetAllRecipeStepImageKeys(ctx).Return([]database.GetAllRecipeStepImageKeysRow{
+					{ID: 4, ImageKey: text("/files/steps/c.png")},
+				}, nil)
+				m.EXPECT().GetAllStockImageKeys(ctx).Return(nil, nil)
+				m.EXPECT().GetAllUndoTokenImageKeys(ctx).Return(nil, nil)
+				m.EXPECT().GetInstanceBranding(ctx, int32(branding.ID)).Return(database.InstanceBranding{
+					ID: branding.ID,
+				}, nil)
+			},
+			wantFiles: map[string]bool{
+				"/files/covers/a.png":          true,
+				"/files/covers/new/b.png":      true,
+				"/files/steps/c.png":           true,
+				"/files/ingredients/stock.png": true,
+				"/files/steps/held.png":        true,
+				"/files/branding/logo.png":     true,
+			},
+			wantResult: Result{Moved: 2},
+		},
+		{
+			name: "update failure restores moved files",
+			setupMock: func(m *database.MockQuerier) {
+				m.EXPECT().GetAllRecipeCoverImageKeys(ctx).Return([]database.GetAllRecipeCoverImageKeysRow{
+					{ID: 1, ImageKey: text("/files/covers/a.png")},
+				}, nil)
+				m.EXPECT().UpdateRecipeCoverImage(ctx, gomock.Any()).Return(nil)
+				m.EXPECT().GetAllRecipeIngredientImageKeys(ctx).Return(nil, nil)
+				m.EXPECT().GetAllRecipeStepImageKeys(ctx).Return([]database.GetAllRecipeStepImageKeysRow{
+					{ID: 4, ImageKey: text("/files/steps/c.png")},
+				}, nil)
+				m.EXPECT().UpdateRecipeStepImage(ctx, gomock.Any()).Return(errors.New("db error"))
+			},
+			wantFiles: map[string]bool{
+				"/files/covers/a.png":          true,
+				"/files/covers/new/b.png":      true,
+				"/files/steps/c.png":           true,
+				"/files/ingredients/stock.png": true,
+				"/files/steps/held.png":        true,
+				"/files/branding/logo.png":     true,
+			},
+			wantErr: true,
+		},
+		{
+			name:      "commit failure restores moved files",
+			commitErr: errors.New("commit failed"),
+			setupMock: func(m *database.MockQuerier) {
+				m.EXPECT().GetAllRecipeCoverImageKeys(ctx).Return([]database.GetAllRecipeCoverImageKeysRow{
+					{ID: 1, ImageKey: text("/files/covers/a.png")},
+				}, nil)
+				m.EXPECT().UpdateRecipeCoverImage(ctx, gomock.Any()).Return(nil)
+				m.EXPECT().GetAllRecipeIngredientImageKeys(ctx).Return(nil, nil)
+				m.EXPECT().GetAllRecipeStepImageKeys(ctx).Return(nil, nil)
+				m.EXPECT().GetAllStockImageKeys(ctx).Return(nil, nil)
+				m.EXPECT().GetAllUndoTokenImageKeys(ctx).Return(nil, nil)
+				m.EXPECT().GetInstanceBranding(ctx, int32(branding.ID)).Return(database.InstanceBranding{}, pgx.ErrNoRows)
+			},
+			wantFiles: map[string]bool{
+				"/files/covers/a.png":          true,
+				"/files/covers/new/b.png":      true,
+				"/files/steps/c.png":           true,
+				"/files/ingredients/stock.png": true,
+				"/files/steps/held.png":        true,
+				"/files/branding/logo.png":     true,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := database.NewMockQuerier(ctrl)
+			tt.setupMock(mockDB)
+
+			store := &fakeStore{files: map[string]bool{
+				"/files/covers/a.png":          true,
+				"/files/covers/new/b.png":      true,
+				"/files/steps/c.png":           true,
+				"/files/ingredients/stock.png": true,
+				"/files/steps/held.png":        true,
+				"/files/branding/logo.png":     true,
+			}}
+
+			result, err := Run(ctx, fakeTx{q: mockDB, commitErr: tt.commitErr}, store, log.NullLogger(), tt.dryRun)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result != tt.wantResult {
+				t.Errorf("result = %+v, want %+v", result, tt.wantResult)
+			}
+			if len(store.files) != len(tt.wantFiles) {
+				t.Fatalf("files = %v, want %v", store.files, tt.wantFiles)
+			}
+			for file := range tt.wantFiles {
+				if !store.files[file] {
+					t.Errorf("expected file %q to exist, files = %v", file, store.files)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/matt-dz/wecook/internal/api/version"
+	"github.com/matt-dz/wecook/internal/branding"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/delivery"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/timezone"
+)
+
+const (
+	// DefaultInterval is how often due reports are looked for.
+	DefaultInterval = time.Hour
+	batchSize       = 100
+	// maxPublished caps the recipes by other cooks listed in a report.
+	maxPublished = 10
+)
+
+// RunWeeklyJob sends due weekly reports every interval until ctx is
+// cancelled.
+func RunWeeklyJob(ctx context.Context, env *env.Env, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		env.Logger.DebugContext(ctx, "sending weekly reports")
+		if err := SendDue(ctx, env); err != nil {
+			env.Logger.ErrorContext(ctx, "failed to send weekly reports", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends the report to every opted-in user whose report is due and
+// schedules their next one for SendDay at SendHour in their time zone.
+// Users who never had a report are due straight away. Users with nothing
+// to report are skipped until the next report is due. An email that
+// cannot be sent is retried by the delivery job; one that cannot be queued
+// is logged and retried on the next run.
+func SendDue(ctx context.Context, env *env.Env) error {
+	now := env.Now()
+	instance, err := branding.Load(ctx, env.Database)
+	if err != nil {
+		env.Logger.WarnContext(ctx, "failed to load branding, using default", slog.Any("error", err))
+		instance = branding.Default()
+	}
+
+	var after int64
+	for {
+		recipients, err := env.Database.GetWeeklyReportRecipients(ctx, database.GetWeeklyReportRecipientsParams{
+			DueBefore: pgtype.Timestamptz{Time: now, Valid: true},
+			After:     after,
+			BatchSize: batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("getting report recipients: %w", err)
+		}
+		if len(recipients) == 0 {
+			return nil
+		}
+
+		for _, recipient := range recipients {
+			after = recipient.ID
+
+			since := now.Add(-Period)
+			if recipient.WeeklyReportSentAt.Valid && recipient.WeeklyReportSentAt.Time.After(since) {
+				since = recipient.WeeklyReportSentAt.Time
+			}
+			loc := timezone.LoadOrUTC(recipient.TimeZone)
+			if err := sendReport(ctx, env, instance.Name, recipient, since, loc); err != nil {
+				env.Logger.ErrorContext(ctx, "failed to send weekly report",
+					slog.Int64("user_id", recipient.ID), slog.Any("error", err))
+				continue
+			}
+			if err := env.Database.MarkWeeklyReportSent(ctx, database.MarkWeeklyReportSentParams{
+				SentAt: pgtype.Timestamptz{Time: now, Valid: true},
+				DueAt:  pgtype.Timestamptz{Time: NextDue(now, loc), Valid: true},
+				ID:     recipient.ID,
+			}); err != nil {
+				return fmt.Errorf("marking report of user %d sent: %w", recipient.ID, err)
+			}
+		}
+	}
+}
+
+func sendReport(ctx context.Context, env *env.Env, instanceName string,
+	recipient database.GetWeeklyReportRecipientsRow, since time.Time, loc *time.Location,
+) error {
+	origin := strings.TrimRight(env.Config.HostOrigin, "/")
+	userID := pgtype.Int8{Int64: recipient.ID, Valid: true}
+	sinceTime := pgtype.Timestamptz{Time: since, Valid: true}
+
+	created, err := env.Database.GetUserRecipesCreatedSince(ctx, database.GetUserRecipesCreatedSinceParams{
+		UserID: userID,
+		Since:  sinceTime,
+	})
+	if err != nil {
+		return fmt.Errorf("getting new recipes: %w", err)
+	}
+	published, err := env.Database.GetPublishedRecipesCreatedSince(ctx, database.GetPublishedRecipesCreatedSinceParams{
+		UserID:     userID,
+		Since:      sinceTime,
+		MaxRecipes: maxPublished,
+	})
+	if err != nil {
+		return fmt.Errorf("getting published recipes: %w", err)
+	}
+
+	unsubscribeURL := UnsubscribeURL(origin+version.Prefix+"/reports/unsubscribe",
+		[]byte(*env.Config.AppSecret.Value), recipient.ID)
+	weekly := Weekly{
+		InstanceName:   instanceName,
+		FirstName:      recipient.FirstName,
+		Since:          since.In(loc),
+		UnsubscribeURL: unsubscribeURL,
+	}
+	for _, recipe := range created {
+		weekly.Created = append(weekly.Created, RecipeLink{
+			Title: recipe.Title,
+			URL:   origin + "/recipes/" + strconv.FormatInt(recipe.ID, 10),
+		})
+	}
+	for _, recipe := range published {
+		weekly.Published = append(weekly.Published, RecipeLink{
+			Title:  recipe.Title,
+			URL:    origin + "/recipes/" + strconv.FormatInt(recipe.ID, 10),
+			Author: strings.TrimSpace(recipe.FirstName + " " + recipe.LastName),
+		})
+	}
+	if weekly.Empty() {
+		env.Logger.DebugContext(ctx, "nothing to report", slog.Int64("user_id", recipient.ID))
+		return nil
+	}
+
+	body, err := Render(weekly)
+	if err != nil {
+		return err
+	}
+	if err := delivery.QueueEmail(ctx, env, []string{recipient.Email}, Subject(instanceName), body,
+		UnsubscribeHeaders(unsubscribeURL)...); err != nil {
+		return fmt.Errorf("queueing email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Package report emails opted-in users a weekly summary of new recipes.
+package report
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/matt-dz/wecook/internal/email"
+	"github.com/matt-dz/wecook/internal/timezone"
+)
+
+// Period is how often a user receives the report.
+const Period = 7 * 24 * time.Hour
+
+// SendDay and SendHour are when the report is sent, in the time zone of
+// each user.
+const (
+	SendDay  = time.Monday
+	SendHour = 8
+)
+
+const (
+	userParam      = "user"
+	signatureParam = "signature"
+)
+
+//go:embed weekly.html
+var weeklyHTML string
+
+var weeklyTemplate = template.Must(template.New("weekly").Parse(weeklyHTML))
+
+// UnsubscribedHTML is the page shown after unsubscribing.
+//
+//go:embed unsubscribed.html
+var UnsubscribedHTML string
+
+//go:embed unsubscribe.html
+var unsubscribeHTML string
+
+var unsubscribeTemplate = template.Must(template.New("unsubscribe").Parse(unsubscribeHTML))
+
+// RecipeLink is a recipe listed in the report.
+type RecipeLink struct {
+	Title string
+	URL   string
+	// Author is set for recipes by other cooks.
+	Author string
+}
+
+// Weekly is the content of one user's report.
+type Weekly struct {
+	// InstanceName is the name the instance is branded with.
+	InstanceName   string
+	FirstName      string
+	Since          time.Time
+	Created        []RecipeLink
+	Published      []RecipeLink
+	UnsubscribeURL string
+}
+
+// Empty reports whether there is nothing to tell the user.
+func (w Weekly) Empty() bool {
+	return len(w.Created) == 0 && len(w.Published) == 0
+}
+
+// Subject returns the subject line of the report email of an instance.
+func Subject(instanceName string) string {
+	return "Your week on " + instanceName
+}
+
+// NextDue returns when the next report after t is due for a user in loc,
+// in UTC.
+func NextDue(t time.Time, loc *time.Location) time.Time {
+	return timezone.NextWeekly(t, loc, SendDay, SendHour)
+}
+
+// Render returns the HTML body of the report.
+func Render(w Weekly) (string, error) {
+	var buf bytes.Buffer
+	if err := weeklyTemplate.Execute(&buf, w); err != nil {
+		return "", fmt.Errorf("rendering weekly report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Sign returns the unsubscribe signature of a user.
+func Sign(secret []byte, userID int64) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("weekly-report-unsubscribe"))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(userID, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the unsubscribe signature of userID.
+func Verify(secret []byte, userID int64, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, userID)), []byte(signature))
+}
+
+// RenderUnsubscribe returns the page shown when following the unsubscribe
+// link of a user. Following the link changes nothing, since mail scanners
+// and link prefetchers follow links too; the page asks the user to confirm
+// by posting the link back.
+func RenderUnsubscribe(userID int64, signature string) (string, error) {
+	var buf bytes.Buffer
+	if err := unsubscribeTemplate.Execute(&buf, struct{ Action string }{
+		Action: "?" + unsubscribeQuery(userID, signature),
+	}); err != nil {
+		return "", fmt.Errorf("rendering unsubscribe page: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// UnsubscribeHeaders returns the headers that let mail clients unsubscribe
+// a user with one click (RFC 8058), by posting to their unsubscribe link.
+func UnsubscribeHeaders(unsubscribeURL string) []email.Header {
+	return []email.Header{
+		{Name: "List-Unsubscribe", Value: "<" + unsubscribeURL + ">"},
+		{Name: "List-Unsubscribe-Post", Value: "List-Unsubscribe=One-Click"},
+	}
+}
+
+// UnsubscribeURL returns the signed unsubscribe link of a user, such as
+// "https://wecook.example.com/api/v1/reports/unsubscribe?user=1&signature=...".
+// Links do not expire, so old emails keep working.
+func UnsubscribeURL(endpoint string, secret []byte, userID int64) string {
+	return endpoint + "?" + unsubscribeQuery(userID, Sign(secret, userID))
+}
+
+func unsubscribeQuery(userID int64, signature string) string {
+	query := url.Values{}
+	query.Set(userParam, strconv.FormatInt(userID, 10))
+	query.Set(signatureParam, signature)
+	return query.Encode()
+}
@@ -0,0 +1,228 @@
+package report
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/branding"
+	"github.com/matt-dz/wecook/internal/clock"
+	"github.com/matt-dz/wecook/internal/config"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/email"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func TestVerify(t *testing.T) {
+	secret := []byte("secret")
+	signature := Sign(secret, 42)
+
+	if !Verify(secret, 42, signature) {
+		t.Error("expected signature to verify")
+	}
+	if Verify(secret, 43, signature) {
+		t.Error("expected signature of another user to fail")
+	}
+	if Verify([]byte("other"), 42, signature) {
+		t.Error("expected signature under another secret to fail")
+	}
+}
+
+func TestUnsubscribeURL(t *testing.T) {
+	secret := []byte("secret")
+	link := UnsubscribeURL("https://wecook.example.com/api/v1/reports/unsubscribe", secret, 42)
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Path != "/api/v1/reports/unsubscribe" {
+		t.Errorf("unexpected path %q", u.Path)
+	}
+	if got := u.Query().Get("user"); got != "42" {
+		t.Errorf("expected user 42, got %q", got)
+	}
+	if !Verify(secret, 42, u.Query().Get("signature")) {
+		t.Error("expected link signature to verify")
+	}
+}
+
+func TestRenderUnsubscribe(t *testing.T) {
+	page, err := RenderUnsubscribe(42, "a+b/c")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(page, `<form method="post" action="?signature=a%2Bb%2Fc&amp;user=42">`) {
+		t.Errorf("expected page to post the link back, got %s", page)
+	}
+}
+
+func TestRender(t *testing.T) {
+	body, err := Render(Weekly{
+		InstanceName: "Ada's Kitchen",
+		FirstName:    "Ada",
+		Since:        time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC),
+		Created: []RecipeLink{
+			{Title: "Salt & <Pepper>", URL: "https://wecook.example.com/recipes/1"},
+		},
+		UnsubscribeURL: "https://wecook.example.com/api/v1/reports/unsubscribe?user=1&signature=abc",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"Hi Ada",
+		"week on Ada&#39;s Kitchen since March 2",
+		"Salt &amp; &lt;Pepper&gt;",
+		"https://wecook.example.com/recipes/1",
+		"user=1&amp;signature=abc",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected body to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "New from other cooks") {
+		t.Error("expected empty section to be omitted")
+	}
+}
+
+func TestSendDue(t *testing.T) {
+	// Monday 08:00 in New York, the day after clocks went forward.
+	now := time.Date(2026, time.March, 9, 12, 0, 0, 0, time.UTC)
+	lastSent := now.Add(-8 * 24 * time.Hour)
+	secret := config.AppSecretValue("secret")
+
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockSMTP := email.NewMockSender(ctrl)
+
+	e := &env.Env{
+		Logger:   log.NullLogger(),
+		Database: &database.Database{Querier: mockDB},
+		SMTP:     mockSMTP,
+		Clock:    clock.NewFrozen(now),
+	}
+	e.Config.HostOrigin = "https://wecook.example.com"
+	e.Config.AppSecret.Value = &secret
+
+	mockDB.EXPECT().
+		GetInstanceBranding(gomock.Any(), int32(branding.ID)).
+		Return(database.InstanceBranding{ID: branding.ID, Name: "Ada's Kitchen"}, nil)
+	dueBefore := pgtype.Timestamptz{Time: now, Valid: true}
+	mockDB.EXPECT().
+		GetWeeklyReportRecipients(gomock.Any(), database.GetWeeklyReportRecipientsParams{
+			DueBefore: dueBefore,
+			BatchSize: batchSize,
+		}).
+		Return([]database.GetWeeklyReportRecipientsRow{
+			{
+				ID:                 1,
+				Email:              "ada@example.com",
+				FirstName:          "Ada",
+				WeeklyReportSentAt: pgtype.Timestamptz{Time: lastSent, Valid: true},
+				TimeZone:           "America/New_York",
+			},
+			{ID: 2, Email: "bob@example.com", FirstName: "Bob", TimeZone: "UTC"},
+		}, nil)
+	mockDB.EXPECT().
+		GetWeeklyReportRecipients(gomock.Any(), database.GetWeeklyReportRecipientsParams{
+			DueBefore: dueBefore,
+			After:     2,
+			BatchSize: batchSize,
+		}).
+		Return(nil, nil)
+
+	// Ada has a new recipe since her last report, a day more than a week ago.
+	mockDB.EXPECT().
+		GetUserRecipesCreatedSince(gomock.Any(), database.GetUserRecipesCreatedSinceParams{
+			UserID: pgtype.Int8{Int64: 1, Valid: true},
+			Since:  pgtype.Timestamptz{Time: now.Add(-Period), Valid: true},
+		}).
+		Return([]database.GetUserRecipesCreatedSinceRow{{ID: 10, Title: "Shakshuka"}}, nil)
+	mockDB.EXPECT().
+		GetPublishedRecipesCreatedSince(gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+	mockDB.EXPECT().
+		CreateDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params database.CreateDeliveryParams) (database.Delivery, error) {
+			if !params.Retry {
+				t.Error("expected report to be retried")
+			}
+			return database.Delivery{ID: 5, Kind: params.Kind, Subject: params.Subject,
+				Payload: params.Payload, Retry: params.Retry}, nil
+		})
+	mockSMTP.EXPECT().
+		Send([]string{"ada@example.com"}, "Your week on Ada's Kitchen", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ []string, _, body string, headers ...email.Header) error {
+			if len(headers) != 2 || !strings.HasPrefix(headers[0].Value,
+				"<https://wecook.example.com/api/v1/reports/unsubscribe?") ||
+				headers[1] != (email.Header{Name: "List-Unsubscribe-Post", Value: "List-Unsubscribe=One-Click"}) {
+				t.Errorf("expected one-click unsubscribe headers, got %+v", headers)
+			}
+			if !strings.Contains(body, "https://wecook.example.com/recipes/10") {
+				t.Errorf("expected body to link the new recipe")
+			}
+			// A week before 08:00 EDT is 07:00 EST on March 2.
+			if !strings.Contains(body, "since March 2") {
+				t.Errorf("expected body to date the report in New York time")
+			}
+			return nil
+		})
+	mockDB.EXPECT().
+		MarkDeliverySent(gomock.Any(), database.MarkDeliverySentParams{
+			ID:  5,
+			Now: pgtype.Timestamptz{Time: now, Valid: true},
+		}).
+		Return(nil)
+	mockDB.EXPECT().
+		MarkWeeklyReportSent(gomock.Any(), database.MarkWeeklyReportSentParams{
+			SentAt: pgtype.Timestamptz{Time: now, Valid: true},
+			DueAt:  pgtype.Timestamptz{Time: time.Date(2026, time.March, 16, 12, 0, 0, 0, time.UTC), Valid: true},
+			ID:     1,
+		}).
+		Return(nil)
+
+	// Bob has nothing to report, so no email is sent.
+	mockDB.EXPECT().
+		GetUserRecipesCreatedSince(gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+	mockDB.EXPECT().
+		GetPublishedRecipesCreatedSince(gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+	mockDB.EXPECT().
+		MarkWeeklyReportSent(gomock.Any(), database.MarkWeeklyReportSentParams{
+			SentAt: pgtype.Timestamptz{Time: now, Valid: true},
+			DueAt:  pgtype.Timestamptz{Time: time.Date(2026, time.March, 16, 8, 0, 0, 0, time.UTC), Valid: true},
+			ID:     2,
+		}).
+		Return(nil)
+
+	if err := SendDue(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNextDue(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clocks go forward in London on Sunday March 29, 2026.
+	before := NextDue(time.Date(2026, time.March, 22, 8, 0, 0, 0, time.UTC), london)
+	after := NextDue(before, london)
+
+	if want := time.Date(2026, time.March, 30, 7, 0, 0, 0, time.UTC); !after.Equal(want) {
+		t.Errorf("NextDue() = %v, want %v", after, want)
+	}
+	if got := after.Sub(before); got != Period-time.Hour {
+		t.Errorf("expected the week across the transition to last %v, got %v", Period-time.Hour, got)
+	}
+}
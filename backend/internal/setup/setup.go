@@ -5,13 +5,18 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/matt-dz/wecook/internal/argon2id"
+	"github.com/matt-dz/wecook/internal/clock"
 	"github.com/matt-dz/wecook/internal/config"
 	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/demo"
 	"github.com/matt-dz/wecook/internal/email"
 	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/filecrypt"
 	"github.com/matt-dz/wecook/internal/filestore"
+	"github.com/matt-dz/wecook/internal/idgen"
 )
 
 // SMTP creates a new SMTP sender from environment variables.
@@ -33,7 +38,23 @@ func SMTP(config config.Config) (*email.SMTPSender, error) {
 	return email.NewSMTPSender(emailConfig), nil
 }
 
-func Database(ctx context.Context, config config.Config) (*database.Database, error) {
+// DatabasePool creates a pool of connections to the configured database.
+// Connections are opened lazily.
+func DatabasePool(ctx context.Context, config config.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := databasePoolConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Creating DB connection
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating database pool: %w", err)
+	}
+	return pool, nil
+}
+
+func databasePoolConfig(config config.Config) (*pgxpool.Config, error) {
 	poolConfig, err := pgxpool.ParseConfig("")
 	if err != nil {
 		return nil, fmt.Errorf("configuring database pool: %w", err)
@@ -44,21 +65,69 @@ func Database(ctx context.Context, config config.Config) (*database.Database, er
 	poolConfig.ConnConfig.User = config.Database.User
 	poolConfig.ConnConfig.Password = config.Database.Password
 	poolConfig.ConnConfig.Database = config.Database.Database
+	return poolConfig, nil
+}
 
-	// Creating DB connection
-	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+func Database(ctx context.Context, config config.Config) (*database.Database, error) {
+	pool, err := DatabasePool(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("creating database pool: %w", err)
+		return nil, err
 	}
 
-	db := database.NewDatabase(pool)
+	var conn database.DBTX = pool
+	if config.Database.RowLevelSecurity {
+		conn = database.WithRowLevelSecurity(pool)
+	}
+	db := database.NewDatabase(conn)
 	if err := db.EnsureSchema(ctx); err != nil {
 		return nil, fmt.Errorf("initializing database: %w", err)
 	}
+	if err := db.SetRowLevelSecurity(ctx, config.Database.RowLevelSecurity); err != nil {
+		return nil, fmt.Errorf("configuring row-level security: %w", err)
+	}
 
 	return db, nil
 }
 
+// Demo creates the API sandbox schema, applies the database schema to it,
+// and seeds it with example data. It returns nil if the sandbox is not
+// enabled. The sandbox has its own pool whose connections only see the
+// sandbox schema, so sandbox requests cannot reach the data of the
+// instance.
+func Demo(ctx context.Context, config config.Config) (*demo.Sandbox, error) {
+	if !config.Demo.Enabled {
+		return nil, nil
+	}
+
+	poolConfig, err := databasePoolConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = demo.Schema
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating demo database pool: %w", err)
+	}
+
+	if _, err := pool.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgx.Identifier{demo.Schema}.Sanitize()); err != nil {
+		return nil, fmt.Errorf("creating demo schema: %w", err)
+	}
+	db := database.NewDatabase(pool)
+	if err := db.EnsureSchema(ctx); err != nil {
+		return nil, fmt.Errorf("initializing demo schema: %w", err)
+	}
+	userID, err := demo.Seed(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("seeding demo schema: %w", err)
+	}
+
+	return &demo.Sandbox{
+		Database: db,
+		UserID:   userID,
+		APIKey:   demo.APIKey(config),
+	}, nil
+}
+
 // Admin setups an admin user if one does not exist. Requires env.Database.
 func Admin(ctx context.Context, env *env.Env) error {
 	// Check admin count
@@ -100,9 +169,33 @@ func Admin(ctx context.Context, env *env.Env) error {
 }
 
 func FileStore(config config.Config) (filestore.FileStore, error) {
-	return filestore.New(config.Fileserver.Volume, config.Fileserver.URLPrefix, config.HostOrigin), nil
+	template, err := filestore.ParsePathTemplate(config.Fileserver.PathTemplate)
+	if err != nil {
+		return filestore.FileStore{}, fmt.Errorf("parsing fileserver path template: %w", err)
+	}
+	store := filestore.New(config.Fileserver.Volume, config.Fileserver.URLPrefix, config.HostOrigin).
+		WithPathTemplate(template)
+
+	keyring, err := filecrypt.FromConfig(config)
+	if err != nil {
+		return filestore.FileStore{}, fmt.Errorf("loading file encryption keys: %w", err)
+	}
+	if keyring != nil {
+		store = store.WithEncryption(keyring)
+	}
+	return store, nil
 }
 
 func Preferences(ctx context.Context, env *env.Env, id int32) error {
 	return env.Database.CreatePreferences(ctx, id)
 }
+
+// Clock creates the clock used to timestamp application events.
+func Clock() clock.Clock {
+	return clock.New()
+}
+
+// IDGenerator creates the generator used for application assigned identifiers.
+func IDGenerator() idgen.IDGenerator {
+	return idgen.New()
+}
@@ -188,3 +188,13 @@ func TestAdmin(t *testing.T) {
 		})
 	}
 }
+
+func TestDemo_Disabled(t *testing.T) {
+	sandbox, err := Demo(context.Background(), config.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sandbox != nil {
+		t.Errorf("expected no sandbox, got %+v", sandbox)
+	}
+}
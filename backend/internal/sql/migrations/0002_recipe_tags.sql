@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS recipe_tags (
+  recipe_id bigint NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  tag text NOT NULL CHECK (tag <> ''),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (recipe_id, tag)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_tag_suggestions (
+  recipe_id bigint NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  tag text NOT NULL CHECK (tag <> ''),
+  score int NOT NULL CHECK (score > 0),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (recipe_id, tag)
+);
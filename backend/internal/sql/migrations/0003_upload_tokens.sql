@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS upload_tokens (
+  id text PRIMARY KEY,
+  user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  recipe_id bigint NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+  target text NOT NULL CHECK (target IN ('cover', 'ingredient', 'step')),
+  target_id bigint NOT NULL,
+  expires_at timestamptz NOT NULL,
+  used_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS upload_tokens_expires_at_idx ON upload_tokens (expires_at);
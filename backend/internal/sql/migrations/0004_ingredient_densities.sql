@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS density_versions (
+  id bigserial PRIMARY KEY,
+  note text NOT NULL DEFAULT '',
+  created_by bigint REFERENCES users (id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_densities (
+  version_id bigint NOT NULL REFERENCES density_versions (id) ON DELETE CASCADE,
+  ingredient text NOT NULL CHECK (ingredient <> ''),
+  ml_per_gram double precision NOT NULL CHECK (ml_per_gram > 0),
+  PRIMARY KEY (version_id, ingredient)
+);
+
+-- Initial dataset. Values are millilitres per gram, e.g. 125 g of flour
+-- fills a 236.6 ml cup.
+WITH v AS (
+INSERT INTO density_versions (note)
+    VALUES ('Initial dataset')
+  RETURNING
+    id)
+  INSERT INTO ingredient_densities (version_id, ingredient, ml_per_gram)
+  SELECT
+    v.id,
+    d.ingredient,
+    d.ml_per_gram
+  FROM
+    v,
+    (
+      VALUES ('water', 1.0),
+        ('milk', 0.97),
+        ('heavy cream', 0.99),
+        ('vegetable oil', 1.09),
+        ('olive oil', 1.09),
+        ('butter', 1.04),
+        ('honey', 0.7),
+        ('maple syrup', 0.75),
+        ('all-purpose flour', 1.89),
+        ('flour', 1.89),
+        ('bread flour', 1.85),
+        ('whole wheat flour', 1.97),
+        ('sugar', 1.18),
+        ('granulated sugar', 1.18),
+        ('brown sugar', 1.08),
+        ('powdered sugar', 1.97),
+        ('cocoa powder', 2.78),
+        ('salt', 0.81),
+        ('kosher salt', 1.63),
+        ('baking soda', 1.08),
+        ('baking powder', 1.2),
+        ('rolled oats', 2.63),
+        ('rice', 1.28),
+        ('cornstarch', 1.84),
+        ('grated parmesan', 2.37),
+        ('chocolate chips', 1.39)) AS d (ingredient, ml_per_gram);
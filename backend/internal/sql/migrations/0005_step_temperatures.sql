@@ -0,0 +1,13 @@
+CREATE TYPE temperature_unit AS enum (
+  'C',
+  'F'
+);
+
+ALTER TABLE recipe_steps
+  ADD COLUMN temperature_value real,
+  ADD COLUMN temperature_unit temperature_unit,
+  ADD CONSTRAINT recipe_steps_temperature_check CHECK ((temperature_value IS NULL) = (temperature_unit IS NULL));
+
+-- Unit the user prefers recipe temperatures in. NULL shows them as written.
+ALTER TABLE users
+  ADD COLUMN temperature_unit temperature_unit;
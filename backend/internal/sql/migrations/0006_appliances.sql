@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS appliances (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  name text NOT NULL CHECK (name <> ''),
+  provider text NOT NULL,
+  endpoint text NOT NULL,
+  scopes text[] NOT NULL,
+  -- Sent with every command so the receiver can authenticate WeCook.
+  token text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS appliances_user_id_idx ON appliances (user_id);
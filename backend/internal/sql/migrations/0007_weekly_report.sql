@@ -0,0 +1,4 @@
+-- Opt-in weekly summary email.
+ALTER TABLE users
+  ADD COLUMN weekly_report bool NOT NULL DEFAULT FALSE,
+  ADD COLUMN weekly_report_sent_at timestamptz;
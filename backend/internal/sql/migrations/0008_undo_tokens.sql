@@ -0,0 +1,17 @@
+-- Deleted ingredients, steps, and images that can still be restored.
+-- user_id has no foreign key so the purge job can still find the images
+-- held by tokens of deleted users.
+CREATE TABLE IF NOT EXISTS undo_tokens (
+  token text PRIMARY KEY,
+  user_id bigint NOT NULL,
+  kind text NOT NULL,
+  recipe_id bigint NOT NULL,
+  -- The deleted row, or the ingredient or step that lost its image.
+  snapshot jsonb NOT NULL,
+  -- Image kept in storage until the token expires.
+  image_key text,
+  expires_at timestamptz NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS undo_tokens_expires_at_idx ON undo_tokens (expires_at);
@@ -0,0 +1,78 @@
+-- Row-level security policies on recipe tables. The policies are only
+-- enforced when the backend runs with DATABASE_ROW_LEVEL_SECURITY=true,
+-- which enables row-level security on these tables at startup.
+--
+-- The backend sets wecook.user_id and wecook.admin for the transaction of
+-- every statement run on behalf of a request. Statements without them,
+-- such as those of background jobs, are unrestricted.
+
+CREATE OR REPLACE FUNCTION wecook_actor_id() RETURNS bigint
+LANGUAGE sql STABLE AS $$
+  SELECT nullif(current_setting('wecook.user_id', true), '')::bigint
+$$;
+
+CREATE OR REPLACE FUNCTION wecook_actor_unrestricted() RETURNS boolean
+LANGUAGE sql STABLE AS $$
+  SELECT wecook_actor_id() IS NULL
+    OR coalesce(current_setting('wecook.admin', true), '') = 'true'
+$$;
+
+-- A recipe can be read by its owner and, once published, by anyone.
+CREATE OR REPLACE FUNCTION wecook_can_read_recipe(recipe bigint) RETURNS boolean
+LANGUAGE sql STABLE AS $$
+  SELECT wecook_actor_unrestricted() OR EXISTS (
+    SELECT 1 FROM recipes
+    WHERE id = recipe AND (user_id = wecook_actor_id() OR published)
+  )
+$$;
+
+-- A recipe can only be changed by its owner.
+CREATE OR REPLACE FUNCTION wecook_owns_recipe(recipe bigint) RETURNS boolean
+LANGUAGE sql STABLE AS $$
+  SELECT wecook_actor_unrestricted() OR EXISTS (
+    SELECT 1 FROM recipes
+    WHERE id = recipe AND user_id = wecook_actor_id()
+  )
+$$;
+
+DROP POLICY IF EXISTS recipes_read ON recipes;
+CREATE POLICY recipes_read ON recipes FOR SELECT
+  USING (wecook_actor_unrestricted() OR user_id = wecook_actor_id() OR published);
+
+DROP POLICY IF EXISTS recipes_write ON recipes;
+CREATE POLICY recipes_write ON recipes FOR ALL
+  USING (wecook_actor_unrestricted() OR user_id = wecook_actor_id())
+  WITH CHECK (wecook_actor_unrestricted() OR user_id = wecook_actor_id());
+
+DROP POLICY IF EXISTS recipe_ingredients_read ON recipe_ingredients;
+CREATE POLICY recipe_ingredients_read ON recipe_ingredients FOR SELECT
+  USING (wecook_can_read_recipe(recipe_id));
+
+DROP POLICY IF EXISTS recipe_ingredients_write ON recipe_ingredients;
+CREATE POLICY recipe_ingredients_write ON recipe_ingredients FOR ALL
+  USING (wecook_owns_recipe(recipe_id))
+  WITH CHECK (wecook_owns_recipe(recipe_id));
+
+DROP POLICY IF EXISTS recipe_steps_read ON recipe_steps;
+CREATE POLICY recipe_steps_read ON recipe_steps FOR SELECT
+  USING (wecook_can_read_recipe(recipe_id));
+
+DROP POLICY IF EXISTS recipe_steps_write ON recipe_steps;
+CREATE POLICY recipe_steps_write ON recipe_steps FOR ALL
+  USING (wecook_owns_recipe(recipe_id))
+  WITH CHECK (wecook_owns_recipe(recipe_id));
+
+DROP POLICY IF EXISTS recipe_tags_read ON recipe_tags;
+CREATE POLICY recipe_tags_read ON recipe_tags FOR SELECT
+  USING (wecook_can_read_recipe(recipe_id));
+
+DROP POLICY IF EXISTS recipe_tags_write ON recipe_tags;
+CREATE POLICY recipe_tags_write ON recipe_tags FOR ALL
+  USING (wecook_owns_recipe(recipe_id))
+  WITH CHECK (wecook_owns_recipe(recipe_id));
+
+-- Suggestions are private to the owner even on published recipes.
+DROP POLICY IF EXISTS recipe_tag_suggestions_owner ON recipe_tag_suggestions;
+CREATE POLICY recipe_tag_suggestions_owner ON recipe_tag_suggestions FOR ALL
+  USING (wecook_owns_recipe(recipe_id))
+  WITH CHECK (wecook_owns_recipe(recipe_id));
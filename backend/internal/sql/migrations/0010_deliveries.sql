@@ -0,0 +1,29 @@
+-- Emails and webhooks sent by the backend, kept so operators can see
+-- whether a message arrived and retry the ones that did not.
+CREATE TABLE IF NOT EXISTS deliveries (
+  id bigserial PRIMARY KEY,
+  -- 'email' or 'webhook'.
+  kind text NOT NULL,
+  -- Email addresses, or the ID of the appliance a webhook is sent to.
+  recipient text NOT NULL,
+  -- Email subject, or the appliance action.
+  subject text NOT NULL,
+  -- What is needed to send the delivery again.
+  payload jsonb NOT NULL,
+  -- 'queued', 'sent', 'failed' (awaiting a retry), or 'dead'.
+  status text NOT NULL DEFAULT 'queued',
+  -- Whether failed attempts are retried by the delivery job.
+  retry boolean NOT NULL DEFAULT FALSE,
+  attempts integer NOT NULL DEFAULT 0,
+  last_error text,
+  next_attempt_at timestamptz,
+  sent_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS deliveries_status_idx ON deliveries (status, id);
+
+CREATE INDEX IF NOT EXISTS deliveries_next_attempt_at_idx ON deliveries (next_attempt_at)
+WHERE
+  status = 'failed';
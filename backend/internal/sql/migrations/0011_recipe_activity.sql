@@ -0,0 +1,27 @@
+-- Recipe creation is recorded in the audit trail, which also serves as
+-- each user's activity timeline. Recipes created before then are given a
+-- creation event at the time they were created.
+INSERT INTO audit_events (actor_id, action, target_type, target_id, created_at)
+SELECT
+  r.user_id,
+  'recipe.create',
+  'recipe',
+  r.id,
+  r.created_at
+FROM
+  recipes r
+WHERE
+  NOT EXISTS (
+    SELECT
+      1
+    FROM
+      audit_events a
+    WHERE
+      a.action = 'recipe.create'
+      AND a.target_type = 'recipe'
+      AND a.target_id = r.id);
+
+-- Dry runs are not part of a timeline.
+CREATE INDEX IF NOT EXISTS audit_events_activity_idx ON audit_events (actor_id, id DESC)
+WHERE
+  NOT dry_run;
@@ -0,0 +1,11 @@
+-- Stock ingredient images uploaded by admins, which users can attach to
+-- their ingredients instead of photographing them.
+CREATE TABLE IF NOT EXISTS stock_images (
+  id bigserial PRIMARY KEY,
+  -- Canonical ingredient name, such as 'egg yolk'.
+  ingredient text NOT NULL UNIQUE CHECK (ingredient <> ''),
+  image_key text NOT NULL,
+  created_by bigint REFERENCES users (id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
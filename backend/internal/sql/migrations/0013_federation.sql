@@ -0,0 +1,62 @@
+-- Experimental ActivityPub federation with other wecook instances, used
+-- when FEDERATION_ENABLED=true.
+
+-- Keys users sign their activities with, created when first needed.
+CREATE TABLE IF NOT EXISTS federation_keys (
+  user_id bigint PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+  -- PEM encoded PKCS #8 RSA key.
+  private_key text NOT NULL,
+  -- PEM encoded PKIX RSA key, published on the user's actor.
+  public_key text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- Remote actors following local users. Published recipes are delivered
+-- to their inboxes.
+CREATE TABLE IF NOT EXISTS federation_followers (
+  user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  actor text NOT NULL,
+  inbox text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, actor)
+);
+
+-- Remote actors local users follow.
+CREATE TABLE IF NOT EXISTS federation_following (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  actor text NOT NULL,
+  -- user@host the actor was followed as.
+  handle text NOT NULL,
+  name text NOT NULL,
+  inbox text NOT NULL,
+  -- Set once the remote instance accepts the follow.
+  accepted boolean NOT NULL DEFAULT FALSE,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  UNIQUE (user_id, actor)
+);
+
+CREATE INDEX IF NOT EXISTS federation_following_actor_idx ON federation_following (actor);
+
+-- Recipes published by followed remote actors, shown in the federated
+-- feed. They are removed when no one follows their actor anymore.
+CREATE TABLE IF NOT EXISTS federated_recipes (
+  id bigserial PRIMARY KEY,
+  -- ActivityPub ID of the recipe.
+  object_id text NOT NULL UNIQUE,
+  actor text NOT NULL,
+  author text NOT NULL,
+  -- Page of the recipe on its instance.
+  url text NOT NULL,
+  title text NOT NULL,
+  description text,
+  ingredients text[] NOT NULL DEFAULT '{}',
+  steps text[] NOT NULL DEFAULT '{}',
+  image_url text,
+  published_at timestamptz NOT NULL,
+  received_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS federated_recipes_actor_idx ON federated_recipes (actor);
+
+CREATE INDEX IF NOT EXISTS federated_recipes_published_at_idx ON federated_recipes (published_at DESC, id DESC);
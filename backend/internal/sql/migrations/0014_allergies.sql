@@ -0,0 +1,4 @@
+-- Allergens the user declared, such as 'peanuts'. Recipes containing them
+-- are flagged with warnings and can be filtered out.
+ALTER TABLE users
+  ADD COLUMN allergies text[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,14 @@
+-- IANA time zone of the user, such as 'America/New_York'. Schedules are
+-- stored in UTC and computed in this zone.
+ALTER TABLE users
+  ADD COLUMN time_zone text NOT NULL DEFAULT 'UTC',
+  ADD COLUMN weekly_report_due_at timestamptz;
+
+-- Reports already sent stay on their weekly cadence until the next one
+-- is scheduled in the user's zone.
+UPDATE
+  users
+SET
+  weekly_report_due_at = weekly_report_sent_at + interval '7 days'
+WHERE
+  weekly_report_sent_at IS NOT NULL;
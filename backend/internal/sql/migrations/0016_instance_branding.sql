@@ -0,0 +1,16 @@
+-- Branding of the instance, shown by the frontend and in emails, exports,
+-- and share pages. There is a single row.
+CREATE TABLE IF NOT EXISTS instance_branding (
+  id int PRIMARY KEY,
+  name text NOT NULL DEFAULT 'WeCook' CHECK (name <> ''),
+  logo_key text,
+  -- Lower-case hex color, such as '#e4572e'.
+  accent_color text CHECK (accent_color ~ '^#[0-9a-f]{6}$'),
+  contact_email text,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+INSERT INTO instance_branding (id)
+  VALUES (1)
+ON CONFLICT (id)
+  DO NOTHING;
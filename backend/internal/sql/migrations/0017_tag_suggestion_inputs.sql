@@ -0,0 +1,9 @@
+-- The title and ingredients the tag suggestions of a recipe were last
+-- computed from. The suggestion job only recomputes suggestions when they
+-- change, so suggestions a user ignored or cleared do not come back.
+CREATE TABLE IF NOT EXISTS recipe_tag_suggestion_inputs (
+  recipe_id bigint PRIMARY KEY REFERENCES recipes (id) ON DELETE CASCADE,
+  -- Hex-encoded SHA-256 of the title and ingredients.
+  fingerprint text NOT NULL,
+  suggested_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,11 @@
+-- Statements without an actor were unrestricted, so a statement that
+-- missed its actor could read and change every recipe. They now only
+-- see published recipes and change none. Statements the backend runs on
+-- its own behalf, such as those of background jobs, set wecook.system
+-- instead.
+
+CREATE OR REPLACE FUNCTION wecook_actor_unrestricted() RETURNS boolean
+LANGUAGE sql STABLE AS $$
+  SELECT coalesce(current_setting('wecook.system', true), '') = 'true'
+    OR coalesce(current_setting('wecook.admin', true), '') = 'true'
+$$;
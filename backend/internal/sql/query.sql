@@ -6,7 +6,7 @@ SELECT
     FROM
       information_schema.tables
     WHERE
-      table_schema = 'public'
+      table_schema = current_schema()
       AND table_name = 'users');
 
 -- name: CreateUser :one
@@ -263,14 +263,38 @@ SELECT
   r.id AS recipe_id,
   r.servings,
   u.first_name,
-  u.last_name
+  u.last_name,
+  (
+    SELECT
+      count(*)
+    FROM
+      recipe_tags rt
+    WHERE
+      rt.recipe_id = r.id) AS tag_count,
+  (
+    SELECT
+      count(*)
+    FROM
+      recipe_steps s
+    WHERE
+      s.recipe_id = r.id) AS step_count,
+  (
+    SELECT
+      count(*)
+    FROM
+      recipe_steps s
+    WHERE
+      s.recipe_id = r.id
+      AND coalesce(btrim(s.instruction), '') = '') AS blank_step_count
 FROM
   recipes r
   JOIN users u ON r.user_id = u.id
 WHERE
-  u.id = $1
+  u.id = @user_id
 ORDER BY
-  r.updated_at DESC;
+  r.updated_at DESC,
+  r.id DESC
+LIMIT sqlc.narg ('limit')::int OFFSET @offset;
 
 -- name: GetPublicRecipes :many
 SELECT
@@ -294,16 +318,28 @@ FROM
   JOIN users u ON r.user_id = u.id
 WHERE
   r.published = TRUE
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      recipe_ingredients i
+    WHERE
+      i.recipe_id = r.id
+      AND i.description ~* ANY (@allergen_patterns::text[]))
 ORDER BY
-  r.updated_at DESC;
+  r.updated_at DESC,
+  r.id DESC
+LIMIT @limit OFFSET @offset;
 
 -- name: DeleteRecipe :exec
 DELETE FROM recipes
 WHERE id = $1;
 
--- name: DeleteRecipeIngredient :exec
+-- name: DeleteRecipeIngredient :one
 DELETE FROM recipe_ingredients
-WHERE id = $1;
+WHERE id = $1
+RETURNING
+  *;
 
 -- name: GetRecipeIngredientImageKey :one
 SELECT
@@ -341,9 +377,11 @@ FROM
 WHERE
   id = $1;
 
--- name: DeleteRecipeStep :exec
+-- name: DeleteRecipeStep :one
 DELETE FROM recipe_steps
-WHERE id = $1;
+WHERE id = $1
+RETURNING
+  *;
 
 -- name: UpdateRecipeStep :one
 UPDATE
@@ -363,6 +401,16 @@ SET
     sqlc.narg ('image_key')
   ELSE
     image_key
+  END,
+  temperature_value = CASE WHEN sqlc.narg ('update_temperature')::boolean THEN
+    sqlc.narg ('temperature_value')
+  ELSE
+    temperature_value
+  END,
+  temperature_unit = CASE WHEN sqlc.narg ('update_temperature')::boolean THEN
+    sqlc.narg ('temperature_unit')
+  ELSE
+    temperature_unit
   END
 WHERE
   id = $1
@@ -370,7 +418,9 @@ RETURNING
   id,
   instruction,
   step_number,
-  image_key;
+  image_key,
+  temperature_value,
+  temperature_unit;
 
 -- name: UpdateRecipeIngredient :one
 UPDATE
@@ -566,7 +616,7 @@ WHERE
   id > coalesce(sqlc.narg ('after'), 0)
 ORDER BY
   id
-LIMIT LEAST (100, GREATEST (1, coalesce(sqlc.narg ('limit')::int, 20)));
+LIMIT @limit;
 
 -- name: GetUserById :one
 SELECT
@@ -640,6 +690,57 @@ FROM
 WHERE
   id = $1;
 
+-- name: GetInstanceBranding :one
+SELECT
+  *
+FROM
+  instance_branding
+WHERE
+  id = $1;
+
+-- name: UpdateInstanceBranding :one
+UPDATE
+  instance_branding
+SET
+  name = coalesce(sqlc.narg ('name'), name),
+  accent_color = CASE WHEN @update_accent_color::boolean THEN
+    sqlc.narg ('accent_color')
+  ELSE
+    accent_color
+  END,
+  contact_email = CASE WHEN @update_contact_email::boolean THEN
+    sqlc.narg ('contact_email')
+  ELSE
+    contact_email
+  END,
+  updated_at = now()
+WHERE
+  id = @id
+RETURNING
+  *;
+
+-- name: SetInstanceLogo :one
+WITH old AS (
+  SELECT
+    logo_key
+  FROM
+    instance_branding
+  WHERE
+    id = @id)
+UPDATE
+  instance_branding
+SET
+  logo_key = sqlc.narg ('logo_key'),
+  updated_at = now()
+WHERE
+  id = @id
+RETURNING
+  (
+    SELECT
+      logo_key
+    FROM
+      old)::text AS old_logo_key;
+
 -- name: GetUserRecipeImages :many
 SELECT
   image_key
@@ -672,3 +773,1020 @@ WHERE
 -- name: DeleteUser :execrows
 DELETE FROM users
 WHERE id = $1;
+
+-- name: GetUserRecipeCount :one
+SELECT
+  count(*)
+FROM
+  recipes
+WHERE
+  user_id = $1;
+
+-- name: GetUserRecipeIDSample :many
+SELECT
+  id
+FROM
+  recipes
+WHERE
+  user_id = $1
+ORDER BY
+  id
+LIMIT $2;
+
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (actor_id, action, target_type, target_id, dry_run, metadata)
+  VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING
+  id;
+
+-- name: GetUntaggedRecipes :many
+SELECT
+  r.id,
+  r.user_id,
+  r.title,
+  si.fingerprint
+FROM
+  recipes r
+  LEFT JOIN recipe_tag_suggestion_inputs si ON si.recipe_id = r.id
+WHERE
+  r.id > sqlc.arg ('after')
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      recipe_tags rt
+    WHERE
+      rt.recipe_id = r.id)
+ORDER BY
+  r.id
+LIMIT sqlc.arg ('batch_size');
+
+-- name: GetUserTags :many
+SELECT DISTINCT
+  rt.tag
+FROM
+  recipe_tags rt
+  JOIN recipes r ON r.id = rt.recipe_id
+WHERE
+  r.user_id = $1
+ORDER BY
+  rt.tag;
+
+-- name: MoveUserTag :one
+WITH moved AS (
+  DELETE FROM recipe_tags rt USING recipes r
+  WHERE r.id = rt.recipe_id
+    AND r.user_id = @user_id
+    AND rt.tag = @tag
+  RETURNING
+    rt.recipe_id,
+    rt.created_at
+),
+added AS (
+INSERT INTO recipe_tags (recipe_id, tag, created_at)
+  SELECT
+    recipe_id,
+    @new_tag,
+    created_at
+  FROM
+    moved
+  ON CONFLICT
+    DO NOTHING
+  RETURNING
+    recipe_id)
+SELECT
+  (
+    SELECT
+      count(*)
+    FROM
+      moved)::bigint AS recipes,
+  (
+    SELECT
+      count(*)
+    FROM
+      moved)::bigint - (
+    SELECT
+      count(*)
+    FROM
+      added)::bigint AS merged;
+
+-- name: RenameUserTag :one
+WITH used AS (
+  SELECT
+    1
+  FROM
+    recipe_tags rt
+    JOIN recipes r ON r.id = rt.recipe_id
+  WHERE
+    r.user_id = @user_id
+    AND rt.tag = @new_tag
+),
+renamed AS (
+  UPDATE
+    recipe_tags rt
+  SET
+    tag = @new_tag
+  FROM
+    recipes r
+  WHERE
+    r.id = rt.recipe_id
+    AND r.user_id = @user_id
+    AND rt.tag = @tag
+    AND NOT EXISTS (
+      SELECT
+        1
+      FROM
+        used)
+  RETURNING
+    rt.recipe_id
+)
+SELECT
+  (
+    SELECT
+      count(*)
+    FROM
+      renamed)::bigint AS recipes,
+  EXISTS (
+    SELECT
+      1
+    FROM
+      used) AS in_use;
+
+-- name: DeleteUserTag :one
+WITH deleted AS (
+  DELETE FROM recipe_tags rt USING recipes r
+  WHERE r.id = rt.recipe_id
+    AND r.user_id = @user_id
+    AND rt.tag = @tag
+  RETURNING
+    rt.recipe_id)
+SELECT
+  count(*)
+FROM
+  deleted;
+
+-- name: GetRecipeTags :many
+SELECT
+  tag
+FROM
+  recipe_tags
+WHERE
+  recipe_id = $1
+ORDER BY
+  tag;
+
+-- name: AddRecipeTag :exec
+INSERT INTO recipe_tags (recipe_id, tag)
+  VALUES ($1, $2)
+ON CONFLICT
+  DO NOTHING;
+
+-- name: GetRecipeTagSuggestions :many
+SELECT
+  tag,
+  score
+FROM
+  recipe_tag_suggestions
+WHERE
+  recipe_id = $1
+ORDER BY
+  score DESC,
+  tag;
+
+-- name: CreateRecipeTagSuggestion :exec
+INSERT INTO recipe_tag_suggestions (recipe_id, tag, score)
+  VALUES ($1, $2, $3);
+
+-- name: DeleteRecipeTagSuggestions :exec
+DELETE FROM recipe_tag_suggestions
+WHERE recipe_id = $1;
+
+-- name: SetRecipeTagSuggestionInputs :exec
+INSERT INTO recipe_tag_suggestion_inputs (recipe_id, fingerprint)
+  VALUES ($1, $2)
+ON CONFLICT (recipe_id)
+  DO UPDATE SET
+    fingerprint = EXCLUDED.fingerprint, suggested_at = now();
+
+-- name: GetAllRecipeCoverImageKeys :many
+SELECT
+  id,
+  image_key
+FROM
+  recipes
+WHERE
+  image_key IS NOT NULL
+ORDER BY
+  id;
+
+-- name: GetAllRecipeIngredientImageKeys :many
+SELECT
+  id,
+  image_key
+FROM
+  recipe_ingredients
+WHERE
+  image_key IS NOT NULL
+ORDER BY
+  id;
+
+-- name: GetAllRecipeStepImageKeys :many
+SELECT
+  id,
+  image_key
+FROM
+  recipe_steps
+WHERE
+  image_key IS NOT NULL
+ORDER BY
+  id;
+
+-- name: CreateUploadToken :exec
+INSERT INTO upload_tokens (id, user_id, recipe_id, target, target_id, expires_at)
+  VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetUploadTokenUser :one
+SELECT
+  user_id
+FROM
+  upload_tokens
+WHERE
+  id = $1
+  AND used_at IS NULL
+  AND expires_at > $2;
+
+-- name: ConsumeUploadToken :one
+UPDATE
+  upload_tokens
+SET
+  used_at = $2
+WHERE
+  id = $1
+  AND used_at IS NULL
+  AND expires_at > $2
+RETURNING
+  user_id,
+  recipe_id,
+  target,
+  target_id;
+
+-- name: DeleteExpiredUploadTokens :execrows
+DELETE FROM upload_tokens
+WHERE expires_at <= $1;
+
+-- name: ImportDensities :one
+WITH v AS (
+INSERT INTO density_versions (note, created_by)
+    VALUES (@note, @created_by)
+  RETURNING
+    id, created_at
+), d AS (
+INSERT INTO ingredient_densities (version_id, ingredient, ml_per_gram)
+  SELECT
+    v.id,
+    unnest(@ingredients::text[]),
+    unnest(@ml_per_gram::float8[])
+  FROM
+    v)
+SELECT
+  id,
+  created_at
+FROM
+  v;
+
+-- name: GetDensityVersions :many
+SELECT
+  dv.id,
+  dv.note,
+  dv.created_by,
+  dv.created_at,
+  count(d.ingredient) AS entries
+FROM
+  density_versions dv
+  LEFT JOIN ingredient_densities d ON d.version_id = dv.id
+GROUP BY
+  dv.id
+ORDER BY
+  dv.id DESC;
+
+-- name: GetDensityVersion :one
+SELECT
+  id,
+  note,
+  created_by,
+  created_at
+FROM
+  density_versions
+WHERE
+  id = $1;
+
+-- name: GetLatestDensityVersion :one
+SELECT
+  id,
+  note,
+  created_by,
+  created_at
+FROM
+  density_versions
+ORDER BY
+  id DESC
+LIMIT 1;
+
+-- name: GetDensities :many
+SELECT
+  ingredient,
+  ml_per_gram
+FROM
+  ingredient_densities
+WHERE
+  version_id = $1
+ORDER BY
+  ingredient;
+
+-- name: GetUserTemperatureUnit :one
+SELECT
+  temperature_unit
+FROM
+  users
+WHERE
+  id = $1;
+
+-- name: GetUserPreferences :one
+SELECT
+  temperature_unit,
+  weekly_report,
+  allergies,
+  time_zone
+FROM
+  users
+WHERE
+  id = $1;
+
+-- name: GetUserForExport :one
+SELECT
+  id,
+  email,
+  first_name,
+  last_name,
+  ROLE,
+  temperature_unit,
+  weekly_report,
+  allergies,
+  time_zone,
+  created_at,
+  updated_at
+FROM
+  users
+WHERE
+  id = $1;
+
+-- name: UpdateUserPreferences :one
+UPDATE
+  users
+SET
+  temperature_unit = CASE WHEN sqlc.arg ('update_temperature_unit')::boolean THEN
+    sqlc.narg ('temperature_unit')
+  ELSE
+    temperature_unit
+  END,
+  weekly_report = coalesce(sqlc.narg ('weekly_report'), weekly_report),
+  allergies = coalesce(sqlc.narg ('allergies')::text[], allergies),
+  time_zone = coalesce(sqlc.narg ('time_zone'), time_zone),
+  weekly_report_due_at = coalesce(sqlc.narg ('weekly_report_due_at'), weekly_report_due_at)
+WHERE
+  id = sqlc.arg ('id')
+RETURNING
+  temperature_unit,
+  weekly_report,
+  allergies,
+  time_zone;
+
+-- name: CreateAppliance :one
+INSERT INTO appliances (user_id, name, provider, endpoint, scopes, token)
+  VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING
+  *;
+
+-- name: GetAppliances :many
+SELECT
+  *
+FROM
+  appliances
+WHERE
+  user_id = $1
+ORDER BY
+  created_at ASC,
+  id ASC;
+
+-- name: GetAppliance :one
+SELECT
+  *
+FROM
+  appliances
+WHERE
+  id = $1
+  AND user_id = $2;
+
+-- name: DeleteAppliance :execrows
+DELETE FROM appliances
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: GetCookingStep :one
+SELECT
+  rs.instruction,
+  rs.temperature_value,
+  rs.temperature_unit
+FROM
+  recipe_steps rs
+  JOIN recipes r ON r.id = rs.recipe_id
+WHERE
+  rs.id = @step_id
+  AND r.id = @recipe_id
+  AND (r.user_id = @user_id
+    OR r.published);
+
+-- name: GetWeeklyReportRecipients :many
+SELECT
+  id,
+  email,
+  first_name,
+  weekly_report_sent_at,
+  time_zone
+FROM
+  users
+WHERE
+  weekly_report
+  AND (weekly_report_due_at IS NULL
+    OR weekly_report_due_at <= @due_before)
+  AND id > @after
+ORDER BY
+  id ASC
+LIMIT @batch_size;
+
+-- name: GetUserRecipesCreatedSince :many
+SELECT
+  id,
+  title
+FROM
+  recipes
+WHERE
+  user_id = @user_id
+  AND created_at >= @since
+ORDER BY
+  created_at ASC;
+
+-- name: GetPublishedRecipesCreatedSince :many
+SELECT
+  r.id,
+  r.title,
+  u.first_name,
+  u.last_name
+FROM
+  recipes r
+  JOIN users u ON r.user_id = u.id
+WHERE
+  r.published
+  AND r.user_id <> @user_id
+  AND r.created_at >= @since
+ORDER BY
+  r.created_at DESC
+LIMIT @max_recipes;
+
+-- name: MarkWeeklyReportSent :exec
+UPDATE
+  users
+SET
+  weekly_report_sent_at = @sent_at,
+  weekly_report_due_at = @due_at
+WHERE
+  id = @id;
+
+-- name: UnsubscribeWeeklyReport :execrows
+UPDATE
+  users
+SET
+  weekly_report = FALSE
+WHERE
+  id = $1;
+
+-- name: CreateUndoToken :exec
+INSERT INTO undo_tokens (token, user_id, kind, recipe_id, snapshot, image_key, expires_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ConsumeUndoToken :one
+DELETE FROM undo_tokens
+WHERE token = $1
+  AND user_id = $2
+  AND expires_at > @now
+RETURNING
+  *;
+
+-- name: DeleteUndoToken :execrows
+DELETE FROM undo_tokens
+WHERE token = $1;
+
+-- name: GetAllUndoTokenImageKeys :many
+SELECT
+  token,
+  kind,
+  image_key
+FROM
+  undo_tokens
+WHERE
+  image_key IS NOT NULL
+ORDER BY
+  token;
+
+-- name: UpdateUndoTokenImage :exec
+UPDATE
+  undo_tokens
+SET
+  image_key = $1
+WHERE
+  token = $2;
+
+-- name: GetExpiredUndoTokens :many
+SELECT
+  token,
+  image_key
+FROM
+  undo_tokens
+WHERE
+  expires_at <= @now
+ORDER BY
+  expires_at
+LIMIT @batch_size;
+
+-- name: RestoreRecipeIngredient :exec
+INSERT INTO recipe_ingredients (id, recipe_id, description, image_key, created_at)
+  VALUES ($1, $2, $3, $4, $5);
+
+-- name: RestoreRecipeStep :exec
+INSERT INTO recipe_steps (id, recipe_id, step_number, instruction, image_key, temperature_value,
+  temperature_unit, created_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: RestoreRecipeCoverImage :execrows
+UPDATE
+  recipes
+SET
+  image_key = $1
+WHERE
+  id = $2
+  AND image_key IS NULL;
+
+-- name: RestoreRecipeIngredientImage :execrows
+UPDATE
+  recipe_ingredients
+SET
+  image_key = $1
+WHERE
+  id = $2
+  AND recipe_id = $3
+  AND image_key IS NULL;
+
+-- name: RestoreRecipeStepImage :execrows
+UPDATE
+  recipe_steps
+SET
+  image_key = $1
+WHERE
+  id = $2
+  AND recipe_id = $3
+  AND image_key IS NULL;
+
+-- name: CreateDelivery :one
+INSERT INTO deliveries (kind, recipient, subject, payload, retry, created_at, updated_at)
+  VALUES (@kind, @recipient, @subject, @payload, @retry, @now, @now)
+RETURNING
+  *;
+
+-- name: MarkDeliverySent :exec
+UPDATE
+  deliveries
+SET
+  status = 'sent',
+  attempts = attempts + 1,
+  last_error = NULL,
+  next_attempt_at = NULL,
+  sent_at = @now,
+  updated_at = @now
+WHERE
+  id = $1;
+
+-- name: MarkDeliveryFailed :exec
+UPDATE
+  deliveries
+SET
+  status = @status,
+  attempts = attempts + 1,
+  last_error = @last_error,
+  next_attempt_at = @next_attempt_at,
+  updated_at = @now
+WHERE
+  id = $1;
+
+-- name: GetDelivery :one
+SELECT
+  *
+FROM
+  deliveries
+WHERE
+  id = $1;
+
+-- name: GetDeliveries :many
+SELECT
+  *
+FROM
+  deliveries
+WHERE
+  id < coalesce(sqlc.narg ('before'), 9223372036854775807)
+  AND (sqlc.narg ('status')::text IS NULL
+    OR status = sqlc.narg ('status'))
+  AND (sqlc.narg ('kind')::text IS NULL
+    OR kind = sqlc.narg ('kind'))
+ORDER BY
+  id DESC
+LIMIT @limit;
+
+-- name: GetDeliveryStats :many
+SELECT
+  kind,
+  count(*) FILTER (WHERE status = 'queued') AS queued,
+  count(*) FILTER (WHERE status = 'sent') AS sent,
+  count(*) FILTER (WHERE status = 'failed') AS failed,
+  count(*) FILTER (WHERE status = 'dead') AS dead,
+  count(*) FILTER (WHERE attempts > 1) AS retried
+FROM
+  deliveries
+GROUP BY
+  kind
+ORDER BY
+  kind;
+
+-- name: ClaimDelivery :one
+UPDATE
+  deliveries
+SET
+  status = 'queued',
+  updated_at = @now
+WHERE
+  id = $1
+  AND status IN ('failed', 'dead')
+RETURNING
+  *;
+
+-- name: ClaimDueDeliveries :many
+UPDATE
+  deliveries
+SET
+  status = 'queued',
+  updated_at = @now
+WHERE
+  id IN (
+    SELECT
+      id
+    FROM
+      deliveries
+    WHERE
+      status = 'failed'
+      AND retry
+      AND next_attempt_at <= @now
+    ORDER BY
+      next_attempt_at
+    LIMIT @batch_size
+    FOR UPDATE
+      SKIP LOCKED)
+RETURNING
+  *;
+
+-- name: DeleteSentDeliveriesBefore :exec
+DELETE FROM deliveries
+WHERE status = 'sent'
+  AND sent_at < @before;
+
+-- name: GetApplianceByID :one
+SELECT
+  *
+FROM
+  appliances
+WHERE
+  id = $1;
+
+-- name: GetUserActivity :many
+SELECT
+  a.id,
+  a.action,
+  a.target_type,
+  a.target_id,
+  a.metadata,
+  a.created_at,
+  r.title AS recipe_title
+FROM
+  audit_events a
+  LEFT JOIN recipes r ON a.target_type = 'recipe'
+    AND r.id = a.target_id
+WHERE
+  a.actor_id = @actor_id
+  AND NOT a.dry_run
+  AND a.id < coalesce(sqlc.narg ('before'), 9223372036854775807)
+ORDER BY
+  a.id DESC
+LIMIT @limit;
+
+-- name: GetUserCount :one
+SELECT
+  count(*)
+FROM
+  users;
+
+-- name: GetPublicRecipeCount :one
+SELECT
+  count(*)
+FROM
+  recipes r
+WHERE
+  r.published = TRUE
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      recipe_ingredients i
+    WHERE
+      i.recipe_id = r.id
+      AND i.description ~* ANY (@allergen_patterns::text[]));
+
+-- name: GetDeliveryCount :one
+SELECT
+  count(*)
+FROM
+  deliveries
+WHERE (sqlc.narg ('status')::text IS NULL
+  OR status = sqlc.narg ('status'))
+AND (sqlc.narg ('kind')::text IS NULL
+  OR kind = sqlc.narg ('kind'));
+
+-- name: GetUserActivityCount :one
+SELECT
+  count(*)
+FROM
+  audit_events
+WHERE
+  actor_id = $1
+  AND NOT dry_run;
+
+-- name: UpsertStockImage :one
+WITH old AS (
+  SELECT
+    image_key
+  FROM
+    stock_images
+  WHERE
+    ingredient = @ingredient)
+INSERT INTO stock_images (ingredient, image_key, created_by)
+  VALUES (@ingredient, @image_key, @created_by)
+ON CONFLICT (ingredient)
+  DO UPDATE SET
+    image_key = EXCLUDED.image_key,
+    created_by = EXCLUDED.created_by,
+    updated_at = now()
+  RETURNING
+    id,
+    ingredient,
+    image_key,
+    created_by,
+    created_at,
+    updated_at,
+    (
+      SELECT
+        image_key
+      FROM
+        old)::text AS old_image_key;
+
+-- name: GetStockImages :many
+SELECT
+  *
+FROM
+  stock_images
+ORDER BY
+  ingredient;
+
+-- name: GetStockImage :one
+SELECT
+  *
+FROM
+  stock_images
+WHERE
+  id = $1;
+
+-- name: MatchStockImage :one
+SELECT
+  *
+FROM
+  stock_images
+WHERE
+  ingredient = ANY (@candidates::text[])
+ORDER BY
+  array_position(@candidates::text[], ingredient)
+LIMIT 1;
+
+-- name: GetAllStockImageKeys :many
+SELECT
+  id,
+  image_key
+FROM
+  stock_images
+ORDER BY
+  id;
+
+-- name: UpdateStockImageKey :exec
+UPDATE
+  stock_images
+SET
+  image_key = $1
+WHERE
+  id = $2;
+
+-- name: DeleteStockImage :one
+DELETE FROM stock_images
+WHERE id = $1
+RETURNING
+  image_key;
+
+-- name: CreateFederationKey :one
+INSERT INTO federation_keys (user_id, private_key, public_key)
+  VALUES (@user_id, @private_key, @public_key)
+ON CONFLICT (user_id)
+  DO UPDATE SET
+    user_id = EXCLUDED.user_id
+  RETURNING
+    *;
+
+-- name: GetFederationKey :one
+SELECT
+  *
+FROM
+  federation_keys
+WHERE
+  user_id = $1;
+
+-- name: GetFederationActor :one
+SELECT
+  u.id,
+  u.first_name,
+  u.last_name,
+  u.created_at
+FROM
+  users u
+WHERE
+  u.id = $1
+  AND EXISTS (
+    SELECT
+      1
+    FROM
+      recipes r
+    WHERE
+      r.user_id = u.id
+      AND r.published = TRUE);
+
+-- name: GetPublishedRecipesByOwner :many
+SELECT
+  *
+FROM
+  recipes
+WHERE
+  user_id = @user_id
+  AND published = TRUE
+ORDER BY
+  updated_at DESC,
+  id DESC
+LIMIT @limit;
+
+-- name: AddFederationFollower :exec
+INSERT INTO federation_followers (user_id, actor, inbox)
+  VALUES (@user_id, @actor, @inbox)
+ON CONFLICT (user_id, actor)
+  DO UPDATE SET
+    inbox = EXCLUDED.inbox;
+
+-- name: DeleteFederationFollower :execrows
+DELETE FROM federation_followers
+WHERE user_id = @user_id
+  AND actor = @actor;
+
+-- name: GetFederationFollowers :many
+SELECT
+  *
+FROM
+  federation_followers
+WHERE
+  user_id = $1
+ORDER BY
+  created_at,
+  actor;
+
+-- name: CreateFederationFollowing :one
+INSERT INTO federation_following (user_id, actor, handle, name, inbox)
+  VALUES (@user_id, @actor, @handle, @name, @inbox)
+ON CONFLICT (user_id, actor)
+  DO UPDATE SET
+    handle = EXCLUDED.handle,
+    name = EXCLUDED.name,
+    inbox = EXCLUDED.inbox
+  RETURNING
+    *;
+
+-- name: GetFederationFollowing :many
+SELECT
+  *
+FROM
+  federation_following
+WHERE
+  user_id = $1
+ORDER BY
+  handle;
+
+-- name: DeleteFederationFollowing :one
+DELETE FROM federation_following
+WHERE id = @id
+  AND user_id = @user_id
+RETURNING
+  *;
+
+-- name: AcceptFederationFollowing :execrows
+UPDATE
+  federation_following
+SET
+  accepted = TRUE
+WHERE
+  user_id = @user_id
+  AND actor = @actor;
+
+-- name: IsFederationActorFollowed :one
+SELECT
+  EXISTS (
+    SELECT
+      1
+    FROM
+      federation_following
+    WHERE
+      actor = $1
+      AND accepted = TRUE);
+
+-- name: UpsertFederatedRecipe :execrows
+INSERT INTO federated_recipes (object_id, actor, author, url, title, description, ingredients, steps, image_url, published_at)
+  VALUES (@object_id, @actor, @author, @url, @title, @description, @ingredients::text[], @steps::text[], @image_url, @published_at)
+ON CONFLICT (object_id)
+  DO UPDATE SET
+    author = EXCLUDED.author,
+    url = EXCLUDED.url,
+    title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    ingredients = EXCLUDED.ingredients,
+    steps = EXCLUDED.steps,
+    image_url = EXCLUDED.image_url,
+    published_at = EXCLUDED.published_at,
+    received_at = now()
+  WHERE
+    federated_recipes.actor = EXCLUDED.actor;
+
+-- name: DeleteFederatedRecipe :execrows
+DELETE FROM federated_recipes
+WHERE object_id = @object_id
+  AND actor = @actor;
+
+-- name: DeleteUnfollowedFederatedRecipes :exec
+DELETE FROM federated_recipes
+WHERE actor = $1
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      federation_following
+    WHERE
+      federation_following.actor = federated_recipes.actor);
+
+-- name: GetFederatedRecipes :many
+SELECT
+  *
+FROM
+  federated_recipes
+ORDER BY
+  published_at DESC,
+  id DESC
+LIMIT @limit OFFSET @offset;
+
+-- name: GetFederatedRecipeCount :one
+SELECT
+  count(*)
+FROM
+  federated_recipes;
+
+-- name: GetUserAllergies :one
+SELECT
+  allergies
+FROM
+  users
+WHERE
+  id = $1;
@@ -1,11 +1,50 @@
 // Package sql includes the database schema
 package sql
 
-import _ "embed"
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
 
 //go:embed schema.sql
 var schema string
 
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migration is a schema change applied on top of the base schema.
+type Migration struct {
+	// Version is the migration file name without its extension.
+	Version string
+	SQL     string
+}
+
 func Schema() string {
 	return schema
 }
+
+// Migrations returns the embedded schema migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("listing migrations: %w", err)
+	}
+	sort.Strings(names)
+
+	out := make([]Migration, 0, len(names))
+	for _, name := range names {
+		contents, err := migrations.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %q: %w", name, err)
+		}
+		out = append(out, Migration{
+			Version: strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".sql"),
+			SQL:     string(contents),
+		})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,67 @@
+// Package stockimage matches ingredients to a library of stock images, so
+// users can attach a picture of salt without photographing a salt shaker.
+//
+// Stock images are stored under canonical ingredient names: lowercase,
+// with whitespace collapsed and the last word singular, as in "egg yolk".
+// An ingredient description is matched by trying its trailing words from
+// the most to the least specific, so "2 large eggs, beaten" matches
+// "large egg" before "egg".
+package stockimage
+
+import (
+	"strings"
+
+	"github.com/matt-dz/wecook/internal/ingredient"
+	"github.com/matt-dz/wecook/internal/mealprep"
+	"github.com/matt-dz/wecook/internal/units"
+)
+
+// Canonical returns the canonical form of an ingredient name.
+func Canonical(name string) string {
+	name = units.NormalizeIngredient(name)
+	i := strings.LastIndexByte(name, ' ')
+	return name[:i+1] + ingredient.Singular(name[i+1:])
+}
+
+// Candidates returns the canonical names an ingredient description may
+// be stored under, most specific first. The quantity, unit, notes after a
+// comma, and parenthesized text are ignored. Descriptions without a name
+// have no candidates.
+func Candidates(description string) []string {
+	_, rest, _ := mealprep.ParseQuantity(stripParens(description))
+	name := units.NormalizeIngredient(ingredient.ParseItem(nil, rest).Name)
+	fields := strings.Fields(strings.TrimPrefix(name, "of "))
+
+	candidates := make([]string, 0, 2*len(fields))
+	seen := make(map[string]bool, 2*len(fields))
+	for i := range fields {
+		suffix := strings.Join(fields[i:], " ")
+		// Try the name as written first, for names that only look
+		// plural, such as "molasses".
+		for _, candidate := range []string{suffix, Canonical(suffix)} {
+			if !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	return candidates
+}
+
+// stripParens removes parenthesized text, as in "1 (14 oz) can tomatoes".
+func stripParens(s string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+			b.WriteByte(' ')
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
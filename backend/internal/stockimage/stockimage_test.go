@@ -0,0 +1,45 @@
+package stockimage
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := map[string]string{
+		"Salt":             "salt",
+		"  Egg   Yolks ":   "egg yolk",
+		"cherry tomatoes":  "cherry tomato",
+		"bay leaves":       "bay leaf",
+		"extra virgin oil": "extra virgin oil",
+		"couscous":         "couscous",
+		"":                 "",
+	}
+	for name, want := range tests {
+		if got := Canonical(name); got != want {
+			t.Errorf("Canonical(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		description string
+		want        []string
+	}{
+		{"2 large eggs, beaten", []string{"large eggs", "large egg", "eggs", "egg"}},
+		{"1½ cups of flour", []string{"flour"}},
+		{"1 (14 oz) can Tomatoes", []string{"can tomatoes", "can tomato", "tomatoes", "tomato"}},
+		{"salt, to taste", []string{"salt"}},
+		{"molasses", []string{"molasses", "molasse"}},
+		{"3", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := Candidates(tt.description); !slices.Equal(got, tt.want) {
+				t.Errorf("Candidates(%q) = %q, want %q", tt.description, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package tagging
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// AllergenVocabulary maps an allergen to the ingredient keywords that
+// indicate it. Matching is by keyword, so it errs towards warning: coconut
+// milk is reported as milk.
+var AllergenVocabulary = Vocabulary{
+	"peanuts": {"peanut", "groundnut"},
+	"tree_nuts": {
+		"almond", "walnut", "cashew", "pecan", "pistachio", "hazelnut", "macadamia",
+		"brazil nut", "pine nut", "chestnut", "marzipan", "praline",
+	},
+	"milk": {
+		"milk", "buttermilk", "butter", "cream", "cheese", "yogurt", "yoghurt", "ghee", "whey",
+		"casein", "parmesan", "mozzarella", "cheddar", "ricotta", "mascarpone", "feta",
+	},
+	"eggs": {"egg", "mayonnaise", "mayo", "meringue", "aioli"},
+	"fish": {
+		"fish", "salmon", "tuna", "cod", "anchovy", "anchovies", "sardine", "trout", "halibut",
+		"tilapia", "mackerel", "haddock",
+	},
+	"shellfish": {
+		"shrimp", "prawn", "crab", "lobster", "scallop", "mussel", "clam", "oyster", "crayfish",
+		"langoustine",
+	},
+	"soy": {"soy", "soya", "tofu", "tempeh", "edamame", "miso", "tamari"},
+	"wheat": {
+		"wheat", "flour", "bread", "breadcrumb", "panko", "pasta", "spaghetti", "couscous", "semolina",
+		"bulgur", "seitan", "spelt",
+	},
+	"sesame": {"sesame", "tahini"},
+}
+
+// AllergenMatch is an allergen found in a recipe and the indexes of the
+// ingredients that contain it.
+type AllergenMatch struct {
+	Allergen    string
+	Ingredients []int
+}
+
+// IsAllergen reports whether allergen is in the allergen vocabulary.
+func IsAllergen(allergen string) bool {
+	_, found := AllergenVocabulary[allergen]
+	return found
+}
+
+// AllergenLabel returns the allergen as it reads in a sentence, such as
+// "tree nuts".
+func AllergenLabel(allergen string) string {
+	return strings.ReplaceAll(allergen, "_", " ")
+}
+
+// DetectAllergens returns the allergens among allergens that appear in
+// the ingredient descriptions, ordered by allergen. Unknown allergens are
+// ignored.
+func DetectAllergens(ingredients []string, allergens []string) []AllergenMatch {
+	ingredientWords := make([][]string, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		ingredientWords = append(ingredientWords, words(ingredient))
+	}
+
+	var matches []AllergenMatch
+	for _, allergen := range allergens {
+		keywords, found := AllergenVocabulary[allergen]
+		if !found || slices.ContainsFunc(matches, func(m AllergenMatch) bool { return m.Allergen == allergen }) {
+			continue
+		}
+		var indexes []int
+		for idx, ingredient := range ingredientWords {
+			for _, keyword := range keywords {
+				if containsPhrase(ingredient, words(keyword)) {
+					indexes = append(indexes, idx)
+					break
+				}
+			}
+		}
+		if len(indexes) > 0 {
+			matches = append(matches, AllergenMatch{Allergen: allergen, Ingredients: indexes})
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b AllergenMatch) int {
+		return strings.Compare(a.Allergen, b.Allergen)
+	})
+	return matches
+}
+
+// AllergenPatterns returns case-insensitive PostgreSQL regular expressions
+// that match an ingredient description the way DetectAllergens does, for
+// filtering recipes in queries. Unknown allergens are ignored.
+func AllergenPatterns(allergens []string) []string {
+	var patterns []string
+	for _, allergen := range allergens {
+		for _, keyword := range AllergenVocabulary[allergen] {
+			parts := words(keyword)
+			for i, part := range parts {
+				parts[i] = regexp.QuoteMeta(part) + "s?"
+			}
+			pattern := `\m` + strings.Join(parts, `[^[:alnum:]]+`) + `\M`
+			if !slices.Contains(patterns, pattern) {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns
+}
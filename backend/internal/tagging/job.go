@@ -0,0 +1,135 @@
+package tagging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+)
+
+const (
+	// DefaultInterval is how often suggestions are refreshed.
+	DefaultInterval = time.Hour
+	batchSize       = 100
+)
+
+// RunSuggestionJob refreshes tag suggestions for untagged recipes every
+// interval until ctx is cancelled.
+func RunSuggestionJob(ctx context.Context, env *env.Env, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		env.Logger.DebugContext(ctx, "refreshing tag suggestions")
+		if err := SuggestUntagged(ctx, env); err != nil {
+			env.Logger.ErrorContext(ctx, "failed to refresh tag suggestions", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SuggestUntagged replaces the stored tag suggestions of every recipe
+// without tags whose title or ingredients changed since its suggestions
+// were last computed. Unchanged recipes keep their suggestions, so ones
+// the user ignored or cleared are not suggested again. Each user's
+// existing tags extend the default vocabulary.
+func SuggestUntagged(ctx context.Context, env *env.Env) error {
+	vocabularies := make(map[int64]Vocabulary)
+
+	var after int64
+	for {
+		recipes, err := env.Database.GetUntaggedRecipes(ctx, database.GetUntaggedRecipesParams{
+			After:     after,
+			BatchSize: batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("getting untagged recipes: %w", err)
+		}
+		if len(recipes) == 0 {
+			return nil
+		}
+
+		for _, recipe := range recipes {
+			after = recipe.ID
+
+			vocabulary, found := vocabularies[recipe.UserID.Int64]
+			if !found {
+				tags, err := env.Database.GetUserTags(ctx, recipe.UserID)
+				if err != nil {
+					return fmt.Errorf("getting tags of user %d: %w", recipe.UserID.Int64, err)
+				}
+				vocabulary = DefaultVocabulary.WithTags(tags)
+				vocabularies[recipe.UserID.Int64] = vocabulary
+			}
+
+			if err := suggestRecipe(ctx, env, recipe, vocabulary); err != nil {
+				return fmt.Errorf("suggesting tags for recipe %d: %w", recipe.ID, err)
+			}
+		}
+	}
+}
+
+func suggestRecipe(ctx context.Context, env *env.Env, recipe database.GetUntaggedRecipesRow,
+	vocabulary Vocabulary,
+) error {
+	ingredients, err := env.Database.GetRecipeIngredients(ctx, recipe.ID)
+	if err != nil {
+		return fmt.Errorf("getting ingredients: %w", err)
+	}
+	descriptions := make([]string, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		if ingredient.Description.Valid {
+			descriptions = append(descriptions, ingredient.Description.String)
+		}
+	}
+
+	fingerprint := inputFingerprint(recipe.Title, descriptions)
+	if recipe.Fingerprint.Valid && recipe.Fingerprint.String == fingerprint {
+		return nil
+	}
+
+	suggestions := Suggest(recipe.Title, descriptions, vocabulary)
+	return env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		if err := tx.DeleteRecipeTagSuggestions(ctx, recipe.ID); err != nil {
+			return fmt.Errorf("clearing suggestions: %w", err)
+		}
+		for _, suggestion := range suggestions {
+			if err := tx.CreateRecipeTagSuggestion(ctx, database.CreateRecipeTagSuggestionParams{
+				RecipeID: recipe.ID,
+				Tag:      suggestion.Tag,
+				Score:    int32(suggestion.Score),
+			}); err != nil {
+				return fmt.Errorf("storing suggestion %q: %w", suggestion.Tag, err)
+			}
+		}
+		if err := tx.SetRecipeTagSuggestionInputs(ctx, database.SetRecipeTagSuggestionInputsParams{
+			RecipeID:    recipe.ID,
+			Fingerprint: fingerprint,
+		}); err != nil {
+			return fmt.Errorf("storing suggestion inputs: %w", err)
+		}
+		return nil
+	})
+}
+
+// inputFingerprint identifies the title and ingredients suggestions are
+// computed from.
+func inputFingerprint(title string, ingredients []string) string {
+	hash := sha256.New()
+	hash.Write([]byte(title))
+	for _, ingredient := range ingredients {
+		hash.Write([]byte{0})
+		hash.Write([]byte(ingredient))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
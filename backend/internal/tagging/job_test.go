@@ -0,0 +1,78 @@
+package tagging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+func TestSuggestUntagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	e := &env.Env{Logger: log.NullLogger(), Database: mockDB}
+
+	user := pgtype.Int8{Int64: 42, Valid: true}
+	ingredients := []database.RecipeIngredient{
+		{Description: pgtype.Text{String: "2 chicken breasts", Valid: true}},
+	}
+	unchanged := inputFingerprint("Chicken Soup", []string{"2 chicken breasts"})
+
+	mockDB.EXPECT().
+		GetUntaggedRecipes(gomock.Any(), database.GetUntaggedRecipesParams{After: 0, BatchSize: batchSize}).
+		Return([]database.GetUntaggedRecipesRow{
+			// Suggested before and unchanged since.
+			{ID: 1, UserID: user, Title: "Chicken Soup", Fingerprint: pgtype.Text{String: unchanged, Valid: true}},
+			// Renamed since suggested.
+			{ID: 2, UserID: user, Title: "Chicken Stew", Fingerprint: pgtype.Text{String: unchanged, Valid: true}},
+		}, nil)
+	mockDB.EXPECT().
+		GetUntaggedRecipes(gomock.Any(), database.GetUntaggedRecipesParams{After: 2, BatchSize: batchSize}).
+		Return(nil, nil)
+	mockDB.EXPECT().GetUserTags(gomock.Any(), user).Return(nil, nil)
+	mockDB.EXPECT().GetRecipeIngredients(gomock.Any(), int64(1)).Return(ingredients, nil)
+	mockDB.EXPECT().GetRecipeIngredients(gomock.Any(), int64(2)).Return(ingredients, nil)
+
+	// Only the renamed recipe gets new suggestions.
+	mockDB.EXPECT().DeleteRecipeTagSuggestions(gomock.Any(), int64(2)).Return(nil)
+	mockDB.EXPECT().
+		CreateRecipeTagSuggestion(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg database.CreateRecipeTagSuggestionParams) error {
+			if arg.RecipeID != 2 {
+				t.Errorf("expected suggestion for recipe 2, got %+v", arg)
+			}
+			return nil
+		}).
+		AnyTimes()
+	mockDB.EXPECT().
+		SetRecipeTagSuggestionInputs(gomock.Any(), database.SetRecipeTagSuggestionInputsParams{
+			RecipeID:    2,
+			Fingerprint: inputFingerprint("Chicken Stew", []string{"2 chicken breasts"}),
+		}).
+		Return(nil)
+
+	if err := SuggestUntagged(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestInputFingerprint(t *testing.T) {
+	base := inputFingerprint("Soup", []string{"1 onion", "2 carrots"})
+	if inputFingerprint("Soup", []string{"1 onion", "2 carrots"}) != base {
+		t.Error("expected fingerprint to be stable")
+	}
+	for _, other := range []string{
+		inputFingerprint("Stew", []string{"1 onion", "2 carrots"}),
+		inputFingerprint("Soup", []string{"1 onion"}),
+		inputFingerprint("Soup", []string{"1 onion2 carrots"}),
+	} {
+		if other == base {
+			t.Error("expected fingerprint to change with the inputs")
+		}
+	}
+}
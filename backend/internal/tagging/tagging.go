@@ -0,0 +1,128 @@
+// Package tagging suggests recipe tags and detects allergens from recipe
+// keywords.
+package tagging
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	titleWeight      = 2
+	ingredientWeight = 1
+)
+
+// Vocabulary maps a tag to the keywords that indicate it.
+type Vocabulary map[string][]string
+
+// DefaultVocabulary is the built-in tag vocabulary available to every user.
+var DefaultVocabulary = Vocabulary{
+	"breakfast":  {"breakfast", "pancake", "waffle", "omelet", "omelette", "granola", "oatmeal", "porridge"},
+	"dessert":    {"dessert", "cake", "cookie", "brownie", "pie", "tart", "pudding", "ice cream", "custard"},
+	"baking":     {"bread", "muffin", "scone", "yeast", "sourdough", "baking powder", "baking soda"},
+	"soup":       {"soup", "stew", "chowder", "broth", "bisque"},
+	"salad":      {"salad", "vinaigrette", "slaw"},
+	"pasta":      {"pasta", "spaghetti", "penne", "linguine", "fettuccine", "macaroni", "lasagna", "noodle"},
+	"chicken":    {"chicken"},
+	"beef":       {"beef", "steak", "brisket", "ground beef"},
+	"pork":       {"pork", "bacon", "ham", "sausage", "prosciutto"},
+	"seafood":    {"fish", "salmon", "tuna", "shrimp", "prawn", "cod", "crab", "lobster", "scallop", "mussel"},
+	"vegetarian": {"vegetarian", "tofu", "tempeh", "chickpea", "lentil"},
+	"grill":      {"grill", "grilled", "barbecue", "bbq", "kebab", "skewer"},
+	"drink":      {"smoothie", "cocktail", "lemonade", "latte", "tea"},
+}
+
+// Suggestion is a suggested tag and the strength of the match.
+type Suggestion struct {
+	Tag   string
+	Score int
+}
+
+// NormalizeTag trims and lowercases a tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// Suggest scores every vocabulary tag against the recipe title and
+// ingredient descriptions. Title matches weigh more than ingredient
+// matches. Suggestions are ordered by descending score, then tag.
+func Suggest(title string, ingredients []string, vocabulary Vocabulary) []Suggestion {
+	titleWords := words(title)
+	ingredientWords := make([][]string, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		ingredientWords = append(ingredientWords, words(ingredient))
+	}
+
+	var suggestions []Suggestion
+	for tag, keywords := range vocabulary {
+		score := 0
+		for _, keyword := range keywords {
+			keyword := words(keyword)
+			if containsPhrase(titleWords, keyword) {
+				score += titleWeight
+			}
+			for _, ingredient := range ingredientWords {
+				if containsPhrase(ingredient, keyword) {
+					score += ingredientWeight
+				}
+			}
+		}
+		if score > 0 {
+			suggestions = append(suggestions, Suggestion{Tag: tag, Score: score})
+		}
+	}
+
+	slices.SortFunc(suggestions, func(a, b Suggestion) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		return strings.Compare(a.Tag, b.Tag)
+	})
+
+	return suggestions
+}
+
+// WithTags returns a copy of the vocabulary extended with tags the user
+// already uses. Each tag is its own keyword.
+func (v Vocabulary) WithTags(tags []string) Vocabulary {
+	out := make(Vocabulary, len(v)+len(tags))
+	for tag, keywords := range v {
+		out[tag] = keywords
+	}
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" {
+			continue
+		}
+		if !slices.Contains(out[tag], tag) {
+			out[tag] = append(slices.Clone(out[tag]), tag)
+		}
+	}
+	return out
+}
+
+// words splits s into lowercase words with a trailing plural "s" removed.
+func words(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, field := range fields {
+		if len(field) > 3 && strings.HasSuffix(field, "s") && !strings.HasSuffix(field, "ss") {
+			fields[i] = strings.TrimSuffix(field, "s")
+		}
+	}
+	return fields
+}
+
+func containsPhrase(haystack, phrase []string) bool {
+	if len(phrase) == 0 || len(phrase) > len(haystack) {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(haystack); i++ {
+		if slices.Equal(haystack[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package tagging
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	vocabulary := Vocabulary{
+		"dessert": {"cake", "cookie", "ice cream"},
+		"chicken": {"chicken"},
+		"soup":    {"soup", "stew"},
+	}
+
+	tests := []struct {
+		name        string
+		title       string
+		ingredients []string
+		want        []Suggestion
+	}{
+		{
+			name:  "title match outweighs ingredient match",
+			title: "Chicken Noodle Soup",
+			ingredients: []string{
+				"2 chicken breasts",
+				"1 cup egg noodles",
+			},
+			want: []Suggestion{
+				{Tag: "chicken", Score: 3},
+				{Tag: "soup", Score: 2},
+			},
+		},
+		{
+			name:  "plural keywords match",
+			title: "Grandma's Cookies",
+			want: []Suggestion{
+				{Tag: "dessert", Score: 2},
+			},
+		},
+		{
+			name:        "multi-word keyword must appear as a phrase",
+			title:       "Sundae",
+			ingredients: []string{"vanilla ice cream", "cream cheese icing"},
+			want: []Suggestion{
+				{Tag: "dessert", Score: 1},
+			},
+		},
+		{
+			name:  "keywords only match whole words",
+			title: "Cupcake Stewart",
+			want:  nil,
+		},
+		{
+			name:        "no matches",
+			title:       "Green Salad",
+			ingredients: []string{"lettuce"},
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(tt.title, tt.ingredients, vocabulary)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVocabularyWithTags(t *testing.T) {
+	vocabulary := Vocabulary{"soup": {"soup"}}
+
+	got := vocabulary.WithTags([]string{" Weeknight ", "soup", ""})
+
+	want := Vocabulary{
+		"soup":      {"soup"},
+		"weeknight": {"weeknight"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WithTags() = %+v, want %+v", got, want)
+	}
+	if _, found := vocabulary["weeknight"]; found {
+		t.Error("WithTags() modified the original vocabulary")
+	}
+}
+
+func TestDetectAllergens(t *testing.T) {
+	ingredients := []string{
+		"2 tbsp peanut butter",
+		"1 cup roasted Peanuts",
+		"3 eggs",
+		"1 tsp eggplant powder",
+		"½ cup toasted sesame seeds",
+	}
+
+	got := DetectAllergens(ingredients, []string{"sesame", "peanuts", "eggs", "shellfish", "peanuts", "gluten"})
+
+	want := []AllergenMatch{
+		{Allergen: "eggs", Ingredients: []int{2}},
+		{Allergen: "peanuts", Ingredients: []int{0, 1}},
+		{Allergen: "sesame", Ingredients: []int{4}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectAllergens() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAllergenPatterns(t *testing.T) {
+	got := AllergenPatterns([]string{"tree_nuts", "unknown"})
+
+	if !slices.Contains(got, `\mpines?[^[:alnum:]]+nuts?\M`) {
+		t.Errorf("AllergenPatterns() = %v, want a pattern for pine nuts", got)
+	}
+	if len(got) != len(AllergenVocabulary["tree_nuts"]) {
+		t.Errorf("AllergenPatterns() returned %d patterns, want %d", len(got), len(AllergenVocabulary["tree_nuts"]))
+	}
+}
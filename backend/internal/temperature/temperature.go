@@ -0,0 +1,87 @@
+// Package temperature converts and validates cooking temperatures such as
+// oven settings on recipe steps.
+package temperature
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrUnknownUnit = errors.New("unknown temperature unit")
+	ErrOutOfRange  = errors.New("temperature out of range")
+)
+
+// Unit is a temperature scale.
+type Unit string
+
+const (
+	Celsius    Unit = "C"
+	Fahrenheit Unit = "F"
+)
+
+// Valid reports whether u is a supported unit.
+func (u Unit) Valid() bool {
+	return u == Celsius || u == Fahrenheit
+}
+
+// The accepted range, in degrees Celsius. It covers everything from a
+// freezer to a wood-fired pizza oven.
+const (
+	MinCelsius = -40
+	MaxCelsius = 550
+)
+
+// Temperature is a value on a given scale.
+type Temperature struct {
+	Value float64
+	Unit  Unit
+}
+
+// Celsius returns the temperature in degrees Celsius.
+func (t Temperature) Celsius() float64 {
+	if t.Unit == Fahrenheit {
+		return (t.Value - 32) * 5 / 9
+	}
+	return t.Value
+}
+
+// Validate checks that the unit is known and that the value is within
+// MinCelsius and MaxCelsius.
+func (t Temperature) Validate() error {
+	if !t.Unit.Valid() {
+		return fmt.Errorf("%w: %q", ErrUnknownUnit, t.Unit)
+	}
+	c := t.Celsius()
+	if math.IsNaN(c) || c < MinCelsius || c > MaxCelsius {
+		return fmt.Errorf("%w: must be between %d°C and %d°C (%.0f°F and %.0f°F)",
+			ErrOutOfRange, MinCelsius, MaxCelsius,
+			toFahrenheit(MinCelsius), toFahrenheit(MaxCelsius))
+	}
+	return nil
+}
+
+// Convert returns the temperature on the given scale, rounded to a whole
+// degree as ovens are set. A temperature already on the target scale is
+// returned unchanged.
+func (t Temperature) Convert(to Unit) (Temperature, error) {
+	if !to.Valid() {
+		return Temperature{}, fmt.Errorf("%w: %q", ErrUnknownUnit, to)
+	}
+	if !t.Unit.Valid() {
+		return Temperature{}, fmt.Errorf("%w: %q", ErrUnknownUnit, t.Unit)
+	}
+	if t.Unit == to {
+		return t, nil
+	}
+	c := t.Celsius()
+	if to == Fahrenheit {
+		return Temperature{Value: math.Round(toFahrenheit(c)), Unit: Fahrenheit}, nil
+	}
+	return Temperature{Value: math.Round(c), Unit: Celsius}, nil
+}
+
+func toFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
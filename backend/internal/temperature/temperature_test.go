@@ -0,0 +1,67 @@
+package temperature
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Temperature
+		to      Unit
+		want    Temperature
+		wantErr error
+	}{
+		{name: "fahrenheit to celsius", in: Temperature{350, Fahrenheit}, to: Celsius, want: Temperature{177, Celsius}},
+		{name: "celsius to fahrenheit", in: Temperature{180, Celsius}, to: Fahrenheit, want: Temperature{356, Fahrenheit}},
+		{name: "same unit is unchanged", in: Temperature{176.5, Celsius}, to: Celsius, want: Temperature{176.5, Celsius}},
+		{name: "negative values", in: Temperature{-40, Celsius}, to: Fahrenheit, want: Temperature{-40, Fahrenheit}},
+		{name: "unknown target", in: Temperature{180, Celsius}, to: "K", wantErr: ErrUnknownUnit},
+		{name: "unknown source", in: Temperature{180, "K"}, to: Celsius, wantErr: ErrUnknownUnit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.in.Convert(tt.to)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Temperature
+		wantErr error
+	}{
+		{name: "oven in fahrenheit", in: Temperature{350, Fahrenheit}},
+		{name: "oven in celsius", in: Temperature{220, Celsius}},
+		{name: "lower bound", in: Temperature{MinCelsius, Celsius}},
+		{name: "upper bound", in: Temperature{MaxCelsius, Celsius}},
+		{name: "celsius range applies to fahrenheit", in: Temperature{1100, Fahrenheit}, wantErr: ErrOutOfRange},
+		{name: "too hot", in: Temperature{600, Celsius}, wantErr: ErrOutOfRange},
+		{name: "too cold", in: Temperature{-50, Celsius}, wantErr: ErrOutOfRange},
+		{name: "unknown unit", in: Temperature{100, "K"}, wantErr: ErrUnknownUnit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.in.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
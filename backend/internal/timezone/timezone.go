@@ -0,0 +1,69 @@
+// Package timezone resolves the IANA time zones of users and computes
+// local-time schedules. Schedules are stored in UTC; the zone is kept
+// alongside so the next occurrence can be computed across daylight saving
+// transitions.
+package timezone
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	// The zone database is embedded so zones resolve in images without
+	// tzdata, such as the Alpine runtime image.
+	_ "time/tzdata"
+)
+
+// Default is the zone of users who have not chosen one.
+const Default = "UTC"
+
+var ErrUnknownZone = errors.New("unknown time zone")
+
+// Load returns the location of an IANA zone name such as
+// "America/New_York". The empty name and "Local" are rejected; the
+// server's zone is never a user's.
+func Load(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownZone, name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownZone, name)
+	}
+	return loc, nil
+}
+
+// LoadOrUTC returns the location of name, or UTC if it is unknown.
+func LoadOrUTC(name string) *time.Location {
+	loc, err := Load(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// NextWeekly returns the first time after t that it is hour o'clock on day
+// in loc, in UTC. The wall-clock time is kept across daylight saving
+// transitions, so the result may be 167 or 169 hours after the previous
+// occurrence. If hour does not exist on that day, it is moved forward by
+// the length of the gap.
+func NextWeekly(t time.Time, loc *time.Location, day time.Weekday, hour int) time.Time {
+	local := t.In(loc)
+	days := (int(day) - int(local.Weekday()) + 7) % 7
+	next := wallClock(local.Year(), local.Month(), local.Day()+days, hour, loc)
+	if !next.After(t) {
+		next = wallClock(local.Year(), local.Month(), local.Day()+days+7, hour, loc)
+	}
+	return next.UTC()
+}
+
+// wallClock returns hour o'clock on a day in loc. An hour skipped by a
+// daylight saving transition is read with the offset in effect before it,
+// which lands at the end of the gap.
+func wallClock(year int, month time.Month, day, hour int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, 0, 0, 0, loc)
+	if t.Hour() == hour {
+		return t
+	}
+	_, offset := time.Date(year, month, day, 0, 0, 0, 0, loc).Zone()
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC).Add(-time.Duration(offset) * time.Second).In(loc)
+}
@@ -0,0 +1,119 @@
+package timezone
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	if _, err := Load("America/New_York"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		if _, err := Load(name); !errors.Is(err, ErrUnknownZone) {
+			t.Errorf("Load(%q) error = %v, want ErrUnknownZone", name, err)
+		}
+	}
+	if loc := LoadOrUTC("nope"); loc != time.UTC {
+		t.Errorf("LoadOrUTC() = %v, want UTC", loc)
+	}
+}
+
+func TestNextWeekly(t *testing.T) {
+	newYork, err := Load("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	london, err := Load("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auckland, err := Load("Pacific/Auckland")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		loc  *time.Location
+		day  time.Weekday
+		hour int
+		want time.Time
+	}{
+		{
+			name: "later the same week",
+			t:    time.Date(2026, time.January, 14, 12, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			day:  time.Monday,
+			hour: 8,
+			want: time.Date(2026, time.January, 19, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "exactly on the hour moves to next week",
+			t:    time.Date(2026, time.January, 19, 8, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			day:  time.Monday,
+			hour: 8,
+			want: time.Date(2026, time.January, 26, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "local day differs from UTC day",
+			// Sunday 23:00 in New York is Monday 04:00 UTC.
+			t:    time.Date(2026, time.January, 19, 4, 0, 0, 0, time.UTC),
+			loc:  newYork,
+			day:  time.Monday,
+			hour: 8,
+			want: time.Date(2026, time.January, 19, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "across the spring forward",
+			// Clocks go forward on Sunday March 8, 2026: 08:00 EDT is 12:00 UTC.
+			t:    time.Date(2026, time.March, 2, 13, 0, 0, 0, time.UTC),
+			loc:  newYork,
+			day:  time.Monday,
+			hour: 8,
+			want: time.Date(2026, time.March, 9, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "across the fall back",
+			// Clocks go back on Sunday October 25, 2026: 08:00 GMT is 08:00 UTC.
+			t:    time.Date(2026, time.October, 19, 7, 0, 0, 0, time.UTC),
+			loc:  london,
+			day:  time.Monday,
+			hour: 8,
+			want: time.Date(2026, time.October, 26, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "hour inside the gap",
+			// 02:00 does not exist on March 8, 2026 in New York.
+			t:    time.Date(2026, time.March, 7, 12, 0, 0, 0, time.UTC),
+			loc:  newYork,
+			day:  time.Sunday,
+			hour: 2,
+			want: time.Date(2026, time.March, 8, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "southern hemisphere",
+			// Daylight saving ends in Auckland on April 5, 2026: 08:00 NZST is 20:00 UTC the day before.
+			t:    time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC),
+			loc:  auckland,
+			day:  time.Monday,
+			hour: 8,
+			want: time.Date(2026, time.April, 5, 20, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextWeekly(tt.t, tt.loc, tt.day, tt.hour)
+			if !got.Equal(tt.want) {
+				t.Errorf("NextWeekly() = %v, want %v", got, tt.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("NextWeekly() location = %v, want UTC", got.Location())
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package undo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/fileserver"
+)
+
+const (
+	// DefaultPurgeInterval is how often expired tokens are purged.
+	DefaultPurgeInterval = time.Minute
+	batchSize            = 100
+)
+
+// RunPurgeJob purges expired tokens every interval until ctx is
+// cancelled.
+func RunPurgeJob(ctx context.Context, env *env.Env, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		env.Logger.DebugContext(ctx, "purging expired undo tokens")
+		if err := Purge(ctx, env); err != nil {
+			env.Logger.ErrorContext(ctx, "failed to purge undo tokens", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes expired tokens and the images they held. Each token is
+// deleted in a transaction that is only committed once its image is gone,
+// and a token restored in the meantime is skipped, so a restored image is
+// never removed.
+func Purge(ctx context.Context, env *env.Env) error {
+	for {
+		tokens, err := env.Database.GetExpiredUndoTokens(ctx, database.GetExpiredUndoTokensParams{
+			Now:       pgtype.Timestamptz{Time: env.Now(), Valid: true},
+			BatchSize: batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("getting expired undo tokens: %w", err)
+		}
+		if len(tokens) == 0 {
+			return nil
+		}
+
+		for _, token := range tokens {
+			err := env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+				rows, err := tx.DeleteUndoToken(ctx, token.Token)
+				if err != nil {
+					return fmt.Errorf("deleting undo token: %w", err)
+				}
+				if rows == 0 || !token.ImageKey.Valid {
+					return nil
+				}
+				err = env.FileStore.DeleteKey(token.ImageKey.String)
+				if errors.Is(err, fileserver.ErrNotExist) {
+					env.Logger.WarnContext(ctx, "held image not found", slog.Any("error", err))
+				} else if err != nil {
+					return fmt.Errorf("deleting held image: %w", err)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package undo keeps deleted ingredients, steps, and images restorable for
+// a short window. Deleting returns a token; posting the token back within
+// the window restores what was deleted. Deleted images stay in storage
+// until the token expires and are removed by the purge job.
+package undo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+)
+
+var (
+	ErrNotFound = errors.New("undo token not found")
+	// ErrConflict is returned when the deletion can no longer be undone,
+	// for example because the recipe was deleted or a new image replaced
+	// the deleted one.
+	ErrConflict = errors.New("deletion can no longer be undone")
+)
+
+// Window is how long a deletion can be undone.
+const Window = 10 * time.Minute
+
+// Kind is what a token restores.
+type Kind string
+
+const (
+	KindIngredient      Kind = "ingredient"
+	KindStep            Kind = "step"
+	KindRecipeImage     Kind = "recipe_image"
+	KindIngredientImage Kind = "ingredient_image"
+	KindStepImage       Kind = "step_image"
+)
+
+// Postgres error codes of rows that cannot be restored.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// Token lets the user who deleted something restore it until ExpiresAt.
+type Token struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+// Restored describes a restored deletion.
+type Restored struct {
+	Kind     Kind
+	RecipeID int64
+}
+
+// snapshot is the state needed to restore a deletion. Image deletions
+// only record the ingredient or step that lost its image.
+type snapshot struct {
+	ID               int64     `json:"id,omitempty"`
+	Description      *string   `json:"description,omitempty"`
+	StepNumber       int32     `json:"step_number,omitempty"`
+	Instruction      *string   `json:"instruction,omitempty"`
+	TemperatureValue *float32  `json:"temperature_value,omitempty"`
+	TemperatureUnit  *string   `json:"temperature_unit,omitempty"`
+	CreatedAt        time.Time `json:"created_at,omitzero"`
+}
+
+// HoldIngredient returns a token restoring a deleted ingredient and its
+// image.
+func HoldIngredient(ctx context.Context, env *env.Env, userID int64, ingredient database.RecipeIngredient) (Token, error) {
+	s := snapshot{ID: ingredient.ID, CreatedAt: ingredient.CreatedAt.Time}
+	if ingredient.Description.Valid {
+		s.Description = &ingredient.Description.String
+	}
+	return hold(ctx, env, userID, KindIngredient, ingredient.RecipeID, s, ingredient.ImageKey)
+}
+
+// HoldStep returns a token restoring a deleted step, at its old position,
+// and its image.
+func HoldStep(ctx context.Context, env *env.Env, userID int64, step database.RecipeStep) (Token, error) {
+	s := snapshot{ID: step.ID, StepNumber: step.StepNumber, CreatedAt: step.CreatedAt.Time}
+	if step.Instruction.Valid {
+		s.Instruction = &step.Instruction.String
+	}
+	if step.TemperatureValue.Valid && step.TemperatureUnit.Valid {
+		unit := string(step.TemperatureUnit.TemperatureUnit)
+		s.TemperatureValue = &step.TemperatureValue.Float32
+		s.TemperatureUnit = &unit
+	}
+	return hold(ctx, env, userID, KindStep, step.RecipeID, s, step.ImageKey)
+}
+
+// HoldImage returns a token restoring a deleted image. itemID is the
+// ingredient or step the image belonged to, and is ignored for recipe
+// cover images.
+func HoldImage(ctx context.Context, env *env.Env, userID int64, kind Kind,
+	recipeID, itemID int64, imageKey string,
+) (Token, error) {
+	if kind != KindRecipeImage && kind != KindIngredientImage && kind != KindStepImage {
+		return Token{}, fmt.Errorf("%q is not an image kind", kind)
+	}
+	return hold(ctx, env, userID, kind, recipeID, snapshot{ID: itemID},
+		pgtype.Text{String: imageKey, Valid: true})
+}
+
+func hold(ctx context.Context, env *env.Env, userID int64, kind Kind,
+	recipeID int64, s snapshot, imageKey pgtype.Text,
+) (Token, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return Token{}, fmt.Errorf("encoding snapshot: %w", err)
+	}
+	token := Token{Value: env.NewID(), ExpiresAt: env.Now().Add(Window)}
+	if err := env.Database.CreateUndoToken(ctx, database.CreateUndoTokenParams{
+		Token:     token.Value,
+		UserID:    userID,
+		Kind:      string(kind),
+		RecipeID:  recipeID,
+		Snapshot:  data,
+		ImageKey:  imageKey,
+		ExpiresAt: pgtype.Timestamptz{Time: token.ExpiresAt, Valid: true},
+	}); err != nil {
+		return Token{}, fmt.Errorf("storing undo token: %w", err)
+	}
+	return token, nil
+}
+
+// Restore undoes the deletion held by a token of the user. A token can
+// only be used once: it is deleted in the transaction that restores the
+// deletion, so a concurrent restore or purge of the same token finds
+// nothing to do.
+func Restore(ctx context.Context, env *env.Env, userID int64, value string) (Restored, error) {
+	var restored Restored
+	err := env.WithTransaction(ctx, func(ctx context.Context, tx database.Querier) error {
+		token, err := tx.ConsumeUndoToken(ctx, database.ConsumeUndoTokenParams{
+			Token:  value,
+			UserID: userID,
+			Now:    pgtype.Timestamptz{Time: env.Now(), Valid: true},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("consuming undo token: %w", err)
+		}
+
+		var s snapshot
+		if err := json.Unmarshal(token.Snapshot, &s); err != nil {
+			return fmt.Errorf("decoding snapshot: %w", err)
+		}
+		restored = Restored{Kind: Kind(token.Kind), RecipeID: token.RecipeID}
+		return restore(ctx, tx, restored, s, token.ImageKey)
+	})
+	if err != nil {
+		return Restored{}, err
+	}
+	return restored, nil
+}
+
+func restore(ctx context.Context, db database.Querier, restored Restored, s snapshot, imageKey pgtype.Text) error {
+	var err error
+	var rows int64 = 1
+	switch restored.Kind {
+	case KindIngredient:
+		err = db.RestoreRecipeIngredient(ctx, database.RestoreRecipeIngredientParams{
+			ID:          s.ID,
+			RecipeID:    restored.RecipeID,
+			Description: optionalText(s.Description),
+			ImageKey:    imageKey,
+			CreatedAt:   pgtype.Timestamptz{Time: s.CreatedAt, Valid: true},
+		})
+	case KindStep:
+		params := database.RestoreRecipeStepParams{
+			ID:          s.ID,
+			RecipeID:    restored.RecipeID,
+			StepNumber:  s.StepNumber,
+			Instruction: optionalText(s.Instruction),
+			ImageKey:    imageKey,
+			CreatedAt:   pgtype.Timestamptz{Time: s.CreatedAt, Valid: true},
+		}
+		if s.TemperatureValue != nil && s.TemperatureUnit != nil {
+			params.TemperatureValue = pgtype.Float4{Float32: *s.TemperatureValue, Valid: true}
+			params.TemperatureUnit = database.NullTemperatureUnit{
+				TemperatureUnit: database.TemperatureUnit(*s.TemperatureUnit),
+				Valid:           true,
+			}
+		}
+		err = db.RestoreRecipeStep(ctx, params)
+	case KindRecipeImage:
+		rows, err = db.RestoreRecipeCoverImage(ctx, database.RestoreRecipeCoverImageParams{
+			ImageKey: imageKey,
+			ID:       restored.RecipeID,
+		})
+	case KindIngredientImage:
+		rows, err = db.RestoreRecipeIngredientImage(ctx, database.RestoreRecipeIngredientImageParams{
+			ImageKey: imageKey,
+			ID:       s.ID,
+			RecipeID: restored.RecipeID,
+		})
+	case KindStepImage:
+		rows, err = db.RestoreRecipeStepImage(ctx, database.RestoreRecipeStepImageParams{
+			ImageKey: imageKey,
+			ID:       s.ID,
+			RecipeID: restored.RecipeID,
+		})
+	default:
+		return fmt.Errorf("unknown undo kind %q", restored.Kind)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == uniqueViolation || pgErr.Code == foreignKeyViolation) {
+		return fmt.Errorf("%w: %s", ErrConflict, pgErr.Message)
+	}
+	if err != nil {
+		return fmt.Errorf("restoring %s: %w", restored.Kind, err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %s was replaced or deleted", ErrConflict, restored.Kind)
+	}
+	return nil
+}
+
+func optionalText(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
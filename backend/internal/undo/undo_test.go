@@ -0,0 +1,197 @@
+package undo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+
+	"github.com/matt-dz/wecook/internal/clock"
+	"github.com/matt-dz/wecook/internal/database"
+	"github.com/matt-dz/wecook/internal/env"
+	"github.com/matt-dz/wecook/internal/fileserver"
+	"github.com/matt-dz/wecook/internal/filestore"
+	"github.com/matt-dz/wecook/internal/idgen"
+	"github.com/matt-dz/wecook/internal/log"
+)
+
+var now = time.Date(2026, time.March, 2, 12, 0, 0, 0, time.UTC)
+
+func newEnv(mockDB *database.MockQuerier, mockFS *filestore.MockFileStoreInterface) *env.Env {
+	return &env.Env{
+		Logger:    log.NullLogger(),
+		Database:  mockDB,
+		FileStore: mockFS,
+		Clock:     clock.NewFrozen(now),
+		IDGen:     idgen.NewSequential("undo"),
+	}
+}
+
+func TestHoldAndRestoreStep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	e := newEnv(mockDB, nil)
+
+	step := database.RecipeStep{
+		ID:               7,
+		RecipeID:         3,
+		StepNumber:       2,
+		Instruction:      pgtype.Text{String: "Bake", Valid: true},
+		ImageKey:         pgtype.Text{String: "files/steps/3/7.png", Valid: true},
+		TemperatureValue: pgtype.Float4{Float32: 180, Valid: true},
+		TemperatureUnit:  database.NullTemperatureUnit{TemperatureUnit: "C", Valid: true},
+		CreatedAt:        pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+	}
+
+	var stored database.CreateUndoTokenParams
+	mockDB.EXPECT().
+		CreateUndoToken(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params database.CreateUndoTokenParams) error {
+			stored = params
+			return nil
+		})
+
+	token, err := HoldStep(context.Background(), e, 1, step)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !token.ExpiresAt.Equal(now.Add(Window)) {
+		t.Errorf("expected token to expire at %v, got %v", now.Add(Window), token.ExpiresAt)
+	}
+	if stored.Kind != string(KindStep) || stored.ImageKey != step.ImageKey {
+		t.Errorf("unexpected stored token %+v", stored)
+	}
+
+	mockDB.EXPECT().
+		ConsumeUndoToken(gomock.Any(), database.ConsumeUndoTokenParams{
+			Token:  token.Value,
+			UserID: 1,
+			Now:    pgtype.Timestamptz{Time: now, Valid: true},
+		}).
+		Return(database.UndoToken{
+			Token:    stored.Token,
+			UserID:   stored.UserID,
+			Kind:     stored.Kind,
+			RecipeID: stored.RecipeID,
+			Snapshot: stored.Snapshot,
+			ImageKey: stored.ImageKey,
+		}, nil)
+	mockDB.EXPECT().
+		RestoreRecipeStep(gomock.Any(), database.RestoreRecipeStepParams{
+			ID:               step.ID,
+			RecipeID:         step.RecipeID,
+			StepNumber:       step.StepNumber,
+			Instruction:      step.Instruction,
+			ImageKey:         step.ImageKey,
+			TemperatureValue: step.TemperatureValue,
+			TemperatureUnit:  step.TemperatureUnit,
+			CreatedAt:        step.CreatedAt,
+		}).
+		Return(nil)
+
+	restored, err := Restore(context.Background(), e, 1, token.Value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored != (Restored{Kind: KindStep, RecipeID: 3}) {
+		t.Errorf("unexpected restored %+v", restored)
+	}
+}
+
+func TestHoldImageRejectsOtherKinds(t *testing.T) {
+	if _, err := HoldImage(context.Background(), &env.Env{}, 1, KindStep, 3, 7, "key"); err == nil {
+		t.Error("expected error for non-image kind")
+	}
+}
+
+func TestRestoreErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(mockDB *database.MockQuerier)
+		wantErr error
+	}{
+		{
+			name: "unknown token",
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					ConsumeUndoToken(gomock.Any(), gomock.Any()).
+					Return(database.UndoToken{}, pgx.ErrNoRows)
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "recipe deleted",
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					ConsumeUndoToken(gomock.Any(), gomock.Any()).
+					Return(database.UndoToken{Token: "t", Kind: string(KindIngredient), Snapshot: []byte(`{"id":4}`)}, nil)
+				mockDB.EXPECT().
+					RestoreRecipeIngredient(gomock.Any(), gomock.Any()).
+					Return(&pgconn.PgError{Code: foreignKeyViolation})
+			},
+			wantErr: ErrConflict,
+		},
+		{
+			name: "image replaced",
+			setup: func(mockDB *database.MockQuerier) {
+				mockDB.EXPECT().
+					ConsumeUndoToken(gomock.Any(), gomock.Any()).
+					Return(database.UndoToken{Token: "t", Kind: string(KindRecipeImage), Snapshot: []byte(`{}`)}, nil)
+				mockDB.EXPECT().
+					RestoreRecipeCoverImage(gomock.Any(), gomock.Any()).
+					Return(int64(0), nil)
+			},
+			wantErr: ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDB := database.NewMockQuerier(ctrl)
+			tt.setup(mockDB)
+
+			_, err := Restore(context.Background(), newEnv(mockDB, nil), 1, "t")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := database.NewMockQuerier(ctrl)
+	mockFS := filestore.NewMockFileStoreInterface(ctrl)
+
+	gomock.InOrder(
+		mockDB.EXPECT().
+			GetExpiredUndoTokens(gomock.Any(), gomock.Any()).
+			Return([]database.GetExpiredUndoTokensRow{
+				{Token: "a", ImageKey: pgtype.Text{String: "files/recipes/1.png", Valid: true}},
+				{Token: "b", ImageKey: pgtype.Text{String: "files/steps/1/2.png", Valid: true}},
+				{Token: "c"},
+				{Token: "d", ImageKey: pgtype.Text{String: "files/ingredients/1/3.png", Valid: true}},
+			}, nil),
+		mockDB.EXPECT().
+			GetExpiredUndoTokens(gomock.Any(), gomock.Any()).
+			Return(nil, nil),
+	)
+	mockDB.EXPECT().DeleteUndoToken(gomock.Any(), "a").Return(int64(1), nil)
+	mockFS.EXPECT().DeleteKey("files/recipes/1.png").Return(nil)
+	// A held image that is already gone does not stop the purge.
+	mockDB.EXPECT().DeleteUndoToken(gomock.Any(), "b").Return(int64(1), nil)
+	mockFS.EXPECT().DeleteKey("files/steps/1/2.png").Return(fileserver.ErrNotExist)
+	mockDB.EXPECT().DeleteUndoToken(gomock.Any(), "c").Return(int64(1), nil)
+	// A token restored since it was listed keeps its image.
+	mockDB.EXPECT().DeleteUndoToken(gomock.Any(), "d").Return(int64(0), nil)
+
+	if err := Purge(context.Background(), newEnv(mockDB, mockFS)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
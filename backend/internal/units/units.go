@@ -0,0 +1,127 @@
+// Package units converts cooking quantities between units, using
+// ingredient densities to convert between volume and mass.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownUnit = errors.New("unknown unit")
+	ErrNoDensity   = errors.New("no density for ingredient")
+)
+
+// Dimension is what a unit measures.
+type Dimension string
+
+const (
+	Volume Dimension = "volume"
+	Mass   Dimension = "mass"
+)
+
+// Unit is a unit of volume or mass.
+type Unit struct {
+	// Name is the canonical abbreviation, such as "cup" or "g".
+	Name      string
+	Dimension Dimension
+	// Base is the size of the unit in millilitres or grams.
+	Base float64
+}
+
+var (
+	milliliter = Unit{"ml", Volume, 1}
+	liter      = Unit{"l", Volume, 1000}
+	teaspoon   = Unit{"tsp", Volume, 4.92892}
+	tablespoon = Unit{"tbsp", Volume, 14.7868}
+	fluidOunce = Unit{"fl oz", Volume, 29.5735}
+	cup        = Unit{"cup", Volume, 236.588}
+	pint       = Unit{"pt", Volume, 473.176}
+	quart      = Unit{"qt", Volume, 946.353}
+	gallon     = Unit{"gal", Volume, 3785.41}
+	gram       = Unit{"g", Mass, 1}
+	kilogram   = Unit{"kg", Mass, 1000}
+	milligram  = Unit{"mg", Mass, 0.001}
+	ounce      = Unit{"oz", Mass, 28.3495}
+	pound      = Unit{"lb", Mass, 453.592}
+)
+
+var unitNames = map[string]Unit{
+	"ml": milliliter, "milliliter": milliliter, "milliliters": milliliter,
+	"millilitre": milliliter, "millilitres": milliliter,
+	"l": liter, "liter": liter, "liters": liter, "litre": liter, "litres": liter,
+	"tsp": teaspoon, "teaspoon": teaspoon, "teaspoons": teaspoon,
+	"tbsp": tablespoon, "tablespoon": tablespoon, "tablespoons": tablespoon,
+	"fl oz": fluidOunce, "fluid ounce": fluidOunce, "fluid ounces": fluidOunce,
+	"cup": cup, "cups": cup, "c": cup,
+	"pt": pint, "pint": pint, "pints": pint,
+	"qt": quart, "quart": quart, "quarts": quart,
+	"gal": gallon, "gallon": gallon, "gallons": gallon,
+	"g": gram, "gram": gram, "grams": gram, "gramme": gram, "grammes": gram,
+	"kg": kilogram, "kilogram": kilogram, "kilograms": kilogram,
+	"mg": milligram, "milligram": milligram, "milligrams": milligram,
+	"oz": ounce, "ounce": ounce, "ounces": ounce,
+	"lb": pound, "lbs": pound, "pound": pound, "pounds": pound,
+}
+
+// LookupUnit finds a unit by name or abbreviation, ignoring case and
+// a trailing period.
+func LookupUnit(name string) (Unit, bool) {
+	name = strings.TrimSuffix(strings.ToLower(strings.Join(strings.Fields(name), " ")), ".")
+	unit, ok := unitNames[name]
+	return unit, ok
+}
+
+// Densities maps normalized ingredient names to millilitres per gram.
+type Densities map[string]float64
+
+// NormalizeIngredient lowercases an ingredient name and collapses
+// whitespace so names match regardless of formatting.
+func NormalizeIngredient(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Lookup returns the density of ingredient in millilitres per gram. A
+// plural name falls back to its singular form.
+func (d Densities) Lookup(ingredient string) (float64, bool) {
+	ingredient = NormalizeIngredient(ingredient)
+	if density, ok := d[ingredient]; ok {
+		return density, true
+	}
+	for _, suffix := range []string{"es", "s"} {
+		if singular, found := strings.CutSuffix(ingredient, suffix); found {
+			if density, ok := d[singular]; ok {
+				return density, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Convert converts quantity from one unit to another. Converting
+// between volume and mass requires the density of ingredient.
+func Convert(quantity float64, from, to, ingredient string, densities Densities) (float64, error) {
+	fromUnit, ok := LookupUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, from)
+	}
+	toUnit, ok := LookupUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, to)
+	}
+
+	base := quantity * fromUnit.Base
+	if fromUnit.Dimension != toUnit.Dimension {
+		density, ok := densities.Lookup(ingredient)
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrNoDensity, ingredient)
+		}
+		if fromUnit.Dimension == Volume {
+			base /= density
+		} else {
+			base *= density
+		}
+	}
+	return base / toUnit.Base, nil
+}
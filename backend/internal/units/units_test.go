@@ -0,0 +1,65 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	densities := Densities{
+		"all-purpose flour": 1.89,
+		"water":             1.0,
+		"egg":               0.95,
+	}
+
+	tests := []struct {
+		name       string
+		quantity   float64
+		from       string
+		to         string
+		ingredient string
+		want       float64
+		wantErr    error
+	}{
+		{name: "volume to volume", quantity: 3, from: "tsp", to: "tbsp", want: 1},
+		{name: "mass to mass", quantity: 1, from: "lb", to: "oz", want: 16},
+		{name: "case and period are ignored", quantity: 2, from: "Cups", to: "ML.", want: 473.176},
+		{name: "volume to mass", quantity: 1, from: "cup", to: "g", ingredient: "All-Purpose  Flour", want: 125.18},
+		{name: "mass to volume", quantity: 500, from: "g", to: "l", ingredient: "water", want: 0.5},
+		{name: "plural ingredient", quantity: 100, from: "g", to: "ml", ingredient: "eggs", want: 95},
+		{name: "density not needed within dimension", quantity: 1, from: "kg", to: "g", ingredient: "unknown", want: 1000},
+		{name: "missing density", quantity: 1, from: "cup", to: "g", ingredient: "saffron", wantErr: ErrNoDensity},
+		{name: "unknown from unit", quantity: 1, from: "pinch", to: "g", wantErr: ErrUnknownUnit},
+		{name: "unknown to unit", quantity: 1, from: "g", to: "handful", wantErr: ErrUnknownUnit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.quantity, tt.from, tt.to, tt.ingredient, densities)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLookupUnit(t *testing.T) {
+	for _, name := range []string{"fl oz", "Fluid  Ounces", "tbsp.", "LBS"} {
+		if _, ok := LookupUnit(name); !ok {
+			t.Errorf("LookupUnit(%q) failed", name)
+		}
+	}
+	if _, ok := LookupUnit("pinch"); ok {
+		t.Error("LookupUnit(\"pinch\") succeeded")
+	}
+}
@@ -0,0 +1,76 @@
+// Package upload signs and verifies one-time upload URLs, which let a
+// client upload an image to a recipe without holding an access token.
+package upload
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Target is what an uploaded image is attached to.
+type Target string
+
+const (
+	TargetCover      Target = "cover"
+	TargetIngredient Target = "ingredient"
+	TargetStep       Target = "step"
+)
+
+// DefaultLifetime is how long an upload URL is valid.
+const DefaultLifetime = 15 * time.Minute
+
+const (
+	expiresParam   = "expires"
+	signatureParam = "signature"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid upload signature")
+	ErrExpired          = errors.New("upload url expired")
+)
+
+// Valid reports whether t is a known target.
+func (t Target) Valid() bool {
+	switch t {
+	case TargetCover, TargetIngredient, TargetStep:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sign returns the signature of an upload token and its expiry.
+func Sign(secret []byte, token string, expires time.Time) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(token))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(expires.Unix(), 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that signature matches the token and expiry, and that
+// the expiry has not passed.
+func Verify(secret []byte, token string, expires time.Time, signature string, now time.Time) error {
+	expected := Sign(secret, token, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	if !now.Before(expires) {
+		return ErrExpired
+	}
+	return nil
+}
+
+// URL returns the signed upload URL of a token below baseURL, such as
+// "https://wecook.example.com/api/v1/uploads".
+func URL(baseURL string, secret []byte, token string, expires time.Time) string {
+	query := url.Values{}
+	query.Set(expiresParam, strconv.FormatInt(expires.Unix(), 10))
+	query.Set(signatureParam, Sign(secret, token, expires))
+	return baseURL + "/" + url.PathEscape(token) + "?" + query.Encode()
+}
@@ -0,0 +1,78 @@
+package upload
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(DefaultLifetime)
+	signature := Sign(secret, "token-1", expires)
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		token     string
+		expires   time.Time
+		signature string
+		now       time.Time
+		wantErr   error
+	}{
+		{name: "valid", secret: secret, token: "token-1", expires: expires, signature: signature, now: now},
+		{name: "other token", secret: secret, token: "token-2", expires: expires, signature: signature, now: now,
+			wantErr: ErrInvalidSignature},
+		{name: "extended expiry", secret: secret, token: "token-1", expires: expires.Add(time.Hour),
+			signature: signature, now: now, wantErr: ErrInvalidSignature},
+		{name: "other secret", secret: []byte("another secret"), token: "token-1", expires: expires,
+			signature: signature, now: now, wantErr: ErrInvalidSignature},
+		{name: "empty signature", secret: secret, token: "token-1", expires: expires, now: now,
+			wantErr: ErrInvalidSignature},
+		{name: "expired", secret: secret, token: "token-1", expires: expires, signature: signature,
+			now: expires, wantErr: ErrExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.token, tt.expires, tt.signature, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	expires := time.Date(2025, 6, 1, 12, 15, 0, 0, time.UTC)
+
+	raw := URL("https://wecook.example.com/api/v1/uploads", secret, "abc", expires)
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("URL() = %q is not a valid url: %v", raw, err)
+	}
+	if u.Path != "/api/v1/uploads/abc" {
+		t.Errorf("path = %q, want %q", u.Path, "/api/v1/uploads/abc")
+	}
+	if got := u.Query().Get(expiresParam); got != strconv.FormatInt(expires.Unix(), 10) {
+		t.Errorf("expires = %q, want %d", got, expires.Unix())
+	}
+	if got := u.Query().Get(signatureParam); got != Sign(secret, "abc", expires) {
+		t.Errorf("signature = %q, want %q", got, Sign(secret, "abc", expires))
+	}
+}
+
+func TestTargetValid(t *testing.T) {
+	for _, target := range []Target{TargetCover, TargetIngredient, TargetStep} {
+		if !target.Valid() {
+			t.Errorf("%q should be valid", target)
+		}
+	}
+	if Target("avatar").Valid() {
+		t.Error(`"avatar" should not be valid`)
+	}
+}
@@ -1,5 +1,7 @@
 // See https://svelte.dev/docs/kit/types#app.d.ts
 // for information about these interfaces
+import type { Instance } from '$lib/instance';
+
 declare global {
 	namespace App {
 		interface Error {
@@ -10,7 +12,9 @@ declare global {
 		}
 		// interface Error {}
 		// interface Locals {}
-		// interface PageData {}
+		interface PageData {
+			instance: Instance;
+		}
 		// interface PageState {}
 		// interface Platform {}
 	}
@@ -1,4 +1,6 @@
-import type { FetchType } from '$lib/http';
+import { type FetchType, PageSchema } from '$lib/http';
+import { StockImageSchema, type StockImage } from '$lib/recipes';
+import { InstanceSchema, type Instance } from '$lib/instance';
 import type { Options } from 'ky';
 import * as z from 'zod';
 
@@ -37,3 +39,188 @@ export async function updatePreferences(
 		.json();
 	return PreferencesSchema.parse(json);
 }
+
+
+export const DeliveryKindSchema = z.enum(['email', 'webhook', 'activity']);
+
+export const DeliveryStatusSchema = z.enum(['queued', 'sent', 'failed', 'dead']);
+
+export const DeliverySchema = z.object({
+	id: z.int().min(0),
+	kind: DeliveryKindSchema,
+	recipient: z.string(),
+	subject: z.string(),
+	status: DeliveryStatusSchema,
+	attempts: z.int().min(0),
+	last_error: z.string().optional(),
+	next_attempt_at: z.string().optional(),
+	sent_at: z.string().optional(),
+	created_at: z.string(),
+	updated_at: z.string()
+});
+
+export type Delivery = z.infer<typeof DeliverySchema>;
+
+export const GetDeliveriesResponseSchema = z.object({
+	deliveries: z.array(DeliverySchema),
+	cursor: z.int().min(0),
+	page: PageSchema
+});
+
+export type GetDeliveriesRequest = {
+	status?: z.infer<typeof DeliveryStatusSchema>;
+	kind?: z.infer<typeof DeliveryKindSchema>;
+	before?: number;
+	limit?: number;
+};
+
+export type GetDeliveriesResponse = z.infer<typeof GetDeliveriesResponseSchema>;
+
+export async function getDeliveries(
+	fetch: FetchType,
+	request: GetDeliveriesRequest,
+	options?: Options,
+	apiUrl?: string
+): Promise<GetDeliveriesResponse> {
+	const searchParams = new URLSearchParams();
+	for (const [key, value] of Object.entries(request)) {
+		if (value !== undefined) {
+			searchParams.set(key, String(value));
+		}
+	}
+	const json = await fetch
+		.get(`${apiUrl ?? ''}/api/deliveries`, {
+			...options,
+			searchParams
+		})
+		.json();
+	return GetDeliveriesResponseSchema.parse(json);
+}
+
+export const DeliveryStatsSchema = z.object({
+	kind: DeliveryKindSchema,
+	queued: z.int().min(0),
+	sent: z.int().min(0),
+	failed: z.int().min(0),
+	dead: z.int().min(0),
+	retried: z.int().min(0)
+});
+
+export const GetDeliveryStatsResponseSchema = z.object({
+	stats: z.array(DeliveryStatsSchema)
+});
+
+export type GetDeliveryStatsResponse = z.infer<typeof GetDeliveryStatsResponseSchema>;
+
+export async function getDeliveryStats(
+	fetch: FetchType,
+	options?: Options,
+	apiUrl?: string
+): Promise<GetDeliveryStatsResponse> {
+	const json = await fetch.get(`${apiUrl ?? ''}/api/deliveries/stats`, options).json();
+	return GetDeliveryStatsResponseSchema.parse(json);
+}
+
+export async function retryDelivery(
+	fetch: FetchType,
+	id: number,
+	options?: Options,
+	apiUrl?: string
+): Promise<Delivery> {
+	const json = await fetch.post(`${apiUrl ?? ''}/api/deliveries/${id}/retry`, options).json();
+	return DeliverySchema.parse(json);
+}
+
+export const GetStockImagesResponseSchema = z.object({
+	stock_images: z.array(StockImageSchema)
+});
+
+export type GetStockImagesResponse = z.infer<typeof GetStockImagesResponseSchema>;
+
+export async function getStockImages(
+	fetch: FetchType,
+	options?: Options,
+	apiUrl?: string
+): Promise<GetStockImagesResponse> {
+	const json = await fetch.get(`${apiUrl ?? ''}/api/stock-images`, options).json();
+	return GetStockImagesResponseSchema.parse(json);
+}
+
+export type UploadStockImageRequest = {
+	ingredient: string;
+	image: File;
+};
+
+export async function uploadStockImage(
+	fetch: FetchType,
+	request: UploadStockImageRequest,
+	options?: Options,
+	apiUrl?: string
+): Promise<StockImage> {
+	const form = new FormData();
+	form.append('ingredient', request.ingredient);
+	form.append('image', request.image);
+	const json = await fetch
+		.post(`${apiUrl ?? ''}/api/stock-images`, {
+			...options,
+			body: form
+		})
+		.json();
+	return StockImageSchema.parse(json);
+}
+
+export async function deleteStockImage(
+	fetch: FetchType,
+	id: number,
+	options?: Options,
+	apiUrl?: string
+): Promise<void> {
+	await fetch.delete(`${apiUrl ?? ''}/api/stock-images/${id}`, options);
+}
+
+export type UpdateInstanceRequest = {
+	name?: string;
+	accent_color?: string | null;
+	contact_email?: string | null;
+};
+
+export async function updateInstance(
+	fetch: FetchType,
+	request: UpdateInstanceRequest,
+	options?: Options,
+	apiUrl?: string
+): Promise<Instance> {
+	const json = await fetch
+		.patch(`${apiUrl ?? ''}/api/instance`, {
+			...options,
+			json: request
+		})
+		.json();
+	return InstanceSchema.parse(json);
+}
+
+export async function uploadInstanceLogo(
+	fetch: FetchType,
+	image: File,
+	options?: Options,
+	apiUrl?: string
+): Promise<Instance> {
+	const form = new FormData();
+	form.append('image', image);
+	const json = await fetch
+		.post(`${apiUrl ?? ''}/api/instance/logo`, {
+			...options,
+			body: form
+		})
+		.json();
+	return InstanceSchema.parse(json);
+}
+
+export async function deleteInstanceLogo(
+	fetch: FetchType,
+	options?: Options,
+	apiUrl?: string
+): Promise<Instance> {
+	const json = await fetch.delete(`${apiUrl ?? ''}/api/instance/logo`, options).json();
+	return InstanceSchema.parse(json);
+}
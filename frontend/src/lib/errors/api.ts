@@ -29,7 +29,28 @@ enum ApiErrorCodes {
 	UserNotFound = 'user_not_found',
 	InvalidInviteCode = 'invalid_invite_code',
 	InvalidPassword = 'invalid_password',
-	UnsupportedImageFormat = 'unsupported_image_format'
+	UnsupportedImageFormat = 'unsupported_image_format',
+	InvalidUploadURL = 'invalid_upload_url',
+	HotlinkNotAllowed = 'hotlink_not_allowed',
+	DensityVersionNotFound = 'density_version_not_found',
+	ApplianceNotFound = 'appliance_not_found',
+	ApplianceScopeDenied = 'appliance_scope_denied',
+	ApplianceUnavailable = 'appliance_unavailable',
+	InvalidUnsubscribeLink = 'invalid_unsubscribe_link',
+	UndoTokenNotFound = 'undo_token_not_found',
+	UndoConflict = 'undo_conflict',
+	DeliveryNotFound = 'delivery_not_found',
+	DeliveryNotRetryable = 'delivery_not_retryable',
+	StockImageNotFound = 'stock_image_not_found',
+	FederationDisabled = 'federation_disabled',
+	RemoteActorNotFound = 'remote_actor_not_found',
+	FollowNotFound = 'follow_not_found',
+	InvalidSignature = 'invalid_signature',
+	InvalidTimeZone = 'invalid_time_zone',
+	TagNotFound = 'tag_not_found',
+	TagConflict = 'tag_conflict',
+	DemoDisabled = 'demo_disabled',
+	DemoReadOnly = 'demo_read_only'
 }
 
 export class RefreshTokenExpiredError extends Error {
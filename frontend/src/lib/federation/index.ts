@@ -0,0 +1,105 @@
+import { type FetchType, PageSchema } from '$lib/http';
+import type { Options } from 'ky';
+import * as z from 'zod';
+
+export const FederationFollowingSchema = z.object({
+	id: z.int().min(0),
+	handle: z.string(),
+	name: z.string(),
+	actor: z.string(),
+	accepted: z.boolean(),
+	created_at: z.string()
+});
+
+export type FederationFollowing = z.infer<typeof FederationFollowingSchema>;
+
+export const GetFederationFollowingResponseSchema = z.object({
+	handle: z.string(),
+	following: z.array(FederationFollowingSchema)
+});
+
+export type GetFederationFollowingResponse = z.infer<typeof GetFederationFollowingResponseSchema>;
+
+export async function getFederationFollowing(
+	fetch: FetchType,
+	options?: Options,
+	apiUrl?: string
+): Promise<GetFederationFollowingResponse> {
+	const json = await fetch.get(`${apiUrl ?? ''}/api/federation/following`, options).json();
+	return GetFederationFollowingResponseSchema.parse(json);
+}
+
+export type FollowRemoteUserRequest = {
+	handle: string;
+};
+
+export async function followRemoteUser(
+	fetch: FetchType,
+	request: FollowRemoteUserRequest,
+	options?: Options,
+	apiUrl?: string
+): Promise<FederationFollowing> {
+	const json = await fetch
+		.post(`${apiUrl ?? ''}/api/federation/following`, {
+			...options,
+			json: request
+		})
+		.json();
+	return FederationFollowingSchema.parse(json);
+}
+
+export async function unfollowRemoteUser(
+	fetch: FetchType,
+	id: number,
+	options?: Options,
+	apiUrl?: string
+): Promise<void> {
+	await fetch.delete(`${apiUrl ?? ''}/api/federation/following/${id}`, options);
+}
+
+export const FederatedRecipeSchema = z.object({
+	id: z.int().min(0),
+	title: z.string(),
+	description: z.string().optional(),
+	author: z.string(),
+	url: z.string(),
+	image_url: z.string().optional(),
+	ingredients: z.array(z.string()),
+	steps: z.array(z.string()),
+	published_at: z.string()
+});
+
+export type FederatedRecipe = z.infer<typeof FederatedRecipeSchema>;
+
+export const GetFederatedRecipesResponseSchema = z.object({
+	recipes: z.array(FederatedRecipeSchema),
+	page: PageSchema
+});
+
+export type GetFederatedRecipesRequest = {
+	limit?: number;
+	offset?: number;
+};
+
+export type GetFederatedRecipesResponse = z.infer<typeof GetFederatedRecipesResponseSchema>;
+
+export async function getFederatedRecipes(
+	fetch: FetchType,
+	request: GetFederatedRecipesRequest,
+	options?: Options,
+	apiUrl?: string
+): Promise<GetFederatedRecipesResponse> {
+	const searchParams = new URLSearchParams();
+	for (const [key, value] of Object.entries(request)) {
+		if (value !== undefined) {
+			searchParams.set(key, String(value));
+		}
+	}
+	const json = await fetch
+		.get(`${apiUrl ?? ''}/api/federation/recipes`, {
+			...options,
+			searchParams
+		})
+		.json();
+	return GetFederatedRecipesResponseSchema.parse(json);
+}
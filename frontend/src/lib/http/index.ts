@@ -1,4 +1,5 @@
 import ky, { type KyResponse, type Options, HTTPError } from 'ky';
+import * as z from 'zod';
 import { accessTokenExpired, refreshTokenExpired } from '$lib/errors/api';
 import { CSRF_HEADER, CSRF_TOKEN_COOKIE_NAME, refreshSession } from '$lib/auth';
 
@@ -93,6 +94,17 @@ export function isRetryable(response: KyResponse) {
 	return retryCodes.includes(response.status);
 }
 
+/**
+ * Describes a page of a list response.
+ */
+export const PageSchema = z.object({
+	limit: z.int().min(1),
+	total: z.int().min(0),
+	has_more: z.boolean()
+});
+
+export type Page = z.infer<typeof PageSchema>;
+
 type FetchType = typeof fetch;
 
 export type { FetchType };
@@ -0,0 +1,25 @@
+import type { FetchType } from '$lib/http';
+import type { Options } from 'ky';
+import * as z from 'zod';
+
+export const DEFAULT_INSTANCE_NAME = 'WeCook';
+
+export const InstanceSchema = z.object({
+	name: z.string(),
+	logo_url: z.string().optional(),
+	accent_color: z.string().optional(),
+	contact_email: z.string().optional()
+});
+
+export type Instance = z.infer<typeof InstanceSchema>;
+
+export const defaultInstance: Instance = { name: DEFAULT_INSTANCE_NAME };
+
+export async function getInstance(
+	fetch: FetchType,
+	options?: Options,
+	apiUrl?: string
+): Promise<Instance> {
+	const json = await fetch.get(`${apiUrl ?? ''}/api/instance`, options).json();
+	return InstanceSchema.parse(json);
+}
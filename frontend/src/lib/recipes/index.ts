@@ -1,4 +1,4 @@
-import { type FetchType } from '$lib/http';
+import { type FetchType, PageSchema } from '$lib/http';
 import type { Options } from 'ky';
 import * as z from 'zod';
 
@@ -77,46 +77,109 @@ export const RecipeSchema = z.object({
 
 export type Recipe = z.infer<typeof RecipeSchema>;
 
+export const AllergenSchema = z.enum([
+	'peanuts',
+	'tree_nuts',
+	'milk',
+	'eggs',
+	'fish',
+	'shellfish',
+	'soy',
+	'wheat',
+	'sesame'
+]);
+
+export type Allergen = z.infer<typeof AllergenSchema>;
+
+export const AllergyWarningSchema = z.object({
+	allergen: AllergenSchema,
+	message: z.string(),
+	ingredient_ids: z.array(z.int())
+});
+
+export type AllergyWarning = z.infer<typeof AllergyWarningSchema>;
+
 export const RecipeWithStepsIngredientsAndOwnerSchema = z.object({
 	owner: RecipeOwner,
-	recipe: RecipeWithIngredientsAndStepsSchema
+	recipe: RecipeWithIngredientsAndStepsSchema,
+	allergy_warnings: z.array(AllergyWarningSchema).default([])
 });
 
 export type RecipeWithStepsIngredientsAndOwner = z.infer<
 	typeof RecipeWithStepsIngredientsAndOwnerSchema
 >;
 
+export const CompletenessItemSchema = z.enum([
+	'cover_image',
+	'times',
+	'servings',
+	'tags',
+	'step_instructions'
+]);
+
+export type CompletenessItem = z.infer<typeof CompletenessItemSchema>;
+
+export const CompletenessSchema = z.object({
+	score: z.int().min(0).max(100),
+	missing: z.array(CompletenessItemSchema)
+});
+
+export type Completeness = z.infer<typeof CompletenessSchema>;
+
 export const RecipeAndOwnerSchema = z.object({
 	owner: RecipeOwner,
-	recipe: RecipeSchema
+	recipe: RecipeSchema,
+	completeness: CompletenessSchema.optional()
 });
 
 export type RecipeAndOwner = z.infer<typeof RecipeAndOwnerSchema>;
 
 export const GetPersonalRecipesResponse = z.object({
-	recipes: z.array(RecipeAndOwnerSchema)
+	recipes: z.array(RecipeAndOwnerSchema),
+	page: PageSchema
 });
 
+/**
+ * Gets every page of the user's recipes.
+ */
 export async function getPersonalRecipes(
 	fetch: FetchType,
 	options?: Options,
 	apiUrl?: string
-): Promise<z.infer<typeof GetPersonalRecipesResponse>> {
-	const res = await fetch(`${apiUrl ?? ''}/api/recipes`, options);
-	return GetPersonalRecipesResponse.parse(await res.json());
+): Promise<{ recipes: RecipeAndOwner[] }> {
+	const recipes: RecipeAndOwner[] = [];
+	for (;;) {
+		const res = await fetch(`${apiUrl ?? ''}/api/recipes?offset=${recipes.length}`, options);
+		const page = GetPersonalRecipesResponse.parse(await res.json());
+		recipes.push(...page.recipes);
+		if (!page.page.has_more || page.recipes.length === 0) {
+			return { recipes };
+		}
+	}
 }
 
 export const GetRecipesResponse = z.object({
-	recipes: z.array(RecipeAndOwnerSchema)
+	recipes: z.array(RecipeAndOwnerSchema),
+	page: PageSchema
 });
 
+/**
+ * Gets every page of the public recipes.
+ */
 export async function getRecipes(
 	fetch: FetchType,
 	options?: Options,
 	apiUrl?: string
-): Promise<z.infer<typeof GetRecipesResponse>> {
-	const res = await fetch(`${apiUrl ?? ''}/api/recipes/public`, options);
-	return GetRecipesResponse.parse(await res.json());
+): Promise<{ recipes: RecipeAndOwner[] }> {
+	const recipes: RecipeAndOwner[] = [];
+	for (;;) {
+		const res = await fetch(`${apiUrl ?? ''}/api/recipes/public?offset=${recipes.length}`, options);
+		const page = GetRecipesResponse.parse(await res.json());
+		recipes.push(...page.recipes);
+		if (!page.page.has_more || page.recipes.length === 0) {
+			return { recipes };
+		}
+	}
 }
 
 export type GetRecipeResponse = RecipeWithStepsIngredientsAndOwner;
@@ -279,6 +342,28 @@ export async function createStep(
 	return StepSchema.parse(res);
 }
 
+export const UndoTokenSchema = z.object({
+	undo_token: z.string(),
+	undo_expires_at: z.iso.datetime()
+});
+export type UndoToken = z.infer<typeof UndoTokenSchema>;
+
+export const UndoResultSchema = z.object({
+	kind: z.enum(['ingredient', 'step', 'recipe_image', 'ingredient_image', 'step_image']),
+	recipe_id: z.int()
+});
+export type UndoResult = z.infer<typeof UndoResultSchema>;
+
+export async function undoDelete(
+	fetch: FetchType,
+	token: string,
+	options?: Options,
+	apiUrl?: string
+): Promise<UndoResult> {
+	const res = await fetch.post(`${apiUrl ?? ''}/api/undo/${token}`, options).json();
+	return UndoResultSchema.parse(res);
+}
+
 export type DeleteIngredientRequest = {
 	recipe_id: number;
 	ingredient_id: number;
@@ -289,11 +374,14 @@ export async function deleteIngredient(
 	request: DeleteIngredientRequest,
 	options?: Options,
 	apiUrl?: string
-): Promise<void> {
-	await fetch.delete(
-		`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/ingredients/${request.ingredient_id}`,
-		options
-	);
+): Promise<UndoToken> {
+	const res = await fetch
+		.delete(
+			`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/ingredients/${request.ingredient_id}`,
+			options
+		)
+		.json();
+	return UndoTokenSchema.parse(res);
 }
 
 export type DeleteStepRequest = {
@@ -306,11 +394,14 @@ export async function deleteStep(
 	request: DeleteStepRequest,
 	options?: Options,
 	apiUrl?: string
-): Promise<void> {
-	await fetch.delete(
-		`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/steps/${request.step_id}`,
-		options
-	);
+): Promise<UndoToken> {
+	const res = await fetch
+		.delete(
+			`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/steps/${request.step_id}`,
+			options
+		)
+		.json();
+	return UndoTokenSchema.parse(res);
 }
 
 export type UploadIngredientImageRequest = {
@@ -351,11 +442,63 @@ export async function deleteIngredientImage(
 	request: DeleteIngredientImageRequest,
 	options?: Options,
 	apiUrl?: string
-): Promise<void> {
-	await fetch.delete(
-		`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/ingredients/${request.ingredient_id}/image`,
-		options
-	);
+): Promise<UndoToken> {
+	const res = await fetch
+		.delete(
+			`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/ingredients/${request.ingredient_id}/image`,
+			options
+		)
+		.json();
+	return UndoTokenSchema.parse(res);
+}
+
+export const StockImageSchema = z.object({
+	id: z.int(),
+	ingredient: z.string(),
+	image_url: z.string(),
+	created_at: z.iso.datetime(),
+	updated_at: z.iso.datetime()
+});
+
+export type StockImage = z.infer<typeof StockImageSchema>;
+
+export async function suggestStockImage(
+	fetch: FetchType,
+	ingredient: string,
+	options?: Options,
+	apiUrl?: string
+): Promise<StockImage> {
+	const res = await fetch
+		.get(`${apiUrl ?? ''}/api/ingredients/stock-image`, {
+			...options,
+			searchParams: { ingredient }
+		})
+		.json();
+	return StockImageSchema.parse(res);
+}
+
+export type AttachStockImageRequest = {
+	recipe_id: number;
+	ingredient_id: number;
+	stock_image_id: number;
+};
+
+export async function attachStockImage(
+	fetch: FetchType,
+	request: AttachStockImageRequest,
+	options?: Options,
+	apiUrl?: string
+): Promise<Ingredient> {
+	const res = await fetch
+		.post(
+			`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/ingredients/${request.ingredient_id}/stock-image`,
+			{
+				...options,
+				json: { stock_image_id: request.stock_image_id }
+			}
+		)
+		.json();
+	return IngredientSchema.parse(res);
 }
 
 export type UploadStepImageRequest = {
@@ -393,11 +536,14 @@ export async function deleteStepImage(
 	request: DeleteStepImageRequest,
 	options?: Options,
 	apiUrl?: string
-): Promise<void> {
-	await fetch.delete(
-		`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/steps/${request.step_id}/image`,
-		options
-	);
+): Promise<UndoToken> {
+	const res = await fetch
+		.delete(
+			`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/steps/${request.step_id}/image`,
+			options
+		)
+		.json();
+	return UndoTokenSchema.parse(res);
 }
 
 export type UploadRecipeImageRequest = {
@@ -433,8 +579,11 @@ export async function deleteRecipeImage(
 	request: DeleteRecipeImageRequest,
 	options?: Options,
 	apiUrl?: string
-): Promise<void> {
-	await fetch.delete(`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/image`, options);
+): Promise<UndoToken> {
+	const res = await fetch
+		.delete(`${apiUrl ?? ''}/api/recipes/${request.recipe_id}/image`, options)
+		.json();
+	return UndoTokenSchema.parse(res);
 }
 
 export type DeleteRecipeRequest = {
@@ -1,5 +1,5 @@
 import { ACCESS_TOKEN_COOKIE_NAME } from '$lib/auth';
-import type { FetchType } from '$lib/http';
+import { type FetchType, PageSchema } from '$lib/http';
 import type { Options } from 'ky';
 import * as z from 'zod';
 
@@ -31,7 +31,8 @@ export async function getUser(
 
 export const GetUsersResponseSchema = z.object({
 	users: z.array(UserSchema),
-	cursor: z.int().min(0)
+	cursor: z.int().min(0),
+	page: PageSchema
 });
 
 export type GetUsersRequest = {
@@ -124,3 +125,76 @@ export async function deleteUser(
 ): Promise<void> {
 	await fetch.delete(`${apiUrl ?? ''}/api/user/${request.user_id}`, options);
 }
+
+export type ExportUserRequest = {
+	user_id: number;
+	justification: string;
+};
+
+export async function exportUser(
+	fetch: FetchType,
+	request: ExportUserRequest,
+	options?: Options,
+	apiUrl?: string
+): Promise<Blob> {
+	return fetch
+		.post(`${apiUrl ?? ''}/api/user/${request.user_id}/export`, {
+			...options,
+			json: { justification: request.justification }
+		})
+		.blob();
+}
+
+export async function exportOwnData(
+	fetch: FetchType,
+	options?: Options,
+	apiUrl?: string
+): Promise<Blob> {
+	return fetch.get(`${apiUrl ?? ''}/api/user/export`, options).blob();
+}
+
+export const ActivitySchema = z.object({
+	id: z.int().min(0),
+	action: z.string(),
+	target_type: z.string(),
+	target_id: z.int().optional(),
+	title: z.string().optional(),
+	metadata: z.record(z.string(), z.unknown()),
+	created_at: z.string()
+});
+
+export type Activity = z.infer<typeof ActivitySchema>;
+
+export const GetActivityResponseSchema = z.object({
+	activity: z.array(ActivitySchema),
+	cursor: z.int().min(0),
+	page: PageSchema
+});
+
+export type GetActivityRequest = {
+	before?: number;
+	limit?: number;
+};
+
+export type GetActivityResponse = z.infer<typeof GetActivityResponseSchema>;
+
+export async function getActivity(
+	fetch: FetchType,
+	request: GetActivityRequest,
+	options?: Options,
+	apiUrl?: string
+): Promise<GetActivityResponse> {
+	const searchParams = new URLSearchParams();
+	for (const [key, value] of Object.entries(request)) {
+		if (value !== undefined) {
+			searchParams.set(key, String(value));
+		}
+	}
+	const json = await fetch
+		.get(`${apiUrl ?? ''}/api/users/me/activity`, {
+			...options,
+			searchParams
+		})
+		.json();
+	return GetActivityResponseSchema.parse(json);
+}
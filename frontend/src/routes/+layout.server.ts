@@ -1,10 +1,21 @@
 import type { LayoutServerLoad } from './$types';
 import { ACCESS_TOKEN_COOKIE_NAME } from '$lib/auth';
+import fetch from '$lib/http';
+import { defaultInstance, getInstance } from '$lib/instance';
+import { env } from '$env/dynamic/private';
 
 export const load: LayoutServerLoad = async ({ cookies }) => {
 	const accessToken = cookies.get(ACCESS_TOKEN_COOKIE_NAME);
 
+	let instance = defaultInstance;
+	try {
+		instance = await getInstance(fetch, {}, env.INTERNAL_BACKEND_URL);
+	} catch (e) {
+		console.error('failed to get instance branding', e);
+	}
+
 	return {
-		isLoggedIn: !!accessToken
+		isLoggedIn: !!accessToken,
+		instance
 	};
 };